@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -20,7 +21,11 @@ func main() {
 // If the user wants to then a bug report is sent.
 func handleError(err error) {
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Encountered an error: %s\n", err)
-		os.Exit(1)
+		// A program run by the CLI has already reported its own error.
+		var exitErr secrethub.ExitError
+		if !errors.As(err, &exitErr) {
+			fmt.Fprintf(os.Stderr, "Encountered an error: %s\n", err)
+		}
+		os.Exit(secrethub.ExitCode(err))
 	}
 }
@@ -0,0 +1,98 @@
+// Package atomicfile writes files atomically, so that a reader never sees a partially written file
+// and writers in concurrent invocations of the CLI do not interleave.
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// lockSuffix is appended to the path of a file to get the path of the file that is locked while it is written.
+const lockSuffix = ".lock"
+
+// Errors
+var (
+	errAtomicFile = errio.Namespace("atomicfile")
+
+	// ErrCannotLock is returned when the lock on a file cannot be taken.
+	ErrCannotLock = errAtomicFile.Code("cannot_lock").ErrorPref("cannot lock %s: %s")
+)
+
+// Write atomically replaces the file at the given path with the given data. The data is written to
+// a temporary file in the same directory, which is renamed over the file once it is completely written.
+// The file is locked while it is written. The directory is created when it does not exist yet,
+// accessible only by the user.
+func Write(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	// After the rename, there is nothing left to remove.
+	defer os.Remove(tmpPath)
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmpPath, perm)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Lock takes an exclusive lock on the file at the given path and returns a function that releases it.
+// It waits until other processes have released their lock. The lock is taken on a separate file next
+// to the file, so that the lock is kept when the file is replaced.
+func Lock(path string) (func(), error) {
+	f, err := os.OpenFile(path+lockSuffix, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, ErrCannotLock(path, err)
+	}
+
+	err = lock(f)
+	if err != nil {
+		f.Close()
+		return nil, ErrCannotLock(path, err)
+	}
+
+	return func() {
+		_ = unlock(f)
+		_ = f.Close()
+	}, nil
+}
+
+// Remove removes the file at the given path that was written with Write, together with its lock file.
+// It must only be used when no other process writes the file anymore, like when a temporary state file
+// is cleaned up. It returns an error that satisfies os.IsNotExist when the file does not exist.
+func Remove(path string) error {
+	err := os.Remove(path + lockSuffix)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,71 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestWrite(t *testing.T) {
+	dir, err := os.MkdirTemp("", "atomicfile")
+	assert.OK(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config", "state.json")
+
+	err = Write(path, []byte("first"), 0600)
+	assert.OK(t, err)
+	err = Write(path, []byte("second"), 0644)
+	assert.OK(t, err)
+
+	actual, err := os.ReadFile(path)
+	assert.OK(t, err)
+	assert.Equal(t, string(actual), "second")
+
+	info, err := os.Stat(filepath.Dir(path))
+	assert.OK(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0700))
+
+	// Only the file and its lock file are left behind.
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.OK(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, names, []string{"state.json", "state.json.lock"})
+
+	err = Remove(path)
+	assert.OK(t, err)
+	entries, err = os.ReadDir(filepath.Dir(path))
+	assert.OK(t, err)
+	assert.Equal(t, len(entries), 0)
+
+	err = Remove(path)
+	assert.Equal(t, os.IsNotExist(err), true)
+}
+
+func TestWrite_Concurrent(t *testing.T) {
+	dir, err := os.MkdirTemp("", "atomicfile")
+	assert.OK(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state.json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.OK(t, Write(path, []byte(strconv.Itoa(i)), 0600))
+		}(i)
+	}
+	wg.Wait()
+
+	actual, err := os.ReadFile(path)
+	assert.OK(t, err)
+	_, err = strconv.Atoi(string(actual))
+	assert.OK(t, err)
+}
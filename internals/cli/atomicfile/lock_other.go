@@ -0,0 +1,17 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+
+package atomicfile
+
+import (
+	"os"
+)
+
+// lock does nothing on systems without file locks. The file is still replaced atomically,
+// but concurrent writers may overwrite each other's changes.
+func lock(f *os.File) error {
+	return nil
+}
+
+func unlock(f *os.File) error {
+	return nil
+}
@@ -2,6 +2,10 @@
 package clip
 
 import (
+	"os"
+	"os/exec"
+	"strings"
+
 	"github.com/atotto/clipboard"
 	"github.com/secrethub/secrethub-go/internals/errio"
 )
@@ -13,18 +17,109 @@ var (
 	ErrCannotRead = errClip.Code("cannot_read").ErrorPref("cannot read from clipboard: %s")
 	// ErrCannotWrite is returned when data cannot be written to the clipboard.
 	ErrCannotWrite = errClip.Code("cannot_write").ErrorPref("cannot write to clipboard: %s")
+	// ErrReadNotSupported is returned when the clipboard cannot be read with the selected backend.
+	ErrReadNotSupported = errClip.Code("read_not_supported").Error("reading from the clipboard is not supported by the osc52 clipboard backend")
+	// ErrUnknownBackend is returned when a backend is selected that does not exist.
+	ErrUnknownBackend = errClip.Code("unknown_backend").ErrorPref("unknown clipboard backend %s: must be one of " + strings.Join(Backends, ", "))
+)
+
+// Clipboard backends
+const (
+	// BackendAuto selects the backend that fits the environment.
+	BackendAuto = "auto"
+	// BackendSystem uses the clipboard of the operating system, through xclip or xsel on Linux.
+	BackendSystem = "system"
+	// BackendWayland uses wl-copy and wl-paste of wl-clipboard.
+	BackendWayland = "wayland"
+	// BackendOSC52 writes the OSC 52 escape sequence to the terminal, which sets the clipboard
+	// of the terminal emulator. This works over SSH and in tmux, but the clipboard cannot be read.
+	BackendOSC52 = "osc52"
 )
 
+// Backends are the names of the backends that can be selected.
+var Backends = []string{BackendAuto, BackendSystem, BackendWayland, BackendOSC52}
+
+// selectedBackend is the backend that is used by the clipboards created with NewClipboard.
+var selectedBackend = BackendAuto
+
+// SetBackend selects the backend that is used by the clipboards created with NewClipboard.
+func SetBackend(name string) error {
+	for _, backend := range Backends {
+		if name == backend {
+			selectedBackend = name
+			return nil
+		}
+	}
+	return ErrUnknownBackend(name)
+}
+
+// Backend returns the backend that is used to access the clipboard.
+// When the auto backend is selected, it returns the backend that fits the environment.
+func Backend() string {
+	if selectedBackend != BackendAuto {
+		return selectedBackend
+	}
+	return autoBackend(os.Getenv, exec.LookPath, clipboard.Unsupported)
+}
+
+// autoBackend returns the backend that fits the environment. Wayland is used when wl-clipboard is installed
+// in a Wayland session. OSC 52 is used in remote sessions without a display and in tmux when the system
+// clipboard is not available, so that the clipboard of the local terminal emulator is set.
+func autoBackend(getenv func(string) string, lookPath func(string) (string, error), systemUnsupported bool) string {
+	if getenv("WAYLAND_DISPLAY") != "" {
+		if _, err := lookPath("wl-copy"); err == nil {
+			return BackendWayland
+		}
+	}
+
+	isRemote := getenv("SSH_TTY") != "" || getenv("SSH_CONNECTION") != ""
+	if isRemote && getenv("DISPLAY") == "" && getenv("WAYLAND_DISPLAY") == "" {
+		return BackendOSC52
+	}
+	if systemUnsupported && (isRemote || getenv("TMUX") != "") {
+		return BackendOSC52
+	}
+	return BackendSystem
+}
+
 // Clipper allows you to read from and write to the clipboard.
 type Clipper interface {
 	ReadAll() ([]byte, error)
 	WriteAll(value []byte) error
 }
 
-// clip implements the Clipper interface
+// clip implements the Clipper interface with the selected backend.
+// The backend is determined on every call, so that the flags are parsed before it is used.
 type clip struct{}
 
+func (c *clip) clipper() Clipper {
+	switch Backend() {
+	case BackendWayland:
+		return &wayland{}
+	case BackendOSC52:
+		return newOSC52()
+	default:
+		return &system{}
+	}
+}
+
 func (c *clip) ReadAll() ([]byte, error) {
+	return c.clipper().ReadAll()
+}
+
+func (c *clip) WriteAll(value []byte) error {
+	return c.clipper().WriteAll(value)
+}
+
+// NewClipboard creates a new Clipper.
+func NewClipboard() Clipper {
+	return &clip{}
+}
+
+// system implements the Clipper interface with the clipboard of the operating system.
+type system struct{}
+
+func (c *system) ReadAll() ([]byte, error) {
 	value, err := clipboard.ReadAll()
 	if err != nil {
 		return nil, ErrCannotRead(err)
@@ -32,15 +127,10 @@ func (c *clip) ReadAll() ([]byte, error) {
 	return []byte(value), nil
 }
 
-func (c *clip) WriteAll(value []byte) error {
+func (c *system) WriteAll(value []byte) error {
 	err := clipboard.WriteAll(string(value))
 	if err != nil {
 		return ErrCannotWrite(err)
 	}
 	return nil
 }
-
-// NewClipboard creates a new Clipper.
-func NewClipboard() Clipper {
-	return &clip{}
-}
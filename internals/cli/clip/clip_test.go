@@ -0,0 +1,132 @@
+package clip
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestAutoBackend(t *testing.T) {
+	cases := map[string]struct {
+		env               map[string]string
+		wlCopyInstalled   bool
+		systemUnsupported bool
+		expected          string
+	}{
+		"desktop": {
+			env:      map[string]string{"DISPLAY": ":0"},
+			expected: BackendSystem,
+		},
+		"wayland": {
+			env:             map[string]string{"WAYLAND_DISPLAY": "wayland-0"},
+			wlCopyInstalled: true,
+			expected:        BackendWayland,
+		},
+		"wayland without wl-clipboard": {
+			env:      map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"},
+			expected: BackendSystem,
+		},
+		"ssh": {
+			env:      map[string]string{"SSH_CONNECTION": "10.0.0.1 51234 10.0.0.2 22"},
+			expected: BackendOSC52,
+		},
+		"ssh with forwarded display": {
+			env:      map[string]string{"SSH_CONNECTION": "10.0.0.1 51234 10.0.0.2 22", "DISPLAY": "localhost:10.0"},
+			expected: BackendSystem,
+		},
+		"tmux without system clipboard": {
+			env:               map[string]string{"TMUX": "/tmp/tmux-1000/default,1234,0", "DISPLAY": ":0"},
+			systemUnsupported: true,
+			expected:          BackendOSC52,
+		},
+		"tmux with system clipboard": {
+			env:      map[string]string{"TMUX": "/tmp/tmux-1000/default,1234,0", "DISPLAY": ":0"},
+			expected: BackendSystem,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			getenv := func(key string) string {
+				return tc.env[key]
+			}
+			lookPath := func(file string) (string, error) {
+				if tc.wlCopyInstalled {
+					return "/usr/bin/" + file, nil
+				}
+				return "", errors.New("not found")
+			}
+
+			actual := autoBackend(getenv, lookPath, tc.systemUnsupported)
+
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestSetBackend(t *testing.T) {
+	defer func() {
+		selectedBackend = BackendAuto
+	}()
+
+	assert.OK(t, SetBackend(BackendOSC52))
+	assert.Equal(t, Backend(), BackendOSC52)
+	assert.Equal(t, SetBackend("x11"), ErrUnknownBackend("x11"))
+	assert.Equal(t, Backend(), BackendOSC52)
+}
+
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (b *bufferCloser) Close() error {
+	return nil
+}
+
+func TestOSC52_WriteAll(t *testing.T) {
+	cases := map[string]struct {
+		value    []byte
+		inTmux   bool
+		expected string
+	}{
+		"terminal": {
+			value:    []byte("secret"),
+			expected: "\x1b]52;c;c2VjcmV0\a",
+		},
+		"tmux": {
+			value:    []byte("secret"),
+			inTmux:   true,
+			expected: "\x1bPtmux;\x1b\x1b]52;c;c2VjcmV0\a\x1b\\",
+		},
+		"clear": {
+			value:    nil,
+			expected: "\x1b]52;c;\a",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			terminal := &bufferCloser{}
+			clipper := &osc52{
+				openTerminal: func() (io.WriteCloser, error) {
+					return terminal, nil
+				},
+				inTmux: tc.inTmux,
+			}
+
+			err := clipper.WriteAll(tc.value)
+
+			assert.OK(t, err)
+			assert.Equal(t, terminal.String(), tc.expected)
+		})
+	}
+}
+
+func TestOSC52_ReadAll(t *testing.T) {
+	_, err := newOSC52().ReadAll()
+
+	assert.Equal(t, err, ErrReadNotSupported)
+}
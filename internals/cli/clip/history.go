@@ -0,0 +1,124 @@
+package clip
+
+import (
+	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// maxHistoryItems is the number of most recent items in the history of a clipboard manager that are checked.
+// A copied secret is cleared after a short time, so it is one of the most recent items.
+const maxHistoryItems = 10
+
+// runFunc runs a command with the given input and returns its output.
+type runFunc func(stdin []byte, name string, args ...string) ([]byte, error)
+
+// historyManager is a clipboard manager that keeps a history of copied values and that
+// has an interface to remove items from it.
+type historyManager struct {
+	command string
+	remove  func(run runFunc, matches func([]byte) bool) (int, error)
+}
+
+// historyManagers are the clipboard managers of which items can be removed from the history.
+var historyManagers = []historyManager{
+	{command: "copyq", remove: removeFromCopyQ},
+	{command: "cliphist", remove: removeFromCliphist},
+}
+
+// RemoveFromHistory removes the items for which matches returns true from the history of the
+// clipboard managers that are installed. Removing items is a best effort, so clipboard managers
+// that are not running or that fail are skipped. It returns the number of items removed.
+func RemoveFromHistory(matches func([]byte) bool) int {
+	return removeFromHistory(exec.LookPath, runCommand, matches)
+}
+
+func removeFromHistory(lookPath func(string) (string, error), run runFunc, matches func([]byte) bool) int {
+	removed := 0
+	for _, manager := range historyManagers {
+		if _, err := lookPath(manager.command); err != nil {
+			continue
+		}
+		n, _ := manager.remove(run, matches)
+		removed += n
+	}
+	return removed
+}
+
+// removeFromCopyQ removes the matching items from the history of CopyQ.
+// Rows are removed from the last to the first, so that the rows that still have to be removed do not shift.
+func removeFromCopyQ(run runFunc, matches func([]byte) bool) (int, error) {
+	out, err := run(nil, "copyq", "count")
+	if err != nil {
+		return 0, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 0, err
+	}
+	if count > maxHistoryItems {
+		count = maxHistoryItems
+	}
+
+	var rows []string
+	for row := count - 1; row >= 0; row-- {
+		item, err := run(nil, "copyq", "read", "text/plain", strconv.Itoa(row))
+		if err != nil {
+			return 0, err
+		}
+		if matches(item) {
+			rows = append(rows, strconv.Itoa(row))
+		}
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	_, err = run(nil, "copyq", append([]string{"remove"}, rows...)...)
+	if err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// removeFromCliphist removes the matching items from the history of cliphist.
+// The items are passed to cliphist on stdin, so that they do not end up in the arguments of a process.
+func removeFromCliphist(run runFunc, matches func([]byte) bool) (int, error) {
+	out, err := run(nil, "cliphist", "list")
+	if err != nil {
+		return 0, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) > maxHistoryItems {
+		lines = lines[:maxHistoryItems]
+	}
+
+	removed := 0
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		item, err := run([]byte(line+"\n"), "cliphist", "decode")
+		if err != nil {
+			return removed, err
+		}
+		if !matches(item) {
+			continue
+		}
+
+		_, err = run([]byte(line+"\n"), "cliphist", "delete")
+		if err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func runCommand(stdin []byte, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	return cmd.Output()
+}
@@ -0,0 +1,109 @@
+package clip
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestRemoveFromHistory(t *testing.T) {
+	cases := map[string]struct {
+		installed       []string
+		copyq           []string
+		cliphist        []string
+		expectedRemoved int
+		expectedCopyQ   []string
+		expectedClip    []string
+	}{
+		"copyq": {
+			installed:       []string{"copyq"},
+			copyq:           []string{"other", "secret", "older", "secret"},
+			expectedRemoved: 2,
+			expectedCopyQ:   []string{"other", "older"},
+		},
+		"cliphist": {
+			installed:       []string{"cliphist"},
+			cliphist:        []string{"secret", "other"},
+			expectedRemoved: 1,
+			expectedClip:    []string{"other"},
+		},
+		"both": {
+			installed:       []string{"copyq", "cliphist"},
+			copyq:           []string{"secret"},
+			cliphist:        []string{"secret"},
+			expectedRemoved: 2,
+			expectedCopyQ:   []string{},
+			expectedClip:    []string{},
+		},
+		"not installed": {
+			copyq:           []string{"secret"},
+			expectedRemoved: 0,
+			expectedCopyQ:   []string{"secret"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			copyq := append([]string{}, tc.copyq...)
+			cliphist := append([]string{}, tc.cliphist...)
+
+			lookPath := func(file string) (string, error) {
+				for _, installed := range tc.installed {
+					if file == installed {
+						return "/usr/bin/" + file, nil
+					}
+				}
+				return "", errors.New("not found")
+			}
+
+			run := func(stdin []byte, name string, args ...string) ([]byte, error) {
+				switch name + " " + args[0] {
+				case "copyq count":
+					return []byte(strconv.Itoa(len(copyq)) + "\n"), nil
+				case "copyq read":
+					row, _ := strconv.Atoi(args[2])
+					return []byte(copyq[row]), nil
+				case "copyq remove":
+					for _, arg := range args[1:] {
+						row, _ := strconv.Atoi(arg)
+						copyq = append(copyq[:row], copyq[row+1:]...)
+					}
+					return nil, nil
+				case "cliphist list":
+					var lines []string
+					for i, item := range cliphist {
+						lines = append(lines, strconv.Itoa(i)+"\t"+item)
+					}
+					return []byte(strings.Join(lines, "\n") + "\n"), nil
+				case "cliphist decode":
+					return []byte(strings.SplitN(strings.TrimSpace(string(stdin)), "\t", 2)[1]), nil
+				case "cliphist delete":
+					item := strings.SplitN(strings.TrimSpace(string(stdin)), "\t", 2)[1]
+					for i := range cliphist {
+						if cliphist[i] == item {
+							cliphist = append(cliphist[:i], cliphist[i+1:]...)
+							break
+						}
+					}
+					return nil, nil
+				}
+				return nil, errors.New("unexpected command")
+			}
+
+			removed := removeFromHistory(lookPath, run, func(item []byte) bool {
+				return string(item) == "secret"
+			})
+
+			assert.Equal(t, removed, tc.expectedRemoved)
+			if tc.expectedCopyQ != nil {
+				assert.Equal(t, copyq, tc.expectedCopyQ)
+			}
+			if tc.expectedClip != nil {
+				assert.Equal(t, cliphist, tc.expectedClip)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package clip
+
+import (
+	"encoding/base64"
+	"io"
+	"os"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/mlock"
+)
+
+// osc52 implements the Clipper interface by writing the OSC 52 escape sequence to the terminal.
+// The terminal emulator sets its clipboard to the value, which also works in an SSH session.
+type osc52 struct {
+	openTerminal func() (io.WriteCloser, error)
+	inTmux       bool
+}
+
+func newOSC52() *osc52 {
+	return &osc52{
+		openTerminal: func() (io.WriteCloser, error) {
+			// The sequence is written to the terminal instead of stdout, so that it also works when stdout is redirected.
+			return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+		},
+		inTmux: os.Getenv("TMUX") != "",
+	}
+}
+
+// ReadAll always returns ErrReadNotSupported, because terminal emulators do not allow
+// reading the clipboard for security reasons.
+func (c *osc52) ReadAll() ([]byte, error) {
+	return nil, ErrReadNotSupported
+}
+
+// WriteAll sets the clipboard of the terminal emulator. An empty value clears the clipboard.
+func (c *osc52) WriteAll(value []byte) error {
+	terminal, err := c.openTerminal()
+	if err != nil {
+		return ErrCannotWrite(err)
+	}
+	defer terminal.Close()
+
+	seq := c.sequence(value)
+	defer seq.Wipe()
+
+	_, err = terminal.Write(seq.Bytes())
+	if err != nil {
+		return ErrCannotWrite(err)
+	}
+	return nil
+}
+
+// sequence returns the OSC 52 escape sequence that sets the clipboard to the value, in a buffer
+// that must be wiped after use. In tmux, the sequence is wrapped in a passthrough sequence, so that
+// tmux passes it on to the terminal emulator.
+func (c *osc52) sequence(value []byte) *mlock.Buffer {
+	prefix, suffix := "\x1b]52;c;", "\a"
+	if c.inTmux {
+		// The escape characters of the wrapped sequence are doubled. The encoded value contains none.
+		prefix, suffix = "\x1bPtmux;\x1b\x1b]52;c;", "\a\x1b\\"
+	}
+
+	seq := mlock.NewBuffer(len(prefix) + base64.StdEncoding.EncodedLen(len(value)) + len(suffix))
+	data := seq.Bytes()
+	n := copy(data, prefix)
+	base64.StdEncoding.Encode(data[n:], value)
+	copy(data[len(data)-len(suffix):], suffix)
+	return seq
+}
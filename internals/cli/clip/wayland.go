@@ -0,0 +1,48 @@
+package clip
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+)
+
+// wayland implements the Clipper interface with wl-copy and wl-paste of wl-clipboard.
+type wayland struct{}
+
+func (c *wayland) ReadAll() ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("wl-paste", "--no-newline")
+	cmd.Stderr = &stderr
+	value, err := cmd.Output()
+	if err != nil {
+		// wl-paste fails when the clipboard is empty.
+		if strings.Contains(stderr.String(), "Nothing is copied") {
+			return []byte{}, nil
+		}
+		return nil, ErrCannotRead(commandError(err, stderr))
+	}
+	return value, nil
+}
+
+func (c *wayland) WriteAll(value []byte) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("wl-copy")
+	if len(value) == 0 {
+		cmd = exec.Command("wl-copy", "--clear")
+	}
+	cmd.Stdin = bytes.NewReader(value)
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return ErrCannotWrite(commandError(err, stderr))
+	}
+	return nil
+}
+
+// commandError returns the error output of a failed command, or the error itself when the command did not output an error.
+func commandError(err error, stderr bytes.Buffer) string {
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return msg
+	}
+	return err.Error()
+}
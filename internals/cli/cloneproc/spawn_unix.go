@@ -10,7 +10,14 @@ import (
 
 // Spawn starts a detached clone of the client with the supplied parameters.
 func Spawn(args ...string) error {
+	return SpawnWithEnv(nil, args...)
+}
+
+// SpawnWithEnv starts a detached clone of the client with the supplied parameters.
+// The given environment variables are added to the environment of the current process.
+func SpawnWithEnv(env []string, args ...string) error {
 	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), env...)
 
 	// Detach spawned process from the current
 	cmd.SysProcAttr = &syscall.SysProcAttr{
@@ -2,6 +2,16 @@ package cli
 
 import (
 	"fmt"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// Errors
+var (
+	errArguments = errio.Namespace("arguments")
+
+	// ErrInvalidArguments is returned when a command is called with the wrong number of arguments.
+	ErrInvalidArguments = errArguments.Code("invalid_arguments").ErrorPref("%s")
 )
 
 func (c *CommandClause) validateArgumentsCount(args []string) error {
@@ -25,7 +35,7 @@ func (c *CommandClause) validateArgumentsArrCount(args []string) error {
 }
 
 func (c *CommandClause) argumentError(errorText string) error {
-	return fmt.Errorf(
+	return ErrInvalidArguments(fmt.Sprintf(
 		"%s %s.\n"+
 			"See `%s --help` for help.\n"+
 			"\n"+
@@ -37,7 +47,7 @@ func (c *CommandClause) argumentError(errorText string) error {
 		c.fullCommand(),
 		useLine(c.Cmd, c.Args),
 		c.Cmd.Short,
-	)
+	))
 }
 
 func pluralize(word string, num int) string {
@@ -1,46 +1,186 @@
 package cli
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/op/go-logging"
+	"github.com/secrethub/secrethub-go/internals/errio"
 )
 
+// Log levels, from the most to the least verbose.
 const (
-	logFormat = `%{color}%{level:.4s} ▶ %{color:reset} %{message}`
-	logModule = "log"
+	LogLevelDebug   = "debug"
+	LogLevelInfo    = "info"
+	LogLevelWarning = "warning"
+	LogLevelError   = "error"
 )
 
-// Logger can be used to log debug and warning messages.
+// Log formats
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+var (
+	// LogLevels are the supported log levels, from the most to the least verbose.
+	LogLevels = []string{LogLevelDebug, LogLevelInfo, LogLevelWarning, LogLevelError}
+	// LogFormats are the supported log formats.
+	LogFormats = []string{LogFormatText, LogFormatJSON}
+)
+
+// Errors
+var (
+	errLog = errio.Namespace("log")
+
+	// ErrUnknownLogLevel is returned when a log level is set that does not exist.
+	ErrUnknownLogLevel = errLog.Code("unknown_level").ErrorPref("unknown log level %s: must be one of " + strings.Join(LogLevels, ", "))
+	// ErrUnknownLogFormat is returned when a log format is set that does not exist.
+	ErrUnknownLogFormat = errLog.Code("unknown_format").ErrorPref("unknown log format %s: must be one of " + strings.Join(LogFormats, ", "))
+)
+
+// logPrefixes are the prefixes of the messages of every level in the text format.
+var logPrefixes = map[string]string{
+	LogLevelDebug:   "DEBUG",
+	LogLevelInfo:    "INFO",
+	LogLevelWarning: "WARN",
+	LogLevelError:   "ERROR",
+}
+
+// Logger can be used to log messages of different levels.
+// Messages are only written when their level is at least the configured log level.
 type Logger interface {
 	// Debugf logs a message when debug mode is enabled.
 	Debugf(format string, args ...interface{})
-	// Warningf logs a message when debug mode is enabled.
+	// Infof logs an informative message, which is shown in verbose mode.
+	Infof(format string, args ...interface{})
+	// Warningf logs a message about something that did not go as expected, but is not fatal.
 	Warningf(format string, args ...interface{})
+	// Errorf logs a message about an error, which is shown even in quiet mode.
+	Errorf(format string, args ...interface{})
 	// EnableDebug turns printing debug messages on.
 	EnableDebug()
 }
 
-type logger struct {
-	*logging.Logger
+// logConfig is shared by all loggers, so that the log flags apply to the loggers of all packages.
+var logConfig = struct {
+	sync.Mutex
+	level  int
+	format string
+	out    io.Writer
+}{
+	level:  logLevelIndex(LogLevelWarning),
+	format: LogFormatText,
+	out:    os.Stderr,
+}
+
+// SetLogLevel sets the minimum level of the messages that are logged.
+func SetLogLevel(level string) error {
+	index := logLevelIndex(level)
+	if index < 0 {
+		return ErrUnknownLogLevel(level)
+	}
+
+	logConfig.Lock()
+	defer logConfig.Unlock()
+	logConfig.level = index
+	return nil
+}
+
+// SetLogFormat sets the format in which messages are logged.
+func SetLogFormat(format string) error {
+	if format != LogFormatText && format != LogFormatJSON {
+		return ErrUnknownLogFormat(format)
+	}
+
+	logConfig.Lock()
+	defer logConfig.Unlock()
+	logConfig.format = format
+	return nil
 }
 
-func init() {
-	formatter := logging.MustStringFormatter(logFormat)
-	backend := logging.NewBackendFormatter(logging.NewLogBackend(os.Stdout, "", 0), formatter)
-	logging.SetBackend(backend)
+func logLevelIndex(level string) int {
+	for i, l := range LogLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
 }
 
-// NewLogger returns a logger with the given format, module and loglevel.
+type logger struct {
+	out io.Writer
+}
+
+// NewLogger returns a logger that writes to stderr.
 func NewLogger() Logger {
-	l := logging.MustGetLogger(logModule)
-	logging.SetLevel(logging.INFO, logModule)
+	return logger{}
+}
 
-	return logger{Logger: l}
+// NewLoggerWithOutput returns a logger that writes to the given writer instead of stderr.
+func NewLoggerWithOutput(w io.Writer) Logger {
+	return logger{out: w}
+}
+
+// Debugf logs a message when debug mode is enabled.
+func (l logger) Debugf(format string, args ...interface{}) {
+	l.log(LogLevelDebug, format, args...)
+}
+
+// Infof logs an informative message, which is shown in verbose mode.
+func (l logger) Infof(format string, args ...interface{}) {
+	l.log(LogLevelInfo, format, args...)
+}
+
+// Warningf logs a message about something that did not go as expected, but is not fatal.
+func (l logger) Warningf(format string, args ...interface{}) {
+	l.log(LogLevelWarning, format, args...)
+}
+
+// Errorf logs a message about an error, which is shown even in quiet mode.
+func (l logger) Errorf(format string, args ...interface{}) {
+	l.log(LogLevelError, format, args...)
 }
 
 // EnableDebug turns printing debug messages on.
 func (l logger) EnableDebug() {
-	logging.SetLevel(logging.DEBUG, l.Module)
-	l.Debug("Loglevel set to debug")
+	_ = SetLogLevel(LogLevelDebug)
+	l.Debugf("Loglevel set to debug")
+}
+
+func (l logger) log(level string, format string, args ...interface{}) {
+	logConfig.Lock()
+	defer logConfig.Unlock()
+
+	if logLevelIndex(level) < logConfig.level {
+		return
+	}
+
+	out := l.out
+	if out == nil {
+		out = logConfig.out
+	}
+
+	message := strings.TrimSuffix(fmt.Sprintf(format, args...), "\n")
+	if logConfig.format == LogFormatJSON {
+		line, err := json.Marshal(struct {
+			Time    string `json:"time"`
+			Level   string `json:"level"`
+			Message string `json:"message"`
+		}{
+			Time:    time.Now().UTC().Format(time.RFC3339),
+			Level:   level,
+			Message: message,
+		})
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(out, "%s\n", line)
+		return
+	}
+	fmt.Fprintf(out, "%s: %s\n", logPrefixes[level], message)
 }
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestLogger(t *testing.T) {
+	cases := map[string]struct {
+		level    string
+		expected string
+	}{
+		"default": {
+			level:    LogLevelWarning,
+			expected: "WARN: warning 2\nERROR: error 3\n",
+		},
+		"quiet": {
+			level:    LogLevelError,
+			expected: "ERROR: error 3\n",
+		},
+		"verbose": {
+			level:    LogLevelInfo,
+			expected: "INFO: info 1\nWARN: warning 2\nERROR: error 3\n",
+		},
+		"debug": {
+			level:    LogLevelDebug,
+			expected: "DEBUG: debug 0\nINFO: info 1\nWARN: warning 2\nERROR: error 3\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				assert.OK(t, SetLogLevel(LogLevelWarning))
+			}()
+			assert.OK(t, SetLogLevel(tc.level))
+
+			out := &bytes.Buffer{}
+			logger := NewLoggerWithOutput(out)
+			logger.Debugf("debug %d", 0)
+			logger.Infof("info %d", 1)
+			logger.Warningf("warning %d", 2)
+			logger.Errorf("error %d\n", 3)
+
+			assert.Equal(t, out.String(), tc.expected)
+		})
+	}
+}
+
+func TestLogger_JSON(t *testing.T) {
+	defer func() {
+		assert.OK(t, SetLogFormat(LogFormatText))
+	}()
+	assert.OK(t, SetLogFormat(LogFormatJSON))
+
+	out := &bytes.Buffer{}
+	NewLoggerWithOutput(out).Warningf("could not write %q", "file")
+
+	var message map[string]string
+	err := json.Unmarshal(out.Bytes(), &message)
+	assert.OK(t, err)
+	assert.Equal(t, message["level"], LogLevelWarning)
+	assert.Equal(t, message["message"], `could not write "file"`)
+}
+
+func TestSetLogLevel_Unknown(t *testing.T) {
+	assert.Equal(t, SetLogLevel("trace"), ErrUnknownLogLevel("trace"))
+	assert.Equal(t, SetLogFormat("xml"), ErrUnknownLogFormat("xml"))
+}
@@ -0,0 +1,30 @@
+// Package machineid reads the identifier that the operating system assigns to the machine.
+package machineid
+
+import (
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// Errors
+var (
+	errMachineID = errio.Namespace("machineid")
+
+	// ErrNotFound is returned when the machine has no identifier or it cannot be read.
+	ErrNotFound = errMachineID.Code("not_found").Error("cannot find the identifier of this machine")
+)
+
+// ID returns the identifier of the machine. It is stable across reboots, but is not a secret:
+// every user and process on the machine can read it.
+func ID() (string, error) {
+	id, err := read()
+	if err != nil {
+		return "", err
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", ErrNotFound
+	}
+	return id, nil
+}
@@ -0,0 +1,21 @@
+package machineid
+
+import (
+	"os/exec"
+	"regexp"
+)
+
+var platformUUIDPattern = regexp.MustCompile(`"IOPlatformUUID" = "([^"]+)"`)
+
+// read returns the hardware UUID of the Mac.
+func read() (string, error) {
+	out, err := exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice").Output()
+	if err != nil {
+		return "", ErrNotFound
+	}
+	match := platformUUIDPattern.FindSubmatch(out)
+	if match == nil {
+		return "", ErrNotFound
+	}
+	return string(match[1]), nil
+}
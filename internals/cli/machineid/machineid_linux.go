@@ -0,0 +1,21 @@
+package machineid
+
+import (
+	"os"
+)
+
+// paths are the locations of the machine ID, set by systemd or D-Bus.
+var paths = []string{
+	"/etc/machine-id",
+	"/var/lib/dbus/machine-id",
+}
+
+func read() (string, error) {
+	for _, path := range paths {
+		id, err := os.ReadFile(path)
+		if err == nil {
+			return string(id), nil
+		}
+	}
+	return "", ErrNotFound
+}
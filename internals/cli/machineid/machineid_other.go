@@ -0,0 +1,16 @@
+//go:build !linux && !darwin && !windows
+
+package machineid
+
+import (
+	"os"
+)
+
+// read returns the host ID that is set on the BSDs.
+func read() (string, error) {
+	id, err := os.ReadFile("/etc/hostid")
+	if err != nil {
+		return "", ErrNotFound
+	}
+	return string(id), nil
+}
@@ -0,0 +1,20 @@
+package machineid
+
+import (
+	"golang.org/x/sys/windows/registry"
+)
+
+// read returns the GUID that Windows generated when it was installed.
+func read() (string, error) {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Cryptography`, registry.QUERY_VALUE|registry.WOW64_64KEY)
+	if err != nil {
+		return "", ErrNotFound
+	}
+	defer key.Close()
+
+	id, _, err := key.GetStringValue("MachineGuid")
+	if err != nil {
+		return "", ErrNotFound
+	}
+	return id, nil
+}
@@ -0,0 +1,60 @@
+package masker
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+)
+
+// encodings are the encodings of secrets that are masked in addition to the secrets themselves.
+// Base64 is encoded without padding, because the padding depends on the length of the surrounding
+// data when the secret is part of a larger encoded value.
+var encodings = []func([]byte) []byte{
+	func(b []byte) []byte { return []byte(base64.RawStdEncoding.EncodeToString(b)) },
+	func(b []byte) []byte { return []byte(base64.RawURLEncoding.EncodeToString(b)) },
+	func(b []byte) []byte { return []byte(hex.EncodeToString(b)) },
+	func(b []byte) []byte { return bytes.ToUpper([]byte(hex.EncodeToString(b))) },
+	func(b []byte) []byte { return []byte(url.QueryEscape(string(b))) },
+	func(b []byte) []byte { return []byte(url.PathEscape(string(b))) },
+	jsonEscape,
+}
+
+// withEncodings returns the sequences together with their encoded forms.
+// Encoded forms that are equal to a sequence that is already masked are left out.
+func withEncodings(sequences [][]byte) [][]byte {
+	seen := make(map[string]bool, len(sequences))
+	res := make([][]byte, 0, len(sequences)*(len(encodings)+1))
+	add := func(sequence []byte) {
+		if len(sequence) == 0 || seen[string(sequence)] {
+			return
+		}
+		seen[string(sequence)] = true
+		res = append(res, sequence)
+	}
+
+	for _, sequence := range sequences {
+		add(sequence)
+	}
+	for _, sequence := range sequences {
+		for _, encode := range encodings {
+			add(encode(sequence))
+		}
+	}
+	return res
+}
+
+// jsonEscape returns the sequence as it appears in a JSON string, without the surrounding quotes.
+// HTML characters are not escaped, as most JSON encoders only do so when asked to.
+func jsonEscape(b []byte) []byte {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(string(b))
+	if err != nil {
+		return nil
+	}
+	encoded := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	return encoded[1 : len(encoded)-1]
+}
@@ -17,7 +17,7 @@ import (
 // 4. After everything has been written to the io.Writers, flush all buffers using Stop()
 type Masker struct {
 	bufferDelay time.Duration
-	sequences   [][]byte
+	automaton   *automaton
 	frames      chan frame
 	stopChan    chan struct{}
 	err         error
@@ -38,13 +38,22 @@ type Options struct {
 	// FrameBufferLength is the number of frames that can be in the buffer simultaneously.
 	// If the frame buffer is full, writing to a stream blocks until there is space.
 	FrameBufferLength int
+
+	// DisableEncodings disables the masking of the base64, hex, URL and JSON encoded forms of the sequences.
+	// This reduces the number of sequences to scan for and with that the time it takes to create the masker.
+	DisableEncodings bool
 }
 
 // New creates a new Masker that scans all streams for the given sequences and masks them.
+// Unless disabled in the options, the encoded forms of the sequences are masked too.
 func New(sequences [][]byte, opts *Options) *Masker {
+	if opts == nil || !opts.DisableEncodings {
+		sequences = withEncodings(sequences)
+	}
+
 	masker := &Masker{
 		bufferDelay: time.Millisecond * 50,
-		sequences:   sequences,
+		automaton:   newAutomaton(sequences),
 		stopChan:    make(chan struct{}),
 	}
 	frameChanlength := 1024
@@ -73,7 +82,7 @@ func (m *Masker) AddStream(w io.Writer) io.Writer {
 		dest:          w,
 		registerFrame: m.registerFrame,
 		matches:       matches{},
-		matcher:       newMatcher(m.sequences),
+		matcher:       newMatcher(m.automaton),
 	}
 	return &s
 }
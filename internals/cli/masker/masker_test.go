@@ -144,6 +144,47 @@ func TestMasker(t *testing.T) {
 			},
 			expected: maskString + " world",
 		},
+		"base64 encoded": {
+			maskStrings: []string{`p@ss w"rd/1`},
+			inputFunc: func(w io.Writer) {
+				_, err := w.Write([]byte("token=cEBzcyB3InJkLzE="))
+				assert.OK(t, err)
+			},
+			expected: "token=" + maskString + "=",
+		},
+		"hex encoded": {
+			maskStrings: []string{`p@ss w"rd/1`},
+			inputFunc: func(w io.Writer) {
+				_, err := w.Write([]byte("7040737320772272642f31 7040737320772272642F31"))
+				assert.OK(t, err)
+			},
+			expected: maskString + " " + maskString,
+		},
+		"url encoded": {
+			maskStrings: []string{`p@ss w"rd/1`},
+			inputFunc: func(w io.Writer) {
+				_, err := w.Write([]byte("/login?password=p%40ss+w%22rd%2F1&user=p@ss%20w%22rd%2F1"))
+				assert.OK(t, err)
+			},
+			expected: "/login?password=" + maskString + "&user=" + maskString,
+		},
+		"json encoded": {
+			maskStrings: []string{`p@ss w"rd/1`},
+			inputFunc: func(w io.Writer) {
+				_, err := w.Write([]byte(`{"password":"p@ss w\"rd/1"}`))
+				assert.OK(t, err)
+			},
+			expected: `{"password":"` + maskString + `"}`,
+		},
+		"encodings disabled": {
+			maskStrings: []string{`p@ss w"rd/1`},
+			inputFunc: func(w io.Writer) {
+				_, err := w.Write([]byte("token=cEBzcyB3InJkLzE="))
+				assert.OK(t, err)
+			},
+			options:  &Options{DisableEncodings: true},
+			expected: "token=cEBzcyB3InJkLzE=",
+		},
 	}
 
 	for name, tc := range tests {
@@ -242,3 +283,41 @@ func TestMasker_MultipleStreams(t *testing.T) {
 	}
 	assert.Equal(t, outputBuffer.String(), expected)
 }
+
+func BenchmarkMasker(b *testing.B) {
+	const outputSize = 8 * 1024 * 1024
+	const writeSize = 4096
+
+	for _, secretCount := range []int{1, 100, 1000} {
+		sequences := make([][]byte, secretCount)
+		for i := range sequences {
+			seq, err := randchar.Generate(64)
+			assert.OK(b, err)
+			sequences[i] = seq
+		}
+
+		// Every write contains one of the secrets, the remainder is random output.
+		writes := make([][]byte, 64)
+		for i := range writes {
+			data, err := randchar.Generate(writeSize)
+			assert.OK(b, err)
+			copy(data[writeSize/2:], sequences[i%len(sequences)])
+			writes[i] = data
+		}
+
+		b.Run(fmt.Sprintf("%d secrets", secretCount), func(b *testing.B) {
+			b.SetBytes(outputSize)
+			for i := 0; i < b.N; i++ {
+				m := New(sequences, &Options{DisableBuffer: true})
+				stream := m.AddStream(io.Discard)
+				go m.Start()
+
+				for written := 0; written < outputSize; written += writeSize {
+					_, err := stream.Write(writes[(written/writeSize)%len(writes)])
+					assert.OK(b, err)
+				}
+				assert.OK(b, m.Stop())
+			}
+		})
+	}
+}
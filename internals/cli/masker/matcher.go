@@ -1,8 +1,7 @@
 package masker
 
 import (
-	"bytes"
-	"crypto/subtle"
+	"sort"
 )
 
 // matches represents a set of sequence matches. The key is the index at which the match is found and the value is the
@@ -18,121 +17,152 @@ func (m matches) add(index int64, length int) matches {
 	return m
 }
 
-// matcher combines multiple sequenceMatchers to check for matches of secrets against any of them.
-type matcher struct {
-	detectors    []*sequenceDetector
-	currentIndex int64
+// rootState is the state of the automaton in which no part of any sequence has been matched.
+const rootState = 0
+
+// automaton is an Aho-Corasick automaton that finds all occurrences of a set of sequences in a single pass over
+// the input. The time it takes to process a byte does not depend on the number or the length of the sequences,
+// which makes it suitable for masking many long secrets. It is immutable after it has been built, so a single
+// automaton is shared by the matchers of all streams.
+type automaton struct {
+	states []automatonState
+	// rootNext is the transition table of the root state. It is stored separately as a full table,
+	// because the root state is the state the automaton is in most of the time.
+	rootNext [256]int32
+}
+
+// automatonState is a state of the automaton, which corresponds to a prefix of one or more sequences.
+type automatonState struct {
+	// edges are the transitions to the states of the prefixes that are one byte longer, sorted by byte.
+	edges []automatonEdge
+	// fail is the state of the longest proper suffix of this prefix that is also a prefix of a sequence.
+	fail int32
+	// output is the state of the longest suffix of this prefix, including the prefix itself, that is a
+	// complete sequence, or -1 if there is no such suffix.
+	output int32
+	// depth is the length of the prefix.
+	depth int
+	// isSequence is true when the prefix is a complete sequence.
+	isSequence bool
+}
+
+type automatonEdge struct {
+	b    byte
+	next int32
 }
 
-// newMatcher returns a new matcher that contains a sequenceDetector for all given sequences.
-func newMatcher(sequences [][]byte) *matcher {
-	res := &matcher{
-		detectors: make([]*sequenceDetector, 0, len(sequences)),
+// newAutomaton builds an automaton that matches the given sequences.
+func newAutomaton(sequences [][]byte) *automaton {
+	a := &automaton{
+		states: []automatonState{{output: -1}},
 	}
 
+	// Build the trie of the sequences. Edges are kept in maps while building and sorted afterwards.
+	children := []map[byte]int32{{}}
 	for _, sequence := range sequences {
-		res.detectors = append(res.detectors, &sequenceDetector{
-			sequence: sequence,
-			offset:   0,
-		})
-		// Add detectors for any repetitions in the sequence.
-		// For example, if the sequence is "aaab", we also require a detector for "aaaab" and "aaaaab".
-		for length, count := range sequenceRepetitions(sequence) {
-			for i := 1; i <= count; i++ {
-				prefixedSequence := make([]byte, len(sequence)+length*i)
-				copy(prefixedSequence, sequence[:length*i])
-				copy(prefixedSequence[length*i:], sequence)
-				res.detectors = append(res.detectors, &sequenceDetector{
-					sequence: prefixedSequence,
-					offset:   length * i,
-				})
+		if len(sequence) == 0 {
+			continue
+		}
+		state := int32(rootState)
+		for _, b := range sequence {
+			next, ok := children[state][b]
+			if !ok {
+				next = int32(len(a.states))
+				a.states = append(a.states, automatonState{depth: a.states[state].depth + 1, output: -1})
+				children = append(children, map[byte]int32{})
+				children[state][b] = next
 			}
+			state = next
 		}
+		a.states[state].isSequence = true
 	}
-	return res
-}
 
-// write takes in a slice of bytes and returns all matches found by any of its detectors.
-func (m *matcher) write(in []byte) matches {
-	res := matches{}
-	for i, b := range in {
-		for _, detector := range m.detectors {
-			match := detector.writeByte(b)
-			if match {
-				res = res.add(m.currentIndex+int64(i-detector.length()+1), detector.length())
+	for state, edges := range children {
+		for b, next := range edges {
+			a.states[state].edges = append(a.states[state].edges, automatonEdge{b: b, next: next})
+		}
+		sort.Slice(a.states[state].edges, func(i, j int) bool {
+			return a.states[state].edges[i].b < a.states[state].edges[j].b
+		})
+	}
+
+	for _, edge := range a.states[rootState].edges {
+		a.rootNext[edge.b] = edge.next
+	}
+
+	// Compute the fail and output links in breadth-first order, so that the links of all
+	// shorter prefixes are known when a state is processed.
+	queue := make([]int32, 0, len(a.states))
+	for _, edge := range a.states[rootState].edges {
+		queue = append(queue, edge.next)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		s := &a.states[state]
+		if s.isSequence {
+			s.output = state
+		} else {
+			s.output = a.states[s.fail].output
+		}
+
+		for _, edge := range s.edges {
+			child := &a.states[edge.next]
+			if s.depth == 0 {
+				child.fail = rootState
+			} else {
+				child.fail = a.next(s.fail, edge.b)
 			}
+			queue = append(queue, edge.next)
 		}
 	}
-	m.currentIndex += int64(len(in))
-	return res
+	return a
 }
 
-// sequenceDetector detects if a sequence is present in the bytes it receives.
-type sequenceDetector struct {
-	sequence []byte
-	offset   int
-	index    int
+// next returns the state the automaton moves to when it receives the given byte in the given state.
+func (a *automaton) next(state int32, b byte) int32 {
+	for state != rootState {
+		// Apart from the root state, most states have a single edge, so a linear search is the fastest.
+		for _, edge := range a.states[state].edges {
+			if edge.b == b {
+				return edge.next
+			} else if edge.b > b {
+				break
+			}
+		}
+		state = a.states[state].fail
+	}
+	return a.rootNext[b]
 }
 
-// length returns the length of the sequence corrected for its offset.
-func (d *sequenceDetector) length() int {
-	return len(d.sequence) - d.offset
+// matcher finds the sequences of an automaton in the bytes written to a single stream.
+type matcher struct {
+	automaton    *automaton
+	state        int32
+	currentIndex int64
 }
 
-// writeByte takes in a new byte to match against.
-// Returns true if the given byte results in a match with sequence
-// The implementation tries to reduce the effect of the input on the execution duration as much as possible
-// to limit the information that can be derived from measuring the execution time of the masking functionality.
-func (d *sequenceDetector) writeByte(in byte) bool {
-	// Implementation of the following code that limits data-dependency as much as possible.
-	//
-	// 	if d.sequence[d.index] == in {
-	// 		d.index++
-	//
-	//		if d.index == len(d.sequence) {
-	//			d.index = 0
-	//			return true
-	//		}
-	//		return false
-	//	} else if d.sequence[0] == in{
-	//		d.index = d.offset + 1
-	//	} else {
-	//      d.index = 0
-	//  }
-	//	return false
-
-	newIndex := d.index
-
-	correctInput := subtle.ConstantTimeByteEq(d.sequence[newIndex], in)
-	newIndex = subtle.ConstantTimeSelect(correctInput, newIndex+1, newIndex)
-	sequenceComplete := subtle.ConstantTimeEq(int32(len(d.sequence)), int32(newIndex))
-
-	newIndexIfNotCorrectInput := subtle.ConstantTimeSelect(subtle.ConstantTimeByteEq(d.sequence[0], in), d.offset+1, 0)
-	newIndex = subtle.ConstantTimeSelect(correctInput, newIndex, newIndexIfNotCorrectInput)
-
-	d.index = subtle.ConstantTimeSelect(sequenceComplete, 0, newIndex)
-
-	return sequenceComplete == 1
+// newMatcher returns a new matcher that uses the given automaton.
+func newMatcher(a *automaton) *matcher {
+	return &matcher{
+		automaton: a,
+	}
 }
 
-// sequenceRepetitions finds all repetitions of bytes in the start of the sequence and returns a map of the length of
-// repeated sequences as the key and the number of repetitions as the value.
-// Example, if the input is aabaabaab, the "a" is repeated 1 time and "aab" is repeated 2 times,
-// so the result is: {1:1, 3:2}
-func sequenceRepetitions(seq []byte) map[int]int {
-	res := map[int]int{}
-	for i := 1; i < len(seq); i++ {
-		count := 0
-		for j := 1; i*(j+1) <= len(seq); j++ {
-			if bytes.Equal(seq[0:i], seq[i*j:i*(j+1)]) {
-				count++
-			} else {
-				break
-			}
-		}
-		if count > 0 {
-			res[i] = count
+// write takes in a slice of bytes and returns all matches of any of the sequences.
+// Matches can span multiple writes.
+func (m *matcher) write(in []byte) matches {
+	states := m.automaton.states
+	res := matches{}
+	for i, b := range in {
+		m.state = m.automaton.next(m.state, b)
+		// Follow the output links to find all sequences that end at this byte.
+		for output := states[m.state].output; output != -1; output = states[states[output].fail].output {
+			length := states[output].depth
+			res = res.add(m.currentIndex+int64(i-length+1), length)
 		}
 	}
+	m.currentIndex += int64(len(in))
 	return res
 }
@@ -6,7 +6,6 @@ import (
 	"strconv"
 	"strings"
 	"testing"
-	"time"
 
 	"github.com/secrethub/secrethub-go/internals/assert"
 	"github.com/secrethub/secrethub-go/pkg/randchar"
@@ -148,7 +147,7 @@ func TestMatcher(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			matcher := newMatcher(tc.sequences)
+			matcher := newMatcher(newAutomaton(tc.sequences))
 
 			for i, input := range tc.inputs {
 				t.Run(strconv.Itoa(i), func(t *testing.T) {
@@ -179,7 +178,7 @@ func TestMatcher_Repeats(t *testing.T) {
 				assert.OK(t, err)
 
 				input := append(prefix, input...)
-				matcher := newMatcher(sequences)
+				matcher := newMatcher(newAutomaton(sequences))
 
 				matches := matcher.write(input)
 
@@ -194,26 +193,26 @@ func TestMatcher_Repeats(t *testing.T) {
 
 }
 
-func TestSequenceDetector(t *testing.T) {
+func TestMatcher_SingleSequence(t *testing.T) {
 	tests := []struct {
 		matchString     string
 		input           string
-		expectedMatches []int
+		expectedMatches []int64
 	}{
 		{
 			matchString:     "test",
 			input:           "test",
-			expectedMatches: []int{0},
+			expectedMatches: []int64{0},
 		},
 		{
 			matchString:     "test",
 			input:           "ttest",
-			expectedMatches: []int{1},
+			expectedMatches: []int64{1},
 		},
 		{
 			matchString:     "test",
 			input:           "testtest",
-			expectedMatches: []int{0, 4},
+			expectedMatches: []int64{0, 4},
 		},
 		{
 			matchString:     "testtest",
@@ -223,12 +222,12 @@ func TestSequenceDetector(t *testing.T) {
 		{
 			matchString:     "foofoobar",
 			input:           "foofoofoobar",
-			expectedMatches: []int{}, // This case is handled by adding multiple detectors with newMatcher
+			expectedMatches: []int64{3},
 		},
 		{
 			matchString:     "test",
 			input:           "123 testtest",
-			expectedMatches: []int{4, 8},
+			expectedMatches: []int64{4, 8},
 		},
 		{
 			matchString:     "test",
@@ -248,12 +247,12 @@ func TestSequenceDetector(t *testing.T) {
 		{
 			matchString:     "t",
 			input:           "ttattt",
-			expectedMatches: []int{0, 1, 3, 4, 5},
+			expectedMatches: []int64{0, 1, 3, 4, 5},
 		},
 		{
 			matchString:     "tt",
 			input:           "ttattt",
-			expectedMatches: []int{0, 3},
+			expectedMatches: []int64{0, 3, 4},
 		},
 	}
 
@@ -261,74 +260,82 @@ func TestSequenceDetector(t *testing.T) {
 		name := fmt.Sprintf("%s in %s", tc.matchString, tc.input)
 
 		t.Run(name, func(t *testing.T) {
-			matcher := sequenceDetector{sequence: []byte(tc.matchString)}
-			var matches []int
-			for i, b := range []byte(tc.input) {
-				match := matcher.writeByte(b)
-				if match {
-					matches = append(matches, i-len(tc.matchString)+1)
+			matcher := newMatcher(newAutomaton([][]byte{[]byte(tc.matchString)}))
+
+			// Write the input byte by byte to check that matches are found across writes.
+			var matches []int64
+			for _, b := range []byte(tc.input) {
+				for index := range matcher.write([]byte{b}) {
+					matches = append(matches, index)
 				}
 			}
 			assert.Equal(t, matches, tc.expectedMatches)
 		})
 	}
-
 }
 
-func TestSequenceRepetitions(t *testing.T) {
+func TestMatcher_Repetitions(t *testing.T) {
 	cases := map[string]struct {
-		sequence string
-		want     map[int]int
+		sequences []string
+		input     string
+		want      matches
 	}{
-		"no repetition": {
-			sequence: "abcd",
-			want:     map[int]int{},
-		},
-		"single sequence": {
-			sequence: "aabcd",
-			want: map[int]int{
-				1: 1,
-			},
+		"repeated prefix": {
+			sequences: []string{"aabcd"},
+			input:     "aaabcd",
+			want:      matches{1: 5},
 		},
-		"single sequence multiple times": {
-			sequence: "aaaabcd",
-			want: map[int]int{
-				1: 3,
-				2: 1,
-			},
+		"repeated prefix multiple times": {
+			sequences: []string{"aaaabcd"},
+			input:     "aaaaaaabcd",
+			want:      matches{3: 7},
 		},
-		"double repetition": {
-			sequence: "aabcaabc",
-			want: map[int]int{
-				1: 1,
-				4: 1,
-			},
+		"repeated sequence": {
+			sequences: []string{"aabcaabc"},
+			input:     "aabcaabcaabc",
+			want:      matches{0: 8, 4: 8},
 		},
 		"repetition with divider": {
-			sequence: "abcdabc",
-			want:     map[int]int{},
+			sequences: []string{"abcdabc"},
+			input:     "abcdabcdabc",
+			want:      matches{0: 7, 4: 7},
+		},
+		"overlapping sequences": {
+			sequences: []string{"abc", "bcd", "cd"},
+			input:     "abcd",
+			want:      matches{0: 3, 1: 3, 2: 2},
+		},
+		"sequence that is a suffix of another": {
+			sequences: []string{"he", "she", "his", "hers"},
+			input:     "ushers",
+			want:      matches{1: 3, 2: 4},
+		},
+		"sequence that is a prefix of another": {
+			sequences: []string{"foo", "foofoobar"},
+			input:     "foofoofoobar",
+			want:      matches{0: 3, 3: 9, 6: 3},
 		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got := sequenceRepetitions([]byte(tc.sequence))
+			sequences := make([][]byte, len(tc.sequences))
+			for i, sequence := range tc.sequences {
+				sequences[i] = []byte(sequence)
+			}
+			matcher := newMatcher(newAutomaton(sequences))
+
+			got := matcher.write([]byte(tc.input))
 
 			assert.Equal(t, got, tc.want)
 		})
 	}
 }
 
-func doBench(sequences [][]byte, input []byte) int {
-	m := newMatcher(sequences)
+func doBench(a *automaton, input []byte) int {
+	m := newMatcher(a)
 	_ = m.write(input)
-	maxIndex := 0
-	for _, d := range m.detectors {
-		if d.index > maxIndex {
-			maxIndex = d.index
-		}
-	}
-	return maxIndex
+	return a.states[m.state].depth
 }
 
 func BenchmarkMatcher(b *testing.B) {
@@ -348,27 +355,21 @@ func BenchmarkMatcher(b *testing.B) {
 		badSequences[i] = badSeq
 	}
 
-	startTime := time.Now()
-	for -time.Until(startTime) < time.Minute {
-		for i := 0; i < len(sequences); i++ {
-			index := doBench(sequences, goodSequences[i])
-			assert.Equal(b, index, 512)
-			index = doBench(sequences, badSequences[i])
-			assert.Equal(b, index < 512, true)
-		}
+	a := newAutomaton(sequences)
+	for i := range sequences {
+		assert.Equal(b, doBench(a, goodSequences[i]), 512)
+		assert.Equal(b, doBench(a, badSequences[i]) < 512, true)
 	}
 
-	fmt.Println("end warming up")
-
 	b.Run("random sequences", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			doBench(sequences, badSequences[rand.Intn(len(badSequences))])
+			doBench(a, badSequences[rand.Intn(len(badSequences))])
 		}
 	})
 
 	b.Run("matching sequences", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			doBench(sequences, goodSequences[rand.Intn(len(goodSequences))])
+			doBench(a, goodSequences[rand.Intn(len(goodSequences))])
 		}
 	})
 
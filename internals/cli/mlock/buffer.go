@@ -0,0 +1,54 @@
+package mlock
+
+// Buffer holds decrypted data, such as a secret or an unlocked credential.
+// When possible, its memory is locked, so that it is never written to swap,
+// also when memory locking of the whole process is off. It is zeroed when it is wiped.
+type Buffer struct {
+	data   []byte
+	mapped bool
+}
+
+// NewBuffer returns a buffer of the given size, which must be wiped with Wipe after use.
+func NewBuffer(size int) *Buffer {
+	if size > 0 && !Locked() {
+		data, err := allocateLocked(size)
+		if err == nil {
+			return &Buffer{data: data, mapped: true}
+		}
+		log.Debugf("cannot allocate locked memory: %s", err)
+	}
+	return &Buffer{data: make([]byte, size)}
+}
+
+// CopyToBuffer returns a buffer that holds a copy of the data and wipes the data.
+func CopyToBuffer(data []byte) *Buffer {
+	b := NewBuffer(len(data))
+	copy(b.data, data)
+	Wipe(data)
+	return b
+}
+
+// Bytes returns the contents of the buffer. They must not be used after the buffer is wiped.
+func (b *Buffer) Bytes() []byte {
+	return b.data
+}
+
+// Wipe zeroes the buffer and releases its memory.
+func (b *Buffer) Wipe() {
+	Wipe(b.data)
+	if b.mapped {
+		err := free(b.data)
+		if err != nil {
+			log.Debugf("cannot free locked memory: %s", err)
+		}
+		b.mapped = false
+	}
+	b.data = nil
+}
+
+// Wipe zeroes the data.
+func Wipe(data []byte) {
+	for i := range data {
+		data[i] = 0
+	}
+}
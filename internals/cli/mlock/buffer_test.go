@@ -0,0 +1,25 @@
+package mlock
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestCopyToBuffer(t *testing.T) {
+	data := []byte("secret")
+
+	buffer := CopyToBuffer(data)
+
+	assert.Equal(t, string(buffer.Bytes()), "secret")
+	assert.Equal(t, data, make([]byte, 6))
+
+	buffer.Wipe()
+	assert.Equal(t, len(buffer.Bytes()), 0)
+}
+
+func TestApply(t *testing.T) {
+	assert.OK(t, Apply(ModeOff))
+	assert.OK(t, Apply("false"))
+	assert.Equal(t, Apply("always"), ErrUnknownMode("always"))
+}
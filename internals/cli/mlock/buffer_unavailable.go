@@ -0,0 +1,12 @@
+//go:build android || darwin || nacl || netbsd || plan9 || windows
+
+package mlock
+
+// allocateLocked always returns ErrNotSupported, so that buffers are allocated on the heap.
+func allocateLocked(size int) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+func free(data []byte) error {
+	return nil
+}
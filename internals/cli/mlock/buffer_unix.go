@@ -0,0 +1,32 @@
+//go:build dragonfly || freebsd || linux || openbsd || solaris
+
+package mlock
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// allocateLocked maps memory outside of the Go heap and locks it. The memory
+// is not moved or copied by the runtime, so wiping it leaves no copies behind.
+func allocateLocked(size int) ([]byte, error) {
+	data, err := unix.Mmap(-1, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+
+	err = unix.Mlock(data)
+	if err != nil {
+		_ = unix.Munmap(data)
+		return nil, err
+	}
+	return data, nil
+}
+
+// free unlocks and unmaps memory that was allocated with allocateLocked.
+func free(data []byte) error {
+	err := unix.Munlock(data)
+	if err != nil {
+		return err
+	}
+	return unix.Munmap(data)
+}
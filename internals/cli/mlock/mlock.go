@@ -2,6 +2,8 @@
 package mlock
 
 import (
+	"strings"
+
 	"github.com/secrethub/secrethub-cli/internals/cli"
 
 	"github.com/secrethub/secrethub-go/internals/errio"
@@ -17,8 +19,26 @@ var (
 
 	// ErrNotSupported is returned when mlock is not available for the platform
 	ErrNotSupported = errMlock.Code("not_supported").Error("mlock is not supported")
+	// ErrUnknownMode is returned when a mode is not one of Modes.
+	ErrUnknownMode = errMlock.Code("unknown_mode").ErrorPref("unknown mlock mode %s: must be one of " + strings.Join(Modes, ", "))
+
+	// locked is set when all memory of the process is locked.
+	locked bool
 )
 
+// Modes of memory locking
+const (
+	// ModeAuto locks memory when it is supported and allowed, and continues without locking otherwise.
+	ModeAuto = "auto"
+	// ModeOn locks memory and fails when that is not possible.
+	ModeOn = "on"
+	// ModeOff does not lock memory.
+	ModeOff = "off"
+)
+
+// Modes are the names of the modes that can be selected.
+var Modes = []string{ModeAuto, ModeOn, ModeOff}
+
 // Supported returns true if LockMemory is available on the system.
 func Supported() bool {
 	return available
@@ -27,7 +47,43 @@ func Supported() bool {
 // LockMemory prevents any memory being written to disk as swap.
 func LockMemory() error {
 	if Supported() {
-		return lockMemory()
+		err := lockMemory()
+		if err != nil {
+			return err
+		}
+		locked = true
+		return nil
 	}
 	return ErrNotSupported
 }
+
+// Locked returns true when all memory of the process is locked.
+func Locked() bool {
+	return locked
+}
+
+// Apply locks memory according to the given mode. For backwards compatibility
+// with the boolean flag, true and false are accepted as on and off.
+func Apply(mode string) error {
+	switch strings.ToLower(mode) {
+	case ModeOff, "false", "":
+		return nil
+	case ModeOn, "true":
+		return LockMemory()
+	case ModeAuto:
+		if !Supported() {
+			log.Debugf("memory is not locked: %s", ErrNotSupported)
+			return nil
+		}
+		if !allowed() {
+			log.Debugf("memory is not locked: the process is not allowed to lock all of its memory")
+			return nil
+		}
+		err := LockMemory()
+		if err != nil {
+			log.Debugf("memory is not locked: %s", err)
+		}
+		return nil
+	}
+	return ErrUnknownMode(mode)
+}
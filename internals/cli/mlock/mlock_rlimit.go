@@ -0,0 +1,21 @@
+//go:build dragonfly || freebsd || linux || openbsd
+
+package mlock
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// allowed returns true when the process may lock all of its current and future memory.
+// Locking succeeds when the locked memory fits in the limit, but allocating memory fails
+// once the limit is reached. So memory is only locked when there is no limit, or for root.
+func allowed() bool {
+	var limit unix.Rlimit
+	err := unix.Getrlimit(unix.RLIMIT_MEMLOCK, &limit)
+	if err == nil && limit.Cur == unix.RLIM_INFINITY {
+		return true
+	}
+	return os.Geteuid() == 0
+}
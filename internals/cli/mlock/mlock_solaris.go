@@ -0,0 +1,12 @@
+//go:build solaris
+
+package mlock
+
+import (
+	"os"
+)
+
+// allowed returns true when the process may lock all of its memory, which requires root privileges.
+func allowed() bool {
+	return os.Geteuid() == 0
+}
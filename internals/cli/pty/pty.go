@@ -0,0 +1,41 @@
+// Package pty provides functionality to run a process in a pseudo-terminal.
+package pty
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// Errors
+var (
+	errPTY = errio.Namespace("pty")
+
+	// ErrNotSupported is returned when pseudo-terminals are not available on the platform.
+	ErrNotSupported = errPTY.Code("not_supported").Error("pseudo-terminals are not supported on this platform")
+)
+
+// Start starts the command with a new pseudo-terminal as its stdin, stdout and stderr, which also becomes
+// its controlling terminal. It returns the controlling side of the pseudo-terminal, through which the
+// input of the command is written and its output is read. The caller is responsible for closing it.
+func Start(cmd *exec.Cmd) (*os.File, error) {
+	ptmx, tty, err := open()
+	if err != nil {
+		return nil, err
+	}
+	// The terminal of the command is closed in this process once the command has it.
+	defer tty.Close()
+
+	cmd.Stdin = tty
+	cmd.Stdout = tty
+	cmd.Stderr = tty
+	setControllingTerminal(cmd)
+
+	err = cmd.Start()
+	if err != nil {
+		ptmx.Close()
+		return nil, err
+	}
+	return ptmx, nil
+}
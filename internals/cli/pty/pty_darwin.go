@@ -0,0 +1,57 @@
+package pty
+
+import (
+	"bytes"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// open opens a new pseudo-terminal and returns its controlling side and its terminal.
+func open() (*os.File, *os.File, error) {
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	name, err := grantAndUnlock(ptmx)
+	if err != nil {
+		ptmx.Close()
+		return nil, nil, err
+	}
+
+	tty, err := os.OpenFile(name, os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		ptmx.Close()
+		return nil, nil, err
+	}
+	return ptmx, tty, nil
+}
+
+// grantAndUnlock performs grantpt and unlockpt on the pseudo-terminal and returns the path of its terminal.
+func grantAndUnlock(ptmx *os.File) (string, error) {
+	fd := ptmx.Fd()
+
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, fd, unix.TIOCPTYGRANT, 0)
+	if errno != 0 {
+		return "", errno
+	}
+	_, _, errno = unix.Syscall(unix.SYS_IOCTL, fd, unix.TIOCPTYUNLK, 0)
+	if errno != 0 {
+		return "", errno
+	}
+
+	// The name is returned in a buffer of 128 bytes, as defined by TIOCPTYGNAME.
+	buf := make([]byte, 128)
+	_, _, errno = unix.Syscall(unix.SYS_IOCTL, fd, unix.TIOCPTYGNAME, uintptr(unsafe.Pointer(&buf[0])))
+	if errno != 0 {
+		return "", errno
+	}
+
+	end := bytes.IndexByte(buf, 0)
+	if end < 0 {
+		end = len(buf)
+	}
+	return string(buf[:end]), nil
+}
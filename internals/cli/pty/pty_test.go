@@ -0,0 +1,33 @@
+//go:build linux || darwin
+
+package pty
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "test -t 0 && test -t 1 && test -t 2 && echo terminal")
+
+	ptmx, err := Start(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ptmx.Close()
+
+	// Reading fails with EIO once the command has exited and closed the terminal,
+	// so the output is read up to the first error.
+	var out bytes.Buffer
+	_, _ = io.Copy(&out, ptmx)
+
+	err = cmd.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("terminal")) {
+		t.Errorf("unexpected output %q: the command is not connected to a terminal", out.String())
+	}
+}
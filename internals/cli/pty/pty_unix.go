@@ -0,0 +1,56 @@
+//go:build linux || darwin
+
+package pty
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// setControllingTerminal makes the command start a new session with its stdin as controlling terminal.
+func setControllingTerminal(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0
+}
+
+// CopySize sets the window size of the pseudo-terminal to the window size of the given terminal.
+func CopySize(pty *os.File, terminal *os.File) error {
+	size, err := unix.IoctlGetWinsize(int(terminal.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return err
+	}
+	return unix.IoctlSetWinsize(int(pty.Fd()), unix.TIOCSWINSZ, size)
+}
+
+// NotifyResize keeps the window size of the pseudo-terminal equal to the window size of the given terminal,
+// until the returned function is called. The process in the pseudo-terminal is notified of every change.
+func NotifyResize(pty *os.File, terminal *os.File) func() {
+	resized := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(resized, syscall.SIGWINCH)
+
+	go func() {
+		for {
+			select {
+			case <-resized:
+				// Resizing is a best effort, the process keeps running with the old size when it fails.
+				_ = CopySize(pty, terminal)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(resized)
+		close(done)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !linux && !darwin
+
+package pty
+
+import (
+	"os"
+	"os/exec"
+)
+
+func open() (*os.File, *os.File, error) {
+	return nil, nil, ErrNotSupported
+}
+
+func setControllingTerminal(cmd *exec.Cmd) {}
+
+// CopySize is not supported on this platform.
+func CopySize(pty *os.File, terminal *os.File) error {
+	return ErrNotSupported
+}
+
+// NotifyResize is not supported on this platform, so the returned function does nothing.
+func NotifyResize(pty *os.File, terminal *os.File) func() {
+	return func() {}
+}
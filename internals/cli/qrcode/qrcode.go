@@ -0,0 +1,488 @@
+// Package qrcode encodes short pieces of data, such as backup codes, as QR codes that can be printed in a terminal.
+//
+// Only what is needed for that is supported: the data is encoded in byte mode with error correction level M,
+// in a QR code of version 1 up to version 6, which fits up to 106 bytes.
+package qrcode
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors
+var (
+	ErrTooLong = errors.New("qrcode: data is too long")
+)
+
+// quietZone is the number of light modules around the code that scanners need to find it.
+const quietZone = 4
+
+// version describes the layout of a QR code of a version with error correction level M.
+type version struct {
+	number int
+	// ecPerBlock is the number of error correction codewords of every block.
+	ecPerBlock int
+	// blocks are the number of data codewords of every block.
+	blocks []int
+	// align are the positions of the centers of the alignment patterns on both axes.
+	align []int
+}
+
+// versions are the supported versions with error correction level M.
+var versions = []version{
+	{number: 1, ecPerBlock: 10, blocks: []int{16}},
+	{number: 2, ecPerBlock: 16, blocks: []int{28}, align: []int{6, 18}},
+	{number: 3, ecPerBlock: 26, blocks: []int{44}, align: []int{6, 22}},
+	{number: 4, ecPerBlock: 18, blocks: []int{32, 32}, align: []int{6, 26}},
+	{number: 5, ecPerBlock: 24, blocks: []int{43, 43}, align: []int{6, 30}},
+	{number: 6, ecPerBlock: 16, blocks: []int{27, 27, 27, 27}, align: []int{6, 34}},
+}
+
+// dataCodewords returns the number of data codewords of the version.
+func (v version) dataCodewords() int {
+	n := 0
+	for _, block := range v.blocks {
+		n += block
+	}
+	return n
+}
+
+// Code is a QR code.
+type Code struct {
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+// Encode returns the smallest QR code that contains the data.
+func Encode(data []byte) (*Code, error) {
+	for _, v := range versions {
+		// The byte mode indicator and the character count take 12 bits.
+		if 12+8*len(data) <= 8*v.dataCodewords() {
+			return encode(v, data), nil
+		}
+	}
+	return nil, ErrTooLong
+}
+
+// Size returns the number of modules on each side of the code, without the quiet zone.
+func (c *Code) Size() int {
+	return c.size
+}
+
+// Dark returns whether the module at the given column and row is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// String renders the code with block characters, surrounded by a quiet zone.
+// Every character represents two rows of modules. The light modules are drawn,
+// so the code can be scanned from a terminal with light text on a dark background.
+func (c *Code) String() string {
+	light := func(x, y int) bool {
+		x -= quietZone
+		y -= quietZone
+		if x < 0 || y < 0 || x >= c.size || y >= c.size {
+			return true
+		}
+		return !c.modules[y][x]
+	}
+
+	total := c.size + 2*quietZone
+	var b strings.Builder
+	for y := 0; y < total; y += 2 {
+		for x := 0; x < total; x++ {
+			top := light(x, y)
+			bottom := light(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// encode returns the QR code of the given version that contains the data.
+func encode(v version, data []byte) *Code {
+	size := 17 + 4*v.number
+	c := &Code{
+		size:     size,
+		modules:  make([][]bool, size),
+		function: make([][]bool, size),
+	}
+	for i := range c.modules {
+		c.modules[i] = make([]bool, size)
+		c.function[i] = make([]bool, size)
+	}
+
+	c.drawFunctionPatterns(v)
+	c.drawCodewords(codewords(v, data))
+
+	bestMask := 0
+	bestPenalty := -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		penalty := c.penalty()
+		if bestPenalty < 0 || penalty < bestPenalty {
+			bestMask = mask
+			bestPenalty = penalty
+		}
+		// Applying the same mask again undoes it.
+		c.applyMask(mask)
+	}
+	c.applyMask(bestMask)
+	c.drawFormatBits(bestMask)
+	return c
+}
+
+// codewords returns the data and error correction codewords of the data, interleaved as they are placed in the code.
+func codewords(v version, data []byte) []byte {
+	capacity := v.dataCodewords()
+
+	var bits bitBuffer
+	bits.append(0x4, 4) // Byte mode
+	bits.append(len(data), 8)
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	terminator := 8*capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	bits.append(0, (8-len(bits)%8)%8)
+
+	encoded := bits.bytes()
+	for pad := byte(0xEC); len(encoded) < capacity; pad ^= 0xEC ^ 0x11 {
+		encoded = append(encoded, pad)
+	}
+
+	var dataBlocks, ecBlocks [][]byte
+	for _, n := range v.blocks {
+		block := encoded[:n]
+		encoded = encoded[n:]
+		dataBlocks = append(dataBlocks, block)
+		ecBlocks = append(ecBlocks, reedSolomon(block, v.ecPerBlock))
+	}
+
+	var res []byte
+	for _, blocks := range [][][]byte{dataBlocks, ecBlocks} {
+		for i := 0; ; i++ {
+			added := false
+			for _, block := range blocks {
+				if i < len(block) {
+					res = append(res, block[i])
+					added = true
+				}
+			}
+			if !added {
+				break
+			}
+		}
+	}
+	return res
+}
+
+// setFunction sets a module that is part of a function pattern.
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+// drawFunctionPatterns draws the timing, finder and alignment patterns and reserves the modules of the format bits.
+func (c *Code) drawFunctionPatterns(v version) {
+	for i := 0; i < c.size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	for _, center := range [][2]int{{3, 3}, {c.size - 4, 3}, {3, c.size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := center[0]+dx, center[1]+dy
+				if x < 0 || y < 0 || x >= c.size || y >= c.size {
+					continue
+				}
+				distance := max(abs(dx), abs(dy))
+				c.setFunction(x, y, distance != 2 && distance != 4)
+			}
+		}
+	}
+
+	last := len(v.align) - 1
+	for i, y := range v.align {
+		for j, x := range v.align {
+			// Alignment patterns are not drawn over the finder patterns.
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	c.drawFormatBits(0)
+}
+
+// drawFormatBits draws both copies of the format bits of error correction level M and the given mask.
+func (c *Code) drawFormatBits(mask int) {
+	// The bits of error correction level M are 00.
+	data := mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool {
+		return bits>>i&1 == 1
+	}
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(i))
+	}
+	c.setFunction(8, 7, bit(6))
+	c.setFunction(8, 8, bit(7))
+	c.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.size-15+i, bit(i))
+	}
+	// The dark module is always dark.
+	c.setFunction(8, c.size-8, true)
+}
+
+// drawCodewords places the codewords in the modules that are not part of a function pattern,
+// in columns of two modules wide, from the bottom right going up and down in turns.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.size - 1; right >= 1; right -= 2 {
+		// The vertical timing pattern is skipped.
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.size; vert++ {
+			y := vert
+			if upward {
+				y = c.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if c.function[y][x] {
+					continue
+				}
+				// The remainder bits after the last codeword are light.
+				if i < 8*len(data) {
+					c.modules[y][x] = data[i/8]>>(7-i%8)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask inverts the modules that are not part of a function pattern for which the mask condition holds.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if c.function[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty returns how hard the code is to scan, as defined by the QR code specification.
+// The mask with the lowest penalty is used.
+func (c *Code) penalty() int {
+	penalty := 0
+	finderLike := []bool{true, false, true, true, true, false, true}
+
+	for _, horizontal := range []bool{true, false} {
+		line := make([]bool, c.size)
+		for i := 0; i < c.size; i++ {
+			for j := 0; j < c.size; j++ {
+				if horizontal {
+					line[j] = c.modules[i][j]
+				} else {
+					line[j] = c.modules[j][i]
+				}
+			}
+
+			// Runs of five or more modules of the same color.
+			run := 1
+			for j := 1; j <= c.size; j++ {
+				if j < c.size && line[j] == line[j-1] {
+					run++
+					continue
+				}
+				if run >= 5 {
+					penalty += 3 + run - 5
+				}
+				run = 1
+			}
+
+			// Patterns that look like a finder pattern, with four light modules on either side.
+			for j := 0; j+len(finderLike) <= c.size; j++ {
+				matches := true
+				for k, dark := range finderLike {
+					if line[j+k] != dark {
+						matches = false
+						break
+					}
+				}
+				if matches && (lightRun(line, j-4, j) || lightRun(line, j+len(finderLike), j+len(finderLike)+4)) {
+					penalty += 40
+				}
+			}
+		}
+	}
+
+	// Blocks of 2x2 modules of the same color.
+	dark := 0
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x > 0 && y > 0 {
+				color := c.modules[y][x]
+				if color == c.modules[y-1][x] && color == c.modules[y][x-1] && color == c.modules[y-1][x-1] {
+					penalty += 3
+				}
+			}
+		}
+	}
+
+	// The balance of dark and light modules.
+	total := c.size * c.size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	if k > 0 {
+		penalty += k * 10
+	}
+	return penalty
+}
+
+// lightRun returns whether the modules from start up to end are all light.
+// Modules outside of the code are light.
+func lightRun(line []bool, start, end int) bool {
+	for i := start; i < end; i++ {
+		if i >= 0 && i < len(line) && line[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// bitBuffer is a sequence of bits.
+type bitBuffer []bool
+
+// append appends the n lowest bits of value, the most significant bit first.
+func (b *bitBuffer) append(value int, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, value>>i&1 == 1)
+	}
+}
+
+// bytes returns the bits packed in bytes. The length of the buffer must be a multiple of 8.
+func (b bitBuffer) bytes() []byte {
+	res := make([]byte, len(b)/8)
+	for i, bit := range b {
+		if bit {
+			res[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return res
+}
+
+// reedSolomon returns the n error correction codewords of the data.
+func reedSolomon(data []byte, n int) []byte {
+	// The generator polynomial is the product of (x - 2^i) for i from 0 up to n, without the leading 1.
+	generator := make([]byte, n)
+	generator[n-1] = 1
+	root := byte(1)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			generator[j] = gfMultiply(generator[j], root)
+			if j+1 < n {
+				generator[j] ^= generator[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+
+	res := make([]byte, n)
+	for _, b := range data {
+		factor := b ^ res[0]
+		copy(res, res[1:])
+		res[n-1] = 0
+		for i := range res {
+			res[i] ^= gfMultiply(generator[i], factor)
+		}
+	}
+	return res
+}
+
+// gfMultiply multiplies two elements of the Galois field GF(2^8) with the polynomial x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
@@ -0,0 +1,104 @@
+package qrcode
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// The codewords of "HELLO WORLD" in a version 1 code with error correction level M.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	expected := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	actual := reedSolomon(data, 10)
+
+	assert.Equal(t, actual, expected)
+}
+
+func TestDrawFormatBits(t *testing.T) {
+	c := encode(versions[0], []byte("test"))
+	c.drawFormatBits(0)
+
+	// Read the copy of the format bits next to the top right and bottom left finder patterns.
+	actual := 0
+	for i := 0; i < 15; i++ {
+		var dark bool
+		if i < 8 {
+			dark = c.Dark(c.size-1-i, 8)
+		} else {
+			dark = c.Dark(8, c.size-15+i)
+		}
+		if dark {
+			actual |= 1 << i
+		}
+	}
+
+	// The format bits of error correction level M and mask 0.
+	assert.Equal(t, actual, 0x5412)
+}
+
+func TestEncode(t *testing.T) {
+	cases := map[string]struct {
+		length       int
+		expectedSize int
+		expectedErr  error
+	}{
+		"version 1": {
+			length:       14,
+			expectedSize: 21,
+		},
+		"version 2": {
+			length:       15,
+			expectedSize: 25,
+		},
+		"backup code": {
+			length:       71,
+			expectedSize: 37,
+		},
+		"version 6": {
+			length:       106,
+			expectedSize: 41,
+		},
+		"too long": {
+			length:      107,
+			expectedErr: ErrTooLong,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			code, err := Encode(bytes.Repeat([]byte("A"), tc.length))
+
+			assert.Equal(t, err, tc.expectedErr)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, code.Size(), tc.expectedSize)
+
+			// The top left finder pattern.
+			for i := 0; i < 7; i++ {
+				assert.Equal(t, code.Dark(i, 0), true)
+				assert.Equal(t, code.Dark(0, i), true)
+				assert.Equal(t, code.Dark(i, 7), false)
+				assert.Equal(t, code.Dark(7, i), false)
+			}
+		})
+	}
+}
+
+func TestCode_String(t *testing.T) {
+	code, err := Encode([]byte("test"))
+	assert.OK(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(code.String(), "\n"), "\n")
+
+	// Every line represents two rows of the code of 21 modules with a quiet zone of 4 modules on both sides.
+	assert.Equal(t, len(lines), 15)
+	for _, line := range lines {
+		assert.Equal(t, len([]rune(line)), 29)
+	}
+	assert.Equal(t, lines[0], strings.Repeat("█", 29))
+}
@@ -0,0 +1,61 @@
+// Package secval holds secret values in memory that can be wiped once they are no longer needed.
+package secval
+
+import (
+	"bytes"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/mlock"
+)
+
+// Secret is a secret value, such as a decrypted secret or an injected template. The value is kept
+// in a locked buffer when possible. Call Wipe once the value has been written to its destination,
+// so that it does not stay in memory, where it could end up in swap or a core dump.
+type Secret struct {
+	buffer *mlock.Buffer
+}
+
+// New returns a secret that holds the data. The data is copied into the secret and then wiped,
+// so the data must not be used afterwards.
+func New(data []byte) *Secret {
+	return &Secret{
+		buffer: mlock.CopyToBuffer(data),
+	}
+}
+
+// Bytes returns the value. It must not be used after the secret is wiped.
+func (s *Secret) Bytes() []byte {
+	return s.buffer.Bytes()
+}
+
+// Len returns the length of the value.
+func (s *Secret) Len() int {
+	return len(s.buffer.Bytes())
+}
+
+// WithNewLine returns a new secret that holds the value followed by a newline,
+// unless the value already ends with one. Both secrets have to be wiped.
+func (s *Secret) WithNewLine() *Secret {
+	value := s.Bytes()
+	size := len(value)
+	if !bytes.HasSuffix(value, []byte("\n")) {
+		size++
+	}
+
+	buffer := mlock.NewBuffer(size)
+	n := copy(buffer.Bytes(), value)
+	if n < size {
+		buffer.Bytes()[n] = '\n'
+	}
+	return &Secret{buffer: buffer}
+}
+
+// Wipe zeroes the value. A secret can be wiped more than once.
+func (s *Secret) Wipe() {
+	s.buffer.Wipe()
+}
+
+// String does not return the value, so that it does not end up in output or logs by accident.
+// Use Bytes to get the value.
+func (s *Secret) String() string {
+	return "<secret>"
+}
@@ -0,0 +1,56 @@
+package secval
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestNew(t *testing.T) {
+	data := []byte("secret")
+
+	secret := New(data)
+
+	assert.Equal(t, string(secret.Bytes()), "secret")
+	assert.Equal(t, secret.Len(), 6)
+	assert.Equal(t, data, make([]byte, 6))
+	assert.Equal(t, fmt.Sprintf("%s", secret), "<secret>")
+
+	secret.Wipe()
+	assert.Equal(t, secret.Len(), 0)
+	secret.Wipe()
+}
+
+func TestSecret_WithNewLine(t *testing.T) {
+	cases := map[string]struct {
+		value    string
+		expected string
+	}{
+		"without newline": {
+			value:    "secret",
+			expected: "secret\n",
+		},
+		"with newline": {
+			value:    "secret\n",
+			expected: "secret\n",
+		},
+		"empty": {
+			value:    "",
+			expected: "\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			secret := New([]byte(tc.value))
+			defer secret.Wipe()
+
+			actual := secret.WithNewLine()
+			defer actual.Wipe()
+
+			assert.Equal(t, string(actual.Bytes()), tc.expected)
+			assert.Equal(t, string(secret.Bytes()), tc.value)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"io"
+)
+
+// nonInteractiveIO is an IO that refuses to prompt for input while non-interactive mode is enabled.
+type nonInteractiveIO struct {
+	IO
+	enabled func() bool
+}
+
+// NewNonInteractiveIO wraps the given IO so that prompting for input fails with ErrCannotAsk
+// whenever enabled returns true, even when a terminal is available. This makes sure
+// that a process never hangs while waiting for input that will never come.
+func NewNonInteractiveIO(io IO, enabled func() bool) IO {
+	return nonInteractiveIO{
+		IO:      io,
+		enabled: enabled,
+	}
+}
+
+// Prompts returns ErrCannotAsk in non-interactive mode and the prompts of the wrapped IO otherwise.
+func (o nonInteractiveIO) Prompts() (io.Reader, io.Writer, error) {
+	if o.enabled() {
+		return nil, nil, ErrCannotAsk
+	}
+	return o.IO.Prompts()
+}
+
+// ReadSecret returns ErrCannotAsk in non-interactive mode and reads a secret from the wrapped IO otherwise.
+func (o nonInteractiveIO) ReadSecret() ([]byte, error) {
+	if o.enabled() {
+		return nil, ErrCannotAsk
+	}
+	return o.IO.ReadSecret()
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestNonInteractiveIO(t *testing.T) {
+	cases := map[string]struct {
+		nonInteractive bool
+		expected       string
+		err            error
+	}{
+		"interactive": {
+			expected: "answer",
+		},
+		"non-interactive": {
+			nonInteractive: true,
+			err:            ErrCannotAsk,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fakeIO := fakeui.NewIO(t)
+			fakeIO.PromptIn.Reads = []string{"answer"}
+			fakeIO.PasswordReader.Reads = []string{"answer"}
+
+			io := NewNonInteractiveIO(fakeIO, func() bool { return tc.nonInteractive })
+
+			actual, err := Ask(io, "question?")
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual, tc.expected)
+
+			actual, err = AskSecret(io, "secret?")
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pickerMatches is the maximum number of matches that is shown at once.
+const pickerMatches = 10
+
+// Errors
+var (
+	ErrNothingPicked = askErr.Code("nothing_picked").Error("nothing was picked")
+)
+
+// Pick lets the user pick one of the options returned by getOptions by narrowing them down
+// with a fuzzy filter. The options are loaded incrementally: getOptions is only called again
+// when fewer matches than can be shown have been found and it has not reported that it is done.
+// Pressing [ENTER] without a filter cancels the picker, in which case ErrNothingPicked is returned.
+// It returns the value of the picked option.
+func Pick(io IO, question string, optionName string, getOptions func() ([]Option, bool, error)) (string, error) {
+	r, w, err := io.Prompts()
+	if err != nil {
+		return "", err
+	}
+
+	p := picker{
+		w:          w,
+		getOptions: getOptions,
+		optionName: optionName,
+	}
+
+	fmt.Fprintf(w, "%s\n", question)
+	filter := ""
+	for {
+		matches, err := p.matches(filter)
+		if err != nil {
+			return "", err
+		}
+		p.print(filter, matches)
+
+		in, err := Readln(r)
+		if err != nil {
+			return "", err
+		}
+		in = strings.TrimSpace(in)
+
+		choice, err := strconv.Atoi(in)
+		if err == nil && choice >= 1 && choice <= len(matches) && choice <= pickerMatches {
+			return matches[choice-1].Value, nil
+		}
+
+		if in == "" && filter == "" {
+			return "", ErrNothingPicked
+		}
+		filter = in
+	}
+}
+
+type picker struct {
+	w          io.Writer
+	getOptions func() ([]Option, bool, error)
+	optionName string
+
+	options []Option
+	done    bool
+}
+
+// matches returns the loaded options that match the filter, the best match first.
+// More options are loaded until there are enough matches to show or all options are loaded.
+func (p *picker) matches(filter string) ([]Option, error) {
+	for {
+		matches := rankFuzzyMatches(filter, p.options)
+		if len(matches) >= pickerMatches || p.done {
+			return matches, nil
+		}
+
+		options, done, err := p.getOptions()
+		if err != nil {
+			return nil, err
+		}
+		p.options = append(p.options, options...)
+		p.done = done
+	}
+}
+
+// print prints the best matches and asks the user to pick one of them or to change the filter.
+func (p *picker) print(filter string, matches []Option) {
+	if len(matches) == 0 {
+		fmt.Fprintf(p.w, "No %ss match %q.\n", p.optionName, filter)
+	}
+	for i, match := range matches {
+		if i == pickerMatches {
+			break
+		}
+		fmt.Fprintf(p.w, "  %d) %s\n", i+1, match)
+	}
+	if len(matches) > pickerMatches {
+		fmt.Fprintf(p.w, "  ... and %d more\n", len(matches)-pickerMatches)
+	} else if !p.done && len(matches) == pickerMatches {
+		fmt.Fprintln(p.w, "  ... and possibly more")
+	}
+
+	if filter == "" {
+		fmt.Fprintf(p.w, "Type the number of a %s, type to filter or press [ENTER] to cancel: ", p.optionName)
+	} else {
+		fmt.Fprintf(p.w, "Type the number of a %s, type to filter or press [ENTER] to clear the filter: ", p.optionName)
+	}
+}
+
+// rankFuzzyMatches returns the options that fuzzy match the filter, the best match first.
+func rankFuzzyMatches(filter string, options []Option) []Option {
+	type match struct {
+		option Option
+		score  int
+	}
+
+	var matches []match
+	for _, option := range options {
+		score, ok := fuzzyScore(filter, option.Display)
+		if ok {
+			matches = append(matches, match{option: option, score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		return len(matches[i].option.Display) < len(matches[j].option.Display)
+	})
+
+	res := make([]Option, len(matches))
+	for i, m := range matches {
+		res[i] = m.option
+	}
+	return res
+}
+
+// fuzzyScore returns whether all characters of the filter occur in the candidate in the same order,
+// ignoring case. The score is the number of characters skipped between the matched characters,
+// so a lower score is a better match. Characters skipped before the first match are not counted
+// when the first match is at the start of a path segment.
+func fuzzyScore(filter, candidate string) (int, bool) {
+	filter = strings.ToLower(filter)
+	candidate = strings.ToLower(candidate)
+
+	score := 0
+	last := -1
+	for _, c := range filter {
+		i := strings.IndexRune(candidate[last+1:], c)
+		if i < 0 {
+			return 0, false
+		}
+		i += last + 1
+
+		if last >= 0 || (i > 0 && candidate[i-1] != '/') {
+			score += i - last - 1
+		}
+		last = i
+	}
+	return score, true
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestPick(t *testing.T) {
+	batches := [][]Option{
+		{{Value: "namespace/repo/db/password", Display: "namespace/repo/db/password"}, {Value: "namespace/repo/db/user", Display: "namespace/repo/db/user"}},
+		{{Value: "namespace/repo/api_key", Display: "namespace/repo/api_key"}},
+	}
+
+	cases := map[string]struct {
+		in          []string
+		expected    string
+		expectedOut string
+		err         error
+	}{
+		"pick without filter": {
+			in:       []string{"2\n"},
+			expected: "namespace/repo/api_key",
+			expectedOut: "Pick a secret\n" +
+				"  1) namespace/repo/db/user\n" +
+				"  2) namespace/repo/api_key\n" +
+				"  3) namespace/repo/db/password\n" +
+				"Type the number of a secret, type to filter or press [ENTER] to cancel: ",
+		},
+		"filter": {
+			in:       []string{"dbpass\n", "1\n"},
+			expected: "namespace/repo/db/password",
+			expectedOut: "Pick a secret\n" +
+				"  1) namespace/repo/db/user\n" +
+				"  2) namespace/repo/api_key\n" +
+				"  3) namespace/repo/db/password\n" +
+				"Type the number of a secret, type to filter or press [ENTER] to cancel: " +
+				"  1) namespace/repo/db/password\n" +
+				"Type the number of a secret, type to filter or press [ENTER] to clear the filter: ",
+		},
+		"no matches": {
+			in:       []string{"foo\n", "\n", "\n"},
+			expected: "",
+			expectedOut: "Pick a secret\n" +
+				"  1) namespace/repo/db/user\n" +
+				"  2) namespace/repo/api_key\n" +
+				"  3) namespace/repo/db/password\n" +
+				"Type the number of a secret, type to filter or press [ENTER] to cancel: " +
+				"No secrets match \"foo\".\n" +
+				"Type the number of a secret, type to filter or press [ENTER] to clear the filter: " +
+				"  1) namespace/repo/db/user\n" +
+				"  2) namespace/repo/api_key\n" +
+				"  3) namespace/repo/db/password\n" +
+				"Type the number of a secret, type to filter or press [ENTER] to cancel: ",
+			err: ErrNothingPicked,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			io.PromptIn.Reads = tc.in
+
+			i := 0
+			getOptions := func() ([]Option, bool, error) {
+				options := batches[i]
+				i++
+				return options, i == len(batches), nil
+			}
+
+			actual, err := Pick(io, "Pick a secret", "secret", getOptions)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual, tc.expected)
+			assert.Equal(t, io.PromptOut.String(), tc.expectedOut)
+		})
+	}
+}
+
+func TestFuzzyScore(t *testing.T) {
+	cases := map[string]struct {
+		filter        string
+		candidate     string
+		expectedScore int
+		expectedMatch bool
+	}{
+		"empty filter": {
+			candidate:     "namespace/repo",
+			expectedMatch: true,
+		},
+		"prefix": {
+			filter:        "name",
+			candidate:     "namespace/repo",
+			expectedMatch: true,
+		},
+		"start of segment": {
+			filter:        "repo",
+			candidate:     "namespace/repo",
+			expectedMatch: true,
+		},
+		"case insensitive": {
+			filter:        "REPO",
+			candidate:     "namespace/repo",
+			expectedMatch: true,
+		},
+		"gaps": {
+			filter:        "nsr",
+			candidate:     "namespace/repo",
+			expectedScore: 8,
+			expectedMatch: true,
+		},
+		"middle of segment": {
+			filter:        "space",
+			candidate:     "namespace/repo",
+			expectedScore: 4,
+			expectedMatch: true,
+		},
+		"wrong order": {
+			filter:    "repons",
+			candidate: "namespace/repo",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			score, ok := fuzzyScore(tc.filter, tc.candidate)
+
+			assert.Equal(t, ok, tc.expectedMatch)
+			assert.Equal(t, score, tc.expectedScore)
+		})
+	}
+}
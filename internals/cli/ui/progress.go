@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const progressInterval = 100 * time.Millisecond
+
+// progressFrames are the frames of the spinner. Only ASCII is used, so that it works in every terminal.
+var progressFrames = []string{"|", "/", "-", "\\"}
+
+// Progress shows the progress of a long-running operation on a single line, with a spinner,
+// the percentage done and the estimated time remaining. When the total is unknown, only
+// the spinner and the elapsed time are shown. Nothing is shown when the output is not a terminal,
+// so that piped output is not polluted.
+type Progress struct {
+	mu          sync.Mutex
+	out         io.Writer
+	enabled     bool
+	description string
+	total       int
+	done        int
+	frame       int
+	width       int
+	started     time.Time
+	now         func() time.Time
+	interval    time.Duration
+	stop        chan struct{}
+	stopped     chan struct{}
+}
+
+// NewProgress creates a new Progress for an operation that consists of total steps.
+// Use a total of 0 when the number of steps is unknown.
+func NewProgress(io IO, description string, total int) *Progress {
+	return &Progress{
+		out:         io.Output(),
+		enabled:     !io.IsOutputPiped() && terminal.IsTerminal(int(io.Stdout().Fd())),
+		description: description,
+		total:       total,
+		now:         time.Now,
+		interval:    progressInterval,
+	}
+}
+
+// Start starts showing the progress until Stop is called.
+// Note that Start does not block.
+func (p *Progress) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.started = p.now()
+	if !p.enabled || p.stop != nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	p.stop, p.stopped = stop, stopped
+	p.draw()
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.mu.Lock()
+				p.frame = (p.frame + 1) % len(progressFrames)
+				p.draw()
+				p.mu.Unlock()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Add marks n more steps as done.
+func (p *Progress) Add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += n
+}
+
+// Describe changes the description of the operation that is shown.
+func (p *Progress) Describe(description string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.description = description
+}
+
+// Printf writes a line to the output above the progress.
+func (p *Progress) Printf(format string, args ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	running := p.stop != nil
+	if running {
+		p.clear()
+	}
+	fmt.Fprintf(p.out, format, args...)
+	if running {
+		p.draw()
+	}
+}
+
+// Stop stops showing the progress and removes it from the output.
+// Stop blocks until the progress is removed.
+func (p *Progress) Stop() {
+	p.mu.Lock()
+	stop, stopped := p.stop, p.stopped
+	p.stop = nil
+	p.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-stopped
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+}
+
+// draw overwrites the current line with the progress.
+func (p *Progress) draw() {
+	line := p.line()
+	if len(line) < p.width {
+		// Overwrite the remainder of the previous, longer line.
+		p.clear()
+	}
+	fmt.Fprintf(p.out, "\r%s", line)
+	p.width = len(line)
+}
+
+// clear removes the progress from the current line.
+func (p *Progress) clear() {
+	fmt.Fprintf(p.out, "\r%s\r", strings.Repeat(" ", p.width))
+	p.width = 0
+}
+
+// line returns the line that shows the current progress.
+func (p *Progress) line() string {
+	elapsed := p.now().Sub(p.started)
+	line := progressFrames[p.frame] + " " + p.description
+	if p.total <= 0 {
+		return line + " " + formatProgressDuration(elapsed)
+	}
+
+	done := p.done
+	if done > p.total {
+		done = p.total
+	}
+	line += fmt.Sprintf(" %d%% (%d/%d)", done*100/p.total, done, p.total)
+	if done > 0 && done < p.total {
+		remaining := time.Duration(int64(elapsed) / int64(done) * int64(p.total-done))
+		line += " ETA " + formatProgressDuration(remaining)
+	}
+	return line
+}
+
+// formatProgressDuration formats a duration rounded to seconds.
+func formatProgressDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
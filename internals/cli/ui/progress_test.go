@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+)
+
+func TestProgress_line(t *testing.T) {
+	started := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		total    int
+		done     int
+		elapsed  time.Duration
+		expected string
+	}{
+		"unknown total": {
+			elapsed:  12*time.Second + 400*time.Millisecond,
+			expected: "| Fetching 12s",
+		},
+		"not started": {
+			total:    50,
+			expected: "| Fetching 0% (0/50)",
+		},
+		"halfway": {
+			total:    50,
+			done:     25,
+			elapsed:  30 * time.Second,
+			expected: "| Fetching 50% (25/50) ETA 30s",
+		},
+		"almost done": {
+			total:    3,
+			done:     2,
+			elapsed:  2 * time.Minute,
+			expected: "| Fetching 66% (2/3) ETA 1m0s",
+		},
+		"done": {
+			total:    3,
+			done:     4,
+			elapsed:  time.Minute,
+			expected: "| Fetching 100% (3/3)",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &Progress{
+				description: "Fetching",
+				total:       tc.total,
+				done:        tc.done,
+				started:     started,
+				now: func() time.Time {
+					return started.Add(tc.elapsed)
+				},
+			}
+
+			assert.Equal(t, p.line(), tc.expected)
+		})
+	}
+}
+
+func TestProgress_Printf(t *testing.T) {
+	out := &bytes.Buffer{}
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := &Progress{
+		out:         out,
+		enabled:     true,
+		description: "Exporting",
+		total:       4,
+		now: func() time.Time {
+			return now
+		},
+		interval: time.Hour,
+	}
+
+	p.Start()
+	p.Add(1)
+	p.Printf("exported %s\n", "foo")
+	p.Stop()
+
+	assert.Equal(t, out.String(), "\r| Exporting 0% (0/4)"+
+		"\r"+strings.Repeat(" ", 20)+"\r"+
+		"exported foo\n"+
+		"\r| Exporting 25% (1/4) ETA 0s"+
+		"\r"+strings.Repeat(" ", 28)+"\r")
+}
+
+func TestProgress_Disabled(t *testing.T) {
+	io := fakeui.NewIO(t)
+
+	p := NewProgress(io, "Exporting", 2)
+	p.Start()
+	p.Add(1)
+	p.Printf("exported %s\n", "foo")
+	p.Stop()
+
+	assert.Equal(t, io.Out.String(), "exported foo\n")
+}
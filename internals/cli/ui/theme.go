@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/secrethub/secrethub-go/internals/errio"
+
+	isatty "github.com/mattn/go-isatty"
+)
+
+// Color modes
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// ColorModes are the supported color modes.
+var ColorModes = []string{ColorAuto, ColorAlways, ColorNever}
+
+// Errors
+var (
+	errTheme = errio.Namespace("theme")
+
+	// ErrUnknownColorMode is returned when a color mode is set that does not exist.
+	ErrUnknownColorMode = errTheme.Code("unknown_color_mode").ErrorPref("unknown color mode %s: must be one of " + strings.Join(ColorModes, ", "))
+)
+
+// Theme defines the prefixes with which the status of a message is shown.
+// The prefixes of warnings and errors always contain text, so that their
+// status does not depend on being able to see colors or emoji.
+type Theme struct {
+	SuccessPrefix string
+	WarningPrefix string
+	ErrorPrefix   string
+}
+
+var (
+	// TextTheme is the default theme, which only uses text.
+	TextTheme = Theme{
+		SuccessPrefix: "",
+		WarningPrefix: "Warning: ",
+		ErrorPrefix:   "Error: ",
+	}
+	// EmojiTheme is a theme that also shows an emoji for every status.
+	EmojiTheme = Theme{
+		SuccessPrefix: "✅ ",
+		WarningPrefix: "⚠️  Warning: ",
+		ErrorPrefix:   "❌ Error: ",
+	}
+)
+
+var (
+	themeMutex   sync.Mutex
+	currentTheme = TextTheme
+
+	successColor = color.New(color.FgGreen)
+	warningColor = color.New(color.FgYellow, color.Bold)
+	errorColor   = color.New(color.FgRed, color.Bold)
+)
+
+// SetTheme sets the theme used to print messages with a status.
+func SetTheme(theme Theme) {
+	themeMutex.Lock()
+	defer themeMutex.Unlock()
+	currentTheme = theme
+}
+
+// SetColorMode configures whether output is colored. In auto mode, output is only colored
+// when stdout is a terminal and the NO_COLOR environment variable is not set.
+func SetColorMode(mode string) error {
+	switch mode {
+	case ColorAuto:
+		color.NoColor = os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" ||
+			(!isatty.IsTerminal(os.Stdout.Fd()) && !isatty.IsCygwinTerminal(os.Stdout.Fd()))
+	case ColorAlways:
+		color.NoColor = false
+	case ColorNever:
+		color.NoColor = true
+	default:
+		return ErrUnknownColorMode(mode)
+	}
+	return nil
+}
+
+// Successf writes a message that reports that an action has succeeded.
+func Successf(w io.Writer, format string, args ...interface{}) {
+	printWithStatus(w, getTheme().SuccessPrefix, successColor, format, args...)
+}
+
+// Warningf writes a message that warns about something the user should be aware of.
+func Warningf(w io.Writer, format string, args ...interface{}) {
+	printWithStatus(w, getTheme().WarningPrefix, warningColor, format, args...)
+}
+
+// Errorf writes a message that reports that something has failed.
+func Errorf(w io.Writer, format string, args ...interface{}) {
+	printWithStatus(w, getTheme().ErrorPrefix, errorColor, format, args...)
+}
+
+// getTheme returns the theme that is currently used.
+func getTheme() Theme {
+	themeMutex.Lock()
+	defer themeMutex.Unlock()
+	return currentTheme
+}
+
+// printWithStatus writes the formatted message with the given prefix, in the given color.
+// A trailing newline is written outside of the color, so that the color does not leak to the next line.
+func printWithStatus(w io.Writer, prefix string, c *color.Color, format string, args ...interface{}) {
+	message := prefix + fmt.Sprintf(format, args...)
+	trimmed := strings.TrimRight(message, "\n")
+	fmt.Fprint(w, c.Sprint(trimmed)+message[len(trimmed):])
+}
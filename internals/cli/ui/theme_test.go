@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestThemes(t *testing.T) {
+	cases := map[string]struct {
+		theme    Theme
+		noColor  bool
+		print    func(w *bytes.Buffer)
+		expected string
+	}{
+		"success": {
+			theme:   TextTheme,
+			noColor: true,
+			print: func(w *bytes.Buffer) {
+				Successf(w, "Write complete! The given value has been written to %s.\n", "namespace/repo/secret")
+			},
+			expected: "Write complete! The given value has been written to namespace/repo/secret.\n",
+		},
+		"warning": {
+			theme:   TextTheme,
+			noColor: true,
+			print: func(w *bytes.Buffer) {
+				Warningf(w, "%s contains no secret declarations.\n", "secrets.yml")
+			},
+			expected: "Warning: secrets.yml contains no secret declarations.\n",
+		},
+		"error with emoji": {
+			theme:   EmojiTheme,
+			noColor: true,
+			print: func(w *bytes.Buffer) {
+				Errorf(w, "could not connect\n")
+			},
+			expected: "❌ Error: could not connect\n",
+		},
+		"colored warning": {
+			theme: TextTheme,
+			print: func(w *bytes.Buffer) {
+				Warningf(w, "insecure\n")
+			},
+			expected: "\x1b[33;1mWarning: insecure\x1b[0m\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			noColor := color.NoColor
+			defer func() {
+				color.NoColor = noColor
+				SetTheme(TextTheme)
+			}()
+			color.NoColor = tc.noColor
+			SetTheme(tc.theme)
+
+			w := &bytes.Buffer{}
+			tc.print(w)
+
+			assert.Equal(t, w.String(), tc.expected)
+		})
+	}
+}
+
+func TestSetColorMode(t *testing.T) {
+	noColor := color.NoColor
+	defer func() {
+		color.NoColor = noColor
+	}()
+
+	assert.OK(t, SetColorMode(ColorAlways))
+	assert.Equal(t, color.NoColor, false)
+
+	assert.OK(t, SetColorMode(ColorNever))
+	assert.Equal(t, color.NoColor, true)
+
+	t.Setenv("NO_COLOR", "1")
+	assert.OK(t, SetColorMode(ColorAuto))
+	assert.Equal(t, color.NoColor, true)
+
+	assert.Equal(t, SetColorMode("sometimes"), ErrUnknownColorMode("sometimes"))
+}
@@ -0,0 +1,409 @@
+// Package kubernetes implements a minimal client for the Kubernetes API that can read and write
+// Secret objects. It authenticates with the service account of the pod when running in a cluster
+// or with the credentials of a kubeconfig file otherwise.
+package kubernetes
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+
+	"github.com/mitchellh/go-homedir"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	inClusterTokenFile     = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	inClusterCAFile        = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+	inClusterNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+	defaultNamespace = "default"
+)
+
+// Errors
+var (
+	errKubernetes = errio.Namespace("kubernetes")
+
+	ErrSecretNotFound       = errKubernetes.Code("secret_not_found").Error("the secret does not exist")
+	ErrRequestFailed        = errKubernetes.Code("request_failed").ErrorPref("request to the Kubernetes API failed: %s: %s")
+	ErrCannotReachAPI       = errKubernetes.Code("cannot_reach_api").ErrorPref("cannot reach the Kubernetes API: %s")
+	ErrInvalidKubeconfig    = errKubernetes.Code("invalid_kubeconfig").ErrorPref("invalid kubeconfig %s: %s")
+	ErrUnsupportedAuth      = errKubernetes.Code("unsupported_auth").ErrorPref("the user %s of the kubeconfig uses exec or auth-provider authentication, which is not supported: use a token or a client certificate")
+	ErrInvalidCACertificate = errKubernetes.Code("invalid_ca_certificate").Error("the CA certificate of the cluster contains no valid PEM certificates")
+	ErrContextNotFound      = errKubernetes.Code("context_not_found").ErrorPref("there is no context %s in the kubeconfig")
+	ErrCannotReadCredential = errKubernetes.Code("cannot_read_credential").ErrorPref("cannot read %s: %s")
+)
+
+// Secret is a Kubernetes Secret object. Only the fields that are needed to sync data are included.
+type Secret struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   ObjectMeta        `json:"metadata"`
+	Type       string            `json:"type,omitempty"`
+	Data       map[string][]byte `json:"data"`
+}
+
+// ObjectMeta is the metadata of a Kubernetes object.
+type ObjectMeta struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+}
+
+// NewSecret returns an Opaque secret with the given name and namespace.
+func NewSecret(namespace, name string) *Secret {
+	return &Secret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata: ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: "Opaque",
+		Data: map[string][]byte{},
+	}
+}
+
+// Client is a client for the Kubernetes API.
+type Client struct {
+	server     string
+	token      string
+	namespace  string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the cluster the CLI runs in, or for the cluster of a context in a kubeconfig file.
+// When kubeconfig is empty, the client for the cluster the CLI runs in is returned if possible and otherwise
+// the file in $KUBECONFIG or ~/.kube/config is used. When kubeContext is empty, the current context is used.
+func NewClient(kubeconfig, kubeContext string) (*Client, error) {
+	if kubeconfig == "" && kubeContext == "" && os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return newInClusterClient()
+	}
+
+	if kubeconfig == "" {
+		kubeconfig = os.Getenv("KUBECONFIG")
+		if kubeconfig != "" {
+			// Merging multiple kubeconfig files is not supported, so only the first file is used.
+			kubeconfig = filepath.SplitList(kubeconfig)[0]
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				return nil, err
+			}
+			kubeconfig = filepath.Join(home, ".kube", "config")
+		}
+	}
+	return newKubeconfigClient(kubeconfig, kubeContext)
+}
+
+// newInClusterClient returns a client that authenticates with the service account of the pod.
+func newInClusterClient() (*Client, error) {
+	token, err := os.ReadFile(inClusterTokenFile)
+	if err != nil {
+		return nil, ErrCannotReadCredential(inClusterTokenFile, err)
+	}
+
+	ca, err := os.ReadFile(inClusterCAFile)
+	if err != nil {
+		return nil, ErrCannotReadCredential(inClusterCAFile, err)
+	}
+
+	tlsConfig, err := newTLSConfig(ca, false)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := defaultNamespace
+	raw, err := os.ReadFile(inClusterNamespaceFile)
+	if err == nil {
+		namespace = strings.TrimSpace(string(raw))
+	}
+
+	host := net.JoinHostPort(os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT"))
+	return newClient("https://"+host, strings.TrimSpace(string(token)), namespace, tlsConfig), nil
+}
+
+// kubeconfig contains the fields of a kubeconfig file that are needed to connect to a cluster.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Contexts       []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster   string `yaml:"cluster"`
+			User      string `yaml:"user"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Clusters []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string      `yaml:"token"`
+			TokenFile             string      `yaml:"tokenFile"`
+			ClientCertificate     string      `yaml:"client-certificate"`
+			ClientCertificateData string      `yaml:"client-certificate-data"`
+			ClientKey             string      `yaml:"client-key"`
+			ClientKeyData         string      `yaml:"client-key-data"`
+			Exec                  interface{} `yaml:"exec"`
+			AuthProvider          interface{} `yaml:"auth-provider"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// newKubeconfigClient returns a client for the cluster and user of a context in the kubeconfig file.
+func newKubeconfigClient(path string, contextName string) (*Client, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrCannotReadCredential(path, err)
+	}
+
+	var config kubeconfig
+	err = yaml.Unmarshal(raw, &config)
+	if err != nil {
+		return nil, ErrInvalidKubeconfig(path, err)
+	}
+
+	if contextName == "" {
+		contextName = config.CurrentContext
+	}
+	if contextName == "" {
+		return nil, ErrInvalidKubeconfig(path, "no current context is set")
+	}
+
+	found := false
+	var clusterName, userName, namespace string
+	for _, c := range config.Contexts {
+		if c.Name == contextName {
+			found = true
+			clusterName, userName, namespace = c.Context.Cluster, c.Context.User, c.Context.Namespace
+		}
+	}
+	if !found {
+		return nil, ErrContextNotFound(contextName)
+	}
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	// Relative paths in a kubeconfig file are relative to the directory of the file.
+	dir := filepath.Dir(path)
+	readFileOrData := func(file, data string) ([]byte, error) {
+		if data != "" {
+			decoded, err := base64.StdEncoding.DecodeString(data)
+			if err != nil {
+				return nil, ErrInvalidKubeconfig(path, err)
+			}
+			return decoded, nil
+		}
+		if file == "" {
+			return nil, nil
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, ErrCannotReadCredential(file, err)
+		}
+		return content, nil
+	}
+
+	var server string
+	var tlsConfig *tls.Config
+	for _, c := range config.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+		server = c.Cluster.Server
+
+		ca, err := readFileOrData(c.Cluster.CertificateAuthority, c.Cluster.CertificateAuthorityData)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig, err = newTLSConfig(ca, c.Cluster.InsecureSkipTLSVerify)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if server == "" {
+		return nil, ErrInvalidKubeconfig(path, fmt.Sprintf("the cluster %s of context %s has no server", clusterName, contextName))
+	}
+
+	var token string
+	for _, u := range config.Users {
+		if u.Name != userName {
+			continue
+		}
+		if u.User.Exec != nil || u.User.AuthProvider != nil {
+			return nil, ErrUnsupportedAuth(userName)
+		}
+
+		token = u.User.Token
+		if token == "" && u.User.TokenFile != "" {
+			raw, err := readFileOrData(u.User.TokenFile, "")
+			if err != nil {
+				return nil, err
+			}
+			token = strings.TrimSpace(string(raw))
+		}
+
+		cert, err := readFileOrData(u.User.ClientCertificate, u.User.ClientCertificateData)
+		if err != nil {
+			return nil, err
+		}
+		key, err := readFileOrData(u.User.ClientKey, u.User.ClientKeyData)
+		if err != nil {
+			return nil, err
+		}
+		if cert != nil && key != nil {
+			pair, err := tls.X509KeyPair(cert, key)
+			if err != nil {
+				return nil, ErrInvalidKubeconfig(path, err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{pair}
+		}
+	}
+
+	return newClient(server, token, namespace, tlsConfig), nil
+}
+
+// newTLSConfig returns the TLS configuration that trusts the given CA certificate.
+// When ca is nil, the system roots are used.
+func newTLSConfig(ca []byte, insecureSkipVerify bool) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if ca != nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, ErrInvalidCACertificate
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+func newClient(server, token, namespace string, tlsConfig *tls.Config) *Client {
+	return &Client{
+		server:    strings.TrimSuffix(server, "/"),
+		token:     token,
+		namespace: namespace,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+		},
+	}
+}
+
+// Namespace returns the namespace of the context of the kubeconfig or of the pod the CLI runs in.
+func (c *Client) Namespace() string {
+	return c.namespace
+}
+
+// GetSecret returns the secret with the given name in the namespace.
+// When the secret does not exist, ErrSecretNotFound is returned.
+func (c *Client) GetSecret(namespace, name string) (*Secret, error) {
+	var secret Secret
+	err := c.do(http.MethodGet, secretURL(namespace, name), nil, &secret)
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// CreateSecret creates the secret in its namespace.
+func (c *Client) CreateSecret(secret *Secret) error {
+	return c.do(http.MethodPost, secretURL(secret.Metadata.Namespace, ""), secret, nil)
+}
+
+// UpdateSecret replaces the secret in its namespace. The resource version of
+// the secret must be set to the version that was read.
+func (c *Client) UpdateSecret(secret *Secret) error {
+	return c.do(http.MethodPut, secretURL(secret.Metadata.Namespace, secret.Metadata.Name), secret, nil)
+}
+
+func secretURL(namespace, name string) string {
+	u := "/api/v1/namespaces/" + url.PathEscape(namespace) + "/secrets"
+	if name != "" {
+		u += "/" + url.PathEscape(name)
+	}
+	return u
+}
+
+// do sends a request to the API with the body encoded as JSON and decodes the response into out.
+func (c *Client) do(method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequest(method, c.server+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ErrCannotReachAPI(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet {
+		return ErrSecretNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Errors are returned as a Status object with a human readable message.
+		var status struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&status)
+		if status.Message == "" {
+			status.Message = http.StatusText(resp.StatusCode)
+		}
+		return ErrRequestFailed(resp.Status, status.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -20,7 +20,7 @@ func (op *OPV1CLI) CreateVault(name string) error {
 	return nil
 }
 
-func (op *OPV1CLI) CreateItem(vault string, template ItemTemplate, title string) error {
+func (op *OPV1CLI) CreateItem(vault, category string, template ItemTemplate, title string) error {
 	jsonTemplate, err := json.Marshal(template)
 	if err != nil {
 		return err
@@ -28,7 +28,7 @@ func (op *OPV1CLI) CreateItem(vault string, template ItemTemplate, title string)
 
 	encodedTemplate := base64.RawURLEncoding.EncodeToString(jsonTemplate)
 
-	_, err = execOP("create", "item", "apicredential", "--vault="+vault, encodedTemplate, "title="+title)
+	_, err = execOP("create", "item", category, "--vault="+vault, encodedTemplate, "title="+title)
 	return err
 }
 
@@ -20,7 +20,7 @@ func (op *OPV2CLI) CreateVault(name string) error {
 	return nil
 }
 
-func (op *OPV2CLI) CreateItem(vault string, template ItemTemplate, title string) error {
+func (op *OPV2CLI) CreateItem(vault, category string, template ItemTemplate, title string) error {
 	jsonTemplate, err := json.Marshal(template)
 	if err != nil {
 		return err
@@ -36,7 +36,7 @@ func (op *OPV2CLI) CreateItem(vault string, template ItemTemplate, title string)
 		return err
 	}
 
-	_, err = execOP("item", "create", "--category=apicredential", "--vault="+vault, "--template="+tempJSONFile.Name(), "--title="+title)
+	_, err = execOP("item", "create", "--category="+category, "--vault="+vault, "--template="+tempJSONFile.Name(), "--title="+title)
 	if err != nil {
 		return err
 	}
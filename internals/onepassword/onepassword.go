@@ -15,7 +15,7 @@ import (
 type OPCLI interface {
 	IsV2() bool
 	CreateVault(name string) error
-	CreateItem(vault string, template ItemTemplate, title string) error
+	CreateItem(vault, category string, template ItemTemplate, title string) error
 	SetField(vault, item, field, value string) error
 	GetFields(vault, item string) (map[string]string, error)
 	ExistsVault(vaultName string) (bool, error)
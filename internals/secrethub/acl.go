@@ -9,21 +9,26 @@ import (
 type ACLCommand struct {
 	io        ui.IO
 	newClient newClientFunc
+	expiries  *AccessRuleExpiryStore
 }
 
 // NewACLCommand creates a new ACLCommand.
-func NewACLCommand(io ui.IO, newClient newClientFunc) *ACLCommand {
+func NewACLCommand(io ui.IO, newClient newClientFunc, store CredentialConfig) *ACLCommand {
 	return &ACLCommand{
 		io:        io,
 		newClient: newClient,
+		expiries:  NewAccessRuleExpiryStore(store),
 	}
 }
 
 // Register registers the command and its sub-commands on the provided Registerer.
 func (cmd *ACLCommand) Register(r cli.Registerer) {
 	clause := r.Command("acl", "Manage access rules on directories.")
+	NewACLApplyCommand(cmd.io, cmd.newClient).Register(clause)
 	NewACLCheckCommand(cmd.io, cmd.newClient).Register(clause)
+	NewACLCopyCommand(cmd.io, cmd.newClient).Register(clause)
 	NewACLListCommand(cmd.io, cmd.newClient).Register(clause)
+	NewACLReportCommand(cmd.io, cmd.newClient).Register(clause)
 	NewACLRmCommand(cmd.io, cmd.newClient).Register(clause)
-	NewACLSetCommand(cmd.io, cmd.newClient).Register(clause)
+	NewACLSetCommand(cmd.io, cmd.newClient, cmd.expiries).Register(clause)
 }
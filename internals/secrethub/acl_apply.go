@@ -0,0 +1,265 @@
+package secrethub
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Errors
+var (
+	ErrInvalidACLPolicy = errMain.Code("invalid_acl_policy").ErrorPref("invalid access policy file %s: %s")
+)
+
+// aclPolicy declares the access rules that should be set on directories.
+//
+// Example:
+//
+//	paths:
+//	  my-org/my-repo:
+//	    - account: dev1
+//	      permission: admin
+//	  my-org/my-repo/prod:
+//	    - account: s-2ABC3DEF4GHI
+//	      permission: read
+type aclPolicy struct {
+	Paths map[string][]aclPolicyRule `yaml:"paths"`
+}
+
+// aclPolicyRule is an access rule of an account on a directory in the policy.
+type aclPolicyRule struct {
+	Account    string `yaml:"account"`
+	Permission string `yaml:"permission"`
+}
+
+// readACLPolicy reads and validates the access policy file at the given path.
+func readACLPolicy(path string) (*aclPolicy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+
+	policy := &aclPolicy{}
+	err = yaml.UnmarshalStrict(raw, policy)
+	if err != nil {
+		return nil, ErrInvalidACLPolicy(path, err)
+	}
+
+	for dirPath, rules := range policy.Paths {
+		_, err := api.NewDirPath(dirPath)
+		if err != nil {
+			return nil, ErrInvalidACLPolicy(path, err)
+		}
+
+		accounts := make(map[string]bool)
+		for _, rule := range rules {
+			var permission api.Permission
+			err = permission.Set(rule.Permission)
+			if err != nil {
+				return nil, ErrInvalidACLPolicy(path, err)
+			}
+
+			account := strings.ToLower(rule.Account)
+			if accounts[account] {
+				return nil, ErrInvalidACLPolicy(path, fmt.Sprintf("account %s has multiple rules on %s", rule.Account, dirPath))
+			}
+			accounts[account] = true
+		}
+	}
+	return policy, nil
+}
+
+// aclPolicyChange is a change to an access rule that is needed to comply with the policy.
+type aclPolicyChange struct {
+	path        string
+	accountName string
+	// current is the current permission, or empty when the rule has to be added.
+	current string
+	// desired is the permission in the policy, or empty when the rule has to be removed.
+	desired string
+}
+
+// String returns a description of the change.
+func (c aclPolicyChange) String() string {
+	switch {
+	case c.current == "":
+		return fmt.Sprintf("+ %s: %s on %s", c.accountName, c.desired, c.path)
+	case c.desired == "":
+		return fmt.Sprintf("- %s: %s on %s", c.accountName, c.current, c.path)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s on %s", c.accountName, c.current, c.desired, c.path)
+	}
+}
+
+// ACLApplyCommand sets the access rules declared in a policy file.
+type ACLApplyCommand struct {
+	file      string
+	force     bool
+	dryRun    bool
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewACLApplyCommand creates a new ACLApplyCommand.
+func NewACLApplyCommand(io ui.IO, newClient newClientFunc) *ACLApplyCommand {
+	return &ACLApplyCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ACLApplyCommand) Register(r cli.Registerer) {
+	clause := r.Command("apply", "Set the access rules declared in a policy file.")
+	clause.HelpLong("The policy file is a YAML file that declares the access rules of every directory under `paths`:\n\n" +
+		"    paths:\n" +
+		"      my-org/my-repo:\n" +
+		"        - account: dev1\n" +
+		"          permission: admin\n" +
+		"      my-org/my-repo/prod:\n" +
+		"        - account: s-2ABC3DEF4GHI\n" +
+		"          permission: read\n\n" +
+		"The access rules on every directory in the policy are compared with the current access rules. " +
+		"Rules that are missing are added, rules with a different permission are changed and rules on the directory for accounts that are not in the policy are removed. " +
+		"Directories that are not in the policy are left as they are.\n\n" +
+		"The changes are printed and applied after confirmation.")
+	clause.Flags().BoolVar(&cmd.dryRun, "dry-run", false, "Only print the changes that would be made.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.file, Name: "policy-file", Required: true, Description: "The path of the YAML file with the access policy."},
+	})
+}
+
+// Run applies the policy.
+func (cmd *ACLApplyCommand) Run() error {
+	policy, err := readACLPolicy(cmd.file)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	changes, err := aclPolicyChanges(client, policy)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.io.Output()
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "All access rules are up to date.")
+		return nil
+	}
+
+	printACLPolicyChanges(out, changes)
+
+	if cmd.dryRun {
+		return nil
+	}
+
+	if !cmd.force {
+		confirmed, err := ui.AskYesNo(cmd.io, "[WARNING] Are you sure you want to apply these changes to the access rules?", ui.DefaultNo)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(out, "Aborting.")
+			return nil
+		}
+	}
+
+	// Rules are set before rules are removed, so that replacing an admin does not lock out the one applying the policy.
+	for _, change := range changes {
+		if change.desired == "" {
+			continue
+		}
+		_, err := client.AccessRules().Set(change.path, change.desired, change.accountName)
+		if err != nil {
+			return err
+		}
+	}
+	for _, change := range changes {
+		if change.desired != "" {
+			continue
+		}
+		err := client.AccessRules().Delete(change.path, change.accountName)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(out, "Access policy applied!")
+	return nil
+}
+
+// aclPolicyChanges returns the changes to the access rules that are needed to comply with the policy,
+// sorted by path and account name.
+func aclPolicyChanges(client secrethub.ClientInterface, policy *aclPolicy) ([]aclPolicyChange, error) {
+	var changes []aclPolicyChange
+	for path, rules := range policy.Paths {
+		currentRules, err := client.AccessRules().List(path, 0, false)
+		if err != nil {
+			return nil, err
+		}
+
+		current := make(map[string]string, len(currentRules))
+		for _, rule := range currentRules {
+			current[strings.ToLower(rule.Account.Name.String())] = rule.Permission.String()
+		}
+
+		desired := make(map[string]bool, len(rules))
+		for _, rule := range rules {
+			account := strings.ToLower(rule.Account)
+			desired[account] = true
+
+			permission := strings.ToLower(rule.Permission)
+			if current[account] != permission {
+				changes = append(changes, aclPolicyChange{
+					path:        path,
+					accountName: rule.Account,
+					current:     current[account],
+					desired:     permission,
+				})
+			}
+		}
+
+		for _, rule := range currentRules {
+			if !desired[strings.ToLower(rule.Account.Name.String())] {
+				changes = append(changes, aclPolicyChange{
+					path:        path,
+					accountName: rule.Account.Name.String(),
+					current:     rule.Permission.String(),
+				})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].path != changes[j].path {
+			return changes[i].path < changes[j].path
+		}
+		return strings.ToLower(changes[i].accountName) < strings.ToLower(changes[j].accountName)
+	})
+	return changes, nil
+}
+
+// printACLPolicyChanges prints one line for every change.
+func printACLPolicyChanges(w io.Writer, changes []aclPolicyChange) {
+	for _, change := range changes {
+		fmt.Fprintln(w, change)
+	}
+}
@@ -0,0 +1,129 @@
+package secrethub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestACLApplyCommand_Run(t *testing.T) {
+	policy := "paths:\n" +
+		"  namespace/repo:\n" +
+		"    - account: dev1\n" +
+		"      permission: admin\n" +
+		"    - account: dev2\n" +
+		"      permission: read\n" +
+		"  namespace/repo/dir:\n" +
+		"    - account: ops\n" +
+		"      permission: write\n"
+
+	current := map[string][]*api.AccessRule{
+		"namespace/repo": {
+			{Account: &api.Account{Name: "dev1"}, Permission: api.PermissionAdmin},
+			{Account: &api.Account{Name: "dev2"}, Permission: api.PermissionWrite},
+			{Account: &api.Account{Name: "dev3"}, Permission: api.PermissionRead},
+		},
+	}
+
+	cases := map[string]struct {
+		policy          string
+		dryRun          bool
+		expectedSet     []string
+		expectedDeleted []string
+		expectedOut     string
+		expectedErr     func(path string) error
+	}{
+		"apply": {
+			policy:          policy,
+			expectedSet:     []string{"namespace/repo read dev2", "namespace/repo/dir write ops"},
+			expectedDeleted: []string{"namespace/repo dev3"},
+			expectedOut: "~ dev2: write -> read on namespace/repo\n" +
+				"- dev3: read on namespace/repo\n" +
+				"+ ops: write on namespace/repo/dir\n" +
+				"Access policy applied!\n",
+		},
+		"dry run": {
+			policy: policy,
+			dryRun: true,
+			expectedOut: "~ dev2: write -> read on namespace/repo\n" +
+				"- dev3: read on namespace/repo\n" +
+				"+ ops: write on namespace/repo/dir\n",
+		},
+		"up to date": {
+			policy: "paths:\n" +
+				"  namespace/repo:\n" +
+				"    - account: dev1\n" +
+				"      permission: admin\n" +
+				"    - account: dev2\n" +
+				"      permission: write\n" +
+				"    - account: dev3\n" +
+				"      permission: read\n",
+			expectedOut: "All access rules are up to date.\n",
+		},
+		"duplicate account": {
+			policy: "paths:\n" +
+				"  namespace/repo:\n" +
+				"    - account: dev1\n" +
+				"      permission: admin\n" +
+				"    - account: DEV1\n" +
+				"      permission: read\n",
+			expectedErr: func(path string) error {
+				return ErrInvalidACLPolicy(path, "account DEV1 has multiple rules on namespace/repo")
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			path := filepath.Join(dir, "policy.yml")
+			assert.OK(t, os.WriteFile(path, []byte(tc.policy), 0600))
+			var expectedErr error
+			if tc.expectedErr != nil {
+				expectedErr = tc.expectedErr(path)
+			}
+
+			var set, deleted []string
+			io := fakeui.NewIO(t)
+			cmd := ACLApplyCommand{
+				file:   path,
+				force:  true,
+				dryRun: tc.dryRun,
+				io:     io,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						AccessRuleService: &fakeclient.AccessRuleService{
+							ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+								return current[path], nil
+							},
+							SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+								set = append(set, path+" "+permission+" "+accountName)
+								return nil, nil
+							},
+							DeleteFunc: func(path string, accountName string) error {
+								deleted = append(deleted, path+" "+accountName)
+								return nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, expectedErr)
+			assert.Equal(t, set, tc.expectedSet)
+			assert.Equal(t, deleted, tc.expectedDeleted)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+		})
+	}
+}
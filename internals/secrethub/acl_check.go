@@ -18,6 +18,7 @@ type ACLCheckCommand struct {
 	accountName api.AccountName
 	io          ui.IO
 	newClient   newClientFunc
+	picker      *pathPicker
 }
 
 // NewACLCheckCommand creates a new ACLCheckCommand.
@@ -25,6 +26,7 @@ func NewACLCheckCommand(io ui.IO, newClient newClientFunc) *ACLCheckCommand {
 	return &ACLCheckCommand{
 		io:        io,
 		newClient: newClient,
+		picker:    newPathPicker(io, newClient, pathKindDir),
 	}
 }
 
@@ -32,15 +34,28 @@ func NewACLCheckCommand(io ui.IO, newClient newClientFunc) *ACLCheckCommand {
 func (cmd *ACLCheckCommand) Register(r cli.Registerer) {
 	clause := r.Command("check", "Checks the effective permission of accounts on a path.")
 
+	cmd.picker.register(clause)
+
 	clause.BindAction(cmd.Run)
 	clause.BindArguments([]cli.Argument{
-		{Value: &cmd.path, Name: "dir-path", Required: true, Placeholder: optionalDirPathPlaceHolder, Description: "The path of the directory to check the effective permission for."},
+		{Value: &cmd.path, Name: "dir-path", Required: false, Placeholder: optionalDirPathPlaceHolder, Description: "The path of the directory to check the effective permission for. When it is left empty in a terminal, a directory can be picked interactively."},
 		{Value: &cmd.accountName, Name: "account-name", Required: false, Description: "Check permissions of a specific account name (username or service name). When left empty, all accounts with permission on the path are printed out."},
 	})
 }
 
 // Run prints the access level(s) on the given directory.
 func (cmd *ACLCheckCommand) Run() error {
+	if cmd.path == "" {
+		path, err := cmd.picker.pick("Which directory do you want to check?")
+		if err != nil {
+			return err
+		}
+		if path == "" {
+			return ErrMissingDirPath
+		}
+		cmd.path = api.DirPath(path)
+	}
+
 	levels, err := cmd.listLevels()
 	if err != nil {
 		return err
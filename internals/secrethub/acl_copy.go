@@ -0,0 +1,161 @@
+package secrethub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// ACLCopyCommand is a command to copy the access rules of a directory to another directory.
+type ACLCopyCommand struct {
+	source    api.DirPath
+	target    api.DirPath
+	recursive bool
+	force     bool
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewACLCopyCommand creates a new ACLCopyCommand.
+func NewACLCopyCommand(io ui.IO, newClient newClientFunc) *ACLCopyCommand {
+	return &ACLCopyCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ACLCopyCommand) Register(r cli.Registerer) {
+	clause := r.Command("copy", "Set the access rules of a directory on another directory.")
+	clause.Alias("cp")
+	clause.HelpLong("The access rules on the source directory are set on the target directory, after showing the changes and asking for confirmation. " +
+		"Rules on the target directory for accounts that have no rule on the source directory are left as they are.\n\n" +
+		"With --recursive, the rules on the subdirectories of the source directory are also set on the subdirectories of the target directory with the same relative path. " +
+		"Subdirectories that do not exist in the target directory are skipped.")
+	clause.Flags().BoolVarP(&cmd.recursive, "recursive", "r", false, "Also copy the access rules on all subdirectories.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.source, Name: "source-dir", Placeholder: dirPathPlaceHolder, Required: true, Description: "The path of the directory to copy the access rules from."},
+		{Value: &cmd.target, Name: "target-dir", Placeholder: dirPathPlaceHolder, Required: true, Description: "The path of the directory to set the access rules on."},
+	})
+}
+
+// Run copies the access rules.
+func (cmd *ACLCopyCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	rules, skipped, err := cmd.targetRules(client)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range skipped {
+		fmt.Fprintf(cmd.io.Output(), "Skipping %s: the directory does not exist.\n", path)
+	}
+
+	var changes []teamAccessChange
+	for _, rule := range rules {
+		current, err := client.AccessRules().Get(rule.rule.Path, rule.accountName)
+		if err == api.ErrAccessRuleNotFound {
+			changes = append(changes, rule)
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if current.Permission.String() != rule.rule.Permission {
+			rule.current = current.Permission.String()
+			changes = append(changes, rule)
+		}
+	}
+
+	_, err = applyTeamAccessChanges(cmd.io, client, changes, cmd.force)
+	return err
+}
+
+// targetRules returns the access rules of the source, with their paths translated to the target.
+// When copying recursively, it also returns the target paths that were skipped because they do not exist.
+func (cmd *ACLCopyCommand) targetRules(client secrethub.ClientInterface) ([]teamAccessChange, []string, error) {
+	depth := 0
+	if cmd.recursive {
+		depth = -1
+	}
+
+	rules, err := client.AccessRules().List(cmd.source.Value(), depth, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sourceTree, err := client.Dirs().GetTree(cmd.source.Value(), depth, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The paths of the existing target directories are only needed when copying recursively,
+	// because the target directory itself is checked when setting its rules.
+	var targetDirs map[string]bool
+	if cmd.recursive {
+		targetTree, err := client.Dirs().GetTree(cmd.target.Value(), -1, false)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		targetDirs = make(map[string]bool)
+		for dirID := range targetTree.Dirs {
+			dirPath, err := targetTree.AbsDirPath(dirID)
+			if err != nil {
+				return nil, nil, err
+			}
+			targetDirs[dirPath.Value()] = true
+		}
+	}
+
+	var res []teamAccessChange
+	skipped := make(map[string]bool)
+	for _, rule := range rules {
+		dirPath, err := sourceTree.AbsDirPath(rule.DirID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		targetPath := cmd.target.Value() + strings.TrimPrefix(dirPath.Value(), cmd.source.Value())
+		if targetDirs != nil && !targetDirs[targetPath] {
+			skipped[targetPath] = true
+			continue
+		}
+
+		res = append(res, teamAccessChange{
+			accountName: rule.Account.Name.String(),
+			rule: teamAccessRule{
+				Path:       targetPath,
+				Permission: rule.Permission.String(),
+			},
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].rule.Path != res[j].rule.Path {
+			return res[i].rule.Path < res[j].rule.Path
+		}
+		return res[i].accountName < res[j].accountName
+	})
+
+	skippedPaths := make([]string, 0, len(skipped))
+	for path := range skipped {
+		skippedPaths = append(skippedPaths, path)
+	}
+	sort.Strings(skippedPaths)
+
+	return res, skippedPaths, nil
+}
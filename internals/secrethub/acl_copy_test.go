@@ -0,0 +1,131 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestACLCopyCommand_Run(t *testing.T) {
+	testError := errors.New("test error")
+
+	sourceRootID := uuid.New()
+	sourceDirID := uuid.New()
+	sourceOldID := uuid.New()
+	targetRootID := uuid.New()
+	targetDirID := uuid.New()
+
+	trees := map[string]*api.Tree{
+		"namespace/repo1": {
+			ParentPath: "namespace",
+			RootDir:    &api.Dir{Name: "repo1", DirID: sourceRootID},
+			Dirs: map[uuid.UUID]*api.Dir{
+				sourceRootID: {Name: "repo1", DirID: sourceRootID},
+				sourceDirID:  {Name: "dir", DirID: sourceDirID, ParentID: &sourceRootID},
+				sourceOldID:  {Name: "old", DirID: sourceOldID, ParentID: &sourceRootID},
+			},
+		},
+		"namespace/repo2": {
+			ParentPath: "namespace",
+			RootDir:    &api.Dir{Name: "repo2", DirID: targetRootID},
+			Dirs: map[uuid.UUID]*api.Dir{
+				targetRootID: {Name: "repo2", DirID: targetRootID},
+				targetDirID:  {Name: "dir", DirID: targetDirID, ParentID: &targetRootID},
+			},
+		},
+	}
+
+	sourceRules := []*api.AccessRule{
+		{Account: &api.Account{Name: "dev1"}, DirID: sourceRootID, Permission: api.PermissionAdmin},
+		{Account: &api.Account{Name: "ops"}, DirID: sourceRootID, Permission: api.PermissionRead},
+		{Account: &api.Account{Name: "ops"}, DirID: sourceDirID, Permission: api.PermissionWrite},
+		{Account: &api.Account{Name: "ops"}, DirID: sourceOldID, Permission: api.PermissionWrite},
+	}
+
+	cases := map[string]struct {
+		recursive   bool
+		listErr     error
+		expectedSet []string
+		expectedOut string
+		expectedErr error
+	}{
+		"directory only": {
+			expectedSet: []string{"namespace/repo2 read ops"},
+			expectedOut: "ops: change write to read on namespace/repo2\n" +
+				"Access rules set!\n",
+		},
+		"recursive": {
+			recursive:   true,
+			expectedSet: []string{"namespace/repo2 read ops", "namespace/repo2/dir write ops"},
+			expectedOut: "Skipping namespace/repo2/old: the directory does not exist.\n" +
+				"ops: change write to read on namespace/repo2\n" +
+				"ops: set write on namespace/repo2/dir\n" +
+				"Access rules set!\n",
+		},
+		"list error": {
+			listErr:     testError,
+			expectedErr: testError,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			var set []string
+
+			cmd := ACLCopyCommand{
+				source:    "namespace/repo1",
+				target:    "namespace/repo2",
+				recursive: tc.recursive,
+				force:     true,
+				io:        io,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						AccessRuleService: &fakeclient.AccessRuleService{
+							ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+								if tc.listErr != nil {
+									return nil, tc.listErr
+								}
+								if depth == 0 {
+									return sourceRules[:2], nil
+								}
+								return sourceRules, nil
+							},
+							GetFunc: func(path string, accountName string) (*api.AccessRule, error) {
+								switch {
+								case path == "namespace/repo2" && accountName == "dev1":
+									return &api.AccessRule{Permission: api.PermissionAdmin}, nil
+								case path == "namespace/repo2" && accountName == "ops":
+									return &api.AccessRule{Permission: api.PermissionWrite}, nil
+								}
+								return nil, api.ErrAccessRuleNotFound
+							},
+							SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+								set = append(set, path+" "+permission+" "+accountName)
+								return nil, nil
+							},
+						},
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								return trees[path], nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, set, tc.expectedSet)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+		})
+	}
+}
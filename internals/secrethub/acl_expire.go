@@ -0,0 +1,330 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+	"github.com/secrethub/secrethub-cli/internals/cli/cloneproc"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/spf13/pflag"
+)
+
+const accessRuleExpiriesFileName = "acl_expiries.json"
+
+// aclExpireEnvVars maps the global flags that determine the account, configuration directory
+// and API of a command to the environment variables that set them for the acl-expire process.
+// They are passed as environment variables instead of arguments, so that a credential or
+// passphrase does not show up in the process list.
+var aclExpireEnvVars = map[string]string{
+	"config-dir":            "SECRETHUB_CONFIG_DIR",
+	"credential":            "SECRETHUB_CREDENTIAL",
+	"credential-passphrase": "SECRETHUB_CREDENTIAL_PASSPHRASE",
+	"p":                     "SECRETHUB_CREDENTIAL_PASSPHRASE",
+	"profile":               "SECRETHUB_PROFILE",
+	"identity-provider":     "SECRETHUB_IDENTITY_PROVIDER",
+	"api-remote":            "SECRETHUB_API_REMOTE",
+	"proxy-address":         "SECRETHUB_PROXY_ADDRESS",
+	"tls-ca-cert":           "SECRETHUB_TLS_CA_CERT",
+	"tls-skip-verify":       "SECRETHUB_TLS_SKIP_VERIFY",
+}
+
+// Errors
+var (
+	ErrInvalidAccessRuleExpiries = errMain.Code("invalid_acl_expiries").ErrorPref("could not parse the access rule expiries file %s: %s")
+	ErrInvalidExpiresIn          = errMain.Code("invalid_expires_in").ErrorPref("--expires-in should be positive, got %s")
+)
+
+// accessRuleExpiry is a temporary access rule that has to be reverted at ExpiresAt.
+// When the account had a permission on the path before the temporary rule was set,
+// Previous holds that permission and the rule is set back to it. Otherwise the rule is removed.
+// Error holds the reason the last attempt to revert the rule failed.
+type accessRuleExpiry struct {
+	Path        string    `json:"path"`
+	AccountName string    `json:"account_name"`
+	Previous    string    `json:"previous,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// AccessRuleExpiryStore keeps track of the temporary access rules in the configuration directory.
+// The API does not support expiring access rules, so they are reverted by a cleanup process
+// of the CLI that is spawned when a temporary access rule is set. The expiries are only
+// stored on this machine: other machines do not know about them.
+type AccessRuleExpiryStore struct {
+	dir func() string
+}
+
+// NewAccessRuleExpiryStore creates a new AccessRuleExpiryStore that stores the expiries in
+// the configuration directory of the given credential config.
+func NewAccessRuleExpiryStore(store CredentialConfig) *AccessRuleExpiryStore {
+	return &AccessRuleExpiryStore{
+		dir: func() string {
+			return store.ConfigDir().Path()
+		},
+	}
+}
+
+// path returns the location of the expiries file.
+func (s *AccessRuleExpiryStore) path() string {
+	return filepath.Join(s.dir(), accessRuleExpiriesFileName)
+}
+
+// list returns all scheduled expiries.
+func (s *AccessRuleExpiryStore) list() ([]accessRuleExpiry, error) {
+	raw, err := os.ReadFile(s.path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, ErrCannotReadFile(s.path(), err)
+	}
+
+	var expiries []accessRuleExpiry
+	err = json.Unmarshal(raw, &expiries)
+	if err != nil {
+		return nil, ErrInvalidAccessRuleExpiries(s.path(), err)
+	}
+	return expiries, nil
+}
+
+// schedule records that the access rule of the account on the path expires at the given time
+// and should then be set back to the previous permission, or removed when previous is empty.
+// An earlier expiry of the same rule is replaced, but its previous permission is kept,
+// because that is the permission the account had before it got temporary access.
+// It returns the scheduled expiry.
+func (s *AccessRuleExpiryStore) schedule(path string, accountName string, previous string, expiresAt time.Time) (accessRuleExpiry, error) {
+	expiries, err := s.list()
+	if err != nil {
+		return accessRuleExpiry{}, err
+	}
+
+	existing, ok := findAccessRuleExpiry(expiries, path, accountName)
+	if ok {
+		previous = existing.Previous
+	}
+
+	expiry := accessRuleExpiry{
+		Path:        path,
+		AccountName: accountName,
+		Previous:    previous,
+		ExpiresAt:   expiresAt,
+	}
+	expiries = removeAccessRuleExpiry(expiries, path, accountName)
+	expiries = append(expiries, expiry)
+	return expiry, s.write(expiries)
+}
+
+// fail records that reverting the access rule of the account on the path failed with the given error.
+func (s *AccessRuleExpiryStore) fail(path string, accountName string, reason error) error {
+	expiries, err := s.list()
+	if err != nil {
+		return err
+	}
+
+	for i, expiry := range expiries {
+		if isAccessRuleExpiry(expiry, path, accountName) {
+			expiries[i].Error = reason.Error()
+		}
+	}
+	return s.write(expiries)
+}
+
+// failed returns the expiries of which the last attempt to revert the access rule failed.
+func (s *AccessRuleExpiryStore) failed() ([]accessRuleExpiry, error) {
+	expiries, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []accessRuleExpiry
+	for _, expiry := range expiries {
+		if expiry.Error != "" {
+			res = append(res, expiry)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ExpiresAt.Before(res[j].ExpiresAt)
+	})
+	return res, nil
+}
+
+// cancel removes the expiry of the access rule of the account on the path, if there is one.
+func (s *AccessRuleExpiryStore) cancel(path string, accountName string) error {
+	expiries, err := s.list()
+	if err != nil {
+		return err
+	}
+
+	remaining := removeAccessRuleExpiry(expiries, path, accountName)
+	if len(remaining) == len(expiries) {
+		return nil
+	}
+	return s.write(remaining)
+}
+
+// write atomically replaces the expiries file with the given expiries.
+func (s *AccessRuleExpiryStore) write(expiries []accessRuleExpiry) error {
+	raw, err := json.MarshalIndent(expiries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(s.path(), raw, 0600)
+}
+
+// isAccessRuleExpiry returns whether the expiry is the expiry of the access rule of the account on the path.
+func isAccessRuleExpiry(expiry accessRuleExpiry, path string, accountName string) bool {
+	return api.DirPath(expiry.Path).Value() == api.DirPath(path).Value() && api.AccountName(expiry.AccountName).Value() == api.AccountName(accountName).Value()
+}
+
+// findAccessRuleExpiry returns the expiry of the access rule of the account on the path, if there is one.
+func findAccessRuleExpiry(expiries []accessRuleExpiry, path string, accountName string) (accessRuleExpiry, bool) {
+	for _, expiry := range expiries {
+		if isAccessRuleExpiry(expiry, path, accountName) {
+			return expiry, true
+		}
+	}
+	return accessRuleExpiry{}, false
+}
+
+// removeAccessRuleExpiry returns the expiries without the expiry of the access rule of the account on the path.
+func removeAccessRuleExpiry(expiries []accessRuleExpiry, path string, accountName string) []accessRuleExpiry {
+	var res []accessRuleExpiry
+	for _, expiry := range expiries {
+		if isAccessRuleExpiry(expiry, path, accountName) {
+			continue
+		}
+		res = append(res, expiry)
+	}
+	return res
+}
+
+// aclExpireEnv returns the environment variables that make the acl-expire process use the same
+// account, configuration directory and API as a command that was run with the given flags.
+// Flags that were set through environment variables are not returned, as the process inherits those.
+func aclExpireEnv(flags *pflag.FlagSet) []string {
+	if flags == nil {
+		return nil
+	}
+
+	var env []string
+	flags.Visit(func(flag *pflag.Flag) {
+		envVar, ok := aclExpireEnvVars[flag.Name]
+		if ok {
+			env = append(env, envVar+"="+flag.Value.String())
+		}
+	})
+	return env
+}
+
+// spawnACLExpire starts a detached process that reverts the temporary access rules when they expire.
+// The process runs in non-interactive mode, because it has no terminal to prompt on.
+func spawnACLExpire(env []string) error {
+	return cloneproc.SpawnWithEnv(env, "acl-expire", "--non-interactive")
+}
+
+// ACLExpireCommand reverts temporary access rules when they expire. It is started
+// in a separate process when a temporary access rule is set and keeps running
+// until all temporary access rules have been reverted. As this process has no
+// output, failures are recorded in the expiries file and reported by `acl set`.
+type ACLExpireCommand struct {
+	expiries  *AccessRuleExpiryStore
+	newClient newClientFunc
+	now       func() time.Time
+	sleep     func(time.Duration)
+}
+
+// NewACLExpireCommand creates a new ACLExpireCommand.
+func NewACLExpireCommand(store CredentialConfig, newClient newClientFunc) *ACLExpireCommand {
+	return &ACLExpireCommand{
+		expiries:  NewAccessRuleExpiryStore(store),
+		newClient: newClient,
+		now:       time.Now,
+		sleep:     time.Sleep,
+	}
+}
+
+// Register registers the command on the provided Registerer.
+func (cmd *ACLExpireCommand) Register(r cli.Registerer) {
+	clause := r.Command("acl-expire", "Revert temporary access rules when they expire.").Hidden()
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run waits for the temporary access rules to expire and reverts them.
+func (cmd *ACLExpireCommand) Run() error {
+	for {
+		next, err := cmd.removeExpired()
+		if err != nil {
+			return err
+		}
+		if next.IsZero() {
+			return nil
+		}
+		cmd.sleep(next.Sub(cmd.now()))
+	}
+}
+
+// removeExpired reverts the access rules that have expired. It returns the time at which
+// the next access rule expires, or the zero time when there are no temporary access rules left
+// that have not expired. Access rules that could not be reverted are kept with the error,
+// so that they are reported and tried again the next time a temporary access rule is set.
+func (cmd *ACLExpireCommand) removeExpired() (time.Time, error) {
+	expiries, err := cmd.expiries.list()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var next time.Time
+	for _, expiry := range expiries {
+		if expiry.ExpiresAt.After(cmd.now()) {
+			if next.IsZero() || expiry.ExpiresAt.Before(next) {
+				next = expiry.ExpiresAt
+			}
+			continue
+		}
+
+		err = cmd.revert(expiry)
+		if err != nil {
+			err = cmd.expiries.fail(expiry.Path, expiry.AccountName, err)
+			if err != nil {
+				return time.Time{}, err
+			}
+			continue
+		}
+
+		// The expiries are read again before removing one, so that temporary
+		// access rules that were set in the meantime are not lost.
+		err = cmd.expiries.cancel(expiry.Path, expiry.AccountName)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	return next, nil
+}
+
+// revert sets the access rule back to the permission the account had before it got temporary access,
+// or removes the access rule when the account had no access rule on the path before.
+func (cmd *ACLExpireCommand) revert(expiry accessRuleExpiry) error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	if expiry.Previous != "" {
+		_, err = client.AccessRules().Set(expiry.Path, expiry.Previous, expiry.AccountName)
+		return err
+	}
+
+	err = client.AccessRules().Delete(expiry.Path, expiry.AccountName)
+	if err != nil && err != api.ErrAccessRuleNotFound {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestACLExpireCommand_Run(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := &AccessRuleExpiryStore{dir: func() string { return dir }}
+	_, err := store.schedule("namespace/repo", "dev1", "", now.Add(-time.Minute))
+	assert.OK(t, err)
+	_, err = store.schedule("namespace/repo/dir", "dev2", "read", now.Add(time.Hour))
+	assert.OK(t, err)
+	// A rule that was removed manually before it expired.
+	_, err = store.schedule("namespace/repo", "dev3", "", now.Add(2*time.Hour))
+	assert.OK(t, err)
+
+	var deleted []string
+	var set []string
+	var slept []time.Duration
+	cmd := ACLExpireCommand{
+		expiries: store,
+		newClient: func() (secrethub.ClientInterface, error) {
+			return fakeclient.Client{
+				AccessRuleService: &fakeclient.AccessRuleService{
+					DeleteFunc: func(path string, accountName string) error {
+						deleted = append(deleted, path+" "+accountName)
+						if accountName == "dev3" {
+							return api.ErrAccessRuleNotFound
+						}
+						return nil
+					},
+					SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+						set = append(set, path+" "+accountName+" "+permission)
+						return nil, nil
+					},
+				},
+			}, nil
+		},
+		now: func() time.Time {
+			return now
+		},
+		sleep: func(d time.Duration) {
+			slept = append(slept, d)
+			now = now.Add(d)
+		},
+	}
+
+	err = cmd.Run()
+
+	assert.OK(t, err)
+	assert.Equal(t, deleted, []string{"namespace/repo dev1", "namespace/repo dev3"})
+	assert.Equal(t, set, []string{"namespace/repo/dir dev2 read"})
+	assert.Equal(t, slept, []time.Duration{time.Hour, time.Hour})
+
+	expiries, err := store.list()
+	assert.OK(t, err)
+	assert.Equal(t, len(expiries), 0)
+}
+
+func TestACLExpireCommand_Run_Error(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := &AccessRuleExpiryStore{dir: func() string { return dir }}
+	_, err := store.schedule("namespace/repo", "dev1", "", now.Add(-time.Minute))
+	assert.OK(t, err)
+
+	cmd := ACLExpireCommand{
+		expiries: store,
+		newClient: func() (secrethub.ClientInterface, error) {
+			return nil, errors.New("cannot ask for input")
+		},
+		now: func() time.Time {
+			return now
+		},
+		sleep: func(d time.Duration) {
+			t.Fatalf("unexpected sleep of %s", d)
+		},
+	}
+
+	err = cmd.Run()
+	assert.OK(t, err)
+
+	failed, err := store.failed()
+	assert.OK(t, err)
+	assert.Equal(t, len(failed), 1)
+	assert.Equal(t, failed[0].Error, "cannot ask for input")
+
+	// Setting a new temporary rule replaces the failed expiry, but keeps the previous permission.
+	_, err = store.schedule("namespace/repo", "dev1", "write", now.Add(time.Hour))
+	assert.OK(t, err)
+	expiries, err := store.list()
+	assert.OK(t, err)
+	assert.Equal(t, expiries, []accessRuleExpiry{{Path: "namespace/repo", AccountName: "dev1", ExpiresAt: now.Add(time.Hour)}})
+}
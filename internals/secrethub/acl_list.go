@@ -10,6 +10,7 @@ import (
 
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
 )
 
 // ACLListCommand prints access rules for the given directory.
@@ -17,17 +18,21 @@ type ACLListCommand struct {
 	path          api.DirPath
 	depth         int
 	ancestors     bool
+	recursive     bool
 	useTimestamps bool
+	fullPaths     bool
 	timeFormatter TimeFormatter
 	io            ui.IO
 	newClient     newClientFunc
+	terminalWidth func(int) (int, error)
 }
 
 // NewACLListCommand creates a new ACLListCommand.
 func NewACLListCommand(io ui.IO, newClient newClientFunc) *ACLListCommand {
 	return &ACLListCommand{
-		io:        io,
-		newClient: newClient,
+		io:            io,
+		newClient:     newClient,
+		terminalWidth: getTerminalWidth,
 	}
 }
 
@@ -37,7 +42,9 @@ func (cmd *ACLListCommand) Register(r cli.Registerer) {
 	clause.Alias("list")
 	clause.Flags().IntVarP(&cmd.depth, "depth", "d", -1, "The maximum depth to which the rules of child directories should be displayed.")
 	clause.Flags().BoolVarP(&cmd.ancestors, "all", "a", false, "List all rules that apply on the directory, including rules on parent directories.")
+	clause.Flags().BoolVarP(&cmd.recursive, "recursive", "r", false, "Show the effective permissions of every account on the directory and each of its subdirectories, and the directory each permission is inherited from.")
 	registerTimestampFlag(clause, &cmd.useTimestamps)
+	registerFullPathsFlag(clause, &cmd.fullPaths)
 
 	clause.BindAction(cmd.Run)
 	clause.BindArguments([]cli.Argument{
@@ -62,6 +69,10 @@ func (cmd *ACLListCommand) run() error {
 		return err
 	}
 
+	if cmd.recursive {
+		return cmd.runRecursive(client)
+	}
+
 	rules, err := client.AccessRules().List(cmd.path.Value(), cmd.depth, cmd.ancestors)
 	if err != nil {
 		return err
@@ -104,22 +115,26 @@ func (cmd *ACLListCommand) run() error {
 
 	sort.Sort(api.SortDirPaths(paths))
 
-	tabWriter := tabwriter.NewWriter(cmd.io.Output(), 0, 4, 4, ' ', 0)
-	fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\n", "PATH", "PERMISSIONS", "LAST EDITED", "ACCOUNT")
-
+	rows := [][]string{{"PATH", "PERMISSIONS", "LAST EDITED", "ACCOUNT"}}
 	for _, p := range paths {
 		rulesForPath := ruleMap[p]
 		sort.Sort(api.SortAccessRules(rules))
 
 		for _, rule := range rulesForPath {
-			fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\n",
-				p,
-				rule.Permission,
+			rows = append(rows, []string{
+				p.String(),
+				rule.Permission.String(),
 				cmd.timeFormatter.Format(rule.LastChangedAt.Local()),
-				rule.Account.Name,
-			)
+				rule.Account.Name.String(),
+			})
 		}
 	}
+	truncateColumn(rows, 0, outputTableWidth(cmd.io, cmd.fullPaths, cmd.terminalWidth), 4)
+
+	tabWriter := tabwriter.NewWriter(cmd.io.Output(), 0, 4, 4, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\n", row[0], row[1], row[2], row[3])
+	}
 
 	err = tabWriter.Flush()
 	if err != nil {
@@ -128,3 +143,115 @@ func (cmd *ACLListCommand) run() error {
 
 	return nil
 }
+
+// runRecursive prints the effective permissions on the directory and each of its subdirectories.
+// A permission is effective on a directory when it is granted by a rule on the directory itself or
+// on one of its parents. When multiple rules apply to an account, the highest permission is shown.
+func (cmd *ACLListCommand) runRecursive(client secrethub.ClientInterface) error {
+	rules, err := client.AccessRules().List(cmd.path.Value(), cmd.depth, true)
+	if err != nil {
+		return err
+	}
+
+	tree, err := client.Dirs().GetTree(cmd.path.Value(), cmd.depth, true)
+	if err != nil {
+		return err
+	}
+
+	rulesPerDir := make(map[uuid.UUID][]*api.AccessRule)
+	for _, rule := range rules {
+		rulesPerDir[rule.DirID] = append(rulesPerDir[rule.DirID], rule)
+	}
+
+	var root *api.Dir
+	children := make(map[uuid.UUID][]*api.Dir)
+	for _, dir := range tree.Dirs {
+		dirPath, err := tree.AbsDirPath(dir.DirID)
+		if err != nil {
+			return err
+		}
+		if dirPath == cmd.path {
+			root = dir
+		}
+		if dir.ParentID != nil {
+			children[*dir.ParentID] = append(children[*dir.ParentID], dir)
+		}
+	}
+	if root == nil {
+		return nil
+	}
+
+	rows := [][]string{{"PATH", "ACCOUNT", "PERMISSIONS", "INHERITED FROM"}}
+	var walk func(dir *api.Dir, name string, indent string) error
+	walk = func(dir *api.Dir, name string, indent string) error {
+		effective := effectiveAccessRules(tree, dir, rulesPerDir)
+
+		label := indent + name
+		if len(effective) == 0 {
+			rows = append(rows, []string{label, "", "", ""})
+		}
+		for _, rule := range effective {
+			inheritedFrom := "-"
+			if rule.DirID != dir.DirID {
+				dirPath, err := tree.AbsDirPath(rule.DirID)
+				if err != nil {
+					return err
+				}
+				inheritedFrom = dirPath.String()
+			}
+			rows = append(rows, []string{label, rule.Account.Name.String(), rule.Permission.String(), inheritedFrom})
+			label = ""
+		}
+
+		subDirs := children[dir.DirID]
+		sort.Sort(api.SortDirByName(subDirs))
+		for _, sub := range subDirs {
+			err := walk(sub, sub.Name+"/", indent+"  ")
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err = walk(root, cmd.path.String()+"/", "")
+	if err != nil {
+		return err
+	}
+	truncateColumn(rows, 3, outputTableWidth(cmd.io, cmd.fullPaths, cmd.terminalWidth), 4)
+
+	tabWriter := tabwriter.NewWriter(cmd.io.Output(), 0, 4, 4, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\n", row[0], row[1], row[2], row[3])
+	}
+	return tabWriter.Flush()
+}
+
+// effectiveAccessRules returns for every account the rule that grants the highest permission on
+// the directory, sorted by account name. Of rules with the same permission, the rule closest to
+// the directory is returned.
+func effectiveAccessRules(tree *api.Tree, dir *api.Dir, rulesPerDir map[uuid.UUID][]*api.AccessRule) []*api.AccessRule {
+	effective := make(map[string]*api.AccessRule)
+	for current := dir; current != nil; {
+		for _, rule := range rulesPerDir[current.DirID] {
+			name := rule.Account.Name.String()
+			if existing, ok := effective[name]; !ok || rule.Permission > existing.Permission {
+				effective[name] = rule
+			}
+		}
+
+		if current.ParentID == nil {
+			break
+		}
+		current = tree.Dirs[*current.ParentID]
+	}
+
+	res := make([]*api.AccessRule, 0, len(effective))
+	for _, rule := range effective {
+		res = append(res, rule)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Account.Name.String() < res[j].Account.Name.String()
+	})
+	return res
+}
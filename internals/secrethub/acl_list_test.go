@@ -20,6 +20,7 @@ func TestACLListCommand_run(t *testing.T) {
 
 	dir1ID := uuid.New()
 	dir2ID := uuid.New()
+	dir3ID := uuid.New()
 
 	cases := map[string]struct {
 		cmd          ACLListCommand
@@ -144,6 +145,72 @@ func TestACLListCommand_run(t *testing.T) {
 				"namespace/repo        read           1 hour ago     developer\n" +
 				"namespace/repo/dir    admin          1 hour ago     developer\n",
 		},
+		"recursive": {
+			cmd: ACLListCommand{
+				path:      api.DirPath("namespace/repo/dir"),
+				depth:     -1,
+				recursive: true,
+			},
+			accessrules: fakeclient.AccessRuleService{
+				ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+					return []*api.AccessRule{
+						{
+							Account: &api.Account{
+								Name: "developer",
+							},
+							DirID:      dir1ID,
+							Permission: api.PermissionAdmin,
+						},
+						{
+							Account: &api.Account{
+								Name: "developer",
+							},
+							DirID:      dir2ID,
+							Permission: api.PermissionRead,
+						},
+						{
+							Account: &api.Account{
+								Name: "another dev",
+							},
+							DirID:      dir2ID,
+							Permission: api.PermissionWrite,
+						},
+					}, nil
+				},
+			},
+			dirs: fakeclient.DirService{
+				GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+					return &api.Tree{
+						ParentPath: "namespace",
+						Dirs: map[uuid.UUID]*api.Dir{
+							dir1ID: {
+								Name:  "repo",
+								DirID: dir1ID,
+							},
+							dir2ID: {
+								Name:     "dir",
+								DirID:    dir2ID,
+								ParentID: &dir1ID,
+							},
+							dir3ID: {
+								Name:     "sub",
+								DirID:    dir3ID,
+								ParentID: &dir2ID,
+							},
+						},
+						RootDir: &api.Dir{
+							Name:  "repo",
+							DirID: dir1ID,
+						},
+					}, nil
+				},
+			},
+			expectedOut: "PATH                   ACCOUNT        PERMISSIONS    INHERITED FROM\n" +
+				"namespace/repo/dir/    another dev    write          -\n" +
+				"                       developer      admin          namespace/repo\n" +
+				"  sub/                 another dev    write          namespace/repo/dir\n" +
+				"                       developer      admin          namespace/repo\n",
+		},
 		"tree fail": {
 			cmd: ACLListCommand{
 				path:      api.DirPath("namespace/repo/dir"),
@@ -0,0 +1,203 @@
+package secrethub
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+
+	"github.com/spf13/cobra"
+)
+
+// ACLReportCommand prints an access review report of a namespace.
+type ACLReportCommand struct {
+	namespace     api.Namespace
+	format        string
+	outFile       string
+	fullPaths     bool
+	io            ui.IO
+	newClient     newClientFunc
+	terminalWidth func(int) (int, error)
+}
+
+// NewACLReportCommand creates a new ACLReportCommand.
+func NewACLReportCommand(io ui.IO, newClient newClientFunc) *ACLReportCommand {
+	return &ACLReportCommand{
+		io:            io,
+		newClient:     newClient,
+		terminalWidth: getTerminalWidth,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ACLReportCommand) Register(r cli.Registerer) {
+	clause := r.Command("report", "Show every account with access to the repositories of a namespace, the directories it can access and the rule granting the access.")
+	clause.HelpLong("The report contains a record for every account and every directory the account can access in the repositories of the namespace. " +
+		"Every record shows the effective permission of the account on the directory and the path of the directory of the access rule that grants it. " +
+		"When multiple rules apply to an account, the rule that grants the highest permission is shown.\n\n" +
+		"Use --output-format csv or json to process the report in other tools.")
+	clause.Flags().StringVar(&cmd.format, "output-format", formatTable, "Specify the format in which to output the report. Options are: table, json and csv. The json format writes one JSON object per line.")
+	_ = clause.Cmd.RegisterFlagCompletionFunc("output-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{formatTable, formatJSON, formatCSV}, cobra.ShellCompDirectiveDefault
+	})
+	clause.Flags().StringVarP(&cmd.outFile, "out-file", "o", "", "Write the report to this file instead of stdout.")
+	registerFullPathsFlag(clause, &cmd.fullPaths)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.namespace, Name: "namespace", Required: true, Placeholder: namespacePlaceHolder, Description: "The namespace to report the access of."},
+	})
+}
+
+// Run prints the access review report.
+func (cmd *ACLReportCommand) Run() error {
+	if cmd.format != formatTable && cmd.format != formatJSON && cmd.format != formatCSV {
+		return errNoSuchFormat(cmd.format)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	repos, err := client.Repos().List(cmd.namespace.String())
+	if err != nil {
+		return err
+	}
+	sort.Sort(api.SortRepoByName(repos))
+
+	var records []accessReportRecord
+	for _, repo := range repos {
+		repoRecords, err := repoAccessReport(client, repo.Path())
+		if err != nil {
+			return err
+		}
+		records = append(records, repoRecords...)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].account < records[j].account
+	})
+
+	out := cmd.io.Output()
+	if cmd.outFile != "" {
+		file, err := os.OpenFile(cmd.outFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return ErrCannotWrite(cmd.outFile, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	return cmd.write(out, records)
+}
+
+// write writes the records in the configured format.
+func (cmd *ACLReportCommand) write(out io.Writer, records []accessReportRecord) error {
+	header := []string{"account", "account type", "path", "permission", "granted by"}
+
+	var formatter listFormatter
+	switch cmd.format {
+	case formatJSON:
+		formatter = newJSONFormatter(out, header)
+	case formatCSV:
+		formatter = newCSVFormatter(out, header)
+	default:
+		rows := [][]string{{"ACCOUNT", "ACCOUNT TYPE", "PATH", "PERMISSION", "GRANTED BY"}}
+		for _, record := range records {
+			rows = append(rows, record.row())
+		}
+		width := outputTableWidth(cmd.io, cmd.fullPaths || cmd.outFile != "", cmd.terminalWidth)
+		truncateColumn(rows, 2, width, 4)
+		truncateColumn(rows, 4, width, 4)
+
+		tabWriter := tabwriter.NewWriter(out, 0, 4, 4, ' ', 0)
+		for _, row := range rows {
+			fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\t%s\n", row[0], row[1], row[2], row[3], row[4])
+		}
+		return tabWriter.Flush()
+	}
+
+	for _, record := range records {
+		err := formatter.Write(record.row())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// accessReportRecord is the access of an account to a single directory.
+type accessReportRecord struct {
+	account     string
+	accountType string
+	path        string
+	permission  string
+	grantedBy   string
+}
+
+func (r accessReportRecord) row() []string {
+	return []string{r.account, r.accountType, r.path, r.permission, r.grantedBy}
+}
+
+// repoAccessReport returns a record for every account and every directory of the repo
+// the account has access to, sorted by path.
+func repoAccessReport(client secrethub.ClientInterface, repo api.RepoPath) ([]accessReportRecord, error) {
+	rules, err := client.AccessRules().List(repo.Value(), -1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := client.Dirs().GetTree(repo.Value(), -1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rulesPerDir := make(map[uuid.UUID][]*api.AccessRule)
+	for _, rule := range rules {
+		rulesPerDir[rule.DirID] = append(rulesPerDir[rule.DirID], rule)
+	}
+
+	dirPaths := make(map[uuid.UUID]api.DirPath, len(tree.Dirs))
+	for dirID := range tree.Dirs {
+		dirPath, err := tree.AbsDirPath(dirID)
+		if err != nil {
+			return nil, err
+		}
+		dirPaths[dirID] = dirPath
+	}
+
+	var records []accessReportRecord
+	for dirID, dir := range tree.Dirs {
+		for _, rule := range effectiveAccessRules(tree, dir, rulesPerDir) {
+			accountType := accountTypeUser
+			if rule.Account.Name.IsService() {
+				accountType = accountTypeService
+			}
+
+			records = append(records, accessReportRecord{
+				account:     rule.Account.Name.String(),
+				accountType: accountType,
+				path:        dirPaths[dirID].String(),
+				permission:  rule.Permission.String(),
+				grantedBy:   dirPaths[rule.DirID].String(),
+			})
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].path != records[j].path {
+			return records[i].path < records[j].path
+		}
+		return records[i].account < records[j].account
+	})
+	return records, nil
+}
@@ -0,0 +1,87 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestACLReportCommand_Run(t *testing.T) {
+	rootID := uuid.New()
+	dirID := uuid.New()
+
+	cases := map[string]struct {
+		format      string
+		expectedOut string
+		expectedErr error
+	}{
+		"csv": {
+			format: formatCSV,
+			expectedOut: "account,account type,path,permission,granted by\n" +
+				"dev1,user,dev1/repo,admin,dev1/repo\n" +
+				"dev1,user,dev1/repo/dir,admin,dev1/repo\n" +
+				"s-abc,service,dev1/repo/dir,read,dev1/repo/dir\n",
+		},
+		"json": {
+			format: formatJSON,
+			expectedOut: `{"Account":"dev1","AccountType":"user","GrantedBy":"dev1/repo","Path":"dev1/repo","Permission":"admin"}` + "\n" +
+				`{"Account":"dev1","AccountType":"user","GrantedBy":"dev1/repo","Path":"dev1/repo/dir","Permission":"admin"}` + "\n" +
+				`{"Account":"s-abc","AccountType":"service","GrantedBy":"dev1/repo/dir","Path":"dev1/repo/dir","Permission":"read"}` + "\n",
+		},
+		"invalid format": {
+			format:      "xml",
+			expectedErr: errNoSuchFormat("xml"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			cmd := ACLReportCommand{
+				namespace: "dev1",
+				format:    tc.format,
+				io:        io,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						RepoService: &fakeclient.RepoService{
+							ListFunc: func(namespace string) ([]*api.Repo, error) {
+								return []*api.Repo{{Owner: "dev1", Name: "repo"}}, nil
+							},
+						},
+						AccessRuleService: &fakeclient.AccessRuleService{
+							ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+								return []*api.AccessRule{
+									{Account: &api.Account{Name: "s-abc"}, DirID: dirID, Permission: api.PermissionRead},
+									{Account: &api.Account{Name: "dev1"}, DirID: rootID, Permission: api.PermissionAdmin},
+								}, nil
+							},
+						},
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								return &api.Tree{
+									ParentPath: "dev1",
+									RootDir:    &api.Dir{Name: "repo", DirID: rootID},
+									Dirs: map[uuid.UUID]*api.Dir{
+										rootID: {Name: "repo", DirID: rootID},
+										dirID:  {Name: "dir", DirID: dirID, ParentID: &rootID},
+									},
+								}, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+		})
+	}
+}
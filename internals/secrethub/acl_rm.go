@@ -73,7 +73,7 @@ func (cmd *ACLRmCommand) Run() error {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Removal complete! The access rule for %s on %s has been removed.\n", cmd.accountName, cmd.path)
+	ui.Successf(cmd.io.Output(), "Removal complete! The access rule for %s on %s has been removed.\n", cmd.accountName, cmd.path)
 
 	return nil
 }
@@ -2,11 +2,14 @@ package secrethub
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // ACLSetCommand is a command to set access rules.
@@ -16,14 +19,20 @@ type ACLSetCommand struct {
 	io          ui.IO
 	path        api.DirPath
 	permission  api.Permission
+	expiresIn   time.Duration
+	expiries    *AccessRuleExpiryStore
+	flags       *pflag.FlagSet
+	spawnExpire func(env []string) error
 	newClient   newClientFunc
 }
 
 // NewACLSetCommand creates a new ACLSetCommand.
-func NewACLSetCommand(io ui.IO, newClient newClientFunc) *ACLSetCommand {
+func NewACLSetCommand(io ui.IO, newClient newClientFunc, expiries *AccessRuleExpiryStore) *ACLSetCommand {
 	return &ACLSetCommand{
-		io:        io,
-		newClient: newClient,
+		io:          io,
+		newClient:   newClient,
+		expiries:    expiries,
+		spawnExpire: spawnACLExpire,
 	}
 }
 
@@ -32,7 +41,18 @@ func NewACLSetCommand(io ui.IO, newClient newClientFunc) *ACLSetCommand {
 func (cmd *ACLSetCommand) Register(r cli.Registerer) {
 	clause := r.Command("set", "Set access rule for a user or service on a path.")
 	registerForceFlag(clause, &cmd.force)
+	clause.Flags().DurationVar(&cmd.expiresIn, "expires-in", 0, "Revert the access rule after this duration, e.g. 24h, to grant temporary access. "+
+		"When it expires, the access rule is set back to the permission the account had before, or removed if the account had no access rule on the directory. "+
+		"The expiry is not stored by SecretHub, but only in acl_expiries.json in the configuration directory of this machine. "+
+		"It is reverted by a process that keeps running in the background on this machine, with the credential of this command. "+
+		"So the machine has to stay on until the rule expires and nothing is reverted if the configuration directory is removed. "+
+		"When the credential is protected by a passphrase, the background process can only use it if the passphrase is set with --credential-passphrase or the credential is added to the agent without confirmation. "+
+		"Expired access rules that could not be reverted are reported and tried again the next time a temporary access rule is set.")
 
+	clause.AddPreRunE(func(c *cobra.Command, _ []string) error {
+		cmd.flags = c.Flags()
+		return nil
+	})
 	clause.BindAction(cmd.Run)
 	clause.BindArguments([]cli.Argument{
 		{Value: &cmd.path, Name: "dir-path", Placeholder: dirPathPlaceHolder, Required: true, Description: "The path of the directory to set the access rule for."},
@@ -43,6 +63,15 @@ func (cmd *ACLSetCommand) Register(r cli.Registerer) {
 
 // Run handles the command with the options as specified in the command.
 func (cmd *ACLSetCommand) Run() error {
+	if cmd.expiresIn < 0 {
+		return ErrInvalidExpiresIn(cmd.expiresIn)
+	}
+
+	err := cmd.reportFailedExpiries()
+	if err != nil {
+		return err
+	}
+
 	if !cmd.force {
 		confirmed, err := ui.AskYesNo(
 			cmd.io,
@@ -72,6 +101,17 @@ func (cmd *ACLSetCommand) Run() error {
 		return err
 	}
 
+	var previous string
+	if cmd.expiresIn > 0 {
+		current, err := client.AccessRules().Get(cmd.path.Value(), cmd.accountName.Value())
+		if err != nil && err != api.ErrAccessRuleNotFound {
+			return err
+		}
+		if err == nil {
+			previous = current.Permission.String()
+		}
+	}
+
 	_, err = client.AccessRules().Set(cmd.path.Value(), cmd.permission.String(), cmd.accountName.Value())
 	if err != nil {
 		return err
@@ -79,5 +119,47 @@ func (cmd *ACLSetCommand) Run() error {
 
 	fmt.Fprintln(cmd.io.Output(), "Access rule set!")
 
+	if cmd.expiresIn == 0 {
+		// A permanent rule replaces a temporary rule, so it should not be removed when the temporary rule would have expired.
+		return cmd.expiries.cancel(cmd.path.Value(), cmd.accountName.Value())
+	}
+
+	expiry, err := cmd.expiries.schedule(cmd.path.Value(), cmd.accountName.Value(), previous, time.Now().Add(cmd.expiresIn))
+	if err != nil {
+		return err
+	}
+
+	err = cmd.spawnExpire(aclExpireEnv(cmd.flags))
+	if err != nil {
+		return err
+	}
+
+	if expiry.Previous != "" {
+		fmt.Fprintf(cmd.io.Output(), "The access rule will be set back to %s in %s.\n", expiry.Previous, cmd.expiresIn)
+	} else {
+		fmt.Fprintf(cmd.io.Output(), "The access rule will be removed in %s.\n", cmd.expiresIn)
+	}
+
+	return nil
+}
+
+// reportFailedExpiries prints the temporary access rules that expired but could not be reverted.
+// These are tried again by the acl-expire process that is started when a temporary access rule is set.
+func (cmd *ACLSetCommand) reportFailedExpiries() error {
+	failed, err := cmd.expiries.failed()
+	if err != nil {
+		return err
+	}
+
+	for _, expiry := range failed {
+		fmt.Fprintf(
+			cmd.io.Output(),
+			"[WARNING] The temporary access rule of %s on %s expired at %s, but could not be reverted: %s\n",
+			expiry.AccountName,
+			expiry.Path,
+			expiry.ExpiresAt.Format(time.RFC3339),
+			expiry.Error,
+		)
+	}
 	return nil
 }
@@ -2,7 +2,9 @@ package secrethub
 
 import (
 	"bytes"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
@@ -12,18 +14,20 @@ import (
 	"github.com/secrethub/secrethub-go/internals/errio"
 	"github.com/secrethub/secrethub-go/pkg/secrethub"
 	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+	"github.com/spf13/pflag"
 )
 
 func TestACLSetCommand_Run(t *testing.T) {
 	testErr := errio.Namespace("test").Code("test").Error("test error")
 
 	cases := map[string]struct {
-		cmd       ACLSetCommand
-		in        string
-		askErr    error
-		err       error
-		stdout    string
-		promptOut string
+		cmd              ACLSetCommand
+		in               string
+		askErr           error
+		err              error
+		stdout           string
+		promptOut        string
+		expectedExpiries []string
 	}{
 		"success": {
 			cmd: ACLSetCommand{
@@ -46,6 +50,65 @@ func TestACLSetCommand_Run(t *testing.T) {
 			promptOut: "[WARNING] This gives dev1 read rights on all directories and secrets contained in namespace/repo/dir. " +
 				"Are you sure you want to set this access rule? [y/N]: ",
 		},
+		"expires in": {
+			cmd: ACLSetCommand{
+				accountName: "dev1",
+				permission:  api.PermissionRead,
+				path:        "namespace/repo/dir",
+				expiresIn:   24 * time.Hour,
+				force:       true,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						AccessRuleService: &fakeclient.AccessRuleService{
+							GetFunc: func(path string, accountName string) (*api.AccessRule, error) {
+								return nil, api.ErrAccessRuleNotFound
+							},
+							SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+								return nil, nil
+							},
+						},
+					}, nil
+				},
+			},
+			stdout: "Setting access rule for dev1 at namespace/repo/dir with read\n" +
+				"Access rule set!\n" +
+				"The access rule will be removed in 24h0m0s.\n",
+			expectedExpiries: []string{"namespace/repo/dir dev1 "},
+		},
+		"expires in with previous permission": {
+			cmd: ACLSetCommand{
+				accountName: "dev1",
+				permission:  api.PermissionWrite,
+				path:        "namespace/repo/dir",
+				expiresIn:   time.Hour,
+				force:       true,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						AccessRuleService: &fakeclient.AccessRuleService{
+							GetFunc: func(path string, accountName string) (*api.AccessRule, error) {
+								return &api.AccessRule{Permission: api.PermissionRead}, nil
+							},
+							SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+								return nil, nil
+							},
+						},
+					}, nil
+				},
+			},
+			stdout: "Setting access rule for dev1 at namespace/repo/dir with write\n" +
+				"Access rule set!\n" +
+				"The access rule will be set back to read in 1h0m0s.\n",
+			expectedExpiries: []string{"namespace/repo/dir dev1 read"},
+		},
+		"negative expires in": {
+			cmd: ACLSetCommand{
+				accountName: "dev1",
+				permission:  api.PermissionRead,
+				path:        "namespace/repo/dir",
+				expiresIn:   -time.Hour,
+			},
+			err: ErrInvalidExpiresIn(-time.Hour),
+		},
 		"abort": {
 			cmd: ACLSetCommand{
 				accountName: "dev1",
@@ -112,6 +175,16 @@ func TestACLSetCommand_Run(t *testing.T) {
 			io.PromptErr = tc.askErr
 			tc.cmd.io = io
 
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+			tc.cmd.expiries = &AccessRuleExpiryStore{dir: func() string { return dir }}
+
+			spawned := false
+			tc.cmd.spawnExpire = func(env []string) error {
+				spawned = true
+				return nil
+			}
+
 			// Act
 			err := tc.cmd.Run()
 
@@ -120,6 +193,64 @@ func TestACLSetCommand_Run(t *testing.T) {
 			assert.Equal(t, io.Out.String(), tc.stdout)
 			assert.Equal(t, io.PromptOut.String(), tc.promptOut)
 
+			expiries, err := tc.cmd.expiries.list()
+			assert.OK(t, err)
+			var actualExpiries []string
+			for _, expiry := range expiries {
+				actualExpiries = append(actualExpiries, expiry.Path+" "+expiry.AccountName+" "+expiry.Previous)
+			}
+			assert.Equal(t, actualExpiries, tc.expectedExpiries)
+			assert.Equal(t, spawned, tc.expectedExpiries != nil)
 		})
 	}
 }
+
+func TestACLSetCommand_Run_ReportFailedExpiries(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	store := &AccessRuleExpiryStore{dir: func() string { return dir }}
+	expiresAt := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	_, err := store.schedule("namespace/repo", "dev1", "", expiresAt)
+	assert.OK(t, err)
+	assert.OK(t, store.fail("namespace/repo", "dev1", errors.New("cannot ask for input")))
+
+	io := fakeui.NewIO(t)
+	cmd := ACLSetCommand{
+		io:          io,
+		accountName: "dev2",
+		permission:  api.PermissionRead,
+		path:        "namespace/repo",
+		force:       true,
+		expiries:    store,
+		newClient: func() (secrethub.ClientInterface, error) {
+			return fakeclient.Client{
+				AccessRuleService: &fakeclient.AccessRuleService{
+					SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+						return nil, nil
+					},
+				},
+			}, nil
+		},
+	}
+
+	err = cmd.Run()
+
+	assert.OK(t, err)
+	assert.Equal(t, io.Out.String(), "[WARNING] The temporary access rule of dev1 on namespace/repo expired at 2018-01-01T12:00:00Z, but could not be reverted: cannot ask for input\n"+
+		"Setting access rule for dev2 at namespace/repo with read\n"+
+		"Access rule set!\n")
+}
+
+func TestACLExpireEnv(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("config-dir", "", "")
+	flags.String("credential-passphrase", "", "")
+	flags.String("profile", "", "")
+	flags.String("log-level", "", "")
+
+	assert.OK(t, flags.Parse([]string{"--config-dir", "/tmp/config", "--credential-passphrase", "secret", "--log-level", "debug"}))
+
+	assert.Equal(t, aclExpireEnv(flags), []string{"SECRETHUB_CONFIG_DIR=/tmp/config", "SECRETHUB_CREDENTIAL_PASSPHRASE=secret"})
+	assert.Equal(t, aclExpireEnv(nil), []string(nil))
+}
@@ -0,0 +1,90 @@
+package secrethub
+
+import (
+	"net/http"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// Errors
+var (
+	ErrAgentNotRunning      = errMain.Code("agent_not_running").Error("the agent is not running: start it with `secrethub agent start`")
+	ErrAgentAlreadyRunning  = errMain.Code("agent_already_running").ErrorPref("an agent is already running on %s")
+	ErrAgentNotStarted      = errMain.Code("agent_not_started").Error("the agent did not start: run `secrethub agent start --foreground` to see why")
+	ErrAgentRequestFailed   = errMain.Code("agent_request_failed").ErrorPref("request to the agent failed: %s")
+	ErrInvalidAgentRequest  = errMain.Code("invalid_agent_request").ErrorPref("invalid request body: %s")
+	ErrInvalidAgentLifetime = errMain.Code("invalid_agent_lifetime").ErrorPref("invalid value for --lifetime: %s: must not be negative")
+	ErrInvalidAgentTTL      = errMain.Code("invalid_agent_ttl").ErrorPref("invalid value for --ttl: %s: must not be negative")
+
+	ErrAgentNoCredentials       = errMain.Code("agent_no_credentials").StatusError("no credentials are unlocked in the agent: add one with `secrethub agent add`", http.StatusNotFound)
+	ErrAgentCredentialNotFound  = errMain.Code("agent_credential_not_found").StatusError("the credential is not unlocked in the agent", http.StatusNotFound)
+	ErrAgentCredentialAmbiguous = errMain.Code("agent_credential_ambiguous").StatusError("multiple credentials are unlocked in the agent: select one by its fingerprint", http.StatusBadRequest)
+	ErrAgentConfirmationDenied  = errMain.Code("agent_confirmation_denied").StatusError("the use of the credential was not confirmed", http.StatusForbidden)
+	ErrAgentCannotConfirm       = errMain.Code("agent_cannot_confirm").StatusError("the agent cannot ask for confirmation: start it with --confirm-program or in the foreground in a terminal", http.StatusForbidden)
+	ErrAgentSessionNotFound     = errMain.Code("agent_session_not_found").StatusError("the session has expired or its credential was removed from the agent", http.StatusNotFound)
+	ErrAgentSignURLNotAllowed   = errMain.Code("agent_sign_url_not_allowed").StatusErrorPref("the agent only signs requests to the API, not to %s", http.StatusForbidden)
+	ErrAgentConfirmationFailed  = errMain.Code("agent_confirmation_failed").ErrorPref("could not ask for confirmation: %s")
+)
+
+// agentErrors are the errors of the agent that the client returns as is, so that callers can handle them.
+var agentErrors = []errio.PublicStatusError{
+	ErrAgentNoCredentials,
+	ErrAgentCredentialNotFound,
+	ErrAgentCredentialAmbiguous,
+	ErrAgentConfirmationDenied,
+	ErrAgentCannotConfirm,
+	ErrAgentSessionNotFound,
+	// Secrets that do not exist are reported as such when they are read through the agent,
+	// so that commands handle them the same as when they read secrets themselves.
+	api.ErrSecretNotFound,
+	api.ErrSecretVersionNotFound,
+}
+
+// AgentCommand handles operations on the agent.
+type AgentCommand struct {
+	io              ui.IO
+	clientFactory   ClientFactory
+	credentialStore CredentialConfig
+}
+
+// NewAgentCommand creates a new AgentCommand.
+func NewAgentCommand(io ui.IO, clientFactory ClientFactory, credentialStore CredentialConfig) *AgentCommand {
+	return &AgentCommand{
+		io:              io,
+		clientFactory:   clientFactory,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *AgentCommand) Register(r cli.Registerer) {
+	clause := r.Command("agent", "Manage the agent that keeps your credentials unlocked.")
+	clause.HelpLong("The agent is a background process that holds unlocked credentials in memory, similar to ssh-agent. " +
+		"While a credential is unlocked in the agent, other secrethub commands that use it get it from the agent instead of unlocking it themselves, " +
+		"so you are not asked for your passphrase and short-lived commands run faster.\n\n" +
+		"Credentials are unlocked with `secrethub agent add`, listed with `secrethub agent list` and removed with `secrethub agent remove`. " +
+		"With --ttl, a credential is removed automatically after the given duration. " +
+		"With --confirm, the agent asks you to confirm every time a command or application uses the credential.\n\n" +
+		"The unlocked credentials never leave the agent: commands let the agent sign their requests to the API and read secrets through the agent. " +
+		"Commands that have to decrypt anything else, like the names in `secrethub tree` or the keys to write a secret, still ask for your passphrase.\n\n" +
+		"The agent listens on the " + agentSocketName + " Unix domain socket in the " + agentDirName + " directory of the configuration directory. " +
+		"Applications can read secrets through its JSON API:\n\n" +
+		"    GET  /v1/status\n" +
+		"    GET  /v1/credentials\n" +
+		"    POST /v1/read      {\"path\": \"my-org/my-repo/my-secret\"}\n" +
+		"    POST /v1/resolve   {\"paths\": [\"my-org/my-repo/my-secret\", ...]}\n" +
+		"    POST /v1/stop\n\n" +
+		"When more than one credential is unlocked, select one by setting \"credential\" to (the start of) its fingerprint in the request body. " +
+		"Reads with credentials that were added with --confirm have to be confirmed. " +
+		"The socket is only accessible by the user that started the agent.")
+	NewAgentStartCommand(cmd.io, cmd.clientFactory.NewClientWithCredentials, cmd.clientFactory.APIRemote, cmd.credentialStore).Register(clause)
+	NewAgentStatusCommand(cmd.io, cmd.credentialStore).Register(clause)
+	NewAgentAddCommand(cmd.io, cmd.credentialStore).Register(clause)
+	NewAgentListCommand(cmd.io, cmd.credentialStore).Register(clause)
+	NewAgentRemoveCommand(cmd.io, cmd.credentialStore).Register(clause)
+	NewAgentStopCommand(cmd.io, cmd.credentialStore).Register(clause)
+}
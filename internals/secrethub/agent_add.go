@@ -0,0 +1,119 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+// AgentAddCommand unlocks a credential and adds it to the agent.
+type AgentAddCommand struct {
+	credentialFile  cli.StringValue
+	ttl             time.Duration
+	confirm         bool
+	io              ui.IO
+	credentialStore CredentialConfig
+}
+
+// NewAgentAddCommand creates a new AgentAddCommand.
+func NewAgentAddCommand(io ui.IO, credentialStore CredentialConfig) *AgentAddCommand {
+	return &AgentAddCommand{
+		io:              io,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AgentAddCommand) Register(r cli.Registerer) {
+	clause := r.Command("add", "Unlock a credential and add it to the agent.")
+	clause.HelpLong("You are asked for the passphrase of the credential once. " +
+		"Then secrethub commands that use this credential get it from the agent, until it is removed with `secrethub agent remove`, its TTL has passed or the agent is stopped.\n\n" +
+		"When no credential file is given, the configured credential is added.")
+	registerAgentCredentialFlags(clause, &cmd.ttl, &cmd.confirm)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.credentialFile, Name: "credential-file", Required: false, Description: "The file that contains the credential to add."},
+	})
+}
+
+// Run adds the credential to the agent.
+func (cmd *AgentAddCommand) Run() error {
+	agent := newAgentClient(agentSocketPath(cmd.credentialStore.ConfigDir().Path()))
+	_, err := agent.status()
+	if err != nil {
+		return err
+	}
+
+	reader := cmd.credentialStore.CredentialReader()
+	source := cmd.credentialStore.CredentialSource()
+	if cmd.credentialFile.Value != "" {
+		raw, err := os.ReadFile(cmd.credentialFile.Value)
+		if err != nil {
+			return ErrCannotReadFile(cmd.credentialFile.Value, err)
+		}
+		reader = credentials.FromString(string(raw))
+		source = cmd.credentialFile.Value
+	}
+
+	req, err := newAgentAddRequest(reader, cmd.credentialStore.PassphraseReader(), source, cmd.ttl, cmd.confirm)
+	if err != nil {
+		return err
+	}
+
+	err = agent.add(req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Added credential %s to the agent.\n", req.Fingerprint[:16])
+	return nil
+}
+
+// registerAgentCredentialFlags registers the flags that configure how a credential is kept in the agent.
+func registerAgentCredentialFlags(clause *cli.CommandClause, ttl *time.Duration, confirm *bool) {
+	clause.Flags().DurationVar(ttl, "ttl", 0, "Remove the credential from the agent after this duration, e.g. 1h. By default, it is kept until the agent stops.")
+	clause.Flags().BoolVar(confirm, "confirm", false, "Let the agent ask for confirmation every time a command or an application through the API of the agent uses the credential. The agent must be able to ask for confirmation, see `secrethub agent start --help`.")
+}
+
+// newAgentAddRequest unlocks the credential with the passphrase reader and returns the request to add it to the agent.
+func newAgentAddRequest(reader credentials.Reader, passphraseReader credentials.Reader, source string, ttl time.Duration, confirm bool) (agentAddRequest, error) {
+	if ttl < 0 {
+		return agentAddRequest{}, ErrInvalidAgentTTL(ttl)
+	}
+
+	raw, err := reader.Read()
+	if err != nil {
+		return agentAddRequest{}, err
+	}
+
+	key, err := credentials.ImportKey(credentials.FromString(string(raw)), passphraseReader)
+	if err != nil {
+		return agentAddRequest{}, err
+	}
+
+	credential, err := key.Export()
+	if err != nil {
+		return agentAddRequest{}, err
+	}
+
+	_, fingerprint, err := key.Verifier().Export()
+	if err != nil {
+		return agentAddRequest{}, err
+	}
+
+	return agentAddRequest{
+		ID:          agentCredentialID(raw),
+		Fingerprint: fingerprint,
+		Source:      source,
+		Credential:  string(credential),
+		// The TTL is rounded up to whole seconds, so that a short TTL does not keep the credential forever.
+		TTL:     int64((ttl + time.Second - 1) / time.Second),
+		Confirm: confirm,
+	}, nil
+}
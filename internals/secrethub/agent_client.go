@@ -0,0 +1,186 @@
+package secrethub
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// agentDirName is the name of the directory in the configuration directory that contains the socket of the agent.
+	// Only the user that started the agent can access it, so that other users cannot connect to the socket
+	// in the time between creating it and restricting its permissions.
+	agentDirName = "agent"
+	// agentSocketName is the name of the socket of the agent in the agent directory.
+	agentSocketName = "agent.sock"
+)
+
+// agentSocketPath returns the path of the socket of the agent in the given configuration directory.
+func agentSocketPath(configDir string) string {
+	return filepath.Join(configDir, agentDirName, agentSocketName)
+}
+
+// agentCredentialID returns the ID of a credential as it is stored, which may be locked with a passphrase.
+// The CLI uses it to find an unlocked credential in the agent without unlocking it first.
+func agentCredentialID(raw []byte) string {
+	sum := sha256.Sum256(bytes.TrimSpace(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+const (
+	// agentRequestTimeout is the maximum duration of a request to the agent.
+	// Requests are short-lived, so a hanging agent does not block the CLI for long.
+	agentRequestTimeout = 10 * time.Second
+	// agentConfirmTimeout is the maximum duration of a request that may wait for the user to confirm the use of a credential.
+	agentConfirmTimeout = 2 * time.Minute
+)
+
+// agentClient sends requests to a running agent over its socket.
+type agentClient struct {
+	httpClient *http.Client
+}
+
+// newAgentClient creates a client for the agent listening on the given socket.
+func newAgentClient(socketPath string) *agentClient {
+	return &agentClient{
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+// status returns the status of the agent. When no agent is running, ErrAgentNotRunning is returned.
+func (c *agentClient) status() (*agentStatus, error) {
+	var status agentStatus
+	err := c.do(http.MethodGet, "/v1/status", nil, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// credentials returns the credentials that are unlocked in the agent.
+func (c *agentClient) credentials() ([]agentCredentialInfo, error) {
+	var resp agentCredentialsResponse
+	err := c.do(http.MethodGet, "/v1/credentials", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Credentials, nil
+}
+
+// add unlocks a credential in the agent.
+func (c *agentClient) add(req agentAddRequest) error {
+	return c.do(http.MethodPost, "/v1/credentials/add", req, nil)
+}
+
+// remove removes the credentials that match the request from the agent and returns their fingerprints.
+func (c *agentClient) remove(req agentRemoveRequest) ([]string, error) {
+	var resp agentRemoveResponse
+	err := c.do(http.MethodPost, "/v1/credentials/remove", req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Removed, nil
+}
+
+// use starts a session to use the unlocked credential with the given ID.
+// When the credential was added with confirmation, the agent asks the user to confirm it first.
+func (c *agentClient) use(req agentUseRequest) (*agentUseResponse, error) {
+	var resp agentUseResponse
+	err := c.doWithTimeout(agentConfirmTimeout, http.MethodPost, "/v1/credentials/use", req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// sign returns the headers that authenticate the described request to the API with the credential of the session.
+func (c *agentClient) sign(req agentSignRequest) (*agentSignResponse, error) {
+	var resp agentSignResponse
+	err := c.do(http.MethodPost, "/v1/credentials/sign", req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// read reads a secret with the credential of the session or the selected credential.
+// When no session is given, the read may have to be confirmed first.
+func (c *agentClient) read(req agentReadRequest) (*agentReadResponse, error) {
+	var resp agentReadResponse
+	err := c.doWithTimeout(agentConfirmTimeout, http.MethodPost, "/v1/read", req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// stop stops the agent.
+func (c *agentClient) stop() error {
+	return c.do(http.MethodPost, "/v1/stop", nil, nil)
+}
+
+// do sends a request to the agent with the body encoded as JSON and decodes the response into out.
+func (c *agentClient) do(method, path string, body interface{}, out interface{}) error {
+	return c.doWithTimeout(agentRequestTimeout, method, path, body, out)
+}
+
+// doWithTimeout sends a request like do, but fails when the agent does not respond within the given timeout.
+func (c *agentClient) doWithTimeout(timeout time.Duration, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	// The host is ignored, because the socket is always dialed.
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, "http://agent"+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ErrAgentNotRunning
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp jsonErrorResponse
+		err = json.NewDecoder(resp.Body).Decode(&errResp)
+		if err != nil {
+			return ErrAgentRequestFailed(resp.Status)
+		}
+		for _, agentErr := range agentErrors {
+			if errResp.Code == agentErr.Code {
+				return agentErr
+			}
+		}
+		return ErrAgentRequestFailed(errResp.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
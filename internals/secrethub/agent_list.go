@@ -0,0 +1,82 @@
+package secrethub
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// AgentListCommand lists the credentials that are unlocked in the agent.
+type AgentListCommand struct {
+	io              ui.IO
+	credentialStore CredentialConfig
+	useTimestamps   bool
+}
+
+// NewAgentListCommand creates a new AgentListCommand.
+func NewAgentListCommand(io ui.IO, credentialStore CredentialConfig) *AgentListCommand {
+	return &AgentListCommand{
+		io:              io,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AgentListCommand) Register(r cli.Registerer) {
+	clause := r.Command("ls", "List the credentials that are unlocked in the agent.")
+	clause.Alias("list")
+
+	registerTimestampFlag(clause, &cmd.useTimestamps)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run lists the credentials that are unlocked in the agent.
+func (cmd *AgentListCommand) Run() error {
+	unlocked, err := newAgentClient(agentSocketPath(cmd.credentialStore.ConfigDir().Path())).credentials()
+	if err != nil {
+		return err
+	}
+
+	timeFormatter := NewTimeFormatter(cmd.useTimestamps)
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w,
+		"FINGERPRINT\t"+
+			"SOURCE\t"+
+			"ADDED\t"+
+			"EXPIRES\t"+
+			"CONFIRM")
+
+	for _, credential := range unlocked {
+		expires := "never"
+		if credential.ExpiresAt != nil {
+			expires = timeFormatter.Format(credential.ExpiresAt.Local())
+		}
+
+		confirm := "no"
+		if credential.Confirm {
+			confirm = "yes"
+		}
+
+		row := []string{
+			credential.Fingerprint[:16],
+			credential.Source,
+			timeFormatter.Format(credential.AddedAt.Local()),
+			expires,
+			confirm,
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
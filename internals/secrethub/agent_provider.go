@@ -0,0 +1,220 @@
+package secrethub
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/auth"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+	httpclient "github.com/secrethub/secrethub-go/pkg/secrethub/internals/http"
+)
+
+// agentProvider provides a credential that is unlocked in the agent. The credential itself never
+// leaves the agent: requests to the API are signed and secrets are read by the agent within a session.
+// When a session expires, a new one is started, which the user may have to confirm.
+//
+// Nothing that is decrypted with the credential leaves the agent other than the secrets that are read.
+// Everything else is decrypted with the credential that is unlocked locally, which is only done when needed.
+type agentProvider struct {
+	agent   *agentClient
+	id      string
+	session string
+	local   credentials.Provider
+
+	mutex sync.Mutex
+
+	// decrypter is the decrypter of the local credential, which is unlocked when it is first used.
+	decrypter      credentials.Decrypter
+	decrypterMutex sync.Mutex
+}
+
+// newAgentProvider starts a session with the agent to use the credential with the given ID.
+// The local provider is used to decrypt anything other than secrets.
+func newAgentProvider(agent *agentClient, id string, local credentials.Provider) (*agentProvider, error) {
+	p := &agentProvider{
+		agent: agent,
+		id:    id,
+		local: local,
+	}
+	_, err := p.renew("")
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Provide implements the credentials.Provider interface.
+func (p *agentProvider) Provide(_ *httpclient.Client) (auth.Authenticator, credentials.Decrypter, error) {
+	return p, p, nil
+}
+
+// Authenticate implements the auth.Authenticator interface by letting the agent sign the request.
+func (p *agentProvider) Authenticate(r *http.Request) error {
+	var body []byte
+	if r.Body != nil && r.ContentLength != 0 {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		// The body is restored, so that it can still be sent.
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	var resp *agentSignResponse
+	err := p.withSession(func(session string) error {
+		var err error
+		resp, err = p.agent.sign(agentSignRequest{
+			Session: session,
+			Method:  r.Method,
+			URL:     r.URL.String(),
+			Body:    body,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set("Date", resp.Date)
+	r.Header.Set("Authorization", resp.Authorization)
+	return nil
+}
+
+// Unwrap implements the credentials.Decrypter interface by decrypting the ciphertext with the local credential.
+// The agent does not decrypt arbitrary ciphertexts, as the account key would then leave the agent.
+func (p *agentProvider) Unwrap(ciphertext *api.EncryptedData) ([]byte, error) {
+	p.decrypterMutex.Lock()
+	defer p.decrypterMutex.Unlock()
+
+	if p.decrypter == nil {
+		_, decrypter, err := p.local.Provide(nil)
+		if err != nil {
+			return nil, err
+		}
+		p.decrypter = decrypter
+	}
+	return p.decrypter.Unwrap(ciphertext)
+}
+
+// read reads a secret through the agent with the credential of the session.
+func (p *agentProvider) read(path string) (*api.SecretVersion, error) {
+	var resp *agentReadResponse
+	err := p.withSession(func(session string) error {
+		var err error
+		resp, err = p.agent.read(agentReadRequest{
+			Session: session,
+			Path:    path,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &api.SecretVersion{
+		Version:   resp.Version,
+		Data:      []byte(resp.Data),
+		CreatedAt: resp.CreatedAt,
+		Status:    resp.Status,
+	}, nil
+}
+
+// withSession calls f with the current session. When the session has expired, a new session is started and f is called again.
+func (p *agentProvider) withSession(f func(session string) error) error {
+	p.mutex.Lock()
+	session := p.session
+	p.mutex.Unlock()
+
+	err := f(session)
+	if err != ErrAgentSessionNotFound {
+		return err
+	}
+
+	session, err = p.renew(session)
+	if err != nil {
+		return err
+	}
+	return f(session)
+}
+
+// renew starts a new session, unless another request already replaced the expired session.
+func (p *agentProvider) renew(expired string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.session != expired {
+		return p.session, nil
+	}
+
+	resp, err := p.agent.use(agentUseRequest{ID: p.id, PID: os.Getpid()})
+	if err != nil {
+		return "", err
+	}
+	p.session = resp.Session
+	return p.session, nil
+}
+
+// agentReadingClient is a client that reads secrets through the agent, so that the credential
+// does not have to be unlocked locally to decrypt them.
+type agentReadingClient struct {
+	secrethub.ClientInterface
+	provider *agentProvider
+}
+
+// newAgentReadingClient wraps the client to read secrets with the credential of the provider in the agent.
+func newAgentReadingClient(client secrethub.ClientInterface, provider *agentProvider) *agentReadingClient {
+	return &agentReadingClient{
+		ClientInterface: client,
+		provider:        provider,
+	}
+}
+
+// Secrets returns a SecretService that reads secrets through the agent.
+func (c *agentReadingClient) Secrets() secrethub.SecretService {
+	return &agentReadingSecretService{
+		SecretService: c.ClientInterface.Secrets(),
+		provider:      c.provider,
+	}
+}
+
+type agentReadingSecretService struct {
+	secrethub.SecretService
+	provider *agentProvider
+}
+
+// Read reads a secret through the agent.
+func (s *agentReadingSecretService) Read(path string) (*api.SecretVersion, error) {
+	return s.provider.read(path)
+}
+
+// ReadString reads a secret through the agent and returns its value as a string.
+func (s *agentReadingSecretService) ReadString(path string) (string, error) {
+	version, err := s.provider.read(path)
+	if err != nil {
+		return "", err
+	}
+	return string(version.Data), nil
+}
+
+// Versions returns a SecretVersionService that reads secret versions through the agent.
+func (s *agentReadingSecretService) Versions() secrethub.SecretVersionService {
+	return &agentReadingSecretVersionService{
+		SecretVersionService: s.SecretService.Versions(),
+		provider:             s.provider,
+	}
+}
+
+type agentReadingSecretVersionService struct {
+	secrethub.SecretVersionService
+	provider *agentProvider
+}
+
+// GetWithData gets a secret version with its value through the agent.
+func (s *agentReadingSecretVersionService) GetWithData(path string) (*api.SecretVersion, error) {
+	return s.provider.read(path)
+}
@@ -0,0 +1,59 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// Errors
+var (
+	ErrAgentRemoveArguments = errMain.Code("agent_remove_arguments").Error("provide either the fingerprint of a credential or --all")
+)
+
+// AgentRemoveCommand removes credentials from the agent.
+type AgentRemoveCommand struct {
+	fingerprint     cli.StringValue
+	all             bool
+	io              ui.IO
+	credentialStore CredentialConfig
+}
+
+// NewAgentRemoveCommand creates a new AgentRemoveCommand.
+func NewAgentRemoveCommand(io ui.IO, credentialStore CredentialConfig) *AgentRemoveCommand {
+	return &AgentRemoveCommand{
+		io:              io,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AgentRemoveCommand) Register(r cli.Registerer) {
+	clause := r.Command("rm", "Remove a credential from the agent, so that commands unlock it themselves again.")
+	clause.Alias("remove")
+	clause.Flags().BoolVar(&cmd.all, "all", false, "Remove all credentials from the agent.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.fingerprint, Name: "fingerprint", Required: false, Description: "(The start of) the fingerprint of the credential to remove, as shown by `secrethub agent ls`."},
+	})
+}
+
+// Run removes the credentials from the agent.
+func (cmd *AgentRemoveCommand) Run() error {
+	if cmd.fingerprint.Value == "" && !cmd.all || cmd.fingerprint.Value != "" && cmd.all {
+		return ErrAgentRemoveArguments
+	}
+
+	removed, err := newAgentClient(agentSocketPath(cmd.credentialStore.ConfigDir().Path())).remove(agentRemoveRequest{
+		Fingerprint: cmd.fingerprint.Value,
+		All:         cmd.all,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Removed %d %s from the agent.\n", len(removed), pluralize("credential", "credentials", len(removed)))
+	return nil
+}
@@ -0,0 +1,661 @@
+package secrethub
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+// agentSessionIdleTimeout is the duration after which a session that is not used anymore expires.
+// A credential that was added with confirmation has to be confirmed again for a new session.
+const agentSessionIdleTimeout = 5 * time.Minute
+
+// agentStatus is the response of the status endpoint of the agent.
+type agentStatus struct {
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+	// ExpiresAt is the time at which the agent stops, or nil when it keeps running until stopped.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// Credentials is the number of credentials that are unlocked in the agent.
+	Credentials int `json:"credentials"`
+	// CanConfirm is set when the agent can ask the user to confirm the use of a credential.
+	CanConfirm bool `json:"can_confirm"`
+}
+
+// agentCredentialInfo describes a credential that is unlocked in the agent, without the credential itself.
+type agentCredentialInfo struct {
+	Fingerprint string    `json:"fingerprint"`
+	Source      string    `json:"source"`
+	AddedAt     time.Time `json:"added_at"`
+	// ExpiresAt is the time at which the credential is removed, or nil when it is kept until the agent stops.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Confirm   bool       `json:"confirm"`
+}
+
+// agentCredentialsResponse is the response of the credentials endpoint of the agent.
+type agentCredentialsResponse struct {
+	Credentials []agentCredentialInfo `json:"credentials"`
+}
+
+// agentAddRequest is the request body of the add endpoint of the agent.
+type agentAddRequest struct {
+	// ID identifies the credential as it is stored, so the CLI can find it without unlocking the credential.
+	ID          string `json:"id"`
+	Fingerprint string `json:"fingerprint"`
+	Source      string `json:"source"`
+	Credential  string `json:"credential"`
+	// TTL is the number of seconds after which the credential is removed, or 0 to keep it until the agent stops.
+	TTL     int64 `json:"ttl,omitempty"`
+	Confirm bool  `json:"confirm,omitempty"`
+}
+
+// agentRemoveRequest is the request body of the remove endpoint of the agent.
+type agentRemoveRequest struct {
+	Fingerprint string `json:"fingerprint,omitempty"`
+	All         bool   `json:"all,omitempty"`
+}
+
+// agentRemoveResponse is the response of the remove endpoint of the agent.
+type agentRemoveResponse struct {
+	Removed []string `json:"removed"`
+}
+
+// agentUseRequest is the request body of the use endpoint of the agent.
+type agentUseRequest struct {
+	ID string `json:"id"`
+	// PID is the process ID of the command that uses the credential. It is only shown when asking for confirmation.
+	PID int `json:"pid,omitempty"`
+}
+
+// agentUseResponse is the response of the use endpoint of the agent.
+type agentUseResponse struct {
+	// Session is passed to the sign and read endpoints to use the credential.
+	Session     string `json:"session"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// agentSignRequest is the request body of the sign endpoint of the agent.
+// The agent signs the request to the API that is described by it, never arbitrary data.
+// Requests to other addresses than the API are not signed.
+type agentSignRequest struct {
+	Session string `json:"session"`
+	Method  string `json:"method"`
+	URL     string `json:"url"`
+	Body    []byte `json:"body,omitempty"`
+}
+
+// agentSignResponse is the response of the sign endpoint of the agent.
+// The headers have to be set on the request to the API.
+type agentSignResponse struct {
+	Date          string `json:"date"`
+	Authorization string `json:"authorization"`
+}
+
+// agentReadRequest is the request body of the read endpoint of the agent.
+type agentReadRequest struct {
+	Path string `json:"path"`
+	// Session is the session of a secrethub command. When it is set, the credential of the session is used.
+	Session string `json:"session,omitempty"`
+	// Credential is (a prefix of) the fingerprint of the credential to use.
+	// It can be omitted when only one credential is unlocked.
+	Credential string `json:"credential,omitempty"`
+}
+
+// agentReadResponse is the response of the read endpoint of the agent.
+type agentReadResponse struct {
+	Path      string    `json:"path"`
+	Version   int       `json:"version"`
+	Data      string    `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+	Status    string    `json:"status"`
+}
+
+// agentResolveRequest is the request body of the resolve endpoint of the agent.
+type agentResolveRequest struct {
+	Paths      []string `json:"paths"`
+	Session    string   `json:"session,omitempty"`
+	Credential string   `json:"credential,omitempty"`
+}
+
+// agentResolveResponse is the response of the resolve endpoint of the agent.
+type agentResolveResponse struct {
+	Secrets map[string]string `json:"secrets"`
+}
+
+// agentCredential is a credential that is unlocked in the agent.
+// The key never leaves the agent: commands use it through a session.
+type agentCredential struct {
+	id   string
+	info agentCredentialInfo
+	key  credentials.Key
+	// client is created when the credential is first used to read secrets. It is guarded by the mutex of the server.
+	client secrethub.ClientInterface
+}
+
+// agentSession gives a command the use of a credential until it expires.
+type agentSession struct {
+	credential *agentCredential
+	expiresAt  time.Time
+}
+
+// agentConfirmFunc asks the user whether the use of a credential is allowed.
+type agentConfirmFunc func(question string) (bool, error)
+
+// agentServer serves the API of the agent. All endpoints accept and return JSON:
+//
+//	GET  /v1/status              the process ID, start time and expiry time of the agent
+//	GET  /v1/credentials         the credentials that are unlocked, without the credentials themselves
+//	POST /v1/credentials/add     adds an unlocked credential
+//	POST /v1/credentials/remove  removes a credential: {"fingerprint": "..."} or {"all": true}
+//	POST /v1/credentials/use     starts a session to use a credential: {"id": "..."}
+//	POST /v1/credentials/sign    signs a request to the API with the credential of a session
+//	POST /v1/read                the latest or given version of a secret: {"path": "..."}
+//	POST /v1/resolve             the values of multiple secrets: {"paths": ["...", ...]}
+//	POST /v1/stop                stops the agent
+//
+// The API is only served on a Unix domain socket that is only accessible by the user that started the agent.
+// The unlocked credentials never leave the agent, nor does anything that is decrypted with them other than
+// the secrets that are read through it. Commands sign their requests to the API and read secrets through the agent instead.
+// When a credential was added with confirmation, the agent asks the user to confirm every new session and every read
+// without a session itself, so that a process cannot skip the confirmation.
+type agentServer struct {
+	credentials []*agentCredential
+	sessions    map[string]*agentSession
+	newClient   func(credentials.Provider) (secrethub.ClientInterface, error)
+	apiRemote   string
+	confirm     agentConfirmFunc
+	status      agentStatus
+	now         func() time.Time
+	stop        func()
+
+	// mutex guards the credentials and sessions. It is never held while asking for confirmation or sending requests,
+	// so that the agent keeps responding, e.g. to remove a credential, while a question is open.
+	mutex sync.Mutex
+	// confirmMutex makes sure that only one question is asked at a time.
+	confirmMutex sync.Mutex
+}
+
+// newAgentServer creates a server that reads secrets with clients created by newClient and stops the agent with the stop function.
+// Only requests to the API at apiRemote are signed.
+// The use of credentials that were added with confirmation is confirmed with the confirm function. When it is nil, such credentials cannot be used.
+func newAgentServer(newClient func(credentials.Provider) (secrethub.ClientInterface, error), apiRemote string, confirm agentConfirmFunc, startedAt time.Time, expiresAt *time.Time, stop func()) *agentServer {
+	return &agentServer{
+		newClient: newClient,
+		apiRemote: apiRemote,
+		confirm:   confirm,
+		sessions:  make(map[string]*agentSession),
+		status: agentStatus{
+			PID:        os.Getpid(),
+			StartedAt:  startedAt,
+			ExpiresAt:  expiresAt,
+			CanConfirm: confirm != nil,
+		},
+		now:  time.Now,
+		stop: stop,
+	}
+}
+
+// handler returns the handler for all endpoints of the agent.
+func (s *agentServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/status", jsonEndpoint(http.MethodGet, s.handleStatus))
+	mux.HandleFunc("/v1/credentials", jsonEndpoint(http.MethodGet, s.handleCredentials))
+	mux.HandleFunc("/v1/credentials/add", jsonEndpoint(http.MethodPost, s.handleAdd))
+	mux.HandleFunc("/v1/credentials/remove", jsonEndpoint(http.MethodPost, s.handleRemove))
+	mux.HandleFunc("/v1/credentials/use", jsonEndpoint(http.MethodPost, s.handleUse))
+	mux.HandleFunc("/v1/credentials/sign", jsonEndpoint(http.MethodPost, s.handleSign))
+	mux.HandleFunc("/v1/read", jsonEndpoint(http.MethodPost, s.handleRead))
+	mux.HandleFunc("/v1/resolve", jsonEndpoint(http.MethodPost, s.handleResolve))
+	mux.HandleFunc("/v1/stop", jsonEndpoint(http.MethodPost, s.handleStop))
+	return mux
+}
+
+// add unlocks a credential in the agent. A credential that is already unlocked is replaced.
+func (s *agentServer) add(req agentAddRequest) error {
+	if req.ID == "" || req.Fingerprint == "" || req.Credential == "" {
+		return ErrInvalidAgentRequest("id, fingerprint and credential are required")
+	}
+	if req.TTL < 0 {
+		return ErrInvalidAgentRequest("ttl must not be negative")
+	}
+	if req.Confirm && s.confirm == nil {
+		return ErrAgentCannotConfirm
+	}
+
+	key, err := credentials.ImportKey(credentials.FromString(req.Credential), nil)
+	if err != nil {
+		return ErrInvalidAgentRequest(err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.removeExpired()
+	s.remove(func(c *agentCredential) bool { return c.id == req.ID })
+
+	added := &agentCredential{
+		id: req.ID,
+		info: agentCredentialInfo{
+			Fingerprint: req.Fingerprint,
+			Source:      req.Source,
+			AddedAt:     s.now(),
+			Confirm:     req.Confirm,
+		},
+		key: key,
+	}
+	if req.TTL > 0 {
+		ttl := time.Duration(req.TTL) * time.Second
+		expiresAt := added.info.AddedAt.Add(ttl)
+		added.info.ExpiresAt = &expiresAt
+
+		// The credential is removed from memory when it expires, not only when it is next requested.
+		time.AfterFunc(ttl, func() {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+			s.removeExpired()
+		})
+	}
+	s.credentials = append(s.credentials, added)
+	return nil
+}
+
+// remove removes the credentials that match and their sessions and returns their fingerprints.
+// The caller must hold the mutex.
+func (s *agentServer) remove(match func(*agentCredential) bool) []string {
+	removed := []string{}
+	kept := s.credentials[:0]
+	for _, c := range s.credentials {
+		if match(c) {
+			removed = append(removed, c.info.Fingerprint)
+			for id, session := range s.sessions {
+				if session.credential == c {
+					delete(s.sessions, id)
+				}
+			}
+			continue
+		}
+		kept = append(kept, c)
+	}
+	s.credentials = kept
+	return removed
+}
+
+// removeExpired removes the credentials of which the TTL has passed.
+// The caller must hold the mutex.
+func (s *agentServer) removeExpired() {
+	now := s.now()
+	s.remove(func(c *agentCredential) bool {
+		return c.info.ExpiresAt != nil && !now.Before(*c.info.ExpiresAt)
+	})
+}
+
+// find returns the credential with a fingerprint that starts with the given prefix.
+// When the prefix is empty, the only unlocked credential is returned.
+// The caller must hold the mutex.
+func (s *agentServer) find(fingerprint string) (*agentCredential, error) {
+	s.removeExpired()
+
+	var found []*agentCredential
+	for _, c := range s.credentials {
+		if strings.HasPrefix(c.info.Fingerprint, fingerprint) {
+			found = append(found, c)
+		}
+	}
+
+	switch {
+	case len(found) == 1:
+		return found[0], nil
+	case len(found) == 0 && fingerprint == "":
+		return nil, ErrAgentNoCredentials
+	case len(found) == 0:
+		return nil, ErrAgentCredentialNotFound
+	default:
+		return nil, ErrAgentCredentialAmbiguous
+	}
+}
+
+// clientFor returns the client that reads secrets with the credential of the given session or,
+// when no session is given, with the credential with the given fingerprint.
+// When the credential was added with confirmation, a read without a session has to be confirmed by the user.
+func (s *agentServer) clientFor(sessionID string, fingerprint string, what string) (secrethub.ClientInterface, error) {
+	var c *agentCredential
+	var err error
+	s.mutex.Lock()
+	if sessionID != "" {
+		var session *agentSession
+		session, err = s.session(sessionID)
+		if err == nil {
+			c = session.credential
+		}
+	} else {
+		c, err = s.find(fingerprint)
+	}
+	s.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// The use was already confirmed when the session was started.
+	if sessionID == "" {
+		err = s.confirmUse(c, what)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.mutex.Lock()
+	client := c.client
+	s.mutex.Unlock()
+	if client != nil {
+		return client, nil
+	}
+
+	client, err = s.newClient(c.key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Another request may have created a client in the meantime, in which case that one is kept.
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if c.client == nil {
+		c.client = client
+	}
+	return c.client, nil
+}
+
+// confirmUse asks the user to confirm the use of the credential when it was added with confirmation.
+// The caller must not hold the mutex, so that the agent keeps responding while the question is open.
+func (s *agentServer) confirmUse(c *agentCredential, what string) error {
+	if !c.info.Confirm {
+		return nil
+	}
+	if s.confirm == nil {
+		return ErrAgentCannotConfirm
+	}
+
+	fingerprint := c.info.Fingerprint
+	if len(fingerprint) > 16 {
+		fingerprint = fingerprint[:16]
+	}
+
+	s.confirmMutex.Lock()
+	defer s.confirmMutex.Unlock()
+	confirmed, err := s.confirm(fmt.Sprintf("Allow %s to use the credential %s from %s?", what, fingerprint, c.info.Source))
+	if err != nil {
+		return ErrAgentConfirmationFailed(err)
+	}
+	if !confirmed {
+		return ErrAgentConfirmationDenied
+	}
+	return nil
+}
+
+// isAPIURL returns whether the URL is an address of the API at the remote, so that the agent only signs requests to the API.
+func isAPIURL(remote string, rawURL string) bool {
+	base, err := url.Parse(remote)
+	if err != nil {
+		return false
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	if u.User != nil || !strings.EqualFold(u.Scheme, base.Scheme) || !strings.EqualFold(u.Host, base.Host) {
+		return false
+	}
+
+	// The path is cleaned, so that it cannot get outside of the path of the remote with "..".
+	basePath := strings.TrimSuffix(base.Path, "/")
+	urlPath := path.Clean("/" + u.Path)
+	return urlPath == basePath || strings.HasPrefix(urlPath, basePath+"/")
+}
+
+// session returns the session with the given ID and extends it.
+// The caller must hold the mutex.
+func (s *agentServer) session(id string) (*agentSession, error) {
+	s.removeExpired()
+
+	now := s.now()
+	for sessionID, session := range s.sessions {
+		if !now.Before(session.expiresAt) {
+			delete(s.sessions, sessionID)
+		}
+	}
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrAgentSessionNotFound
+	}
+	session.expiresAt = now.Add(agentSessionIdleTimeout)
+	return session, nil
+}
+
+// newAgentSessionID returns a random session ID that cannot be guessed.
+func newAgentSessionID() (string, error) {
+	raw := make([]byte, 32)
+	_, err := rand.Read(raw)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func (s *agentServer) handleStatus(r *http.Request) (interface{}, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.removeExpired()
+	status := s.status
+	status.Credentials = len(s.credentials)
+	return status, nil
+}
+
+func (s *agentServer) handleCredentials(r *http.Request) (interface{}, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.removeExpired()
+	resp := agentCredentialsResponse{Credentials: make([]agentCredentialInfo, len(s.credentials))}
+	for i, c := range s.credentials {
+		resp.Credentials[i] = c.info
+	}
+	return resp, nil
+}
+
+func (s *agentServer) handleAdd(r *http.Request) (interface{}, error) {
+	var req agentAddRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, ErrInvalidAgentRequest(err)
+	}
+
+	err = s.add(req)
+	if err != nil {
+		return nil, err
+	}
+	return struct{}{}, nil
+}
+
+func (s *agentServer) handleRemove(r *http.Request) (interface{}, error) {
+	var req agentRemoveRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, ErrInvalidAgentRequest(err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if req.All {
+		return agentRemoveResponse{Removed: s.remove(func(*agentCredential) bool { return true })}, nil
+	}
+	if req.Fingerprint == "" {
+		return nil, ErrInvalidAgentRequest("fingerprint or all is required")
+	}
+
+	c, err := s.find(req.Fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	return agentRemoveResponse{Removed: s.remove(func(other *agentCredential) bool { return other == c })}, nil
+}
+
+func (s *agentServer) handleUse(r *http.Request) (interface{}, error) {
+	var req agentUseRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, ErrInvalidAgentRequest(err)
+	}
+
+	s.mutex.Lock()
+	s.removeExpired()
+	var c *agentCredential
+	for _, credential := range s.credentials {
+		if credential.id == req.ID {
+			c = credential
+		}
+	}
+	s.mutex.Unlock()
+	if c == nil {
+		return nil, ErrAgentCredentialNotFound
+	}
+
+	what := "a secrethub command"
+	if req.PID != 0 {
+		what = fmt.Sprintf("a secrethub command (PID %d)", req.PID)
+	}
+	err = s.confirmUse(c, what)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newAgentSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// The credential may have been removed or have expired while the user was asked for confirmation.
+	s.removeExpired()
+	found := false
+	for _, credential := range s.credentials {
+		if credential == c {
+			found = true
+		}
+	}
+	if !found {
+		return nil, ErrAgentCredentialNotFound
+	}
+
+	s.sessions[id] = &agentSession{
+		credential: c,
+		expiresAt:  s.now().Add(agentSessionIdleTimeout),
+	}
+	return agentUseResponse{Session: id, Fingerprint: c.info.Fingerprint}, nil
+}
+
+func (s *agentServer) handleSign(r *http.Request) (interface{}, error) {
+	var req agentSignRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, ErrInvalidAgentRequest(err)
+	}
+
+	s.mutex.Lock()
+	session, err := s.session(req.Session)
+	s.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAPIURL(s.apiRemote, req.URL) {
+		return nil, ErrAgentSignURLNotAllowed(req.URL)
+	}
+
+	apiReq, err := http.NewRequest(req.Method, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, ErrInvalidAgentRequest(err)
+	}
+
+	authenticator, _, err := session.credential.key.Provide(nil)
+	if err != nil {
+		return nil, err
+	}
+	err = authenticator.Authenticate(apiReq)
+	if err != nil {
+		return nil, err
+	}
+	return agentSignResponse{
+		Date:          apiReq.Header.Get("Date"),
+		Authorization: apiReq.Header.Get("Authorization"),
+	}, nil
+}
+
+func (s *agentServer) handleRead(r *http.Request) (interface{}, error) {
+	var req agentReadRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, ErrInvalidAgentRequest(err)
+	}
+
+	client, err := s.clientFor(req.Session, req.Credential, "an application to read "+req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := client.Secrets().Versions().GetWithData(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	return agentReadResponse{
+		Path:      req.Path,
+		Version:   version.Version,
+		Data:      string(version.Data),
+		CreatedAt: version.CreatedAt,
+		Status:    version.Status,
+	}, nil
+}
+
+func (s *agentServer) handleResolve(r *http.Request) (interface{}, error) {
+	var req agentResolveRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, ErrInvalidAgentRequest(err)
+	}
+
+	client, err := s.clientFor(req.Session, req.Credential, "an application to read "+pluralize("secret", "secrets", len(req.Paths)))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := agentResolveResponse{Secrets: make(map[string]string, len(req.Paths))}
+	for _, path := range req.Paths {
+		version, err := client.Secrets().Versions().GetWithData(path)
+		if err != nil {
+			return nil, err
+		}
+		resp.Secrets[path] = string(version.Data)
+	}
+	return resp, nil
+}
+
+func (s *agentServer) handleStop(r *http.Request) (interface{}, error) {
+	// The agent is stopped after the response is written.
+	defer s.stop()
+	return struct{}{}, nil
+}
@@ -0,0 +1,287 @@
+package secrethub
+
+import (
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestAgentServer(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	socketPath := filepath.Join(dir, agentSocketName)
+	listener, err := net.Listen("unix", socketPath)
+	assert.OK(t, err)
+
+	startedAt := time.Date(2018, 1, 1, 1, 1, 1, 0, time.UTC)
+	now := startedAt
+	stopped := make(chan struct{})
+	client := fakeclient.Client{
+		SecretService: &fakeclient.SecretService{
+			VersionService: &fakeclient.SecretVersionService{
+				GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+					if path == "namespace/repo/missing" {
+						return nil, api.ErrSecretNotFound
+					}
+					return &api.SecretVersion{Version: 2, Data: []byte("value of " + path)}, nil
+				},
+			},
+		},
+	}
+	newClient := func(credentials.Provider) (secrethub.ClientInterface, error) {
+		return client, nil
+	}
+	var questions []string
+	confirmed := false
+	confirm := func(question string) (bool, error) {
+		questions = append(questions, question)
+		return confirmed, nil
+	}
+	agentServer := newAgentServer(newClient, "https://api.secrethub.io", confirm, startedAt, nil, func() { close(stopped) })
+	agentServer.now = func() time.Time { return now }
+	server := &http.Server{
+		Handler: agentServer.handler(),
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Close()
+
+	agent := newAgentClient(socketPath)
+
+	keyA, addA := newTestAgentAddRequest(t, "id-a", "a")
+	_, addB := newTestAgentAddRequest(t, "id-b", "b")
+	addB.TTL = 60
+	addB.Confirm = true
+	fingerprintA := addA.Fingerprint
+	fingerprintB := addB.Fingerprint
+
+	err = agent.add(addA)
+	assert.OK(t, err)
+	err = agent.add(addB)
+	assert.OK(t, err)
+	err = agent.add(agentAddRequest{ID: "id-c", Fingerprint: "cccc3333", Credential: "not a credential"})
+	assert.Equal(t, err != nil, true)
+
+	status, err := agent.status()
+	assert.OK(t, err)
+	assert.Equal(t, status.StartedAt, startedAt)
+	assert.Equal(t, status.Credentials, 2)
+	assert.Equal(t, status.CanConfirm, true)
+
+	expiresAt := startedAt.Add(time.Minute)
+	unlocked, err := agent.credentials()
+	assert.OK(t, err)
+	assert.Equal(t, unlocked, []agentCredentialInfo{
+		{Fingerprint: fingerprintA, Source: "a", AddedAt: startedAt},
+		{Fingerprint: fingerprintB, Source: "b", AddedAt: startedAt, ExpiresAt: &expiresAt, Confirm: true},
+	})
+
+	// Commands sign requests and read secrets through the agent, without getting the credential.
+	provider, err := newAgentProvider(agent, "id-a", credentials.UseKey(credentials.FromString(addA.Credential)))
+	assert.OK(t, err)
+	authenticator, decrypter, err := provider.Provide(nil)
+	assert.OK(t, err)
+	reader := newAgentReadingClient(fakeclient.Client{SecretService: &fakeclient.SecretService{}}, provider)
+
+	req, err := http.NewRequest(http.MethodPost, "https://api.secrethub.io/repos/namespace/repo", strings.NewReader("{}"))
+	assert.OK(t, err)
+	err = authenticator.Authenticate(req)
+	assert.OK(t, err)
+	assert.Equal(t, strings.HasPrefix(req.Header.Get("Authorization"), "SH1-PKCS1v15 "+fingerprintA+":"), true)
+	assert.Equal(t, req.Header.Get("Date") != "", true)
+
+	// Only requests to the API are signed.
+	_, err = agent.sign(agentSignRequest{Session: provider.session, Method: http.MethodGet, URL: "https://example.com/me"})
+	assert.Equal(t, err, ErrAgentRequestFailed(ErrAgentSignURLNotAllowed("https://example.com/me").Message))
+
+	version, err := reader.Secrets().Versions().GetWithData("namespace/repo/secret")
+	assert.OK(t, err)
+	assert.Equal(t, version.Version, 2)
+	assert.Equal(t, string(version.Data), "value of namespace/repo/secret")
+
+	// The account key is not decrypted by the agent, but with the credential that is unlocked locally.
+	ciphertext, err := keyA.Wrap([]byte("account key"))
+	assert.OK(t, err)
+	plaintext, err := decrypter.Unwrap(ciphertext)
+	assert.OK(t, err)
+	assert.Equal(t, string(plaintext), "account key")
+
+	_, err = agent.sign(agentSignRequest{Session: "guessed", Method: http.MethodGet, URL: "https://api.secrethub.io/me"})
+	assert.Equal(t, err, ErrAgentSessionNotFound)
+
+	// The agent asks for confirmation itself, so the confirmation cannot be skipped by the client.
+	_, err = newAgentProvider(agent, "id-b", nil)
+	assert.Equal(t, err, ErrAgentConfirmationDenied)
+	agentServer.confirmMutex.Lock()
+	assert.Equal(t, len(questions), 1)
+	assert.Equal(t, strings.HasPrefix(questions[0], "Allow a secrethub command (PID "), true)
+	confirmed = true
+	agentServer.confirmMutex.Unlock()
+
+	_, err = newAgentProvider(agent, "id-b", nil)
+	assert.OK(t, err)
+
+	_, err = newAgentProvider(agent, "id-c", nil)
+	assert.Equal(t, err, ErrAgentCredentialNotFound)
+
+	err = agent.do(http.MethodPost, "/v1/read", agentReadRequest{Path: "namespace/repo/secret"}, nil)
+	assert.Equal(t, err, ErrAgentCredentialAmbiguous)
+
+	agentServer.confirmMutex.Lock()
+	confirmed = false
+	agentServer.confirmMutex.Unlock()
+	err = agent.do(http.MethodPost, "/v1/read", agentReadRequest{Path: "namespace/repo/secret", Credential: fingerprintB}, nil)
+	assert.Equal(t, err, ErrAgentConfirmationDenied)
+	agentServer.confirmMutex.Lock()
+	assert.Equal(t, strings.HasPrefix(questions[len(questions)-1], "Allow an application to read namespace/repo/secret to use the credential "), true)
+	agentServer.confirmMutex.Unlock()
+
+	var read agentReadResponse
+	err = agent.do(http.MethodPost, "/v1/read", agentReadRequest{Path: "namespace/repo/secret", Credential: fingerprintA}, &read)
+	assert.OK(t, err)
+	assert.Equal(t, read, agentReadResponse{Path: "namespace/repo/secret", Version: 2, Data: "value of namespace/repo/secret"})
+
+	// The second credential expires, so the first one is used when none is selected.
+	agentServer.mutex.Lock()
+	now = expiresAt
+	agentServer.mutex.Unlock()
+	_, err = newAgentProvider(agent, "id-b", nil)
+	assert.Equal(t, err, ErrAgentCredentialNotFound)
+
+	// A session that is not used anymore expires, after which a new one is started.
+	agentServer.mutex.Lock()
+	now = now.Add(agentSessionIdleTimeout)
+	agentServer.mutex.Unlock()
+	expired := provider.session
+	_, err = reader.Secrets().ReadString("namespace/repo/secret")
+	assert.OK(t, err)
+	assert.Equal(t, provider.session != expired, true)
+
+	var resolved agentResolveResponse
+	err = agent.do(http.MethodPost, "/v1/resolve", agentResolveRequest{Paths: []string{"namespace/repo/a", "namespace/repo/b"}}, &resolved)
+	assert.OK(t, err)
+	assert.Equal(t, resolved.Secrets, map[string]string{
+		"namespace/repo/a": "value of namespace/repo/a",
+		"namespace/repo/b": "value of namespace/repo/b",
+	})
+
+	err = agent.do(http.MethodPost, "/v1/read", agentReadRequest{Path: "namespace/repo/missing"}, nil)
+	assert.Equal(t, err, api.ErrSecretNotFound)
+
+	err = agent.do(http.MethodGet, "/v1/read", nil, nil)
+	assert.Equal(t, err, ErrAgentRequestFailed("GET is not allowed, use POST"))
+
+	removed, err := agent.remove(agentRemoveRequest{Fingerprint: fingerprintA[:8]})
+	assert.OK(t, err)
+	assert.Equal(t, removed, []string{fingerprintA})
+
+	// The sessions of a removed credential cannot be used anymore.
+	_, err = reader.Secrets().Read("namespace/repo/secret")
+	assert.Equal(t, err, ErrAgentCredentialNotFound)
+
+	err = agent.do(http.MethodPost, "/v1/read", agentReadRequest{Path: "namespace/repo/secret"}, nil)
+	assert.Equal(t, err, ErrAgentNoCredentials)
+
+	err = agent.stop()
+	assert.OK(t, err)
+	<-stopped
+}
+
+func TestAgentServer_CannotConfirm(t *testing.T) {
+	agentServer := newAgentServer(nil, "https://api.secrethub.io", nil, time.Now(), nil, func() {})
+
+	_, req := newTestAgentAddRequest(t, "id", "source")
+	req.Confirm = true
+
+	err := agentServer.add(req)
+	assert.Equal(t, err, ErrAgentCannotConfirm)
+}
+
+func TestIsAPIURL(t *testing.T) {
+	cases := map[string]struct {
+		remote   string
+		url      string
+		expected bool
+	}{
+		"api": {
+			remote:   "https://api.secrethub.io",
+			url:      "https://api.secrethub.io/v1/me/user",
+			expected: true,
+		},
+		"other host": {
+			remote: "https://api.secrethub.io",
+			url:    "https://example.com/v1/me/user",
+		},
+		"host with suffix": {
+			remote: "https://api.secrethub.io",
+			url:    "https://api.secrethub.io.example.com/v1/me/user",
+		},
+		"other scheme": {
+			remote: "https://api.secrethub.io",
+			url:    "http://api.secrethub.io/v1/me/user",
+		},
+		"user info": {
+			remote: "https://api.secrethub.io",
+			url:    "https://user@api.secrethub.io/v1/me/user",
+		},
+		"under path": {
+			remote:   "https://example.com/secrethub/",
+			url:      "https://example.com/secrethub/v1/me/user",
+			expected: true,
+		},
+		"other path": {
+			remote: "https://example.com/secrethub",
+			url:    "https://example.com/secrethub-other/v1/me/user",
+		},
+		"path outside remote": {
+			remote: "https://example.com/secrethub",
+			url:    "https://example.com/secrethub/../other",
+		},
+		"invalid": {
+			remote: "https://api.secrethub.io",
+			url:    "://",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, isAPIURL(tc.remote, tc.url), tc.expected)
+		})
+	}
+}
+
+// newTestAgentAddRequest generates a credential and returns it with the request to add it to the agent.
+func newTestAgentAddRequest(t *testing.T, id string, source string) (*credentials.RSACredential, agentAddRequest) {
+	key, err := credentials.GenerateRSACredential(1024)
+	assert.OK(t, err)
+	raw, err := credentials.EncodeCredential(key)
+	assert.OK(t, err)
+	_, fingerprint, err := key.Export()
+	assert.OK(t, err)
+
+	return key, agentAddRequest{
+		ID:          id,
+		Fingerprint: fingerprint,
+		Source:      source,
+		Credential:  string(raw),
+	}
+}
+
+func TestAgentClient_NotRunning(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	_, err := newAgentClient(filepath.Join(dir, agentSocketName)).status()
+	assert.Equal(t, err, ErrAgentNotRunning)
+}
@@ -0,0 +1,239 @@
+package secrethub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/cloneproc"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+// AgentStartCommand starts an agent and adds the configured credential to it.
+type AgentStartCommand struct {
+	foreground               bool
+	empty                    bool
+	lifetime                 time.Duration
+	ttl                      time.Duration
+	confirm                  bool
+	confirmProgram           string
+	io                       ui.IO
+	credentialStore          CredentialConfig
+	newClientWithCredentials func(credentials.Provider) (secrethub.ClientInterface, error)
+	apiRemote                func() string
+	spawn                    func(args ...string) error
+	now                      func() time.Time
+}
+
+// NewAgentStartCommand creates a new AgentStartCommand.
+func NewAgentStartCommand(io ui.IO, newClientWithCredentials func(credentials.Provider) (secrethub.ClientInterface, error), apiRemote func() string, credentialStore CredentialConfig) *AgentStartCommand {
+	return &AgentStartCommand{
+		io:                       io,
+		credentialStore:          credentialStore,
+		newClientWithCredentials: newClientWithCredentials,
+		apiRemote:                apiRemote,
+		spawn:                    cloneproc.Spawn,
+		now:                      time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AgentStartCommand) Register(r cli.Registerer) {
+	clause := r.Command("start", "Start the agent in the background and add your credential to it.")
+	clause.HelpLong("You are asked for the passphrase of your credential once. " +
+		"Then the agent is started in the background with the unlocked credential and keeps running until it is stopped with `secrethub agent stop` or its lifetime has passed. " +
+		"More credentials can be added with `secrethub agent add`.\n\n" +
+		"With --foreground, the agent runs in the current process instead, which is useful when it is managed by a service manager.\n\n" +
+		"To use credentials that are added with --confirm, the agent has to be able to ask for confirmation itself. " +
+		"It runs the program set with --confirm-program, or $SSH_ASKPASS, with the question as argument and takes exit status 0 as confirmation. " +
+		"Without such a program, an agent that runs in the foreground in a terminal asks on that terminal.")
+	clause.Flags().BoolVar(&cmd.foreground, "foreground", false, "Run the agent in the current process instead of in the background. Stop it with Ctrl+C.")
+	clause.Flags().BoolVar(&cmd.empty, "empty", false, "Start the agent without adding a credential to it.")
+	clause.Flags().DurationVar(&cmd.lifetime, "lifetime", 0, "Stop the agent after this duration, e.g. 8h. By default, it keeps running until it is stopped.")
+	clause.Flags().StringVar(&cmd.confirmProgram, "confirm-program", os.Getenv("SSH_ASKPASS"), "The program that asks the user to confirm the use of a credential that was added with --confirm, like ssh-askpass. It is run with the question as argument and must exit with status 0 to confirm. Defaults to $SSH_ASKPASS.")
+	registerAgentCredentialFlags(clause, &cmd.ttl, &cmd.confirm)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run starts the agent.
+func (cmd *AgentStartCommand) Run() error {
+	if cmd.lifetime < 0 {
+		return ErrInvalidAgentLifetime(cmd.lifetime)
+	}
+	// A spawned agent has no terminal, so it can only ask for confirmation with a program.
+	if cmd.confirm && !cmd.empty && !cmd.foreground && cmd.confirmProgram == "" {
+		return ErrAgentCannotConfirm
+	}
+
+	configDir := cmd.credentialStore.ConfigDir().Path()
+	socketPath := agentSocketPath(configDir)
+	agent := newAgentClient(socketPath)
+	_, err := agent.status()
+	if err == nil {
+		return ErrAgentAlreadyRunning(socketPath)
+	}
+
+	// The credential is unlocked before the agent is started, so that a wrong passphrase does not leave an empty agent running.
+	var req *agentAddRequest
+	if !cmd.empty {
+		added, err := newAgentAddRequest(cmd.credentialStore.CredentialReader(), cmd.credentialStore.PassphraseReader(), cmd.credentialStore.CredentialSource(), cmd.ttl, cmd.confirm)
+		if err != nil {
+			return err
+		}
+		req = &added
+	}
+
+	if cmd.foreground {
+		return cmd.serve(socketPath, req)
+	}
+
+	args := []string{"agent", "start", "--foreground", "--empty", "--config-dir", configDir}
+	if cmd.lifetime > 0 {
+		args = append(args, "--lifetime", cmd.lifetime.String())
+	}
+	if cmd.confirmProgram != "" {
+		args = append(args, "--confirm-program", cmd.confirmProgram)
+	}
+	// The agent only signs requests to the API that this command uses.
+	if apiRemote := cmd.apiRemote(); apiRemote != defaultAPIRemote {
+		args = append(args, "--api-remote", apiRemote)
+	}
+	err = cmd.spawn(args...)
+	if err != nil {
+		return err
+	}
+
+	// Wait until the agent accepts requests, so that commands that are run right after this one use it.
+	for i := 0; i < 50; i++ {
+		status, err := agent.status()
+		if err != nil {
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+
+		if req != nil {
+			err = agent.add(*req)
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(cmd.io.Output(), "The agent is running with PID %d. Stop it with `secrethub agent stop`.\n", status.PID)
+		return nil
+	}
+	return ErrAgentNotStarted
+}
+
+// serve runs the agent in the current process until it is stopped.
+// When req is set, the credential is added to the agent before it accepts requests.
+func (cmd *AgentStartCommand) serve(socketPath string, req *agentAddRequest) error {
+	// The socket is created in a directory that is only accessible by the current user,
+	// as the socket itself is accessible by everyone until its permissions are restricted.
+	socketDir := filepath.Dir(socketPath)
+	err := os.MkdirAll(socketDir, 0700)
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(socketDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	// A socket that is left behind by an agent that did not stop cleanly is replaced.
+	err = os.Remove(socketPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	err = os.Chmod(socketPath, 0600)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	startedAt := cmd.now()
+	var expiresAt *time.Time
+	if cmd.lifetime > 0 {
+		t := startedAt.Add(cmd.lifetime)
+		expiresAt = &t
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, t)
+		defer cancel()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	agent := newAgentServer(cmd.newClientWithCredentials, cmd.apiRemote(), cmd.confirmFunc(), startedAt, expiresAt, cancel)
+	if req != nil {
+		err = agent.add(*req)
+		if err != nil {
+			return err
+		}
+	}
+
+	server := &http.Server{
+		Handler: agent.handler(),
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "The agent is listening on %s.\n", socketPath)
+	return serveHTTP(ctx, server, func() error {
+		return server.Serve(listener)
+	})
+}
+
+// confirmFunc returns the function with which the agent asks the user to confirm the use of a credential,
+// or nil when the agent cannot ask for confirmation.
+func (cmd *AgentStartCommand) confirmFunc() agentConfirmFunc {
+	if cmd.confirmProgram != "" {
+		return func(question string) (bool, error) {
+			return runConfirmProgram(cmd.confirmProgram, question)
+		}
+	}
+
+	_, _, err := cmd.io.Prompts()
+	if err != nil {
+		return nil
+	}
+	return func(question string) (bool, error) {
+		return ui.AskYesNo(cmd.io, question, ui.DefaultNo)
+	}
+}
+
+// runConfirmProgram runs the program with the question as argument and returns whether it exited with status 0.
+// SSH_ASKPASS_PROMPT is set, so that ssh-askpass programs show a confirmation dialog instead of asking for a passphrase.
+func runConfirmProgram(program string, question string) (bool, error) {
+	c := exec.Command(program, question)
+	c.Env = append(os.Environ(), "SSH_ASKPASS_PROMPT=confirm")
+
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,57 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// AgentStatusCommand prints whether the agent is running.
+type AgentStatusCommand struct {
+	io              ui.IO
+	credentialStore CredentialConfig
+	timeFormatter   TimeFormatter
+	useTimestamps   bool
+}
+
+// NewAgentStatusCommand creates a new AgentStatusCommand.
+func NewAgentStatusCommand(io ui.IO, credentialStore CredentialConfig) *AgentStatusCommand {
+	return &AgentStatusCommand{
+		io:              io,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AgentStatusCommand) Register(r cli.Registerer) {
+	clause := r.Command("status", "Show whether the agent is running.")
+	registerTimestampFlag(clause, &cmd.useTimestamps)
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run prints the status of the agent.
+func (cmd *AgentStatusCommand) Run() error {
+	cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps)
+
+	socketPath := agentSocketPath(cmd.credentialStore.ConfigDir().Path())
+	fmt.Fprintf(cmd.io.Output(), "Socket:\t\t%s\n", socketPath)
+
+	status, err := newAgentClient(socketPath).status()
+	if err == ErrAgentNotRunning {
+		fmt.Fprintln(cmd.io.Output(), "Status:\t\tnot running")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.io.Output(), "Status:\t\trunning")
+	fmt.Fprintf(cmd.io.Output(), "PID:\t\t%d\n", status.PID)
+	fmt.Fprintf(cmd.io.Output(), "Credentials:\t%d\n", status.Credentials)
+	fmt.Fprintf(cmd.io.Output(), "Started:\t%s\n", cmd.timeFormatter.Format(status.StartedAt.Local()))
+	if status.ExpiresAt != nil {
+		fmt.Fprintf(cmd.io.Output(), "Stops:\t\t%s\n", cmd.timeFormatter.Format(status.ExpiresAt.Local()))
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// AgentStopCommand stops the agent.
+type AgentStopCommand struct {
+	io              ui.IO
+	credentialStore CredentialConfig
+}
+
+// NewAgentStopCommand creates a new AgentStopCommand.
+func NewAgentStopCommand(io ui.IO, credentialStore CredentialConfig) *AgentStopCommand {
+	return &AgentStopCommand{
+		io:              io,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AgentStopCommand) Register(r cli.Registerer) {
+	clause := r.Command("stop", "Stop the agent, so that commands unlock the credential themselves again.")
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run stops the agent.
+func (cmd *AgentStopCommand) Run() error {
+	err := newAgentClient(agentSocketPath(cmd.credentialStore.ConfigDir().Path())).stop()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.io.Output(), "Stopped the agent.")
+	return nil
+}
@@ -67,7 +67,15 @@ type App struct {
 	clientFactory   ClientFactory
 	cli             *cli.App
 	io              ui.IO
-	logger          cli.Logger
+	hooks           *HookRunner
+	projectTrust    *ProjectConfigTrust
+	journal         *Journal
+	teams           *TeamStore
+	usageStats      *UsageStats
+	crashReporter   *CrashReporter
+	metrics         *Metrics
+	keyringJanitor  *KeyringJanitor
+	nonInteractive  *bool
 }
 
 // newClientFunc creates a ClientAdapater.
@@ -75,7 +83,10 @@ type newClientFunc func() (secrethub.ClientInterface, error)
 
 // NewApp creates a new command-line application.
 func NewApp() *App {
-	io := ui.NewUserIO()
+	nonInteractive := false
+	io := ui.NewNonInteractiveIO(ui.NewUserIO(), func() bool {
+		return nonInteractive
+	})
 	store := NewCredentialConfig(io)
 	help := "The SecretHub command-line interface is a unified tool to manage your infrastructure secrets with SecretHub.\n\n" +
 		"If you do not yet have a SecretHub account, go here to create one:\n\n" +
@@ -88,6 +99,9 @@ func NewApp() *App {
 		"Options set on the command-line take precedence over those set in the environment. " +
 		"The format for environment variables is `SECRETHUB_[COMMAND_]FLAG_NAME`."
 
+	projectTrust := NewProjectConfigTrust(store)
+	projectConfig := NewProjectConfig(projectTrust)
+	metrics := NewMetrics()
 	app := App{
 		cli: cli.NewApp(ApplicationName, help).ExtraEnvVarFunc(
 			func(key string) bool {
@@ -95,11 +109,20 @@ func NewApp() *App {
 			},
 		),
 		credentialStore: store,
-		clientFactory:   NewClientFactory(store),
+		clientFactory:   NewClientFactory(store, metrics),
 		io:              io,
-		logger:          cli.NewLogger(),
+		hooks:           NewHookRunner(projectConfig),
+		projectTrust:    projectTrust,
+		journal:         NewJournal(store),
+		usageStats:      NewUsageStats(store),
+		crashReporter:   NewCrashReporter(store),
+		metrics:         metrics,
+		keyringJanitor:  NewKeyringJanitor(store),
+		nonInteractive:  &nonInteractive,
 	}
 
+	app.teams = NewTeamStore(app.clientFactory.NewClient)
+
 	app.cli.Root.Cmd.SetUsageFunc(func(command *cobra.Command) error {
 		err := cli.ApplyTemplate(os.Stdout, cli.UsageTemplate, app.cli.Root)
 		if err != nil {
@@ -113,11 +136,22 @@ func NewApp() *App {
 			fmt.Fprint(os.Stderr, err.Error())
 		}
 	})
-	RegisterDebugFlag(app.cli, app.logger)
+	app.cli.Root.Cmd.SetFlagErrorFunc(flagError)
+	RegisterLogFlags(app.cli)
+	RegisterNonInteractiveFlag(app.cli, app.nonInteractive)
 	RegisterMlockFlag(app.cli)
 	RegisterColorFlag(app.cli)
+	RegisterClipboardBackendFlag(app.cli)
 	app.credentialStore.Register(app.cli)
 	app.clientFactory.Register(app.cli)
+	projectConfig.Register(app.cli)
+	app.hooks.Register(app.cli)
+	app.journal.Register(app.cli)
+	app.teams.Register(app.cli)
+	app.usageStats.Register(app.cli)
+	app.crashReporter.Register(app.cli)
+	app.metrics.Register(app.cli)
+	app.keyringJanitor.Register(app.cli)
 	app.registerCommands()
 
 	return &app
@@ -132,47 +166,96 @@ func (app *App) Version(version string, commit string) *App {
 // Run builds the command-line application, parses the arguments,
 // configures global behavior and executes the command given by the args.
 func (app *App) Run() error {
+	defer app.crashReporter.Recover()
+
 	// Parse also executes the command when parsing is successful.
-	err := app.cli.Root.Cmd.Execute()
-	return err
+	command, err := app.cli.Root.Cmd.ExecuteC()
+	if *app.nonInteractive {
+		err = nonInteractiveError(command, err)
+	}
+	app.journal.Record(err)
+	app.usageStats.Record(err)
+
+	metricsErr := app.metrics.Write(err)
+	if metricsErr != nil {
+		logger.Warningf("%s", metricsErr)
+	}
+
+	hookErr := app.hooks.RunPost(err)
+	if err != nil {
+		if hookErr != nil {
+			logger.Errorf("%s", hookErr)
+		}
+		return err
+	}
+	return hookErr
 }
 
 // registerCommands initializes all commands and registers them on the app.
 func (app *App) registerCommands() {
+	secretCache := NewSecretCache(app.credentialStore)
+	secretTrash := NewSecretTrash(app.credentialStore)
+	completionCache := NewCompletionCache(app.credentialStore)
 
 	// Management commands
-	NewOrgCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
-	NewRepoCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
-	NewACLCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
-	NewServiceCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewOrgCommand(app.io, app.clientFactory, app.credentialStore, app.teams).Register(app.cli)
+	NewRepoCommand(app.io, app.clientFactory.NewClient, app.credentialStore).Register(app.cli)
+	NewACLCommand(app.io, app.clientFactory.NewClient, app.credentialStore).Register(app.cli)
+	NewTeamCommand(app.io, app.clientFactory.NewClient, app.teams).Register(app.cli)
+	NewServiceCommand(app.io, app.clientFactory).Register(app.cli)
 	NewAccountCommand(app.io, app.clientFactory.NewClient, app.credentialStore).Register(app.cli)
 	NewCredentialCommand(app.io, app.clientFactory, app.credentialStore).Register(app.cli)
-	NewConfigCommand(app.io, app.credentialStore).Register(app.cli)
+	NewConfigCommand(app.io, app.credentialStore, app.projectTrust).Register(app.cli)
 	NewEnvCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewK8sCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewSystemdCommand(app.io).Register(app.cli)
+	NewCICommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewCacheCommand(app.io, secretCache).Register(app.cli)
+	NewAgentCommand(app.io, app.clientFactory, app.credentialStore).Register(app.cli)
+	NewProxyCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewTerraformOutputCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewRotateCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewExpireCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewClipboardCommand(app.io, app.credentialStore).Register(app.cli)
 
 	// Commands
 	NewMigrateCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewInitCommand(app.io, app.clientFactory.NewClientWithCredentials, app.credentialStore).Register(app.cli)
 	NewSignUpCommand(app.io).Register(app.cli)
-	NewWriteCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
-	NewReadCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewWriteCommand(app.io, app.clientFactory.NewClient, completionCache).Register(app.cli)
+	NewReadCommand(app.io, app.clientFactory.NewClient, secretCache, completionCache).Register(app.cli)
 	NewGenerateSecretCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewLsCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewMkDirCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
-	NewRmCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewRmCommand(app.io, app.clientFactory.NewClient, secretTrash, completionCache).Register(app.cli)
+	NewRestoreCommand(app.io, app.clientFactory.NewClient, secretTrash).Register(app.cli)
+	NewVersionsCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewMetaCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewWatchCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewTreeCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewInspectCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewAuditCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
-	NewInjectCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
-	NewRunCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewInjectCommand(app.io, app.clientFactory.NewClient, secretCache).Register(app.cli)
+	NewRunCommand(app.io, app.clientFactory.NewClient, secretCache).Register(app.cli)
 	NewPrintEnvCommand(app.cli, app.io).Register(app.cli)
+	NewHistoryCommand(app.io, app.journal).Register(app.cli)
+	NewStatsCommand(app.io, app.usageStats).Register(app.cli)
+	NewBugReportCommand(app.io, app.credentialStore, app.crashReporter).Register(app.cli)
+	NewDoctorCommand(app.io, app.clientFactory, app.credentialStore).Register(app.cli)
+	NewSelfUpdateCommand(app.io, app.clientFactory).Register(app.cli)
+	NewKeyringCommand(app.io, app.keyringJanitor).Register(app.cli)
 
 	// Hidden commands
 	NewClearCommand(app.io).Register(app.cli)
 	NewSetCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
-	NewClearClipboardCommand().Register(app.cli)
-	NewKeyringClearCommand().Register(app.cli)
-	NewCompletionCommand().Register(app.cli)
+	NewClearClipboardCommand(app.credentialStore).Register(app.cli)
+	NewACLExpireCommand(app.credentialStore, app.clientFactory.NewClient).Register(app.cli)
+	NewCompletionCommand(app.io).Register(app.cli)
+	NewCompletionCacheRefreshCommand(app.credentialStore, app.clientFactory.NewClient).Register(app.cli)
+	NewBenchCommand(app.io).Register(app.cli)
+	NewExitCodesCommand(app.io).Register(app.cli)
 
 	demo.NewCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+
+	completionCache.registerFlag(app.cli.Root.Cmd, "secrets-dir", pathKindDir)
 }
@@ -3,6 +3,8 @@ package secrethub
 import (
 	"fmt"
 	"io"
+	"os"
+	"sort"
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
@@ -27,6 +29,8 @@ const (
 	defaultTerminalWidth = 80
 	formatTable          = "table"
 	formatJSON           = "json"
+	formatJSONL          = "jsonl"
+	formatCSV            = "csv"
 	pipedOutputLineLimit = 1000
 )
 
@@ -42,6 +46,9 @@ type AuditCommand struct {
 	perPage            int
 	maxResults         int
 	format             string
+	outFile            string
+	fullPaths          bool
+	filter             auditFilter
 }
 
 // NewAuditCommand creates a new audit command.
@@ -67,17 +74,26 @@ func (cmd *AuditCommand) Register(r cli.Registerer) {
 	clause := r.Command("audit", "Show the audit log.")
 	clause.Flags().IntVar(&cmd.perPage, "per-page", 20, "Number of audit events shown per page")
 	clause.Cmd.Flag("per-page").Hidden = true
-	clause.Flags().StringVar(&cmd.format, "output-format", "table", "Specify the format in which to output the log. Options are: table and json. If the output of the command is parsed by a script an alternative of the table format must be used.")
+	clause.Flags().StringVar(&cmd.format, "output-format", "table", "Specify the format in which to output the log. Options are: table, json, jsonl and csv. The json format writes one JSON object per line, just like jsonl. If the output of the command is parsed by a script an alternative of the table format must be used.")
 	_ = clause.Cmd.RegisterFlagCompletionFunc("output-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"table", "json"}, cobra.ShellCompDirectiveDefault
+		return []string{formatTable, formatJSON, formatJSONL, formatCSV}, cobra.ShellCompDirectiveDefault
 	})
+	clause.Flags().StringVarP(&cmd.outFile, "out-file", "o", "", "Write the log to this file instead of stdout, e.g. to archive it or to ingest it in other tools.")
 	clause.Flags().IntVar(&cmd.maxResults, "max-results", defaultLimit, "Specify the number of entries to list. If maxResults < 0 all entries are displayed. If the output of the command is piped, maxResults defaults to 1000.")
+	clause.Flags().StringSliceVar(&cmd.filter.actions, "action", nil, "Only show events with one of the given actions, e.g. read.secret or secret.read.")
+	clause.Flags().StringSliceVar(&cmd.filter.actors, "actor", nil, "Only show events performed by one of the given usernames or service IDs.")
+	clause.Flags().Var(&cmd.filter.since, "since", "Only show events logged on or after the given date (2006-01-02) or timestamp (RFC3339).")
+	clause.Flags().Var(&cmd.filter.until, "until", "Only show events logged on or before the given date (2006-01-02) or timestamp (RFC3339).")
 	registerTimestampFlag(clause, &cmd.useTimestamps)
+	registerFullPathsFlag(clause, &cmd.fullPaths)
 
 	clause.BindAction(cmd.Run)
 	clause.BindArguments([]cli.Argument{
-		{Value: &cmd.path, Name: "path", Required: true, Description: "Path to the repository or the secret to audit " + repoPathPlaceHolder + " or " + secretPathPlaceHolder, Placeholder: optionalSecretPathPlaceHolder},
+		{Value: &cmd.path, Name: "path", Required: true, Description: "Path to the namespace, the repository or the secret to audit " + namespacePlaceHolder + ", " + repoPathPlaceHolder + " or " + secretPathPlaceHolder, Placeholder: namespaceOrSecretPathPlaceHolder},
 	})
+
+	NewAuditTailCommand(cmd.io, cmd.newClient).Register(clause)
+	NewAuditForwardCommand(cmd.io, cmd.newClient).Register(clause)
 }
 
 // Run prints all audit events for the given repository or secret.
@@ -88,7 +104,7 @@ func (cmd *AuditCommand) Run() error {
 
 // beforeRun configures the command using the flag values.
 func (cmd *AuditCommand) beforeRun() {
-	if cmd.format == formatJSON {
+	if cmd.format != formatTable {
 		cmd.timeFormatter = NewTimeFormatter(true)
 	} else {
 		cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps)
@@ -106,28 +122,26 @@ func (cmd *AuditCommand) run() error {
 		return err
 	}
 
-	paginatedWriter, err := cmd.newPaginatedWriter(cmd.io.Output())
-	if err != nil {
-		return err
-	}
-	defer paginatedWriter.Close()
-
-	var formatter listFormatter
-	if cmd.format == formatJSON {
-		formatter = newJSONFormatter(paginatedWriter, auditTable.header())
-	} else if cmd.format == formatTable && cmd.io.IsOutputPiped() {
-		formatter = newLineFormatter(paginatedWriter)
-	} else if cmd.format == formatTable {
-		terminalWidth, err := cmd.terminalWidth(int(cmd.io.Stdout().Fd()))
+	var out io.WriteCloser
+	if cmd.outFile != "" {
+		out, err = os.OpenFile(cmd.outFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 		if err != nil {
-			terminalWidth = defaultTerminalWidth
+			return ErrCannotWrite(cmd.outFile, err)
 		}
-		formatter = newTableFormatter(paginatedWriter, terminalWidth, auditTable.columns())
 	} else {
-		return errNoSuchFormat(cmd.format)
+		out, err = cmd.newPaginatedWriter(cmd.io.Output())
+		if err != nil {
+			return err
+		}
+	}
+	defer out.Close()
+
+	formatter, err := cmd.newFormatter(out, auditTable)
+	if err != nil {
+		return err
 	}
 
-	for lineCount := 0; lineCount != cmd.maxResults; lineCount++ {
+	for lineCount := 0; lineCount != cmd.maxResults; {
 		event, err := iter.Next()
 		if err == iterator.Done {
 			break
@@ -135,6 +149,15 @@ func (cmd *AuditCommand) run() error {
 			return err
 		}
 
+		match, err := cmd.filter.match(event)
+		if err != nil {
+			return err
+		}
+		if !match {
+			continue
+		}
+		lineCount++
+
 		row, err := auditTable.row(event)
 		if err != nil {
 			return err
@@ -150,6 +173,26 @@ func (cmd *AuditCommand) run() error {
 	return nil
 }
 
+// newFormatter returns a formatter for the configured output format that writes the rows of the audit table to out.
+func (cmd *AuditCommand) newFormatter(out io.Writer, auditTable auditTable) (listFormatter, error) {
+	switch {
+	case cmd.format == formatJSON || cmd.format == formatJSONL:
+		return newJSONFormatter(out, auditTable.header()), nil
+	case cmd.format == formatCSV:
+		return newCSVFormatter(out, auditTable.header()), nil
+	case cmd.format == formatTable && (cmd.io.IsOutputPiped() || cmd.outFile != ""):
+		return newLineFormatter(out), nil
+	case cmd.format == formatTable:
+		terminalWidth, err := cmd.terminalWidth(int(cmd.io.Stdout().Fd()))
+		if err != nil {
+			terminalWidth = defaultTerminalWidth
+		}
+		return newTableFormatter(out, terminalWidth, auditTable.columns(), cmd.fullPaths), nil
+	default:
+		return nil, errNoSuchFormat(cmd.format)
+	}
+}
+
 func (cmd *AuditCommand) iterAndAuditTable() (secrethub.AuditEventIterator, auditTable, error) {
 	repoPath, err := cmd.path.ToRepoPath()
 	if err == nil {
@@ -185,7 +228,25 @@ func (cmd *AuditCommand) iterAndAuditTable() (secrethub.AuditEventIterator, audi
 		}
 
 		iter := client.Secrets().EventIterator(secretPath.Value(), &secrethub.AuditEventIteratorParams{})
-		auditTable := newSecretAuditTable(cmd.timeFormatter)
+		auditTable := newSecretAuditTable(secretPath, cmd.format != formatTable, cmd.timeFormatter)
+		return iter, auditTable, nil
+	}
+
+	namespace, err := cmd.path.ToNamespace()
+	if err == nil {
+		client, err := cmd.newClient()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		repos, err := client.Repos().List(namespace.String())
+		if err != nil {
+			return nil, nil, err
+		}
+		sort.Sort(api.SortRepoByName(repos))
+
+		iter := newNamespaceAuditEventIterator(client, repos)
+		auditTable := newNamespaceAuditTable(client, iter, cmd.timeFormatter)
 		return iter, auditTable, nil
 	}
 
@@ -195,6 +256,8 @@ func (cmd *AuditCommand) iterAndAuditTable() (secrethub.AuditEventIterator, audi
 type tableColumn struct {
 	name     string
 	maxWidth int
+	// isPath marks a column of which the values are truncated in the middle instead of wrapped.
+	isPath bool
 }
 
 type auditTable interface {
@@ -246,14 +309,26 @@ func (table baseAuditTable) columns() []tableColumn {
 	return table.tableColumns
 }
 
-func newSecretAuditTable(timeFormatter TimeFormatter) secretAuditTable {
+// newSecretAuditTable returns an audit table for the events of a secret. In the table format,
+// the subject column is left out because all events are about the same secret. For other
+// formats it is included, so that every record contains the path of the secret.
+func newSecretAuditTable(path api.SecretPath, includeSubject bool, timeFormatter TimeFormatter) secretAuditTable {
+	if !includeSubject {
+		return secretAuditTable{
+			baseAuditTable: newBaseAuditTable(timeFormatter),
+		}
+	}
 	return secretAuditTable{
-		baseAuditTable: newBaseAuditTable(timeFormatter),
+		baseAuditTable: newBaseAuditTable(timeFormatter, tableColumn{name: "event subject", isPath: true}),
+		path:           path,
+		includeSubject: true,
 	}
 }
 
 type secretAuditTable struct {
 	baseAuditTable
+	path           api.SecretPath
+	includeSubject bool
 }
 
 func (table secretAuditTable) header() []string {
@@ -261,12 +336,20 @@ func (table secretAuditTable) header() []string {
 }
 
 func (table secretAuditTable) row(event api.Audit) ([]string, error) {
-	return table.baseAuditTable.row(event)
+	if !table.includeSubject {
+		return table.baseAuditTable.row(event)
+	}
+
+	subject := table.path.Value()
+	if event.Subject.Type == api.AuditSubjectSecretVersion && event.Subject.SecretVersion != nil {
+		subject = fmt.Sprintf("%s:%d", subject, event.Subject.SecretVersion.Version)
+	}
+	return table.baseAuditTable.row(event, subject)
 }
 
 func newRepoAuditTable(tree *api.Tree, timeFormatter TimeFormatter) repoAuditTable {
 	return repoAuditTable{
-		baseAuditTable: newBaseAuditTable(timeFormatter, tableColumn{name: "event subject"}),
+		baseAuditTable: newBaseAuditTable(timeFormatter, tableColumn{name: "event subject", isPath: true}),
 		tree:           tree,
 	}
 }
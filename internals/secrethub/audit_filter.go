@@ -0,0 +1,62 @@
+package secrethub
+
+import (
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// auditFilter selects the audit events to show. The filters are applied while iterating the events,
+// because the API does not support filtering yet. An empty filter matches all events.
+type auditFilter struct {
+	actions []string
+	actors  []string
+	since   timeValue
+	until   timeValue
+}
+
+// match returns whether the event passes all filters.
+func (f auditFilter) match(event api.Audit) (bool, error) {
+	if len(f.actions) > 0 && !f.matchAction(event) {
+		return false, nil
+	}
+
+	if len(f.actors) > 0 {
+		actor, err := getAuditActor(event)
+		if err != nil {
+			return false, err
+		}
+		if !containsFold(f.actors, actor) {
+			return false, nil
+		}
+	}
+
+	if !f.since.IsZero() && event.LoggedAt.Before(f.since.Time) {
+		return false, nil
+	}
+
+	if !f.until.IsZero() && !event.LoggedAt.Before(f.until.end()) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// matchAction returns whether the event has one of the filtered actions. An action can be given as
+// it is shown in the event column, e.g. read.secret, or the other way around, e.g. secret.read.
+func (f auditFilter) matchAction(event api.Audit) bool {
+	action := getEventAction(event)
+	parts := strings.SplitN(action, ".", 2)
+	reversed := parts[len(parts)-1] + "." + parts[0]
+	return containsFold(f.actions, action) || containsFold(f.actions, reversed)
+}
+
+// containsFold returns whether the list contains the value, ignoring case.
+func containsFold(list []string, value string) bool {
+	for _, elem := range list {
+		if strings.EqualFold(elem, value) {
+			return true
+		}
+	}
+	return false
+}
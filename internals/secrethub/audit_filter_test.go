@@ -0,0 +1,120 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestAuditFilter_match(t *testing.T) {
+	event := api.Audit{
+		Action: "read",
+		Actor: api.AuditActor{
+			Type: "user",
+			User: &api.User{
+				Username: "developer",
+			},
+		},
+		LoggedAt: time.Date(2018, 1, 15, 12, 0, 0, 0, time.Local),
+		Subject: api.AuditSubject{
+			Type: api.AuditSubjectSecret,
+		},
+	}
+
+	date := func(value string) timeValue {
+		var v timeValue
+		assert.OK(t, v.Set(value))
+		return v
+	}
+
+	cases := map[string]struct {
+		filter   auditFilter
+		expected bool
+	}{
+		"empty filter": {
+			expected: true,
+		},
+		"action": {
+			filter:   auditFilter{actions: []string{"read.secret"}},
+			expected: true,
+		},
+		"reversed action": {
+			filter:   auditFilter{actions: []string{"update.secret", "Secret.Read"}},
+			expected: true,
+		},
+		"other action": {
+			filter:   auditFilter{actions: []string{"secret.update"}},
+			expected: false,
+		},
+		"actor": {
+			filter:   auditFilter{actors: []string{"developer"}},
+			expected: true,
+		},
+		"other actor": {
+			filter:   auditFilter{actors: []string{"service-id"}},
+			expected: false,
+		},
+		"since": {
+			filter:   auditFilter{since: date("2018-01-15")},
+			expected: true,
+		},
+		"since after event": {
+			filter:   auditFilter{since: date("2018-01-16")},
+			expected: false,
+		},
+		"until includes the entire day": {
+			filter:   auditFilter{until: date("2018-01-15")},
+			expected: true,
+		},
+		"until before event": {
+			filter:   auditFilter{until: date("2018-01-14")},
+			expected: false,
+		},
+		"until timestamp before event": {
+			filter:   auditFilter{until: timeValue{Time: event.LoggedAt.Add(-time.Second)}},
+			expected: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := tc.filter.match(event)
+			assert.OK(t, err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestTimeValue_Set(t *testing.T) {
+	cases := map[string]struct {
+		value    string
+		expected time.Time
+		err      error
+	}{
+		"date": {
+			value:    "2018-01-01",
+			expected: time.Date(2018, 1, 1, 0, 0, 0, 0, time.Local),
+		},
+		"timestamp": {
+			value:    "2018-01-01T12:00:00Z",
+			expected: time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC),
+		},
+		"invalid": {
+			value: "last month",
+			err:   ErrInvalidTime("last month"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var v timeValue
+			err := v.Set(tc.value)
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, v.Equal(tc.expected), true)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package secrethub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrMissingForwardTarget    = errAudit.Code("missing_target").Error("a target to forward the events to must be set with --target")
+	ErrMissingForwardStateFile = errAudit.Code("missing_state_file").Error("a file to keep track of the forwarded events must be set with --state-file")
+	ErrInvalidForwardTarget    = errAudit.Code("invalid_target").ErrorPref("invalid target %s: the target must be an http(s) URL for a webhook or a syslog://, syslog+udp:// or syslog+tcp:// address")
+	ErrForwardFailed           = errAudit.Code("forward_failed").ErrorPref("could not forward audit event to %s: %s")
+	ErrInvalidForwardState     = errAudit.Code("invalid_state_file").ErrorPref("invalid state file %s: %s")
+)
+
+const (
+	defaultSyslogPort = "514"
+	// syslogPriority is the priority of forwarded events: the log audit facility (13) with the informational severity (6).
+	syslogPriority = 13*8 + 6
+	webhookTimeout = 30 * time.Second
+)
+
+// AuditForwardCommand forwards new audit events of a namespace, repo or secret to a webhook or a syslog server.
+// The last forwarded event is recorded in a state file, so that every run only forwards the events
+// that were logged since the previous run.
+type AuditForwardCommand struct {
+	audit     AuditCommand
+	target    string
+	stateFile string
+	daemon    bool
+	interval  time.Duration
+	wait      func(ctx context.Context, d time.Duration) bool
+	newSink   func(target string) (auditSink, error)
+}
+
+// NewAuditForwardCommand creates a new AuditForwardCommand.
+func NewAuditForwardCommand(io ui.IO, newClient newClientFunc) *AuditForwardCommand {
+	return &AuditForwardCommand{
+		audit: AuditCommand{
+			io:        io,
+			newClient: newClient,
+			format:    formatJSONL,
+		},
+		wait:    waitContext,
+		newSink: newAuditSink,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AuditForwardCommand) Register(r cli.Registerer) {
+	clause := r.Command("forward", "Forward new audit events to a webhook or a syslog server.")
+	clause.HelpLong("Every event is sent as a JSON object. For a webhook, every event is POSTed to the target URL. " +
+		"For syslog, every event is sent as an RFC 5424 message with the JSON object as the message.\n\n" +
+		"The last forwarded event is recorded in the state file, so that the next run only forwards the events that were logged since. " +
+		"When the state file does not exist yet, all events are forwarded. " +
+		"Run the command periodically, e.g. from cron, or keep it running with --daemon.")
+	clause.Flags().StringVar(&cmd.target, "target", "", "The webhook URL (http:// or https://) or the syslog server address (syslog://host:port, syslog+udp://host:port or syslog+tcp://host:port) to forward the events to.")
+	clause.Flags().StringVar(&cmd.stateFile, "state-file", "", "The file in which the last forwarded event is recorded.")
+	clause.Flags().BoolVar(&cmd.daemon, "daemon", false, "Keep running and forward new events as they are logged. Stop with Ctrl+C.")
+	clause.Flags().DurationVar(&cmd.interval, "interval", time.Minute, "The time between checks for new events when running as a daemon.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.audit.path, Name: "path", Required: true, Description: "Path to the namespace, the repository or the secret of which to forward the audit events " + namespacePlaceHolder + ", " + repoPathPlaceHolder + " or " + secretPathPlaceHolder, Placeholder: namespaceOrSecretPathPlaceHolder},
+	})
+}
+
+// Run forwards the new events and keeps forwarding events until interrupted when running as a daemon.
+func (cmd *AuditForwardCommand) Run() error {
+	cmd.audit.beforeRun()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return cmd.run(ctx)
+}
+
+func (cmd *AuditForwardCommand) run(ctx context.Context) error {
+	if cmd.target == "" {
+		return ErrMissingForwardTarget
+	}
+	if cmd.stateFile == "" {
+		return ErrMissingForwardStateFile
+	}
+	if cmd.daemon && cmd.interval <= 0 {
+		return ErrInvalidPollInterval(cmd.interval)
+	}
+
+	sink, err := cmd.newSink(cmd.target)
+	if err != nil {
+		return err
+	}
+	defer sink.Close()
+
+	cursor, err := loadAuditForwardState(cmd.stateFile)
+	if err != nil {
+		return err
+	}
+
+	for {
+		n, err := cmd.forward(sink, &cursor)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			fmt.Fprintf(cmd.audit.io.Output(), "Forwarded %d audit events.\n", n)
+		}
+
+		if !cmd.daemon || !cmd.wait(ctx, cmd.interval) {
+			return nil
+		}
+	}
+}
+
+// forward sends the events logged after the cursor to the sink, from the oldest to the newest.
+// The cursor is saved after every forwarded event, so that a failure does not cause events
+// to be lost or to be forwarded twice on the next run.
+func (cmd *AuditForwardCommand) forward(sink auditSink, cursor *auditCursor) (int, error) {
+	iter, auditTable, err := cmd.audit.iterAndAuditTable()
+	if err != nil {
+		return 0, err
+	}
+
+	next := *cursor
+	events, err := next.newEvents(iter, -1)
+	if err != nil {
+		return 0, err
+	}
+
+	var buf bytes.Buffer
+	formatter := newJSONFormatter(&buf, auditTable.header())
+	forwarded := 0
+	for i := len(events) - 1; i >= 0; i-- {
+		row, err := auditTable.row(events[i])
+		if err != nil {
+			return forwarded, err
+		}
+
+		buf.Reset()
+		err = formatter.Write(row)
+		if err != nil {
+			return forwarded, err
+		}
+
+		err = sink.Send(events[i].LoggedAt, bytes.TrimSpace(buf.Bytes()))
+		if err != nil {
+			return forwarded, ErrForwardFailed(cmd.target, err)
+		}
+		forwarded++
+
+		cursor.advance(events[i])
+		err = saveAuditForwardState(cmd.stateFile, *cursor)
+		if err != nil {
+			return forwarded, err
+		}
+	}
+	return forwarded, nil
+}
+
+// advance moves the cursor to the given event, which must not be older than the cursor.
+func (c *auditCursor) advance(event api.Audit) {
+	if c.eventIDs == nil || event.LoggedAt.After(c.loggedAt) {
+		*c = auditCursor{loggedAt: event.LoggedAt, eventIDs: make(map[string]bool)}
+	}
+	c.eventIDs[event.EventID.String()] = true
+}
+
+// auditForwardState is the content of the state file of the forward command.
+type auditForwardState struct {
+	LoggedAt time.Time `json:"logged_at"`
+	// EventIDs contains the IDs of the forwarded events that were logged at LoggedAt.
+	EventIDs []string `json:"event_ids"`
+}
+
+// loadAuditForwardState reads the cursor from the state file. When the file does not exist, an empty cursor is returned.
+func loadAuditForwardState(path string) (auditCursor, error) {
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return auditCursor{}, nil
+	} else if err != nil {
+		return auditCursor{}, ErrCannotReadFile(path, err)
+	}
+
+	var state auditForwardState
+	err = json.Unmarshal(raw, &state)
+	if err != nil {
+		return auditCursor{}, ErrInvalidForwardState(path, err)
+	}
+
+	cursor := auditCursor{
+		loggedAt: state.LoggedAt,
+		eventIDs: make(map[string]bool, len(state.EventIDs)),
+	}
+	for _, id := range state.EventIDs {
+		cursor.eventIDs[id] = true
+	}
+	return cursor, nil
+}
+
+// saveAuditForwardState atomically replaces the state file with the given cursor.
+func saveAuditForwardState(path string, cursor auditCursor) error {
+	state := auditForwardState{
+		LoggedAt: cursor.loggedAt,
+		EventIDs: make([]string, 0, len(cursor.eventIDs)),
+	}
+	for id := range cursor.eventIDs {
+		state.EventIDs = append(state.EventIDs, id)
+	}
+
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	err = atomicfile.Write(path, raw, 0600)
+	if err != nil {
+		return ErrCannotWrite(path, err)
+	}
+	return nil
+}
+
+// auditSink receives forwarded audit events.
+type auditSink interface {
+	// Send sends the JSON representation of an event that was logged at the given time.
+	Send(loggedAt time.Time, event []byte) error
+	Close() error
+}
+
+// newAuditSink returns the sink for the given target.
+func newAuditSink(target string) (auditSink, error) {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return nil, ErrInvalidForwardTarget(target)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return &webhookSink{
+			url:    target,
+			client: &http.Client{Timeout: webhookTimeout},
+		}, nil
+	case "syslog", "syslog+udp", "syslog+tcp":
+		network := "udp"
+		if u.Scheme == "syslog+tcp" {
+			network = "tcp"
+		}
+
+		address := u.Host
+		if u.Port() == "" {
+			address = net.JoinHostPort(u.Hostname(), defaultSyslogPort)
+		}
+
+		conn, err := net.Dial(network, address)
+		if err != nil {
+			return nil, ErrForwardFailed(target, err)
+		}
+
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "-"
+		}
+
+		return &syslogSink{
+			conn:     conn,
+			hostname: hostname,
+		}, nil
+	default:
+		return nil, ErrInvalidForwardTarget(target)
+	}
+}
+
+// webhookSink POSTs every event to a URL.
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// Send POSTs the event to the webhook.
+func (s *webhookSink) Send(loggedAt time.Time, event []byte) error {
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(event))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
+
+// Close does nothing, as every event is sent in its own request.
+func (s *webhookSink) Close() error {
+	return nil
+}
+
+// syslogSink sends every event as an RFC 5424 message to a syslog server.
+type syslogSink struct {
+	conn     net.Conn
+	hostname string
+}
+
+// Send writes the event as a syslog message. Messages are separated by a newline,
+// which is how messages are framed when sent over TCP.
+func (s *syslogSink) Send(loggedAt time.Time, event []byte) error {
+	msg := fmt.Sprintf("<%d>1 %s %s %s - - - %s\n", syslogPriority, loggedAt.UTC().Format(time.RFC3339), s.hostname, strings.ToLower(ApplicationName), event)
+	_, err := s.conn.Write([]byte(msg))
+	return err
+}
+
+// Close closes the connection to the syslog server.
+func (s *syslogSink) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,163 @@
+package secrethub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+type fakeAuditSink struct {
+	sent []map[string]string
+	err  error
+}
+
+func (s *fakeAuditSink) Send(loggedAt time.Time, event []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	var fields map[string]string
+	err := json.Unmarshal(event, &fields)
+	if err != nil {
+		return err
+	}
+	s.sent = append(s.sent, fields)
+	return nil
+}
+
+func (s *fakeAuditSink) Close() error {
+	return nil
+}
+
+func TestAuditForwardCommand_run(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := func(username string, loggedAt time.Time) api.Audit {
+		return api.Audit{
+			EventID: uuid.New(),
+			Action:  "create",
+			Actor: api.AuditActor{
+				Type: "user",
+				User: &api.User{Username: username},
+			},
+			LoggedAt: loggedAt,
+			Subject: api.AuditSubject{
+				Type: "repo",
+				Repo: &api.Repo{Name: "repo"},
+			},
+			IPAddress: "127.0.0.1",
+		}
+	}
+
+	e1 := event("dev1", now)
+	e2 := event("dev2", now.Add(time.Second))
+	e3 := event("dev3", now.Add(2*time.Second))
+
+	var events []api.Audit
+	sink := &fakeAuditSink{}
+	io := fakeui.NewIO(t)
+	cmd := AuditForwardCommand{
+		audit: AuditCommand{
+			io:   io,
+			path: "namespace/repo",
+			newClient: func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					DirService: &fakeclient.DirService{
+						GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+							return nil, nil
+						},
+					},
+					RepoService: &fakeclient.RepoService{
+						AuditEventIterator: &fakeclient.AuditEventIterator{
+							Events: events,
+						},
+					},
+				}, nil
+			},
+			format: formatJSONL,
+		},
+		target:    "https://siem.example.com/hook",
+		stateFile: filepath.Join(dir, "forward.json"),
+		newSink: func(target string) (auditSink, error) {
+			return sink, nil
+		},
+	}
+	cmd.audit.beforeRun()
+
+	// The first run forwards all events, from the oldest to the newest.
+	events = []api.Audit{e2, e1}
+	err := cmd.run(context.Background())
+	assert.OK(t, err)
+	assert.Equal(t, len(sink.sent), 2)
+	assert.Equal(t, sink.sent[0]["Author"], "dev1")
+	assert.Equal(t, sink.sent[1]["Author"], "dev2")
+	assert.Equal(t, sink.sent[1]["Event"], "create.repo")
+	assert.Equal(t, io.Out.String(), "Forwarded 2 audit events.\n")
+
+	// The next run only forwards the events logged since.
+	sink.sent = nil
+	events = []api.Audit{e3, e2, e1}
+	err = cmd.run(context.Background())
+	assert.OK(t, err)
+	assert.Equal(t, len(sink.sent), 1)
+	assert.Equal(t, sink.sent[0]["Author"], "dev3")
+
+	// A failure leaves the state untouched, so the event is forwarded again on the next run.
+	e4 := event("dev4", now.Add(3*time.Second))
+	sink.sent = nil
+	sink.err = errors.New("connection refused")
+	events = []api.Audit{e4, e3, e2, e1}
+	err = cmd.run(context.Background())
+	assert.Equal(t, err, ErrForwardFailed(cmd.target, sink.err))
+
+	sink.err = nil
+	err = cmd.run(context.Background())
+	assert.OK(t, err)
+	assert.Equal(t, len(sink.sent), 1)
+	assert.Equal(t, sink.sent[0]["Author"], "dev4")
+}
+
+func TestNewAuditSink(t *testing.T) {
+	cases := map[string]struct {
+		target string
+		err    error
+	}{
+		"webhook": {
+			target: "https://siem.example.com/hook",
+		},
+		"no scheme": {
+			target: "siem.example.com",
+			err:    ErrInvalidForwardTarget("siem.example.com"),
+		},
+		"unsupported scheme": {
+			target: "ftp://siem.example.com",
+			err:    ErrInvalidForwardTarget("ftp://siem.example.com"),
+		},
+		"no host": {
+			target: "syslog://",
+			err:    ErrInvalidForwardTarget("syslog://"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sink, err := newAuditSink(tc.target)
+			assert.Equal(t, err, tc.err)
+			if err == nil {
+				assert.OK(t, sink.Close())
+			}
+		})
+	}
+}
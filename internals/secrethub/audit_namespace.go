@@ -0,0 +1,133 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
+)
+
+// namespaceAuditEventIterator merges the audit event iterators of the repos in a namespace into a
+// single iterator that returns the events of all repos from the newest to the oldest. Repos of which
+// the audit log cannot be read, because the user is not an admin, are skipped.
+type namespaceAuditEventIterator struct {
+	sources []*repoAuditEventSource
+	// repos maps the IDs of the returned events to the repo they belong to.
+	repos map[string]api.RepoPath
+}
+
+// repoAuditEventSource is the audit event iterator of a single repo, with the next event it returns.
+type repoAuditEventSource struct {
+	repo    api.RepoPath
+	iter    secrethub.AuditEventIterator
+	next    *api.Audit
+	started bool
+	done    bool
+}
+
+// newNamespaceAuditEventIterator creates an iterator over the events of the given repos.
+func newNamespaceAuditEventIterator(client secrethub.ClientInterface, repos []*api.Repo) *namespaceAuditEventIterator {
+	sources := make([]*repoAuditEventSource, len(repos))
+	for i, repo := range repos {
+		sources[i] = &repoAuditEventSource{
+			repo: repo.Path(),
+			iter: client.Repos().EventIterator(repo.Path().Value(), &secrethub.AuditEventIteratorParams{}),
+		}
+	}
+	return &namespaceAuditEventIterator{
+		sources: sources,
+		repos:   make(map[string]api.RepoPath),
+	}
+}
+
+// Next returns the newest event of all repos that has not been returned yet.
+func (it *namespaceAuditEventIterator) Next() (api.Audit, error) {
+	var newest *repoAuditEventSource
+	for _, source := range it.sources {
+		err := source.peek()
+		if err != nil {
+			return api.Audit{}, err
+		}
+		if source.next == nil {
+			continue
+		}
+		if newest == nil || source.next.LoggedAt.After(newest.next.LoggedAt) {
+			newest = source
+		}
+	}
+
+	if newest == nil {
+		return api.Audit{}, iterator.Done
+	}
+
+	event := *newest.next
+	newest.next = nil
+	it.repos[event.EventID.String()] = newest.repo
+	return event, nil
+}
+
+// peek fetches the next event of the repo, unless it has already been fetched.
+func (s *repoAuditEventSource) peek() error {
+	if s.next != nil || s.done {
+		return nil
+	}
+
+	event, err := s.iter.Next()
+	if err == iterator.Done || (err == api.ErrForbidden && !s.started) {
+		s.done = true
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	s.started = true
+	s.next = &event
+	return nil
+}
+
+// namespaceAuditTable is an audit table for the events of all repos in a namespace.
+type namespaceAuditTable struct {
+	baseAuditTable
+	client secrethub.ClientInterface
+	iter   *namespaceAuditEventIterator
+	trees  map[api.RepoPath]*api.Tree
+}
+
+func newNamespaceAuditTable(client secrethub.ClientInterface, iter *namespaceAuditEventIterator, timeFormatter TimeFormatter) namespaceAuditTable {
+	return namespaceAuditTable{
+		baseAuditTable: newBaseAuditTable(timeFormatter,
+			tableColumn{name: "repo", isPath: true},
+			tableColumn{name: "event subject", isPath: true},
+		),
+		client: client,
+		iter:   iter,
+		trees:  make(map[api.RepoPath]*api.Tree),
+	}
+}
+
+// row returns the row for an event that was returned by the iterator of the table.
+func (table namespaceAuditTable) row(event api.Audit) ([]string, error) {
+	repo := table.iter.repos[event.EventID.String()]
+
+	// The tree of a repo is only needed to resolve the path of a secret,
+	// so it is only fetched for the repos that have events on secrets.
+	var tree *api.Tree
+	if !event.Subject.Deleted && (event.Subject.Type == api.AuditSubjectSecret || event.Subject.Type == api.AuditSubjectSecretVersion) {
+		var ok bool
+		tree, ok = table.trees[repo]
+		if !ok {
+			var err error
+			tree, err = table.client.Dirs().GetTree(repo.GetDirPath().Value(), -1, false)
+			if err != nil {
+				return nil, err
+			}
+			table.trees[repo] = tree
+		}
+	}
+
+	subject, err := getAuditSubject(event, tree)
+	if err != nil {
+		return nil, err
+	}
+
+	return table.baseAuditTable.row(event, repo.String(), subject)
+}
@@ -0,0 +1,45 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
+)
+
+func TestNamespaceAuditEventIterator(t *testing.T) {
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := func(seconds int) api.Audit {
+		return api.Audit{EventID: uuid.New(), LoggedAt: now.Add(time.Duration(seconds) * time.Second)}
+	}
+
+	a1, a3 := event(1), event(3)
+	b2, b4 := event(2), event(4)
+
+	iter := &namespaceAuditEventIterator{
+		sources: []*repoAuditEventSource{
+			{repo: "namespace/a", iter: &fakeclient.AuditEventIterator{Events: []api.Audit{a3, a1}}},
+			{repo: "namespace/b", iter: &fakeclient.AuditEventIterator{Events: []api.Audit{b4, b2}}},
+			{repo: "namespace/forbidden", iter: &fakeclient.AuditEventIterator{Err: api.ErrForbidden}},
+		},
+		repos: make(map[string]api.RepoPath),
+	}
+
+	var events []api.Audit
+	for {
+		event, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		assert.OK(t, err)
+		events = append(events, event)
+	}
+
+	assert.Equal(t, events, []api.Audit{b4, a3, b2, a1})
+	assert.Equal(t, iter.repos[a1.EventID.String()], api.RepoPath("namespace/a"))
+	assert.Equal(t, iter.repos[b4.EventID.String()], api.RepoPath("namespace/b"))
+}
@@ -72,6 +72,51 @@ func TestAuditSecretCommand_run(t *testing.T) {
 				"            ret                     T01:01:01+\n" +
 				"                                    01:00     \n",
 		},
+		"csv": {
+			cmd: AuditCommand{
+				path: "namespace/repo/secret",
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						DirService: &fakeclient.DirService{
+							ExistsFunc: func(_ string) (bool, error) {
+								return false, nil
+							},
+						},
+						SecretService: &fakeclient.SecretService{
+							AuditEventIterator: &fakeclient.AuditEventIterator{
+								Events: []api.Audit{
+									{
+										Action: "read",
+										Actor: api.AuditActor{
+											Type: "user",
+											User: &api.User{
+												Username: "developer",
+											},
+										},
+										LoggedAt: time.Date(2018, 1, 1, 1, 1, 1, 1, time.Local),
+										Subject: api.AuditSubject{
+											Type: api.AuditSubjectSecretVersion,
+											SecretVersion: &api.EncryptedSecretVersion{
+												Version: 2,
+											},
+										},
+										IPAddress: "127.0.0.1",
+									},
+								},
+							},
+						},
+					}, nil
+				},
+				format:     formatCSV,
+				perPage:    20,
+				maxResults: -1,
+				timeFormatter: &fakes.TimeFormatter{
+					Response: "2018-01-01T01:01:01+01:00",
+				},
+			},
+			out: "author,event,event subject,IP address,date\n" +
+				"developer,read.secret_version,namespace/repo/secret:2,127.0.0.1,2018-01-01T01:01:01+01:00\n",
+		},
 		"0 events": {
 			cmd: AuditCommand{
 				path: "namespace/repo/secret",
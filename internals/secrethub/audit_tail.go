@@ -0,0 +1,195 @@
+package secrethub
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Errors
+var (
+	ErrInvalidPollInterval = errAudit.Code("invalid_interval").ErrorPref("the interval should be positive, got %s")
+)
+
+// AuditTailCommand prints the most recent audit events of a repo or a secret and
+// optionally keeps printing new events as they are logged.
+type AuditTailCommand struct {
+	audit    AuditCommand
+	lines    int
+	follow   bool
+	interval time.Duration
+	wait     func(ctx context.Context, d time.Duration) bool
+}
+
+// NewAuditTailCommand creates a new AuditTailCommand.
+func NewAuditTailCommand(io ui.IO, newClient newClientFunc) *AuditTailCommand {
+	return &AuditTailCommand{
+		audit: AuditCommand{
+			io:        io,
+			newClient: newClient,
+			terminalWidth: func(fd int) (int, error) {
+				w, _, err := terminal.GetSize(fd)
+				return w, err
+			},
+		},
+		wait: waitContext,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *AuditTailCommand) Register(r cli.Registerer) {
+	clause := r.Command("tail", "Show the most recent audit events and optionally follow new events as they are logged.")
+	clause.Flags().IntVarP(&cmd.lines, "lines", "n", 10, "The number of most recent events to show.")
+	clause.Flags().BoolVarP(&cmd.follow, "follow", "F", false, "Keep checking for new events and print them as they are logged. Stop with Ctrl+C.")
+	clause.Flags().DurationVar(&cmd.interval, "interval", 5*time.Second, "The time between checks for new events when following.")
+	clause.Flags().StringVar(&cmd.audit.format, "output-format", formatTable, "Specify the format in which to output the events. Options are: table, json, jsonl and csv.")
+	_ = clause.Cmd.RegisterFlagCompletionFunc("output-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{formatTable, formatJSON, formatJSONL, formatCSV}, cobra.ShellCompDirectiveDefault
+	})
+	registerTimestampFlag(clause, &cmd.audit.useTimestamps)
+	registerFullPathsFlag(clause, &cmd.audit.fullPaths)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.audit.path, Name: "path", Required: true, Description: "Path to the namespace, the repository or the secret to audit " + namespacePlaceHolder + ", " + repoPathPlaceHolder + " or " + secretPathPlaceHolder, Placeholder: namespaceOrSecretPathPlaceHolder},
+	})
+}
+
+// Run prints the most recent events and follows new events until interrupted.
+func (cmd *AuditTailCommand) Run() error {
+	cmd.audit.beforeRun()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return cmd.run(ctx)
+}
+
+func (cmd *AuditTailCommand) run(ctx context.Context) error {
+	if cmd.follow && cmd.interval <= 0 {
+		return ErrInvalidPollInterval(cmd.interval)
+	}
+
+	var formatter listFormatter
+	var cursor auditCursor
+	limit := cmd.lines
+	for {
+		// The tree is fetched again for every check, so that the subjects of new secrets can be resolved.
+		iter, auditTable, err := cmd.audit.iterAndAuditTable()
+		if err != nil {
+			return err
+		}
+
+		if formatter == nil {
+			formatter, err = cmd.audit.newFormatter(cmd.audit.io.Output(), auditTable)
+			if err != nil {
+				return err
+			}
+		}
+
+		events, err := cursor.newEvents(iter, limit)
+		if err != nil {
+			return err
+		}
+
+		// Events are returned from the newest to the oldest, but are printed in the order in which they were logged.
+		for i := len(events) - 1; i >= 0; i-- {
+			row, err := auditTable.row(events[i])
+			if err != nil {
+				return err
+			}
+
+			err = formatter.Write(row)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !cmd.follow || !cmd.wait(ctx, cmd.interval) {
+			return nil
+		}
+		limit = -1
+	}
+}
+
+// waitContext waits for the given duration. It returns false when the context is done before that.
+func waitContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// auditCursor remembers the newest audit event that has been seen, so that only events
+// logged after it are returned. Because multiple events can be logged at the same time,
+// the IDs of all events logged at the time of the newest event are remembered.
+type auditCursor struct {
+	loggedAt time.Time
+	eventIDs map[string]bool
+}
+
+// isNew returns whether the event was logged after the cursor.
+func (c *auditCursor) isNew(event api.Audit) bool {
+	if c.eventIDs == nil {
+		return true
+	}
+	if event.LoggedAt.Equal(c.loggedAt) {
+		return !c.eventIDs[event.EventID.String()]
+	}
+	return event.LoggedAt.After(c.loggedAt)
+}
+
+// newEvents returns the events from the iterator that were logged after the cursor, from the newest
+// to the oldest, and moves the cursor to the newest event. At most limit events are returned, unless
+// limit is negative. The iterator must return the events from the newest to the oldest.
+func (c *auditCursor) newEvents(iter secrethub.AuditEventIterator, limit int) ([]api.Audit, error) {
+	next := *c
+	var events []api.Audit
+	for {
+		event, err := iter.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if !c.isNew(event) {
+			if event.LoggedAt.Before(c.loggedAt) {
+				break
+			}
+			continue
+		}
+
+		if next.eventIDs == nil || event.LoggedAt.After(next.loggedAt) {
+			next = auditCursor{loggedAt: event.LoggedAt, eventIDs: make(map[string]bool)}
+		}
+		if event.LoggedAt.Equal(next.loggedAt) {
+			next.eventIDs[event.EventID.String()] = true
+		}
+
+		if limit >= 0 && len(events) >= limit {
+			break
+		}
+		events = append(events, event)
+	}
+
+	*c = next
+	return events, nil
+}
@@ -0,0 +1,56 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestAuditCursor_newEvents(t *testing.T) {
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := func(loggedAt time.Time) api.Audit {
+		return api.Audit{EventID: uuid.New(), LoggedAt: loggedAt}
+	}
+
+	e1 := event(now)
+	e2 := event(now.Add(time.Second))
+	e3 := event(now.Add(2 * time.Second))
+	// e4 is logged at the same time as e3.
+	e4 := event(now.Add(2 * time.Second))
+	e5 := event(now.Add(3 * time.Second))
+
+	var cursor auditCursor
+
+	// The first check returns the most recent events up to the limit.
+	events, err := cursor.newEvents(&fakeclient.AuditEventIterator{Events: []api.Audit{e3, e2, e1}}, 2)
+	assert.OK(t, err)
+	assert.Equal(t, events, []api.Audit{e3, e2})
+
+	// Later checks only return events that were not seen before.
+	events, err = cursor.newEvents(&fakeclient.AuditEventIterator{Events: []api.Audit{e5, e4, e3, e2, e1}}, -1)
+	assert.OK(t, err)
+	assert.Equal(t, events, []api.Audit{e5, e4})
+
+	events, err = cursor.newEvents(&fakeclient.AuditEventIterator{Events: []api.Audit{e5, e4, e3, e2, e1}}, -1)
+	assert.OK(t, err)
+	assert.Equal(t, len(events), 0)
+}
+
+func TestAuditCursor_newEvents_noLines(t *testing.T) {
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	e1 := api.Audit{EventID: uuid.New(), LoggedAt: now}
+	e2 := api.Audit{EventID: uuid.New(), LoggedAt: now.Add(time.Second)}
+
+	var cursor auditCursor
+	events, err := cursor.newEvents(&fakeclient.AuditEventIterator{Events: []api.Audit{e1}}, 0)
+	assert.OK(t, err)
+	assert.Equal(t, len(events), 0)
+
+	events, err = cursor.newEvents(&fakeclient.AuditEventIterator{Events: []api.Audit{e2, e1}}, -1)
+	assert.OK(t, err)
+	assert.Equal(t, events, []api.Audit{e2})
+}
@@ -0,0 +1,115 @@
+package secrethub
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// Errors
+var (
+	ErrPathRequired       = errMain.Code("path_required").Error("a path is required. Give it as an argument or read paths from stdin with the --stdin-null-delimited flag")
+	ErrBatchPathArgument  = errMain.Code("batch_path_argument").Error("no path can be given as an argument when paths are read from stdin with the --stdin-null-delimited flag")
+	ErrBatchRequiresForce = errMain.Code("batch_requires_force").Error("paths read from stdin can only be removed in conjunction with the --force flag, because no confirmation can be asked")
+	ErrBatchFailed        = errMain.Code("batch_failed").ErrorPref("%d of %d paths failed")
+)
+
+// registerStdinNullDelimitedFlag registers the flag for reading paths from stdin
+// as a list of NUL-delimited paths. The path argument of the command is required
+// unless the flag is set, in which case it is not allowed. When the command can
+// also read paths from a file, the argument is not required when pathsFile is set.
+func registerStdinNullDelimitedFlag(clause *cli.CommandClause, enabled *bool, pathsFile *string) {
+	clause.Flags().BoolVar(enabled, "stdin-null-delimited", false, "Read the paths from stdin, separated by NUL characters (e.g. the output of find -print0), instead of from the arguments. The result for every path is written to stdout as a line of JSON.")
+	clause.AddPreRunE(func(_ *cobra.Command, args []string) error {
+		if *enabled && len(args) > 0 {
+			return ErrBatchPathArgument
+		}
+		if !*enabled && len(args) == 0 && (pathsFile == nil || *pathsFile == "") {
+			return ErrPathRequired
+		}
+		return nil
+	})
+}
+
+// batchResult is the result of processing a single path read from stdin.
+// It is written to the output as a single line of JSON.
+type batchResult struct {
+	Path  string  `json:"path"`
+	Value *string `json:"value,omitempty"`
+	Error string  `json:"error,omitempty"`
+}
+
+// runBatch reads NUL-delimited paths from the given reader and calls fn for
+// every path. For every path, the result is written to w as a line of JSON.
+// A failure for one path does not stop the processing of the other paths.
+// If processing any of the paths failed, an error is returned after all
+// paths have been processed.
+func runBatch(r io.Reader, w io.Writer, fn func(path string) (*string, error)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(scanNullDelimited)
+
+	encoder := json.NewEncoder(w)
+	total := 0
+	failed := 0
+	for scanner.Scan() {
+		path := scanner.Text()
+		if path == "" {
+			continue
+		}
+		total++
+
+		result := batchResult{Path: path}
+		value, err := fn(path)
+		if err != nil {
+			failed++
+			result.Error = err.Error()
+		} else {
+			result.Value = value
+		}
+
+		err = encoder.Encode(result)
+		if err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ui.ErrReadInput(err)
+	}
+
+	if failed > 0 {
+		return ErrBatchFailed(failed, total)
+	}
+	return nil
+}
+
+// scanNullDelimited is a bufio.SplitFunc that splits the input on NUL characters.
+// A final token that is not followed by a NUL character is also returned.
+func scanNullDelimited(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// discardOutputIO is a ui.IO that discards everything written to its output.
+// It is used to suppress the human-readable output of commands in batch mode.
+type discardOutputIO struct {
+	ui.IO
+}
+
+// Output returns a writer that discards everything written to it.
+func (discardOutputIO) Output() io.Writer {
+	return io.Discard
+}
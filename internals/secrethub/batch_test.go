@@ -0,0 +1,65 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestRunBatch(t *testing.T) {
+	secrets := map[string]string{
+		"namespace/repo/foo":           "foo",
+		"namespace/repo/dir with\nbar": "bar",
+	}
+	read := func(path string) (*string, error) {
+		value, ok := secrets[path]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return &value, nil
+	}
+
+	cases := map[string]struct {
+		in          string
+		expectedOut string
+		expectedErr error
+	}{
+		"empty": {
+			in:          "",
+			expectedOut: "",
+		},
+		"single without delimiter": {
+			in:          "namespace/repo/foo",
+			expectedOut: `{"path":"namespace/repo/foo","value":"foo"}` + "\n",
+		},
+		"multiple": {
+			in: "namespace/repo/foo\x00namespace/repo/dir with\nbar\x00",
+			expectedOut: `{"path":"namespace/repo/foo","value":"foo"}` + "\n" +
+				`{"path":"namespace/repo/dir with\nbar","value":"bar"}` + "\n",
+		},
+		"empty entries are skipped": {
+			in:          "\x00namespace/repo/foo\x00\x00",
+			expectedOut: `{"path":"namespace/repo/foo","value":"foo"}` + "\n",
+		},
+		"failure does not stop batch": {
+			in: "namespace/repo/missing\x00namespace/repo/foo\x00",
+			expectedOut: `{"path":"namespace/repo/missing","error":"not found"}` + "\n" +
+				`{"path":"namespace/repo/foo","value":"foo"}` + "\n",
+			expectedErr: ErrBatchFailed(1, 2),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			err := runBatch(strings.NewReader(tc.in), out, read)
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, out.String(), tc.expectedOut)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package secrethub
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/docker/go-units"
+)
+
+// BenchCommand measures the performance of local operations of the CLI.
+type BenchCommand struct {
+	io ui.IO
+}
+
+// NewBenchCommand creates a new BenchCommand.
+func NewBenchCommand(io ui.IO) *BenchCommand {
+	return &BenchCommand{
+		io: io,
+	}
+}
+
+// Errors
+var (
+	ErrInvalidBenchArgs = errMain.Code("invalid_bench_args").Error("all counts and sizes of a benchmark should be positive")
+)
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *BenchCommand) Register(r cli.Registerer) {
+	clause := r.Command("bench", "Measure the performance of the masker and the template engine on this machine.").Hidden()
+	NewBenchMaskCommand(cmd.io).Register(clause)
+	NewBenchTemplateCommand(cmd.io).Register(clause)
+}
+
+// byteSizeValue is a flag value for a size in bytes, given in human readable form, e.g. 100MB.
+type byteSizeValue int64
+
+func (v *byteSizeValue) Set(value string) error {
+	size, err := units.FromHumanSize(value)
+	if err != nil {
+		return err
+	}
+	*v = byteSizeValue(size)
+	return nil
+}
+
+func (v *byteSizeValue) String() string {
+	return units.HumanSize(float64(*v))
+}
+
+func (v *byteSizeValue) Type() string {
+	return "size"
+}
+
+// benchCharset is the set of characters used for generated secrets and data.
+const benchCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// randomBenchString returns a random string of the given length. A fixed seed is used
+// by the callers so that runs are comparable.
+func randomBenchString(rnd *rand.Rand, length int) string {
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = benchCharset[rnd.Intn(len(benchCharset))]
+	}
+	return string(b)
+}
+
+// printBenchResult writes a row with the result of a benchmark that processed
+// the given total amount of the unit (e.g. MB or templates) to w.
+func printBenchResult(w io.Writer, name string, duration time.Duration, total float64, unit string) {
+	perSecond := total / duration.Seconds()
+	fmt.Fprintf(w, "%s\t%s\t%.1f %s\t%.1f %s/s\n", name, duration.Round(time.Microsecond), total, unit, perSecond, unit)
+}
@@ -0,0 +1,104 @@
+package secrethub
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"text/tabwriter"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/masker"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/docker/go-units"
+)
+
+// BenchMaskCommand measures the throughput of the masker used by the run command.
+type BenchMaskCommand struct {
+	io           ui.IO
+	secretCount  int
+	secretLength int
+	size         byteSizeValue
+	writeSize    byteSizeValue
+	bufferDelay  time.Duration
+	disableBuf   bool
+	disableEnc   bool
+}
+
+// NewBenchMaskCommand creates a new BenchMaskCommand.
+func NewBenchMaskCommand(io ui.IO) *BenchMaskCommand {
+	return &BenchMaskCommand{
+		io:        io,
+		size:      100 * units.MB,
+		writeSize: 4 * units.KB,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *BenchMaskCommand) Register(r cli.Registerer) {
+	clause := r.Command("mask", "Measure the throughput of masking secrets in output, as done by the run command.")
+	clause.Flags().IntVar(&cmd.secretCount, "secrets", 100, "The number of secrets to mask.")
+	clause.Flags().IntVar(&cmd.secretLength, "secret-length", 32, "The length of every secret.")
+	clause.Flags().Var(&cmd.size, "size", "The total amount of output to mask, e.g. 100MB.")
+	clause.Flags().Var(&cmd.writeSize, "write-size", "The size of every write to the masked output, e.g. 4KB.")
+	clause.Flags().DurationVar(&cmd.bufferDelay, "buffer-delay", 0, "The masking buffer delay. Defaults to the default of the run command.")
+	clause.Flags().BoolVar(&cmd.disableBuf, "no-buffer", false, "Disable the masking buffer.")
+	clause.Flags().BoolVar(&cmd.disableEnc, "no-encodings", false, "Do not mask the encoded forms of the secrets.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run writes generated output containing the secrets through the masker and reports the throughput.
+func (cmd *BenchMaskCommand) Run() error {
+	if cmd.secretCount < 1 || cmd.secretLength < 1 || cmd.size < 1 || cmd.writeSize < 1 {
+		return ErrInvalidBenchArgs
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+
+	sequences := make([][]byte, cmd.secretCount)
+	for i := range sequences {
+		sequences[i] = []byte(randomBenchString(rnd, cmd.secretLength))
+	}
+
+	// Every write contains one of the secrets, the remainder is random data.
+	writes := make([][]byte, 64)
+	for i := range writes {
+		secret := sequences[rnd.Intn(len(sequences))]
+		data := []byte(randomBenchString(rnd, int(cmd.writeSize)))
+		if len(secret) < len(data) {
+			copy(data[rnd.Intn(len(data)-len(secret)+1):], secret)
+		}
+		writes[i] = data
+	}
+
+	m := masker.New(sequences, &masker.Options{
+		DisableBuffer:    cmd.disableBuf,
+		BufferDelay:      cmd.bufferDelay,
+		DisableEncodings: cmd.disableEnc,
+	})
+	stream := m.AddStream(io.Discard)
+	go m.Start()
+
+	start := time.Now()
+	written := 0
+	for i := 0; written < int(cmd.size); i++ {
+		n, err := stream.Write(writes[i%len(writes)])
+		if err != nil {
+			return err
+		}
+		written += n
+	}
+	err := m.Stop()
+	if err != nil {
+		return err
+	}
+	duration := time.Since(start)
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 4, 4, ' ', 0)
+	fmt.Fprintf(w, "BENCHMARK\tDURATION\tTOTAL\tTHROUGHPUT\n")
+	printBenchResult(w, "mask", duration, float64(written)/units.MB, "MB")
+	return w.Flush()
+}
@@ -0,0 +1,104 @@
+package secrethub
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// BenchTemplateCommand measures the performance of parsing and evaluating templates.
+type BenchTemplateCommand struct {
+	io              ui.IO
+	secretCount     int
+	iterations      int
+	templateVersion string
+}
+
+// NewBenchTemplateCommand creates a new BenchTemplateCommand.
+func NewBenchTemplateCommand(io ui.IO) *BenchTemplateCommand {
+	return &BenchTemplateCommand{
+		io: io,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *BenchTemplateCommand) Register(r cli.Registerer) {
+	clause := r.Command("template", "Measure the performance of parsing and evaluating templates, as done by the inject and run commands.")
+	clause.Flags().IntVar(&cmd.secretCount, "secrets", 100, "The number of secret tags in the template.")
+	clause.Flags().IntVar(&cmd.iterations, "iterations", 1000, "The number of times the template is parsed and evaluated.")
+	clause.Flags().StringVar(&cmd.templateVersion, "template-version", "latest", "The template syntax version to benchmark. The options are v1, v2, v3 and latest.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run parses and evaluates a generated template with in-memory secrets and reports the performance.
+func (cmd *BenchTemplateCommand) Run() error {
+	if cmd.secretCount < 1 || cmd.iterations < 1 {
+		return ErrInvalidBenchArgs
+	}
+
+	tagFormat := "{{ %s }}"
+	if cmd.templateVersion == "1" || cmd.templateVersion == "v1" {
+		tagFormat = "${ %s }"
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	secrets := make(benchSecretReader, cmd.secretCount)
+	var b strings.Builder
+	for i := 0; i < cmd.secretCount; i++ {
+		path := fmt.Sprintf("bench/repo/secret_%d", i)
+		secrets[path] = randomBenchString(rnd, 32)
+		fmt.Fprintf(&b, "KEY_%d = "+tagFormat+"\n", i, path)
+	}
+	raw := b.String()
+
+	parser, err := getTemplateParser([]byte(raw), cmd.templateVersion)
+	if err != nil {
+		return err
+	}
+
+	varReader, err := newVariableReader(nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var parseDuration, evalDuration time.Duration
+	for i := 0; i < cmd.iterations; i++ {
+		start := time.Now()
+		template, err := parser.Parse(raw, 1, 1)
+		if err != nil {
+			return err
+		}
+		parseDuration += time.Since(start)
+
+		start = time.Now()
+		_, err = template.Evaluate(varReader, secrets)
+		if err != nil {
+			return err
+		}
+		evalDuration += time.Since(start)
+	}
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 4, 4, ' ', 0)
+	fmt.Fprintf(w, "BENCHMARK\tDURATION\tTOTAL\tTHROUGHPUT\n")
+	printBenchResult(w, "parse", parseDuration, float64(cmd.iterations), "templates")
+	printBenchResult(w, "evaluate", evalDuration, float64(cmd.iterations), "templates")
+	return w.Flush()
+}
+
+// benchSecretReader reads secrets from memory.
+type benchSecretReader map[string]string
+
+func (sr benchSecretReader) ReadSecret(path string) (string, error) {
+	secret, ok := sr[path]
+	if !ok {
+		return "", ErrSecretNotFound(path)
+	}
+	return secret, nil
+}
@@ -0,0 +1,55 @@
+package secrethub
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// Errors
+var (
+	ErrNotBinarySecret        = errMain.Code("not_binary_secret").Error("the secret was not written with --binary")
+	ErrInvalidBinarySecret    = errMain.Code("invalid_binary_secret").ErrorPref("the binary secret is corrupt: %s")
+	ErrBinaryChecksumMismatch = errMain.Code("binary_checksum_mismatch").Error("the checksum of the binary secret does not match its contents, so it was modified or corrupted")
+)
+
+// binarySecretPrefix starts the value of secrets that are written with --binary.
+// It is followed by the hex encoded SHA-256 checksum of the binary data, a colon and the base64 encoded data.
+const binarySecretPrefix = "secrethub-binary:v1:sha256:"
+
+// encodeBinarySecret encodes binary data, so that it can be stored and printed as text, and records its checksum.
+func encodeBinarySecret(data []byte) []byte {
+	checksum := sha256.Sum256(data)
+	return []byte(binarySecretPrefix + hex.EncodeToString(checksum[:]) + ":" + base64.StdEncoding.EncodeToString(data))
+}
+
+// decodeBinarySecret decodes a secret that was written with --binary and validates its checksum.
+func decodeBinarySecret(value []byte) ([]byte, error) {
+	value = bytes.TrimSpace(value)
+	if !bytes.HasPrefix(value, []byte(binarySecretPrefix)) {
+		return nil, ErrNotBinarySecret
+	}
+
+	encoded := value[len(binarySecretPrefix):]
+	i := bytes.IndexByte(encoded, ':')
+	if i < 0 {
+		return nil, ErrInvalidBinarySecret("missing checksum")
+	}
+
+	checksum, err := hex.DecodeString(string(encoded[:i]))
+	if err != nil {
+		return nil, ErrInvalidBinarySecret(err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(string(encoded[i+1:]))
+	if err != nil {
+		return nil, ErrInvalidBinarySecret(err)
+	}
+
+	actual := sha256.Sum256(data)
+	if !bytes.Equal(checksum, actual[:]) {
+		return nil, ErrBinaryChecksumMismatch
+	}
+	return data, nil
+}
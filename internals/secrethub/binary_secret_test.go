@@ -0,0 +1,48 @@
+package secrethub
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestBinarySecret(t *testing.T) {
+	data := []byte{0x00, 0xff, '\n', ' ', 0x7f}
+
+	encoded := encodeBinarySecret(data)
+	assert.Equal(t, string(encoded), "secrethub-binary:v1:sha256:1ea4d510820624787a3db576c152e3d5353fdce5af24b1ac413f439a0b92e94c:AP8KIH8=")
+
+	decoded, err := decodeBinarySecret(encoded)
+	assert.OK(t, err)
+	assert.Equal(t, decoded, data)
+}
+
+func TestDecodeBinarySecret_Errors(t *testing.T) {
+	encoded := encodeBinarySecret([]byte("license"))
+
+	cases := map[string]struct {
+		value       []byte
+		expectedErr error
+	}{
+		"not binary": {
+			value:       []byte("license"),
+			expectedErr: ErrNotBinarySecret,
+		},
+		"modified": {
+			value:       bytes.Replace(encoded, []byte("bGljZW5zZQ=="), []byte("bGljZW5zZg=="), 1),
+			expectedErr: ErrBinaryChecksumMismatch,
+		},
+		"missing checksum": {
+			value:       []byte(binarySecretPrefix + "bGljZW5zZQ=="),
+			expectedErr: ErrInvalidBinarySecret("missing checksum"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeBinarySecret(tc.value)
+			assert.Equal(t, err, tc.expectedErr)
+		})
+	}
+}
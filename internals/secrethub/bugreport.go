@@ -0,0 +1,100 @@
+package secrethub
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// BugReportCommand prints information for a bug report, including the most recent crash report.
+type BugReportCommand struct {
+	io              ui.IO
+	credentialStore CredentialConfig
+	crashReporter   *CrashReporter
+	environ         func() []string
+}
+
+// NewBugReportCommand creates a new BugReportCommand.
+func NewBugReportCommand(io ui.IO, credentialStore CredentialConfig, crashReporter *CrashReporter) *BugReportCommand {
+	return &BugReportCommand{
+		io:              io,
+		credentialStore: credentialStore,
+		crashReporter:   crashReporter,
+		environ:         os.Environ,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *BugReportCommand) Register(r cli.Registerer) {
+	clause := r.Command("bugreport", "Print information about this installation and the most recent crash, to attach to a bug report.")
+	clause.HelpLong("The report contains the version of the CLI, the platform, whether a credential is configured, " +
+		"the names of the SECRETHUB_ environment variables that are set and the most recent crash report. " +
+		"It never contains the values of secrets or environment variables and paths are replaced with a hash. " +
+		"Nothing is sent automatically: review the output before you share it.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run prints the bug report.
+func (cmd *BugReportCommand) Run() error {
+	w := cmd.io.Output()
+
+	fmt.Fprintln(w, "Version:", Version+", build "+Commit)
+	fmt.Fprintf(w, "Platform: %s/%s, %s\n", runtime.GOOS, runtime.GOARCH, runtime.Version())
+
+	configDir := cmd.credentialStore.ConfigDir().Path()
+	configDirStatus := "present"
+	if _, err := os.Stat(configDir); err != nil {
+		configDirStatus = "missing"
+	}
+	fmt.Fprintf(w, "Configuration directory: %s (%s)\n", hashPath(configDir), configDirStatus)
+	credential := "missing"
+	if cmd.credentialStore.ConfigDir().Credential().Exists() {
+		credential = "present"
+	}
+	fmt.Fprintf(w, "Credential: %s\n", credential)
+	fmt.Fprintf(w, "Environment variables: %s\n", strings.Join(cmd.envVarNames(), ", "))
+
+	reports, err := cmd.crashReporter.reports()
+	if err != nil {
+		return err
+	}
+	if len(reports) == 0 {
+		fmt.Fprintln(w, "Crash reports: none")
+		return nil
+	}
+
+	latest := reports[len(reports)-1]
+	fmt.Fprintf(w, "Crash reports: %d, the most recent is %s\n\n", len(reports), filepath.Base(latest))
+	f, err := os.Open(latest)
+	if err != nil {
+		return ErrCannotReadFile(latest, err)
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// envVarNames returns the sorted names of the environment variables that configure the CLI.
+func (cmd *BugReportCommand) envVarNames() []string {
+	var names []string
+	for _, kv := range cmd.environ() {
+		name := strings.SplitN(kv, "=", 2)[0]
+		if strings.HasPrefix(name, "SECRETHUB_") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return []string{"none"}
+	}
+	return names
+}
@@ -0,0 +1,29 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// CacheCommand handles operations on the local secret cache.
+type CacheCommand struct {
+	io    ui.IO
+	cache *SecretCache
+}
+
+// NewCacheCommand creates a new CacheCommand.
+func NewCacheCommand(io ui.IO, cache *SecretCache) *CacheCommand {
+	return &CacheCommand{
+		io:    io,
+		cache: cache,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *CacheCommand) Register(r cli.Registerer) {
+	clause := r.Command("cache", "Manage the local secret cache.")
+	clause.HelpLong("The read, run and inject commands can serve secrets from an encrypted local cache with the --cache-ttl flag. " +
+		"The cache is stored in the configuration directory and is encrypted with the key of your credential.")
+	NewCacheClearCommand(cmd.io, cmd.cache).Register(clause)
+	NewCacheStatusCommand(cmd.io, cmd.cache).Register(clause)
+}
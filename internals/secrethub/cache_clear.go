@@ -0,0 +1,38 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// CacheClearCommand removes all secrets from the local secret cache.
+type CacheClearCommand struct {
+	io    ui.IO
+	cache *SecretCache
+}
+
+// NewCacheClearCommand creates a new CacheClearCommand.
+func NewCacheClearCommand(io ui.IO, cache *SecretCache) *CacheClearCommand {
+	return &CacheClearCommand{
+		io:    io,
+		cache: cache,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CacheClearCommand) Register(r cli.Registerer) {
+	clause := r.Command("clear", "Remove all secrets from the local secret cache.")
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run removes the cache file.
+func (cmd *CacheClearCommand) Run() error {
+	err := cmd.cache.clear()
+	if err != nil {
+		return err
+	}
+
+	ui.Successf(cmd.io.Output(), "The secret cache has been cleared.\n")
+	return nil
+}
@@ -0,0 +1,77 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/docker/go-units"
+)
+
+// CacheStatusCommand prints information about the local secret cache.
+type CacheStatusCommand struct {
+	io            ui.IO
+	cache         *SecretCache
+	timeFormatter TimeFormatter
+	useTimestamps bool
+}
+
+// NewCacheStatusCommand creates a new CacheStatusCommand.
+func NewCacheStatusCommand(io ui.IO, cache *SecretCache) *CacheStatusCommand {
+	return &CacheStatusCommand{
+		io:    io,
+		cache: cache,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CacheStatusCommand) Register(r cli.Registerer) {
+	clause := r.Command("status", "Show the location, size and age of the local secret cache.")
+	registerTimestampFlag(clause, &cmd.useTimestamps)
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run prints the status of the cache. The secrets in the cache are not
+// decrypted, so no credential is needed.
+func (cmd *CacheStatusCommand) Run() error {
+	cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps)
+
+	path := cmd.cache.path()
+	fmt.Fprintf(cmd.io.Output(), "Location:\t%s\n", path)
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		fmt.Fprintln(cmd.io.Output(), "Secrets:\t0")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	file, err := readSecretCacheFile(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Size:\t\t%s\n", units.HumanSize(float64(info.Size())))
+	fmt.Fprintf(cmd.io.Output(), "Secrets:\t%d\n", len(file.Secrets))
+	if len(file.Secrets) == 0 {
+		return nil
+	}
+
+	var oldest, newest time.Time
+	for _, entry := range file.Secrets {
+		if oldest.IsZero() || entry.FetchedAt.Before(oldest) {
+			oldest = entry.FetchedAt
+		}
+		if entry.FetchedAt.After(newest) {
+			newest = entry.FetchedAt
+		}
+	}
+	fmt.Fprintf(cmd.io.Output(), "Oldest:\t\t%s\n", cmd.timeFormatter.Format(oldest))
+	fmt.Fprintf(cmd.io.Output(), "Newest:\t\t%s\n", cmd.timeFormatter.Format(newest))
+	return nil
+}
@@ -0,0 +1,29 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// CICommand handles the integration with CI systems.
+type CICommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewCICommand creates a new CICommand.
+func NewCICommand(io ui.IO, newClient newClientFunc) *CICommand {
+	return &CICommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *CICommand) Register(r cli.Registerer) {
+	clause := r.Command("ci", "Pass secrets to the next steps of CI pipelines.")
+	for _, system := range ciSystems {
+		systemClause := clause.Command(system.name, "Pass secrets to the next steps of "+system.title+" jobs.")
+		NewCIExportCommand(cmd.io, cmd.newClient, system).Register(systemClause)
+	}
+}
@@ -0,0 +1,222 @@
+package secrethub
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// Errors
+var (
+	ErrCIFileNotSet     = errMain.Code("ci_file_not_set").ErrorPref("$%s is not set: run this command in a %s job or set --file")
+	ErrCIMultilineValue = errMain.Code("ci_multiline_value").ErrorPref("the value of %s contains a newline, which is not supported by %s")
+)
+
+// ciSystem describes how variables are passed to the next steps of a CI system.
+type ciSystem struct {
+	name  string
+	title string
+	help  string
+	// fileEnvar is the environment variable that the CI system sets to the file that defines variables for the next steps.
+	// It is empty when the file must be configured in the pipeline instead.
+	fileEnvar   string
+	defaultFile string
+	// appendToFile is set when the file is shared with other steps, so it must be appended to instead of overwritten.
+	appendToFile bool
+	// mask writes the command that masks the value in the log of the CI system.
+	// It is nil when values cannot be masked while a job is running.
+	mask func(w io.Writer, value string)
+	// format returns the definition of the variable in the file.
+	format func(name, value string) (string, error)
+}
+
+// ciSystems are the CI systems that variables can be exported to.
+var ciSystems = []ciSystem{
+	{
+		name:  "github-actions",
+		title: "GitHub Actions",
+		help: "The variables are appended to the file in $GITHUB_ENV, so they are set in the next steps of the job. " +
+			"Every value is masked in the log of the workflow with the add-mask workflow command.",
+		fileEnvar:    "GITHUB_ENV",
+		appendToFile: true,
+		mask:         maskGitHubActions,
+		format:       formatGitHubActions,
+	},
+	{
+		name:  "gitlab",
+		title: "GitLab CI/CD",
+		help: "The variables are written to a dotenv file, which must be declared as a dotenv report artifact of the job, so they are set in the jobs that depend on it:\n\n" +
+			"  artifacts:\n" +
+			"    reports:\n" +
+			"      dotenv: secrethub.env\n\n" +
+			"GitLab cannot mask values while a job is running, so take care that the values do not end up in the job log. " +
+			"Also note that everyone who can download the artifacts of the job can read the values.",
+		defaultFile: "secrethub.env",
+		format:      formatGitLab,
+	},
+	{
+		name:  "circleci",
+		title: "CircleCI",
+		help: "The variables are appended to the file in $BASH_ENV as export statements, so they are set in the next steps of the job. " +
+			"CircleCI only masks the values of project environment variables and contexts, so take care that the values do not end up in the job log.",
+		fileEnvar:    "BASH_ENV",
+		appendToFile: true,
+		format:       formatCircleCI,
+	},
+}
+
+// CIExportCommand resolves the environment and passes it to the next steps of a CI system.
+type CIExportCommand struct {
+	system      ciSystem
+	file        string
+	io          ui.IO
+	newClient   newClientFunc
+	environment *environment
+	getenv      func(string) string
+}
+
+// NewCIExportCommand creates a new CIExportCommand for the given CI system.
+func NewCIExportCommand(io ui.IO, newClient newClientFunc, system ciSystem) *CIExportCommand {
+	return &CIExportCommand{
+		system:      system,
+		io:          io,
+		newClient:   newClient,
+		environment: newEnvironment(io, newClient),
+		getenv:      os.Getenv,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CIExportCommand) Register(r cli.Registerer) {
+	clause := r.Command("export", "Resolve the environment and set it in the next steps of "+cmd.system.title+" jobs.")
+	clause.HelpLong("The environment is configured with the same flags as `secrethub run`. " +
+		"Only the variables that differ from the current environment are exported.\n\n" + cmd.system.help)
+
+	cmd.environment.register(clause)
+	fileHelp := "The file to write the variables to."
+	if cmd.system.fileEnvar != "" {
+		fileHelp += " Defaults to $" + cmd.system.fileEnvar + "."
+	}
+	clause.Flags().StringVar(&cmd.file, "file", cmd.system.defaultFile, fileHelp)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run resolves the environment and writes it to the file of the CI system.
+func (cmd *CIExportCommand) Run() error {
+	file := cmd.file
+	if file == "" {
+		file = cmd.getenv(cmd.system.fileEnvar)
+		if file == "" {
+			return ErrCIFileNotSet(cmd.system.fileEnvar, cmd.system.title)
+		}
+	}
+
+	envValues, err := cmd.environment.env()
+	if err != nil {
+		return err
+	}
+
+	resolved, err := cmd.environment.resolve(envValues, newCachingSecretReader(newSecretReader(cmd.newClient)))
+	if err != nil {
+		return err
+	}
+	cmd.environment.removeUnchanged(resolved)
+
+	names := make([]string, 0, len(resolved))
+	for name := range resolved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// All variables are formatted before anything is written, so that an unsupported value does not leave a partial file.
+	var definitions bytes.Buffer
+	for _, name := range names {
+		definition, err := cmd.system.format(name, resolved[name])
+		if err != nil {
+			return err
+		}
+		definitions.WriteString(definition)
+	}
+
+	// The values are masked before they are written, so they are masked in all output of the next steps.
+	if cmd.system.mask != nil {
+		for _, name := range names {
+			cmd.system.mask(cmd.io.Output(), resolved[name])
+		}
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if cmd.system.appendToFile {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := os.OpenFile(file, flags, 0600)
+	if err != nil {
+		return ErrCannotWrite(file, err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(definitions.Bytes())
+	if err != nil {
+		return ErrCannotWrite(file, err)
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Exported %d %s to %s.\n", len(names), pluralize("variable", "variables", len(names)), cmd.system.title)
+	return nil
+}
+
+// maskGitHubActions writes the add-mask workflow command for every line of the value.
+// A multiline value is masked per line, because the log is masked line by line.
+func maskGitHubActions(w io.Writer, value string) {
+	for _, line := range strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		line = strings.NewReplacer("%", "%25", "\r", "%0D").Replace(line)
+		fmt.Fprintf(w, "::add-mask::%s\n", line)
+	}
+}
+
+// formatGitHubActions returns the definition of the variable for $GITHUB_ENV.
+// A multiline value is defined with a random delimiter that does not occur in the value.
+func formatGitHubActions(name, value string) (string, error) {
+	if !strings.ContainsAny(value, "\r\n") {
+		return fmt.Sprintf("%s=%s\n", name, value), nil
+	}
+
+	for {
+		random := make([]byte, 16)
+		_, err := rand.Read(random)
+		if err != nil {
+			return "", err
+		}
+
+		delimiter := "ghadelimiter_" + hex.EncodeToString(random)
+		if !strings.Contains(value, delimiter) {
+			return fmt.Sprintf("%s<<%s\n%s\n%s\n", name, delimiter, value, delimiter), nil
+		}
+	}
+}
+
+// formatGitLab returns the definition of the variable in a dotenv report.
+// GitLab does not support multiline values in dotenv reports.
+func formatGitLab(name, value string) (string, error) {
+	if strings.ContainsAny(value, "\r\n") {
+		return "", ErrCIMultilineValue(name, "GitLab dotenv reports")
+	}
+	return fmt.Sprintf("%s=%s\n", name, value), nil
+}
+
+// formatCircleCI returns the statement in $BASH_ENV that exports the variable.
+func formatCircleCI(name, value string) (string, error) {
+	return exportBash(name, value) + "\n", nil
+}
@@ -0,0 +1,48 @@
+package secrethub
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestMaskGitHubActions(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	maskGitHubActions(buf, "100%\r\n\nsecond line")
+
+	assert.Equal(t, buf.String(), "::add-mask::100%25\n::add-mask::second line\n")
+}
+
+func TestFormatGitHubActions(t *testing.T) {
+	actual, err := formatGitHubActions("FOO", "foo")
+	assert.OK(t, err)
+	assert.Equal(t, actual, "FOO=foo\n")
+
+	actual, err = formatGitHubActions("FOO", "first\nsecond")
+	assert.OK(t, err)
+
+	lines := strings.Split(actual, "\n")
+	assert.Equal(t, len(lines), 5)
+	assert.Equal(t, strings.HasPrefix(lines[0], "FOO<<ghadelimiter_"), true)
+	assert.Equal(t, lines[1:3], []string{"first", "second"})
+	assert.Equal(t, lines[3], strings.TrimPrefix(lines[0], "FOO<<"))
+	assert.Equal(t, lines[4], "")
+}
+
+func TestFormatGitLab(t *testing.T) {
+	actual, err := formatGitLab("FOO", "foo")
+	assert.OK(t, err)
+	assert.Equal(t, actual, "FOO=foo\n")
+
+	_, err = formatGitLab("FOO", "first\nsecond")
+	assert.Equal(t, err, ErrCIMultilineValue("FOO", "GitLab dotenv reports"))
+}
+
+func TestFormatCircleCI(t *testing.T) {
+	actual, err := formatCircleCI("FOO", "it's")
+	assert.OK(t, err)
+	assert.Equal(t, actual, "export FOO='it'\\''s'\n")
+}
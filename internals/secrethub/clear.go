@@ -3,6 +3,7 @@ package secrethub
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
@@ -12,8 +13,9 @@ import (
 
 // ClearCommand clears the secrets from the system.
 type ClearCommand struct {
-	in string
-	io ui.IO
+	in  string
+	all bool
+	io  ui.IO
 }
 
 // NewClearCommand creates a new ClearCommand.
@@ -27,6 +29,7 @@ func NewClearCommand(io ui.IO) *ClearCommand {
 func (cmd *ClearCommand) Register(r cli.Registerer) {
 	clause := r.Command("clear", "Clear the secrets from your local environment. This reads and parses the secrets.yml file in the current working directory.").Hidden()
 	clause.Flags().StringVarP(&cmd.in, "in", "i", "secrets.yml", "The path to a secrets.yml file to read")
+	clause.Flags().BoolVar(&cmd.all, "all", false, "Securely delete every environment in the "+secretspec.SecretEnvPath+" directory, including environments that are no longer declared in the secrets.yml file.")
 
 	clause.BindAction(cmd.Run)
 	clause.BindArguments(nil)
@@ -34,6 +37,10 @@ func (cmd *ClearCommand) Register(r cli.Registerer) {
 
 // Run clears the secrets from the system.
 func (cmd *ClearCommand) Run() error {
+	if cmd.all {
+		return cmd.clearAllEnvs()
+	}
+
 	presenter, err := secretspec.NewPresenter("", true, secretspec.DefaultParsers...)
 	if err != nil {
 		return err
@@ -61,7 +68,51 @@ func (cmd *ClearCommand) Run() error {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Clear complete! The secrets are no longer available on the system.\n")
+	ui.Successf(cmd.io.Output(), "Clear complete! The secrets are no longer available on the system.\n")
 
 	return nil
 }
+
+// clearAllEnvs securely deletes every environment prepared by the set command.
+func (cmd *ClearCommand) clearAllEnvs() error {
+	envs, err := os.ReadDir(secretspec.SecretEnvPath)
+	if os.IsNotExist(err) {
+		fmt.Fprintln(cmd.io.Output(), "There are no environments to clear.")
+		return nil
+	} else if err != nil {
+		return ErrReadEnvDir(err)
+	}
+
+	for _, env := range envs {
+		dirPath := filepath.Join(secretspec.SecretEnvPath, env.Name())
+		if !env.IsDir() {
+			continue
+		}
+
+		files, err := os.ReadDir(dirPath)
+		if err != nil {
+			return ErrReadEnvDir(err)
+		}
+		for _, file := range files {
+			if !file.Type().IsRegular() {
+				continue
+			}
+			err = shredFile(filepath.Join(dirPath, file.Name()))
+			if err != nil {
+				return secretspec.ErrCannotClearEnvironmentVariable(err)
+			}
+		}
+
+		err = os.RemoveAll(dirPath)
+		if err != nil {
+			return secretspec.ErrCannotClearEnvironmentVariable(err)
+		}
+		fmt.Fprintf(cmd.io.Output(), "Cleared environment %s.\n", env.Name())
+	}
+
+	err = os.Remove(secretspec.SecretEnvPath)
+	if err != nil && !os.IsNotExist(err) {
+		return secretspec.ErrCannotClearEnvironmentVariable(err)
+	}
+	return nil
+}
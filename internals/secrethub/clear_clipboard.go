@@ -16,15 +16,21 @@ const clearClipboardAfter = 45 * time.Second
 
 // ClearClipboardCommand is a command to clear the contents of the clipboard after some time passed.
 type ClearClipboardCommand struct {
-	clipper clip.Clipper
-	hash    cli.ByteValue
-	timeout time.Duration
+	clipper           clip.Clipper
+	pending           *PendingClipboardClearStore
+	removeFromHistory func(matches func([]byte) bool) int
+	hash              cli.ByteValue
+	timeout           time.Duration
+	now               func() time.Time
 }
 
 // NewClearClipboardCommand creates a new ClearClipboardCommand.
-func NewClearClipboardCommand() *ClearClipboardCommand {
+func NewClearClipboardCommand(store CredentialConfig) *ClearClipboardCommand {
 	return &ClearClipboardCommand{
-		clipper: clip.NewClipboard(),
+		clipper:           clip.NewClipboard(),
+		pending:           NewPendingClipboardClearStore(store),
+		removeFromHistory: clip.RemoveFromHistory,
+		now:               time.Now,
 	}
 }
 
@@ -35,24 +41,49 @@ func (cmd *ClearClipboardCommand) Register(r cli.Registerer) {
 
 	clause.BindAction(cmd.Run)
 	clause.BindArguments([]cli.Argument{
-		{Value: &cmd.hash, Name: "hash", Required: true, Description: "Hash from the secret to be cleared."},
+		{Value: &cmd.hash, Name: "hash", Required: true, Description: "Hex encoded bcrypt hash of the secret to be cleared."},
 	})
 }
 
 // Run handles the command with the options as specified in the command.
+// The clipboard is only cleared when it still contains the secret, but the secret
+// is always removed from the history of clipboard managers.
 func (cmd *ClearClipboardCommand) Run() error {
+	hash, err := hex.DecodeString(string(cmd.hash))
+	if err != nil {
+		return err
+	}
+	matches := func(value []byte) bool {
+		return bcrypt.CompareHashAndPassword(hash, value) == nil
+	}
+
+	// Keeping track of the pending clear is a best effort, so that the clipboard
+	// is still cleared when the configuration directory cannot be written.
+	now := cmd.now()
+	_ = cmd.pending.add(pendingClipboardClear{
+		Hash:     string(cmd.hash),
+		CopiedAt: now,
+		ClearAt:  now.Add(cmd.timeout),
+		Backend:  clip.Backend(),
+	})
+	defer func() {
+		_ = cmd.pending.remove(string(cmd.hash))
+	}()
+
 	if cmd.timeout > 0 {
 		time.Sleep(cmd.timeout)
 	}
+	defer cmd.removeFromHistory(matches)
 
+	// When the clipboard cannot be read, it is cleared without checking whether it still contains the secret.
 	read, err := cmd.clipper.ReadAll()
-	if err != nil {
-		return err
-	}
-
-	err = bcrypt.CompareHashAndPassword(cmd.hash, read)
-	if err != nil {
-		return nil
+	if err != clip.ErrReadNotSupported {
+		if err != nil {
+			return err
+		}
+		if !matches(read) {
+			return nil
+		}
 	}
 
 	err = cmd.clipper.WriteAll(nil)
@@ -82,9 +113,11 @@ func (clipWriter *ClipboardWriterAutoClear) Write(data []byte) error {
 		return err
 	}
 
+	// The backend is passed on, so that the same clipboard is cleared when the backend was selected with a flag.
 	err = cloneproc.Spawn(
 		"clipboard-clear", hex.EncodeToString(hash),
-		"--timeout", clearClipboardAfter.String())
+		"--timeout", clearClipboardAfter.String(),
+		"--clipboard-backend", clip.Backend())
 
 	return err
 }
@@ -0,0 +1,138 @@
+package secrethub
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// treeCacheKey identifies a request for a directory tree.
+type treeCacheKey struct {
+	path      string
+	depth     int
+	ancestors bool
+}
+
+// treeCache memoizes the directory trees that are fetched during a single invocation of
+// the CLI, so that helpers that need the same tree only fetch it once. Trees are shared
+// between callers, so they must not be modified.
+type treeCache struct {
+	mutex sync.Mutex
+	trees map[treeCacheKey]*api.Tree
+}
+
+func newTreeCache() *treeCache {
+	return &treeCache{
+		trees: make(map[treeCacheKey]*api.Tree),
+	}
+}
+
+// get returns the cached tree or fetches it with the given function.
+func (c *treeCache) get(key treeCacheKey, fetch func() (*api.Tree, error)) (*api.Tree, error) {
+	key.path = strings.ToLower(strings.TrimSuffix(key.path, "/"))
+
+	c.mutex.Lock()
+	tree, ok := c.trees[key]
+	c.mutex.Unlock()
+	if ok {
+		return tree, nil
+	}
+
+	tree, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.trees[key] = tree
+	c.mutex.Unlock()
+	return tree, nil
+}
+
+// clear removes all trees from the cache. It is called after every change to
+// directories or secrets, because the change can be part of any cached tree.
+func (c *treeCache) clear() {
+	c.mutex.Lock()
+	c.trees = make(map[treeCacheKey]*api.Tree)
+	c.mutex.Unlock()
+}
+
+// treeCachingClient is a client that memoizes the directory trees it fetches.
+type treeCachingClient struct {
+	secrethub.ClientInterface
+	cache *treeCache
+}
+
+// newTreeCachingClient wraps the client to memoize the directory trees it fetches.
+func newTreeCachingClient(client secrethub.ClientInterface) *treeCachingClient {
+	return &treeCachingClient{
+		ClientInterface: client,
+		cache:           newTreeCache(),
+	}
+}
+
+// Dirs returns a DirService that memoizes the trees it fetches.
+func (c *treeCachingClient) Dirs() secrethub.DirService {
+	return &treeCachingDirService{
+		DirService: c.ClientInterface.Dirs(),
+		cache:      c.cache,
+	}
+}
+
+// Secrets returns a SecretService that clears the cached trees when secrets change.
+func (c *treeCachingClient) Secrets() secrethub.SecretService {
+	return &treeCachingSecretService{
+		SecretService: c.ClientInterface.Secrets(),
+		cache:         c.cache,
+	}
+}
+
+type treeCachingDirService struct {
+	secrethub.DirService
+	cache *treeCache
+}
+
+// GetTree returns the tree from the cache or fetches it when it is not cached yet.
+func (s *treeCachingDirService) GetTree(path string, depth int, ancestors bool) (*api.Tree, error) {
+	key := treeCacheKey{path: path, depth: depth, ancestors: ancestors}
+	return s.cache.get(key, func() (*api.Tree, error) {
+		return s.DirService.GetTree(path, depth, ancestors)
+	})
+}
+
+// Create creates a directory and clears the cached trees.
+func (s *treeCachingDirService) Create(path string) (*api.Dir, error) {
+	defer s.cache.clear()
+	return s.DirService.Create(path)
+}
+
+// CreateAll creates a directory and its parents and clears the cached trees.
+func (s *treeCachingDirService) CreateAll(path string) error {
+	defer s.cache.clear()
+	return s.DirService.CreateAll(path)
+}
+
+// Delete removes a directory and clears the cached trees.
+func (s *treeCachingDirService) Delete(path string) error {
+	defer s.cache.clear()
+	return s.DirService.Delete(path)
+}
+
+type treeCachingSecretService struct {
+	secrethub.SecretService
+	cache *treeCache
+}
+
+// Write writes a secret and clears the cached trees.
+func (s *treeCachingSecretService) Write(path string, data []byte) (*api.SecretVersion, error) {
+	defer s.cache.clear()
+	return s.SecretService.Write(path, data)
+}
+
+// Delete removes a secret and clears the cached trees.
+func (s *treeCachingSecretService) Delete(path string) error {
+	defer s.cache.clear()
+	return s.SecretService.Delete(path)
+}
@@ -0,0 +1,45 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestTreeCachingClient(t *testing.T) {
+	var fetched []string
+	client := newTreeCachingClient(fakeclient.Client{
+		DirService: &fakeclient.DirService{
+			GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				fetched = append(fetched, path)
+				return &api.Tree{}, nil
+			},
+		},
+		SecretService: &fakeclient.SecretService{
+			WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+				return &api.SecretVersion{}, nil
+			},
+		},
+	})
+
+	first, err := client.Dirs().GetTree("namespace/repo", -1, false)
+	assert.OK(t, err)
+	second, err := client.Dirs().GetTree("Namespace/Repo/", -1, false)
+	assert.OK(t, err)
+	assert.Equal(t, first == second, true)
+	assert.Equal(t, fetched, []string{"namespace/repo"})
+
+	// A different depth is fetched separately.
+	_, err = client.Dirs().GetTree("namespace/repo", 1, false)
+	assert.OK(t, err)
+	assert.Equal(t, fetched, []string{"namespace/repo", "namespace/repo"})
+
+	// Writing a secret clears the cache.
+	_, err = client.Secrets().Write("namespace/repo/secret", []byte("value"))
+	assert.OK(t, err)
+	_, err = client.Dirs().GetTree("namespace/repo", -1, false)
+	assert.OK(t, err)
+	assert.Equal(t, fetched, []string{"namespace/repo", "namespace/repo", "namespace/repo"})
+}
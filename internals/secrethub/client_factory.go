@@ -1,12 +1,20 @@
 package secrethub
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
 
+	"github.com/spf13/cobra"
+
 	"github.com/secrethub/secrethub-go/pkg/secrethub"
 	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
 	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
@@ -15,6 +23,10 @@ import (
 // Errors
 var (
 	ErrUnknownIdentityProvider = errMain.Code("unknown_identity_provider").ErrorPref("%s is not a supported identity provider. Valid options are `aws`, `gcp` and `key`.")
+	ErrInvalidAPITimeout       = errMain.Code("invalid_api_timeout").ErrorPref("the API timeout cannot be negative, got %s")
+	ErrInvalidAPIRetries       = errMain.Code("invalid_api_retries").ErrorPref("the number of API retries cannot be negative, got %d")
+	ErrInvalidAPIRetryBackoff  = errMain.Code("invalid_api_retry_backoff").ErrorPref("the API retry backoff cannot be negative, got %s")
+	ErrInvalidTLSCACert        = errMain.Code("invalid_tls_ca_cert").ErrorPref("%s does not contain any PEM encoded certificates")
 )
 
 // ClientFactory handles creating a new client with the configured options.
@@ -22,35 +34,118 @@ type ClientFactory interface {
 	// NewClient returns a new SecretHub client.
 	NewClient() (secrethub.ClientInterface, error)
 	NewClientWithCredentials(credentials.Provider) (secrethub.ClientInterface, error)
+	// Transport returns the transport that the clients use to send requests to the API.
+	Transport() http.RoundTripper
+	// APIRemote returns the address of the API.
+	APIRemote() string
+	// Proxy returns the address of the proxy that requests to the API go through, or nil when no proxy is used.
+	Proxy() (*url.URL, error)
 	Register(app *cli.App)
 }
 
+// defaultAPIRemote is the address of the API that is used when --api-remote is not set.
+const defaultAPIRemote = "https://api.secrethub.io"
+
 // NewClientFactory creates a new ClientFactory.
-func NewClientFactory(store CredentialConfig) ClientFactory {
+func NewClientFactory(store CredentialConfig, metrics *Metrics) ClientFactory {
 	return &clientFactory{
-		store: store,
+		store:   store,
+		metrics: metrics,
 	}
 }
 
 type clientFactory struct {
-	client           *secrethub.Client
+	client           secrethub.ClientInterface
+	clientMutex      sync.Mutex
 	ServerURL        urlValue
 	identityProvider string
 	proxyAddress     urlValue
+	apiTimeout       time.Duration
+	apiRetries       int
+	apiRetryBackoff  time.Duration
+	tlsCACert        string
+	tlsSkipVerify    bool
+	rootCAs          *x509.CertPool
+	debugHTTP        bool
+	traceID          string
 	store            CredentialConfig
+	metrics          *Metrics
 }
 
+// Default values of the flags that configure how requests to the API are retried.
+const (
+	defaultAPIRetries      = 3
+	defaultAPIRetryBackoff = 500 * time.Millisecond
+)
+
 // Register the flags for configuration on a cli application.
 // The environment variables of these flags are also checked on the client, but checking them here allows us to fail fast.
 func (f *clientFactory) Register(app *cli.App) {
 	app.PersistentFlags().VarPF(&f.ServerURL, "api-remote", "", "The SecretHub API address, don't set this unless you know what you're doing.").Hidden()
 	app.PersistentFlags().StringVar(&f.identityProvider, "identity-provider", "key", "Enable native authentication with a trusted identity provider. Options are `aws` (IAM + KMS), `gcp` (IAM + KMS) and `key`. When you run the CLI on one of the platforms, you can leverage their respective identity providers to do native keyless authentication. Defaults to key, which uses the default credential sourced from a file, command-line flag, or environment variable.")
 	app.PersistentFlags().VarPF(&f.proxyAddress, "proxy-address", "", "Set to the address of a proxy to connect to the API through a proxy. The prepended scheme determines the proxy type (http, https and socks5 are supported). For example: `--proxy-address http://my-proxy:1234`")
+	app.PersistentFlags().StringVar(&f.tlsCACert, "tls-ca-cert", "", "The path to a PEM encoded CA certificate that is trusted in addition to the system's CA certificates when connecting to the API. Use this when a proxy intercepts TLS connections.")
+	app.PersistentFlags().BoolVar(&f.tlsSkipVerify, "tls-skip-verify", false, "Do not verify the TLS certificate of the API. This makes the connection vulnerable to interception, so only use this for debugging. Prefer --tls-ca-cert instead.")
+	app.PersistentFlags().BoolVar(&f.debugHTTP, "debug-http", false, "Write the method, path, status, duration and request ID of every request to the API to stderr. Request and response bodies are never written. All requests share a trace ID, which can be given to support to look into failures and latency.")
+	app.PersistentFlags().DurationVar(&f.apiTimeout, "api-timeout", 0, "The maximum duration of a single request to the API, e.g. 10s. Requests that take longer are retried. There is no limit when set to 0.")
+	app.PersistentFlags().IntVar(&f.apiRetries, "api-retries", defaultAPIRetries, "The number of times a request to the API is retried when it fails with a network error, a server error or because of rate limiting. Set to 0 to disable retries.")
+	app.PersistentFlags().DurationVar(&f.apiRetryBackoff, "api-retry-backoff", defaultAPIRetryBackoff, "The time to wait before the first retry of a request to the API. The time doubles with every retry and is randomized to spread out retries.")
+	app.Root.AddPersistentPreRunE(func(_ *cobra.Command, _ []string) error {
+		if f.apiTimeout < 0 {
+			return ErrInvalidAPITimeout(f.apiTimeout)
+		}
+		if f.apiRetries < 0 {
+			return ErrInvalidAPIRetries(f.apiRetries)
+		}
+		if f.apiRetryBackoff < 0 {
+			return ErrInvalidAPIRetryBackoff(f.apiRetryBackoff)
+		}
+		if f.debugHTTP {
+			traceID, err := newTraceID()
+			if err != nil {
+				return err
+			}
+			f.traceID = traceID
+			fmt.Fprintf(os.Stderr, "HTTP: trace ID %s\n", f.traceID)
+		}
+		if f.tlsSkipVerify {
+			logger.Warningf("TLS certificate verification is disabled, so the connection to the API is not protected against interception.")
+		}
+		return f.loadCACert()
+	})
+}
+
+// loadCACert adds the certificates in the file set with --tls-ca-cert to the system's CA certificates.
+func (f *clientFactory) loadCACert() error {
+	if f.tlsCACert == "" {
+		return nil
+	}
+
+	pem, err := os.ReadFile(f.tlsCACert)
+	if err != nil {
+		return ErrReadFile(f.tlsCACert, err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		logger.Debugf("could not load the system's CA certificates: %s", err)
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return ErrInvalidTLSCACert(f.tlsCACert)
+	}
+	f.rootCAs = pool
+	return nil
 }
 
 // NewClient returns a new client that is configured to use the remote that
-// is set with the flag.
+// is set with the flag. The client is created once and shared by all callers.
+// It is safe to call NewClient concurrently, e.g. from the workers that resolve
+// secrets. They wait for the first call, so the passphrase is asked only once.
 func (f *clientFactory) NewClient() (secrethub.ClientInterface, error) {
+	f.clientMutex.Lock()
+	defer f.clientMutex.Unlock()
+
 	if f.client == nil {
 		var credentialProvider credentials.Provider
 		switch strings.ToLower(f.identityProvider) {
@@ -73,7 +168,12 @@ func (f *clientFactory) NewClient() (secrethub.ClientInterface, error) {
 		} else if err != nil {
 			return nil, err
 		}
-		f.client = client
+		var wrapped secrethub.ClientInterface = client
+		// When the credential is unlocked in the agent, secrets are read through the agent.
+		if agentProvider, ok := credentialProvider.(*agentProvider); ok {
+			wrapped = newAgentReadingClient(client, agentProvider)
+		}
+		f.client = newTreeCachingClient(f.wrapMetrics(wrapped))
 	}
 	return f.client, nil
 }
@@ -87,7 +187,18 @@ func (f *clientFactory) NewClientWithCredentials(provider credentials.Provider)
 		return nil, err
 	}
 
-	return client, nil
+	return newTreeCachingClient(f.wrapMetrics(client)), nil
+}
+
+// wrapMetrics wraps the client to count the resolved secrets, when metrics are enabled.
+func (f *clientFactory) wrapMetrics(client secrethub.ClientInterface) secrethub.ClientInterface {
+	if !f.metrics.enabled() {
+		return client
+	}
+	return &metricsClient{
+		ClientInterface: client,
+		metrics:         f.metrics,
+	}
 }
 
 func (f *clientFactory) baseClientOptions() []secrethub.ClientOption {
@@ -99,13 +210,7 @@ func (f *clientFactory) baseClientOptions() []secrethub.ClientOption {
 		}),
 	}
 
-	if f.proxyAddress.u != nil {
-		transport := http.DefaultTransport.(*http.Transport)
-		transport.Proxy = func(request *http.Request) (*url.URL, error) {
-			return f.proxyAddress.u, nil
-		}
-		options = append(options, secrethub.WithTransport(transport))
-	}
+	options = append(options, secrethub.WithTransport(f.Transport()))
 
 	if f.ServerURL.u != nil {
 		options = append(options, secrethub.WithServerURL(f.ServerURL.String()))
@@ -114,6 +219,62 @@ func (f *clientFactory) baseClientOptions() []secrethub.ClientOption {
 	return options
 }
 
+// Transport returns the transport that is configured with the flags.
+func (f *clientFactory) Transport() http.RoundTripper {
+	var transport http.RoundTripper = http.DefaultTransport
+	if f.proxyAddress.u != nil || f.rootCAs != nil || f.tlsSkipVerify {
+		// The default transport is cloned, so that it is not changed for other users of it.
+		httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+		if f.proxyAddress.u != nil {
+			httpTransport.Proxy = func(request *http.Request) (*url.URL, error) {
+				return f.proxyAddress.u, nil
+			}
+		}
+		if f.rootCAs != nil || f.tlsSkipVerify {
+			httpTransport.TLSClientConfig = &tls.Config{
+				RootCAs:            f.rootCAs,
+				InsecureSkipVerify: f.tlsSkipVerify,
+			}
+		}
+		transport = httpTransport
+	}
+
+	// Every attempt is counted in the metrics, so that retries are reported.
+	if f.metrics.enabled() {
+		transport = f.metrics.Transport(transport)
+	}
+
+	if f.debugHTTP {
+		transport = newTraceTransport(transport, os.Stderr, f.traceID)
+	}
+
+	if f.apiRetries > 0 || f.apiTimeout > 0 {
+		transport = newRetryTransport(transport, f.apiTimeout, f.apiRetries, f.apiRetryBackoff)
+	}
+	return transport
+}
+
+// APIRemote returns the address of the API that is set with --api-remote, or the default address.
+func (f *clientFactory) APIRemote() string {
+	if f.ServerURL.u != nil {
+		return f.ServerURL.String()
+	}
+	return defaultAPIRemote
+}
+
+// Proxy returns the proxy that is set with --proxy-address or, when it is not set,
+// with the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+func (f *clientFactory) Proxy() (*url.URL, error) {
+	if f.proxyAddress.u != nil {
+		return f.proxyAddress.u, nil
+	}
+	req, err := http.NewRequest(http.MethodGet, f.APIRemote(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
 type urlValue struct {
 	u *url.URL
 }
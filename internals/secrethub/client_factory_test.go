@@ -1,14 +1,26 @@
 package secrethub
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/assert"
 	"github.com/secrethub/secrethub-go/internals/auth"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
 	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
 	httpclient "github.com/secrethub/secrethub-go/pkg/secrethub/internals/http"
 
@@ -66,3 +78,93 @@ func (n nopDecrypter) Unwrap(ciphertext *api.EncryptedData) ([]byte, error) {
 func (d dummyCredential) Provide(client *httpclient.Client) (auth.Authenticator, credentials.Decrypter, error) {
 	return auth.NopAuthenticator{}, nopDecrypter{}, nil
 }
+
+// countingCredentialConfig is a credential store that counts how often a credential is provided.
+type countingCredentialConfig struct {
+	CredentialConfig
+	provided int32
+}
+
+func (c *countingCredentialConfig) Provider() credentials.Provider {
+	atomic.AddInt32(&c.provided, 1)
+	return dummyCredential{}
+}
+
+func TestClientFactory_NewClient_Concurrent(t *testing.T) {
+	store := &countingCredentialConfig{
+		CredentialConfig: NewCredentialConfig(ui.NewUserIO()),
+	}
+	factory := clientFactory{
+		identityProvider: "key",
+		store:            store,
+	}
+
+	const workers = 10
+	clients := make([]secrethub.ClientInterface, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clients[i], errs[i] = factory.NewClient()
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < workers; i++ {
+		assert.OK(t, errs[i])
+		assert.Equal(t, clients[i] == clients[0], true)
+	}
+	assert.Equal(t, store.provided, int32(1))
+}
+
+func TestClientFactory_loadCACert(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.OK(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Intercepting Proxy CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.OK(t, err)
+
+	caCert := filepath.Join(dir, "ca.pem")
+	assert.OK(t, os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	noCert := filepath.Join(dir, "empty.pem")
+	assert.OK(t, os.WriteFile(noCert, []byte("not a certificate"), 0600))
+
+	cases := map[string]struct {
+		path        string
+		expectedCAs bool
+		err         error
+	}{
+		"not set": {},
+		"ca cert": {
+			path:        caCert,
+			expectedCAs: true,
+		},
+		"no certificates": {
+			path: noCert,
+			err:  ErrInvalidTLSCACert(noCert),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			factory := clientFactory{tlsCACert: tc.path}
+
+			err := factory.loadCACert()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, factory.rootCAs != nil, tc.expectedCAs)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+const (
+	pendingClipboardClearsFileName = "clipboard_pending.json"
+
+	// pendingClipboardClearGrace is how long a pending clear is kept after the time it should
+	// have been cleared. A clear that is still pending after that is assumed to have been killed.
+	pendingClipboardClearGrace = time.Minute
+)
+
+// Errors
+var (
+	ErrInvalidPendingClipboardClears = errMain.Code("invalid_pending_clipboard_clears").ErrorPref("could not parse the pending clipboard clears file %s: %s")
+)
+
+// ClipboardCommand handles the secrets that are copied to the clipboard.
+type ClipboardCommand struct {
+	io    ui.IO
+	store CredentialConfig
+}
+
+// NewClipboardCommand creates a new ClipboardCommand.
+func NewClipboardCommand(io ui.IO, store CredentialConfig) *ClipboardCommand {
+	return &ClipboardCommand{
+		io:    io,
+		store: store,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *ClipboardCommand) Register(r cli.Registerer) {
+	clause := r.Command("clipboard", "Manage the secrets that are copied to the clipboard.")
+	clause.HelpLong("A secret that is copied to the clipboard with --clip is cleared after " + clearClipboardAfter.String() + ", " +
+		"unless something else has been copied in the meantime. " +
+		"It is also removed from the history of the CopyQ and cliphist clipboard managers when they are installed.")
+	NewClipboardStatusCommand(cmd.io, NewPendingClipboardClearStore(cmd.store)).Register(clause)
+}
+
+// pendingClipboardClear is a secret that has been copied to the clipboard and that is yet to be cleared.
+// Only the bcrypt hash of the secret is stored.
+type pendingClipboardClear struct {
+	Hash     string    `json:"hash"`
+	CopiedAt time.Time `json:"copied_at"`
+	ClearAt  time.Time `json:"clear_at"`
+	Backend  string    `json:"backend"`
+}
+
+// PendingClipboardClearStore keeps track of the secrets that are pending clearance from the clipboard
+// in the configuration directory, so that they can be shown by another process than the one clearing them.
+type PendingClipboardClearStore struct {
+	dir func() string
+	now func() time.Time
+}
+
+// NewPendingClipboardClearStore creates a new PendingClipboardClearStore that stores the pending
+// clears in the configuration directory of the given credential config.
+func NewPendingClipboardClearStore(store CredentialConfig) *PendingClipboardClearStore {
+	return &PendingClipboardClearStore{
+		dir: func() string {
+			return store.ConfigDir().Path()
+		},
+		now: time.Now,
+	}
+}
+
+// path returns the location of the pending clears file.
+func (s *PendingClipboardClearStore) path() string {
+	return filepath.Join(s.dir(), pendingClipboardClearsFileName)
+}
+
+// list returns the pending clears, the last copied first.
+// Clears that should have happened more than the grace period ago are left out.
+func (s *PendingClipboardClearStore) list() ([]pendingClipboardClear, error) {
+	raw, err := os.ReadFile(s.path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, ErrCannotReadFile(s.path(), err)
+	}
+
+	var clears []pendingClipboardClear
+	err = json.Unmarshal(raw, &clears)
+	if err != nil {
+		return nil, ErrInvalidPendingClipboardClears(s.path(), err)
+	}
+
+	now := s.now()
+	var res []pendingClipboardClear
+	for _, pending := range clears {
+		if now.After(pending.ClearAt.Add(pendingClipboardClearGrace)) {
+			continue
+		}
+		res = append(res, pending)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].CopiedAt.After(res[j].CopiedAt)
+	})
+	return res, nil
+}
+
+// add adds a pending clear.
+func (s *PendingClipboardClearStore) add(pending pendingClipboardClear) error {
+	clears, err := s.list()
+	if err != nil {
+		return err
+	}
+	return s.write(append(clears, pending))
+}
+
+// remove removes the pending clear of the secret with the given hash.
+func (s *PendingClipboardClearStore) remove(hash string) error {
+	clears, err := s.list()
+	if err != nil {
+		return err
+	}
+
+	var res []pendingClipboardClear
+	for _, pending := range clears {
+		if pending.Hash != hash {
+			res = append(res, pending)
+		}
+	}
+	return s.write(res)
+}
+
+// write atomically replaces the pending clears file with the given clears.
+func (s *PendingClipboardClearStore) write(clears []pendingClipboardClear) error {
+	raw, err := json.MarshalIndent(clears, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(s.path(), raw, 0600)
+}
@@ -0,0 +1,22 @@
+package secrethub
+
+import (
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/clip"
+
+	"github.com/spf13/cobra"
+)
+
+// RegisterClipboardBackendFlag registers a flag that selects how the clipboard is accessed.
+func RegisterClipboardBackendFlag(app *cli.App) {
+	var backend string
+	app.PersistentFlags().StringVar(&backend, "clipboard-backend", clip.BackendAuto, "How to access the clipboard. Options are "+strings.Join(clip.Backends, ", ")+". "+
+		"By default, wayland (wl-copy and wl-paste) is used in Wayland sessions and osc52 in SSH sessions without a display, or in tmux when the system clipboard is not available. "+
+		"With osc52, the clipboard of the terminal emulator is set with an escape sequence, which the terminal emulator has to allow. "+
+		"It cannot read the clipboard, so the clipboard is cleared after the timeout even when something else has been copied in the meantime.")
+	app.Root.AddPersistentPreRunE(func(_ *cobra.Command, _ []string) error {
+		return clip.SetBackend(backend)
+	})
+}
@@ -0,0 +1,86 @@
+package secrethub
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/clip"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/docker/go-units"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ClipboardStatusCommand shows whether a secret is pending clearance from the clipboard.
+type ClipboardStatusCommand struct {
+	io            ui.IO
+	clipper       clip.Clipper
+	pending       *PendingClipboardClearStore
+	useTimestamps bool
+	now           func() time.Time
+}
+
+// NewClipboardStatusCommand creates a new ClipboardStatusCommand.
+func NewClipboardStatusCommand(io ui.IO, pending *PendingClipboardClearStore) *ClipboardStatusCommand {
+	return &ClipboardStatusCommand{
+		io:      io,
+		clipper: clip.NewClipboard(),
+		pending: pending,
+		now:     time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ClipboardStatusCommand) Register(r cli.Registerer) {
+	clause := r.Command("status", "Show whether a secret in the clipboard is pending clearance and when it will be cleared.")
+	clause.Flags().BoolVarP(&cmd.useTimestamps, "timestamp", "T", false, "Show the time of clearance formatted to RFC3339 instead of a human readable duration.")
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run prints the status of the secret that was last copied to the clipboard.
+func (cmd *ClipboardStatusCommand) Run() error {
+	clears, err := cmd.pending.list()
+	if err != nil {
+		return err
+	}
+	if len(clears) == 0 {
+		fmt.Fprintln(cmd.io.Output(), "No secret is pending clearance from the clipboard.")
+		return nil
+	}
+
+	// Only the last copied secret can still be in the clipboard.
+	last := clears[0]
+	clearAt := cmd.formatClearAt(last.ClearAt)
+
+	read, err := cmd.clipper.ReadAll()
+	if err == clip.ErrReadNotSupported {
+		fmt.Fprintf(cmd.io.Output(), "A secret was copied to the clipboard with the %s backend, which cannot be read. The clipboard will be cleared %s.\n", last.Backend, clearAt)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	hash, err := hex.DecodeString(last.Hash)
+	if err != nil || bcrypt.CompareHashAndPassword(hash, read) != nil {
+		fmt.Fprintln(cmd.io.Output(), "The clipboard has changed since the last secret was copied to it, so it will not be cleared.")
+		return nil
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "The clipboard contains a secret that will be cleared %s.\n", clearAt)
+	return nil
+}
+
+// formatClearAt returns when the clipboard is cleared, relative to now unless timestamps are used.
+func (cmd *ClipboardStatusCommand) formatClearAt(t time.Time) string {
+	if cmd.useTimestamps {
+		return "at " + t.Format(time.RFC3339)
+	}
+	remaining := t.Sub(cmd.now())
+	if remaining < time.Second {
+		return "any moment now"
+	}
+	return "in " + units.HumanDuration(remaining)
+}
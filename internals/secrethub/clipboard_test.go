@@ -0,0 +1,170 @@
+package secrethub
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/clip"
+	"github.com/secrethub/secrethub-cli/internals/cli/clip/fakeclip"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPendingClipboardClearStore(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	now := time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)
+	store := &PendingClipboardClearStore{
+		dir: func() string { return dir },
+		now: func() time.Time { return now },
+	}
+
+	older := pendingClipboardClear{Hash: "a", CopiedAt: now.Add(-10 * time.Second), ClearAt: now.Add(35 * time.Second)}
+	newer := pendingClipboardClear{Hash: "b", CopiedAt: now.Add(-5 * time.Second), ClearAt: now.Add(40 * time.Second)}
+	stale := pendingClipboardClear{Hash: "c", CopiedAt: now.Add(-time.Hour), ClearAt: now.Add(-time.Hour).Add(45 * time.Second)}
+	assert.OK(t, store.add(stale))
+	assert.OK(t, store.add(older))
+	assert.OK(t, store.add(newer))
+
+	clears, err := store.list()
+	assert.OK(t, err)
+	assert.Equal(t, clears, []pendingClipboardClear{newer, older})
+
+	assert.OK(t, store.remove("b"))
+	clears, err = store.list()
+	assert.OK(t, err)
+	assert.Equal(t, clears, []pendingClipboardClear{older})
+}
+
+func TestClearClipboardCommand_Run(t *testing.T) {
+	secret := []byte("secret")
+	hash, err := bcrypt.GenerateFromPassword(secret, bcrypt.MinCost)
+	assert.OK(t, err)
+
+	cases := map[string]struct {
+		clipper  clip.Clipper
+		expected []byte
+	}{
+		"unchanged": {
+			clipper:  fakeclip.NewWithValue(secret),
+			expected: nil,
+		},
+		"changed": {
+			clipper:  fakeclip.NewWithValue([]byte("something else")),
+			expected: []byte("something else"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			var removedFromHistory bool
+			cmd := ClearClipboardCommand{
+				clipper: tc.clipper,
+				pending: &PendingClipboardClearStore{
+					dir: func() string { return dir },
+					now: time.Now,
+				},
+				removeFromHistory: func(matches func([]byte) bool) int {
+					removedFromHistory = matches(secret)
+					return 1
+				},
+				hash: cli.ByteValue(hex.EncodeToString(hash)),
+				now:  time.Now,
+			}
+
+			// Act
+			err := cmd.Run()
+
+			// Assert
+			assert.OK(t, err)
+			actual, err := tc.clipper.ReadAll()
+			assert.OK(t, err)
+			assert.Equal(t, actual, tc.expected)
+			assert.Equal(t, removedFromHistory, true)
+
+			clears, err := cmd.pending.list()
+			assert.OK(t, err)
+			assert.Equal(t, len(clears), 0)
+		})
+	}
+}
+
+func TestClipboardStatusCommand_Run(t *testing.T) {
+	now := time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)
+	secret := []byte("secret")
+	hash, err := bcrypt.GenerateFromPassword(secret, bcrypt.MinCost)
+	assert.OK(t, err)
+
+	cases := map[string]struct {
+		pending     []pendingClipboardClear
+		clipper     clip.Clipper
+		expectedOut string
+	}{
+		"nothing pending": {
+			clipper:     fakeclip.NewWithValue(secret),
+			expectedOut: "No secret is pending clearance from the clipboard.\n",
+		},
+		"pending": {
+			pending: []pendingClipboardClear{
+				{Hash: hex.EncodeToString(hash), CopiedAt: now.Add(-15 * time.Second), ClearAt: now.Add(30 * time.Second)},
+			},
+			clipper:     fakeclip.NewWithValue(secret),
+			expectedOut: "The clipboard contains a secret that will be cleared in 30 seconds.\n",
+		},
+		"changed": {
+			pending: []pendingClipboardClear{
+				{Hash: hex.EncodeToString(hash), CopiedAt: now.Add(-15 * time.Second), ClearAt: now.Add(30 * time.Second)},
+			},
+			clipper:     fakeclip.NewWithValue([]byte("something else")),
+			expectedOut: "The clipboard has changed since the last secret was copied to it, so it will not be cleared.\n",
+		},
+		"cannot read": {
+			pending: []pendingClipboardClear{
+				{Hash: hex.EncodeToString(hash), CopiedAt: now.Add(-15 * time.Second), ClearAt: now.Add(30 * time.Second), Backend: clip.BackendOSC52},
+			},
+			clipper:     fakeclip.NewWithErr(clip.ErrReadNotSupported, nil),
+			expectedOut: "A secret was copied to the clipboard with the osc52 backend, which cannot be read. The clipboard will be cleared in 30 seconds.\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			store := &PendingClipboardClearStore{
+				dir: func() string { return dir },
+				now: func() time.Time { return now },
+			}
+			for _, pending := range tc.pending {
+				assert.OK(t, store.add(pending))
+			}
+
+			io := fakeui.NewIO(t)
+			cmd := ClipboardStatusCommand{
+				io:      io,
+				clipper: tc.clipper,
+				pending: store,
+				now:     func() time.Time { return now },
+			}
+
+			// Act
+			err := cmd.Run()
+
+			// Assert
+			assert.OK(t, err)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+		})
+	}
+}
@@ -1,11 +1,33 @@
 package secrethub
 
 import (
-	"github.com/fatih/color"
+	"strings"
+
 	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/spf13/cobra"
 )
 
-// RegisterColorFlag registers a color flag that configures whether colored output is used.
+// RegisterColorFlag registers the flags that configure whether colored output and emoji are used.
 func RegisterColorFlag(app *cli.App) {
-	app.PersistentFlags().BoolVar(&color.NoColor, "no-color", false, "Disable colored output.")
+	var mode string
+	var noColor, emoji bool
+	app.PersistentFlags().StringVar(&mode, "color", ui.ColorAuto, "When to use colored output. Options are "+strings.Join(ui.ColorModes, ", ")+". "+
+		"With auto, output is colored when it is written to a terminal and the NO_COLOR environment variable is not set.")
+	app.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output. This is the same as --color=never.").Hidden()
+	app.PersistentFlags().BoolVar(&emoji, "emoji", false, "Show an emoji in front of success, warning and error messages.")
+	app.Root.AddPersistentPreRunE(func(command *cobra.Command, _ []string) error {
+		if noColor {
+			if command.Flags().Changed("color") {
+				return ErrFlagsConflict("--color and --no-color")
+			}
+			mode = ui.ColorNever
+		}
+
+		if emoji {
+			ui.SetTheme(ui.EmojiTheme)
+		}
+		return ui.SetColorMode(mode)
+	})
 }
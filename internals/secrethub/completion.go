@@ -1,42 +1,59 @@
 package secrethub
 
 import (
+	"io"
 	"os"
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/spf13/cobra"
 )
 
+// completionShells are the shells for which a completion script can be generated.
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
 type CompletionCommand struct {
 	shell  cli.StringValue
 	clause *cli.CommandClause
+	io     ui.IO
 }
 
 // NewCompletionCommand is a command that, when executed, generates a completion script
 // for a specific shell, based on the argument it is provided with. It is able to generate
 // completions for Bash, ZSh, Fish and PowerShell.
-func NewCompletionCommand() *CompletionCommand {
-	return &CompletionCommand{}
+func NewCompletionCommand(io ui.IO) *CompletionCommand {
+	return &CompletionCommand{
+		io: io,
+	}
 }
 
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *CompletionCommand) Register(r cli.Registerer) {
-	cmd.clause = r.Command("completion", "Generate completion script").Hidden()
+	cmd.clause = r.Command("completion", "Generate a shell completion script. Use `secrethub completion install` to install it.")
 	cmd.clause.Cmd.DisableFlagsInUseLine = true
-	cmd.clause.Cmd.ValidArgs = []string{"bash", "zsh", "fish", "powershell"}
+	cmd.clause.Cmd.ValidArgs = completionShells
 	cmd.clause.BindAction(cmd.run)
 	cmd.clause.BindArguments([]cli.Argument{{Value: &cmd.shell, Name: "shell", Required: true}})
+
+	NewCompletionInstallCommand(cmd.io).Register(cmd.clause)
 }
 
 func (cmd *CompletionCommand) run() error {
-	switch cmd.shell.Value {
+	return writeCompletionScript(cmd.clause.Cmd.Root(), cmd.shell.Value, os.Stdout)
+}
+
+// writeCompletionScript writes the completion script of the given shell for the root command to w.
+func writeCompletionScript(root *cobra.Command, shell string, w io.Writer) error {
+	switch shell {
 	case "bash":
-		_ = cmd.clause.Cmd.Root().GenBashCompletion(os.Stdout)
+		return root.GenBashCompletion(w)
 	case "zsh":
-		_ = cmd.clause.Cmd.Root().GenZshCompletion(os.Stdout)
+		return root.GenZshCompletion(w)
 	case "fish":
-		_ = cmd.clause.Cmd.Root().GenFishCompletion(os.Stdout, true)
+		return root.GenFishCompletion(w, true)
 	case "powershell":
-		_ = cmd.clause.Cmd.Root().GenPowerShellCompletion(os.Stdout)
+		return root.GenPowerShellCompletion(w)
 	}
-	return nil
+	return ErrUnknownShell(shell)
 }
@@ -0,0 +1,239 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+	"github.com/secrethub/secrethub-cli/internals/cli/cloneproc"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/spf13/cobra"
+)
+
+const (
+	completionCacheFileName = "completion_cache.json"
+
+	// completionCacheMaxAge is how long the cached paths are used before they are refreshed in the background.
+	completionCacheMaxAge = 5 * time.Minute
+	// completionCacheRefreshTimeout is how long a refresh may take before another refresh is started.
+	completionCacheRefreshTimeout = time.Minute
+)
+
+// Errors
+var (
+	ErrInvalidCompletionCache = errMain.Code("invalid_completion_cache").ErrorPref("could not parse the completion cache file %s: %s")
+)
+
+// completionCacheContents are the paths that are used for shell completion.
+type completionCacheContents struct {
+	RefreshedAt      time.Time `json:"refreshed_at"`
+	RefreshStartedAt time.Time `json:"refresh_started_at"`
+	Dirs             []string  `json:"dirs"`
+	Secrets          []string  `json:"secrets"`
+}
+
+// CompletionCache keeps the paths of the directories and secrets of the user in the configuration
+// directory, so that paths can be completed without waiting for the API. The cache is refreshed
+// by a separate process of the CLI that is spawned when a completion finds the cache outdated.
+type CompletionCache struct {
+	dir          func() string
+	now          func() time.Time
+	spawnRefresh func() error
+}
+
+// NewCompletionCache creates a new CompletionCache that stores the paths in the
+// configuration directory of the given credential config.
+func NewCompletionCache(store CredentialConfig) *CompletionCache {
+	return &CompletionCache{
+		dir: func() string {
+			return store.ConfigDir().Path()
+		},
+		now: time.Now,
+		spawnRefresh: func() error {
+			return cloneproc.Spawn("completion-cache-refresh")
+		},
+	}
+}
+
+// registerArgument completes the first argument of the command with the paths of the given kinds.
+func (c *CompletionCache) registerArgument(clause *cli.CommandClause, kinds pathKind) {
+	if c == nil {
+		return
+	}
+	clause.Cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return c.complete(toComplete, kinds)
+	}
+}
+
+// registerFlag completes the values of the flag with the given name with the paths of the
+// given kinds, on the given command and on all of its sub-commands that have the flag.
+func (c *CompletionCache) registerFlag(cmd *cobra.Command, name string, kinds pathKind) {
+	if cmd.Flags().Lookup(name) != nil {
+		_ = cmd.RegisterFlagCompletionFunc(name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return c.complete(toComplete, kinds)
+		})
+	}
+	for _, subCmd := range cmd.Commands() {
+		c.registerFlag(subCmd, name, kinds)
+	}
+}
+
+// complete returns the cached paths of the given kinds that complete toComplete by one more
+// path segment. When the cache is outdated, a refresh is started in the background, so the
+// paths that are created or removed in the meantime are only completed on a next try.
+func (c *CompletionCache) complete(toComplete string, kinds pathKind) ([]string, cobra.ShellCompDirective) {
+	contents, err := c.read()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	c.refreshIfOutdated(contents)
+	return completePath(contents, toComplete, kinds)
+}
+
+// refreshIfOutdated spawns a refresh of the cache when it is outdated and no other refresh is running.
+func (c *CompletionCache) refreshIfOutdated(contents *completionCacheContents) {
+	now := c.now()
+	if now.Sub(contents.RefreshedAt) < completionCacheMaxAge || now.Sub(contents.RefreshStartedAt) < completionCacheRefreshTimeout {
+		return
+	}
+
+	contents.RefreshStartedAt = now
+	err := c.write(contents)
+	if err != nil {
+		return
+	}
+	_ = c.spawnRefresh()
+}
+
+// refresh replaces the cached paths with the paths in all repositories of the user.
+func (c *CompletionCache) refresh(client secrethub.ClientInterface) error {
+	repos, err := client.Repos().ListMine()
+	if err != nil {
+		return err
+	}
+	sort.Sort(api.SortRepoByName(repos))
+
+	contents := &completionCacheContents{
+		RefreshedAt: c.now(),
+	}
+	for _, repo := range repos {
+		repoPath := repo.Path().Value()
+		tree, err := client.Dirs().GetTree(repoPath, -1, false)
+		if err != nil {
+			return err
+		}
+		contents.Dirs = append(contents.Dirs, repoPath)
+		contents.Dirs = append(contents.Dirs, treePaths(tree, repoPath, pathKindDir)...)
+		contents.Secrets = append(contents.Secrets, treePaths(tree, repoPath, pathKindSecret)...)
+	}
+	return c.write(contents)
+}
+
+// path returns the location of the cache file.
+func (c *CompletionCache) path() string {
+	return filepath.Join(c.dir(), completionCacheFileName)
+}
+
+// read returns the cached paths. Empty contents are returned when nothing has been cached yet.
+func (c *CompletionCache) read() (*completionCacheContents, error) {
+	raw, err := os.ReadFile(c.path())
+	if os.IsNotExist(err) {
+		return &completionCacheContents{}, nil
+	} else if err != nil {
+		return nil, ErrCannotReadFile(c.path(), err)
+	}
+
+	var contents completionCacheContents
+	err = json.Unmarshal(raw, &contents)
+	if err != nil {
+		return nil, ErrInvalidCompletionCache(c.path(), err)
+	}
+	return &contents, nil
+}
+
+// write atomically replaces the cache file with the given contents.
+func (c *CompletionCache) write(contents *completionCacheContents) error {
+	raw, err := json.Marshal(contents)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(c.path(), raw, 0600)
+}
+
+// completePath returns the paths of the given kinds that complete toComplete by one more path segment.
+// Namespaces and directories are always suggested, so that they can be completed further. No space is
+// added after them, so that the next segment can be completed right away after typing a /.
+func completePath(contents *completionCacheContents, toComplete string, kinds pathKind) ([]string, cobra.ShellCompDirective) {
+	directive := cobra.ShellCompDirectiveNoFileComp
+	seen := make(map[string]bool)
+	var suggestions []string
+	add := func(candidate string, isDir bool) {
+		if seen[candidate] || candidate == toComplete && isDir && strings.HasSuffix(candidate, "/") {
+			return
+		}
+		if !strings.HasPrefix(candidate, toComplete) || strings.Contains(strings.TrimSuffix(candidate[len(toComplete):], "/"), "/") {
+			return
+		}
+		seen[candidate] = true
+		suggestions = append(suggestions, candidate)
+		if isDir {
+			directive |= cobra.ShellCompDirectiveNoSpace
+		}
+	}
+
+	for _, dir := range contents.Dirs {
+		add(strings.SplitN(dir, "/", 2)[0]+"/", true)
+		add(dir, true)
+	}
+	if kinds&pathKindSecret != 0 {
+		for _, secret := range contents.Secrets {
+			add(secret, false)
+		}
+	}
+
+	sort.Strings(suggestions)
+	return suggestions, directive
+}
+
+// CompletionCacheRefreshCommand refreshes the paths that are used for shell completion.
+// It is started in a separate process when a completion finds the cache outdated.
+type CompletionCacheRefreshCommand struct {
+	cache     *CompletionCache
+	newClient newClientFunc
+}
+
+// NewCompletionCacheRefreshCommand creates a new CompletionCacheRefreshCommand.
+func NewCompletionCacheRefreshCommand(store CredentialConfig, newClient newClientFunc) *CompletionCacheRefreshCommand {
+	return &CompletionCacheRefreshCommand{
+		cache:     NewCompletionCache(store),
+		newClient: newClient,
+	}
+}
+
+// Register registers the command on the provided Registerer.
+func (cmd *CompletionCacheRefreshCommand) Register(r cli.Registerer) {
+	clause := r.Command("completion-cache-refresh", "Refresh the paths that are used for shell completion.").Hidden()
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run fetches all paths of the user and stores them in the completion cache.
+func (cmd *CompletionCacheRefreshCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+	return cmd.cache.refresh(client)
+}
@@ -0,0 +1,120 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+	"github.com/spf13/cobra"
+)
+
+func TestCompletePath(t *testing.T) {
+	contents := &completionCacheContents{
+		Dirs:    []string{"namespace/a", "namespace/a/db", "namespace/b", "other/c"},
+		Secrets: []string{"namespace/a/db/password", "namespace/a/key", "other/c/key"},
+	}
+
+	cases := map[string]struct {
+		toComplete        string
+		kinds             pathKind
+		expected          []string
+		expectedDirective cobra.ShellCompDirective
+	}{
+		"namespaces": {
+			kinds:             pathKindSecret,
+			expected:          []string{"namespace/", "other/"},
+			expectedDirective: cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace,
+		},
+		"repos": {
+			toComplete:        "namespace/",
+			kinds:             pathKindSecret,
+			expected:          []string{"namespace/a", "namespace/b"},
+			expectedDirective: cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace,
+		},
+		"secrets and dirs": {
+			toComplete:        "namespace/a/",
+			kinds:             pathKindSecret,
+			expected:          []string{"namespace/a/db", "namespace/a/key"},
+			expectedDirective: cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace,
+		},
+		"dirs only": {
+			toComplete:        "namespace/a/",
+			kinds:             pathKindDir,
+			expected:          []string{"namespace/a/db"},
+			expectedDirective: cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace,
+		},
+		"secret": {
+			toComplete:        "namespace/a/db/p",
+			kinds:             pathKindSecret,
+			expected:          []string{"namespace/a/db/password"},
+			expectedDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+		"no match": {
+			toComplete:        "namespace/c",
+			kinds:             pathKindSecret,
+			expectedDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, directive := completePath(contents, tc.toComplete, tc.kinds)
+
+			assert.Equal(t, actual, tc.expected)
+			assert.Equal(t, directive, tc.expectedDirective)
+		})
+	}
+}
+
+func TestCompletionCache(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	now := time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)
+	spawned := 0
+	cache := &CompletionCache{
+		dir: func() string { return dir },
+		now: func() time.Time { return now },
+		spawnRefresh: func() error {
+			spawned++
+			return nil
+		},
+	}
+
+	// Without a cache, nothing is completed and a refresh is started once.
+	actual, _ := cache.complete("", pathKindSecret)
+	assert.Equal(t, actual, []string(nil))
+	_, _ = cache.complete("", pathKindSecret)
+	assert.Equal(t, spawned, 1)
+
+	client := fakeclient.Client{
+		RepoService: &fakeclient.RepoService{
+			ListMineFunc: func() ([]*api.Repo, error) {
+				return []*api.Repo{{Owner: "namespace", Name: "repo"}}, nil
+			},
+		},
+		DirService: &fakeclient.DirService{
+			GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				return &api.Tree{
+					RootDir: &api.Dir{
+						Secrets: []*api.Secret{{Name: "key"}},
+						SubDirs: []*api.Dir{{Name: "db"}},
+					},
+				}, nil
+			},
+		},
+	}
+	assert.OK(t, cache.refresh(client))
+
+	actual, _ = cache.complete("namespace/repo/", pathKindSecret)
+	assert.Equal(t, actual, []string{"namespace/repo/db", "namespace/repo/key"})
+	assert.Equal(t, spawned, 1)
+
+	// An outdated cache is still used while it is refreshed.
+	now = now.Add(completionCacheMaxAge)
+	actual, _ = cache.complete("namespace/repo/", pathKindDir)
+	assert.Equal(t, actual, []string{"namespace/repo/db"})
+	assert.Equal(t, spawned, 2)
+}
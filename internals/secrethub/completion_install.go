@@ -0,0 +1,262 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+// Errors
+var (
+	ErrUnknownShell           = errMain.Code("unknown_shell").ErrorPref("unknown shell %q: use --shell to set it to one of " + strings.Join(completionShells, ", "))
+	ErrCompletionNotInstalled = errMain.Code("completion_not_installed").ErrorPref("could not verify that the completion is installed in %s")
+)
+
+// completionInstallation is where the completion script of a shell is installed.
+type completionInstallation struct {
+	// path is the file the completion script is written to.
+	path string
+	// profile is the file the shell runs on startup, to which loadLine is added to load the script.
+	// It is empty for shells that load the script from path by themselves.
+	profile  string
+	loadLine string
+}
+
+// CompletionInstallCommand installs the completion script for the shell of the user.
+type CompletionInstallCommand struct {
+	shell  string
+	print  bool
+	io     ui.IO
+	root   func() *cobra.Command
+	home   func() (string, error)
+	getenv func(string) string
+	goos   string
+}
+
+// NewCompletionInstallCommand creates a new CompletionInstallCommand.
+func NewCompletionInstallCommand(io ui.IO) *CompletionInstallCommand {
+	return &CompletionInstallCommand{
+		io:     io,
+		home:   homedir.Dir,
+		getenv: os.Getenv,
+		goos:   runtime.GOOS,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CompletionInstallCommand) Register(r cli.Registerer) {
+	clause := r.Command("install", "Install the completion script for your shell.")
+	clause.HelpLong("The shell is detected from the SHELL environment variable, or set with --shell. " +
+		"For bash and fish, the script is installed in the directory from which the shell loads completions automatically. " +
+		"Bash requires the bash-completion package for this. " +
+		"For zsh and PowerShell, a line that loads the script is added to ~/.zshrc or the PowerShell profile. " +
+		"Restart your shell after installing to start using the completion.")
+	clause.Flags().StringVar(&cmd.shell, "shell", "", "The shell to install the completion for: "+strings.Join(completionShells, ", ")+". Detected automatically when not set.")
+	_ = clause.Cmd.RegisterFlagCompletionFunc("shell", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completionShells, cobra.ShellCompDirectiveDefault
+	})
+	clause.Flags().BoolVar(&cmd.print, "print", false, "Print the instructions to install the completion yourself, instead of installing it.")
+
+	cmd.root = clause.Cmd.Root
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run installs the completion script and verifies that it is installed.
+func (cmd *CompletionInstallCommand) Run() error {
+	shell, err := cmd.detectShell()
+	if err != nil {
+		return err
+	}
+
+	installation, err := cmd.installation(shell)
+	if err != nil {
+		return err
+	}
+
+	if cmd.print {
+		fmt.Fprintf(cmd.io.Output(), "To install the completion for %s, run:\n\n", shell)
+		fmt.Fprintf(cmd.io.Output(), "    mkdir -p %s\n", filepath.Dir(installation.path))
+		fmt.Fprintf(cmd.io.Output(), "    secrethub completion %s > %s\n", shell, installation.path)
+		if installation.profile != "" {
+			fmt.Fprintf(cmd.io.Output(), "    echo '%s' >> %s\n", installation.loadLine, installation.profile)
+		}
+		fmt.Fprintf(cmd.io.Output(), "\nThen restart your shell.\n")
+		return nil
+	}
+
+	var script bytes.Buffer
+	err = writeCompletionScript(cmd.root(), shell, &script)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(installation.path), 0755)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(installation.path, script.Bytes(), 0644)
+	if err != nil {
+		return ErrCannotWrite(installation.path, err)
+	}
+
+	if installation.profile != "" {
+		err = addLineToFile(installation.profile, installation.loadLine)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = verifyCompletionInstallation(installation, script.Bytes())
+	if err != nil {
+		return err
+	}
+
+	ui.Successf(cmd.io.Output(), "Installed the %s completion in %s.\n", shell, installation.path)
+	if installation.profile != "" {
+		fmt.Fprintf(cmd.io.Output(), "It is loaded from %s.\n", installation.profile)
+	}
+	fmt.Fprintf(cmd.io.Output(), "Restart your shell to start using it.\n")
+	return nil
+}
+
+// detectShell returns the shell set with --shell, or the shell of the user when it is not set.
+func (cmd *CompletionInstallCommand) detectShell() (string, error) {
+	shell := cmd.shell
+	if shell == "" {
+		shell = cmd.getenv("SHELL")
+		if shell == "" && cmd.goos == "windows" {
+			return "powershell", nil
+		}
+		shell = strings.TrimSuffix(filepath.Base(shell), ".exe")
+	}
+
+	switch shell {
+	case "bash", "zsh", "fish", "powershell":
+		return shell, nil
+	case "pwsh":
+		return "powershell", nil
+	}
+	return "", ErrUnknownShell(shell)
+}
+
+// installation returns where the completion script of the given shell is installed.
+func (cmd *CompletionInstallCommand) installation(shell string) (completionInstallation, error) {
+	home, err := cmd.home()
+	if err != nil {
+		return completionInstallation{}, ErrCannotFindHomeDir(err)
+	}
+
+	dataHome := cmd.getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	configHome := cmd.getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+
+	switch shell {
+	case "bash":
+		return completionInstallation{
+			path: filepath.Join(dataHome, "bash-completion", "completions", "secrethub"),
+		}, nil
+	case "zsh":
+		zdotdir := cmd.getenv("ZDOTDIR")
+		if zdotdir == "" {
+			zdotdir = home
+		}
+		path := filepath.Join(dataHome, "secrethub", "completion.zsh")
+		return completionInstallation{
+			path:     path,
+			profile:  filepath.Join(zdotdir, ".zshrc"),
+			loadLine: fmt.Sprintf("source \"%s\" && compdef _secrethub secrethub", path),
+		}, nil
+	case "fish":
+		return completionInstallation{
+			path: filepath.Join(configHome, "fish", "completions", "secrethub.fish"),
+		}, nil
+	case "powershell":
+		profile := filepath.Join(configHome, "powershell", "Microsoft.PowerShell_profile.ps1")
+		if cmd.goos == "windows" {
+			profile = filepath.Join(home, "Documents", "PowerShell", "Microsoft.PowerShell_profile.ps1")
+		}
+		path := filepath.Join(dataHome, "secrethub", "completion.ps1")
+		return completionInstallation{
+			path:     path,
+			profile:  profile,
+			loadLine: fmt.Sprintf(". \"%s\"", path),
+		}, nil
+	}
+	return completionInstallation{}, ErrUnknownShell(shell)
+}
+
+// addLineToFile appends the line to the file, unless the file already contains it.
+// The file is created when it does not exist.
+func addLineToFile(path string, line string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return ErrCannotReadFile(path, err)
+	}
+	if containsLine(content, line) {
+		return nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return ErrCannotWrite(path, err)
+	}
+	defer f.Close()
+
+	prefix := ""
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		prefix = "\n"
+	}
+	_, err = fmt.Fprintf(f, "%s\n# Load the completion of the SecretHub CLI.\n%s\n", prefix, line)
+	if err != nil {
+		return ErrCannotWrite(path, err)
+	}
+	return nil
+}
+
+// verifyCompletionInstallation checks that the script is installed and that it is loaded from the profile.
+func verifyCompletionInstallation(installation completionInstallation, script []byte) error {
+	installed, err := os.ReadFile(installation.path)
+	if err != nil || !bytes.Equal(installed, script) {
+		return ErrCompletionNotInstalled(installation.path)
+	}
+
+	if installation.profile != "" {
+		profile, err := os.ReadFile(installation.profile)
+		if err != nil || !containsLine(profile, installation.loadLine) {
+			return ErrCompletionNotInstalled(installation.profile)
+		}
+	}
+	return nil
+}
+
+// containsLine returns whether the content has a line that equals the given line,
+// ignoring leading and trailing whitespace.
+func containsLine(content []byte, line string) bool {
+	for _, l := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(l) == line {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package secrethub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/spf13/cobra"
+)
+
+func TestCompletionInstallCommand_detectShell(t *testing.T) {
+	cases := map[string]struct {
+		shell       string
+		envShell    string
+		goos        string
+		expected    string
+		expectedErr error
+	}{
+		"from env": {
+			envShell: "/usr/bin/zsh",
+			expected: "zsh",
+		},
+		"flag overrides env": {
+			shell:    "fish",
+			envShell: "/bin/bash",
+			expected: "fish",
+		},
+		"pwsh": {
+			envShell: "/usr/local/bin/pwsh",
+			expected: "powershell",
+		},
+		"windows": {
+			goos:     "windows",
+			expected: "powershell",
+		},
+		"unknown": {
+			envShell:    "/bin/tcsh",
+			expectedErr: ErrUnknownShell("tcsh"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := CompletionInstallCommand{
+				shell: tc.shell,
+				goos:  tc.goos,
+				getenv: func(key string) string {
+					if key == "SHELL" {
+						return tc.envShell
+					}
+					return ""
+				},
+			}
+
+			actual, err := cmd.detectShell()
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestCompletionInstallCommand_Run(t *testing.T) {
+	home, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	io := fakeui.NewIO(t)
+	cmd := CompletionInstallCommand{
+		shell: "zsh",
+		io:    io,
+		root: func() *cobra.Command {
+			return &cobra.Command{Use: "secrethub"}
+		},
+		home: func() (string, error) {
+			return home, nil
+		},
+		getenv: func(string) string {
+			return ""
+		},
+	}
+
+	zshrc := filepath.Join(home, ".zshrc")
+	assert.OK(t, os.WriteFile(zshrc, []byte("autoload -U compinit && compinit"), 0644))
+
+	// Installing twice adds the line to load the script only once.
+	assert.OK(t, cmd.Run())
+	assert.OK(t, cmd.Run())
+
+	script := filepath.Join(home, ".local", "share", "secrethub", "completion.zsh")
+	_, err := os.Stat(script)
+	assert.OK(t, err)
+
+	profile, err := os.ReadFile(zshrc)
+	assert.OK(t, err)
+	assert.Equal(t, string(profile), "autoload -U compinit && compinit\n"+
+		"\n"+
+		"# Load the completion of the SecretHub CLI.\n"+
+		"source \""+script+"\" && compdef _secrethub secrethub\n")
+}
+
+func TestCompletionInstallCommand_Run_Print(t *testing.T) {
+	io := fakeui.NewIO(t)
+	cmd := CompletionInstallCommand{
+		shell: "fish",
+		print: true,
+		io:    io,
+		home: func() (string, error) {
+			return "/home/dev", nil
+		},
+		getenv: func(string) string {
+			return ""
+		},
+	}
+
+	err := cmd.Run()
+
+	assert.OK(t, err)
+	assert.Equal(t, io.Out.String(), "To install the completion for fish, run:\n\n"+
+		"    mkdir -p /home/dev/.config/fish/completions\n"+
+		"    secrethub completion fish > /home/dev/.config/fish/completions/secrethub.fish\n"+
+		"\nThen restart your shell.\n")
+}
@@ -9,13 +9,15 @@ import (
 type ConfigCommand struct {
 	io              ui.IO
 	credentialStore CredentialConfig
+	trust           *ProjectConfigTrust
 }
 
 // NewConfigCommand creates a new ConfigCommand.
-func NewConfigCommand(io ui.IO, store CredentialConfig) *ConfigCommand {
+func NewConfigCommand(io ui.IO, store CredentialConfig, trust *ProjectConfigTrust) *ConfigCommand {
 	return &ConfigCommand{
 		io:              io,
 		credentialStore: store,
+		trust:           trust,
 	}
 }
 
@@ -24,4 +26,5 @@ func (cmd *ConfigCommand) Register(r cli.Registerer) {
 	clause := r.Command("config", "Manage your local configuration.").Hidden()
 	NewCredentialUpdatePassphraseCommand(cmd.io, cmd.credentialStore).Register(clause)
 	NewConfigUpgradeCommand().Register(clause)
+	NewConfigTrustCommand(cmd.io, cmd.trust).Register(clause)
 }
@@ -0,0 +1,86 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// Errors
+var (
+	ErrProjectConfigNotFound = errMain.Code("project_config_not_found").Error("no " + defaultProjectConfigFilename + " file found in the working directory or one of its parents")
+)
+
+// ConfigTrustCommand allows the hooks of a project configuration file to run.
+type ConfigTrustCommand struct {
+	io    ui.IO
+	trust *ProjectConfigTrust
+	path  cli.StringValue
+}
+
+// NewConfigTrustCommand creates a new ConfigTrustCommand.
+func NewConfigTrustCommand(io ui.IO, trust *ProjectConfigTrust) *ConfigTrustCommand {
+	return &ConfigTrustCommand{
+		io:    io,
+		trust: trust,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ConfigTrustCommand) Register(r cli.Registerer) {
+	clause := r.Command("trust", "Allow the hooks of a project configuration file to run.")
+	clause.HelpLong("The hooks in a " + defaultProjectConfigFilename + " file run shell commands whenever a matching secrethub command is executed. " +
+		"Because such a file can come with any repository you clone, its hooks are only run after you have reviewed the file and trusted it with this command. " +
+		"Trust is given to the current contents of the file: when the file changes, it has to be trusted again.\n\n" +
+		"When no path is given, the first " + defaultProjectConfigFilename + " file found in the working directory or one of its parents is trusted.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{{Value: &cmd.path, Name: "path", Required: false, Description: "The path to the project configuration file to trust."}})
+}
+
+// Run validates the project configuration file, prints its hooks and trusts its current contents.
+func (cmd *ConfigTrustCommand) Run() error {
+	path := cmd.path.Value
+	if path == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return ErrCannotGetWorkingDir(err)
+		}
+		path = findProjectConfig(wd)
+		if path == "" {
+			return ErrProjectConfigNotFound
+		}
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return ErrCannotReadFile(path, err)
+	}
+
+	cfg, err := parseProjectConfig(path, raw)
+	if err != nil {
+		return err
+	}
+
+	err = cmd.trust.trust(path, raw)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Trusted %s. The following hooks will run:\n", path)
+	for _, hook := range cfg.Hooks {
+		fmt.Fprintf(cmd.io.Output(), "  %s %s: %s\n", hook.When, hook.Command, hook.description())
+	}
+	if len(cfg.Hooks) == 0 {
+		fmt.Fprintln(cmd.io.Output(), "  (none)")
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package secrethub
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	crashReportDir    = "crashes"
+	crashReportPrefix = "crash-"
+	crashReportExt    = ".txt"
+	// crashReportMaxFiles is the number of crash reports that are kept.
+	crashReportMaxFiles = 10
+)
+
+var (
+	// regexpStackFileLine matches the location lines of a stack trace, e.g. `	/path/to/file.go:12 +0x1d`.
+	regexpStackFileLine = regexp.MustCompile(`^(\s+)(.*)/([^/]+\.(?:go|s):\d+)(.*)$`)
+	// regexpStackCallArgs matches the arguments of a function call in a stack trace, e.g. `main.f(0xc000010000, {0x3, 0x1?})`.
+	regexpStackCallArgs = regexp.MustCompile(`\([0-9a-fx, .{}?]*\)$`)
+)
+
+// CrashReporter writes a redacted report to the configuration directory when the CLI panics.
+// Nothing is sent anywhere: the user decides whether to share the report, e.g. with `secrethub bugreport`.
+type CrashReporter struct {
+	dir         func() string
+	errOut      io.Writer
+	now         func() time.Time
+	commandPath string
+}
+
+// NewCrashReporter creates a new CrashReporter that stores its reports in the
+// configuration directory of the given credential config.
+func NewCrashReporter(store CredentialConfig) *CrashReporter {
+	return &CrashReporter{
+		dir: func() string {
+			return filepath.Join(store.ConfigDir().Path(), crashReportDir)
+		},
+		errOut: os.Stderr,
+		now:    time.Now,
+	}
+}
+
+// Register records the name of the executed command, so that it can be included in a crash report.
+// The arguments and flags are not recorded, because they can contain sensitive paths.
+func (c *CrashReporter) Register(app *cli.App) {
+	app.Root.AddPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+		c.commandPath = cmd.CommandPath()
+		return nil
+	})
+}
+
+// Recover writes a crash report when the current goroutine is panicking and exits
+// the process. It must be deferred directly.
+func (c *CrashReporter) Recover() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	path, err := c.write(c.report(r, debug.Stack()))
+	if err != nil {
+		fmt.Fprintf(c.errOut, "%s crashed unexpectedly and could not write a crash report: %s\n", ApplicationName, err)
+	} else {
+		fmt.Fprintf(c.errOut, "%s crashed unexpectedly. A crash report without any secrets or paths was written to %s.\n"+
+			"Please run `%s bugreport` and attach its output to an issue.\n", ApplicationName, path, ApplicationName)
+	}
+	os.Exit(ExitCodeCrash)
+}
+
+// report returns a crash report for the panic value and stack trace.
+func (c *CrashReporter) report(r interface{}, stack []byte) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Time: %s\n", c.now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(&sb, "Version: %s, build %s\n", Version, Commit)
+	fmt.Fprintf(&sb, "Platform: %s/%s, %s\n", runtime.GOOS, runtime.GOARCH, runtime.Version())
+	fmt.Fprintf(&sb, "Command: %s\n", c.commandPath)
+	fmt.Fprintf(&sb, "Panic: %s\n\n", redactPanicValue(r))
+	sb.WriteString(redactStack(string(stack)))
+	return sb.String()
+}
+
+// write stores the report in a new file and removes the oldest reports when there
+// are more than crashReportMaxFiles. It returns the path of the new file.
+func (c *CrashReporter) write(report string) (string, error) {
+	err := os.MkdirAll(c.dir(), 0700)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(c.dir(), crashReportPrefix+c.now().UTC().Format("20060102T150405Z")+crashReportExt)
+	err = os.WriteFile(path, []byte(report), 0600)
+	if err != nil {
+		return "", err
+	}
+
+	reports, err := c.reports()
+	if err != nil {
+		return "", err
+	}
+	for len(reports) > crashReportMaxFiles {
+		_ = os.Remove(reports[0])
+		reports = reports[1:]
+	}
+	return path, nil
+}
+
+// reports returns the paths of all crash reports, from the oldest to the newest.
+func (c *CrashReporter) reports() ([]string, error) {
+	files, err := os.ReadDir(c.dir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var reports []string
+	for _, f := range files {
+		if !f.IsDir() && strings.HasPrefix(f.Name(), crashReportPrefix) && strings.HasSuffix(f.Name(), crashReportExt) {
+			reports = append(reports, filepath.Join(c.dir(), f.Name()))
+		}
+	}
+	// The names contain the time of the crash, so they sort chronologically.
+	sort.Strings(reports)
+	return reports, nil
+}
+
+// redactPanicValue returns a description of the panic value that cannot contain secrets.
+// Only the messages of runtime errors are included, because they are generated by the
+// Go runtime and only contain details such as indices. Other values are reduced to their type.
+func redactPanicValue(r interface{}) string {
+	if err, ok := r.(runtime.Error); ok {
+		return err.Error()
+	}
+	return fmt.Sprintf("%T (value redacted)", r)
+}
+
+// redactStack removes the values of function arguments from a stack trace and replaces
+// the directories of source files with a hash, so that a stack trace cannot reveal
+// secrets, user names or the layout of the file system. The same directory always
+// results in the same hash, so that frames can still be related to each other.
+func redactStack(stack string) string {
+	lines := strings.Split(stack, "\n")
+	for i, line := range lines {
+		if m := regexpStackFileLine.FindStringSubmatch(line); m != nil {
+			lines[i] = m[1] + hashPath(m[2]) + "/" + m[3] + m[4]
+			continue
+		}
+		lines[i] = regexpStackCallArgs.ReplaceAllString(line, "(...)")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// hashPath returns a short hash of the path.
+func hashPath(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return "<" + hex.EncodeToString(sum[:4]) + ">"
+}
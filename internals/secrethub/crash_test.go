@@ -0,0 +1,101 @@
+package secrethub
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestRedactStack(t *testing.T) {
+	cases := map[string]struct {
+		in       string
+		expected string
+	}{
+		"file line": {
+			in:       "\t/home/user/go/src/x/main.go:12 +0x1d",
+			expected: "\t" + hashPath("/home/user/go/src/x") + "/main.go:12 +0x1d",
+		},
+		"function arguments": {
+			in:       "main.f(0xc000010000, {0x3, 0x1?})",
+			expected: "main.f(...)",
+		},
+		"method without arguments": {
+			in:       "main.(*T).f()",
+			expected: "main.(*T).f(...)",
+		},
+		"goroutine header": {
+			in:       "goroutine 1 [running]:",
+			expected: "goroutine 1 [running]:",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, redactStack(tc.in), tc.expected)
+		})
+	}
+}
+
+type fakeRuntimeError struct{}
+
+func (fakeRuntimeError) RuntimeError() {}
+func (fakeRuntimeError) Error() string { return "index out of range [3] with length 3" }
+
+func TestRedactPanicValue(t *testing.T) {
+	cases := map[string]struct {
+		in       interface{}
+		expected string
+	}{
+		"string": {
+			in:       "secret value",
+			expected: "string (value redacted)",
+		},
+		"error": {
+			in:       errors.New("secret value"),
+			expected: "*errors.errorString (value redacted)",
+		},
+		"runtime error": {
+			in:       fakeRuntimeError{},
+			expected: "index out of range [3] with length 3",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, redactPanicValue(tc.in), tc.expected)
+		})
+	}
+}
+
+func TestCrashReporter_write(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	reporter := &CrashReporter{
+		dir: func() string { return filepath.Join(dir, crashReportDir) },
+		now: func() time.Time { return now },
+	}
+
+	var last string
+	for i := 0; i < crashReportMaxFiles+2; i++ {
+		now = now.Add(time.Second)
+		path, err := reporter.write(fmt.Sprintf("report %d", i))
+		assert.OK(t, err)
+		last = path
+	}
+
+	reports, err := reporter.reports()
+	assert.OK(t, err)
+	assert.Equal(t, len(reports), crashReportMaxFiles)
+	assert.Equal(t, reports[len(reports)-1], last)
+
+	contents, err := os.ReadFile(reports[0])
+	assert.OK(t, err)
+	assert.Equal(t, string(contents), "report 2")
+}
@@ -28,4 +28,5 @@ func (cmd *CredentialCommand) Register(r cli.Registerer) {
 	NewCredentialBackupCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
 	NewCredentialDisableCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
 	NewCredentialUpdatePassphraseCommand(cmd.io, cmd.credentialStore).Register(clause)
+	NewCredentialProfileCommand(cmd.io, cmd.credentialStore).Register(clause)
 }
@@ -1,16 +1,42 @@
 package secrethub
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
+	"unicode"
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/qrcode"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 
 	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
 )
 
+const (
+	// backupCodeWordBits is the number of bits that every word of a word-encoded backup code encodes.
+	backupCodeWordBits = 10
+	// backupCodeWordsPerLine is the number of words that are printed on every line of a backup code.
+	backupCodeWordsPerLine = 9
+	// backupCodeGroupSize is the number of characters in every group of a backup code.
+	backupCodeGroupSize = 8
+)
+
+// backupCodeWords are the words that backup codes are encoded in, so that they are easier to write down.
+// Note that changing passphraseWords changes these words, after which written down backup codes can no longer be read.
+var backupCodeWords = passphraseWords[:1<<backupCodeWordBits]
+
+// Errors
+var (
+	ErrInvalidBackupCode         = errMain.Code("invalid_backup_code").ErrorPref("invalid backup code: %s")
+	ErrUnknownBackupCodeWord     = errMain.Code("unknown_backup_code_word").ErrorPref("%q is not a word of a backup code: check its spelling")
+	ErrInvalidBackupCodeChecksum = errMain.Code("invalid_backup_code_checksum").Error("the words of the backup code are incorrect: check that no words are missing or in the wrong order")
+)
+
 // CredentialBackupCommand creates a backup code to restore a credential from a code.
 type CredentialBackupCommand struct {
+	qr        bool
 	io        ui.IO
 	newClient newClientFunc
 }
@@ -26,6 +52,10 @@ func NewCredentialBackupCommand(io ui.IO, newClient newClientFunc) *CredentialBa
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *CredentialBackupCommand) Register(r cli.Registerer) {
 	clause := r.Command("backup", "Create a backup code for restoring your account.")
+	clause.HelpLong("The backup code is printed both as a code and as a list of words, which is easier to write down. " +
+		"Either of them can be used to restore your account on another device with `secrethub init`, " +
+		"so losing your device does not mean losing your account.")
+	clause.Flags().BoolVar(&cmd.qr, "qr", false, "Also print the backup code as a QR code, to store it with your phone.")
 
 	clause.BindAction(cmd.Run)
 	clause.BindArguments(nil)
@@ -68,9 +98,114 @@ func (cmd *CredentialBackupCommand) Run() error {
 		return err
 	}
 
+	words, err := encodeBackupCode(code)
+	if err != nil {
+		return err
+	}
+
 	fmt.Fprintf(cmd.io.Output(), "This is your backup code: \n%s\n", code)
+	fmt.Fprintf(cmd.io.Output(), "Or in words:\n%s\n", words)
+	if cmd.qr {
+		qr, err := qrcode.Encode([]byte(code))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Or as a QR code:\n%s", qr)
+	}
 	fmt.Fprintln(cmd.io.Output(), "Write it down and store it in a safe location! "+
 		"You can restore your account by running `secrethub init`.")
 
 	return nil
 }
+
+// encodeBackupCode returns the words that encode the given backup code, with a checksum to detect mistakes
+// made while writing them down or typing them in. The words are split in lines of backupCodeWordsPerLine words.
+func encodeBackupCode(code string) (string, error) {
+	raw, err := hex.DecodeString(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if err != nil {
+		return "", ErrInvalidBackupCode(err)
+	}
+	checksum := sha256.Sum256(raw)
+	data := append(raw, checksum[0])
+
+	n := (8*len(data) + backupCodeWordBits - 1) / backupCodeWordBits
+	var res strings.Builder
+	for i := 0; i < n; i++ {
+		index := 0
+		for j := 0; j < backupCodeWordBits; j++ {
+			bit := i*backupCodeWordBits + j
+			index <<= 1
+			if bit < 8*len(data) {
+				index |= int(data[bit/8] >> (7 - bit%8) & 1)
+			}
+		}
+
+		if i > 0 && i%backupCodeWordsPerLine == 0 {
+			res.WriteString("\n")
+		} else if i > 0 {
+			res.WriteString(" ")
+		}
+		res.WriteString(backupCodeWords[index])
+	}
+	return res.String(), nil
+}
+
+// decodeBackupCode returns the backup code that is encoded in the given words.
+func decodeBackupCode(words string) (string, error) {
+	indices := make(map[string]int, len(backupCodeWords))
+	for i, word := range backupCodeWords {
+		indices[word] = i
+	}
+
+	fields := strings.Fields(strings.ToLower(words))
+	data := make([]byte, len(fields)*backupCodeWordBits/8)
+	for i, word := range fields {
+		index, ok := indices[word]
+		if !ok {
+			return "", ErrUnknownBackupCodeWord(word)
+		}
+		for j := 0; j < backupCodeWordBits; j++ {
+			bit := i*backupCodeWordBits + j
+			if bit < 8*len(data) && index>>(backupCodeWordBits-1-j)&1 == 1 {
+				data[bit/8] |= 1 << (7 - bit%8)
+			}
+		}
+	}
+
+	if len(data) < 2 {
+		return "", ErrInvalidBackupCodeChecksum
+	}
+	raw := data[:len(data)-1]
+	checksum := sha256.Sum256(raw)
+	if checksum[0] != data[len(data)-1] {
+		return "", ErrInvalidBackupCodeChecksum
+	}
+
+	encoded := strings.ToUpper(hex.EncodeToString(raw))
+	var groups []string
+	for len(encoded) > backupCodeGroupSize {
+		groups = append(groups, encoded[:backupCodeGroupSize])
+		encoded = encoded[backupCodeGroupSize:]
+	}
+	return strings.Join(append(groups, encoded), "-"), nil
+}
+
+// normalizeBackupCode returns the backup code that is entered, decoding it when it is entered in words.
+func normalizeBackupCode(code string) (string, error) {
+	isWords := strings.IndexFunc(code, func(r rune) bool {
+		return unicode.IsLetter(r) && !strings.ContainsRune("abcdefABCDEF", r)
+	}) >= 0
+	if isWords {
+		return decodeBackupCode(code)
+	}
+	return code, nil
+}
+
+// validateBackupCode returns an error when the entered backup code, as a code or in words, is invalid.
+func validateBackupCode(code string) error {
+	code, err := normalizeBackupCode(code)
+	if err != nil {
+		return err
+	}
+	return credentials.ValidateBootstrapCode(code)
+}
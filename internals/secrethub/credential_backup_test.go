@@ -134,10 +134,70 @@ func TestCredentialBackupCommand_Run(t *testing.T) {
 				if !beginning && !end {
 					t.Errorf("The output did not match the expected format. " + io.Out.String())
 				}
-				credentialValue := strings.TrimSuffix(strings.TrimPrefix(io.Out.String(), backupPrefix), backupSuffix)
-				assert.Equal(t, len(credentialValue), 71)
+				backup := strings.TrimSuffix(strings.TrimPrefix(io.Out.String(), backupPrefix), backupSuffix)
+				parts := strings.Split(backup, "\nOr in words:\n")
+				assert.Equal(t, len(parts), 2)
+				assert.Equal(t, len(parts[0]), 71)
+
+				decoded, err := decodeBackupCode(parts[1])
+				assert.OK(t, err)
+				assert.Equal(t, normalizeHex(decoded), normalizeHex(parts[0]))
 			}
 			assert.Equal(t, io.PromptOut.String(), tc.expectedPromptOut)
 		})
 	}
 }
+
+func TestBackupCodeWords(t *testing.T) {
+	const code = "0123ABCD-4567EF01-89ABCDEF-00000000-FFFFFFFF-12345678-9ABCDEF0-DEADBEEF"
+
+	words, err := encodeBackupCode(code)
+	assert.OK(t, err)
+	assert.Equal(t, len(strings.Fields(words)), 27)
+	assert.Equal(t, len(strings.Split(words, "\n")), 3)
+
+	cases := map[string]struct {
+		in          string
+		expected    string
+		expectedErr error
+	}{
+		"words": {
+			in:       words,
+			expected: code,
+		},
+		"words on one line in upper case": {
+			in:       strings.ToUpper(strings.ReplaceAll(words, "\n", " ")),
+			expected: code,
+		},
+		"code": {
+			in:       code,
+			expected: code,
+		},
+		"missing word": {
+			in:          strings.Join(strings.Fields(words)[1:], " "),
+			expectedErr: ErrInvalidBackupCodeChecksum,
+		},
+		"swapped words": {
+			in:          strings.Join(append([]string{strings.Fields(words)[1], strings.Fields(words)[0]}, strings.Fields(words)[2:]...), " "),
+			expectedErr: ErrInvalidBackupCodeChecksum,
+		},
+		"unknown word": {
+			in:          "secrethub " + words,
+			expectedErr: ErrUnknownBackupCodeWord("secrethub"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := normalizeBackupCode(tc.in)
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+// normalizeHex returns the hex characters of a code in upper case, without separators.
+func normalizeHex(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
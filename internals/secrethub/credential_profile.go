@@ -0,0 +1,200 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
+)
+
+const (
+	// defaultCredentialProfile is the name of the profile of the credential file in the root of the configuration directory.
+	defaultCredentialProfile = "default"
+
+	credentialProfilesDirName       = "profiles"
+	credentialProfileConfigFileName = "profile.json"
+)
+
+// Errors
+var (
+	ErrInvalidCredentialProfileName   = errMain.Code("invalid_profile_name").ErrorPref("invalid profile name %s: must consist of letters, digits, '_' and '-'")
+	ErrCredentialProfileNotFound      = errMain.Code("profile_not_found").ErrorPref("profile %s does not exist: add it with `secrethub credential profile add`")
+	ErrCredentialProfileAlreadyExists = errMain.Code("profile_already_exists").ErrorPref("profile %s already exists: use --force to overwrite it")
+	ErrCannotRemoveDefaultProfile     = errMain.Code("cannot_remove_default_profile").Error("the default profile cannot be removed")
+	ErrInvalidCredentialProfileConfig = errMain.Code("invalid_profile_config").ErrorPref("could not parse the profile configuration file %s: %s")
+)
+
+var credentialProfileNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// validateCredentialProfileName returns an error when the name cannot be used as the name of a profile.
+func validateCredentialProfileName(name string) error {
+	if !credentialProfileNamePattern.MatchString(name) {
+		return ErrInvalidCredentialProfileName(name)
+	}
+	return nil
+}
+
+// CredentialProfileCommand handles the profiles of the credentials that are stored in the configuration directory.
+type CredentialProfileCommand struct {
+	io              ui.IO
+	credentialStore CredentialConfig
+}
+
+// NewCredentialProfileCommand creates a new CredentialProfileCommand.
+func NewCredentialProfileCommand(io ui.IO, credentialStore CredentialConfig) *CredentialProfileCommand {
+	return &CredentialProfileCommand{
+		io:              io,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *CredentialProfileCommand) Register(r cli.Registerer) {
+	clause := r.Command("profile", "Manage multiple credentials, e.g. of your personal account and of a service account.")
+	clause.HelpLong("Every profile stores a credential in the configuration directory. " +
+		"The default profile is the credential that is created by `secrethub init`. " +
+		"Use `secrethub credential profile switch` to change the profile that is used, " +
+		"or --profile or SECRETHUB_PROFILE to use another profile for a single command. " +
+		"A credential set with --credential or SECRETHUB_CREDENTIAL takes precedence over all profiles.")
+
+	profiles := NewCredentialProfileStore(cmd.credentialStore)
+	NewCredentialProfileAddCommand(cmd.io, cmd.credentialStore, profiles).Register(clause)
+	NewCredentialProfileListCommand(cmd.io, profiles).Register(clause)
+	NewCredentialProfileSwitchCommand(cmd.io, profiles).Register(clause)
+	NewCredentialProfileRemoveCommand(cmd.io, profiles).Register(clause)
+}
+
+// credentialProfileConfig is the configuration of the profiles that is stored in the configuration directory.
+type credentialProfileConfig struct {
+	Current string `json:"current"`
+}
+
+// CredentialProfileStore keeps the credentials of the profiles in the configuration directory.
+// The credential of the default profile is the credential file in the root of the configuration
+// directory, the credentials of other profiles are stored in profiles/<name>/credential.
+type CredentialProfileStore struct {
+	dir func() string
+}
+
+// NewCredentialProfileStore creates a new CredentialProfileStore that stores the profiles in
+// the configuration directory of the given credential config.
+func NewCredentialProfileStore(store CredentialConfig) *CredentialProfileStore {
+	return &CredentialProfileStore{
+		dir: func() string {
+			return store.ConfigDir().Path()
+		},
+	}
+}
+
+// credential returns the credential file of the profile with the given name.
+func (s *CredentialProfileStore) credential(name string) *configdir.CredentialFile {
+	if name == defaultCredentialProfile {
+		return configdir.New(s.dir()).Credential()
+	}
+	return configdir.New(filepath.Join(s.dir(), credentialProfilesDirName, name)).Credential()
+}
+
+// exists returns whether the profile with the given name has a credential.
+func (s *CredentialProfileStore) exists(name string) bool {
+	return s.credential(name).Exists()
+}
+
+// list returns the names of the profiles that have a credential, the default profile first.
+func (s *CredentialProfileStore) list() ([]string, error) {
+	var names []string
+	if s.exists(defaultCredentialProfile) {
+		names = append(names, defaultCredentialProfile)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(s.dir(), credentialProfilesDirName))
+	if os.IsNotExist(err) {
+		return names, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var named []string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != defaultCredentialProfile && s.exists(entry.Name()) {
+			named = append(named, entry.Name())
+		}
+	}
+	sort.Strings(named)
+	return append(names, named...), nil
+}
+
+// write stores the credential of the profile with the given name.
+func (s *CredentialProfileStore) write(name string, credential []byte) error {
+	file := s.credential(name)
+	err := os.MkdirAll(filepath.Dir(file.Path()), 0700)
+	if err != nil {
+		return err
+	}
+	return file.Write(credential)
+}
+
+// remove removes the profile with the given name. When it is the current profile,
+// the default profile becomes the current profile.
+func (s *CredentialProfileStore) remove(name string) error {
+	if name == defaultCredentialProfile {
+		return ErrCannotRemoveDefaultProfile
+	}
+	if !s.exists(name) {
+		return ErrCredentialProfileNotFound(name)
+	}
+
+	current, err := s.current()
+	if err != nil {
+		return err
+	}
+	if current == name {
+		err = s.setCurrent(defaultCredentialProfile)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.RemoveAll(filepath.Join(s.dir(), credentialProfilesDirName, name))
+}
+
+// path returns the location of the profile configuration file.
+func (s *CredentialProfileStore) path() string {
+	return filepath.Join(s.dir(), credentialProfileConfigFileName)
+}
+
+// current returns the name of the profile that is used when no profile is given.
+func (s *CredentialProfileStore) current() (string, error) {
+	raw, err := os.ReadFile(s.path())
+	if os.IsNotExist(err) {
+		return defaultCredentialProfile, nil
+	} else if err != nil {
+		return "", ErrCannotReadFile(s.path(), err)
+	}
+
+	var config credentialProfileConfig
+	err = json.Unmarshal(raw, &config)
+	if err != nil {
+		return "", ErrInvalidCredentialProfileConfig(s.path(), err)
+	}
+	if config.Current == "" {
+		return defaultCredentialProfile, nil
+	}
+	return config.Current, nil
+}
+
+// setCurrent atomically sets the profile that is used when no profile is given.
+func (s *CredentialProfileStore) setCurrent(name string) error {
+	raw, err := json.MarshalIndent(credentialProfileConfig{Current: name}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(s.path(), raw, 0600)
+}
@@ -0,0 +1,95 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// Errors
+var (
+	ErrEmptyCredential = errMain.Code("empty_credential").Error("the credential to add is empty")
+)
+
+// CredentialProfileAddCommand adds a profile with a credential.
+type CredentialProfileAddCommand struct {
+	name            cli.StringValue
+	inFile          string
+	force           bool
+	io              ui.IO
+	credentialStore CredentialConfig
+	profiles        *CredentialProfileStore
+}
+
+// NewCredentialProfileAddCommand creates a new CredentialProfileAddCommand.
+func NewCredentialProfileAddCommand(io ui.IO, credentialStore CredentialConfig, profiles *CredentialProfileStore) *CredentialProfileAddCommand {
+	return &CredentialProfileAddCommand{
+		io:              io,
+		credentialStore: credentialStore,
+		profiles:        profiles,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CredentialProfileAddCommand) Register(r cli.Registerer) {
+	clause := r.Command("add", "Add a profile with a credential.")
+	clause.HelpLong("The credential is read from --in-file or from stdin. " +
+		"When neither is given, the credential that is used at the moment is added, " +
+		"e.g. to keep the credential of your personal account under a name before switching to another profile.")
+	clause.Flags().StringVarP(&cmd.inFile, "in-file", "i", "", "Read the credential from this file.")
+	_ = clause.Cmd.MarkFlagFilename("in-file")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.name, Name: "name", Required: true, Description: "The name of the profile, e.g. personal or ci."},
+	})
+}
+
+// Run adds the profile.
+func (cmd *CredentialProfileAddCommand) Run() error {
+	name := cmd.name.Value
+	err := validateCredentialProfileName(name)
+	if err != nil {
+		return err
+	}
+	if !cmd.force && cmd.profiles.exists(name) {
+		return ErrCredentialProfileAlreadyExists(name)
+	}
+
+	var credential []byte
+	if cmd.inFile != "" {
+		credential, err = os.ReadFile(cmd.inFile)
+		if err != nil {
+			return ErrReadFile(cmd.inFile, err)
+		}
+	} else if cmd.io.IsInputPiped() {
+		credential, err = io.ReadAll(cmd.io.Input())
+		if err != nil {
+			return ui.ErrReadInput(err)
+		}
+	} else {
+		credential, err = cmd.credentialStore.CredentialReader().Read()
+		if err != nil {
+			return err
+		}
+	}
+
+	credential = bytes.TrimSpace(credential)
+	if len(credential) == 0 {
+		return ErrEmptyCredential
+	}
+
+	err = cmd.profiles.write(name, credential)
+	if err != nil {
+		return err
+	}
+
+	ui.Successf(cmd.io.Output(), "Added profile %s.\n", name)
+	fmt.Fprintf(cmd.io.Output(), "Run `secrethub credential profile switch %s` to use it.\n", name)
+	return nil
+}
@@ -0,0 +1,56 @@
+package secrethub
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// CredentialProfileListCommand lists the profiles.
+type CredentialProfileListCommand struct {
+	io       ui.IO
+	profiles *CredentialProfileStore
+}
+
+// NewCredentialProfileListCommand creates a new CredentialProfileListCommand.
+func NewCredentialProfileListCommand(io ui.IO, profiles *CredentialProfileStore) *CredentialProfileListCommand {
+	return &CredentialProfileListCommand{
+		io:       io,
+		profiles: profiles,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CredentialProfileListCommand) Register(r cli.Registerer) {
+	clause := r.Command("ls", "List the profiles. The current profile is marked with a *.")
+	clause.Alias("list")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run lists the profiles.
+func (cmd *CredentialProfileListCommand) Run() error {
+	names, err := cmd.profiles.list()
+	if err != nil {
+		return err
+	}
+
+	current, err := cmd.profiles.current()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w, "CURRENT\tNAME\tCREDENTIAL")
+	for _, name := range names {
+		marker := ""
+		if name == current {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", marker, name, cmd.profiles.credential(name).Path())
+	}
+	return w.Flush()
+}
@@ -0,0 +1,73 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// CredentialProfileRemoveCommand removes a profile and its credential.
+type CredentialProfileRemoveCommand struct {
+	name     cli.StringValue
+	force    bool
+	io       ui.IO
+	profiles *CredentialProfileStore
+}
+
+// NewCredentialProfileRemoveCommand creates a new CredentialProfileRemoveCommand.
+func NewCredentialProfileRemoveCommand(io ui.IO, profiles *CredentialProfileStore) *CredentialProfileRemoveCommand {
+	return &CredentialProfileRemoveCommand{
+		io:       io,
+		profiles: profiles,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CredentialProfileRemoveCommand) Register(r cli.Registerer) {
+	clause := r.Command("rm", "Remove a profile and its credential. When it is the current profile, the default profile becomes the current profile.")
+	clause.Alias("remove")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.name, Name: "name", Required: true, Description: "The name of the profile to remove."},
+	})
+}
+
+// Run removes the profile.
+func (cmd *CredentialProfileRemoveCommand) Run() error {
+	name := cmd.name.Value
+	err := validateCredentialProfileName(name)
+	if err != nil {
+		return err
+	}
+	if name == defaultCredentialProfile {
+		return ErrCannotRemoveDefaultProfile
+	}
+	if !cmd.profiles.exists(name) {
+		return ErrCredentialProfileNotFound(name)
+	}
+
+	ok, err := askRmConfirmation(
+		cmd.io,
+		fmt.Sprintf("This will permanently remove the credential of the %s profile. "+
+			"Please type in the name of the profile to confirm", name),
+		cmd.force,
+		name,
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	err = cmd.profiles.remove(name)
+	if err != nil {
+		return err
+	}
+
+	ui.Successf(cmd.io.Output(), "Removed profile %s.\n", name)
+	return nil
+}
@@ -0,0 +1,52 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// CredentialProfileSwitchCommand changes the profile that is used when no profile is given.
+type CredentialProfileSwitchCommand struct {
+	name     cli.StringValue
+	io       ui.IO
+	profiles *CredentialProfileStore
+}
+
+// NewCredentialProfileSwitchCommand creates a new CredentialProfileSwitchCommand.
+func NewCredentialProfileSwitchCommand(io ui.IO, profiles *CredentialProfileStore) *CredentialProfileSwitchCommand {
+	return &CredentialProfileSwitchCommand{
+		io:       io,
+		profiles: profiles,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CredentialProfileSwitchCommand) Register(r cli.Registerer) {
+	clause := r.Command("switch", "Use the credential of another profile for all following commands.")
+	clause.Alias("use")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.name, Name: "name", Required: true, Description: "The name of the profile to switch to."},
+	})
+}
+
+// Run switches to the profile.
+func (cmd *CredentialProfileSwitchCommand) Run() error {
+	name := cmd.name.Value
+	err := validateCredentialProfileName(name)
+	if err != nil {
+		return err
+	}
+	if !cmd.profiles.exists(name) {
+		return ErrCredentialProfileNotFound(name)
+	}
+
+	err = cmd.profiles.setCurrent(name)
+	if err != nil {
+		return err
+	}
+
+	ui.Successf(cmd.io.Output(), "Switched to profile %s.\n", name)
+	return nil
+}
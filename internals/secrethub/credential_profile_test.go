@@ -0,0 +1,117 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestCredentialProfileStore(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	store := &CredentialProfileStore{dir: func() string { return dir }}
+
+	current, err := store.current()
+	assert.OK(t, err)
+	assert.Equal(t, current, defaultCredentialProfile)
+
+	assert.OK(t, store.write(defaultCredentialProfile, []byte("personal")))
+	assert.OK(t, store.write("work", []byte("work")))
+	assert.OK(t, store.write("ci", []byte("ci")))
+
+	names, err := store.list()
+	assert.OK(t, err)
+	assert.Equal(t, names, []string{defaultCredentialProfile, "ci", "work"})
+
+	credential, err := store.credential("work").Read()
+	assert.OK(t, err)
+	assert.Equal(t, string(credential), "work")
+
+	assert.OK(t, store.setCurrent("work"))
+	current, err = store.current()
+	assert.OK(t, err)
+	assert.Equal(t, current, "work")
+
+	// Removing the current profile switches back to the default profile.
+	assert.OK(t, store.remove("work"))
+	current, err = store.current()
+	assert.OK(t, err)
+	assert.Equal(t, current, defaultCredentialProfile)
+
+	assert.Equal(t, store.remove("work"), ErrCredentialProfileNotFound("work"))
+	assert.Equal(t, store.remove(defaultCredentialProfile), ErrCannotRemoveDefaultProfile)
+
+	names, err = store.list()
+	assert.OK(t, err)
+	assert.Equal(t, names, []string{defaultCredentialProfile, "ci"})
+}
+
+func TestCredentialProfileAddCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		name        string
+		in          string
+		force       bool
+		expected    string
+		expectedErr error
+	}{
+		"from stdin": {
+			name:     "work",
+			in:       "credential\n",
+			expected: "credential",
+		},
+		"exists": {
+			name:        "ci",
+			in:          "credential",
+			expectedErr: ErrCredentialProfileAlreadyExists("ci"),
+		},
+		"overwrite": {
+			name:     "ci",
+			in:       "credential",
+			force:    true,
+			expected: "credential",
+		},
+		"empty": {
+			name:        "work",
+			in:          " \n",
+			expectedErr: ErrEmptyCredential,
+		},
+		"invalid name": {
+			name:        "../work",
+			expectedErr: ErrInvalidCredentialProfileName("../work"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			profiles := &CredentialProfileStore{dir: func() string { return dir }}
+			assert.OK(t, profiles.write("ci", []byte("ci")))
+
+			io := fakeui.NewIO(t)
+			io.In.Piped = true
+			io.In.WriteString(tc.in)
+
+			cmd := CredentialProfileAddCommand{
+				name:     cli.StringValue{Value: tc.name},
+				force:    tc.force,
+				io:       io,
+				profiles: profiles,
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.expectedErr)
+			if tc.expectedErr == nil {
+				credential, err := profiles.credential(tc.name).Read()
+				assert.OK(t, err)
+				assert.Equal(t, string(credential), tc.expected)
+			}
+		})
+	}
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/spf13/cobra"
 )
 
 // Errors
@@ -22,22 +24,31 @@ type CredentialConfig interface {
 	Import() (credentials.Key, error)
 	ConfigDir() configdir.Dir
 	PassphraseReader() credentials.Reader
+	CredentialReader() credentials.Reader
+	CredentialSource() string
+	CredentialFile() *configdir.CredentialFile
+	PassphraseCacheKeyring() Keyring
 
 	Register(app *cli.App)
 }
 
 // NewCredentialConfig creates a new CredentialConfig.
 func NewCredentialConfig(io ui.IO) CredentialConfig {
-	return &credentialConfig{
+	store := &credentialConfig{
 		io: io,
 	}
+	store.profiles = NewCredentialProfileStore(store)
+	return store
 }
 
 type credentialConfig struct {
 	configDir                    ConfigDir
 	credentialReader             *flagCredentialReader
 	credentialPassphrase         string
-	CredentialPassphraseCacheTTL time.Duration
+	credentialPassphraseCacheTTL time.Duration
+	passphraseCacheBackend       string
+	profile                      string
+	profiles                     *CredentialProfileStore
 	io                           ui.IO
 }
 
@@ -56,31 +67,113 @@ func (store *credentialConfig) Register(app *cli.App) {
 	store.credentialReader = &flagCredentialReader{}
 	store.credentialReader.Flag = app.PersistentFlags().StringVar(&store.credentialReader.value, "credential", "", "Use a specific account credential to authenticate to the API. This overrides the credential stored in the configuration directory.")
 	app.PersistentFlags().StringVarP(&store.credentialPassphrase, "p", "p", "", "").NoEnvar().Hidden() // Shorthand -p is deprecated. Use --credential-passphrase instead.
-	app.PersistentFlags().StringVar(&store.credentialPassphrase, "credential-passphrase", "", "The passphrase to unlock your credential file. When set, it will not prompt for the passphrase, nor cache it in the OS keyring. Please only use this if you know what you're doing and ensure your passphrase doesn't end up in bash history.")
-	app.PersistentFlags().DurationVar(&store.CredentialPassphraseCacheTTL, "credential-passphrase-cache-ttl", 5*time.Minute, "Cache the credential passphrase in the OS keyring for this duration. The cache is automatically cleared after the timer runs out. Each time the passphrase is read from the cache the timer is reset. Passphrase caching is turned on by default for 5 minutes. Turn it off by setting the duration to 0.")
+	app.PersistentFlags().StringVar(&store.credentialPassphrase, "credential-passphrase", "", "The passphrase to unlock your credential file. When set, it will not prompt for the passphrase, nor cache it. Please only use this if you know what you're doing and ensure your passphrase doesn't end up in bash history.")
+	app.PersistentFlags().DurationVar(&store.credentialPassphraseCacheTTL, "credential-passphrase-cache-ttl", 5*time.Minute, "Cache the credential passphrase for this duration, in the backend set with --passphrase-cache-backend. The cache is automatically cleared after the timer runs out. Each time the passphrase is read from the cache the timer is reset. Passphrase caching is turned on by default for 5 minutes. Turn it off by setting the duration to 0.")
+	app.PersistentFlags().StringVar(&store.passphraseCacheBackend, "passphrase-cache-backend", passphraseCacheBackendKeyring, "Where to cache the credential passphrase: keyring caches it in the OS keyring, file in a file in the configuration directory that is encrypted with a key of this machine and user, for systems without an OS keyring, and none does not cache it.")
+	app.PersistentFlags().StringVar(&store.profile, "profile", "", "Use the credential of this profile instead of the current profile. See `secrethub credential profile` for managing profiles.")
+	app.Root.AddPersistentPreRunE(func(_ *cobra.Command, _ []string) error {
+		_, err := newPassphraseCacheKeyring(store.passphraseCacheBackend, store.ConfigDir().Path())
+		if err != nil {
+			return err
+		}
+		if store.profile == "" {
+			return nil
+		}
+		return validateCredentialProfileName(store.profile)
+	})
 }
 
 // Provider retrieves a credential from the store.
-// When a credential is set, that credential is returned,
-// otherwise the credential is read from the configured file.
+// When the credential is unlocked in the agent, the agent is used to sign requests and read secrets.
+// Otherwise, when a credential is set, that credential is returned,
+// or the credential is read from the configured file.
 func (store *credentialConfig) Provider() credentials.Provider {
+	if !store.IsPassphraseSet() {
+		provider, ok := store.agentProvider()
+		if ok {
+			return provider
+		}
+	}
 	return credentials.UseKey(store.getCredentialReader()).Passphrase(store.PassphraseReader())
 }
 
+// agentProvider returns a provider that uses the configured credential in the agent, if it was added to a running agent.
+// When the credential was added with confirmation, the agent asks the user to confirm its use.
+// If the use is not confirmed, the credential is unlocked by the command itself.
+func (store *credentialConfig) agentProvider() (credentials.Provider, bool) {
+	raw, err := store.getCredentialReader().Read()
+	if err != nil {
+		return nil, false
+	}
+
+	agent := newAgentClient(agentSocketPath(store.ConfigDir().Path()))
+	local := credentials.UseKey(store.getCredentialReader()).Passphrase(store.PassphraseReader())
+	provider, err := newAgentProvider(agent, agentCredentialID(raw), local)
+	if err != nil {
+		if err != ErrAgentNotRunning && err != ErrAgentCredentialNotFound {
+			logger.Debugf("not using the credential in the agent: %s", err)
+		}
+		return nil, false
+	}
+	return provider, true
+}
+
 func (store *credentialConfig) Import() (credentials.Key, error) {
 	return credentials.ImportKey(store.getCredentialReader(), store.PassphraseReader())
 }
 
 func (store *credentialConfig) getCredentialReader() credentials.Reader {
 	if store.credentialReader.value == "" {
-		return store.configDir.Credential()
+		return store.CredentialFile()
 	}
 	return store.credentialReader
 }
 
+// CredentialFile returns the credential file of the profile that is used: the profile set with
+// --profile, or the current profile when no profile is set.
+func (store *credentialConfig) CredentialFile() *configdir.CredentialFile {
+	profile := store.profile
+	if profile == "" {
+		current, err := store.profiles.current()
+		if err != nil {
+			current = defaultCredentialProfile
+		}
+		profile = current
+	}
+	return store.profiles.credential(profile)
+}
+
 // PassphraseReader returns a PassphraseReader configured by the flags.
 func (store *credentialConfig) PassphraseReader() credentials.Reader {
-	return NewPassphraseReader(store.io, store.credentialPassphrase, store.CredentialPassphraseCacheTTL)
+	ttl := store.credentialPassphraseCacheTTL
+	if store.passphraseCacheBackend == passphraseCacheBackendNone {
+		ttl = 0
+	}
+	cleaner := NewKeyringCleaner(store.ConfigDir().Path(), store.passphraseCacheBackend)
+	return NewPassphraseReader(store.io, store.credentialPassphrase, NewPassphraseCache(ttl, cleaner, store.PassphraseCacheKeyring()))
+}
+
+// PassphraseCacheKeyring returns the keyring of the configured passphrase cache backend.
+func (store *credentialConfig) PassphraseCacheKeyring() Keyring {
+	keyring, err := newPassphraseCacheKeyring(store.passphraseCacheBackend, store.ConfigDir().Path())
+	if err != nil {
+		// The backend is validated before the command runs.
+		return noKeyring{}
+	}
+	return keyring
+}
+
+// CredentialReader returns a reader for the configured credential, which is still locked.
+func (store *credentialConfig) CredentialReader() credentials.Reader {
+	return store.getCredentialReader()
+}
+
+// CredentialSource describes where the configured credential is read from.
+func (store *credentialConfig) CredentialSource() string {
+	if store.credentialReader.value == "" {
+		return store.CredentialFile().Path()
+	}
+	return store.credentialReader.Source()
 }
 
 type flagCredentialReader struct {
@@ -2,6 +2,7 @@ package secrethub
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 )
 
+// CredentialUpdatePassphraseCommand changes the passphrase of the local credential.
 type CredentialUpdatePassphraseCommand struct {
 	io              ui.IO
 	credentialStore CredentialConfig
@@ -24,16 +26,22 @@ func NewCredentialUpdatePassphraseCommand(io ui.IO, credentialStore CredentialCo
 
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *CredentialUpdatePassphraseCommand) Register(r cli.Registerer) {
-	clause := r.Command("update-passphrase", "Update the passphrase of your local key credential file.")
+	clause := r.Command("passphrase", "Change the passphrase of your local key credential file.")
+	clause.HelpLong("The credential is unlocked with the current passphrase and locked again with the new passphrase. " +
+		"The credential itself does not change, so it does not have to be re-created. " +
+		"When the credential is unlocked in a running agent, it is updated in the agent, " +
+		"and the old passphrase is removed from the passphrase cache.")
+	// Alias for backwards compatibility with the old name of the command.
+	clause.Alias("update-passphrase")
 
 	clause.BindAction(cmd.Run)
 	clause.BindArguments(nil)
 }
 
-// Run upgrades the configuration in the profile directory to the new version.
+// Run re-encrypts the credential with a new passphrase.
 func (cmd *CredentialUpdatePassphraseCommand) Run() error {
-	if !cmd.credentialStore.ConfigDir().Credential().Exists() {
-		fmt.Println("No credentials. Nothing to do.")
+	if !cmd.credentialStore.CredentialFile().Exists() {
+		fmt.Fprintln(cmd.io.Output(), "No credentials. Nothing to do.")
 		return nil
 	}
 	// Run command
@@ -41,7 +49,7 @@ func (cmd *CredentialUpdatePassphraseCommand) Run() error {
 		cmd.io,
 		fmt.Sprintf(
 			"Do you want to update the passphrase of your local key credential stored at %s?",
-			cmd.credentialStore.ConfigDir(),
+			cmd.credentialStore.CredentialFile().Path(),
 		),
 		ui.DefaultYes,
 	)
@@ -63,6 +71,16 @@ func (cmd *CredentialUpdatePassphraseCommand) Run() error {
 	if err != nil {
 		return err
 	}
+
+	unlocked, err := credential.Export()
+	if err != nil {
+		return err
+	}
+	_, fingerprint, err := credential.Verifier().Export()
+	if err != nil {
+		return err
+	}
+
 	if passphrase != "" {
 		credential = credential.Passphrase(credentials.FromString(passphrase))
 	}
@@ -71,12 +89,68 @@ func (cmd *CredentialUpdatePassphraseCommand) Run() error {
 		return err
 	}
 
-	err = cmd.credentialStore.ConfigDir().Credential().Write(exportedCredential)
+	err = cmd.credentialStore.CredentialFile().Write(exportedCredential)
+	if err != nil {
+		return err
+	}
+
+	err = cmd.updateAgent(exportedCredential, unlocked, fingerprint)
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintln(cmd.io.Output(), "Successfully updated passphrase!")
+	// The old passphrase may still be cached in the keyring.
+	keyring := cmd.credentialStore.PassphraseCacheKeyring()
+	if keyring.IsAvailable() {
+		err = keyring.Delete()
+		if err != nil && err != ErrKeyringItemNotFound {
+			return err
+		}
+	}
+
+	ui.Successf(cmd.io.Output(), "Successfully updated passphrase!\n")
+
+	return nil
+}
+
+// updateAgent replaces the credential in a running agent, so that the agent keeps serving
+// the credential that is stored with the new passphrase. The credential keeps its settings
+// and the time at which it is removed from the agent. Nothing happens when no agent is running
+// or when the credential is not unlocked in the agent.
+func (cmd *CredentialUpdatePassphraseCommand) updateAgent(stored []byte, unlocked []byte, fingerprint string) error {
+	agent := newAgentClient(agentSocketPath(cmd.credentialStore.ConfigDir().Path()))
+	infos, err := agent.credentials()
+	if err == ErrAgentNotRunning {
+		return nil
+	} else if err != nil {
+		return err
+	}
 
+	for _, info := range infos {
+		if info.Fingerprint != fingerprint {
+			continue
+		}
+
+		_, err = agent.remove(agentRemoveRequest{Fingerprint: fingerprint})
+		if err != nil {
+			return err
+		}
+
+		req := agentAddRequest{
+			ID:          agentCredentialID(stored),
+			Fingerprint: fingerprint,
+			Source:      info.Source,
+			Credential:  string(unlocked),
+			Confirm:     info.Confirm,
+		}
+		if info.ExpiresAt != nil {
+			ttl := time.Until(*info.ExpiresAt)
+			if ttl <= 0 {
+				return nil
+			}
+			req.TTL = int64((ttl + time.Second - 1) / time.Second)
+		}
+		return agent.add(req)
+	}
 	return nil
 }
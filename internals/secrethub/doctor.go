@@ -0,0 +1,249 @@
+package secrethub
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"text/tabwriter"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/clip"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/pager"
+)
+
+// Errors
+var (
+	ErrDoctorChecksFailed = errMain.Code("doctor_checks_failed").ErrorPref("%d of the checks failed")
+)
+
+// Statuses of a check of the doctor command.
+const (
+	doctorPass = "PASS"
+	doctorWarn = "WARN"
+	doctorFail = "FAIL"
+)
+
+// maxClockSkew is the largest difference with the clock of the API that is not reported.
+const maxClockSkew = time.Minute
+
+// doctorResult is the outcome of a check, with a hint on how to fix it when it did not pass.
+type doctorResult struct {
+	status  string
+	message string
+	hint    string
+}
+
+// DoctorCommand checks whether the CLI is set up correctly and whether it can reach the API.
+type DoctorCommand struct {
+	io              ui.IO
+	credentialStore CredentialConfig
+	clientFactory   ClientFactory
+	passphraseCache func() Keyring
+	clipBackend     func() string
+	pagerCommand    func() (string, error)
+	lookPath        func(string) (string, error)
+	now             func() time.Time
+}
+
+// NewDoctorCommand creates a new DoctorCommand.
+func NewDoctorCommand(io ui.IO, clientFactory ClientFactory, credentialStore CredentialConfig) *DoctorCommand {
+	return &DoctorCommand{
+		io:              io,
+		credentialStore: credentialStore,
+		clientFactory:   clientFactory,
+		passphraseCache: credentialStore.PassphraseCacheKeyring,
+		clipBackend:     clip.Backend,
+		pagerCommand:    pager.Command,
+		lookPath:        exec.LookPath,
+		now:             time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *DoctorCommand) Register(r cli.Registerer) {
+	clause := r.Command("doctor", "Check whether the CLI is set up correctly and can reach the API.")
+	clause.HelpLong("The doctor checks the permissions of the configuration directory, whether a credential is configured and can be decrypted, " +
+		"whether the passphrase cache, a clipboard and a terminal pager are available, whether the API can be reached through the configured proxy " +
+		"and whether the clock of this machine is in sync with the API. " +
+		"Every check is reported as PASS, WARN or FAIL, with a hint on how to fix it. " +
+		"The command fails when any of the checks fails.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run runs all checks and prints their results.
+func (cmd *DoctorCommand) Run() error {
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 4, 2, ' ', 0)
+
+	failed := 0
+	report := func(name string, result doctorResult) {
+		if result.status == doctorFail {
+			failed++
+		}
+		fmt.Fprintf(w, "%s\t%s:\t%s\n", result.status, name, result.message)
+		if result.hint != "" {
+			fmt.Fprintf(w, "\t\t%s\n", result.hint)
+		}
+	}
+
+	report("Configuration directory", cmd.checkConfigDir())
+	credential := cmd.checkCredential()
+	report("Credential", credential)
+	if credential.status == doctorPass {
+		report("Credential decryption", cmd.checkDecryption())
+	}
+	report("Passphrase cache", cmd.checkPassphraseCache())
+	report("Clipboard", cmd.checkClipboard())
+	report("Pager", cmd.checkPager())
+	report("Proxy", cmd.checkProxy())
+
+	api, skew := cmd.checkAPI()
+	report("API", api)
+	if skew != nil {
+		report("Clock", *skew)
+	}
+
+	err := w.Flush()
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return ErrDoctorChecksFailed(failed)
+	}
+	return nil
+}
+
+// checkConfigDir checks that the configuration directory exists and is only accessible by the current user.
+func (cmd *DoctorCommand) checkConfigDir() doctorResult {
+	path := cmd.credentialStore.ConfigDir().Path()
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return doctorResult{doctorWarn, path + " does not exist", "Run `secrethub init` to set up the CLI."}
+	} else if err != nil {
+		return doctorResult{doctorFail, err.Error(), "Make sure the current user can access the directory or set --config-dir."}
+	}
+	if !info.IsDir() {
+		return doctorResult{doctorFail, path + " is not a directory", "Remove the file or set --config-dir to another location."}
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return doctorResult{doctorWarn, fmt.Sprintf("%s can be accessed by other users (%s)", path, info.Mode().Perm()), "Run `chmod 700 " + path + "`."}
+	}
+	return doctorResult{doctorPass, path, ""}
+}
+
+// checkCredential checks that a credential is configured.
+func (cmd *DoctorCommand) checkCredential() doctorResult {
+	source := cmd.credentialStore.CredentialSource()
+	_, err := cmd.credentialStore.CredentialReader().Read()
+	if err != nil {
+		return doctorResult{doctorFail, "no credential found in " + source, "Run `secrethub init` or set the SECRETHUB_CREDENTIAL environment variable."}
+	}
+	return doctorResult{doctorPass, "read from " + source, ""}
+}
+
+// checkDecryption checks that the configured credential can be decrypted. This asks for the passphrase of the credential.
+func (cmd *DoctorCommand) checkDecryption() doctorResult {
+	_, err := cmd.credentialStore.Import()
+	if err != nil {
+		return doctorResult{doctorFail, err.Error(), "Check the passphrase of the credential, or restore it with `secrethub init --backup-code`."}
+	}
+	return doctorResult{doctorPass, "the credential can be decrypted", ""}
+}
+
+// checkPassphraseCache checks whether the backend set with --passphrase-cache-backend is available to cache the credential passphrase in.
+func (cmd *DoctorCommand) checkPassphraseCache() doctorResult {
+	keyring := cmd.passphraseCache()
+	if _, ok := keyring.(noKeyring); ok {
+		return doctorResult{doctorPass, "turned off", ""}
+	}
+	if !keyring.IsAvailable() {
+		return doctorResult{doctorWarn, "not available", "The passphrase is not cached. Set --passphrase-cache-backend=file on systems without an OS keyring."}
+	}
+	return doctorResult{doctorPass, "available", ""}
+}
+
+// checkClipboard checks whether the tools that the selected clipboard backend needs are installed.
+func (cmd *DoctorCommand) checkClipboard() doctorResult {
+	backend := cmd.clipBackend()
+	switch backend {
+	case clip.BackendWayland:
+		if _, err := cmd.lookPath("wl-copy"); err != nil {
+			return doctorResult{doctorWarn, "wl-copy is not installed", "Install wl-clipboard or select another backend with --clipboard-backend."}
+		}
+	case clip.BackendOSC52:
+		return doctorResult{doctorPass, "osc52, which the terminal emulator has to allow", ""}
+	case clip.BackendSystem:
+		if runtime.GOOS != "windows" && runtime.GOOS != "darwin" && !cmd.anyInstalled("xclip", "xsel", "wl-copy") {
+			return doctorResult{doctorWarn, "xclip, xsel and wl-copy are not installed", "Install xclip or xsel, or use --clipboard-backend=osc52 in a terminal emulator that supports it."}
+		}
+	}
+	return doctorResult{doctorPass, backend, ""}
+}
+
+// anyInstalled returns whether any of the given programs is installed.
+func (cmd *DoctorCommand) anyInstalled(programs ...string) bool {
+	for _, program := range programs {
+		if _, err := cmd.lookPath(program); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPager checks whether a terminal pager is available.
+func (cmd *DoctorCommand) checkPager() doctorResult {
+	path, err := cmd.pagerCommand()
+	if err != nil {
+		return doctorResult{doctorWarn, "no pager found", "Set $PAGER or install less, so that long output can be paged."}
+	}
+	return doctorResult{doctorPass, path, ""}
+}
+
+// checkProxy reports the proxy that requests to the API go through.
+func (cmd *DoctorCommand) checkProxy() doctorResult {
+	proxy, err := cmd.clientFactory.Proxy()
+	if err != nil {
+		return doctorResult{doctorFail, err.Error(), "Fix the HTTPS_PROXY or HTTP_PROXY environment variable, or set --proxy-address."}
+	}
+	if proxy == nil {
+		return doctorResult{doctorPass, "no proxy", ""}
+	}
+	return doctorResult{doctorPass, proxy.Redacted(), ""}
+}
+
+// checkAPI checks that the API can be reached. When it can, the clock skew with the API is checked as well.
+func (cmd *DoctorCommand) checkAPI() (doctorResult, *doctorResult) {
+	remote := cmd.clientFactory.APIRemote()
+	client := &http.Client{Transport: cmd.clientFactory.Transport()}
+
+	start := cmd.now()
+	resp, err := client.Get(remote)
+	if err != nil {
+		return doctorResult{doctorFail, err.Error(), "Check your network connection and proxy settings, or set --tls-ca-cert when a proxy intercepts TLS connections."}, nil
+	}
+	resp.Body.Close()
+	latency := cmd.now().Sub(start).Round(time.Millisecond)
+
+	api := doctorResult{doctorPass, fmt.Sprintf("%s reached in %s", remote, latency), ""}
+	if resp.StatusCode >= 500 {
+		api = doctorResult{doctorWarn, fmt.Sprintf("%s responded with %s", remote, resp.Status), "The API may be unavailable. Try again later."}
+	}
+
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return api, nil
+	}
+	skew := cmd.now().Sub(serverTime).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew {
+		return api, &doctorResult{doctorWarn, fmt.Sprintf("differs %s from the API", skew), "Synchronize the clock of this machine, for example by enabling NTP. Requests are signed with the current time."}
+	}
+	return api, &doctorResult{doctorPass, "in sync with the API", ""}
+}
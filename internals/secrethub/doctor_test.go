@@ -0,0 +1,138 @@
+package secrethub
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/clip"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+type doctorClientFactory struct {
+	ClientFactory
+	remote string
+}
+
+func (f doctorClientFactory) APIRemote() string {
+	return f.remote
+}
+
+func (f doctorClientFactory) Transport() http.RoundTripper {
+	return http.DefaultTransport
+}
+
+func TestDoctorCommand_checkAPI(t *testing.T) {
+	serverTime := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		status        int
+		now           time.Time
+		expectedAPI   string
+		expectedClock string
+	}{
+		"in sync": {
+			status:        http.StatusOK,
+			now:           serverTime.Add(10 * time.Second),
+			expectedAPI:   doctorPass,
+			expectedClock: doctorPass,
+		},
+		"clock behind": {
+			status:        http.StatusNotFound,
+			now:           serverTime.Add(-5 * time.Minute),
+			expectedAPI:   doctorPass,
+			expectedClock: doctorWarn,
+		},
+		"server error": {
+			status:        http.StatusBadGateway,
+			now:           serverTime,
+			expectedAPI:   doctorWarn,
+			expectedClock: doctorPass,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			cmd := DoctorCommand{
+				clientFactory: doctorClientFactory{remote: server.URL},
+				now: func() time.Time {
+					return tc.now
+				},
+			}
+
+			api, clock := cmd.checkAPI()
+
+			assert.Equal(t, api.status, tc.expectedAPI)
+			assert.Equal(t, clock != nil, true)
+			assert.Equal(t, clock.status, tc.expectedClock)
+		})
+	}
+}
+
+func TestDoctorCommand_checkAPI_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	cmd := DoctorCommand{
+		clientFactory: doctorClientFactory{remote: server.URL},
+		now:           time.Now,
+	}
+
+	api, clock := cmd.checkAPI()
+
+	assert.Equal(t, api.status, doctorFail)
+	assert.Equal(t, clock == nil, true)
+}
+
+func TestDoctorCommand_checkClipboard(t *testing.T) {
+	cases := map[string]struct {
+		backend   string
+		installed []string
+		expected  string
+	}{
+		"wayland": {
+			backend:   clip.BackendWayland,
+			installed: []string{"wl-copy"},
+			expected:  doctorPass,
+		},
+		"wayland not installed": {
+			backend:  clip.BackendWayland,
+			expected: doctorWarn,
+		},
+		"osc52": {
+			backend:  clip.BackendOSC52,
+			expected: doctorPass,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := DoctorCommand{
+				clipBackend: func() string {
+					return tc.backend
+				},
+				lookPath: func(program string) (string, error) {
+					for _, installed := range tc.installed {
+						if program == installed {
+							return "/usr/bin/" + program, nil
+						}
+					}
+					return "", errors.New("not found")
+				},
+			}
+
+			actual := cmd.checkClipboard()
+
+			assert.Equal(t, actual.status, tc.expected)
+		})
+	}
+}
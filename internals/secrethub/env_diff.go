@@ -0,0 +1,155 @@
+package secrethub
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// Errors
+var (
+	ErrNothingToCompare = errMain.Code("nothing_to_compare").Error("nothing to compare: use --to-var or --to-env-file to configure the environment to compare with")
+)
+
+// EnvDiffCommand is a command to compare two resolved environments.
+type EnvDiffCommand struct {
+	io          ui.IO
+	newClient   newClientFunc
+	environment *environment
+	toVars      map[string]string
+	toEnvFile   string
+	showValues  bool
+}
+
+// NewEnvDiffCommand creates a new EnvDiffCommand.
+func NewEnvDiffCommand(io ui.IO, newClient newClientFunc) *EnvDiffCommand {
+	return &EnvDiffCommand{
+		io:          io,
+		newClient:   newClient,
+		environment: newEnvironment(io, newClient),
+		toVars:      make(map[string]string),
+	}
+}
+
+// Register adds a CommandClause and it's args and flags to a Registerer.
+func (cmd *EnvDiffCommand) Register(r cli.Registerer) {
+	clause := r.Command("diff", "[BETA] Show which environment variables differ between two environments.")
+	clause.HelpLong("The environment configured with the regular flags is compared with the same environment in which the template variables set with --to-var and the env-file set with --to-env-file are overridden. " +
+		"Added variables are prefixed with +, removed variables with - and changed variables with ~. " +
+		"For example, to validate the promotion from staging to production:\n\n" +
+		"  secrethub env diff --var env=staging --to-var env=prod\n\n" +
+		"This command is hidden because it is still in beta. Future versions may break.")
+
+	cmd.environment.register(clause)
+	clause.Flags().StringToStringVar(&cmd.toVars, "to-var", nil, "Override the value of a template variable in the environment to compare with, e.g. --to-var env=prod")
+	clause.Flags().StringVar(&cmd.toEnvFile, "to-env-file", "", "The path to the env-file of the environment to compare with.")
+	clause.Flags().BoolVar(&cmd.showValues, "show-values", false, "Also print the values of the variables that differ. Note that this prints secrets.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run executes the command.
+func (cmd *EnvDiffCommand) Run() error {
+	if len(cmd.toVars) == 0 && cmd.toEnvFile == "" {
+		return ErrNothingToCompare
+	}
+
+	to := *cmd.environment
+	to.templateVars = make(map[string]string, len(cmd.environment.templateVars)+len(cmd.toVars))
+	for name, value := range cmd.environment.templateVars {
+		to.templateVars[name] = value
+	}
+	for name, value := range cmd.toVars {
+		to.templateVars[name] = value
+	}
+	if cmd.toEnvFile != "" {
+		to.envFile = cmd.toEnvFile
+	}
+
+	secretReader := newCachingSecretReader(newSecretReader(cmd.newClient))
+
+	fromEnv, err := cmd.environment.env()
+	if err != nil {
+		return err
+	}
+	from, err := cmd.environment.resolve(fromEnv, secretReader)
+	if err != nil {
+		return err
+	}
+
+	toEnv, err := to.env()
+	if err != nil {
+		return err
+	}
+	toResolved, err := to.resolve(toEnv, secretReader)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range diffEnvs(from, toResolved) {
+		if cmd.showValues {
+			fmt.Fprintln(cmd.io.Output(), change.StringWithValues())
+		} else {
+			fmt.Fprintln(cmd.io.Output(), change.String())
+		}
+	}
+
+	return nil
+}
+
+// envChange is a difference of a single environment variable between two environments.
+type envChange struct {
+	name     string
+	kind     rune
+	oldValue string
+	newValue string
+}
+
+const (
+	envChangeAdded   = '+'
+	envChangeRemoved = '-'
+	envChangeChanged = '~'
+)
+
+// String returns the kind of change and the name of the variable.
+func (c envChange) String() string {
+	return fmt.Sprintf("%c %s", c.kind, c.name)
+}
+
+// StringWithValues returns the kind of change, the name of the variable and its values.
+func (c envChange) StringWithValues() string {
+	switch c.kind {
+	case envChangeAdded:
+		return fmt.Sprintf("%s=%s", c, c.newValue)
+	case envChangeRemoved:
+		return fmt.Sprintf("%s=%s", c, c.oldValue)
+	default:
+		return fmt.Sprintf("%s=%s -> %s", c, c.oldValue, c.newValue)
+	}
+}
+
+// diffEnvs returns the changes needed to get from one environment to another, sorted by name.
+func diffEnvs(from, to map[string]string) []envChange {
+	var changes []envChange
+	for name, oldValue := range from {
+		newValue, found := to[name]
+		if !found {
+			changes = append(changes, envChange{name: name, kind: envChangeRemoved, oldValue: oldValue})
+		} else if newValue != oldValue {
+			changes = append(changes, envChange{name: name, kind: envChangeChanged, oldValue: oldValue, newValue: newValue})
+		}
+	}
+	for name, newValue := range to {
+		if _, found := from[name]; !found {
+			changes = append(changes, envChange{name: name, kind: envChangeAdded, newValue: newValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].name < changes[j].name
+	})
+	return changes
+}
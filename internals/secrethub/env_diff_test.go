@@ -0,0 +1,49 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestDiffEnvs(t *testing.T) {
+	cases := map[string]struct {
+		from     map[string]string
+		to       map[string]string
+		expected []string
+	}{
+		"equal": {
+			from:     map[string]string{"FOO": "foo"},
+			to:       map[string]string{"FOO": "foo"},
+			expected: nil,
+		},
+		"added, removed and changed": {
+			from: map[string]string{
+				"DB_PASSWORD": "staging-password",
+				"DEBUG":       "true",
+				"USER":        "app",
+			},
+			to: map[string]string{
+				"DB_PASSWORD": "prod-password",
+				"SENTRY_DSN":  "https://sentry",
+				"USER":        "app",
+			},
+			expected: []string{
+				"~ DB_PASSWORD=staging-password -> prod-password",
+				"- DEBUG=true",
+				"+ SENTRY_DSN=https://sentry",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var actual []string
+			for _, change := range diffEnvs(tc.from, tc.to) {
+				actual = append(actual, change.StringWithValues())
+			}
+
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
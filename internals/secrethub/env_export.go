@@ -0,0 +1,154 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// Errors
+var (
+	ErrUnknownExportShell = errMain.Code("unknown_export_shell").ErrorPref("unknown shell %s: the options are bash, fish, powershell, dotenv and json")
+)
+
+// envExportFormats contains the functions that format the exported variables for every supported shell.
+var envExportFormats = map[string]func(name, value string) string{
+	"bash":       exportBash,
+	"fish":       exportFish,
+	"powershell": exportPowerShell,
+	"dotenv":     exportDotEnv,
+}
+
+// EnvExportCommand is a command to print the resolved environment in a format for a shell.
+type EnvExportCommand struct {
+	io            ui.IO
+	newClient     newClientFunc
+	environment   *environment
+	shell         string
+	all           bool
+	noMaskWarning bool
+}
+
+// NewEnvExportCommand creates a new EnvExportCommand.
+func NewEnvExportCommand(io ui.IO, newClient newClientFunc) *EnvExportCommand {
+	return &EnvExportCommand{
+		io:          io,
+		newClient:   newClient,
+		environment: newEnvironment(io, newClient),
+	}
+}
+
+// Register adds a CommandClause and it's args and flags to a Registerer.
+func (cmd *EnvExportCommand) Register(r cli.Registerer) {
+	clause := r.Command("export", "[BETA] Print the resolved environment in a format that can be evaluated by a shell or read from a file.")
+	clause.HelpLong("By default, only the variables that differ from the current environment are printed. " +
+		"For example, to load the secrets in the current bash shell:\n\n" +
+		"  eval \"$(secrethub env export --shell=bash)\"\n\n" +
+		"This command is hidden because it is still in beta. Future versions may break.")
+
+	cmd.environment.register(clause)
+	clause.Flags().StringVar(&cmd.shell, "shell", "bash", "The format of the output. The options are bash, fish, powershell, dotenv and json.")
+	_ = clause.Cmd.RegisterFlagCompletionFunc("shell", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"bash", "fish", "powershell", "dotenv", "json"}, cobra.ShellCompDirectiveDefault
+	})
+	clause.Flags().BoolVar(&cmd.all, "all", false, "Also print the variables that are unchanged from the current environment.")
+	clause.Flags().BoolVar(&cmd.noMaskWarning, "no-mask-warning", false, "Do not ask for confirmation before printing secrets to a terminal.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run executes the command.
+func (cmd *EnvExportCommand) Run() error {
+	format, ok := envExportFormats[cmd.shell]
+	if !ok && cmd.shell != "json" {
+		return ErrUnknownExportShell(cmd.shell)
+	}
+
+	envValues, err := cmd.environment.env()
+	if err != nil {
+		return err
+	}
+
+	resolved, err := cmd.environment.resolve(envValues, newCachingSecretReader(newSecretReader(cmd.newClient)))
+	if err != nil {
+		return err
+	}
+
+	if !cmd.all {
+		cmd.environment.removeUnchanged(resolved)
+	}
+
+	if !cmd.noMaskWarning && !cmd.io.IsOutputPiped() {
+		confirmed, err := ui.AskYesNo(cmd.io, "This prints the values of secrets in plaintext to your terminal. Do you want to continue?", ui.DefaultNo)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return nil
+		}
+	}
+
+	if cmd.shell == "json" {
+		return exportJSON(cmd.io.Output(), resolved)
+	}
+
+	names := make([]string, 0, len(resolved))
+	for name := range resolved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintln(cmd.io.Output(), format(name, resolved[name]))
+	}
+	return nil
+}
+
+// exportBash returns a statement that exports the variable in bash and other POSIX shells.
+// The value is single quoted, so nothing in it is expanded.
+func exportBash(name, value string) string {
+	return fmt.Sprintf("export %s=%s", name, shellQuote(value))
+}
+
+// shellQuote quotes the value with single quotes for POSIX shells.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// exportFish returns a statement that exports the variable in fish. Within single quotes,
+// fish only interprets escaped backslashes and single quotes.
+func exportFish(name, value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	return fmt.Sprintf("set -gx %s '%s'", name, value)
+}
+
+// exportPowerShell returns a statement that sets the variable in PowerShell.
+// Within single quotes, PowerShell only interprets doubled single quotes.
+func exportPowerShell(name, value string) string {
+	return fmt.Sprintf("$env:%s = '%s'", name, strings.ReplaceAll(value, "'", "''"))
+}
+
+// exportDotEnv returns a line that defines the variable in a .env file.
+// The value is double quoted, with backslashes, double quotes, dollar signs
+// and newlines escaped.
+func exportDotEnv(name, value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`).Replace(value)
+	return fmt.Sprintf(`%s="%s"`, name, value)
+}
+
+// exportJSON writes the variables as a JSON object, sorted by name.
+func exportJSON(w io.Writer, env map[string]string) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(env)
+}
@@ -0,0 +1,35 @@
+package secrethub
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestEnvExportFormats(t *testing.T) {
+	const value = "it's a \"$ecret\"\\\nline"
+
+	cases := map[string]string{
+		"bash":       `export FOO='it'\''s a "$ecret"\` + "\nline'",
+		"fish":       `set -gx FOO 'it\'s a "$ecret"\\` + "\nline'",
+		"powershell": `$env:FOO = 'it''s a "$ecret"\` + "\nline'",
+		"dotenv":     `FOO="it's a \"\$ecret\"\\\nline"`,
+	}
+
+	for shell, expected := range cases {
+		t.Run(shell, func(t *testing.T) {
+			actual := envExportFormats[shell]("FOO", value)
+			assert.Equal(t, actual, expected)
+		})
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	err := exportJSON(buf, map[string]string{"FOO": "foo", "BAR": "<bar>"})
+	assert.OK(t, err)
+
+	assert.Equal(t, buf.String(), "{\n  \"BAR\": \"<bar>\",\n  \"FOO\": \"foo\"\n}\n")
+}
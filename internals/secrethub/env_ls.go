@@ -2,6 +2,7 @@ package secrethub
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
@@ -40,10 +41,16 @@ func (cmd *EnvListCommand) Run() error {
 		return err
 	}
 
-	for key, value := range env {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
 		// For now only environment variables in which a secret is loaded are printed.
 		// TODO: Make this behavior configurable.
-		if value.containsSecret() {
+		if env[key].containsSecret() {
 			fmt.Fprintln(cmd.io.Output(), key)
 		}
 	}
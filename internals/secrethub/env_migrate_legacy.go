@@ -0,0 +1,253 @@
+package secrethub
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secretspec"
+)
+
+// Errors
+var (
+	ErrLegacyEnvDirNotFound = errMain.Code("legacy_env_dir_not_found").ErrorPref("the legacy environment directory %s does not exist")
+	ErrCannotDeleteLegacy   = errMain.Code("cannot_delete_legacy_env_dir").ErrorPref("could not delete the legacy environment directory: %s")
+)
+
+// EnvMigrateLegacyCommand converts a .secretenv directory created by the set command
+// into a secrethub.env file.
+type EnvMigrateLegacyCommand struct {
+	io       ui.IO
+	specFile string
+	envName  string
+	outFile  string
+	force    bool
+	delete   bool
+	keep     bool
+}
+
+// NewEnvMigrateLegacyCommand creates a new EnvMigrateLegacyCommand.
+func NewEnvMigrateLegacyCommand(io ui.IO) *EnvMigrateLegacyCommand {
+	return &EnvMigrateLegacyCommand{
+		io: io,
+	}
+}
+
+// Register adds a CommandClause and it's args and flags to a Registerer.
+func (cmd *EnvMigrateLegacyCommand) Register(r cli.Registerer) {
+	clause := r.Command("migrate-legacy", "[BETA] Convert a "+secretspec.SecretEnvPath+" directory into a "+defaultEnvFile+" file.")
+	clause.HelpLong("The set command stores the values of secrets as plaintext files in the " + secretspec.SecretEnvPath + " directory, which the run command still reads for backwards compatibility. " +
+		"This command writes a " + defaultEnvFile + " file that sources the same environment variables directly from SecretHub, using the secrets.yml file to look up the secret of every variable. " +
+		"Files in the directory that do not correspond to a variable in the secrets.yml file are stale plaintext secrets and are reported. " +
+		"Afterwards, the directory can be securely deleted.\n\n" +
+		"This command is hidden because it is still in beta. Future versions may break.")
+
+	clause.Flags().StringVarP(&cmd.specFile, "in", "i", "secrets.yml", "The path to the secrets.yml file that was used to create the directory.")
+	clause.Flags().StringVar(&cmd.envName, "env", "default", "The name of the environment in the secrets.yml file.")
+	clause.Flags().StringVarP(&cmd.outFile, "out-file", "o", defaultEnvFile, "The path of the env-file to write.")
+	clause.Flags().BoolVarP(&cmd.force, "force", "f", false, "Overwrite the output file if it already exists, without prompting for confirmation.")
+	clause.Flags().BoolVar(&cmd.delete, "delete", false, "Securely delete the directory after the migration, without prompting for confirmation.")
+	clause.Flags().BoolVar(&cmd.keep, "keep", false, "Keep the directory after the migration, without prompting.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run executes the command.
+func (cmd *EnvMigrateLegacyCommand) Run() error {
+	if cmd.delete && cmd.keep {
+		return ErrFlagsConflict("--delete and --keep")
+	}
+
+	dirPath := filepath.Join(secretspec.SecretEnvPath, cmd.envName)
+	files, err := os.ReadDir(dirPath)
+	if os.IsNotExist(err) {
+		return ErrLegacyEnvDirNotFound(dirPath)
+	} else if err != nil {
+		return ErrReadEnvDir(err)
+	}
+
+	vars, err := cmd.readSpecVars()
+	if err != nil {
+		return err
+	}
+
+	var legacyFiles []string
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		legacyFiles = append(legacyFiles, f.Name())
+		if _, ok := vars[f.Name()]; !ok {
+			fmt.Fprintf(cmd.io.Output(), "WARN: %s contains a plaintext value that is not sourced from a secret in %s. It is stale and is not migrated.\n", filepath.Join(dirPath, f.Name()), cmd.specFile)
+		}
+	}
+
+	if len(vars) > 0 {
+		written, err := cmd.writeEnvFile(vars)
+		if err != nil {
+			return err
+		}
+		if !written {
+			return nil
+		}
+		fmt.Fprintf(cmd.io.Output(), "Wrote %d environment variables to %s.\n", len(vars), cmd.outFile)
+	}
+
+	return cmd.deleteLegacyDir(dirPath, legacyFiles)
+}
+
+// readSpecVars returns the variables of the environment in the secrets.yml file, mapping the variable
+// names to the paths of their secrets. When the default secrets.yml file does not exist, no variables are returned.
+func (cmd *EnvMigrateLegacyCommand) readSpecVars() (map[string]string, error) {
+	spec, err := os.ReadFile(cmd.specFile)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(cmd.io.Output(), "WARN: %s does not exist, so the files cannot be mapped to secrets.\n", cmd.specFile)
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, ErrCannotReadFile(cmd.specFile, err)
+	}
+
+	presenter, err := secretspec.NewPresenter("", true, secretspec.DefaultParsers...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = presenter.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, found := presenter.EnvVars(cmd.envName)
+	if !found {
+		fmt.Fprintf(cmd.io.Output(), "WARN: %s does not contain an environment named %s.\n", cmd.specFile, cmd.envName)
+		return map[string]string{}, nil
+	}
+	return vars, nil
+}
+
+// writeEnvFile writes an env-file sourcing the given variables from their secrets.
+// It returns false when the user declined to overwrite an existing file.
+func (cmd *EnvMigrateLegacyCommand) writeEnvFile(vars map[string]string) (bool, error) {
+	_, err := os.Stat(cmd.outFile)
+	if err == nil && !cmd.force {
+		if cmd.io.IsOutputPiped() {
+			return false, ErrFileAlreadyExists
+		}
+
+		confirmed, err := ui.AskYesNo(cmd.io, fmt.Sprintf("File %s already exists, overwrite it?", cmd.outFile), ui.DefaultNo)
+		if err != nil {
+			return false, err
+		}
+		if !confirmed {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return false, nil
+		}
+	}
+
+	err = os.WriteFile(cmd.outFile, []byte(legacyEnvFile(vars)), 0644)
+	if err != nil {
+		return false, ErrCannotWrite(cmd.outFile, err)
+	}
+	return true, nil
+}
+
+// legacyEnvFile returns the contents of an env-file that sources the given variables from their secrets.
+func legacyEnvFile(vars map[string]string) string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&sb, "%s={{ %s }}\n", name, vars[name])
+	}
+	return sb.String()
+}
+
+// deleteLegacyDir securely deletes the legacy directory, after asking for confirmation if needed.
+func (cmd *EnvMigrateLegacyCommand) deleteLegacyDir(dirPath string, files []string) error {
+	if cmd.keep {
+		return nil
+	}
+
+	if !cmd.delete {
+		if cmd.io.IsOutputPiped() {
+			fmt.Fprintf(cmd.io.Output(), "Run this command with --delete to securely delete %s.\n", dirPath)
+			return nil
+		}
+
+		confirmed, err := ui.AskYesNo(
+			cmd.io,
+			fmt.Sprintf("%s contains %d plaintext secrets. Do you want to securely delete it?", dirPath, len(files)),
+			ui.DefaultNo,
+		)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return nil
+		}
+	}
+
+	for _, name := range files {
+		err := shredFile(filepath.Join(dirPath, name))
+		if err != nil {
+			return ErrCannotDeleteLegacy(err)
+		}
+	}
+
+	err := os.Remove(dirPath)
+	if err != nil {
+		return ErrCannotDeleteLegacy(err)
+	}
+
+	// Also remove the parent directory once its last environment is removed.
+	_ = os.Remove(secretspec.SecretEnvPath)
+
+	fmt.Fprintf(cmd.io.Output(), "Deleted %s.\n", dirPath)
+	return nil
+}
+
+// shredFile overwrites the contents of a file with random data before removing it.
+// Note that this is a best effort: file systems and storage devices that do not
+// write in place (e.g. copy-on-write file systems and SSDs) can retain the original data.
+func shredFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	_, err = io.CopyN(f, rand.Reader, info.Size())
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	err = f.Sync()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
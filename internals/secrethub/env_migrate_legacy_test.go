@@ -0,0 +1,35 @@
+package secrethub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestLegacyEnvFile(t *testing.T) {
+	actual := legacyEnvFile(map[string]string{
+		"DB_USER":     "namespace/repo/db/user",
+		"DB_PASSWORD": "namespace/repo/db/password",
+	})
+
+	expected := "DB_PASSWORD={{ namespace/repo/db/password }}\n" +
+		"DB_USER={{ namespace/repo/db/user }}\n"
+	assert.Equal(t, actual, expected)
+}
+
+func TestShredFile(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	path := filepath.Join(dir, "SECRET")
+	err := os.WriteFile(path, []byte("plaintext secret"), 0600)
+	assert.OK(t, err)
+
+	err = shredFile(path)
+	assert.OK(t, err)
+
+	_, err = os.Stat(path)
+	assert.Equal(t, os.IsNotExist(err), true)
+}
@@ -2,6 +2,7 @@ package secrethub
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
@@ -13,6 +14,7 @@ type EnvReadCommand struct {
 	newClient   newClientFunc
 	environment *environment
 	key         cli.StringValue
+	verbose     bool
 }
 
 // NewEnvReadCommand creates a new EnvReadCommand.
@@ -30,6 +32,7 @@ func (cmd *EnvReadCommand) Register(r cli.Registerer) {
 	clause.HelpLong("This command is hidden because it is still in beta. Future versions may break.")
 
 	cmd.environment.register(clause)
+	clause.Flags().BoolVar(&cmd.verbose, "verbose", false, "Also print the source that supplies the environment variable and the sources it overrides.")
 
 	clause.BindAction(cmd.Run)
 	clause.BindArguments([]cli.Argument{
@@ -39,7 +42,7 @@ func (cmd *EnvReadCommand) Register(r cli.Registerer) {
 
 // Run executes the command.
 func (cmd *EnvReadCommand) Run() error {
-	env, err := cmd.environment.env()
+	env, origins, err := cmd.environment.envWithSources()
 	if err != nil {
 		return err
 	}
@@ -58,5 +61,13 @@ func (cmd *EnvReadCommand) Run() error {
 
 	fmt.Fprintln(cmd.io.Output(), res)
 
+	if cmd.verbose {
+		sources := origins[cmd.key.Value]
+		fmt.Fprintf(cmd.io.Output(), "Source: %s\n", sources[len(sources)-1])
+		if len(sources) > 1 {
+			fmt.Fprintf(cmd.io.Output(), "Overrides: %s\n", strings.Join(sources[:len(sources)-1], ", "))
+		}
+	}
+
 	return nil
 }
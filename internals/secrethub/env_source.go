@@ -8,7 +8,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"unicode"
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
@@ -44,7 +46,12 @@ type environment struct {
 	templateVersion              string
 	dontPromptMissingTemplateVar bool
 	secretsDir                   string
+	secretsDirNaming             secretsDirNaming
 	secretsEnvDir                string
+	envDirOptions                envDirOptions
+	sourcePriority               []string
+	strictCollisions             bool
+	concurrency                  int
 }
 
 func newEnvironment(io ui.IO, newClient newClientFunc) *environment {
@@ -56,61 +63,193 @@ func newEnvironment(io ui.IO, newClient newClientFunc) *environment {
 		osStat:       os.Stat,
 		templateVars: make(map[string]string),
 		envar:        make(map[string]string),
+		concurrency:  defaultEnvConcurrency,
+		secretsDirNaming: secretsDirNaming{
+			separator: "_",
+			nameCase:  secretsDirCaseUpper,
+		},
+		envDirOptions: envDirOptions{
+			maxFileSize: defaultEnvDirMaxFileSize,
+			logger:      cli.NewLogger(),
+		},
 	}
 }
 
 func (env *environment) register(clause *cli.CommandClause) {
 	clause.Flags().StringToStringVarP(&env.envar, "envar", "e", nil, "Source an environment variable from a secret at a given path with `NAME=<path>`")
-	clause.Flags().StringVar(&env.envFile, "env-file", "", "The path to a file with environment variable mappings of the form `NAME=value`. Template syntax can be used to inject secrets.")
+	clause.Flags().StringVar(&env.envFile, "env-file", "", "The path to a file with environment variable mappings of the form `NAME=value`. Template syntax can be used to inject secrets. Use - to read the file from stdin.")
 	clause.Flags().StringVar(&env.envFile, "template", "", "")
 	clause.Cmd.Flag("template").Hidden = true
 	clause.Flags().StringToStringVarP(&env.templateVars, "var", "v", nil, "Define the value for a template variable with `VAR=VALUE`, e.g. --var env=prod")
-	clause.Flags().StringVar(&env.templateVersion, "template-version", "auto", "The template syntax version to be used. The options are v1, v2, latest or auto to automatically detect the version.")
+	clause.Flags().StringVar(&env.templateVersion, "template-version", "auto", "The template syntax version to be used. The options are v1, v2, v3, latest or auto to automatically detect the version.")
 	_ = clause.Cmd.RegisterFlagCompletionFunc("template-version", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"v1", "v2", "latest", "auto"}, cobra.ShellCompDirectiveDefault
+		return []string{"v1", "v2", "v3", "latest", "auto"}, cobra.ShellCompDirectiveDefault
 	})
 	clause.Flags().BoolVar(&env.dontPromptMissingTemplateVar, "no-prompt", false, "Do not prompt when a template variable is missing and return an error instead.")
 	clause.Flags().StringVar(&env.secretsDir, "secrets-dir", "", "Recursively include all secrets from a directory. Environment variable names are derived from the path of the secret: `/` are replaced with `_` and the name is uppercased.")
+	clause.Flags().StringVar(&env.secretsDirNaming.prefix, "secrets-dir-prefix", "", "A prefix for the names of the environment variables sourced with --secrets-dir, e.g. APP__.")
+	clause.Flags().StringVar(&env.secretsDirNaming.separator, "secrets-dir-separator", "_", "The separator that replaces the `/` between directories in the names of the environment variables sourced with --secrets-dir.")
+	clause.Flags().StringVar(&env.secretsDirNaming.nameCase, "secrets-dir-case", secretsDirCaseUpper, "The case of the names of the environment variables sourced with --secrets-dir: upper, lower or preserve.")
+	_ = clause.Cmd.RegisterFlagCompletionFunc("secrets-dir-case", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{secretsDirCaseUpper, secretsDirCaseLower, secretsDirCasePreserve}, cobra.ShellCompDirectiveDefault
+	})
 	clause.Flags().StringVar(&env.secretsEnvDir, "env", "default", "The name of the environment prepared by the set command.")
 	clause.Cmd.Flag("env").Hidden = true
+	clause.Flags().BoolVar(&env.envDirOptions.followSymlinks, "env-dir-follow-symlinks", false, "Read symbolic links in the environment prepared by the set command as the file they point to, instead of skipping them.")
+	clause.Cmd.Flag("env-dir-follow-symlinks").Hidden = true
+	clause.Flags().Var(&env.envDirOptions.maxFileSize, "env-dir-max-file-size", "The maximum size of a file in the environment prepared by the set command, e.g. 512KB. Use 0 for no maximum.")
+	clause.Cmd.Flag("env-dir-max-file-size").Hidden = true
+	clause.Flags().StringSliceVar(&env.sourcePriority, "source-priority", defaultEnvSourcePriority, "The sources of environment variables to use, from the lowest to the highest priority. A variable from a source overrides the same variable from the sources before it and sources that are left out are disabled. The sources are: "+strings.Join(defaultEnvSourcePriority, ", ")+".")
+	clause.Flags().BoolVar(&env.strictCollisions, "strict-collisions", false, "Return an error when an environment variable is supplied by more than one source, instead of using the source with the highest priority. Overriding variables of the os environment is always allowed.")
+	clause.Flags().IntVar(&env.concurrency, "concurrency", defaultEnvConcurrency, "The maximum number of environment variables that are resolved concurrently.")
+}
+
+// Names of the sources of environment variables, as used in the --source-priority flag.
+const (
+	envSourceOS         = "os"
+	envSourceSecretsEnv = "secretsenv"
+	envSourceSecretsDir = "secrets-dir"
+	envSourceEnvFile    = "env-file"
+	envSourceReferences = "references"
+	envSourceFlags      = "envar"
+)
+
+// defaultEnvSourcePriority lists all sources of environment variables from the lowest
+// to the highest priority. A variable from a source overrides the same variable from
+// the sources before it.
+var defaultEnvSourcePriority = []string{
+	envSourceOS,
+	envSourceSecretsEnv,
+	envSourceSecretsDir,
+	envSourceEnvFile,
+	envSourceReferences,
+	envSourceFlags,
+}
+
+// namedEnvSource is a source of environment variables with the name it is configured by.
+type namedEnvSource struct {
+	name   string
+	source EnvSource
 }
 
 func (env *environment) env() (map[string]value, error) {
-	osEnvMap, _ := parseKeyValueStringsToMap(env.osEnv)
-	var sources []EnvSource
+	values, _, err := env.envWithSources()
+	return values, err
+}
 
-	sources = append(sources, &osEnv{
-		osEnv: osEnvMap,
-	})
+// envWithSources returns the environment and, for every variable in it, the names of
+// the sources that supply it, from the lowest to the highest priority. The value of the
+// variable is taken from the last of these sources. When strict collisions are enabled,
+// an error is returned if a variable is supplied by more than one source other than the
+// os environment.
+func (env *environment) envWithSources() (map[string]value, map[string][]string, error) {
+	sources, err := env.sources()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// .secretsenv dir (for backwards compatibility)
-	envDir := filepath.Join(secretspec.SecretEnvPath, env.secretsEnvDir)
-	_, err := os.Stat(envDir)
-	if err == nil {
-		dirSource, err := NewEnvDir(envDir)
+	result := map[string]value{}
+	origins := map[string][]string{}
+	for _, source := range sources {
+		vars, err := source.source.env()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		for name, value := range vars {
+			result[name] = value
+			origins[name] = append(origins[name], source.name)
+		}
+	}
+
+	if env.strictCollisions {
+		collisions := envCollisions(origins)
+		if len(collisions) > 0 {
+			return nil, nil, ErrEnvCollisions(strings.Join(collisions, ", "))
+		}
+	}
+	return result, origins, nil
+}
+
+// envCollisions returns a sorted description of every variable that is supplied by more
+// than one source, e.g. `DB_PASSWORD (env-file, envar)`. Variables are allowed to override
+// the os environment, so that source is not taken into account.
+func envCollisions(origins map[string][]string) []string {
+	var collisions []string
+	for name, sources := range origins {
+		var colliding []string
+		for _, source := range sources {
+			if source != envSourceOS {
+				colliding = append(colliding, source)
+			}
+		}
+		if len(colliding) > 1 {
+			collisions = append(collisions, fmt.Sprintf("%s (%s)", name, strings.Join(colliding, ", ")))
 		}
-		sources = append(sources, dirSource)
 	}
+	sort.Strings(collisions)
+	return collisions
+}
 
-	// --secrets-dir flag
-	if env.secretsDir != "" {
-		secretsDirEnv := newSecretsDirEnv(env.newClient, env.secretsDir)
-		sources = append(sources, secretsDirEnv)
+// sources returns the enabled sources of environment variables, from the lowest to the highest priority.
+func (env *environment) sources() ([]namedEnvSource, error) {
+	priority, err := parseEnvSourcePriority(env.sourcePriority)
+	if err != nil {
+		return nil, err
 	}
 
-	//secrethub.env file
-	if env.envFile == "" {
-		_, err := env.osStat(defaultEnvFile)
-		if err == nil {
-			env.envFile = defaultEnvFile
-		} else if !os.IsNotExist(err) {
-			return nil, ErrReadDefaultEnvFile(defaultEnvFile, err)
+	osEnvMap, _ := parseKeyValueStringsToMap(env.osEnv)
+	var sources []namedEnvSource
+	for _, name := range priority {
+		source, err := env.source(name, osEnvMap)
+		if err != nil {
+			return nil, err
+		}
+		if source != nil {
+			sources = append(sources, namedEnvSource{name: name, source: source})
 		}
 	}
+	return sources, nil
+}
+
+// source returns the source of environment variables with the given name,
+// or nil when that source is not configured.
+func (env *environment) source(name string, osEnvMap map[string]string) (EnvSource, error) {
+	switch name {
+	case envSourceOS:
+		return &osEnv{
+			osEnv: osEnvMap,
+		}, nil
+	case envSourceSecretsEnv:
+		// .secretsenv dir (for backwards compatibility)
+		envDir := filepath.Join(secretspec.SecretEnvPath, env.secretsEnvDir)
+		_, err := os.Stat(envDir)
+		if err != nil {
+			return nil, nil
+		}
+		return NewEnvDir(envDir, env.envDirOptions)
+	case envSourceSecretsDir:
+		if env.secretsDir == "" {
+			return nil, nil
+		}
+		err := env.secretsDirNaming.validate()
+		if err != nil {
+			return nil, err
+		}
+		return newSecretsDirEnv(env.newClient, env.secretsDir, env.secretsDirNaming), nil
+	case envSourceEnvFile:
+		//secrethub.env file
+		if env.envFile == "" {
+			_, err := env.osStat(defaultEnvFile)
+			if err == nil {
+				env.envFile = defaultEnvFile
+			} else if !os.IsNotExist(err) {
+				return nil, ErrReadDefaultEnvFile(defaultEnvFile, err)
+			}
+		}
+		if env.envFile == "" {
+			return nil, nil
+		}
 
-	if env.envFile != "" {
 		templateVariableReader, err := newVariableReader(osEnvMap, env.templateVars)
 		if err != nil {
 			return nil, err
@@ -120,9 +259,9 @@ func (env *environment) env() (map[string]value, error) {
 			templateVariableReader = newPromptMissingVariableReader(templateVariableReader, env.io)
 		}
 
-		raw, err := env.readFile(env.envFile)
+		raw, err := env.readEnvFile()
 		if err != nil {
-			return nil, ErrCannotReadFile(env.envFile, err)
+			return nil, err
 		}
 
 		parser, err := getTemplateParser(raw, env.templateVersion)
@@ -130,35 +269,140 @@ func (env *environment) env() (map[string]value, error) {
 			return nil, err
 		}
 
-		envFile, err := ReadEnvFile(env.envFile, bytes.NewReader(raw), templateVariableReader, parser)
+		name := env.envFile
+		if name == envFileStdin {
+			name = "stdin"
+		}
+		return ReadEnvFile(name, bytes.NewReader(raw), templateVariableReader, parser)
+	case envSourceReferences:
+		// secret references (secrethub://)
+		return newReferenceEnv(osEnvMap), nil
+	case envSourceFlags:
+		// TODO: Validate the flags when parsing by implementing the Flag interface for EnvFlags.
+		return NewEnvFlags(env.envar)
+	default:
+		return nil, ErrUnknownEnvSource(name, strings.Join(defaultEnvSourcePriority, ", "))
+	}
+}
+
+// readEnvFile returns the contents of the env-file, which is read from stdin when the
+// env-file is "-". Missing template variables are then still prompted for, because
+// prompts are read from the terminal when stdin is piped.
+func (env *environment) readEnvFile() ([]byte, error) {
+	if env.envFile != envFileStdin {
+		raw, err := env.readFile(env.envFile)
 		if err != nil {
-			return nil, err
+			return nil, ErrCannotReadFile(env.envFile, err)
 		}
-		sources = append(sources, envFile)
+		return raw, nil
 	}
 
-	// secret references (secrethub://)
-	referenceEnv := newReferenceEnv(osEnvMap)
-	sources = append(sources, referenceEnv)
+	if !env.io.IsInputPiped() {
+		return nil, ErrNoDataOnStdin
+	}
+	return io.ReadAll(env.io.Input())
+}
 
-	// --envar flag
-	// TODO: Validate the flags when parsing by implementing the Flag interface for EnvFlags.
-	flagEnv, err := NewEnvFlags(env.envar)
-	if err != nil {
-		return nil, err
+// parseEnvSourcePriority validates the configured order of the sources of environment
+// variables. When no order is configured, the default order is returned.
+func parseEnvSourcePriority(priority []string) ([]string, error) {
+	if len(priority) == 0 {
+		return defaultEnvSourcePriority, nil
 	}
-	sources = append(sources, flagEnv)
 
-	envs := make([]map[string]value, len(sources))
-	for _, source := range sources {
-		env, err := source.env()
-		if err != nil {
-			return nil, err
+	seen := make(map[string]bool, len(priority))
+	for _, name := range priority {
+		found := false
+		for _, known := range defaultEnvSourcePriority {
+			if name == known {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrUnknownEnvSource(name, strings.Join(defaultEnvSourcePriority, ", "))
 		}
-		envs = append(envs, env)
+		if seen[name] {
+			return nil, ErrDuplicateEnvSource(name)
+		}
+		seen[name] = true
+	}
+	return priority, nil
+}
+
+// resolve resolves the values of the given environment variables with the given secret reader.
+// Up to env.concurrency values are resolved concurrently, so the secret reader must be safe for
+// concurrent use. When a value cannot be resolved, no new values are resolved and the first error
+// that occurred is returned.
+func (env *environment) resolve(values map[string]value, sr tpl.SecretReader) (map[string]string, error) {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	workers := env.concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(names) {
+		workers = len(names)
+	}
+
+	result := make(map[string]string, len(values))
+	var mutex sync.Mutex
+	var firstErr error
+	var failOnce sync.Once
+	failed := make(chan struct{})
+
+	jobs := make(chan string)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				resolved, err := values[name].resolve(sr)
+				if err != nil {
+					failOnce.Do(func() {
+						firstErr = err
+						close(failed)
+					})
+					return
+				}
+
+				mutex.Lock()
+				result[name] = resolved
+				mutex.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, name := range names {
+		select {
+		case jobs <- name:
+		case <-failed:
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
 	}
+	return result, nil
+}
 
-	return mergeEnvs(envs...), nil
+// removeUnchanged removes the resolved variables that have the same value in the os environment.
+func (env *environment) removeUnchanged(resolved map[string]string) {
+	osEnv, _ := parseKeyValueStringsToMap(env.osEnv)
+	for name, value := range resolved {
+		if osValue, found := osEnv[name]; found && osValue == value {
+			delete(resolved, name)
+		}
+	}
 }
 
 func mergeEnvs(envs ...map[string]value) map[string]value {
@@ -198,10 +442,35 @@ func newSecretValue(path string) value {
 	return &secretValue{path: path}
 }
 
+// Cases of the names of environment variables sourced with the --secrets-dir flag.
+const (
+	secretsDirCaseUpper    = "upper"
+	secretsDirCaseLower    = "lower"
+	secretsDirCasePreserve = "preserve"
+)
+
+// secretsDirNaming configures how the paths of secrets are converted to the names
+// of environment variables by the --secrets-dir flag.
+type secretsDirNaming struct {
+	prefix    string
+	separator string
+	nameCase  string
+}
+
+func (n secretsDirNaming) validate() error {
+	switch n.nameCase {
+	case secretsDirCaseUpper, secretsDirCaseLower, secretsDirCasePreserve:
+		return nil
+	default:
+		return ErrInvalidSecretsDirCase(n.nameCase)
+	}
+}
+
 // secretsDirEnv sources environment variables from the directory specified with the --secrets-dir flag.
 type secretsDirEnv struct {
 	newClient newClientFunc
 	dirPath   string
+	naming    secretsDirNaming
 }
 
 // env returns a map of environment variables containing all secrets from the specified path.
@@ -246,22 +515,30 @@ func (s *secretsDirEnv) env() (map[string]value, error) {
 	return result, nil
 }
 
-// envVarName returns the environment variable name corresponding to the secret on the specified path
-// by converting the relative path to uppercase snake case.
+// envVarName returns the environment variable name corresponding to the secret on the specified path.
+// The relative path is converted to snake case, with the configured separator between directories,
+// and converted to the configured case. Finally, the configured prefix is prepended.
 func (s *secretsDirEnv) envVarName(path string) string {
 	envVarName := strings.TrimPrefix(path, s.dirPath)
 	envVarName = strings.TrimPrefix(envVarName, "/")
-	envVarName = strings.ReplaceAll(envVarName, "/", "_")
 	envVarName = strings.ReplaceAll(envVarName, "-", "_")
 	envVarName = strings.ReplaceAll(envVarName, ".", "_")
-	envVarName = strings.ToUpper(envVarName)
-	return envVarName
+	envVarName = strings.ReplaceAll(envVarName, "/", s.naming.separator)
+	switch s.naming.nameCase {
+	case secretsDirCaseLower:
+		envVarName = strings.ToLower(envVarName)
+	case secretsDirCasePreserve:
+	default:
+		envVarName = strings.ToUpper(envVarName)
+	}
+	return s.naming.prefix + envVarName
 }
 
-func newSecretsDirEnv(newClient newClientFunc, dirPath string) *secretsDirEnv {
+func newSecretsDirEnv(newClient newClientFunc, dirPath string, naming secretsDirNaming) *secretsDirEnv {
 	return &secretsDirEnv{
 		newClient: newClient,
 		dirPath:   dirPath,
+		naming:    naming,
 	}
 }
 
@@ -344,9 +621,20 @@ func newEnvDirSecretValue(value string) value {
 // EnvDir defines environment variables sourced from files in a directory.
 type EnvDir map[string]value
 
+// envDirOptions configure how files in an environment directory are read.
+type envDirOptions struct {
+	// followSymlinks makes symbolic links be read as the file they point to. Otherwise, they are skipped.
+	followSymlinks bool
+	// maxFileSize is the maximum size of a file. A value of 0 means no maximum.
+	maxFileSize byteSizeValue
+	logger      cli.Logger
+}
+
 // NewEnvDir sources environment variables from files in a given directory,
-// using the file name as key and contents as value.
-func NewEnvDir(path string) (EnvDir, error) {
+// using the file name as key and contents as value. Special files, such as
+// fifos and sockets, are skipped with a warning, because reading them can
+// block indefinitely.
+func NewEnvDir(path string, options envDirOptions) (EnvDir, error) {
 	files, err := os.ReadDir(path)
 	if err != nil {
 		return nil, ErrReadEnvDir(err)
@@ -354,15 +642,41 @@ func NewEnvDir(path string) (EnvDir, error) {
 
 	env := make(map[string]value)
 	for _, f := range files {
-		if !f.IsDir() {
-			filePath := filepath.Join(path, f.Name())
-			fileContent, err := os.ReadFile(filePath)
+		filePath := filepath.Join(path, f.Name())
+
+		info, err := f.Info()
+		if err != nil {
+			return nil, ErrReadEnvFile(f.Name(), err)
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			if !options.followSymlinks {
+				options.logger.Warningf("skipping %s because it is a symbolic link. Use --env-dir-follow-symlinks to read the file it points to.", filePath)
+				continue
+			}
+			info, err = os.Stat(filePath)
 			if err != nil {
 				return nil, ErrReadEnvFile(f.Name(), err)
 			}
+		}
+
+		if info.IsDir() {
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			options.logger.Warningf("skipping %s because it is not a regular file.", filePath)
+			continue
+		}
+		if options.maxFileSize > 0 && info.Size() > int64(options.maxFileSize) {
+			return nil, ErrEnvFileTooLarge(f.Name(), options.maxFileSize.String())
+		}
 
-			env[f.Name()] = newEnvDirSecretValue(string(fileContent))
+		fileContent, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, ErrReadEnvFile(f.Name(), err)
 		}
+
+		env[f.Name()] = newEnvDirSecretValue(string(fileContent))
 	}
 
 	return env, nil
@@ -1,9 +1,16 @@
 package secrethub
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"testing"
 
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl/fakes"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/api/uuid"
 	"github.com/secrethub/secrethub-go/internals/assert"
@@ -127,7 +134,7 @@ func TestSecretsDirEnv(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			source := newSecretsDirEnv(tc.newClient, dirPath)
+			source := newSecretsDirEnv(tc.newClient, dirPath, secretsDirNaming{separator: "_", nameCase: secretsDirCaseUpper})
 			secrets, err := source.env()
 			if tc.expectedCollission != nil {
 				collisionErr, ok := err.(errNameCollision)
@@ -152,3 +159,242 @@ func TestSecretsDirEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironment_Resolve(t *testing.T) {
+	secrets := map[string]string{}
+	values := map[string]value{}
+	expected := map[string]string{}
+	for i := 0; i < 50; i++ {
+		path := fmt.Sprintf("namespace/repo/secret%d", i)
+		name := fmt.Sprintf("SECRET_%d", i)
+		secrets[path] = fmt.Sprintf("value%d", i)
+		values[name] = newSecretValue(path)
+		expected[name] = secrets[path]
+	}
+	values["PLAIN"] = newPlaintextValue("plain")
+	expected["PLAIN"] = "plain"
+
+	cases := map[string]struct {
+		concurrency int
+		values      map[string]value
+		expected    map[string]string
+		err         error
+	}{
+		"sequential": {
+			concurrency: 1,
+			values:      values,
+			expected:    expected,
+		},
+		"concurrent": {
+			concurrency: 8,
+			values:      values,
+			expected:    expected,
+		},
+		"more workers than values": {
+			concurrency: 100,
+			values:      values,
+			expected:    expected,
+		},
+		"unset concurrency": {
+			values:   values,
+			expected: expected,
+		},
+		"no values": {
+			concurrency: 8,
+			values:      map[string]value{},
+			expected:    map[string]string{},
+		},
+		"error": {
+			concurrency: 8,
+			values: map[string]value{
+				"FOO":     newSecretValue("namespace/repo/secret1"),
+				"MISSING": newSecretValue("namespace/repo/missing"),
+			},
+			err: api.ErrSecretNotFound,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			env := &environment{concurrency: tc.concurrency}
+			sr := newCachingSecretReader(fakes.FakeSecretReader{Secrets: secrets})
+
+			actual, err := env.resolve(tc.values, sr)
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSecretsDirEnv_EnvVarName(t *testing.T) {
+	const path = "namespace/repo/db/primary-host.password"
+
+	cases := map[string]struct {
+		naming   secretsDirNaming
+		expected string
+	}{
+		"default": {
+			naming:   secretsDirNaming{separator: "_", nameCase: secretsDirCaseUpper},
+			expected: "DB_PRIMARY_HOST_PASSWORD",
+		},
+		"prefix and separator": {
+			naming:   secretsDirNaming{prefix: "APP__", separator: "__", nameCase: secretsDirCaseUpper},
+			expected: "APP__DB__PRIMARY_HOST_PASSWORD",
+		},
+		"lower": {
+			naming:   secretsDirNaming{separator: "_", nameCase: secretsDirCaseLower},
+			expected: "db_primary_host_password",
+		},
+		"preserve": {
+			naming:   secretsDirNaming{prefix: "App_", separator: "__", nameCase: secretsDirCasePreserve},
+			expected: "App_db__primary_host_password",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			source := newSecretsDirEnv(nil, "namespace/repo", tc.naming)
+			assert.Equal(t, source.envVarName(path), tc.expected)
+		})
+	}
+}
+
+func TestEnvironment_EnvWithSources(t *testing.T) {
+	cases := map[string]struct {
+		priority []string
+		envar    map[string]string
+		strict   bool
+		expected map[string][]string
+		err      error
+	}{
+		"default priority": {
+			expected: map[string][]string{
+				"FOO": {envSourceOS, envSourceReferences},
+				"BAR": {envSourceFlags},
+				"BAZ": {envSourceOS},
+			},
+		},
+		"reversed priority": {
+			priority: []string{envSourceFlags, envSourceReferences, envSourceOS},
+			expected: map[string][]string{
+				"FOO": {envSourceReferences, envSourceOS},
+				"BAR": {envSourceFlags},
+				"BAZ": {envSourceOS},
+			},
+		},
+		"disabled source": {
+			priority: []string{envSourceReferences, envSourceFlags},
+			expected: map[string][]string{
+				"FOO": {envSourceReferences},
+				"BAR": {envSourceFlags},
+			},
+		},
+		"unknown source": {
+			priority: []string{envSourceOS, "foo"},
+			err:      ErrUnknownEnvSource("foo", "os, secretsenv, secrets-dir, env-file, references, envar"),
+		},
+		"duplicate source": {
+			priority: []string{envSourceOS, envSourceFlags, envSourceOS},
+			err:      ErrDuplicateEnvSource(envSourceOS),
+		},
+		"collision": {
+			envar: map[string]string{"FOO": "namespace/repo/foo2"},
+			expected: map[string][]string{
+				"FOO": {envSourceOS, envSourceReferences, envSourceFlags},
+				"BAZ": {envSourceOS},
+			},
+		},
+		"strict collision": {
+			envar:  map[string]string{"FOO": "namespace/repo/foo2", "BAZ": "namespace/repo/baz"},
+			strict: true,
+			err:    ErrEnvCollisions("FOO (references, envar)"),
+		},
+		"strict override of os environment": {
+			strict: true,
+			expected: map[string][]string{
+				"FOO": {envSourceOS, envSourceReferences},
+				"BAR": {envSourceFlags},
+				"BAZ": {envSourceOS},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			envar := tc.envar
+			if envar == nil {
+				envar = map[string]string{"BAR": "namespace/repo/bar"}
+			}
+
+			env := &environment{
+				osEnv: []string{"FOO=secrethub://namespace/repo/foo", "BAZ=baz"},
+				osStat: func(filename string) (os.FileInfo, error) {
+					return nil, os.ErrNotExist
+				},
+				envar:            envar,
+				secretsEnvDir:    "default",
+				sourcePriority:   tc.priority,
+				strictCollisions: tc.strict,
+			}
+
+			_, actual, err := env.envWithSources()
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestNewEnvDir(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	assert.OK(t, os.WriteFile(filepath.Join(dir, "FOO"), []byte("foo"), 0600))
+	assert.OK(t, os.WriteFile(filepath.Join(dir, "large"), []byte("0123456789"), 0600))
+	err := os.Symlink(filepath.Join(dir, "FOO"), filepath.Join(dir, "LINK"))
+	if err != nil {
+		t.Skipf("cannot create symbolic link: %s", err)
+	}
+
+	cases := map[string]struct {
+		options  envDirOptions
+		expected []string
+		warnings string
+		err      error
+	}{
+		"skip symlinks": {
+			options:  envDirOptions{maxFileSize: 100},
+			expected: []string{"FOO", "large"},
+			warnings: "WARN: skipping " + filepath.Join(dir, "LINK") + " because it is a symbolic link. Use --env-dir-follow-symlinks to read the file it points to.\n",
+		},
+		"follow symlinks": {
+			options:  envDirOptions{followSymlinks: true},
+			expected: []string{"FOO", "LINK", "large"},
+		},
+		"file too large": {
+			options: envDirOptions{followSymlinks: true, maxFileSize: 5},
+			err:     ErrEnvFileTooLarge("large", "5B"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			warnings := &bytes.Buffer{}
+			tc.options.logger = cli.NewLoggerWithOutput(warnings)
+
+			env, err := NewEnvDir(dir, tc.options)
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, warnings.String(), tc.warnings)
+
+			var names []string
+			for name := range env {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			assert.Equal(t, names, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package secrethub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// EnvSourcesCommand is a command to print the source that supplies each environment variable.
+type EnvSourcesCommand struct {
+	io          ui.IO
+	environment *environment
+}
+
+// NewEnvSourcesCommand creates a new EnvSourcesCommand.
+func NewEnvSourcesCommand(io ui.IO, newClient newClientFunc) *EnvSourcesCommand {
+	return &EnvSourcesCommand{
+		io:          io,
+		environment: newEnvironment(io, newClient),
+	}
+}
+
+// Register adds a CommandClause and it's args and flags to a Registerer.
+func (cmd *EnvSourcesCommand) Register(r cli.Registerer) {
+	clause := r.Command("sources", "[BETA] Print which source supplies each environment variable and which sources it overrides, taking the --source-priority into account.")
+	clause.HelpLong("This command is hidden because it is still in beta. Future versions may break.")
+
+	cmd.environment.register(clause)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run executes the command.
+func (cmd *EnvSourcesCommand) Run() error {
+	_, origins, err := cmd.environment.envWithSources()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(origins))
+	for name := range origins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\n", "NAME", "SOURCE", "OVERRIDES")
+	for _, name := range names {
+		sources := origins[name]
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, sources[len(sources)-1], strings.Join(sources[:len(sources)-1], ", "))
+	}
+	return w.Flush()
+}
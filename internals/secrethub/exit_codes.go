@@ -0,0 +1,163 @@
+package secrethub
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-go/internals/errio"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the CLI. These are a contract with scripts that call the CLI,
+// so existing exit codes must never change meaning. Apart from the generic 1, they
+// are taken from sysexits.h, so that they are not confused with the small exit codes
+// that most programs use.
+const (
+	ExitCodeOK             = 0
+	ExitCodeError          = 1
+	ExitCodeValidation     = 64 // EX_USAGE
+	ExitCodeNotFound       = 66 // EX_NOINPUT
+	ExitCodeCrash          = 70 // EX_SOFTWARE
+	ExitCodeConflict       = 73 // EX_CANTCREAT
+	ExitCodeForbidden      = 77 // EX_NOPERM
+	ExitCodeNonInteractive = 78 // EX_CONFIG
+)
+
+// exitCodes documents the exit codes in the order they are listed in `secrethub help exit-codes`.
+var exitCodes = []struct {
+	code        int
+	description string
+}{
+	{ExitCodeOK, "The command succeeded."},
+	{ExitCodeError, "The command failed for a reason that does not have its own exit code."},
+	{ExitCodeValidation, "The arguments, flags or input of the command are invalid."},
+	{ExitCodeNotFound, "A secret, directory, repository or other resource does not exist."},
+	{ExitCodeCrash, "The CLI crashed unexpectedly. A crash report has been written."},
+	{ExitCodeConflict, "A resource already exists or has been changed in the meantime."},
+	{ExitCodeForbidden, "You are not authenticated or do not have access to a resource."},
+	{ExitCodeNonInteractive, "The command had to prompt for input while --non-interactive is set."},
+}
+
+// Errors
+var (
+	ErrInvalidFlag = errMain.Code("invalid_flag").ErrorPref("%s")
+)
+
+// ExitError is returned when a program run by the CLI fails. The CLI then exits with the
+// exit code of the program. The program reports its own errors, so the error is not printed.
+type ExitError struct {
+	Code int
+}
+
+// Error implements the error interface.
+func (e ExitError) Error() string {
+	return fmt.Sprintf("exited with code %d", e.Code)
+}
+
+// ExitCode returns the exit code of the CLI for the given error returned by App.Run.
+// Wrapped errors are unwrapped, so that an API error keeps its exit code when context is added to it.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+
+	var exitErr ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	var statusErr errio.PublicStatusError
+	if errors.As(err, &statusErr) {
+		return exitCodeForStatus(statusErr.StatusCode)
+	}
+	var publicErr errio.PublicError
+	if errors.As(err, &publicErr) {
+		return exitCodeForErrorCode(publicErr.Code)
+	}
+	return ExitCodeError
+}
+
+// exitCodeForStatus returns the exit code for an error response of the API with the given status code.
+func exitCodeForStatus(status int) int {
+	switch status {
+	case http.StatusNotFound:
+		return ExitCodeNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ExitCodeForbidden
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ExitCodeValidation
+	case http.StatusConflict:
+		return ExitCodeConflict
+	}
+	return ExitCodeError
+}
+
+// exitCodeForErrorCode returns the exit code for an error of the CLI with the given error code.
+// The naming conventions of the error codes are used, so that new errors get the right exit code
+// without having to list them here.
+func exitCodeForErrorCode(code string) int {
+	switch {
+	case code == nonInteractiveErrCode:
+		return ExitCodeNonInteractive
+	case code == "not_found" || strings.HasSuffix(code, "_not_found"):
+		return ExitCodeNotFound
+	case strings.Contains(code, "already_") || strings.HasSuffix(code, "_exists") || strings.HasSuffix(code, "_conflicts"):
+		return ExitCodeConflict
+	case strings.HasPrefix(code, "invalid_") || strings.HasPrefix(code, "unknown_") || strings.HasPrefix(code, "missing_") ||
+		strings.HasSuffix(code, "_conflict") || code == "parse_error":
+		return ExitCodeValidation
+	}
+	return ExitCodeError
+}
+
+// flagError makes errors that occur while parsing flags recognizable as validation errors.
+func flagError(_ *cobra.Command, err error) error {
+	return ErrInvalidFlag(err)
+}
+
+// ExitCodesCommand prints the exit codes of the CLI.
+type ExitCodesCommand struct {
+	io ui.IO
+}
+
+// NewExitCodesCommand creates a new ExitCodesCommand.
+func NewExitCodesCommand(io ui.IO) *ExitCodesCommand {
+	return &ExitCodesCommand{
+		io: io,
+	}
+}
+
+// Register registers the command on the provided Registerer.
+func (cmd *ExitCodesCommand) Register(r cli.Registerer) {
+	clause := r.Command("exit-codes", "Show the exit codes of the CLI.").Hidden()
+	clause.HelpLong("Every command exits with one of the following codes, so that scripts can handle failures without parsing error messages:\n\n" + formatExitCodes() + "\n" +
+		"Commands that run another program, such as run and history rerun, exit with the exit code of that program when it fails. " +
+		"A program can use any exit code, including the ones above. " +
+		"For these commands, the codes above are only reliable when the program is known not to use them itself.")
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run prints the exit codes.
+func (cmd *ExitCodesCommand) Run() error {
+	_, err := fmt.Fprint(cmd.io.Output(), formatExitCodes())
+	return err
+}
+
+// formatExitCodes returns a table of the exit codes and their descriptions.
+func formatExitCodes() string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 4, 4, ' ', 0)
+	fmt.Fprintf(w, "CODE\tDESCRIPTION\n")
+	for _, exitCode := range exitCodes {
+		fmt.Fprintf(w, "%d\t%s\n", exitCode.code, exitCode.description)
+	}
+	_ = w.Flush()
+	return sb.String()
+}
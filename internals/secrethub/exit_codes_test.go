@@ -0,0 +1,96 @@
+package secrethub
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	cases := map[string]struct {
+		err      error
+		expected int
+	}{
+		"no error": {
+			expected: ExitCodeOK,
+		},
+		"other error": {
+			err:      errors.New("test"),
+			expected: ExitCodeError,
+		},
+		"api not found": {
+			err:      api.ErrSecretNotFound,
+			expected: ExitCodeNotFound,
+		},
+		"api forbidden": {
+			err:      api.ErrForbidden,
+			expected: ExitCodeForbidden,
+		},
+		"wrapped api not found": {
+			err:      fmt.Errorf("cannot read: %w", api.ErrSecretNotFound),
+			expected: ExitCodeNotFound,
+		},
+		"wrapped validation error": {
+			err:      fmt.Errorf("cannot write: %w", ErrFlagsConflict("--foo and --bar")),
+			expected: ExitCodeValidation,
+		},
+		"program exited": {
+			err:      ExitError{Code: 3},
+			expected: 3,
+		},
+		"secret not found": {
+			err:      ErrSecretNotFound("namespace/repo/secret"),
+			expected: ExitCodeNotFound,
+		},
+		"secret already exists": {
+			err:      ErrSecretAlreadyExists,
+			expected: ExitCodeConflict,
+		},
+		"flags conflict": {
+			err:      ErrFlagsConflict("--foo and --bar"),
+			expected: ExitCodeValidation,
+		},
+		"invalid flag": {
+			err:      flagError(nil, errors.New("unknown flag: --foo")),
+			expected: ExitCodeValidation,
+		},
+		"invalid arguments": {
+			err:      cli.ErrInvalidArguments("secrethub read requires exactly 1 argument."),
+			expected: ExitCodeValidation,
+		},
+		"non-interactive": {
+			err:      ErrNonInteractive("provide all required input with flags or arguments"),
+			expected: ExitCodeNonInteractive,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, ExitCode(tc.err), tc.expected)
+		})
+	}
+}
+
+func TestExitCodesCommand_Run(t *testing.T) {
+	io := fakeui.NewIO(t)
+	cmd := NewExitCodesCommand(io)
+
+	err := cmd.Run()
+
+	assert.OK(t, err)
+	assert.Equal(t, io.Out.String(), "CODE    DESCRIPTION\n"+
+		"0       The command succeeded.\n"+
+		"1       The command failed for a reason that does not have its own exit code.\n"+
+		"64      The arguments, flags or input of the command are invalid.\n"+
+		"66      A secret, directory, repository or other resource does not exist.\n"+
+		"70      The CLI crashed unexpectedly. A crash report has been written.\n"+
+		"73      A resource already exists or has been changed in the meantime.\n"+
+		"77      You are not authenticated or do not have access to a resource.\n"+
+		"78      The command had to prompt for input while --non-interactive is set.\n")
+}
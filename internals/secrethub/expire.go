@@ -0,0 +1,134 @@
+package secrethub
+
+import (
+	"sort"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// secretExpiryLabel is the label that stores the moment at which a secret expires, as an RFC3339 timestamp.
+const secretExpiryLabel = "expires-at"
+
+// Errors
+var (
+	ErrInvalidSecretExpiry  = errMain.Code("invalid_secret_expiry").ErrorPref("invalid expiration date %q of %s: must be an RFC3339 timestamp")
+	ErrSecretExpiryNotFound = errMain.Code("secret_expiry_not_found").ErrorPref("no expiration date is set for %s")
+)
+
+// ExpireCommand handles the expiration dates of secrets.
+type ExpireCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewExpireCommand creates a new ExpireCommand.
+func NewExpireCommand(io ui.IO, newClient newClientFunc) *ExpireCommand {
+	return &ExpireCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *ExpireCommand) Register(r cli.Registerer) {
+	clause := r.Command("expire", "Keep track of when secrets expire, e.g. certificates and API tokens.")
+	clause.HelpLong("An expiration date is a reminder that a secret, e.g. a certificate or an API token, has to be replaced before it stops working. " +
+		"The secret is not removed when it expires. " +
+		"The expiration date is stored in the " + secretExpiryLabel + " label of the secret, " +
+		"so it is known to everyone who can read the directory of the secret.\n\n" +
+		"Run `secrethub expire ls <dir-path> --fail-if-within 14d` periodically, e.g. from a CI cron job, to be reminded of the secrets that expire soon.")
+
+	expiries := NewSecretExpiryStore(cmd.newClient)
+	NewExpireSetCommand(cmd.io, cmd.newClient, expiries).Register(clause)
+	NewExpireRemoveCommand(cmd.io, expiries).Register(clause)
+	NewExpireListCommand(cmd.io, expiries).Register(clause)
+}
+
+// secretExpiry is the moment at which the secret at Path expires.
+type secretExpiry struct {
+	Path      string
+	ExpiresAt time.Time
+}
+
+// SecretExpiryStore reads and writes the expiration dates in the labels of the secrets.
+// The API does not support expiration dates, so they are kept track of by the CLI.
+type SecretExpiryStore struct {
+	newClient newClientFunc
+}
+
+// NewSecretExpiryStore creates a new SecretExpiryStore.
+func NewSecretExpiryStore(newClient newClientFunc) *SecretExpiryStore {
+	return &SecretExpiryStore{
+		newClient: newClient,
+	}
+}
+
+// list returns the expiration dates of the secrets in the directory and its subdirectories,
+// sorted by the moment the secrets expire.
+func (s *SecretExpiryStore) list(dirPath api.DirPath) ([]secretExpiry, error) {
+	client, err := s.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := findLabeledSecrets(client, dirPath, labelFilter{secretExpiryLabel: ""})
+	if err != nil {
+		return nil, err
+	}
+
+	expiries := make([]secretExpiry, len(secrets))
+	for i, secret := range secrets {
+		value := secret.labels[secretExpiryLabel]
+		expiresAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, ErrInvalidSecretExpiry(value, secret.path)
+		}
+		expiries[i] = secretExpiry{
+			Path:      secret.path.Value(),
+			ExpiresAt: expiresAt,
+		}
+	}
+	sort.SliceStable(expiries, func(i, j int) bool {
+		return expiries[i].ExpiresAt.Before(expiries[j].ExpiresAt)
+	})
+	return expiries, nil
+}
+
+// set records in the labels of the secret at the given path that it expires at the given time.
+// An earlier expiration date of the secret is replaced.
+func (s *SecretExpiryStore) set(path api.SecretPath, expiresAt time.Time) error {
+	client, err := s.newClient()
+	if err != nil {
+		return err
+	}
+
+	labels, err := readSecretLabels(client, path)
+	if err != nil {
+		return err
+	}
+	labels[secretExpiryLabel] = expiresAt.Format(time.RFC3339)
+	return writeSecretLabels(client, path, labels)
+}
+
+// remove removes the expiration date from the labels of the secret at the given path.
+func (s *SecretExpiryStore) remove(path api.SecretPath) error {
+	client, err := s.newClient()
+	if err != nil {
+		return err
+	}
+
+	labels, err := readSecretLabels(client, path)
+	if err != nil {
+		return err
+	}
+	if _, ok := labels[secretExpiryLabel]; !ok {
+		return ErrSecretExpiryNotFound(path)
+	}
+
+	delete(labels, secretExpiryLabel)
+	return writeSecretLabels(client, path, labels)
+}
@@ -0,0 +1,99 @@
+package secrethub
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+
+	"github.com/docker/go-units"
+)
+
+// Errors
+var (
+	ErrSecretsExpiringSoon = errMain.Code("secrets_expiring_soon").ErrorPref("%d %s expired or %s within %s")
+)
+
+// ExpireListCommand lists the secrets with an expiration date.
+type ExpireListCommand struct {
+	path          api.DirPath
+	within        dayDurationValue
+	failIfWithin  dayDurationValue
+	useTimestamps bool
+	io            ui.IO
+	expiries      *SecretExpiryStore
+	now           func() time.Time
+}
+
+// NewExpireListCommand creates a new ExpireListCommand.
+func NewExpireListCommand(io ui.IO, expiries *SecretExpiryStore) *ExpireListCommand {
+	return &ExpireListCommand{
+		io:       io,
+		expiries: expiries,
+		now:      time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ExpireListCommand) Register(r cli.Registerer) {
+	clause := r.Command("ls", "List the secrets with an expiration date, the first to expire first.")
+	clause.Alias("list")
+	clause.Flags().Var(&cmd.within, "within", "Only list the secrets that have expired or expire within this duration, in days (30d), weeks (4w) or as a duration (36h).")
+	clause.Flags().Var(&cmd.failIfWithin, "fail-if-within", "Exit with an error when a listed secret has expired or expires within this duration, e.g. 14d. Use this to be reminded of expiring secrets in a CI cron job.")
+	registerTimestampFlag(clause, &cmd.useTimestamps)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.path, Name: "dir-path", Required: true, Placeholder: dirPathPlaceHolder, Description: "The path of the directory to list the expiring secrets of, including its subdirectories."},
+	})
+}
+
+// Run lists the secrets with an expiration date.
+func (cmd *ExpireListCommand) Run() error {
+	expiries, err := cmd.expiries.list(cmd.path)
+	if err != nil {
+		return err
+	}
+
+	now := cmd.now()
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tEXPIRES AT\tSTATUS")
+
+	expiring := 0
+	for _, expiry := range expiries {
+		remaining := expiry.ExpiresAt.Sub(now)
+		if cmd.within.Duration != 0 && remaining > cmd.within.Duration {
+			continue
+		}
+		if cmd.failIfWithin.Duration != 0 && remaining <= cmd.failIfWithin.Duration {
+			expiring++
+		}
+
+		expiresAt := expiry.ExpiresAt.Local().Format("2006-01-02 15:04")
+		if cmd.useTimestamps {
+			expiresAt = expiry.ExpiresAt.Local().Format(time.RFC3339)
+		}
+
+		status := "expires in " + units.HumanDuration(remaining)
+		if remaining <= 0 {
+			status = "expired " + units.HumanDuration(-remaining) + " ago"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", expiry.Path, expiresAt, status)
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	if expiring > 0 {
+		return ErrSecretsExpiringSoon(expiring, pluralize("secret", "secrets", expiring), pluralize("expires", "expire", expiring), cmd.failIfWithin.String())
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// ExpireRemoveCommand removes the expiration date of a secret.
+type ExpireRemoveCommand struct {
+	path     api.SecretPath
+	io       ui.IO
+	expiries *SecretExpiryStore
+}
+
+// NewExpireRemoveCommand creates a new ExpireRemoveCommand.
+func NewExpireRemoveCommand(io ui.IO, expiries *SecretExpiryStore) *ExpireRemoveCommand {
+	return &ExpireRemoveCommand{
+		io:       io,
+		expiries: expiries,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ExpireRemoveCommand) Register(r cli.Registerer) {
+	clause := r.Command("rm", "Remove the expiration date of a secret. The secret itself is not removed.")
+	clause.Alias("remove")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.path, Name: "path", Required: true, Placeholder: secretPathPlaceHolder, Description: "The path to the secret."},
+	})
+}
+
+// Run removes the expiration date of the secret.
+func (cmd *ExpireRemoveCommand) Run() error {
+	err := cmd.expiries.remove(cmd.path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Removed the expiration date of %s.\n", cmd.path.Value())
+	return nil
+}
@@ -0,0 +1,78 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrExpiryAtMissing = errMain.Code("expiry_at_missing").Error("--at is required: set it to the date (2006-01-02) or timestamp (RFC3339) at which the secret expires")
+)
+
+// ExpireSetCommand sets the expiration date of a secret.
+type ExpireSetCommand struct {
+	path      api.SecretPath
+	at        timeValue
+	io        ui.IO
+	newClient newClientFunc
+	expiries  *SecretExpiryStore
+}
+
+// NewExpireSetCommand creates a new ExpireSetCommand.
+func NewExpireSetCommand(io ui.IO, newClient newClientFunc, expiries *SecretExpiryStore) *ExpireSetCommand {
+	return &ExpireSetCommand{
+		io:        io,
+		newClient: newClient,
+		expiries:  expiries,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ExpireSetCommand) Register(r cli.Registerer) {
+	clause := r.Command("set", "Set the date at which a secret expires.")
+	clause.HelpLong("The expiration date is stored in the " + secretExpiryLabel + " label of the secret and replaces an earlier expiration date.")
+	clause.Flags().Var(&cmd.at, "at", "The date (2006-01-02) or timestamp (RFC3339) at which the secret expires. A date is the start of that day in the local time zone.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.path, Name: "path", Required: true, Placeholder: secretPathPlaceHolder, Description: "The path to the secret that expires."},
+	})
+}
+
+// Run stores the expiration date of the secret.
+func (cmd *ExpireSetCommand) Run() error {
+	if cmd.at.IsZero() {
+		return ErrExpiryAtMissing
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	if isSecretMetaName(cmd.path.Value()) {
+		return ErrMetaOfMetaSecret(cmd.path)
+	}
+
+	// The secret is checked, so that a typo in the path does not result in a reminder that never fires.
+	exists, err := client.Secrets().Exists(cmd.path.Value())
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return api.ErrSecretNotFound
+	}
+
+	err = cmd.expiries.set(cmd.path, cmd.at.Time)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "%s expires at %s.\n", cmd.path.Value(), cmd.at.String())
+	return nil
+}
@@ -0,0 +1,183 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestSecretExpiryStore(t *testing.T) {
+	secrets := newFakeSecretStore()
+	secrets.data["namespace/repo/a.meta"] = []byte(`{"owner":"team-a"}`)
+
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewSecretExpiryStore(secrets.newClient)
+	assert.OK(t, store.set("namespace/repo/a", now.Add(48*time.Hour)))
+	assert.OK(t, store.set("namespace/repo/b", now.Add(24*time.Hour)))
+	assert.OK(t, store.set("namespace/repo/a", now.Add(72*time.Hour)))
+
+	// Other labels are kept.
+	assert.Equal(t, string(secrets.data["namespace/repo/a.meta"]), `{"expires-at":"2018-01-04T12:00:00Z","owner":"team-a"}`)
+
+	expiries, err := store.list("namespace/repo")
+	assert.OK(t, err)
+	assert.Equal(t, expiries, []secretExpiry{
+		{Path: "namespace/repo/b", ExpiresAt: now.Add(24 * time.Hour)},
+		{Path: "namespace/repo/a", ExpiresAt: now.Add(72 * time.Hour)},
+	})
+
+	assert.OK(t, store.remove("namespace/repo/a"))
+	assert.Equal(t, store.remove("namespace/repo/a"), ErrSecretExpiryNotFound(api.SecretPath("namespace/repo/a")))
+	assert.Equal(t, string(secrets.data["namespace/repo/a.meta"]), `{"owner":"team-a"}`)
+
+	// An expiration date that is set with `secrethub meta set` has to be a timestamp.
+	secrets.data["namespace/repo/c.meta"] = []byte(`{"expires-at":"next week"}`)
+	_, err = store.list("namespace/repo")
+	assert.Equal(t, err, ErrInvalidSecretExpiry("next week", api.SecretPath("namespace/repo/c")))
+}
+
+func TestExpireSetCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		path          api.SecretPath
+		at            string
+		exists        bool
+		expectedOut   string
+		expectedLabel string
+		expectedErr   error
+	}{
+		"success": {
+			path:          "namespace/repo/cert",
+			at:            "2025-07-01T00:00:00Z",
+			exists:        true,
+			expectedOut:   "namespace/repo/cert expires at 2025-07-01T00:00:00Z.\n",
+			expectedLabel: `{"expires-at":"2025-07-01T00:00:00Z"}`,
+		},
+		"secret not found": {
+			path:        "namespace/repo/cert",
+			at:          "2025-07-01",
+			expectedErr: api.ErrSecretNotFound,
+		},
+		"no date": {
+			path:        "namespace/repo/cert",
+			exists:      true,
+			expectedErr: ErrExpiryAtMissing,
+		},
+		"meta secret": {
+			path:        "namespace/repo/cert.meta",
+			at:          "2025-07-01",
+			exists:      true,
+			expectedErr: ErrMetaOfMetaSecret(api.SecretPath("namespace/repo/cert.meta")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			secrets := newFakeSecretStore()
+			if tc.exists {
+				secrets.data[tc.path.Value()] = []byte("certificate")
+			}
+
+			io := fakeui.NewIO(t)
+			cmd := ExpireSetCommand{
+				path:      tc.path,
+				io:        io,
+				newClient: secrets.newClient,
+				expiries:  NewSecretExpiryStore(secrets.newClient),
+			}
+			if tc.at != "" {
+				assert.OK(t, cmd.at.Set(tc.at))
+			}
+
+			// Act
+			err := cmd.Run()
+
+			// Assert
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+			assert.Equal(t, string(secrets.data["namespace/repo/cert.meta"]), tc.expectedLabel)
+		})
+	}
+}
+
+func TestExpireListCommand_Run(t *testing.T) {
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	format := func(t time.Time) string {
+		return t.Local().Format("2006-01-02 15:04")
+	}
+
+	cases := map[string]struct {
+		within       string
+		failIfWithin string
+		expectedOut  string
+		expectedErr  error
+	}{
+		"all": {
+			expectedOut: "PATH                 EXPIRES AT        STATUS\n" +
+				"namespace/repo/old   " + format(now.Add(-48*time.Hour)) + "  expired 2 days ago\n" +
+				"namespace/repo/cert  " + format(now.Add(10*24*time.Hour)) + "  expires in 10 days\n" +
+				"namespace/repo/key   " + format(now.Add(60*24*time.Hour)) + "  expires in 2 months\n",
+		},
+		"within": {
+			within: "7d",
+			expectedOut: "PATH                EXPIRES AT        STATUS\n" +
+				"namespace/repo/old  " + format(now.Add(-48*time.Hour)) + "  expired 2 days ago\n",
+		},
+		"fail if within": {
+			within:       "30d",
+			failIfWithin: "14d",
+			expectedOut: "PATH                 EXPIRES AT        STATUS\n" +
+				"namespace/repo/old   " + format(now.Add(-48*time.Hour)) + "  expired 2 days ago\n" +
+				"namespace/repo/cert  " + format(now.Add(10*24*time.Hour)) + "  expires in 10 days\n",
+			expectedErr: ErrSecretsExpiringSoon(2, "secrets", "expire", "14d"),
+		},
+		"expired": {
+			within:       "90d",
+			failIfWithin: "1h",
+			expectedOut: "PATH                 EXPIRES AT        STATUS\n" +
+				"namespace/repo/old   " + format(now.Add(-48*time.Hour)) + "  expired 2 days ago\n" +
+				"namespace/repo/cert  " + format(now.Add(10*24*time.Hour)) + "  expires in 10 days\n" +
+				"namespace/repo/key   " + format(now.Add(60*24*time.Hour)) + "  expires in 2 months\n",
+			expectedErr: ErrSecretsExpiringSoon(1, "secret", "expires", "1h"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			secrets := newFakeSecretStore()
+			store := NewSecretExpiryStore(secrets.newClient)
+			assert.OK(t, store.set("namespace/repo/cert", now.Add(10*24*time.Hour)))
+			assert.OK(t, store.set("namespace/repo/old", now.Add(-48*time.Hour)))
+			assert.OK(t, store.set("namespace/repo/key", now.Add(60*24*time.Hour)))
+			secrets.data["namespace/repo/other.meta"] = []byte(`{"owner":"team-a"}`)
+
+			io := fakeui.NewIO(t)
+			cmd := ExpireListCommand{
+				path:     "namespace/repo",
+				io:       io,
+				expiries: store,
+				now: func() time.Time {
+					return now
+				},
+			}
+			if tc.within != "" {
+				assert.OK(t, cmd.within.Set(tc.within))
+			}
+			if tc.failIfWithin != "" {
+				assert.OK(t, cmd.failIfWithin.Set(tc.failIfWithin))
+			}
+
+			// Act
+			err := cmd.Run()
+
+			// Assert
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+		})
+	}
+}
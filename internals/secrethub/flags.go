@@ -1,9 +1,19 @@
 package secrethub
 
 import (
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/secrethub/secrethub-cli/internals/cli"
 )
 
+// Errors
+var (
+	ErrInvalidTime        = errMain.Code("invalid_time").ErrorPref("invalid time %q: use a date (2006-01-02) or a timestamp (2006-01-02T15:04:05Z07:00)")
+	ErrInvalidDayDuration = errMain.Code("invalid_day_duration").ErrorPref("invalid duration %q: use a positive number of days (90d), weeks (12w) or a duration (36h)")
+)
+
 func registerTimestampFlag(r *cli.CommandClause, p *bool) {
 	r.Flags().BoolVarP(p, "timestamp", "T", false, "Show timestamps formatted to RFC3339 instead of human readable durations.")
 }
@@ -11,3 +21,102 @@ func registerTimestampFlag(r *cli.CommandClause, p *bool) {
 func registerForceFlag(r *cli.CommandClause, p *bool) {
 	r.Flags().BoolVarP(p, "force", "f", false, "Ignore confirmation and fail instead of prompt for missing arguments.")
 }
+
+func registerFullPathsFlag(r *cli.CommandClause, p *bool) {
+	r.Flags().BoolVar(p, "full-paths", false, "Show full paths instead of shortening long paths to fit the width of the terminal.")
+}
+
+func registerFilterLabelFlag(r *cli.CommandClause, p *[]string) {
+	r.Flags().StringArrayVar(p, "filter-label", nil, "Only show secrets with this label, given as key=value or as key to only require the label to be set. Can be repeated to require multiple labels.")
+}
+
+// timeValue is a flag value for a point in time, given as a date or an RFC3339 timestamp.
+// A date is interpreted in the local time zone.
+type timeValue struct {
+	time.Time
+	isDate bool
+}
+
+func (v *timeValue) Set(value string) error {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		*v = timeValue{Time: t}
+		return nil
+	}
+
+	t, err = time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return ErrInvalidTime(value)
+	}
+	*v = timeValue{Time: t, isDate: true}
+	return nil
+}
+
+func (v *timeValue) String() string {
+	if v.IsZero() {
+		return ""
+	}
+	if v.isDate {
+		return v.Format("2006-01-02")
+	}
+	return v.Format(time.RFC3339)
+}
+
+func (v *timeValue) Type() string {
+	return "time"
+}
+
+// end returns the first moment after the given time. For a date, this is the start
+// of the next day, so that the entire day is included.
+func (v timeValue) end() time.Time {
+	if v.isDate {
+		return v.AddDate(0, 0, 1)
+	}
+	return v.Add(time.Nanosecond)
+}
+
+// parseDayDuration parses a positive number of days (90d), weeks (12w) or a duration (36h).
+// Days and weeks are not supported by time.ParseDuration, but are the natural unit for schedules.
+func parseDayDuration(value string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	switch {
+	case strings.HasSuffix(value, "d"), strings.HasSuffix(value, "w"):
+		unit := 24 * time.Hour
+		if strings.HasSuffix(value, "w") {
+			unit = 7 * unit
+		}
+		var n int
+		n, err = strconv.Atoi(value[:len(value)-1])
+		d = time.Duration(n) * unit
+	default:
+		d, err = time.ParseDuration(value)
+	}
+	if err != nil || d <= 0 {
+		return 0, ErrInvalidDayDuration(value)
+	}
+	return d, nil
+}
+
+// dayDurationValue is a flag value for a duration that can also be given in days or weeks.
+type dayDurationValue struct {
+	time.Duration
+	raw string
+}
+
+func (v *dayDurationValue) Set(value string) error {
+	d, err := parseDayDuration(value)
+	if err != nil {
+		return err
+	}
+	*v = dayDurationValue{Duration: d, raw: value}
+	return nil
+}
+
+func (v *dayDurationValue) String() string {
+	return v.raw
+}
+
+func (v *dayDurationValue) Type() string {
+	return "duration"
+}
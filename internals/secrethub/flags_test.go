@@ -0,0 +1,54 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestParseDayDuration(t *testing.T) {
+	cases := map[string]struct {
+		in          string
+		expected    time.Duration
+		expectedErr error
+	}{
+		"days": {
+			in:       "90d",
+			expected: 90 * 24 * time.Hour,
+		},
+		"weeks": {
+			in:       "2w",
+			expected: 14 * 24 * time.Hour,
+		},
+		"duration": {
+			in:       "36h",
+			expected: 36 * time.Hour,
+		},
+		"zero": {
+			in:          "0d",
+			expectedErr: ErrInvalidDayDuration("0d"),
+		},
+		"negative": {
+			in:          "-1h",
+			expectedErr: ErrInvalidDayDuration("-1h"),
+		},
+		"no number": {
+			in:          "d",
+			expectedErr: ErrInvalidDayDuration("d"),
+		},
+		"empty": {
+			in:          "",
+			expectedErr: ErrInvalidDayDuration(""),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseDayDuration(tc.in)
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
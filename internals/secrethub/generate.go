@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/clip"
@@ -46,6 +47,17 @@ type GenerateSecretCommand struct {
 	copyToClipboard bool
 	newClient       newClientFunc
 	clipWriter      ClipboardWriter
+
+	rotateTemplatesDir string
+	restartCommand     string
+	runRotation        bool
+
+	profileName    string
+	profileFile    string
+	profile        *generateProfile
+	certCommonName string
+	certValidity   time.Duration
+	isFlagChanged  func(name string) bool
 }
 
 // NewGenerateSecretCommand creates a new GenerateSecretCommand.
@@ -74,6 +86,26 @@ func (cmd *GenerateSecretCommand) Register(r cli.Registerer) {
 	})
 	clause.Flags().BoolVarP(&cmd.symbolsFlag, "symbols", "s", false, "Include symbols in secret.")
 	clause.Cmd.Flag("symbols").Hidden = true
+	clause.Flags().StringVar(&cmd.rotateTemplatesDir, "rotate-referencing-templates", "", "After writing the secret, scan the given directory for templates that reference it and print the commands to re-render them. Templates ending in .tpl, .tmpl or .template are rendered to the file without that extension.")
+	clause.Flags().StringVar(&cmd.restartCommand, "restart-command", "", "A shell command that restarts the services using the templates, e.g. --restart-command 'systemctl restart app'. It is only used with --rotate-referencing-templates and only when a template references the secret.")
+	clause.Flags().BoolVar(&cmd.runRotation, "run-rotation", false, "Run the commands to re-render the templates and restart the services instead of printing them. It is ignored without the --rotate-referencing-templates flag.")
+	clause.Flags().StringVar(&cmd.profileName, "profile", "", "Generate the secret with a named profile instead of a random string of characters. "+
+		"The built-in profiles are alnum32 (32 alphanumeric characters), hex32 (64 hexadecimal characters), base64-32 (32 random bytes encoded with base64), uuid (a random UUID), "+
+		"passphrase-diceware (8 words separated by dashes, from a list of "+strconv.Itoa(len(passphraseWords))+" words), rsa-2048, rsa-4096, ec-p256 and ec-p384. "+
+		"The key profiles write the PEM encoded private key to the secret path and the public key to the sibling path with the .pub suffix.")
+	_ = clause.Cmd.RegisterFlagCompletionFunc("profile", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		names := make([]string, 0, len(builtinProfiles))
+		for name := range builtinProfiles {
+			names = append(names, name)
+		}
+		return names, cobra.ShellCompDirectiveDefault
+	})
+	clause.Flags().StringVar(&cmd.profileFile, "profile-file", "", "A YAML file with additional profiles, of the form `profiles: {<name>: {type: <type>, ...}}`. "+
+		"The type is one of chars (with length, charset and min), hex or base64 (with bytes), uuid, passphrase (with words and separator), rsa (with bits) or ec (with curve p256, p384 or p521). "+
+		"A profile in the file replaces the built-in profile with the same name.")
+	clause.Flags().StringVar(&cmd.certCommonName, "cert-common-name", "", "With an rsa or ec profile, also generate a self-signed certificate for this common name and write it to the sibling path with the .crt suffix.")
+	clause.Flags().DurationVar(&cmd.certValidity, "cert-validity", 365*24*time.Hour, "How long the certificate generated with --cert-common-name is valid.")
+	cmd.isFlagChanged = clause.Cmd.Flags().Changed
 
 	clause.BindAction(cmd.Run)
 	clause.BindArguments([]cli.Argument{
@@ -85,6 +117,13 @@ func (cmd *GenerateSecretCommand) Register(r cli.Registerer) {
 
 // before configures the command using the flag values.
 func (cmd *GenerateSecretCommand) before() error {
+	if cmd.profileName != "" {
+		return cmd.beforeProfile()
+	}
+	if cmd.certCommonName != "" {
+		return ErrCertWithoutKeyProfile
+	}
+
 	useSymbols, err := cmd.useSymbols()
 	if err != nil {
 		return err
@@ -103,6 +142,35 @@ func (cmd *GenerateSecretCommand) before() error {
 	return nil
 }
 
+// beforeProfile loads the profile to generate the secret with.
+func (cmd *GenerateSecretCommand) beforeProfile() error {
+	if cmd.lengthArg.IsSet() {
+		return ErrFlagsConflict("--profile and the length argument")
+	}
+	if cmd.isFlagChanged != nil {
+		for _, flag := range []string{"length", "charset", "min", "symbols"} {
+			if cmd.isFlagChanged(flag) {
+				return ErrFlagsConflict("--profile and --" + flag)
+			}
+		}
+	}
+
+	profile, err := loadProfile(cmd.profileName, cmd.profileFile)
+	if err != nil {
+		return err
+	}
+
+	if profile.isKeyPair() && cmd.copyToClipboard {
+		return ErrFlagsConflict("--clip and a key profile")
+	}
+	if cmd.certCommonName != "" && !profile.isKeyPair() {
+		return ErrCertWithoutKeyProfile
+	}
+
+	cmd.profile = &profile
+	return nil
+}
+
 // Run generates a new secret and writes to the output path.
 func (cmd *GenerateSecretCommand) Run() error {
 	err := cmd.before()
@@ -113,40 +181,41 @@ func (cmd *GenerateSecretCommand) Run() error {
 }
 
 // run generates a new secret and writes to the output path.
+// The parts of a secret that consists of multiple parts are written to sibling paths.
 func (cmd *GenerateSecretCommand) run() error {
-	length, err := cmd.length()
-	if err != nil {
-		return err
-	}
-
 	path, err := cmd.path()
 	if err != nil {
 		return err
 	}
 
-	if length <= 0 {
-		return ErrInvalidRandLength
-	}
-
-	data, err := cmd.generator.Generate(length)
+	parts, err := cmd.generate()
 	if err != nil {
 		return err
 	}
 
-	client, err := cmd.newClient()
-	if err != nil {
-		return err
+	// All paths are validated before anything is written, so that no parts are written when one of the paths is invalid.
+	for _, part := range parts[1:] {
+		err = api.ValidateSecretPath(path + part.suffix)
+		if err != nil {
+			return err
+		}
 	}
 
-	version, err := client.Secrets().Write(path, data)
+	client, err := cmd.newClient()
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "A randomly generated secret has been written to %s:%d.\n", path, version.Version)
+	for _, part := range parts {
+		version, err := client.Secrets().Write(path+part.suffix, part.data)
+		if err != nil {
+			return err
+		}
 
+		fmt.Fprintf(cmd.io.Output(), "A randomly generated secret has been written to %s:%d.\n", path+part.suffix, version.Version)
+	}
 	if cmd.copyToClipboard {
-		err = cmd.clipWriter.Write(data)
+		err = cmd.clipWriter.Write(parts[0].data)
 		if err != nil {
 			return err
 		}
@@ -158,9 +227,41 @@ func (cmd *GenerateSecretCommand) run() error {
 		)
 	}
 
+	if cmd.rotateTemplatesDir != "" {
+		return cmd.rotateReferencingTemplates(path)
+	}
+
 	return nil
 }
 
+// generate returns the parts of the secret, which is a single random string of characters when no profile is used.
+func (cmd *GenerateSecretCommand) generate() ([]generatedPart, error) {
+	if cmd.profile != nil {
+		var cert *certificateOptions
+		if cmd.certCommonName != "" {
+			cert = &certificateOptions{
+				commonName: cmd.certCommonName,
+				validity:   cmd.certValidity,
+			}
+		}
+		return cmd.profile.generate(cert)
+	}
+
+	length, err := cmd.length()
+	if err != nil {
+		return nil, err
+	}
+	if length <= 0 {
+		return nil, ErrInvalidRandLength
+	}
+
+	data, err := cmd.generator.Generate(length)
+	if err != nil {
+		return nil, err
+	}
+	return []generatedPart{{data: data}}, nil
+}
+
 func (cmd *GenerateSecretCommand) length() (int, error) {
 	if cmd.lengthArg.IsSet() && cmd.lengthFlag.IsSet() {
 		return 0, ErrCannotUseLengthArgAndFlag
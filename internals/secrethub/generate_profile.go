@@ -0,0 +1,369 @@
+package secrethub
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-go/pkg/randchar"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Errors
+var (
+	ErrUnknownProfile        = errGenerate.Code("unknown_profile").ErrorPref("unknown profile %s: available profiles are %s")
+	ErrInvalidProfile        = errGenerate.Code("invalid_profile").ErrorPref("invalid profile %s: %s")
+	ErrInvalidProfileFile    = errGenerate.Code("invalid_profile_file").ErrorPref("could not parse profile file %s: %s")
+	ErrCertWithoutKeyProfile = errGenerate.Code("cert_without_key_profile").Error("a certificate can only be generated with an rsa or ec profile")
+)
+
+// Profile types
+const (
+	profileTypeChars      = "chars"
+	profileTypeHex        = "hex"
+	profileTypeBase64     = "base64"
+	profileTypeUUID       = "uuid"
+	profileTypePassphrase = "passphrase"
+	profileTypeRSA        = "rsa"
+	profileTypeEC         = "ec"
+)
+
+// Secret path suffixes of the parts of a key pair.
+const (
+	publicKeySuffix   = ".pub"
+	certificateSuffix = ".crt"
+)
+
+// generateProfile describes how a secret is generated.
+// The profiles in a profile file are decoded into this struct, so fields that are
+// not set take the default value of the profile type.
+type generateProfile struct {
+	Type      string   `yaml:"type" json:"type"`
+	Length    int      `yaml:"length" json:"length,omitempty"`
+	Charset   string   `yaml:"charset" json:"charset,omitempty"`
+	Min       []string `yaml:"min" json:"min,omitempty"`
+	Bytes     int      `yaml:"bytes" json:"bytes,omitempty"`
+	Words     int      `yaml:"words" json:"words,omitempty"`
+	Separator string   `yaml:"separator" json:"separator,omitempty"`
+	Bits      int      `yaml:"bits" json:"bits,omitempty"`
+	Curve     string   `yaml:"curve" json:"curve,omitempty"`
+}
+
+// generateProfileFile is the format of a file with user-defined profiles.
+type generateProfileFile struct {
+	Profiles map[string]generateProfile `yaml:"profiles"`
+}
+
+// builtinProfiles are the profiles that are available without a profile file.
+var builtinProfiles = map[string]generateProfile{
+	"alnum32":             {Type: profileTypeChars, Length: 32, Charset: "alphanumeric"},
+	"hex32":               {Type: profileTypeHex, Bytes: 32},
+	"base64-32":           {Type: profileTypeBase64, Bytes: 32},
+	"uuid":                {Type: profileTypeUUID},
+	"passphrase-diceware": {Type: profileTypePassphrase, Words: 8, Separator: "-"},
+	"rsa-2048":            {Type: profileTypeRSA, Bits: 2048},
+	"rsa-4096":            {Type: profileTypeRSA, Bits: 4096},
+	"ec-p256":             {Type: profileTypeEC, Curve: "p256"},
+	"ec-p384":             {Type: profileTypeEC, Curve: "p384"},
+}
+
+// profileCurves are the elliptic curves that can be used in ec profiles.
+var profileCurves = map[string]elliptic.Curve{
+	"p256": elliptic.P256(),
+	"p384": elliptic.P384(),
+	"p521": elliptic.P521(),
+}
+
+// generatedPart is a part of a generated secret. The main part has an empty suffix
+// and the other parts are written to sibling paths with the suffix appended.
+type generatedPart struct {
+	suffix string
+	data   []byte
+}
+
+// certificateOptions configure the self-signed certificate that is generated with a key pair.
+type certificateOptions struct {
+	commonName string
+	validity   time.Duration
+}
+
+// loadProfile returns the profile with the given name. Profiles in the profile file
+// take precedence over the built-in profiles with the same name.
+func loadProfile(name string, profileFile string) (generateProfile, error) {
+	profiles := make(map[string]generateProfile, len(builtinProfiles))
+	for profileName, profile := range builtinProfiles {
+		profiles[profileName] = profile
+	}
+
+	if profileFile != "" {
+		raw, err := os.ReadFile(profileFile)
+		if err != nil {
+			return generateProfile{}, ErrReadFile(profileFile, err)
+		}
+
+		var file generateProfileFile
+		err = yaml.UnmarshalStrict(raw, &file)
+		if err != nil {
+			return generateProfile{}, ErrInvalidProfileFile(profileFile, err)
+		}
+		for profileName, profile := range file.Profiles {
+			profiles[profileName] = profile
+		}
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for profileName := range profiles {
+			names = append(names, profileName)
+		}
+		sort.Strings(names)
+		return generateProfile{}, ErrUnknownProfile(name, strings.Join(names, ", "))
+	}
+
+	err := profile.validate(name)
+	if err != nil {
+		return generateProfile{}, err
+	}
+	return profile, nil
+}
+
+// validate checks whether the profile can be used to generate a secret.
+func (p generateProfile) validate(name string) error {
+	switch p.Type {
+	case profileTypeChars:
+		if p.Length < 0 {
+			return ErrInvalidProfile(name, "length must be larger than 0")
+		}
+		_, err := p.charsGenerator()
+		if err != nil {
+			return ErrInvalidProfile(name, err)
+		}
+	case profileTypeHex, profileTypeBase64:
+		if p.Bytes < 0 {
+			return ErrInvalidProfile(name, "bytes must be larger than 0")
+		}
+	case profileTypeUUID:
+	case profileTypePassphrase:
+		if p.Words < 0 {
+			return ErrInvalidProfile(name, "words must be larger than 0")
+		}
+	case profileTypeRSA:
+		if p.Bits != 0 && p.Bits < 2048 {
+			return ErrInvalidProfile(name, "bits must be at least 2048")
+		}
+	case profileTypeEC:
+		if _, ok := profileCurves[p.curve()]; !ok {
+			return ErrInvalidProfile(name, "curve must be one of p256, p384 and p521")
+		}
+	default:
+		return ErrInvalidProfile(name, fmt.Sprintf("unknown type %q: must be one of chars, hex, base64, uuid, passphrase, rsa and ec", p.Type))
+	}
+	return nil
+}
+
+// isKeyPair returns whether the profile generates a key pair, which consists of multiple parts.
+func (p generateProfile) isKeyPair() bool {
+	return p.Type == profileTypeRSA || p.Type == profileTypeEC
+}
+
+// generate generates the parts of a secret. A certificate is only generated for
+// key pairs and only when cert is not nil.
+func (p generateProfile) generate(cert *certificateOptions) ([]generatedPart, error) {
+	if cert != nil && !p.isKeyPair() {
+		return nil, ErrCertWithoutKeyProfile
+	}
+
+	var data []byte
+	var err error
+	switch p.Type {
+	case profileTypeChars:
+		data, err = p.generateChars()
+	case profileTypeHex:
+		data, err = p.generateBytes(hex.EncodeToString)
+	case profileTypeBase64:
+		data, err = p.generateBytes(base64.StdEncoding.EncodeToString)
+	case profileTypeUUID:
+		data, err = generateUUID()
+	case profileTypePassphrase:
+		data, err = p.generatePassphrase()
+	case profileTypeRSA:
+		bits := p.Bits
+		if bits == 0 {
+			bits = 4096
+		}
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, err
+		}
+		return generateKeyPairParts(key, cert)
+	case profileTypeEC:
+		key, err := ecdsa.GenerateKey(profileCurves[p.curve()], rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return generateKeyPairParts(key, cert)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []generatedPart{{data: data}}, nil
+}
+
+// charsGenerator returns the generator of a chars profile.
+func (p generateProfile) charsGenerator() (randchar.Generator, error) {
+	charset := charsetValue{}
+	charsetNames := p.Charset
+	if charsetNames == "" {
+		charsetNames = "alphanumeric"
+	}
+	err := charset.Set(charsetNames)
+	if err != nil {
+		return nil, err
+	}
+
+	mins := minRuleValue{}
+	for _, min := range p.Min {
+		err = mins.Set(min)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return randchar.NewRand(charset.v, mins.v...)
+}
+
+func (p generateProfile) generateChars() ([]byte, error) {
+	generator, err := p.charsGenerator()
+	if err != nil {
+		return nil, err
+	}
+
+	length := p.Length
+	if length == 0 {
+		length = defaultLength
+	}
+	return generator.Generate(length)
+}
+
+func (p generateProfile) generateBytes(encode func([]byte) string) ([]byte, error) {
+	n := p.Bytes
+	if n == 0 {
+		n = 32
+	}
+
+	random := make([]byte, n)
+	_, err := rand.Read(random)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(encode(random)), nil
+}
+
+// generatePassphrase returns a passphrase of words that are chosen uniformly at random from passphraseWords.
+func (p generateProfile) generatePassphrase() ([]byte, error) {
+	n := p.Words
+	if n == 0 {
+		n = 8
+	}
+	separator := p.Separator
+	if separator == "" {
+		separator = "-"
+	}
+
+	words := make([]string, n)
+	for i := range words {
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(passphraseWords))))
+		if err != nil {
+			return nil, err
+		}
+		words[i] = passphraseWords[index.Int64()]
+	}
+	return []byte(strings.Join(words, separator)), nil
+}
+
+func (p generateProfile) curve() string {
+	if p.Curve == "" {
+		return "p256"
+	}
+	return strings.ToLower(p.Curve)
+}
+
+// generateUUID returns a random (version 4) UUID.
+func generateUUID() ([]byte, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return []byte(fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])), nil
+}
+
+// generateKeyPairParts returns the PEM encoded private key, public key and, when cert
+// is not nil, a self-signed certificate of the key pair.
+func generateKeyPairParts(key crypto.Signer, cert *certificateOptions) ([]generatedPart, error) {
+	privateDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	parts := []generatedPart{
+		{data: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})},
+		{suffix: publicKeySuffix, data: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})},
+	}
+
+	if cert != nil {
+		certDER, err := createSelfSignedCertificate(key, *cert)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, generatedPart{suffix: certificateSuffix, data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})})
+	}
+	return parts, nil
+}
+
+// createSelfSignedCertificate returns a DER encoded certificate for the key that is signed by the key itself.
+func createSelfSignedCertificate(key crypto.Signer, options certificateOptions) ([]byte, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	keyUsage := x509.KeyUsageDigitalSignature
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		keyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: options.commonName},
+		DNSNames:              []string{options.commonName},
+		NotBefore:             now,
+		NotAfter:              now.Add(options.validity),
+		KeyUsage:              keyUsage,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+	return x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+}
@@ -0,0 +1,265 @@
+package secrethub
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestLoadProfile(t *testing.T) {
+	cases := map[string]struct {
+		name        string
+		file        string
+		expected    generateProfile
+		expectedErr error
+	}{
+		"built-in": {
+			name:     "hex32",
+			expected: generateProfile{Type: profileTypeHex, Bytes: 32},
+		},
+		"from file": {
+			name:     "pin",
+			file:     "profiles:\n  pin:\n    type: chars\n    length: 6\n    charset: numeric\n",
+			expected: generateProfile{Type: profileTypeChars, Length: 6, Charset: "numeric"},
+		},
+		"file replaces built-in": {
+			name:     "uuid",
+			file:     "profiles:\n  uuid:\n    type: hex\n    bytes: 16\n",
+			expected: generateProfile{Type: profileTypeHex, Bytes: 16},
+		},
+		"unknown profile": {
+			name:        "hex64",
+			expectedErr: ErrUnknownProfile("hex64", "alnum32, base64-32, ec-p256, ec-p384, hex32, passphrase-diceware, rsa-2048, rsa-4096, uuid"),
+		},
+		"unknown type": {
+			name:        "custom",
+			file:        "profiles:\n  custom:\n    type: ssh\n",
+			expectedErr: ErrInvalidProfile("custom", `unknown type "ssh": must be one of chars, hex, base64, uuid, passphrase, rsa and ec`),
+		},
+		"weak rsa key": {
+			name:        "rsa-1024",
+			file:        "profiles:\n  rsa-1024:\n    type: rsa\n    bits: 1024\n",
+			expectedErr: ErrInvalidProfile("rsa-1024", "bits must be at least 2048"),
+		},
+		"unknown curve": {
+			name:        "ec-p224",
+			file:        "profiles:\n  ec-p224:\n    type: ec\n    curve: p224\n",
+			expectedErr: ErrInvalidProfile("ec-p224", "curve must be one of p256, p384 and p521"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			var file string
+			if tc.file != "" {
+				file = filepath.Join(dir, "profiles.yml")
+				err := os.WriteFile(file, []byte(tc.file), 0600)
+				assert.OK(t, err)
+			}
+
+			// Act
+			actual, err := loadProfile(tc.name, file)
+
+			// Assert
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestGenerateProfile_generate(t *testing.T) {
+	cases := map[string]struct {
+		profile generateProfile
+		pattern string
+	}{
+		"hex": {
+			profile: builtinProfiles["hex32"],
+			pattern: `^[0-9a-f]{64}$`,
+		},
+		"base64": {
+			profile: builtinProfiles["base64-32"],
+			pattern: `^[A-Za-z0-9+/]{43}=$`,
+		},
+		"uuid": {
+			profile: builtinProfiles["uuid"],
+			pattern: `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+		},
+		"passphrase": {
+			profile: builtinProfiles["passphrase-diceware"],
+			pattern: `^[a-z]+(-[a-z]+){7}$`,
+		},
+		"passphrase with separator": {
+			profile: generateProfile{Type: profileTypePassphrase, Words: 4, Separator: " "},
+			pattern: `^[a-z]+( [a-z]+){3}$`,
+		},
+		"chars": {
+			profile: generateProfile{Type: profileTypeChars, Length: 6, Charset: "numeric"},
+			pattern: `^[0-9]{6}$`,
+		},
+		"chars default": {
+			profile: generateProfile{Type: profileTypeChars},
+			pattern: `^[a-zA-Z0-9]{22}$`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			parts, err := tc.profile.generate(nil)
+
+			// Assert
+			assert.OK(t, err)
+			assert.Equal(t, len(parts), 1)
+			if !regexp.MustCompile(tc.pattern).Match(parts[0].data) {
+				t.Errorf("generated secret %q does not match %s", parts[0].data, tc.pattern)
+			}
+		})
+	}
+}
+
+func TestGenerateProfile_generate_KeyPair(t *testing.T) {
+	// Act
+	parts, err := builtinProfiles["ec-p256"].generate(&certificateOptions{
+		commonName: "example.com",
+		validity:   24 * time.Hour,
+	})
+
+	// Assert
+	assert.OK(t, err)
+	assert.Equal(t, len(parts), 3)
+	assert.Equal(t, parts[0].suffix, "")
+	assert.Equal(t, parts[1].suffix, publicKeySuffix)
+	assert.Equal(t, parts[2].suffix, certificateSuffix)
+
+	privateBlock, _ := pem.Decode(parts[0].data)
+	assert.Equal(t, privateBlock.Type, "PRIVATE KEY")
+	_, err = x509.ParsePKCS8PrivateKey(privateBlock.Bytes)
+	assert.OK(t, err)
+
+	publicBlock, _ := pem.Decode(parts[1].data)
+	assert.Equal(t, publicBlock.Type, "PUBLIC KEY")
+	_, err = x509.ParsePKIXPublicKey(publicBlock.Bytes)
+	assert.OK(t, err)
+
+	certBlock, _ := pem.Decode(parts[2].data)
+	assert.Equal(t, certBlock.Type, "CERTIFICATE")
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	assert.OK(t, err)
+	assert.Equal(t, cert.Subject.CommonName, "example.com")
+	assert.OK(t, cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature))
+	assert.Equal(t, cert.RawSubjectPublicKeyInfo, publicBlock.Bytes)
+}
+
+func TestGenerateProfile_generate_CertWithoutKeyPair(t *testing.T) {
+	// Act
+	_, err := builtinProfiles["uuid"].generate(&certificateOptions{commonName: "example.com"})
+
+	// Assert
+	assert.Equal(t, err, ErrCertWithoutKeyProfile)
+}
+
+func TestGenerateSecretCommand_Run_Profile(t *testing.T) {
+	cases := map[string]struct {
+		cmd           GenerateSecretCommand
+		expectedPaths []string
+		expectedErr   error
+	}{
+		"uuid": {
+			cmd: GenerateSecretCommand{
+				profileName: "uuid",
+			},
+			expectedPaths: []string{"namespace/repo/secret"},
+		},
+		"key pair": {
+			cmd: GenerateSecretCommand{
+				profileName: "ec-p256",
+			},
+			expectedPaths: []string{"namespace/repo/secret", "namespace/repo/secret.pub"},
+		},
+		"key pair with certificate": {
+			cmd: GenerateSecretCommand{
+				profileName:    "ec-p256",
+				certCommonName: "example.com",
+				certValidity:   24 * time.Hour,
+			},
+			expectedPaths: []string{"namespace/repo/secret", "namespace/repo/secret.pub", "namespace/repo/secret.crt"},
+		},
+		"certificate without key pair": {
+			cmd: GenerateSecretCommand{
+				profileName:    "hex32",
+				certCommonName: "example.com",
+			},
+			expectedErr: ErrCertWithoutKeyProfile,
+		},
+		"clip key pair": {
+			cmd: GenerateSecretCommand{
+				profileName:     "rsa-4096",
+				copyToClipboard: true,
+			},
+			expectedErr: ErrFlagsConflict("--clip and a key profile"),
+		},
+		"length argument": {
+			cmd: GenerateSecretCommand{
+				profileName: "uuid",
+				lengthArg:   newIntValue(24),
+			},
+			expectedErr: ErrFlagsConflict("--profile and the length argument"),
+		},
+		"charset flag": {
+			cmd: GenerateSecretCommand{
+				profileName: "uuid",
+				isFlagChanged: func(name string) bool {
+					return name == "charset"
+				},
+			},
+			expectedErr: ErrFlagsConflict("--profile and --charset"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			var paths []string
+
+			tc.cmd.io = fakeui.NewIO(t)
+			tc.cmd.firstArg = cli.StringValue{Value: "namespace/repo/secret"}
+			tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					SecretService: &fakeclient.SecretService{
+						WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+							paths = append(paths, path)
+							if strings.TrimSpace(string(data)) == "" {
+								t.Errorf("empty secret written to %s", path)
+							}
+							return &api.SecretVersion{Version: 1}, nil
+						},
+					},
+				}, nil
+			}
+
+			// Act
+			err := tc.cmd.Run()
+
+			// Assert
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, paths, tc.expectedPaths)
+		})
+	}
+}
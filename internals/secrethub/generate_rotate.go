@@ -0,0 +1,162 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Errors
+var (
+	ErrRotationCommandFailed = errGenerate.Code("rotation_command_failed").ErrorPref("could not run %s: %s")
+)
+
+// templateExtensions are the file extensions of templates that are rendered to a file
+// with the same name without the extension.
+var templateExtensions = []string{".tpl", ".tmpl", ".template"}
+
+// templateRotation is a template that references a rotated secret.
+type templateRotation struct {
+	inFile string
+	// outFile is the file the template is rendered to. It is empty when the template
+	// does not have a template extension, e.g. an env-file that is read by secrethub run.
+	outFile string
+}
+
+// injectArgs returns the arguments of the command that re-renders the template.
+func (r templateRotation) injectArgs() []string {
+	return []string{"inject", "-i", r.inFile, "-o", r.outFile, "--force"}
+}
+
+// findReferencingTemplates returns the templates in the directory that reference the secret at the given path.
+func findReferencingTemplates(dir string, path string) ([]templateRotation, error) {
+	files, err := collectTemplateFiles([]string{dir})
+	if err != nil {
+		return nil, err
+	}
+
+	var rotations []templateRotation
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, ErrReadFile(file, err)
+		}
+		if !referencesSecret(string(raw), path) {
+			continue
+		}
+
+		rotation := templateRotation{inFile: file}
+		for _, ext := range templateExtensions {
+			if filepath.Ext(file) == ext {
+				rotation.outFile = strings.TrimSuffix(file, ext)
+				break
+			}
+		}
+		rotations = append(rotations, rotation)
+	}
+	return rotations, nil
+}
+
+// referencesSecret returns whether the contents contain a template tag or a secrethub://
+// reference to the secret at the given path. Secret paths are case insensitive.
+func referencesSecret(contents string, path string) bool {
+	for _, match := range regexpSecretTemplateTags.FindAllStringSubmatch(contents, -1) {
+		if strings.EqualFold(match[1], path) {
+			return true
+		}
+	}
+	for _, match := range regexpSecretsRef.FindAllStringSubmatch(contents, -1) {
+		if strings.EqualFold(strings.TrimPrefix(match[2], secretReferencePrefix), path) {
+			return true
+		}
+	}
+	return false
+}
+
+// rotateReferencingTemplates prints the templates that reference the rotated secret and the
+// commands that re-render them and restart the affected services. When cmd.runRotation is set,
+// the commands are run instead.
+func (cmd *GenerateSecretCommand) rotateReferencingTemplates(path string) error {
+	rotations, err := findReferencingTemplates(cmd.rotateTemplatesDir, path)
+	if err != nil {
+		return err
+	}
+
+	if len(rotations) == 0 {
+		fmt.Fprintf(cmd.io.Output(), "No templates in %s reference %s.\n", cmd.rotateTemplatesDir, path)
+		return nil
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "The following templates reference %s:\n", path)
+	for _, rotation := range rotations {
+		fmt.Fprintf(cmd.io.Output(), "  %s\n", rotation.inFile)
+	}
+
+	var commands [][]string
+	for _, rotation := range rotations {
+		if rotation.outFile != "" {
+			commands = append(commands, append([]string{ApplicationName}, rotation.injectArgs()...))
+		}
+	}
+
+	if !cmd.runRotation {
+		if len(commands) == 0 && cmd.restartCommand == "" {
+			return nil
+		}
+		fmt.Fprintln(cmd.io.Output(), "Run the following commands to re-render them and restart the affected services:")
+		for _, command := range commands {
+			quoted := make([]string, len(command))
+			for i, arg := range command {
+				quoted[i] = arg
+				if strings.ContainsAny(arg, " '\"$\\") {
+					quoted[i] = shellQuote(arg)
+				}
+			}
+			fmt.Fprintf(cmd.io.Output(), "  %s\n", strings.Join(quoted, " "))
+		}
+		if cmd.restartCommand != "" {
+			fmt.Fprintf(cmd.io.Output(), "  %s\n", cmd.restartCommand)
+		}
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	for _, command := range commands {
+		err = cmd.runRotationCommand(exec.Command(executable, command[1:]...), strings.Join(command, " "))
+		if err != nil {
+			return err
+		}
+	}
+	if cmd.restartCommand != "" {
+		var restart *exec.Cmd
+		if runtime.GOOS == "windows" {
+			restart = exec.Command("cmd", "/C", cmd.restartCommand)
+		} else {
+			restart = exec.Command("sh", "-c", cmd.restartCommand)
+		}
+		err = cmd.runRotationCommand(restart, cmd.restartCommand)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runRotationCommand runs the command, writing its output to stderr so that it does not
+// mix with the output of the generate command itself.
+func (cmd *GenerateSecretCommand) runRotationCommand(command *exec.Cmd, description string) error {
+	fmt.Fprintf(cmd.io.Output(), "Running %s\n", description)
+	command.Stdout = os.Stderr
+	command.Stderr = os.Stderr
+	err := command.Run()
+	if err != nil {
+		return ErrRotationCommandFailed(description, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package secrethub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestReferencesSecret(t *testing.T) {
+	cases := map[string]struct {
+		contents string
+		expected bool
+	}{
+		"template tag": {
+			contents: "password = {{ company/app/db/password }}",
+			expected: true,
+		},
+		"template tag without spaces": {
+			contents: "password = {{company/app/db/password}}",
+			expected: true,
+		},
+		"template tag different case": {
+			contents: "password = {{ Company/App/DB/Password }}",
+			expected: true,
+		},
+		"reference": {
+			contents: "DB_PASSWORD=secrethub://company/app/db/password",
+			expected: true,
+		},
+		"other secret": {
+			contents: "password = {{ company/app/db/password2 }}\nDB_USER=secrethub://company/app/db/user",
+			expected: false,
+		},
+		"plain path": {
+			contents: "company/app/db/password",
+			expected: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, referencesSecret(tc.contents, "company/app/db/password"), tc.expected)
+		})
+	}
+}
+
+func TestGenerateSecretCommand_rotateReferencingTemplates(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	files := map[string]string{
+		"config.yml.tpl": "password: {{ company/app/db/password }}\n",
+		"other.tpl":      "password: {{ company/app/other }}\n",
+		"secrethub.env":  "DB_PASSWORD={{ company/app/db/password }}\n",
+	}
+	for name, contents := range files {
+		assert.OK(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0600))
+	}
+
+	io := fakeui.NewIO(t)
+	cmd := GenerateSecretCommand{
+		io:                 io,
+		rotateTemplatesDir: dir,
+		restartCommand:     "systemctl restart app",
+	}
+
+	err := cmd.rotateReferencingTemplates("company/app/db/password")
+	assert.OK(t, err)
+
+	configTemplate := filepath.Join(dir, "config.yml.tpl")
+	expected := "The following templates reference company/app/db/password:\n" +
+		"  " + configTemplate + "\n" +
+		"  " + filepath.Join(dir, "secrethub.env") + "\n" +
+		"Run the following commands to re-render them and restart the affected services:\n" +
+		"  secrethub inject -i " + configTemplate + " -o " + filepath.Join(dir, "config.yml") + " --force\n" +
+		"  systemctl restart app\n"
+	assert.Equal(t, io.Out.String(), expected)
+}
@@ -0,0 +1,180 @@
+package secrethub
+
+// passphraseWords is the list of words that passphrases are generated from.
+// It consists of short and common English words, so that passphrases are easy to type and remember.
+var passphraseWords = []string{
+	"abacus", "abbey", "able", "abode", "about", "above", "absent", "absorb", "abyss", "academy",
+	"accent", "accept", "access", "acid", "acorn", "acre", "across", "action", "actor", "adapt",
+	"adder", "admiral", "adobe", "adopt", "adult", "advent", "advice", "aerial", "affair", "afford",
+	"afloat", "afraid", "after", "again", "agenda", "agent", "agile", "aging", "agree", "ahead",
+	"aid", "aim", "air", "airbag", "airline", "airport", "aisle", "alarm", "album", "alcove",
+	"alert", "algae", "alibi", "alien", "alike", "alive", "alley", "allow", "alloy", "almond",
+	"aloft", "alone", "along", "alpaca", "alpha", "alpine", "also", "altar", "alter", "amber",
+	"amend", "amount", "ample", "amuse", "anchor", "angel", "anger", "angle", "ankle", "annex",
+	"answer", "antler", "anvil", "apart", "apex", "apple", "apron", "aqua", "arbor", "arcade",
+	"arch", "arctic", "arena", "argue", "arise", "armor", "army", "aroma", "around", "arrow",
+	"art", "artist", "ascend", "ash", "aside", "ask", "aspect", "aspen", "asset", "atlas",
+	"atom", "attic", "audio", "audit", "august", "aunt", "aura", "author", "auto", "autumn",
+	"avenue", "avid", "avoid", "awake", "award", "aware", "axis", "axle", "babble", "baby",
+	"bacon", "badge", "bagel", "baker", "balcony", "bald", "ballad", "ballet", "bamboo", "banana",
+	"band", "banjo", "bank", "banner", "barber", "barge", "barley", "barn", "barrel", "basil",
+	"basin", "basket", "batch", "bath", "baton", "bay", "beach", "beacon", "beak", "beam",
+	"bean", "bear", "beard", "beast", "beaver", "bed", "beef", "beetle", "begin", "behind",
+	"beige", "bell", "belly", "below", "belt", "bench", "berry", "beside", "best", "bet",
+	"better", "beyond", "bicycle", "bike", "binder", "biology", "birch", "bird", "birth", "biscuit",
+	"bishop", "bison", "black", "blade", "blame", "blank", "blanket", "blast", "blaze", "blend",
+	"bless", "blimp", "blind", "blink", "bliss", "block", "blond", "blossom", "blouse", "blue",
+	"bluff", "blunt", "blur", "blush", "board", "boat", "body", "boil", "bold", "bolt",
+	"bonus", "book", "boost", "boot", "border", "borrow", "boss", "botany", "bottle", "bottom",
+	"bounce", "bow", "bowl", "box", "boxer", "brain", "brake", "branch", "brand", "brass",
+	"brave", "bread", "break", "breeze", "brew", "brick", "bride", "bridge", "brief", "bright",
+	"brim", "bring", "brisk", "broad", "broken", "bronze", "brook", "broom", "brother", "brown",
+	"brush", "bubble", "bucket", "buckle", "budget", "buffalo", "bugle", "build", "bulb", "bulk",
+	"bundle", "bunker", "burden", "burger", "burst", "bus", "bush", "butter", "button", "buyer",
+	"buzz", "cabin", "cable", "cactus", "cadet", "cafe", "cage", "cake", "calm", "camel",
+	"camera", "camp", "canal", "candle", "candy", "cane", "canoe", "canvas", "canyon", "capital",
+	"captain", "car", "caramel", "carbon", "card", "cargo", "carpet", "carrot", "cart", "carton",
+	"carve", "case", "cash", "castle", "casual", "cat", "catalog", "catch", "cattle", "cause",
+	"cave", "cedar", "ceiling", "celery", "cell", "cello", "cement", "census", "cereal", "chain",
+	"chair", "chalk", "chamber", "chance", "change", "chapel", "chapter", "charge", "chart", "chase",
+	"cheek", "cheese", "chef", "cherry", "chess", "chest", "chew", "chicken", "chief", "child",
+	"chili", "chimney", "chin", "chip", "chorus", "chrome", "chunk", "cider", "cinema", "circle",
+	"circus", "citrus", "city", "civic", "civil", "claim", "clamp", "clap", "clarify", "class",
+	"clay", "clean", "clerk", "clever", "click", "client", "cliff", "climb", "clinic", "clip",
+	"cloak", "clock", "close", "cloth", "cloud", "clover", "clown", "club", "clue", "cluster",
+	"coach", "coal", "coast", "coat", "cobalt", "cocoa", "coconut", "code", "coffee", "coil",
+	"coin", "cold", "collar", "colony", "color", "column", "combo", "comet", "comfort", "comic",
+	"common", "compass", "concert", "condor", "cone", "copper", "coral", "cord", "core", "corn",
+	"corner", "cosmic", "cottage", "cotton", "couch", "cougar", "count", "county", "couple", "course",
+	"cousin", "cover", "cowboy", "coyote", "crab", "cradle", "craft", "crane", "crater", "crayon",
+	"cream", "credit", "creek", "crew", "cricket", "crisp", "critic", "crop", "cross", "crowd",
+	"crown", "cruise", "crumb", "crust", "crystal", "cube", "cuckoo", "cupboard", "curious", "current",
+	"curtain", "curve", "cushion", "custom", "cycle", "cypress", "daisy", "dance", "dancer", "dare",
+	"dark", "dash", "data", "date", "dawn", "day", "deal", "debate", "debut", "decade",
+	"decent", "decide", "deck", "decor", "deer", "degree", "delay", "delta", "demand", "denim",
+	"dense", "dental", "depth", "deputy", "desert", "design", "desk", "detail", "device", "dial",
+	"diamond", "diary", "diesel", "diet", "digit", "dinner", "dip", "direct", "disco", "dish",
+	"distant", "ditch", "dive", "divide", "dock", "doctor", "dog", "dollar", "dolphin", "domain",
+	"dome", "donkey", "donor", "door", "dose", "double", "dough", "dove", "down", "dozen",
+	"draft", "dragon", "drama", "drawer", "dream", "dress", "drift", "drill", "drink", "drive",
+	"drizzle", "drop", "drum", "dry", "duck", "duet", "dune", "dusk", "dust", "duty",
+	"dwarf", "dynamic", "eager", "eagle", "early", "earn", "earth", "easel", "east", "easy",
+	"echo", "eclipse", "edge", "edit", "effort", "eight", "elbow", "elder", "elect", "elegant",
+	"element", "elephant", "elevator", "elite", "elk", "elm", "embark", "ember", "emblem", "emerald",
+	"emerge", "empire", "employ", "empty", "enable", "endless", "energy", "engine", "enjoy", "enough",
+	"enter", "entry", "envoy", "epic", "equal", "equip", "era", "erase", "errand", "escape",
+	"essay", "estate", "eternal", "ethics", "evening", "event", "ever", "evolve", "exact", "example",
+	"excel", "excite", "exit", "exotic", "expand", "expert", "extra", "fabric", "face", "fact",
+	"factor", "fade", "falcon", "fall", "fame", "family", "famous", "fancy", "farm", "fashion",
+	"fast", "father", "fault", "fauna", "favor", "feast", "feather", "fellow", "fence", "fern",
+	"ferry", "festival", "fetch", "fever", "fiber", "fiction", "field", "fiesta", "fig", "figure",
+	"filter", "final", "finch", "find", "finger", "finish", "fire", "firm", "first", "fish",
+	"fitness", "five", "flag", "flame", "flash", "flavor", "fleet", "flight", "flint", "float",
+	"flock", "flood", "floor", "flora", "flour", "flower", "fluid", "flute", "focus", "fog",
+	"foil", "folk", "follow", "food", "foot", "forest", "forge", "fork", "form", "fort",
+	"forum", "fossil", "found", "fox", "frame", "fresh", "friend", "frog", "front", "frost",
+	"fruit", "fuel", "funny", "future", "gadget", "galaxy", "gallery", "galley", "game", "garage",
+	"garden", "garlic", "garment", "gas", "gate", "gather", "gauge", "gazelle", "gear", "gecko",
+	"gem", "general", "genius", "gentle", "genuine", "geology", "giant", "gift", "ginger", "giraffe",
+	"glacier", "glad", "glance", "glass", "glide", "globe", "glory", "glove", "glow", "glue",
+	"goat", "gold", "golf", "good", "goose", "gorilla", "gospel", "gossip", "govern", "grace",
+	"grain", "grant", "grape", "graph", "grass", "gravel", "gravity", "great", "green", "grid",
+	"grill", "grin", "grip", "grocery", "ground", "group", "grove", "grow", "guard", "guess",
+	"guest", "guide", "guitar", "gull", "gym", "habit", "hair", "half", "hall", "hammer",
+	"hamster", "hand", "happy", "harbor", "hard", "harvest", "hat", "hatch", "haven", "hawk",
+	"hazel", "head", "health", "heart", "heat", "heavy", "hedge", "height", "helmet", "help",
+	"hen", "herb", "herd", "hero", "heron", "hidden", "high", "hike", "hill", "hint",
+	"hip", "history", "hobby", "hockey", "hold", "hole", "holiday", "hollow", "home", "honey",
+	"hood", "hook", "hope", "horizon", "horn", "horse", "hotel", "hour", "house", "hover",
+	"hub", "huge", "human", "humble", "humor", "hunt", "hurdle", "husband", "hut", "hybrid",
+	"ice", "icon", "idea", "ideal", "idle", "igloo", "image", "impact", "import", "improve",
+	"inch", "income", "index", "indoor", "infant", "inform", "inlet", "inner", "input", "insect",
+	"inside", "inspire", "install", "intact", "island", "item", "ivory", "ivy", "jacket", "jaguar",
+	"jam", "jar", "jazz", "jeans", "jelly", "jersey", "jewel", "job", "jockey", "join",
+	"joke", "journey", "joy", "judge", "juice", "jump", "jungle", "junior", "jury", "just",
+	"kayak", "keen", "keep", "kernel", "kettle", "key", "kid", "kidney", "kind", "king",
+	"kiosk", "kit", "kitchen", "kite", "kitten", "kiwi", "knee", "knife", "knock", "know",
+	"koala", "label", "labor", "lace", "ladder", "lady", "lagoon", "lake", "lamb", "lamp",
+	"land", "lane", "language", "lantern", "laptop", "large", "laser", "latch", "later", "laugh",
+	"laundry", "lava", "lawn", "layer", "lead", "leaf", "league", "lean", "learn", "leather",
+	"lecture", "left", "legend", "lemon", "length", "lens", "leopard", "lesson", "letter", "level",
+	"liberty", "library", "license", "lift", "light", "lilac", "lily", "limb", "lime", "limit",
+	"linen", "lion", "liquid", "list", "little", "live", "lizard", "llama", "load", "loan",
+	"lobby", "lobster", "local", "lock", "lodge", "logic", "long", "loop", "lottery", "loud",
+	"lounge", "love", "loyal", "lucky", "lumber", "lunar", "lunch", "lyric", "machine", "magnet",
+	"maid", "mail", "main", "major", "make", "mammal", "mango", "manor", "maple", "marble",
+	"march", "margin", "marine", "market", "mask", "mason", "master", "match", "meadow", "medal",
+	"media", "melody", "melon", "member", "memory", "menu", "mercy", "merit", "mesh", "metal",
+	"meteor", "method", "middle", "midnight", "mild", "milk", "mill", "mimic", "mind", "mineral",
+	"minor", "minute", "mirror", "mist", "mixer", "model", "modern", "moment", "monitor", "monkey",
+	"month", "moon", "moral", "morning", "mosaic", "moss", "motel", "mother", "motion", "motor",
+	"mountain", "mouse", "mouth", "move", "movie", "muffin", "mule", "muscle", "museum", "music",
+	"mustard", "mutual", "myth", "nail", "name", "napkin", "narrow", "nation", "native", "nature",
+	"navy", "near", "neck", "needle", "neon", "nephew", "nerve", "nest", "net", "network",
+	"neutral", "never", "night", "noble", "noise", "noodle", "normal", "north", "nose", "notable",
+	"note", "novel", "number", "nurse", "nut", "nylon", "oak", "oasis", "object", "ocean",
+	"octave", "odor", "offer", "office", "often", "olive", "omega", "onion", "online", "open",
+	"opera", "option", "orange", "orbit", "orchard", "orchid", "order", "organ", "origin", "ornate",
+	"ostrich", "other", "otter", "outdoor", "outer", "oval", "oven", "owl", "owner", "oxygen",
+	"oyster", "ozone", "pace", "paddle", "page", "paint", "pair", "palace", "palm", "panda",
+	"panel", "panther", "paper", "parade", "parcel", "parent", "park", "parrot", "party", "pass",
+	"pasta", "patch", "path", "patrol", "pause", "pave", "peace", "peach", "peak", "peanut",
+	"pear", "pebble", "pelican", "pencil", "penguin", "people", "pepper", "perfect", "permit", "person",
+	"pet", "phone", "photo", "phrase", "piano", "picnic", "picture", "piece", "pier", "pig",
+	"pigeon", "pillow", "pilot", "pine", "pink", "pioneer", "pipe", "pitch", "pizza", "place",
+	"planet", "plant", "plate", "play", "plaza", "pledge", "plenty", "plot", "plug", "plum",
+	"plumber", "pocket", "poem", "poet", "point", "polar", "police", "pond", "pony", "pool",
+	"popcorn", "poppy", "portal", "position", "possum", "potato", "pottery", "powder", "power", "prairie",
+	"praise", "predict", "present", "press", "price", "pride", "prince", "print", "prism", "prize",
+	"problem", "process", "produce", "profit", "program", "promise", "proof", "proud", "public", "pudding",
+	"pulse", "pumpkin", "pupil", "puppy", "purple", "puzzle", "pyramid", "quail", "quality", "quantum",
+	"quarter", "queen", "query", "quest", "quick", "quiet", "quilt", "quiz", "quote", "rabbit",
+	"raccoon", "race", "radar", "radio", "raft", "rail", "rain", "rainbow", "raise", "rally",
+	"ramp", "ranch", "random", "range", "rapid", "raven", "razor", "ready", "real", "reason",
+	"rebel", "recipe", "record", "recycle", "reef", "reflex", "region", "relax", "relay", "remote",
+	"rent", "repair", "repeat", "reptile", "rescue", "result", "retire", "return", "reunion", "reveal",
+	"review", "reward", "rhythm", "ribbon", "rice", "rich", "ride", "ridge", "right", "ring",
+	"ripple", "risk", "ritual", "rival", "river", "road", "roast", "robin", "robot", "rocket",
+	"rodeo", "roof", "rookie", "room", "root", "rope", "rose", "rotate", "rough", "round",
+	"route", "royal", "rubber", "rug", "rule", "run", "rural", "saddle", "safari", "safe",
+	"saga", "sail", "salad", "salmon", "salon", "salt", "sample", "sand", "satin", "sauce",
+	"sausage", "scale", "scarf", "scene", "scheme", "school", "science", "scout", "screen", "script",
+	"scroll", "sea", "season", "seat", "second", "secret", "sector", "seed", "select", "senior",
+	"sense", "series", "service", "session", "settle", "seven", "shadow", "shallow", "shape", "share",
+	"shark", "shed", "shelf", "shell", "shelter", "sheriff", "shield", "shift", "shine", "ship",
+	"shirt", "shoe", "shore", "short", "shoulder", "shovel", "show", "shrimp", "shrub", "sign",
+	"signal", "silk", "silver", "simple", "siren", "sister", "sketch", "ski", "skill", "skin",
+	"skirt", "skull", "sky", "slab", "sled", "sleep", "slice", "slide", "slogan", "slope",
+	"slot", "small", "smart", "smile", "smoke", "snack", "snail", "snake", "snow", "soap",
+	"soccer", "social", "sock", "soda", "sofa", "soft", "solar", "soldier", "solid", "solo",
+	"song", "sonic", "soon", "sound", "soup", "south", "space", "spark", "sparrow", "speak",
+	"speed", "sphere", "spice", "spider", "spike", "spin", "spirit", "split", "sponge", "spoon",
+	"sport", "spot", "spray", "spring", "spruce", "square", "squid", "stable", "stadium", "staff",
+	"stage", "stairs", "stamp", "stand", "star", "start", "state", "station", "statue", "steak",
+	"steel", "stem", "step", "stereo", "stick", "still", "stock", "stone", "stool", "storm",
+	"story", "stove", "strategy", "straw", "stream", "street", "strike", "strong", "studio", "style",
+	"subway", "sugar", "suit", "summer", "summit", "sun", "sunny", "sunset", "super", "supply",
+	"surface", "surge", "survey", "swallow", "swamp", "swan", "sweet", "swift", "swim", "swing",
+	"switch", "sword", "symbol", "syrup", "system", "table", "tablet", "tackle", "tactic", "tail",
+	"talent", "tank", "tape", "target", "task", "taxi", "tea", "teach", "team", "tennis",
+	"tent", "term", "test", "text", "theater", "theme", "theory", "thirty", "thread", "thrive",
+	"thumb", "thunder", "ticket", "tide", "tiger", "timber", "time", "tiny", "tip", "tissue",
+	"title", "toast", "today", "toddler", "token", "tomato", "tone", "tongue", "tool", "tooth",
+	"topic", "torch", "tornado", "total", "tourist", "towel", "tower", "town", "toy", "track",
+	"trade", "traffic", "trail", "train", "tram", "travel", "tray", "treat", "tree", "trend",
+	"trial", "tribe", "trick", "trip", "trophy", "tropic", "truck", "true", "trumpet", "trust",
+	"truth", "tulip", "tuna", "tunnel", "turkey", "turtle", "tutor", "twelve", "twenty", "twin",
+	"twist", "type", "umbrella", "uncle", "under", "unfold", "uniform", "union", "unique", "unit",
+	"universe", "unlock", "update", "upgrade", "uphold", "upper", "upset", "urban", "usage", "useful",
+	"usual", "utmost", "vacuum", "valid", "valley", "value", "valve", "vanilla", "vapor", "vast",
+	"vault", "velvet", "vendor", "venture", "venue", "verb", "verse", "vessel", "veteran", "video",
+	"view", "village", "vintage", "violin", "virtual", "visa", "visit", "visual", "vital", "vivid",
+	"vocal", "voice", "volcano", "volume", "vote", "voyage", "wagon", "waist", "walk", "wall",
+	"walnut", "walrus", "wander", "warm", "wash", "wasp", "water", "wave", "wax", "wealth",
+	"weather", "web", "wedding", "week", "weight", "welcome", "west", "whale", "wheat", "wheel",
+	"whisper", "whistle", "white", "wide", "wild", "willow", "win", "window", "wine", "wing",
+	"winner", "winter", "wire", "wisdom", "wise", "wish", "witness", "wizard", "wolf", "woman",
+	"wonder", "wood", "wool", "word", "work", "world", "worth", "wrap", "wreath", "wrist",
+	"write", "yacht", "yard", "yarn", "year", "yellow", "yes", "yield", "yoga", "young",
+	"youth", "zebra", "zero", "zest", "zigzag", "zinc", "zipper", "zodiac", "zone", "zoo",
+}
@@ -0,0 +1,80 @@
+package secrethub
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// HistoryCommand prints the mutating commands that were run on this device.
+type HistoryCommand struct {
+	io            ui.IO
+	journal       *Journal
+	limit         int
+	failedOnly    bool
+	useTimestamps bool
+}
+
+// NewHistoryCommand creates a new HistoryCommand.
+func NewHistoryCommand(io ui.IO, journal *Journal) *HistoryCommand {
+	return &HistoryCommand{
+		io:      io,
+		journal: journal,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *HistoryCommand) Register(r cli.Registerer) {
+	clause := r.Command("history", "Show the commands that changed secrets, access or accounts from this device.")
+	clause.HelpLong("The history is stored in the configuration directory. " +
+		"Only the arguments and the names of the flags of a command are recorded, never the values of secrets or flags. " +
+		"Use the --no-history flag to leave a command out of the history.")
+	clause.Flags().IntVarP(&cmd.limit, "limit", "n", 20, "The number of most recent commands to show. Set to 0 to show all commands.")
+	clause.Flags().BoolVar(&cmd.failedOnly, "failed", false, "Only show commands that failed.")
+	registerTimestampFlag(clause, &cmd.useTimestamps)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+
+	NewHistoryRerunCommand(cmd.io, cmd.journal).Register(clause)
+	NewHistoryClearCommand(cmd.io, cmd.journal).Register(clause)
+}
+
+// Run prints the most recent commands in the history.
+func (cmd *HistoryCommand) Run() error {
+	entries, err := cmd.journal.entries()
+	if err != nil {
+		return err
+	}
+
+	if cmd.failedOnly {
+		failed := make([]journalEntry, 0, len(entries))
+		for _, entry := range entries {
+			if entry.Status == journalStatusFailure {
+				failed = append(failed, entry)
+			}
+		}
+		entries = failed
+	}
+	if cmd.limit > 0 && len(entries) > cmd.limit {
+		entries = entries[len(entries)-cmd.limit:]
+	}
+
+	timeFormatter := NewTimeFormatter(cmd.useTimestamps)
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", "ID", "TIME", "STATUS", "COMMAND", "FLAGS", "ERROR")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			entry.ID,
+			timeFormatter.Format(entry.Time.Local()),
+			entry.Status,
+			entry.CommandLine(),
+			strings.Join(entry.Flags, ","),
+			entry.Error,
+		)
+	}
+	return w.Flush()
+}
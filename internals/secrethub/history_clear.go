@@ -0,0 +1,40 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// HistoryClearCommand removes all commands from the history.
+type HistoryClearCommand struct {
+	io      ui.IO
+	journal *Journal
+}
+
+// NewHistoryClearCommand creates a new HistoryClearCommand.
+func NewHistoryClearCommand(io ui.IO, journal *Journal) *HistoryClearCommand {
+	return &HistoryClearCommand{
+		io:      io,
+		journal: journal,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *HistoryClearCommand) Register(r cli.Registerer) {
+	clause := r.Command("clear", "Remove all commands from the history.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run removes the history file.
+func (cmd *HistoryClearCommand) Run() error {
+	err := cmd.journal.clear()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.io.Output(), "Cleared the command history.")
+	return nil
+}
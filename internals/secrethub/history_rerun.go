@@ -0,0 +1,107 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// Errors
+var (
+	ErrInvalidHistoryID  = errMain.Code("invalid_history_id").ErrorPref("invalid history ID %s: it must be a number")
+	ErrHistoryIDNotFound = errMain.Code("history_id_not_found").ErrorPref("there is no command with ID %d in the history")
+)
+
+// HistoryRerunCommand runs a command from the history again.
+type HistoryRerunCommand struct {
+	io      ui.IO
+	journal *Journal
+	args    cli.StringListValue
+	force   bool
+}
+
+// NewHistoryRerunCommand creates a new HistoryRerunCommand.
+func NewHistoryRerunCommand(io ui.IO, journal *Journal) *HistoryRerunCommand {
+	return &HistoryRerunCommand{
+		io:      io,
+		journal: journal,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *HistoryRerunCommand) Register(r cli.Registerer) {
+	clause := r.Command("rerun", "Run a command from the history again with the same arguments.")
+	clause.HelpLong("The values of flags are not recorded in the history, so flags are not passed to the command again. " +
+		"Extra flags and arguments for the command can be given after a double dash, e.g. `secrethub history rerun 12 -- --role admin`.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArgumentsArr(cli.Argument{Value: &cmd.args, Name: "id", Required: true, Placeholder: "<id> [-- <extra-args>...]", Description: "The ID of the command in the history, optionally followed by extra arguments for the command."})
+}
+
+// Run executes the command with the given ID from the history in a new process.
+func (cmd *HistoryRerunCommand) Run() error {
+	id, err := strconv.Atoi(cmd.args[0])
+	if err != nil {
+		return ErrInvalidHistoryID(cmd.args[0])
+	}
+	extraArgs := cmd.args[1:]
+
+	entries, err := cmd.journal.entries()
+	if err != nil {
+		return err
+	}
+
+	var entry *journalEntry
+	for i := range entries {
+		if entries[i].ID == id {
+			entry = &entries[i]
+			break
+		}
+	}
+	if entry == nil {
+		return ErrHistoryIDNotFound(id)
+	}
+
+	args := append(strings.Fields(entry.Command), entry.Args...)
+	args = append(args, extraArgs...)
+
+	if !cmd.force {
+		fmt.Fprintf(cmd.io.Output(), "This will run: %s\n", strings.Join(append([]string{ApplicationName}, args...), " "))
+		if len(entry.Flags) > 0 {
+			fmt.Fprintf(cmd.io.Output(), "The command originally also had these flags, which are not passed again: %s\n", strings.Join(entry.Flags, ", "))
+		}
+		ok, err := ui.AskYesNo(cmd.io, "Do you want to continue?", ui.DefaultNo)
+		if err == ui.ErrCannotAsk {
+			return ErrCannotDoWithoutForce
+		} else if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return nil
+		}
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	command := exec.Command(executable, args...)
+	command.Stdin = os.Stdin
+	command.Stdout = cmd.io.Stdout()
+	command.Stderr = os.Stderr
+
+	err = command.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		// The command already printed its error, so only pass on its exit code.
+		return ExitError{Code: exitErr.ExitCode()}
+	}
+	return err
+}
@@ -0,0 +1,335 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	hookPre  = "pre"
+	hookPost = "post"
+
+	hookOnSuccess = "success"
+	hookOnFailure = "failure"
+
+	// hookWebhookTimeout is how long a webhook may take to respond.
+	hookWebhookTimeout = 10 * time.Second
+)
+
+// Errors
+var (
+	ErrHookFailed = errMain.Code("hook_failed").ErrorPref("%s hook `%s` failed: %s")
+)
+
+// hookConfig configures a command to run before or after a CLI command is executed.
+//
+// Example:
+//
+//	hooks:
+//	  - command: write
+//	    paths: ["org/app/prod/*"]
+//	    when: post
+//	    run: ./notify.sh
+//	  - command: rm
+//	    on: success
+//	    webhook: ${SLACK_WEBHOOK_URL}
+type hookConfig struct {
+	// Command is the CLI command to hook into, without the application name, e.g. `write` or `repo export`.
+	Command string `yaml:"command"`
+	// Paths optionally restricts the hook to invocations with an argument matching one of these patterns.
+	// The patterns use the syntax of path.Match.
+	Paths []string `yaml:"paths"`
+	// When is either pre or post. Defaults to post.
+	When string `yaml:"when"`
+	// On optionally restricts a post hook to commands that succeeded or failed.
+	// It is either success or failure. By default, post hooks run after every execution.
+	On string `yaml:"on"`
+	// Run is the shell command to execute.
+	Run string `yaml:"run"`
+	// Webhook is the URL to POST the event to as JSON, instead of executing a command.
+	// Environment variables in the URL are expanded, so that it does not have to be stored in the file.
+	Webhook string `yaml:"webhook"`
+	// Headers are added to the webhook request. Environment variables in the values are expanded.
+	Headers map[string]string `yaml:"headers"`
+}
+
+func (h hookConfig) validate() error {
+	if h.Command == "" {
+		return fmt.Errorf("hook is missing a command")
+	}
+	if h.Run == "" && h.Webhook == "" {
+		return fmt.Errorf("hook for %s is missing a run command or webhook", h.Command)
+	}
+	if h.Run != "" && h.Webhook != "" {
+		return fmt.Errorf("hook for %s has both a run command and a webhook", h.Command)
+	}
+	if len(h.Headers) > 0 && h.Webhook == "" {
+		return fmt.Errorf("hook for %s has headers, but no webhook", h.Command)
+	}
+	if h.When != "" && h.When != hookPre && h.When != hookPost {
+		return fmt.Errorf("hook for %s has an invalid value for when: %s (expected pre or post)", h.Command, h.When)
+	}
+	if h.On != "" && h.On != hookOnSuccess && h.On != hookOnFailure {
+		return fmt.Errorf("hook for %s has an invalid value for on: %s (expected success or failure)", h.Command, h.On)
+	}
+	if h.On != "" && h.When == hookPre {
+		return fmt.Errorf("hook for %s sets on, which can only be used for post hooks", h.Command)
+	}
+	for _, pattern := range h.Paths {
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return fmt.Errorf("hook for %s has an invalid path pattern %s: %s", h.Command, pattern, err)
+		}
+	}
+	return nil
+}
+
+// match returns whether the hook applies to the given command and arguments.
+// When the hook is restricted to paths, the matching argument is returned.
+func (h hookConfig) match(command string, args []string) (bool, string) {
+	if h.Command != command {
+		return false, ""
+	}
+	if len(h.Paths) == 0 {
+		return true, ""
+	}
+	for _, arg := range args {
+		for _, pattern := range h.Paths {
+			if ok, _ := path.Match(pattern, arg); ok {
+				return true, arg
+			}
+		}
+	}
+	return false, ""
+}
+
+// runsAfter returns whether a post hook runs after a command that returned the given error.
+func (h hookConfig) runsAfter(commandErr error) bool {
+	switch h.On {
+	case hookOnSuccess:
+		return commandErr == nil
+	case hookOnFailure:
+		return commandErr != nil
+	default:
+		return true
+	}
+}
+
+// description returns how the hook is referred to in errors. Only the host of a webhook
+// is included, as its URL often contains a token.
+func (h hookConfig) description() string {
+	if h.Webhook == "" {
+		return h.Run
+	}
+	u, err := url.Parse(os.ExpandEnv(h.Webhook))
+	if err != nil || u.Host == "" {
+		return "webhook"
+	}
+	return "webhook to " + u.Host
+}
+
+// hookEvent is the context of a command execution that is passed to hooks.
+type hookEvent struct {
+	command string
+	args    []string
+	err     error
+}
+
+// HookRunner executes the hooks configured in the project configuration.
+type HookRunner struct {
+	config     *ProjectConfig
+	event      *hookEvent
+	httpClient *http.Client
+}
+
+// NewHookRunner creates a new HookRunner that reads its hooks from the given project configuration.
+func NewHookRunner(config *ProjectConfig) *HookRunner {
+	return &HookRunner{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: hookWebhookTimeout,
+		},
+	}
+}
+
+// Register registers the execution of pre hooks on the provided app.
+func (r *HookRunner) Register(app *cli.App) {
+	app.Root.AddPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+		command := strings.TrimPrefix(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()), " ")
+		// Trusting a project configuration file must work regardless of its hooks or contents.
+		if command == "config trust" {
+			return nil
+		}
+		r.event = &hookEvent{
+			command: command,
+			args:    args,
+		}
+		return r.run(hookPre)
+	})
+}
+
+// RunPost executes the post hooks for the executed command, with the error
+// the command returned. It does nothing when no command has been executed.
+func (r *HookRunner) RunPost(commandErr error) error {
+	if r.event == nil {
+		return nil
+	}
+	r.event.err = commandErr
+	return r.run(hookPost)
+}
+
+// run executes all hooks configured for the given stage that match the current event.
+func (r *HookRunner) run(stage string) error {
+	cfg, err := r.config.Get()
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range cfg.Hooks {
+		if hook.When != stage {
+			continue
+		}
+		if stage == hookPost && !hook.runsAfter(r.event.err) {
+			continue
+		}
+
+		ok, matchedPath := hook.match(r.event.command, r.event.args)
+		if !ok {
+			continue
+		}
+
+		if hook.Webhook != "" {
+			err = r.post(hook, stage, matchedPath)
+		} else {
+			err = r.exec(cfg.dir, hook, stage, matchedPath)
+		}
+		if err != nil {
+			return ErrHookFailed(stage, hook.description(), err)
+		}
+	}
+	return nil
+}
+
+// exec runs a single hook in a shell. The context of the event is passed in
+// SECRETHUB_HOOK_* environment variables. The output of the hook is written
+// to stderr, so that it does not mix with the output of the command itself.
+func (r *HookRunner) exec(dir string, hook hookConfig, stage string, matchedPath string) error {
+	var command *exec.Cmd
+	if runtime.GOOS == "windows" {
+		command = exec.Command("cmd", "/C", hook.Run)
+	} else {
+		command = exec.Command("sh", "-c", hook.Run)
+	}
+	command.Dir = dir
+	command.Stdout = os.Stderr
+	command.Stderr = os.Stderr
+	command.Env = append(os.Environ(), r.event.env(stage, matchedPath)...)
+	return command.Run()
+}
+
+// hookPayload is the JSON body that is sent to webhooks. The text field
+// makes the payload usable for chat webhooks, such as the ones of Slack.
+type hookPayload struct {
+	Text    string   `json:"text"`
+	Stage   string   `json:"stage"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	Path    string   `json:"path,omitempty"`
+	Status  string   `json:"status,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// post sends the event to the webhook of the hook. A response with a status other than 2xx is an error.
+func (r *HookRunner) post(hook hookConfig, stage string, matchedPath string) error {
+	body, err := json.Marshal(r.event.payload(stage, matchedPath))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, os.ExpandEnv(hook.Webhook), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range hook.Headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		// The error contains the URL, which can contain a token.
+		if urlErr, ok := err.(*url.Error); ok {
+			return urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// payload returns the event as it is sent to webhooks.
+func (e hookEvent) payload(stage string, matchedPath string) hookPayload {
+	payload := hookPayload{
+		Stage:   stage,
+		Command: e.command,
+		Args:    e.args,
+		Path:    matchedPath,
+	}
+	if payload.Args == nil {
+		payload.Args = []string{}
+	}
+
+	subject := strings.TrimSpace("secrethub " + e.command + " " + strings.Join(e.args, " "))
+	switch {
+	case stage == hookPre:
+		payload.Text = "Running `" + subject + "`"
+	case e.err != nil:
+		payload.Status = "failure"
+		payload.Error = e.err.Error()
+		payload.Text = "`" + subject + "` failed: " + payload.Error
+	default:
+		payload.Status = "success"
+		payload.Text = "`" + subject + "` succeeded"
+	}
+	return payload
+}
+
+// env returns the environment variables describing the event to a hook.
+func (e hookEvent) env(stage string, matchedPath string) []string {
+	env := []string{
+		"SECRETHUB_HOOK_STAGE=" + stage,
+		"SECRETHUB_HOOK_COMMAND=" + e.command,
+		"SECRETHUB_HOOK_ARGS=" + strings.Join(e.args, " "),
+		"SECRETHUB_HOOK_PATH=" + matchedPath,
+	}
+	if stage == hookPost {
+		status := "success"
+		errMessage := ""
+		if e.err != nil {
+			status = "failure"
+			errMessage = e.err.Error()
+		}
+		env = append(env,
+			"SECRETHUB_HOOK_STATUS="+status,
+			"SECRETHUB_HOOK_ERROR="+errMessage,
+		)
+	}
+	return env
+}
@@ -0,0 +1,305 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestHookConfig_match(t *testing.T) {
+	cases := map[string]struct {
+		hook        hookConfig
+		command     string
+		args        []string
+		expected    bool
+		matchedPath string
+	}{
+		"command without paths": {
+			hook:     hookConfig{Command: "write"},
+			command:  "write",
+			args:     []string{"org/app/prod/db"},
+			expected: true,
+		},
+		"other command": {
+			hook:     hookConfig{Command: "write"},
+			command:  "rm",
+			args:     []string{"org/app/prod/db"},
+			expected: false,
+		},
+		"matching path": {
+			hook:        hookConfig{Command: "write", Paths: []string{"org/app/dev/*", "org/app/prod/*"}},
+			command:     "write",
+			args:        []string{"org/app/prod/db"},
+			expected:    true,
+			matchedPath: "org/app/prod/db",
+		},
+		"no matching path": {
+			hook:     hookConfig{Command: "write", Paths: []string{"org/app/prod/*"}},
+			command:  "write",
+			args:     []string{"org/app/dev/db"},
+			expected: false,
+		},
+		"sub command": {
+			hook:     hookConfig{Command: "repo export"},
+			command:  "repo export",
+			expected: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, matchedPath := tc.hook.match(tc.command, tc.args)
+
+			assert.Equal(t, actual, tc.expected)
+			assert.Equal(t, matchedPath, tc.matchedPath)
+		})
+	}
+}
+
+func TestHookEvent_env(t *testing.T) {
+	event := hookEvent{
+		command: "write",
+		args:    []string{"org/app/prod/db"},
+		err:     errors.New("test error"),
+	}
+
+	assert.Equal(t, event.env(hookPre, "org/app/prod/db"), []string{
+		"SECRETHUB_HOOK_STAGE=pre",
+		"SECRETHUB_HOOK_COMMAND=write",
+		"SECRETHUB_HOOK_ARGS=org/app/prod/db",
+		"SECRETHUB_HOOK_PATH=org/app/prod/db",
+	})
+	assert.Equal(t, event.env(hookPost, ""), []string{
+		"SECRETHUB_HOOK_STAGE=post",
+		"SECRETHUB_HOOK_COMMAND=write",
+		"SECRETHUB_HOOK_ARGS=org/app/prod/db",
+		"SECRETHUB_HOOK_PATH=",
+		"SECRETHUB_HOOK_STATUS=failure",
+		"SECRETHUB_HOOK_ERROR=test error",
+	})
+}
+
+func TestReadProjectConfig(t *testing.T) {
+	cases := map[string]struct {
+		raw      string
+		expected []hookConfig
+		err      bool
+	}{
+		"hooks": {
+			raw: "hooks:\n" +
+				"  - command: write\n" +
+				"    paths: [\"org/app/prod/*\"]\n" +
+				"    run: ./notify.sh\n" +
+				"  - command: rm\n" +
+				"    when: pre\n" +
+				"    run: ./backup.sh\n",
+			expected: []hookConfig{
+				{Command: "write", Paths: []string{"org/app/prod/*"}, When: hookPost, Run: "./notify.sh"},
+				{Command: "rm", When: hookPre, Run: "./backup.sh"},
+			},
+		},
+		"invalid when": {
+			raw: "hooks:\n" +
+				"  - command: write\n" +
+				"    when: during\n" +
+				"    run: ./notify.sh\n",
+			err: true,
+		},
+		"webhook": {
+			raw: "hooks:\n" +
+				"  - command: write\n" +
+				"    on: success\n" +
+				"    webhook: ${SLACK_WEBHOOK_URL}\n" +
+				"    headers:\n" +
+				"      Authorization: Bearer ${TOKEN}\n",
+			expected: []hookConfig{
+				{Command: "write", When: hookPost, On: hookOnSuccess, Webhook: "${SLACK_WEBHOOK_URL}", Headers: map[string]string{"Authorization": "Bearer ${TOKEN}"}},
+			},
+		},
+		"run and webhook": {
+			raw: "hooks:\n" +
+				"  - command: write\n" +
+				"    run: ./notify.sh\n" +
+				"    webhook: https://example.com\n",
+			err: true,
+		},
+		"on for pre hook": {
+			raw: "hooks:\n" +
+				"  - command: write\n" +
+				"    when: pre\n" +
+				"    on: success\n" +
+				"    run: ./notify.sh\n",
+			err: true,
+		},
+		"missing run": {
+			raw: "hooks:\n" +
+				"  - command: write\n",
+			err: true,
+		},
+		"unknown field": {
+			raw: "hook: []\n",
+			err: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			path := filepath.Join(dir, defaultProjectConfigFilename)
+			err := os.WriteFile(path, []byte(tc.raw), 0600)
+			assert.OK(t, err)
+
+			actual, err := readProjectConfig(path)
+			assert.Equal(t, err != nil, tc.err)
+			if err == nil {
+				assert.Equal(t, actual.Hooks, tc.expected)
+				assert.Equal(t, findProjectConfig(filepath.Join(dir)), path)
+			}
+		})
+	}
+}
+
+func TestHookRunner_webhook(t *testing.T) {
+	cases := map[string]struct {
+		on       string
+		err      error
+		status   int
+		expected *hookPayload
+		runErr   bool
+	}{
+		"success": {
+			on:     hookOnSuccess,
+			status: http.StatusOK,
+			expected: &hookPayload{
+				Text:    "`secrethub write org/app/prod/db` succeeded",
+				Stage:   hookPost,
+				Command: "write",
+				Args:    []string{"org/app/prod/db"},
+				Path:    "org/app/prod/db",
+				Status:  "success",
+			},
+		},
+		"failure": {
+			err:    errors.New("test error"),
+			status: http.StatusOK,
+			expected: &hookPayload{
+				Text:    "`secrethub write org/app/prod/db` failed: test error",
+				Stage:   hookPost,
+				Command: "write",
+				Args:    []string{"org/app/prod/db"},
+				Path:    "org/app/prod/db",
+				Status:  "failure",
+				Error:   "test error",
+			},
+		},
+		"skipped on failure": {
+			on:  hookOnSuccess,
+			err: errors.New("test error"),
+		},
+		"error status": {
+			status: http.StatusInternalServerError,
+			runErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var received *hookPayload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Header.Get("X-Token"), "secret-token")
+				received = &hookPayload{}
+				assert.OK(t, json.NewDecoder(r.Body).Decode(received))
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			t.Setenv("TEST_HOOK_WEBHOOK_URL", server.URL)
+			t.Setenv("TEST_HOOK_TOKEN", "secret-token")
+
+			runner := NewHookRunner(&ProjectConfig{
+				loaded: true,
+				cfg: &projectConfig{
+					Hooks: []hookConfig{{
+						Command: "write",
+						Paths:   []string{"org/app/prod/*"},
+						When:    hookPost,
+						On:      tc.on,
+						Webhook: "${TEST_HOOK_WEBHOOK_URL}/hook",
+						Headers: map[string]string{"X-Token": "${TEST_HOOK_TOKEN}"},
+					}},
+				},
+			})
+			runner.event = &hookEvent{
+				command: "write",
+				args:    []string{"org/app/prod/db"},
+			}
+
+			err := runner.RunPost(tc.err)
+
+			assert.Equal(t, err != nil, tc.runErr)
+			if !tc.runErr {
+				assert.Equal(t, received, tc.expected)
+			}
+		})
+	}
+}
+
+func TestProjectConfig_trust(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	projectDir := filepath.Join(dir, "project")
+	assert.OK(t, os.MkdirAll(filepath.Join(projectDir, "sub"), 0700))
+	path := filepath.Join(projectDir, defaultProjectConfigFilename)
+	raw := []byte("hooks:\n" +
+		"  - command: write\n" +
+		"    run: ./notify.sh\n")
+	assert.OK(t, os.WriteFile(path, raw, 0600))
+
+	trust := &ProjectConfigTrust{
+		dir: func() string {
+			return filepath.Join(dir, "config")
+		},
+	}
+	get := func() (*projectConfig, string) {
+		warnings := &bytes.Buffer{}
+		config := &ProjectConfig{
+			trust:  trust,
+			logger: cli.NewLoggerWithOutput(warnings),
+			getwd: func() (string, error) {
+				return filepath.Join(projectDir, "sub"), nil
+			},
+		}
+		cfg, err := config.Get()
+		assert.OK(t, err)
+		return cfg, warnings.String()
+	}
+
+	// An untrusted file is not used.
+	cfg, warnings := get()
+	assert.Equal(t, len(cfg.Hooks), 0)
+	assert.Equal(t, strings.Contains(warnings, "is not trusted"), true)
+
+	assert.OK(t, trust.trust(path, raw))
+	cfg, warnings = get()
+	assert.Equal(t, len(cfg.Hooks), 1)
+	assert.Equal(t, warnings, "")
+
+	// A changed file has to be trusted again. A broken file does not break the command.
+	assert.OK(t, os.WriteFile(path, []byte("hooks: [\n"), 0600))
+	cfg, warnings = get()
+	assert.Equal(t, len(cfg.Hooks), 0)
+	assert.Equal(t, strings.Contains(warnings, "has changed since it was trusted"), true)
+}
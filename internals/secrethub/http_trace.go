@@ -0,0 +1,68 @@
+package secrethub
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Headers that identify requests, so that support can find them in the logs of the API.
+const (
+	traceIDHeader   = "X-Trace-Id"
+	requestIDHeader = "X-Request-Id"
+)
+
+// traceTransport writes a line to stderr for every request made to the API, with its method,
+// path, status, duration and request ID. Bodies are never written, because they can contain secrets.
+// Every request is sent with the same trace ID, so that all requests of one invocation can be found.
+type traceTransport struct {
+	base    http.RoundTripper
+	out     io.Writer
+	traceID string
+	now     func() time.Time
+}
+
+// newTraceTransport creates a new traceTransport that writes to the given writer.
+func newTraceTransport(base http.RoundTripper, out io.Writer, traceID string) *traceTransport {
+	return &traceTransport{
+		base:    base,
+		out:     out,
+		traceID: traceID,
+		now:     time.Now,
+	}
+}
+
+// RoundTrip executes the request and writes a line that describes it.
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(traceIDHeader, t.traceID)
+
+	start := t.now()
+	resp, err := t.base.RoundTrip(req)
+	duration := t.now().Sub(start).Round(time.Millisecond)
+
+	if err != nil {
+		fmt.Fprintf(t.out, "HTTP: %s %s failed after %s: %s\n", req.Method, req.URL.Path, duration, err)
+		return nil, err
+	}
+
+	requestID := resp.Header.Get(requestIDHeader)
+	if requestID == "" {
+		requestID = "-"
+	}
+	fmt.Fprintf(t.out, "HTTP: %s %s %d %s request-id=%s\n", req.Method, req.URL.Path, resp.StatusCode, duration, requestID)
+	return resp, nil
+}
+
+// newTraceID returns a random ID for the requests of one invocation.
+func newTraceID() (string, error) {
+	id := make([]byte, 8)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
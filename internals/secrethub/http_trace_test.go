@@ -0,0 +1,73 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestTraceTransport(t *testing.T) {
+	errNetwork := errors.New("connection reset")
+
+	cases := map[string]struct {
+		resp     *http.Response
+		err      error
+		expected string
+	}{
+		"success": {
+			resp: &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{requestIDHeader: []string{"req-1"}},
+				Body:       io.NopCloser(strings.NewReader("secret response")),
+			},
+			expected: "HTTP: POST /repos/dev1/example 200 250ms request-id=req-1\n",
+		},
+		"no request id": {
+			resp: &http.Response{
+				StatusCode: http.StatusNotFound,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader("")),
+			},
+			expected: "HTTP: POST /repos/dev1/example 404 250ms request-id=-\n",
+		},
+		"network error": {
+			err:      errNetwork,
+			expected: "HTTP: POST /repos/dev1/example failed after 250ms: connection reset\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			var traceID string
+			transport := newTraceTransport(fakeRoundTripper(func(req *http.Request) (*http.Response, error) {
+				traceID = req.Header.Get(traceIDHeader)
+				return tc.resp, tc.err
+			}), out, "0123456789abcdef")
+			start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			calls := 0
+			transport.now = func() time.Time {
+				calls++
+				if calls == 1 {
+					return start
+				}
+				return start.Add(250 * time.Millisecond)
+			}
+
+			req, err := http.NewRequest("POST", "https://api.secrethub.io/repos/dev1/example?secret=query", strings.NewReader("secret body"))
+			assert.OK(t, err)
+
+			_, err = transport.RoundTrip(req)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, out.String(), tc.expected)
+			assert.Equal(t, traceID, "0123456789abcdef")
+		})
+	}
+}
@@ -44,7 +44,7 @@ func NewInitCommand(io ui.IO, newClientWithCredentials func(credentials.Provider
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *InitCommand) Register(r cli.Registerer) {
 	clause := r.Command("init", "Initialize the SecretHub client for first use on this device.")
-	clause.Flags().StringVar(&cmd.backupCode, "backup-code", "", "The backup code used to restore an existing account to this device.")
+	clause.Flags().StringVar(&cmd.backupCode, "backup-code", "", "The backup code used to restore an existing account to this device, either as a code or in words.")
 	clause.Flags().StringVar(&cmd.setupCode, "setup-code", "", "The setup code used to configure the CLI to use an account created on the website.")
 	registerForceFlag(clause, &cmd.force)
 
@@ -186,19 +186,24 @@ func (cmd *InitCommand) Run() error {
 			return err
 		}
 
-		fmt.Fprintf(cmd.io.Output(), "Setup complete. To read your first secret, run:\n\n    secrethub read %s\n\n", secretPath)
+		ui.Successf(cmd.io.Output(), "Setup complete. To read your first secret, run:\n\n    secrethub read %s\n\n", secretPath)
 		return nil
 	case InitModeBackupCode:
 		backupCode := cmd.backupCode
 
 		if backupCode == "" {
 			var err error
-			backupCode, err = ui.AskAndValidate(cmd.io, "What is your backup code?\n", 3, credentials.ValidateBootstrapCode)
+			backupCode, err = ui.AskAndValidate(cmd.io, "What is your backup code?\n", 3, validateBackupCode)
 			if err != nil {
 				return err
 			}
 		}
 
+		backupCode, err := normalizeBackupCode(backupCode)
+		if err != nil {
+			return err
+		}
+
 		client, err := cmd.newClientWithCredentials(credentials.UseBackupCode(backupCode))
 		if err != nil {
 			return err
@@ -9,7 +9,7 @@ import (
 	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/clip"
 	"github.com/secrethub/secrethub-cli/internals/cli/filemode"
-	"github.com/secrethub/secrethub-cli/internals/cli/posix"
+	"github.com/secrethub/secrethub-cli/internals/cli/secval"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl"
 
@@ -18,7 +18,7 @@ import (
 
 // Errors
 var (
-	ErrUnknownTemplateVersion = errMain.Code("unknown_template_version").ErrorPref("unknown template version: '%s' supported versions are 1, 2 and latest")
+	ErrUnknownTemplateVersion = errMain.Code("unknown_template_version").ErrorPref("unknown template version: '%s' supported versions are 1, 2, 3 and latest")
 	ErrReadFile               = errMain.Code("in_file_read_error").ErrorPref("could not read the input file %s: %s")
 )
 
@@ -36,10 +36,11 @@ type InjectCommand struct {
 	templateVars                  map[string]string
 	templateVersion               string
 	dontPromptMissingTemplateVars bool
+	cache                         *SecretCache
 }
 
 // NewInjectCommand creates a new InjectCommand.
-func NewInjectCommand(io ui.IO, newClient newClientFunc) *InjectCommand {
+func NewInjectCommand(io ui.IO, newClient newClientFunc, cache *SecretCache) *InjectCommand {
 	return &InjectCommand{
 		clipWriter: &ClipboardWriterAutoClear{
 			clipper: clip.NewClipboard(),
@@ -49,6 +50,7 @@ func NewInjectCommand(io ui.IO, newClient newClientFunc) *InjectCommand {
 		newClient:    newClient,
 		templateVars: make(map[string]string),
 		fileMode:     filemode.New(0600),
+		cache:        cache,
 	}
 }
 
@@ -62,15 +64,17 @@ func (cmd *InjectCommand) Register(r cli.Registerer) {
 			"Copy the injected template to the clipboard instead of stdout. The clipboard is automatically cleared after %s.",
 			units.HumanDuration(clearClipboardAfter),
 		))
-	clause.Flags().StringVarP(&cmd.inFile, "in-file", "i", "", "The filename of a template file to inject.")
+	clause.Flags().StringVarP(&cmd.inFile, "in-file", "i", "", "The filename of a template file to inject. Other template files can be included with {{ include \"path/to/file\" }}, relative to the including file.")
+	_ = clause.Cmd.MarkFlagFilename("in-file")
 	clause.Flags().StringVarP(&cmd.outFile, "out-file", "o", "", "Write the injected template to a file instead of stdout.")
 	clause.Flags().StringVar(&cmd.outFile, "file", "", "") // Alias of --out-file (for backwards compatibility)
 	clause.Cmd.Flag("file").Hidden = true
 	clause.Flags().Var(&cmd.fileMode, "file-mode", "Set filemode for the output file if it does not yet exist. It is ignored without the --out-file flag.")
 	clause.Flags().StringToStringVarP(&cmd.templateVars, "var", "v", nil, "Define the value for a template variable with `VAR=VALUE`, e.g. --var env=prod")
-	clause.Flags().StringVar(&cmd.templateVersion, "template-version", "auto", "Do not prompt when a template variable is missing and return an error instead.")
+	clause.Flags().StringVar(&cmd.templateVersion, "template-version", "auto", "The template syntax version to be used. The options are v1, v2, v3, latest or auto to automatically detect the version.")
 	clause.Flags().BoolVar(&cmd.dontPromptMissingTemplateVars, "no-prompt", false, "Do not prompt when a template variable is missing and return an error instead.")
 	clause.Flags().BoolVarP(&cmd.force, "force", "f", false, "Overwrite the output file if it already exists, without prompting for confirmation. This flag is ignored if no --out-file is supplied.")
+	cmd.cache.register(clause)
 
 	clause.BindAction(cmd.Run)
 	clause.BindArguments(nil)
@@ -101,6 +105,12 @@ func (cmd *InjectCommand) Run() error {
 		}
 	}
 
+	expanded, sources, err := tpl.ExpandIncludes(string(raw), cmd.inFile, os.ReadFile)
+	if err != nil {
+		return err
+	}
+	raw = []byte(expanded)
+
 	osEnv, _ := parseKeyValueStringsToMap(cmd.osEnv)
 
 	var templateVariableReader tpl.VariableReader
@@ -120,17 +130,19 @@ func (cmd *InjectCommand) Run() error {
 
 	template, err := parser.Parse(string(raw), 1, 1)
 	if err != nil {
-		return err
+		return sources.Translate(err)
 	}
 
-	injected, err := template.Evaluate(templateVariableReader, newSecretReader(cmd.newClient))
+	injected, err := template.Evaluate(templateVariableReader, newCachingSecretReader(newSecretCacheReader(newSecretReader(cmd.newClient), cmd.cache)))
 	if err != nil {
 		return err
 	}
 
-	out := []byte(injected)
+	out := secval.New([]byte(injected))
+	defer out.Wipe()
+
 	if cmd.useClipboard {
-		err = cmd.clipWriter.Write(out)
+		err = cmd.clipWriter.Write(out.Bytes())
 		if err != nil {
 			return err
 		}
@@ -164,7 +176,10 @@ func (cmd *InjectCommand) Run() error {
 			}
 		}
 
-		err = os.WriteFile(cmd.outFile, posix.AddNewLine(out), cmd.fileMode.FileMode())
+		withNewLine := out.WithNewLine()
+		defer withNewLine.Wipe()
+
+		err = os.WriteFile(cmd.outFile, withNewLine.Bytes(), cmd.fileMode.FileMode())
 		if err != nil {
 			return ErrCannotWrite(cmd.outFile, err)
 		}
@@ -176,7 +191,10 @@ func (cmd *InjectCommand) Run() error {
 
 		fmt.Fprintf(cmd.io.Output(), "%s\n", absPath)
 	} else {
-		fmt.Fprintf(cmd.io.Output(), "%s", posix.AddNewLine(out))
+		withNewLine := out.WithNewLine()
+		defer withNewLine.Wipe()
+
+		_, _ = cmd.io.Output().Write(withNewLine.Bytes())
 	}
 
 	return nil
@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"time"
+
 	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-go/internals/api"
@@ -10,26 +12,37 @@ import (
 // a path as argument that is not a repository- or secret-path.
 var ErrInspectResourceNotSupported = errMain.Code("inspect_resource_not_supported").Error("currently only inspecting repositories or secrets is supported")
 
+// ErrInspectValueRequiresSecret is returned when --cert or --ssh-key is used with a path that is not a secret path.
+var ErrInspectValueRequiresSecret = errMain.Code("inspect_value_requires_secret").Error("--cert and --ssh-key can only be used to inspect a secret")
+
 // InspectCommand prints information about a repository or a secret.
 type InspectCommand struct {
-	path          api.Path
-	io            ui.IO
-	newClient     newClientFunc
-	timeFormatter TimeFormatter
+	path           api.Path
+	cert           bool
+	sshKey         bool
+	expiringWithin dayDurationValue
+	io             ui.IO
+	newClient      newClientFunc
+	timeFormatter  TimeFormatter
 }
 
 // NewInspectCommand creates a new InspectCommand.
 func NewInspectCommand(io ui.IO, newClient newClientFunc) *InspectCommand {
 	return &InspectCommand{
-		io:            io,
-		newClient:     newClient,
-		timeFormatter: NewTimeFormatter(true),
+		expiringWithin: dayDurationValue{Duration: 30 * 24 * time.Hour, raw: "30d"},
+		io:             io,
+		newClient:      newClient,
+		timeFormatter:  NewTimeFormatter(true),
 	}
 }
 
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *InspectCommand) Register(r cli.Registerer) {
 	clause := r.Command("inspect", "Print details of a resource.")
+	clause.Flags().BoolVar(&cmd.cert, "cert", false, "Print the details of the PEM encoded X.509 certificates in the value of the secret, like the subject, the issuer, the SANs and the expiry. "+
+		"The status of a certificate is valid, expiring, expired or not yet valid.")
+	clause.Flags().Var(&cmd.expiringWithin, "expiring-within", "With --cert, the status of a certificate that expires within this duration is expiring, e.g. 14d.")
+	clause.Flags().BoolVar(&cmd.sshKey, "ssh-key", false, "Print the type and the fingerprints of the SSH key in the value of the secret. The key can be a private key or a public key in the authorized_keys format.")
 
 	clause.BindAction(cmd.Run)
 	clause.BindArguments([]cli.Argument{
@@ -39,6 +52,13 @@ func (cmd *InspectCommand) Register(r cli.Registerer) {
 
 // Run inspects a repository or a secret
 func (cmd *InspectCommand) Run() error {
+	if cmd.cert && cmd.sshKey {
+		return ErrFlagsConflict("--cert and --ssh-key")
+	}
+	if cmd.cert || cmd.sshKey {
+		return cmd.runValue()
+	}
+
 	repoPath, err := cmd.path.ToRepoPath()
 	if err == nil {
 		repoInspectCmd := NewRepoInspectCommand(
@@ -68,3 +88,26 @@ func (cmd *InspectCommand) Run() error {
 
 	return ErrInspectResourceNotSupported
 }
+
+// runValue inspects the value of a secret as a certificate or an SSH key.
+func (cmd *InspectCommand) runValue() error {
+	secretPath, err := cmd.path.ToSecretPath()
+	if err != nil {
+		return ErrInspectValueRequiresSecret
+	}
+
+	if cmd.cert {
+		return NewInspectCertificateCommand(
+			secretPath,
+			cmd.expiringWithin.Duration,
+			cmd.io,
+			cmd.newClient,
+		).Run()
+	}
+
+	return NewInspectSSHKeyCommand(
+		secretPath,
+		cmd.io,
+		cmd.newClient,
+	).Run()
+}
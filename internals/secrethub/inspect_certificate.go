@@ -0,0 +1,175 @@
+package secrethub
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrNoCertificates = errMain.Code("no_certificates").ErrorPref("%s does not contain a PEM encoded certificate")
+)
+
+// Certificate statuses
+const (
+	certificateStatusValid       = "valid"
+	certificateStatusExpiring    = "expiring"
+	certificateStatusExpired     = "expired"
+	certificateStatusNotYetValid = "not yet valid"
+)
+
+// InspectCertificateCommand prints out the details of the X.509 certificates in a secret.
+type InspectCertificateCommand struct {
+	path           api.SecretPath
+	expiringWithin time.Duration
+	io             ui.IO
+	newClient      newClientFunc
+	timeFormatter  TimeFormatter
+	now            func() time.Time
+}
+
+// NewInspectCertificateCommand creates a new InspectCertificateCommand.
+func NewInspectCertificateCommand(path api.SecretPath, expiringWithin time.Duration, io ui.IO, newClient newClientFunc) *InspectCertificateCommand {
+	return &InspectCertificateCommand{
+		path:           path,
+		expiringWithin: expiringWithin,
+		io:             io,
+		newClient:      newClient,
+		timeFormatter:  NewTimeFormatter(true),
+		now:            time.Now,
+	}
+}
+
+// Run prints out the details of the certificates in the secret.
+func (cmd *InspectCertificateCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	secret, err := client.Secrets().Versions().GetWithData(cmd.path.Value())
+	if err != nil {
+		return err
+	}
+
+	certs, err := parseCertificates(secret.Data)
+	if err != nil {
+		return err
+	}
+	if len(certs) == 0 {
+		return ErrNoCertificates(cmd.path.Value())
+	}
+
+	out := make([]certificateOutput, len(certs))
+	for i, cert := range certs {
+		out[i] = newCertificateOutput(cert, cmd.now(), cmd.expiringWithin, cmd.timeFormatter)
+	}
+
+	output, err := cli.PrettyJSON(out)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.io.Output(), output)
+
+	return nil
+}
+
+// parseCertificates returns the certificates in the PEM encoded data, e.g. a certificate with its chain.
+// Other PEM blocks, like a private key that is stored together with the certificate, are skipped.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+}
+
+// newCertificateOutput returns the JSON output of a certificate.
+func newCertificateOutput(cert *x509.Certificate, now time.Time, expiringWithin time.Duration, timeFormatter TimeFormatter) certificateOutput {
+	out := certificateOutput{
+		Subject:           cert.Subject.String(),
+		Issuer:            cert.Issuer.String(),
+		SerialNumber:      colonHex(cert.SerialNumber.Bytes()),
+		NotBefore:         timeFormatter.Format(cert.NotBefore.Local()),
+		NotAfter:          timeFormatter.Format(cert.NotAfter.Local()),
+		DNSNames:          cert.DNSNames,
+		EmailAddresses:    cert.EmailAddresses,
+		IsCA:              cert.IsCA,
+		FingerprintSHA256: colonHex(sha256Sum(cert.Raw)),
+		Status:            certificateStatus(cert, now, expiringWithin),
+	}
+	for _, ip := range cert.IPAddresses {
+		out.IPAddresses = append(out.IPAddresses, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		out.URIs = append(out.URIs, uri.String())
+	}
+	return out
+}
+
+// certificateStatus returns whether the certificate is valid at the given time,
+// or expires within the given duration.
+func certificateStatus(cert *x509.Certificate, now time.Time, expiringWithin time.Duration) string {
+	switch {
+	case now.Before(cert.NotBefore):
+		return certificateStatusNotYetValid
+	case !now.Before(cert.NotAfter):
+		return certificateStatusExpired
+	case cert.NotAfter.Sub(now) <= expiringWithin:
+		return certificateStatusExpiring
+	default:
+		return certificateStatusValid
+	}
+}
+
+// colonHex returns the bytes as uppercase hexadecimal pairs separated by colons, as openssl prints them.
+func colonHex(b []byte) string {
+	pairs := make([]string, len(b))
+	for i, c := range b {
+		pairs[i] = fmt.Sprintf("%02X", c)
+	}
+	return strings.Join(pairs, ":")
+}
+
+func sha256Sum(b []byte) []byte {
+	sum := sha256.Sum256(b)
+	return sum[:]
+}
+
+// certificateOutput is the printable JSON format of a certificate.
+type certificateOutput struct {
+	Subject           string
+	Issuer            string
+	SerialNumber      string
+	NotBefore         string
+	NotAfter          string
+	DNSNames          []string `json:",omitempty"`
+	IPAddresses       []string `json:",omitempty"`
+	EmailAddresses    []string `json:",omitempty"`
+	URIs              []string `json:",omitempty"`
+	IsCA              bool
+	FingerprintSHA256 string
+	Status            string
+}
@@ -0,0 +1,137 @@
+package secrethub
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestCertificateStatus(t *testing.T) {
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		notBefore time.Time
+		notAfter  time.Time
+		expected  string
+	}{
+		"valid": {
+			notBefore: now.Add(-time.Hour),
+			notAfter:  now.Add(60 * 24 * time.Hour),
+			expected:  certificateStatusValid,
+		},
+		"expiring": {
+			notBefore: now.Add(-time.Hour),
+			notAfter:  now.Add(10 * 24 * time.Hour),
+			expected:  certificateStatusExpiring,
+		},
+		"expired": {
+			notBefore: now.Add(-48 * time.Hour),
+			notAfter:  now.Add(-time.Hour),
+			expected:  certificateStatusExpired,
+		},
+		"not yet valid": {
+			notBefore: now.Add(time.Hour),
+			notAfter:  now.Add(60 * 24 * time.Hour),
+			expected:  certificateStatusNotYetValid,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cert := &x509.Certificate{NotBefore: tc.notBefore, NotAfter: tc.notAfter}
+
+			actual := certificateStatus(cert, now, 30*24*time.Hour)
+
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestInspectCertificateCommand_Run(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.OK(t, err)
+	certDER, err := createSelfSignedCertificate(key, certificateOptions{commonName: "example.com", validity: 24 * time.Hour})
+	assert.OK(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.OK(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	cases := map[string]struct {
+		data             []byte
+		expectedStatuses []string
+		expectedErr      error
+	}{
+		"certificate": {
+			data:             certPEM,
+			expectedStatuses: []string{certificateStatusExpiring},
+		},
+		"chain": {
+			data:             append(append([]byte{}, certPEM...), certPEM...),
+			expectedStatuses: []string{certificateStatusExpiring, certificateStatusExpiring},
+		},
+		"certificate with key": {
+			data:             append(append([]byte{}, keyPEM...), certPEM...),
+			expectedStatuses: []string{certificateStatusExpiring},
+		},
+		"no certificate": {
+			data:        []byte("password"),
+			expectedErr: ErrNoCertificates("namespace/repo/cert"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			io := fakeui.NewIO(t)
+			cmd := NewInspectCertificateCommand("namespace/repo/cert", 30*24*time.Hour, io, func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					SecretService: &fakeclient.SecretService{
+						VersionService: &fakeclient.SecretVersionService{
+							GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+								return &api.SecretVersion{Data: tc.data}, nil
+							},
+						},
+					},
+				}, nil
+			})
+
+			// Act
+			err := cmd.Run()
+
+			// Assert
+			assert.Equal(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				return
+			}
+
+			var out []certificateOutput
+			assert.OK(t, json.Unmarshal(io.Out.Bytes(), &out))
+			statuses := make([]string, len(out))
+			for i, cert := range out {
+				statuses[i] = cert.Status
+				assert.Equal(t, cert.Subject, "CN=example.com")
+				assert.Equal(t, cert.DNSNames, []string{"example.com"})
+			}
+			assert.Equal(t, statuses, tc.expectedStatuses)
+		})
+	}
+}
+
+func TestColonHex(t *testing.T) {
+	assert.Equal(t, colonHex([]byte{0x01, 0xab, 0xff}), "01:AB:FF")
+	assert.Equal(t, colonHex(nil), "")
+}
@@ -0,0 +1,112 @@
+package secrethub
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Errors
+var (
+	ErrInvalidSSHKey = errMain.Code("invalid_ssh_key").ErrorPref("%s does not contain an SSH key: %s")
+)
+
+// InspectSSHKeyCommand prints out the details of the SSH key in a secret.
+type InspectSSHKeyCommand struct {
+	path      api.SecretPath
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewInspectSSHKeyCommand creates a new InspectSSHKeyCommand.
+func NewInspectSSHKeyCommand(path api.SecretPath, io ui.IO, newClient newClientFunc) *InspectSSHKeyCommand {
+	return &InspectSSHKeyCommand{
+		path:      path,
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Run prints out the details of the SSH key in the secret.
+func (cmd *InspectSSHKeyCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	secret, err := client.Secrets().Versions().GetWithData(cmd.path.Value())
+	if err != nil {
+		return err
+	}
+
+	out, err := newSSHKeyOutput(secret.Data)
+	if err != nil {
+		return ErrInvalidSSHKey(cmd.path.Value(), err)
+	}
+
+	output, err := cli.PrettyJSON(out)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.io.Output(), output)
+
+	return nil
+}
+
+// newSSHKeyOutput returns the JSON output of a private key, or of a public key in the authorized_keys format.
+// The fingerprints of a private key are those of its public key, so they can be compared with `ssh-keygen -l`.
+func newSSHKeyOutput(data []byte) (sshKeyOutput, error) {
+	var out sshKeyOutput
+	var publicKey ssh.PublicKey
+	if bytes.Contains(data, []byte("PRIVATE KEY-----")) {
+		signer, err := ssh.ParsePrivateKey(data)
+		if err != nil {
+			return sshKeyOutput{}, err
+		}
+		publicKey = signer.PublicKey()
+		out.IsPrivate = true
+	} else {
+		var comment string
+		var err error
+		publicKey, comment, _, _, err = ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			return sshKeyOutput{}, err
+		}
+		out.Comment = comment
+	}
+
+	out.Type = publicKey.Type()
+	out.FingerprintSHA256 = ssh.FingerprintSHA256(publicKey)
+	out.FingerprintMD5 = ssh.FingerprintLegacyMD5(publicKey)
+	if cryptoPublicKey, ok := publicKey.(ssh.CryptoPublicKey); ok {
+		switch key := cryptoPublicKey.CryptoPublicKey().(type) {
+		case *rsa.PublicKey:
+			out.Bits = key.N.BitLen()
+		case *ecdsa.PublicKey:
+			out.Bits = key.Curve.Params().BitSize
+		case ed25519.PublicKey:
+			out.Bits = 256
+		}
+	}
+	return out, nil
+}
+
+// sshKeyOutput is the printable JSON format of an SSH key.
+type sshKeyOutput struct {
+	Type              string
+	Bits              int    `json:",omitempty"`
+	Comment           string `json:",omitempty"`
+	IsPrivate         bool
+	FingerprintSHA256 string
+	FingerprintMD5    string
+}
@@ -0,0 +1,66 @@
+package secrethub
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestNewSSHKeyOutput(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.OK(t, err)
+	publicKey, err := ssh.NewPublicKey(&key.PublicKey)
+	assert.OK(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.OK(t, err)
+
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey))) + " deploy@example.com\n"
+
+	cases := map[string]struct {
+		data        []byte
+		expected    sshKeyOutput
+		expectedErr bool
+	}{
+		"private key": {
+			data: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+			expected: sshKeyOutput{
+				Type:              "ecdsa-sha2-nistp256",
+				Bits:              256,
+				IsPrivate:         true,
+				FingerprintSHA256: ssh.FingerprintSHA256(publicKey),
+				FingerprintMD5:    ssh.FingerprintLegacyMD5(publicKey),
+			},
+		},
+		"public key": {
+			data: []byte(authorizedKey),
+			expected: sshKeyOutput{
+				Type:              "ecdsa-sha2-nistp256",
+				Bits:              256,
+				Comment:           "deploy@example.com",
+				FingerprintSHA256: ssh.FingerprintSHA256(publicKey),
+				FingerprintMD5:    ssh.FingerprintLegacyMD5(publicKey),
+			},
+		},
+		"no key": {
+			data:        []byte("password"),
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := newSSHKeyOutput(tc.data)
+
+			assert.Equal(t, err != nil, tc.expectedErr)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
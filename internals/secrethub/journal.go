@@ -0,0 +1,215 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	journalFileName = "history.json"
+	// journalMaxEntries is the number of commands that are kept in the history.
+	journalMaxEntries = 1000
+
+	journalStatusSuccess = "success"
+	journalStatusFailure = "failure"
+)
+
+// Errors
+var (
+	ErrInvalidJournal = errMain.Code("invalid_history").ErrorPref("could not parse the command history file %s: %s")
+)
+
+// journaledCommands are the commands that change secrets, access or accounts. Only these
+// commands are recorded in the history.
+var journaledCommands = map[string]bool{
+	"account init":            true,
+	"acl apply":               true,
+	"acl rm":                  true,
+	"acl set":                 true,
+	"credential backup":       true,
+	"credential disable":      true,
+	"generate":                true,
+	"init":                    true,
+	"migrate apply":           true,
+	"mkdir":                   true,
+	"org init":                true,
+	"org invite":              true,
+	"org onboard":             true,
+	"org recovery setup":      true,
+	"org revoke":              true,
+	"org rm":                  true,
+	"org set-role":            true,
+	"repo archive":            true,
+	"repo init":               true,
+	"repo invite":             true,
+	"repo push":               true,
+	"repo revoke":             true,
+	"repo rm":                 true,
+	"repo unarchive":          true,
+	"rm":                      true,
+	"service aws init":        true,
+	"service gcp delete-link": true,
+	"service gcp init":        true,
+	"service gcp link":        true,
+	"service init":            true,
+	"service rotate":          true,
+	"team add":                true,
+	"team grant":              true,
+	"team sync":               true,
+	"write":                   true,
+}
+
+// journalEntry is a single command in the history. Only the arguments and the
+// names of the flags are recorded, so that no secret values end up in the history.
+type journalEntry struct {
+	ID      int       `json:"id"`
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Args    []string  `json:"args,omitempty"`
+	Flags   []string  `json:"flags,omitempty"`
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// CommandLine returns the command with its arguments, without its flags.
+func (e journalEntry) CommandLine() string {
+	return strings.Join(append([]string{ApplicationName, e.Command}, e.Args...), " ")
+}
+
+// Journal records the mutating commands that are run in a history file in the configuration directory.
+type Journal struct {
+	disabled bool
+	dir      func() string
+	logger   cli.Logger
+	now      func() time.Time
+	entry    *journalEntry
+}
+
+// NewJournal creates a new Journal that stores its history in the configuration
+// directory of the given credential config.
+func NewJournal(store CredentialConfig) *Journal {
+	return &Journal{
+		dir: func() string {
+			return store.ConfigDir().Path()
+		},
+		logger: cli.NewLogger(),
+		now:    time.Now,
+	}
+}
+
+// Register registers the flag for disabling the history and starts a journal
+// entry before a command is executed.
+func (j *Journal) Register(app *cli.App) {
+	app.PersistentFlags().BoolVar(&j.disabled, "no-history", false, "Do not record the command in the local command history.")
+	app.Root.AddPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+		command := strings.TrimPrefix(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()), " ")
+		if j.disabled || !journaledCommands[command] {
+			return nil
+		}
+
+		var flags []string
+		cmd.Flags().Visit(func(flag *pflag.Flag) {
+			if flag.Name != "no-history" {
+				flags = append(flags, flag.Name)
+			}
+		})
+
+		j.entry = &journalEntry{
+			Time:    j.now().UTC(),
+			Command: command,
+			Args:    args,
+			Flags:   flags,
+		}
+		return nil
+	})
+}
+
+// Record adds the executed command with its result to the history. It does nothing
+// when the command is not journaled. The command has already run when it is recorded,
+// so a history that cannot be written is logged as a warning and does not change the
+// outcome of the command.
+func (j *Journal) Record(commandErr error) {
+	if j.entry == nil {
+		return
+	}
+
+	j.entry.Status = journalStatusSuccess
+	if commandErr != nil {
+		j.entry.Status = journalStatusFailure
+		j.entry.Error = commandErr.Error()
+	}
+
+	err := j.append(*j.entry)
+	if err != nil {
+		j.logger.Warningf("could not write to the command history: %s", err)
+	}
+}
+
+// path returns the location of the history file.
+func (j *Journal) path() string {
+	return filepath.Join(j.dir(), journalFileName)
+}
+
+// entries returns all commands in the history, from the oldest to the newest.
+func (j *Journal) entries() ([]journalEntry, error) {
+	raw, err := os.ReadFile(j.path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, ErrCannotReadFile(j.path(), err)
+	}
+
+	var entries []journalEntry
+	err = json.Unmarshal(raw, &entries)
+	if err != nil {
+		return nil, ErrInvalidJournal(j.path(), err)
+	}
+	return entries, nil
+}
+
+// append adds the entry to the history with the next ID, dropping the oldest
+// entries when the history holds more than journalMaxEntries commands.
+func (j *Journal) append(entry journalEntry) error {
+	entries, err := j.entries()
+	if err != nil {
+		return err
+	}
+
+	entry.ID = 1
+	if len(entries) > 0 {
+		entry.ID = entries[len(entries)-1].ID + 1
+	}
+	entries = append(entries, entry)
+	if len(entries) > journalMaxEntries {
+		entries = entries[len(entries)-journalMaxEntries:]
+	}
+	return j.write(entries)
+}
+
+// clear removes all commands from the history.
+func (j *Journal) clear() error {
+	err := os.Remove(j.path())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// write atomically replaces the history file with the given entries.
+func (j *Journal) write(entries []journalEntry) error {
+	raw, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(j.path(), raw, 0600)
+}
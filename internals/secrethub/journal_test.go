@@ -0,0 +1,86 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestJournal_Record(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	warnings := &bytes.Buffer{}
+	journal := &Journal{
+		dir: func() string {
+			return dir
+		},
+		logger: cli.NewLoggerWithOutput(warnings),
+		now: func() time.Time {
+			return now
+		},
+	}
+
+	// Commands that are not journaled are not recorded.
+	journal.Record(nil)
+
+	journal.entry = &journalEntry{Time: now, Command: "write", Args: []string{"namespace/repo/secret"}, Flags: []string{"clip"}}
+	journal.Record(nil)
+	journal.entry = &journalEntry{Time: now, Command: "rm", Args: []string{"namespace/repo/secret"}}
+	journal.Record(errors.New("secret not found"))
+
+	entries, err := journal.entries()
+	assert.OK(t, err)
+	assert.Equal(t, warnings.String(), "")
+	assert.Equal(t, entries, []journalEntry{
+		{
+			ID:      1,
+			Time:    now,
+			Command: "write",
+			Args:    []string{"namespace/repo/secret"},
+			Flags:   []string{"clip"},
+			Status:  journalStatusSuccess,
+		},
+		{
+			ID:      2,
+			Time:    now,
+			Command: "rm",
+			Args:    []string{"namespace/repo/secret"},
+			Status:  journalStatusFailure,
+			Error:   "secret not found",
+		},
+	})
+	assert.Equal(t, entries[0].CommandLine(), "secrethub write namespace/repo/secret")
+}
+
+func TestJournal_MaxEntries(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	journal := &Journal{
+		dir: func() string {
+			return dir
+		},
+	}
+
+	entries := make([]journalEntry, journalMaxEntries)
+	for i := range entries {
+		entries[i] = journalEntry{ID: i + 1, Command: "write"}
+	}
+	assert.OK(t, journal.write(entries))
+
+	err := journal.append(journalEntry{Command: "rm"})
+	assert.OK(t, err)
+
+	actual, err := journal.entries()
+	assert.OK(t, err)
+	assert.Equal(t, len(actual), journalMaxEntries)
+	assert.Equal(t, actual[0].ID, 2)
+	assert.Equal(t, actual[len(actual)-1], journalEntry{ID: journalMaxEntries + 1, Command: "rm"})
+}
@@ -0,0 +1,81 @@
+package secrethub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// jsonErrorResponse is the response of a JSON API served by the CLI when a request fails.
+type jsonErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// jsonEndpoint only passes requests with the given method to the handler and writes its result as JSON.
+func jsonEndpoint(method string, handler func(r *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			writeJSONResponse(w, http.StatusMethodNotAllowed, jsonErrorResponse{
+				Code:    "method_not_allowed",
+				Message: r.Method + " is not allowed, use " + method,
+			})
+			return
+		}
+
+		resp, err := handler(r)
+		if err != nil {
+			writeJSONError(w, err)
+			return
+		}
+		writeJSONResponse(w, http.StatusOK, resp)
+	}
+}
+
+// writeJSONError writes the error as response, with the status code of the error if it has one.
+func writeJSONError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	resp := jsonErrorResponse{
+		Code:    "internal_error",
+		Message: err.Error(),
+	}
+
+	switch e := err.(type) {
+	case errio.PublicStatusError:
+		status = e.StatusCode
+		resp.Code = e.Code
+		resp.Message = e.Message
+	case errio.PublicError:
+		status = http.StatusBadRequest
+		resp.Code = e.Code
+		resp.Message = e.Message
+	}
+
+	writeJSONResponse(w, status, resp)
+}
+
+func writeJSONResponse(w http.ResponseWriter, status int, resp interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// serveHTTP serves the server until the context is done.
+func serveHTTP(ctx context.Context, server *http.Server, serve func() error) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- serve()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
@@ -0,0 +1,26 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// K8sCommand handles operations on Kubernetes clusters.
+type K8sCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewK8sCommand creates a new K8sCommand.
+func NewK8sCommand(io ui.IO, newClient newClientFunc) *K8sCommand {
+	return &K8sCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *K8sCommand) Register(r cli.Registerer) {
+	clause := r.Command("k8s", "Sync secrets to Kubernetes.")
+	NewK8sSyncCommand(cmd.io, cmd.newClient).Register(clause)
+}
@@ -0,0 +1,282 @@
+package secrethub
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/kubernetes"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+const (
+	k8sManagedByLabel   = "app.kubernetes.io/managed-by"
+	k8sManagedByValue   = "secrethub"
+	k8sSourceAnnotation = "secrethub.io/source"
+)
+
+// Errors
+var (
+	ErrMissingK8sMapping   = errMain.Code("missing_k8s_mapping").Error("at least one --map must be given")
+	ErrInvalidK8sMapping   = errMain.Code("invalid_k8s_mapping").ErrorPref("invalid value for --map: %s: must be <dir-path>:<secret-name>")
+	ErrInvalidK8sName      = errMain.Code("invalid_k8s_name").ErrorPref("invalid Kubernetes secret name %s: must consist of lowercase letters, digits, '-' and '.', and start and end with a letter or digit")
+	ErrK8sSecretNotManaged = errMain.Code("k8s_secret_not_managed").ErrorPref("the Kubernetes secret %s/%s was not created by secrethub: use --force to overwrite it")
+)
+
+// k8sNamePattern matches a valid name of a Kubernetes object (a DNS subdomain).
+var k8sNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?$`)
+
+// k8sSecretClient reads and writes Kubernetes secrets.
+type k8sSecretClient interface {
+	Namespace() string
+	GetSecret(namespace, name string) (*kubernetes.Secret, error)
+	CreateSecret(secret *kubernetes.Secret) error
+	UpdateSecret(secret *kubernetes.Secret) error
+}
+
+// k8sMapping maps a SecretHub directory to a Kubernetes secret.
+type k8sMapping struct {
+	dirPath    string
+	secretName string
+}
+
+// parseK8sMapping parses a mapping in the format <dir-path>:<secret-name>.
+func parseK8sMapping(value string) (k8sMapping, error) {
+	i := strings.LastIndex(value, ":")
+	if i < 0 {
+		return k8sMapping{}, ErrInvalidK8sMapping(value)
+	}
+
+	dirPath, err := api.NewDirPath(value[:i])
+	if err != nil {
+		return k8sMapping{}, err
+	}
+
+	name := value[i+1:]
+	if !k8sNamePattern.MatchString(name) {
+		return k8sMapping{}, ErrInvalidK8sName(name)
+	}
+
+	return k8sMapping{
+		dirPath:    dirPath.Value(),
+		secretName: name,
+	}, nil
+}
+
+// K8sSyncCommand writes the secrets in SecretHub directories to Kubernetes secrets.
+type K8sSyncCommand struct {
+	mappings     []string
+	namespace    string
+	kubeconfig   string
+	kubeContext  string
+	watch        bool
+	interval     time.Duration
+	force        bool
+	io           ui.IO
+	newClient    newClientFunc
+	newK8sClient func(kubeconfig, kubeContext string) (k8sSecretClient, error)
+	wait         func(ctx context.Context, d time.Duration) bool
+}
+
+// NewK8sSyncCommand creates a new K8sSyncCommand.
+func NewK8sSyncCommand(io ui.IO, newClient newClientFunc) *K8sSyncCommand {
+	return &K8sSyncCommand{
+		io:        io,
+		newClient: newClient,
+		newK8sClient: func(kubeconfig, kubeContext string) (k8sSecretClient, error) {
+			return kubernetes.NewClient(kubeconfig, kubeContext)
+		},
+		wait: waitContext,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *K8sSyncCommand) Register(r cli.Registerer) {
+	clause := r.Command("sync", "Write the secrets in SecretHub directories to Kubernetes secrets.")
+	clause.HelpLong("Every --map writes all secrets in a SecretHub directory, including its subdirectories, to a Kubernetes secret of type Opaque. " +
+		"The key of a secret is its path relative to the directory, with the slashes replaced by dots. " +
+		"The Kubernetes secret is created when it does not exist and its data is replaced otherwise.\n\n" +
+		"When the CLI runs in a Kubernetes pod, the service account of the pod is used to connect to the cluster. " +
+		"Otherwise, the current context of the kubeconfig file in $KUBECONFIG or ~/.kube/config is used. " +
+		"Only token and client certificate authentication are supported.\n\n" +
+		"Kubernetes secrets that were not created by this command are not overwritten, unless --force is given.\n\n" +
+		"With --watch, the secrets are synced again every interval until the command is stopped.")
+	clause.Flags().StringArrayVar(&cmd.mappings, "map", nil, "A SecretHub directory and the Kubernetes secret to write its secrets to, e.g. my-org/my-repo/app:app-secrets. Can be repeated.")
+	clause.Flags().StringVar(&cmd.namespace, "namespace", "", "The Kubernetes namespace of the secrets. Defaults to the namespace of the kubeconfig context or the pod.")
+	clause.Flags().StringVar(&cmd.kubeconfig, "kubeconfig", "", "The kubeconfig file to use.")
+	clause.Flags().StringVar(&cmd.kubeContext, "context", "", "The kubeconfig context to use instead of the current context.")
+	clause.Flags().BoolVar(&cmd.watch, "watch", false, "Keep running and sync the secrets every interval. Stop with Ctrl+C.")
+	clause.Flags().DurationVar(&cmd.interval, "interval", time.Minute, "The time between syncs when watching.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+}
+
+// Run syncs the secrets and keeps syncing them until interrupted when watching.
+func (cmd *K8sSyncCommand) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return cmd.run(ctx)
+}
+
+func (cmd *K8sSyncCommand) run(ctx context.Context) error {
+	if len(cmd.mappings) == 0 {
+		return ErrMissingK8sMapping
+	}
+	if cmd.watch && cmd.interval <= 0 {
+		return ErrInvalidPollInterval(cmd.interval)
+	}
+
+	mappings := make([]k8sMapping, len(cmd.mappings))
+	for i, value := range cmd.mappings {
+		mapping, err := parseK8sMapping(value)
+		if err != nil {
+			return err
+		}
+		mappings[i] = mapping
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	k8sClient, err := cmd.newK8sClient(cmd.kubeconfig, cmd.kubeContext)
+	if err != nil {
+		return err
+	}
+
+	namespace := cmd.namespace
+	if namespace == "" {
+		namespace = k8sClient.Namespace()
+	}
+
+	for {
+		for _, mapping := range mappings {
+			err = cmd.sync(client, k8sClient, namespace, mapping)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !cmd.watch || !cmd.wait(ctx, cmd.interval) {
+			return nil
+		}
+	}
+}
+
+// sync writes the secrets in the directory of the mapping to the Kubernetes secret.
+func (cmd *K8sSyncCommand) sync(client secrethub.ClientInterface, k8sClient k8sSecretClient, namespace string, mapping k8sMapping) error {
+	data, err := k8sSecretData(client, mapping.dirPath)
+	if err != nil {
+		return err
+	}
+
+	secret, err := k8sClient.GetSecret(namespace, mapping.secretName)
+	if err == kubernetes.ErrSecretNotFound {
+		secret = kubernetes.NewSecret(namespace, mapping.secretName)
+		secret.Metadata.Labels = map[string]string{k8sManagedByLabel: k8sManagedByValue}
+		secret.Metadata.Annotations = map[string]string{k8sSourceAnnotation: mapping.dirPath}
+		secret.Data = data
+
+		err = k8sClient.CreateSecret(secret)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Created %s/%s with %d %s from %s.\n", namespace, mapping.secretName, len(data), pluralize("key", "keys", len(data)), mapping.dirPath)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if secret.Metadata.Labels[k8sManagedByLabel] != k8sManagedByValue && !cmd.force {
+		return ErrK8sSecretNotManaged(namespace, mapping.secretName)
+	}
+
+	if k8sSecretDataEqual(secret.Data, data) && secret.Metadata.Labels[k8sManagedByLabel] == k8sManagedByValue {
+		// Only report unchanged secrets on a single sync, to keep the output of --watch limited to changes.
+		if !cmd.watch {
+			fmt.Fprintf(cmd.io.Output(), "%s/%s is up to date.\n", namespace, mapping.secretName)
+		}
+		return nil
+	}
+
+	if secret.Metadata.Labels == nil {
+		secret.Metadata.Labels = map[string]string{}
+	}
+	secret.Metadata.Labels[k8sManagedByLabel] = k8sManagedByValue
+	if secret.Metadata.Annotations == nil {
+		secret.Metadata.Annotations = map[string]string{}
+	}
+	secret.Metadata.Annotations[k8sSourceAnnotation] = mapping.dirPath
+	secret.Data = data
+
+	err = k8sClient.UpdateSecret(secret)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.io.Output(), "Updated %s/%s with %d %s from %s.\n", namespace, mapping.secretName, len(data), pluralize("key", "keys", len(data)), mapping.dirPath)
+	return nil
+}
+
+// k8sSecretData returns the latest version of every secret in the directory and its subdirectories,
+// keyed by the path of the secret relative to the directory with the slashes replaced by dots.
+func k8sSecretData(client secrethub.ClientInterface, dirPath string) (map[string][]byte, error) {
+	tree, err := client.Dirs().GetTree(dirPath, -1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rootPath, err := tree.AbsDirPath(tree.RootDir.DirID)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(tree.Secrets))
+	for id := range tree.Secrets {
+		secretPath, err := tree.AbsSecretPath(id)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, secretPath.String())
+	}
+	sort.Strings(paths)
+
+	data := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		version, err := client.Secrets().Versions().GetWithData(path)
+		if err != nil {
+			return nil, err
+		}
+
+		key := strings.ReplaceAll(strings.TrimPrefix(path, rootPath.String()+"/"), "/", ".")
+		data[key] = version.Data
+	}
+	return data, nil
+}
+
+// k8sSecretDataEqual returns whether the data of two Kubernetes secrets is equal.
+func k8sSecretDataEqual(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		other, ok := b[key]
+		if !ok || !bytes.Equal(value, other) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,217 @@
+package secrethub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+	"github.com/secrethub/secrethub-cli/internals/kubernetes"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+type fakeK8sSecretClient struct {
+	secrets map[string]*kubernetes.Secret
+	created []string
+	updated []string
+}
+
+func (c *fakeK8sSecretClient) Namespace() string {
+	return "default"
+}
+
+func (c *fakeK8sSecretClient) GetSecret(namespace, name string) (*kubernetes.Secret, error) {
+	secret, ok := c.secrets[namespace+"/"+name]
+	if !ok {
+		return nil, kubernetes.ErrSecretNotFound
+	}
+	return secret, nil
+}
+
+func (c *fakeK8sSecretClient) CreateSecret(secret *kubernetes.Secret) error {
+	c.created = append(c.created, secret.Metadata.Namespace+"/"+secret.Metadata.Name)
+	c.secrets[secret.Metadata.Namespace+"/"+secret.Metadata.Name] = secret
+	return nil
+}
+
+func (c *fakeK8sSecretClient) UpdateSecret(secret *kubernetes.Secret) error {
+	c.updated = append(c.updated, secret.Metadata.Namespace+"/"+secret.Metadata.Name)
+	c.secrets[secret.Metadata.Namespace+"/"+secret.Metadata.Name] = secret
+	return nil
+}
+
+func TestK8sSyncCommand_run(t *testing.T) {
+	appID := uuid.New()
+	dbID := uuid.New()
+	tree := &api.Tree{
+		ParentPath: "org/repo",
+		RootDir: &api.Dir{
+			Name:  "app",
+			DirID: appID,
+		},
+		Dirs: map[uuid.UUID]*api.Dir{
+			appID: {
+				Name:  "app",
+				DirID: appID,
+			},
+			dbID: {
+				Name:     "db",
+				DirID:    dbID,
+				ParentID: &appID,
+			},
+		},
+		Secrets: map[uuid.UUID]*api.Secret{},
+	}
+	for name, dirID := range map[string]uuid.UUID{"api_key": appID, "password": dbID} {
+		secretID := uuid.New()
+		tree.Secrets[secretID] = &api.Secret{SecretID: secretID, DirID: dirID, Name: name}
+	}
+	data := map[string][]byte{
+		"org/repo/app/api_key":     []byte("key"),
+		"org/repo/app/db/password": []byte("pass"),
+	}
+
+	cases := map[string]struct {
+		cmd             K8sSyncCommand
+		existing        map[string]*kubernetes.Secret
+		expectedCreated []string
+		expectedUpdated []string
+		expectedData    map[string][]byte
+		expectedOut     string
+		expectedErr     error
+	}{
+		"create": {
+			cmd: K8sSyncCommand{
+				mappings:  []string{"org/repo/app:app-secrets"},
+				namespace: "prod",
+			},
+			existing:        map[string]*kubernetes.Secret{},
+			expectedCreated: []string{"prod/app-secrets"},
+			expectedData: map[string][]byte{
+				"api_key":     []byte("key"),
+				"db.password": []byte("pass"),
+			},
+			expectedOut: "Created prod/app-secrets with 2 keys from org/repo/app.\n",
+		},
+		"update": {
+			cmd: K8sSyncCommand{
+				mappings: []string{"org/repo/app:app-secrets"},
+			},
+			existing: map[string]*kubernetes.Secret{
+				"default/app-secrets": {
+					Metadata: kubernetes.ObjectMeta{
+						Name:            "app-secrets",
+						Namespace:       "default",
+						Labels:          map[string]string{k8sManagedByLabel: k8sManagedByValue},
+						ResourceVersion: "12",
+					},
+					Data: map[string][]byte{"api_key": []byte("old")},
+				},
+			},
+			expectedUpdated: []string{"default/app-secrets"},
+			expectedData: map[string][]byte{
+				"api_key":     []byte("key"),
+				"db.password": []byte("pass"),
+			},
+			expectedOut: "Updated default/app-secrets with 2 keys from org/repo/app.\n",
+		},
+		"up to date": {
+			cmd: K8sSyncCommand{
+				mappings: []string{"org/repo/app:app-secrets"},
+			},
+			existing: map[string]*kubernetes.Secret{
+				"default/app-secrets": {
+					Metadata: kubernetes.ObjectMeta{
+						Name:      "app-secrets",
+						Namespace: "default",
+						Labels:    map[string]string{k8sManagedByLabel: k8sManagedByValue},
+					},
+					Data: map[string][]byte{
+						"api_key":     []byte("key"),
+						"db.password": []byte("pass"),
+					},
+				},
+			},
+			expectedData: map[string][]byte{
+				"api_key":     []byte("key"),
+				"db.password": []byte("pass"),
+			},
+			expectedOut: "default/app-secrets is up to date.\n",
+		},
+		"not managed": {
+			cmd: K8sSyncCommand{
+				mappings: []string{"org/repo/app:app-secrets"},
+			},
+			existing: map[string]*kubernetes.Secret{
+				"default/app-secrets": {
+					Metadata: kubernetes.ObjectMeta{
+						Name:      "app-secrets",
+						Namespace: "default",
+					},
+					Data: map[string][]byte{"other": []byte("value")},
+				},
+			},
+			expectedData: map[string][]byte{"other": []byte("value")},
+			expectedErr:  ErrK8sSecretNotManaged("default", "app-secrets"),
+		},
+		"no mapping": {
+			expectedErr: ErrMissingK8sMapping,
+		},
+		"invalid mapping": {
+			cmd: K8sSyncCommand{
+				mappings: []string{"org/repo/app"},
+			},
+			expectedErr: ErrInvalidK8sMapping("org/repo/app"),
+		},
+		"invalid secret name": {
+			cmd: K8sSyncCommand{
+				mappings: []string{"org/repo/app:App_Secrets"},
+			},
+			expectedErr: ErrInvalidK8sName("App_Secrets"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			k8sClient := &fakeK8sSecretClient{secrets: tc.existing}
+
+			tc.cmd.io = io
+			tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					DirService: &fakeclient.DirService{
+						GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+							return tree, nil
+						},
+					},
+					SecretService: &fakeclient.SecretService{
+						VersionService: &fakeclient.SecretVersionService{
+							GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+								return &api.SecretVersion{Data: data[path]}, nil
+							},
+						},
+					},
+				}, nil
+			}
+			tc.cmd.newK8sClient = func(kubeconfig, kubeContext string) (k8sSecretClient, error) {
+				return k8sClient, nil
+			}
+
+			err := tc.cmd.run(context.Background())
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, k8sClient.created, tc.expectedCreated)
+			assert.Equal(t, k8sClient.updated, tc.expectedUpdated)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+			if tc.expectedData != nil {
+				for _, secret := range k8sClient.secrets {
+					assert.Equal(t, secret.Data, tc.expectedData)
+				}
+			}
+		})
+	}
+}
@@ -8,7 +8,6 @@ import (
 
 	libkeyring "github.com/zalando/go-keyring"
 
-	"github.com/secrethub/secrethub-cli/internals/cli/cloneproc"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
 )
@@ -67,15 +66,11 @@ func (pr *passphraseReader) Read() ([]byte, error) {
 }
 
 // NewPassphraseReader constructs a new PassphraseReader using values in the CLI.
-func NewPassphraseReader(io ui.IO, credentialPassphrase string, credentialPassphraseTTL time.Duration) credentials.Reader {
-	ttl := credentialPassphraseTTL
-	cleaner := NewKeyringCleaner()
-	keyring := NewKeyring()
-
+func NewPassphraseReader(io ui.IO, credentialPassphrase string, cache *PassphraseCache) credentials.Reader {
 	return &passphraseReader{
 		io:        io,
 		FlagValue: credentialPassphrase,
-		Cache:     NewPassphraseCache(ttl, cleaner, keyring),
+		Cache:     cache,
 	}
 }
 
@@ -121,12 +116,17 @@ func (pr *passphraseReader) get() (string, error) {
 		if err != nil {
 			return "", err
 		}
+	} else if pr.Cache.ttl > 0 && passphrase != "" {
+		logger.Warningf("The passphrase is not cached, because the passphrase cache backend is not available. " +
+			"On systems without an OS keyring, set --passphrase-cache-backend=file to cache it in an encrypted file instead, " +
+			"or set --passphrase-cache-backend=none to turn off caching.")
 	}
 
 	return passphrase, nil
 }
 
 // PassphraseCache caches passphrases in a keyring for a given time to live.
+// The keyring is the OS keyring or one of the other backends set with --passphrase-cache-backend.
 type PassphraseCache struct {
 	keyring Keyring
 	ttl     time.Duration
@@ -158,15 +158,14 @@ func (c PassphraseCache) Set(passphrase string) error {
 		return err
 	}
 
-	if !item.RunningCleanupProcess {
-		err = c.cleaner.Cleanup()
-		if err != nil {
-			return err
-		}
-	}
-
 	item.ExpiresAt = c.ExpiresAt()
 
+	// The expiry is recorded before the item is stored, so that it is never left in the keyring unnoticed.
+	err = c.cleaner.Cleanup(item.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
 	return c.keyring.Set(item)
 }
 
@@ -186,15 +185,13 @@ func (c PassphraseCache) Get() (string, error) {
 		return "", ErrKeyringItemNotFound
 	}
 
-	if !item.RunningCleanupProcess {
-		err = c.cleaner.Cleanup()
-		if err != nil {
-			return "", err
-		}
-	}
-
 	item.ExpiresAt = c.ExpiresAt()
 
+	err = c.cleaner.Cleanup(item.ExpiresAt)
+	if err != nil {
+		return "", err
+	}
+
 	err = c.keyring.Set(item)
 	if err != nil {
 		return "", err
@@ -214,6 +211,8 @@ func (c PassphraseCache) ExpiresAt() time.Time {
 }
 
 // KeyringItem wraps a passphrase with metadata to be stored the keyring.
+// RunningCleanupProcess was set by previous versions of the CLI, which spawned a process
+// to remove the item when it expired. It is no longer used.
 type KeyringItem struct {
 	RunningCleanupProcess bool      `json:"running_cleanup_process,omitempty"`
 	ExpiresAt             time.Time `json:"expires_at"`
@@ -307,24 +306,6 @@ func (kr keyring) Delete() error {
 
 // KeyringCleaner is used to remove items from a keyring.
 type KeyringCleaner interface {
-	// Cleanup removes an item from the keyring when it expires.
-	Cleanup() error
-}
-
-// keyringCleaner cleans up the credential by spawning a new CLI process that will take care of cleaning up the credential.
-type keyringCleaner struct{}
-
-// NewKeyringCleaner returns a new KeyringCleaner.
-func NewKeyringCleaner() KeyringCleaner {
-	return &keyringCleaner{}
-}
-
-// Cleanup starts a Cleanup process to clean up the cached passphrase when it expires.
-func (kc keyringCleaner) Cleanup() error {
-	err := cloneproc.Spawn("keyring-clear")
-	if err != nil {
-		return err
-	}
-
-	return nil
+	// Cleanup makes sure that the item is removed from the keyring once it expires at the given time.
+	Cleanup(expiresAt time.Time) error
 }
@@ -0,0 +1,188 @@
+package secrethub
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+	"github.com/secrethub/secrethub-cli/internals/cli/machineid"
+
+	"github.com/secrethub/secrethub-go/internals/crypto"
+)
+
+// Errors
+var (
+	ErrInvalidPassphraseCacheBackend = errMain.Code("invalid_passphrase_cache_backend").ErrorPref("invalid passphrase cache backend %q: must be one of keyring, file or none")
+	ErrCannotReadPassphraseCacheFile = errMain.Code("cannot_read_passphrase_cache_file").ErrorPref("cannot read the passphrase cache file: %s")
+)
+
+// Passphrase cache backends that can be set with --passphrase-cache-backend.
+const (
+	passphraseCacheBackendKeyring = "keyring"
+	passphraseCacheBackendFile    = "file"
+	passphraseCacheBackendNone    = "none"
+)
+
+const passphraseCacheFileName = "passphrase_cache.json"
+
+// newPassphraseCacheKeyring returns the keyring in which the passphrase is cached with the given backend.
+func newPassphraseCacheKeyring(backend string, configDir string) (Keyring, error) {
+	switch backend {
+	case passphraseCacheBackendKeyring:
+		return NewKeyring(), nil
+	case passphraseCacheBackendFile:
+		return newFileKeyring(filepath.Join(configDir, passphraseCacheFileName)), nil
+	case passphraseCacheBackendNone:
+		return noKeyring{}, nil
+	default:
+		return nil, ErrInvalidPassphraseCacheBackend(backend)
+	}
+}
+
+// fileKeyring implements the Keyring interface by storing the item in a file in the configuration directory,
+// for systems that do not have an OS keyring, like headless servers. The item is encrypted with a key that is
+// derived from the ID of the machine and the user, so that a copy of the file cannot be decrypted elsewhere.
+// As the key can be derived by anyone who can run code as the user on the machine, the file is only readable
+// by the user.
+type fileKeyring struct {
+	path string
+	key  func() (*crypto.SymmetricKey, error)
+}
+
+// fileKeyringContent is the format in which the item is stored on disk.
+type fileKeyringContent struct {
+	Item crypto.CiphertextAES `json:"item"`
+}
+
+// newFileKeyring returns a keyring that stores its item in the file at the given path.
+func newFileKeyring(path string) *fileKeyring {
+	return &fileKeyring{
+		path: path,
+		key:  machineKey,
+	}
+}
+
+// machineKey derives the key that encrypts the passphrase cache file from the IDs of the machine and the user.
+func machineKey() (*crypto.SymmetricKey, error) {
+	id, err := machineid.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	uid := strconv.Itoa(os.Getuid())
+	current, err := user.Current()
+	if err == nil {
+		uid = current.Uid
+	}
+
+	mac := hmac.New(sha256.New, []byte(id))
+	mac.Write([]byte("secrethub-passphrase-cache:" + uid))
+	return crypto.NewSymmetricKey(mac.Sum(nil)), nil
+}
+
+// IsAvailable returns true when the ID of the machine can be read to encrypt the file with.
+func (kr fileKeyring) IsAvailable() bool {
+	_, err := kr.key()
+	return err == nil
+}
+
+// Get reads and decrypts the item from the file.
+func (kr fileKeyring) Get() (*KeyringItem, error) {
+	raw, err := os.ReadFile(kr.path)
+	if os.IsNotExist(err) {
+		return nil, ErrKeyringItemNotFound
+	} else if err != nil {
+		return nil, ErrCannotReadPassphraseCacheFile(err)
+	}
+
+	content := fileKeyringContent{}
+	err = json.Unmarshal(raw, &content)
+	if err != nil {
+		return nil, ErrCannotReadPassphraseCacheFile(err)
+	}
+
+	key, err := kr.key()
+	if err != nil {
+		return nil, ErrCannotGetKeyringItem(err)
+	}
+	plaintext, err := key.Decrypt(content.Item)
+	if err != nil {
+		// The file was copied from another machine or written for another user.
+		return nil, ErrCannotReadPassphraseCacheFile(err)
+	}
+
+	item := &KeyringItem{}
+	err = json.Unmarshal(plaintext, item)
+	if err != nil {
+		return nil, ErrCannotReadPassphraseCacheFile(err)
+	}
+	return item, nil
+}
+
+// Set encrypts the item and writes it to the file.
+func (kr fileKeyring) Set(item *KeyringItem) error {
+	plaintext, err := json.Marshal(item)
+	if err != nil {
+		return ErrCannotSetKeyringItem(err)
+	}
+
+	key, err := kr.key()
+	if err != nil {
+		return ErrCannotSetKeyringItem(err)
+	}
+	ciphertext, err := key.Encrypt(plaintext)
+	if err != nil {
+		return ErrCannotSetKeyringItem(err)
+	}
+
+	raw, err := json.Marshal(fileKeyringContent{Item: ciphertext})
+	if err != nil {
+		return ErrCannotSetKeyringItem(err)
+	}
+
+	err = atomicfile.Write(kr.path, raw, 0600)
+	if err != nil {
+		return ErrCannotSetKeyringItem(err)
+	}
+	return nil
+}
+
+// Delete removes the file.
+func (kr fileKeyring) Delete() error {
+	err := os.Remove(kr.path)
+	if os.IsNotExist(err) {
+		return ErrKeyringItemNotFound
+	} else if err != nil {
+		return ErrCannotDeleteKeyringItem(err)
+	}
+	return nil
+}
+
+// noKeyring implements the Keyring interface for --passphrase-cache-backend=none. It is never available,
+// so the passphrase is never cached.
+type noKeyring struct{}
+
+// IsAvailable always returns false.
+func (noKeyring) IsAvailable() bool {
+	return false
+}
+
+// Get always returns ErrKeyringItemNotFound.
+func (noKeyring) Get() (*KeyringItem, error) {
+	return nil, ErrKeyringItemNotFound
+}
+
+// Set does not store the item.
+func (noKeyring) Set(*KeyringItem) error {
+	return nil
+}
+
+// Delete always returns ErrKeyringItemNotFound.
+func (noKeyring) Delete() error {
+	return ErrKeyringItemNotFound
+}
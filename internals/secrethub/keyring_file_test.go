@@ -0,0 +1,96 @@
+package secrethub
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+)
+
+func newTestFileKeyring(t *testing.T, path string) *fileKeyring {
+	key, err := crypto.GenerateSymmetricKey()
+	assert.OK(t, err)
+
+	return &fileKeyring{
+		path: path,
+		key: func() (*crypto.SymmetricKey, error) {
+			return key, nil
+		},
+	}
+}
+
+func TestFileKeyring(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "config", passphraseCacheFileName)
+
+	kr := newTestFileKeyring(t, path)
+	assert.Equal(t, kr.IsAvailable(), true)
+
+	_, err := kr.Get()
+	assert.Equal(t, err, ErrKeyringItemNotFound)
+
+	item := &KeyringItem{
+		ExpiresAt:  time.Now().UTC().Add(testTTL).Round(0),
+		Passphrase: []byte(password),
+	}
+	err = kr.Set(item)
+	assert.OK(t, err)
+
+	info, err := os.Stat(path)
+	assert.OK(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0600))
+
+	raw, err := os.ReadFile(path)
+	assert.OK(t, err)
+	assert.Equal(t, bytes.Contains(raw, []byte(password)), false)
+
+	actual, err := kr.Get()
+	assert.OK(t, err)
+	assert.Equal(t, actual.Passphrase, item.Passphrase)
+	assert.Equal(t, actual.ExpiresAt.Equal(item.ExpiresAt), true)
+
+	// A file that was written on another machine or by another user cannot be decrypted.
+	_, err = newTestFileKeyring(t, path).Get()
+	assert.Equal(t, err != nil && err != ErrKeyringItemNotFound, true)
+
+	err = kr.Delete()
+	assert.OK(t, err)
+	err = kr.Delete()
+	assert.Equal(t, err, ErrKeyringItemNotFound)
+}
+
+func TestPassphraseCache_FileKeyring(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	cleaner := &TestKeyringCleaner{}
+	cache := NewPassphraseCache(testTTL, cleaner, newTestFileKeyring(t, filepath.Join(dir, passphraseCacheFileName)))
+	assert.Equal(t, cache.IsEnabled(), true)
+
+	err := cache.Set(password)
+	assert.OK(t, err)
+	assert.Equal(t, cleaner.cleanupCalled, true)
+
+	actual, err := cache.Get()
+	assert.OK(t, err)
+	assert.Equal(t, actual, password)
+}
+
+func TestNewPassphraseCacheKeyring(t *testing.T) {
+	kr, err := newPassphraseCacheKeyring(passphraseCacheBackendFile, "config")
+	assert.OK(t, err)
+	assert.Equal(t, kr.(*fileKeyring).path, filepath.Join("config", passphraseCacheFileName))
+
+	kr, err = newPassphraseCacheKeyring(passphraseCacheBackendNone, "config")
+	assert.OK(t, err)
+	assert.Equal(t, kr.IsAvailable(), false)
+	assert.Equal(t, NewPassphraseCache(testTTL, &TestKeyringCleaner{}, kr).IsEnabled(), false)
+
+	_, err = newPassphraseCacheKeyring("disk", "config")
+	assert.Equal(t, err, ErrInvalidPassphraseCacheBackend("disk"))
+}
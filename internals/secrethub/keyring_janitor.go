@@ -0,0 +1,226 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+
+	"github.com/spf13/cobra"
+)
+
+// Errors
+var (
+	ErrPassphraseCacheUnavailable = errMain.Code("passphrase_cache_unavailable").ErrorPref("cannot remove the cached passphrase: the %s passphrase cache backend is not available")
+)
+
+// passphraseCacheExpiryFileName is the file in the configuration directory that records
+// when the cached passphrase expires.
+const passphraseCacheExpiryFileName = "passphrase_cache_expiry.json"
+
+// passphraseCacheExpiry records in which backend a passphrase is cached and when it expires.
+type passphraseCacheExpiry struct {
+	Backend   string    `json:"backend"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// expiryRecorder implements the KeyringCleaner interface by recording when the cached passphrase
+// expires, so that the KeyringJanitor of a later invocation of the CLI removes it.
+type expiryRecorder struct {
+	path    string
+	backend string
+}
+
+// NewKeyringCleaner returns a KeyringCleaner that records the expiry of the passphrase that is
+// cached in the given backend in the given configuration directory.
+func NewKeyringCleaner(configDir string, backend string) KeyringCleaner {
+	return expiryRecorder{
+		path:    filepath.Join(configDir, passphraseCacheExpiryFileName),
+		backend: backend,
+	}
+}
+
+// Cleanup records that the cached passphrase expires at the given time.
+func (r expiryRecorder) Cleanup(expiresAt time.Time) error {
+	return writePassphraseCacheExpiry(r.path, passphraseCacheExpiry{
+		Backend:   r.backend,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// KeyringJanitor removes the cached passphrase once it has expired. Previous versions of the CLI spawned
+// a detached process that waited for the passphrase to expire. That process did not run on all systems,
+// leaving expired passphrases behind. Instead, every invocation of the CLI now removes an expired passphrase.
+//
+// The keyring is only accessed when the expiry that the PassphraseCache recorded in the configuration
+// directory has passed, so that commands do not query the keyring every time they run.
+type KeyringJanitor struct {
+	dir        func() string
+	newKeyring func(backend string) (Keyring, error)
+	keyring    func() Keyring
+	now        func() time.Time
+}
+
+// NewKeyringJanitor creates a new KeyringJanitor for the passphrase cache of the given credential config.
+func NewKeyringJanitor(store CredentialConfig) *KeyringJanitor {
+	return &KeyringJanitor{
+		dir: func() string {
+			return store.ConfigDir().Path()
+		},
+		newKeyring: func(backend string) (Keyring, error) {
+			return newPassphraseCacheKeyring(backend, store.ConfigDir().Path())
+		},
+		keyring: store.PassphraseCacheKeyring,
+		now:     time.Now,
+	}
+}
+
+// Register removes an expired passphrase from the passphrase cache before every command is run.
+func (j *KeyringJanitor) Register(app *cli.App) {
+	app.Root.AddPersistentPreRunE(func(_ *cobra.Command, _ []string) error {
+		j.purgeExpired()
+		return nil
+	})
+}
+
+// purgeExpired removes the cached passphrase when its recorded expiry has passed.
+// Errors are only logged, so that a broken keyring does not stop commands that do not need it.
+// When the passphrase could not be removed, the expiry is kept and removal is tried again next time.
+func (j *KeyringJanitor) purgeExpired() {
+	expiry, err := readPassphraseCacheExpiry(j.path())
+	if os.IsNotExist(err) {
+		return
+	} else if err != nil {
+		logger.Debugf("could not read the expiry of the cached passphrase: %s", err)
+		_ = j.clearExpiry()
+		return
+	}
+	if j.now().Before(expiry.ExpiresAt) {
+		return
+	}
+
+	keyring, err := j.newKeyring(expiry.Backend)
+	if err == nil {
+		_, err = j.purge(keyring, expiry.Backend, false)
+	}
+	if err != nil {
+		logger.Debugf("could not remove the expired passphrase from the passphrase cache: %s", err)
+	}
+}
+
+// purgeAll removes the cached passphrase from the backend that the recorded expiry refers to and from
+// the configured backend, also when it has not yet expired. It returns whether a passphrase was removed.
+func (j *KeyringJanitor) purgeAll() (bool, error) {
+	removed := false
+
+	expiry, err := readPassphraseCacheExpiry(j.path())
+	if err == nil {
+		keyring, err := j.newKeyring(expiry.Backend)
+		if err != nil {
+			return false, err
+		}
+		removed, err = j.purge(keyring, expiry.Backend, true)
+		if err != nil {
+			return false, err
+		}
+	} else if !os.IsNotExist(err) {
+		// The record cannot be read, so it does not tell where the passphrase is cached.
+		err = j.clearExpiry()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// The configured backend may hold a passphrase for which no expiry was recorded,
+	// like one that was cached by a previous version of the CLI.
+	keyring := j.keyring()
+	if !keyring.IsAvailable() {
+		return removed, nil
+	}
+	err = keyring.Delete()
+	if err == ErrKeyringItemNotFound {
+		return removed, nil
+	} else if err != nil {
+		return removed, err
+	}
+	return true, nil
+}
+
+// purge removes the passphrase from the given keyring of the given backend. Unless all is set, it is only
+// removed when it has expired. It returns whether a passphrase was removed. The recorded expiry is only removed
+// once the keyring is known to hold no passphrase anymore.
+func (j *KeyringJanitor) purge(keyring Keyring, backend string, all bool) (bool, error) {
+	if !keyring.IsAvailable() {
+		return false, ErrPassphraseCacheUnavailable(backend)
+	}
+
+	item, err := keyring.Get()
+	if err == ErrKeyringItemNotFound {
+		return false, j.clearExpiry()
+	} else if err != nil && !all {
+		return false, err
+	}
+
+	// An item that cannot be read is removed as well when everything is purged.
+	if !all && !item.IsExpired() {
+		// The passphrase was used again after the expiry was recorded.
+		return false, writePassphraseCacheExpiry(j.path(), passphraseCacheExpiry{
+			Backend:   backend,
+			ExpiresAt: item.ExpiresAt,
+		})
+	}
+
+	err = keyring.Delete()
+	if err == ErrKeyringItemNotFound {
+		return false, j.clearExpiry()
+	} else if err != nil {
+		return false, err
+	}
+	return true, j.clearExpiry()
+}
+
+// path returns the location of the file that records when the cached passphrase expires.
+func (j *KeyringJanitor) path() string {
+	return filepath.Join(j.dir(), passphraseCacheExpiryFileName)
+}
+
+// clearExpiry removes the recorded expiry, after the passphrase has been removed.
+func (j *KeyringJanitor) clearExpiry() error {
+	err := os.Remove(j.path())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// readPassphraseCacheExpiry reads the expiry that is recorded in the file at the given path.
+func readPassphraseCacheExpiry(path string) (*passphraseCacheExpiry, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	expiry := &passphraseCacheExpiry{}
+	err = json.Unmarshal(raw, expiry)
+	if err != nil {
+		return nil, err
+	}
+	return expiry, nil
+}
+
+// writePassphraseCacheExpiry records the expiry in the file at the given path.
+func writePassphraseCacheExpiry(path string, expiry passphraseCacheExpiry) error {
+	raw, err := json.Marshal(expiry)
+	if err != nil {
+		return err
+	}
+
+	err = atomicfile.Write(path, raw, 0600)
+	if err != nil {
+		return ErrCannotWrite(path, err)
+	}
+	return nil
+}
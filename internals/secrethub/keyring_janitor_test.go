@@ -0,0 +1,157 @@
+package secrethub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+// testJanitorKeyring counts how often the keyring is accessed and can be made unavailable.
+type testJanitorKeyring struct {
+	Keyring
+	unavailable bool
+	accessed    int
+}
+
+func (kr *testJanitorKeyring) IsAvailable() bool {
+	kr.accessed++
+	return !kr.unavailable
+}
+
+func newTestKeyringJanitor(dir string, keyring Keyring) *KeyringJanitor {
+	return &KeyringJanitor{
+		dir: func() string { return dir },
+		newKeyring: func(backend string) (Keyring, error) {
+			return keyring, nil
+		},
+		keyring: func() Keyring { return keyring },
+		now:     time.Now,
+	}
+}
+
+func TestKeyringJanitor_purgeExpired(t *testing.T) {
+	cases := map[string]struct {
+		expiry          *time.Time
+		item            *KeyringItem
+		unavailable     bool
+		expectedAccess  bool
+		expectedRemoved bool
+		expectedExpiry  bool
+	}{
+		"nothing cached": {
+			item:           &KeyringItem{ExpiresAt: time.Now().Add(-time.Minute)},
+			expectedAccess: false,
+			expectedExpiry: false,
+		},
+		"not expired": {
+			expiry:         timePtr(time.Now().Add(time.Hour)),
+			item:           &KeyringItem{ExpiresAt: time.Now().Add(time.Hour)},
+			expectedAccess: false,
+			expectedExpiry: true,
+		},
+		"expired": {
+			expiry:          timePtr(time.Now().Add(-time.Minute)),
+			item:            &KeyringItem{ExpiresAt: time.Now().Add(-time.Minute)},
+			expectedAccess:  true,
+			expectedRemoved: true,
+			expectedExpiry:  false,
+		},
+		"used again": {
+			expiry:         timePtr(time.Now().Add(-time.Minute)),
+			item:           &KeyringItem{ExpiresAt: time.Now().Add(time.Hour)},
+			expectedAccess: true,
+			expectedExpiry: true,
+		},
+		"already removed": {
+			expiry:         timePtr(time.Now().Add(-time.Minute)),
+			expectedAccess: true,
+			expectedExpiry: false,
+		},
+		"keyring unavailable": {
+			expiry:         timePtr(time.Now().Add(-time.Minute)),
+			item:           &KeyringItem{ExpiresAt: time.Now().Add(-time.Minute)},
+			unavailable:    true,
+			expectedAccess: true,
+			expectedExpiry: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			keyring := &testJanitorKeyring{Keyring: newTestKeyring(), unavailable: tc.unavailable}
+			if tc.item != nil {
+				assert.OK(t, keyring.Set(tc.item))
+			}
+			janitor := newTestKeyringJanitor(dir, keyring)
+			if tc.expiry != nil {
+				assert.OK(t, NewKeyringCleaner(dir, passphraseCacheBackendKeyring).Cleanup(*tc.expiry))
+			}
+
+			janitor.purgeExpired()
+
+			assert.Equal(t, keyring.accessed > 0, tc.expectedAccess)
+
+			_, err := keyring.Get()
+			assert.Equal(t, err == ErrKeyringItemNotFound, tc.expectedRemoved || tc.item == nil)
+
+			expiry, err := readPassphraseCacheExpiry(janitor.path())
+			assert.Equal(t, err == nil, tc.expectedExpiry)
+			if tc.expectedExpiry && tc.item != nil && !tc.unavailable {
+				assert.Equal(t, expiry.ExpiresAt.Equal(tc.item.ExpiresAt), true)
+			}
+		})
+	}
+}
+
+func TestKeyringJanitor_purgeAll(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	keyring := &testJanitorKeyring{Keyring: newTestKeyring()}
+	janitor := newTestKeyringJanitor(dir, keyring)
+
+	cache := NewPassphraseCache(testTTL, NewKeyringCleaner(dir, passphraseCacheBackendKeyring), keyring)
+	assert.OK(t, cache.Set(password))
+
+	_, err := os.Stat(filepath.Join(dir, passphraseCacheExpiryFileName))
+	assert.OK(t, err)
+
+	removed, err := janitor.purgeAll()
+	assert.OK(t, err)
+	assert.Equal(t, removed, true)
+
+	_, err = keyring.Get()
+	assert.Equal(t, err, ErrKeyringItemNotFound)
+	_, err = os.Stat(janitor.path())
+	assert.Equal(t, os.IsNotExist(err), true)
+
+	removed, err = janitor.purgeAll()
+	assert.OK(t, err)
+	assert.Equal(t, removed, false)
+}
+
+func TestKeyringJanitor_purgeAll_Unavailable(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	keyring := &testJanitorKeyring{Keyring: newTestKeyring(), unavailable: true}
+	janitor := newTestKeyringJanitor(dir, keyring)
+	assert.OK(t, NewKeyringCleaner(dir, passphraseCacheBackendKeyring).Cleanup(time.Now().Add(time.Hour)))
+
+	_, err := janitor.purgeAll()
+	assert.Equal(t, err, ErrPassphraseCacheUnavailable(passphraseCacheBackendKeyring))
+
+	// The expiry is kept, so that the passphrase is removed once the keyring is available again.
+	_, err = os.Stat(janitor.path())
+	assert.OK(t, err)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,67 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// KeyringCommand handles the passphrase that is cached in the OS keyring or another passphrase cache backend.
+type KeyringCommand struct {
+	io      ui.IO
+	janitor *KeyringJanitor
+}
+
+// NewKeyringCommand creates a new KeyringCommand.
+func NewKeyringCommand(io ui.IO, janitor *KeyringJanitor) *KeyringCommand {
+	return &KeyringCommand{
+		io:      io,
+		janitor: janitor,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *KeyringCommand) Register(r cli.Registerer) {
+	clause := r.Command("keyring", "Manage the cached credential passphrase.")
+	clause.HelpLong("The passphrase of your credential is cached for the duration set with --credential-passphrase-cache-ttl, " +
+		"in the backend set with --passphrase-cache-backend. " +
+		"Once it has expired, it is removed the next time the CLI runs.")
+	NewKeyringPurgeCommand(cmd.io, cmd.janitor).Register(clause)
+}
+
+// KeyringPurgeCommand removes the cached passphrase.
+type KeyringPurgeCommand struct {
+	io      ui.IO
+	janitor *KeyringJanitor
+}
+
+// NewKeyringPurgeCommand creates a new KeyringPurgeCommand.
+func NewKeyringPurgeCommand(io ui.IO, janitor *KeyringJanitor) *KeyringPurgeCommand {
+	return &KeyringPurgeCommand{
+		io:      io,
+		janitor: janitor,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *KeyringPurgeCommand) Register(r cli.Registerer) {
+	clause := r.Command("purge", "Remove the cached passphrase, also when it has not yet expired.")
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run removes the cached passphrase.
+func (cmd *KeyringPurgeCommand) Run() error {
+	removed, err := cmd.janitor.purgeAll()
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		fmt.Fprintln(cmd.io.Output(), "No passphrase is cached.")
+		return nil
+	}
+	ui.Successf(cmd.io.Output(), "The cached passphrase has been removed.\n")
+	return nil
+}
@@ -28,7 +28,7 @@ type TestKeyringCleaner struct {
 	cleanupCalled bool
 }
 
-func (c *TestKeyringCleaner) Cleanup() error {
+func (c *TestKeyringCleaner) Cleanup(time.Time) error {
 	c.cleanupCalled = true
 	return nil
 }
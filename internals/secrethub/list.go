@@ -12,6 +12,12 @@ import (
 
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrFilterLabelNotDir = errMain.Code("filter_label_not_dir").Error("--filter-label can only be used to list a directory")
 )
 
 // LsCommand lists a repo, secret or namespace.
@@ -19,15 +25,21 @@ type LsCommand struct {
 	path          api.Path
 	quiet         bool
 	useTimestamps bool
+	fullPaths     bool
+	filterLabel   []string
 	io            ui.IO
 	newClient     newClientFunc
+	terminalWidth func(int) (int, error)
+	picker        *pathPicker
 }
 
 // NewLsCommand creates a new LsCommand.
 func NewLsCommand(io ui.IO, newClient newClientFunc) *LsCommand {
 	return &LsCommand{
-		io:        io,
-		newClient: newClient,
+		io:            io,
+		newClient:     newClient,
+		terminalWidth: getTerminalWidth,
+		picker:        newPathPicker(io, newClient, pathKindDir|pathKindSecret),
 	}
 }
 
@@ -37,10 +49,13 @@ func (cmd *LsCommand) Register(r cli.Registerer) {
 	clause.Alias("list")
 	clause.Flags().BoolVarP(&cmd.quiet, "quiet", "q", false, "Only print paths.")
 	registerTimestampFlag(clause, &cmd.useTimestamps)
+	registerFullPathsFlag(clause, &cmd.fullPaths)
+	registerFilterLabelFlag(clause, &cmd.filterLabel)
+	cmd.picker.register(clause)
 
 	clause.BindAction(cmd.Run)
 	clause.BindArguments([]cli.Argument{
-		{Value: &cmd.path, Name: "path", Required: false, Description: "The path to list contents of."},
+		{Value: &cmd.path, Name: "path", Required: false, Description: "The path to list contents of. When it is left empty in a terminal, a path can be picked interactively. Otherwise, the repositories you have access to are listed."},
 	})
 }
 
@@ -48,7 +63,23 @@ func (cmd *LsCommand) Register(r cli.Registerer) {
 func (cmd *LsCommand) Run() error {
 	timeFormatter := NewTimeFormatter(cmd.useTimestamps)
 
+	filter, err := parseLabelFilter(cmd.filterLabel)
+	if err != nil {
+		return err
+	}
+
+	if cmd.path == "" {
+		path, err := cmd.picker.pick("Which path do you want to list?")
+		if err != nil {
+			return err
+		}
+		cmd.path = api.Path(path)
+	}
+
 	if cmd.path == "" {
+		if len(filter) > 0 {
+			return ErrFilterLabelNotDir
+		}
 		repoLSCommand := NewRepoLSCommand(cmd.io, cmd.newClient)
 		repoLSCommand.quiet = cmd.quiet
 		repoLSCommand.useTimestamps = cmd.useTimestamps
@@ -62,6 +93,10 @@ func (cmd *LsCommand) Run() error {
 
 	// It must be a SecretPath as only SecretPaths has versions.
 	if cmd.path.HasVersion() {
+		if len(filter) > 0 {
+			return ErrFilterLabelNotDir
+		}
+
 		secretPath, err := cmd.path.ToSecretPath()
 		if err != nil {
 			fmt.Println("no secret path!")
@@ -90,7 +125,15 @@ func (cmd *LsCommand) Run() error {
 		} else if err != nil && !api.IsErrNotFound(err) {
 			return err
 		} else if err == nil {
-			err = printDir(cmd.io.Output(), cmd.quiet, dirFS.RootDir, timeFormatter)
+			dir := dirFS.RootDir
+			if len(filter) > 0 {
+				dir, err = filterDirByLabel(client, dirPath, dir, filter)
+				if err != nil {
+					return err
+				}
+			}
+			width := outputTableWidth(cmd.io, cmd.fullPaths, cmd.terminalWidth)
+			err = printDir(cmd.io.Output(), cmd.quiet, dir, timeFormatter, width)
 			if err != nil {
 				return err
 			}
@@ -98,6 +141,10 @@ func (cmd *LsCommand) Run() error {
 		}
 	}
 
+	if len(filter) > 0 {
+		return ErrFilterLabelNotDir
+	}
+
 	// Try SecretPath
 	secretPath, err := cmd.path.ToSecretPath()
 	if err == nil {
@@ -134,6 +181,23 @@ func (cmd *LsCommand) Run() error {
 	return errio.UnexpectedError(errors.New("invalid path argument"))
 }
 
+// filterDirByLabel returns a copy of the directory with only the secrets whose labels match the filter.
+// Subdirectories are left out, as they have no labels.
+func filterDirByLabel(client secrethub.ClientInterface, dirPath api.DirPath, dir *api.Dir, filter labelFilter) (*api.Dir, error) {
+	secrets, err := filterSecretsByLabel(client, dirPath, dir.Secrets, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := *dir
+	filtered.SubDirs = nil
+	filtered.Secrets = make([]*api.Secret, len(secrets))
+	for i, secret := range secrets {
+		filtered.Secrets[i] = secret.Secret
+	}
+	return &filtered, nil
+}
+
 // printVersions prints out secret versions in long or short format.
 func printVersions(w io.Writer, quiet bool, timeFormatter TimeFormatter, versions ...*api.SecretVersion) error {
 	if quiet {
@@ -154,8 +218,9 @@ func printVersions(w io.Writer, quiet bool, timeFormatter TimeFormatter, version
 	return nil
 }
 
-// printDir prints out directory contents in long or short format.
-func printDir(w io.Writer, quiet bool, dir *api.Dir, timeFormatter TimeFormatter) error {
+// printDir prints out directory contents in long or short format. In the long format, names
+// are truncated to fit the given width, unless it is 0.
+func printDir(w io.Writer, quiet bool, dir *api.Dir, timeFormatter TimeFormatter, width int) error {
 	sort.Sort(api.SortDirByName(dir.SubDirs))
 	sort.Sort(api.SortSecretByName(dir.Secrets))
 
@@ -167,13 +232,18 @@ func printDir(w io.Writer, quiet bool, dir *api.Dir, timeFormatter TimeFormatter
 			fmt.Fprintf(w, "%s\n", secret.Name)
 		}
 	} else {
-		tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
-		fmt.Fprintf(tw, "%s\t%s\t%s\n", "NAME", "STATUS", "CREATED")
+		rows := [][]string{{"NAME", "STATUS", "CREATED"}}
 		for _, dir := range dir.SubDirs {
-			fmt.Fprintf(tw, "%s/\t%s\t%s\n", dir.Name, dir.Status, timeFormatter.Format(dir.CreatedAt.Local()))
+			rows = append(rows, []string{dir.Name + "/", string(dir.Status), timeFormatter.Format(dir.CreatedAt.Local())})
 		}
 		for _, secret := range dir.Secrets {
-			fmt.Fprintf(tw, "%s\t%s\t%s\n", secret.Name, secret.Status, timeFormatter.Format(secret.CreatedAt.Local()))
+			rows = append(rows, []string{secret.Name, string(secret.Status), timeFormatter.Format(secret.CreatedAt.Local())})
+		}
+		truncateColumn(rows, 0, width, 2)
+
+		tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+		for _, row := range rows {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", row[0], row[1], row[2])
 		}
 		err := tw.Flush()
 		if err != nil {
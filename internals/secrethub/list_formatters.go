@@ -1,11 +1,15 @@
 package secrethub
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
 
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"golang.org/x/crypto/ssh/terminal"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -65,12 +69,48 @@ func (f *jsonFormatter) Write(values []string) error {
 	return f.encoder.Encode(jsonMap)
 }
 
+// newCSVFormatter returns a list formatter that formats the given table rows as CSV records,
+// preceded by a header record with the given field names.
+func newCSVFormatter(writer io.Writer, fieldNames []string) *csvFormatter {
+	return &csvFormatter{
+		writer: csv.NewWriter(writer),
+		fields: fieldNames,
+	}
+}
+
+type csvFormatter struct {
+	writer        *csv.Writer
+	fields        []string
+	headerPrinted bool
+}
+
+// Write writes the given values as a CSV record. The header record is written on the first call.
+func (f *csvFormatter) Write(values []string) error {
+	if !f.headerPrinted {
+		err := f.writer.Write(f.fields)
+		if err != nil {
+			return err
+		}
+		f.headerPrinted = true
+	}
+
+	err := f.writer.Write(values)
+	if err != nil {
+		return err
+	}
+	f.writer.Flush()
+	return f.writer.Error()
+}
+
 // newTableFormatter returns a list formatter that formats entries in a table.
-func newTableFormatter(writer io.Writer, tableWidth int, columns []tableColumn) *tableFormatter {
+// Unless fullPaths is true, the values of path columns are truncated in the middle
+// instead of being wrapped when they do not fit in their column.
+func newTableFormatter(writer io.Writer, tableWidth int, columns []tableColumn, fullPaths bool) *tableFormatter {
 	return &tableFormatter{
 		writer:     writer,
 		tableWidth: tableWidth,
 		columns:    columns,
+		fullPaths:  fullPaths,
 	}
 }
 
@@ -79,6 +119,7 @@ type tableFormatter struct {
 	writer               io.Writer
 	computedColumnWidths []int
 	columns              []tableColumn
+	fullPaths            bool
 	headerPrinted        bool
 }
 
@@ -107,6 +148,16 @@ func (f *tableFormatter) Write(values []string) error {
 // giving each cell an equal width and wrapping the text in cells that exceed it.
 func (f *tableFormatter) formatRow(row []string) []byte {
 	columnWidths := f.columnWidths()
+	if !f.fullPaths {
+		truncated := make([]string, len(row))
+		for i, cell := range row {
+			truncated[i] = cell
+			if f.columns[i].isPath {
+				truncated[i] = truncateMiddle(cell, columnWidths[i])
+			}
+		}
+		row = truncated
+	}
 	grid := f.fitToColumns(row, columnWidths)
 
 	strRes := strings.Builder{}
@@ -210,3 +261,88 @@ func (f *tableFormatter) columnWidths() []int {
 	f.computedColumnWidths = adjustedWidths
 	return adjustedWidths
 }
+
+const (
+	// truncationMarker replaces the middle of a truncated value.
+	truncationMarker = "..."
+	// minTruncatedWidth is the minimum width to which a column of a tabwriter is truncated,
+	// so that its values remain recognizable.
+	minTruncatedWidth = 16
+)
+
+// truncateMiddle shortens the value to maxWidth characters by replacing its middle with "...".
+// Most of the remaining characters are taken from the end, because the last elements of a path
+// distinguish it the most.
+func truncateMiddle(value string, maxWidth int) string {
+	if maxWidth < len(truncationMarker)+2 {
+		maxWidth = len(truncationMarker) + 2
+	}
+
+	runes := []rune(value)
+	if len(runes) <= maxWidth {
+		return value
+	}
+
+	available := maxWidth - len(truncationMarker)
+	head := available / 3
+	tail := available - head
+	return string(runes[:head]) + truncationMarker + string(runes[len(runes)-tail:])
+}
+
+// truncateColumn truncates the values of the given column in the middle, so that the rows fit
+// in the given width when they are aligned by a tabwriter that adds padding between the columns.
+// A width of 0 or less disables truncation.
+func truncateColumn(rows [][]string, column int, width int, padding int) {
+	if width <= 0 {
+		return
+	}
+
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := len([]rune(cell)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	available := width
+	for i, w := range widths {
+		if i != column {
+			available -= w + padding
+		}
+	}
+
+	if available < minTruncatedWidth {
+		available = minTruncatedWidth
+	}
+
+	for _, row := range rows {
+		if column < len(row) {
+			row[column] = truncateMiddle(row[column], available)
+		}
+	}
+}
+
+// outputTableWidth returns the width available for a table that is printed to the output, or 0 when
+// values should not be truncated, because fullPaths is set, the output is piped or the width
+// of the terminal is unknown.
+func outputTableWidth(io ui.IO, fullPaths bool, terminalWidth func(int) (int, error)) int {
+	if fullPaths || terminalWidth == nil || io.IsOutputPiped() {
+		return 0
+	}
+	width, err := terminalWidth(int(io.Stdout().Fd()))
+	if err != nil {
+		return defaultTerminalWidth
+	}
+	return width
+}
+
+// getTerminalWidth returns the width of the terminal with the given file descriptor.
+func getTerminalWidth(fd int) (int, error) {
+	w, _, err := terminal.GetSize(fd)
+	return w, err
+}
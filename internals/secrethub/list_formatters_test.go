@@ -128,3 +128,48 @@ func Test_columnFormatter_formatRow(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncateMiddle(t *testing.T) {
+	cases := map[string]struct {
+		value    string
+		maxWidth int
+		expected string
+	}{
+		"fits": {
+			value:    "namespace/repo/secret",
+			maxWidth: 21,
+			expected: "namespace/repo/secret",
+		},
+		"truncated": {
+			value:    "namespace/repo/dir/subdir/secret",
+			maxWidth: 18,
+			expected: "names...dir/secret",
+		},
+		"minimum width": {
+			value:    "namespace/repo/secret",
+			maxWidth: 1,
+			expected: "...et",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := truncateMiddle(tc.value, tc.maxWidth)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestTruncateColumn(t *testing.T) {
+	rows := [][]string{
+		{"PATH", "PERMISSION"},
+		{"namespace/repo/dir/subdir/subsubdir", "read"},
+	}
+
+	truncateColumn(rows, 0, 30, 2)
+
+	assert.Equal(t, rows, [][]string{
+		{"PATH", "PERMISSION"},
+		{"names.../subsubdir", "read"},
+	})
+}
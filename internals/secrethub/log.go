@@ -0,0 +1,47 @@
+package secrethub
+
+import (
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/spf13/cobra"
+)
+
+// RegisterLogFlags registers the flags that configure which messages are logged and how.
+// Messages are logged to stderr, so they do not mix with the output of commands.
+func RegisterLogFlags(app *cli.App) {
+	var level, format string
+	var quiet, verbose, debug bool
+	app.PersistentFlags().StringVar(&level, "log-level", cli.LogLevelWarning, "The minimum level of the messages that are logged. Options are "+strings.Join(cli.LogLevels, ", ")+".")
+	app.PersistentFlags().StringVar(&format, "log-format", cli.LogFormatText, "The format in which messages are logged. Options are "+strings.Join(cli.LogFormats, ", ")+". With json, every message is a JSON object on a single line with a time, level and message.")
+	app.PersistentFlags().BoolVar(&quiet, "quiet", false, "Only log errors. This is an alias of --log-level=error.")
+	app.PersistentFlags().BoolVar(&verbose, "verbose", false, "Also log informative messages. This is an alias of --log-level=info.")
+	app.PersistentFlags().BoolVarP(&debug, "debug", "D", false, "Enable debug mode. This is an alias of --log-level=debug.")
+	app.Root.AddPersistentPreRunE(func(command *cobra.Command, _ []string) error {
+		err := cli.SetLogFormat(format)
+		if err != nil {
+			return err
+		}
+
+		// --quiet, --verbose and --debug are aliases of a log level, so they conflict with each other
+		// and with --log-level, unless they set the same level.
+		levels := map[string]bool{}
+		if command.Flags().Changed("log-level") {
+			levels[level] = true
+		}
+		for alias, isSet := range map[string]bool{cli.LogLevelError: quiet, cli.LogLevelInfo: verbose, cli.LogLevelDebug: debug} {
+			if isSet {
+				levels[alias] = true
+				level = alias
+			}
+		}
+		if len(levels) > 1 {
+			return ErrFlagsConflict("--quiet, --verbose, --debug and --log-level")
+		}
+
+		return cli.SetLogLevel(level)
+	})
+}
+
+// logger is used to log messages of the commands that are not part of their output.
+var logger = cli.NewLogger()
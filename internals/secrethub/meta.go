@@ -0,0 +1,35 @@
+package secrethub
+
+import (
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// MetaCommand handles operations on the labels of secrets.
+type MetaCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewMetaCommand creates a new MetaCommand.
+func NewMetaCommand(io ui.IO, newClient newClientFunc) *MetaCommand {
+	return &MetaCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *MetaCommand) Register(r cli.Registerer) {
+	clause := r.Command("meta", "Manage the labels of secrets.")
+	clause.HelpLong("Labels are key=value pairs that describe a secret, e.g. who owns it or how it is rotated. " +
+		"Any key can be used, but the following keys have a conventional meaning: " + strings.Join(wellKnownLabels, ", ") + ".\n\n" +
+		"The labels of a secret are stored as JSON in a secret next to it, with " + secretMetaSuffix + " appended to its name. " +
+		"So, they are encrypted and versioned like any other secret and everyone with access to the directory can read them. " +
+		"Secrets in a directory can be filtered by their labels with `secrethub ls --filter-label` or `secrethub meta ls --filter-label`.")
+	NewMetaSetCommand(cmd.io, cmd.newClient).Register(clause)
+	NewMetaGetCommand(cmd.io, cmd.newClient).Register(clause)
+	NewMetaLsCommand(cmd.io, cmd.newClient).Register(clause)
+}
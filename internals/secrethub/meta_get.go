@@ -0,0 +1,70 @@
+package secrethub
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// MetaGetCommand prints the labels of a secret.
+type MetaGetCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+	path      api.SecretPath
+	key       cli.StringValue
+}
+
+// NewMetaGetCommand creates a new MetaGetCommand.
+func NewMetaGetCommand(io ui.IO, newClient newClientFunc) *MetaGetCommand {
+	return &MetaGetCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *MetaGetCommand) Register(r cli.Registerer) {
+	clause := r.Command("get", "Print the labels of a secret.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.path, Name: "path", Required: true, Placeholder: secretPathPlaceHolder, Description: "The path to the secret."},
+		{Value: &cmd.key, Name: "key", Required: false, Description: "Only print the value of the label with this key."},
+	})
+}
+
+// Run prints the labels of the secret, one key=value pair per line, or the value of a single label.
+func (cmd *MetaGetCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	labels, err := readSecretLabels(client, cmd.path)
+	if err != nil {
+		return err
+	}
+
+	if cmd.key.Value != "" {
+		value, ok := labels[cmd.key.Value]
+		if !ok {
+			return ErrLabelNotSet(cmd.key.Value, cmd.path)
+		}
+		fmt.Fprintln(cmd.io.Output(), value)
+		return nil
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(cmd.io.Output(), "%s=%s\n", key, labels[key])
+	}
+	return nil
+}
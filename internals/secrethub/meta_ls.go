@@ -0,0 +1,67 @@
+package secrethub
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// MetaLsCommand lists the labels of the secrets in a directory.
+type MetaLsCommand struct {
+	io          ui.IO
+	newClient   newClientFunc
+	path        api.DirPath
+	filterLabel []string
+}
+
+// NewMetaLsCommand creates a new MetaLsCommand.
+func NewMetaLsCommand(io ui.IO, newClient newClientFunc) *MetaLsCommand {
+	return &MetaLsCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *MetaLsCommand) Register(r cli.Registerer) {
+	clause := r.Command("ls", "List the labels of the secrets in a directory.")
+	clause.Alias("list")
+	registerFilterLabelFlag(clause, &cmd.filterLabel)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{{Value: &cmd.path, Name: "dir-path", Required: true, Placeholder: optionalDirPathPlaceHolder, Description: "The path to the directory."}})
+}
+
+// Run lists the secrets directly in the directory with their labels.
+func (cmd *MetaLsCommand) Run() error {
+	filter, err := parseLabelFilter(cmd.filterLabel)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	tree, err := client.Dirs().GetTree(cmd.path.Value(), 1, false)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := filterSecretsByLabel(client, cmd.path, tree.RootDir.Secrets, filter)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\n", "NAME", "LABELS")
+	for _, secret := range secrets {
+		fmt.Fprintf(w, "%s\t%s\n", secret.Name, formatLabels(secret.labels))
+	}
+	return w.Flush()
+}
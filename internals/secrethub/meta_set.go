@@ -0,0 +1,91 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// MetaSetCommand sets the labels of a secret.
+type MetaSetCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+	args      cli.StringListValue
+}
+
+// NewMetaSetCommand creates a new MetaSetCommand.
+func NewMetaSetCommand(io ui.IO, newClient newClientFunc) *MetaSetCommand {
+	return &MetaSetCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *MetaSetCommand) Register(r cli.Registerer) {
+	clause := r.Command("set", "Set labels on a secret.")
+	clause.HelpLong("Set one or more labels on a secret, given as key=value after the path of the secret. " +
+		"Other labels of the secret are left as they are. A label is removed by giving it an empty value, e.g. ticket=.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArgumentsArr(cli.Argument{Value: &cmd.args, Name: "path", Required: true, Placeholder: secretPathPlaceHolder + " <key>=<value>...", Description: "The path to the secret, followed by the labels to set."})
+}
+
+// Run sets the labels on the secret.
+func (cmd *MetaSetCommand) Run() error {
+	if len(cmd.args) < 2 {
+		return ErrNoLabels
+	}
+
+	path, err := api.NewSecretPath(cmd.args[0])
+	if err != nil {
+		return err
+	}
+	if isSecretMetaName(path.Value()) {
+		return ErrMetaOfMetaSecret(path)
+	}
+
+	updates := make(map[string]string, len(cmd.args)-1)
+	for _, raw := range cmd.args[1:] {
+		key, value, err := parseLabel(raw)
+		if err != nil {
+			return err
+		}
+		updates[key] = value
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Secrets().Get(path.Value())
+	if api.IsErrNotFound(err) {
+		return ErrResourceNotFound(path)
+	} else if err != nil {
+		return err
+	}
+
+	labels, err := readSecretLabels(client, path)
+	if err != nil {
+		return err
+	}
+	for key, value := range updates {
+		if value == "" {
+			delete(labels, key)
+			continue
+		}
+		labels[key] = value
+	}
+
+	err = writeSecretLabels(client, path, labels)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "The labels of %s have been set.\n", path)
+	return nil
+}
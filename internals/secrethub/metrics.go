@@ -0,0 +1,240 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+
+	"github.com/spf13/cobra"
+)
+
+// metricsReport is the content of the metrics file.
+type metricsReport struct {
+	Command         string  `json:"command"`
+	Status          string  `json:"status"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	APICalls        int64   `json:"api_calls"`
+	BytesSent       int64   `json:"bytes_sent"`
+	BytesReceived   int64   `json:"bytes_received"`
+	Retries         int64   `json:"retries"`
+	SecretsResolved int64   `json:"secrets_resolved"`
+}
+
+// Metrics measures the overhead of a single invocation of the CLI and writes it
+// to a metrics file, so that it can be tracked in CI pipelines.
+type Metrics struct {
+	path    string
+	now     func() time.Time
+	start   time.Time
+	command string
+
+	apiCalls        int64
+	bytesSent       int64
+	bytesReceived   int64
+	retries         int64
+	secretsResolved int64
+
+	mutex sync.Mutex
+	// failed contains the requests that failed, so that repeating them can be counted as a retry.
+	failed map[string]bool
+}
+
+// NewMetrics creates a new Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		now:    time.Now,
+		failed: make(map[string]bool),
+	}
+}
+
+// Register registers the flag for the metrics file and starts measuring before a command is executed.
+func (m *Metrics) Register(app *cli.App) {
+	app.PersistentFlags().StringVar(&m.path, "metrics-file", "", "Write metrics of the command, such as its duration and the number of API calls, as JSON to this file.")
+	app.Root.AddPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+		m.start = m.now()
+		m.command = strings.TrimPrefix(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()), " ")
+		return nil
+	})
+}
+
+// enabled returns whether a metrics file should be written.
+func (m *Metrics) enabled() bool {
+	return m != nil && m.path != ""
+}
+
+// Write writes the metrics of the executed command with its result to the metrics file.
+// It does nothing when no metrics file is configured or no command has been executed.
+func (m *Metrics) Write(commandErr error) error {
+	if !m.enabled() || m.start.IsZero() {
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(m.report(commandErr), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(m.path, raw, 0644)
+	if err != nil {
+		return ErrCannotWrite(m.path, err)
+	}
+	return nil
+}
+
+// report returns the metrics of the executed command.
+func (m *Metrics) report(commandErr error) metricsReport {
+	status := journalStatusSuccess
+	if commandErr != nil {
+		status = journalStatusFailure
+	}
+
+	return metricsReport{
+		Command:         m.command,
+		Status:          status,
+		DurationSeconds: m.now().Sub(m.start).Seconds(),
+		APICalls:        atomic.LoadInt64(&m.apiCalls),
+		BytesSent:       atomic.LoadInt64(&m.bytesSent),
+		BytesReceived:   atomic.LoadInt64(&m.bytesReceived),
+		Retries:         atomic.LoadInt64(&m.retries),
+		SecretsResolved: atomic.LoadInt64(&m.secretsResolved),
+	}
+}
+
+// addSecretsResolved counts secrets of which the value was fetched.
+func (m *Metrics) addSecretsResolved(n int) {
+	atomic.AddInt64(&m.secretsResolved, int64(n))
+}
+
+// Transport returns a RoundTripper that counts the requests made through the given RoundTripper.
+func (m *Metrics) Transport(base http.RoundTripper) http.RoundTripper {
+	return &metricsTransport{
+		base:    base,
+		metrics: m,
+	}
+}
+
+// metricsTransport counts the API calls, the bytes in the bodies of requests and responses and the retries.
+// A request is counted as a retry when the same request failed before, with a network error or a server error.
+type metricsTransport struct {
+	base    http.RoundTripper
+	metrics *Metrics
+}
+
+// RoundTrip executes the request and records it in the metrics.
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	m := t.metrics
+	key := req.Method + " " + req.URL.String()
+
+	atomic.AddInt64(&m.apiCalls, 1)
+	if req.ContentLength > 0 {
+		atomic.AddInt64(&m.bytesSent, req.ContentLength)
+	}
+
+	m.mutex.Lock()
+	if m.failed[key] {
+		atomic.AddInt64(&m.retries, 1)
+	}
+	m.mutex.Unlock()
+
+	resp, err := t.base.RoundTrip(req)
+
+	m.mutex.Lock()
+	m.failed[key] = err != nil || resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	m.mutex.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &countingReadCloser{ReadCloser: resp.Body, n: &m.bytesReceived}
+	return resp, nil
+}
+
+// countingReadCloser adds the number of bytes read to a counter.
+type countingReadCloser struct {
+	io.ReadCloser
+	n *int64
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	atomic.AddInt64(r.n, int64(n))
+	return n, err
+}
+
+// metricsClient is a client that counts the secrets of which the value is fetched.
+type metricsClient struct {
+	secrethub.ClientInterface
+	metrics *Metrics
+}
+
+// Secrets returns a SecretService that counts the secrets of which the value is fetched.
+func (c *metricsClient) Secrets() secrethub.SecretService {
+	return &metricsSecretService{
+		SecretService: c.ClientInterface.Secrets(),
+		metrics:       c.metrics,
+	}
+}
+
+type metricsSecretService struct {
+	secrethub.SecretService
+	metrics *Metrics
+}
+
+// Read reads a secret and counts it.
+func (s *metricsSecretService) Read(path string) (*api.SecretVersion, error) {
+	version, err := s.SecretService.Read(path)
+	if err == nil {
+		s.metrics.addSecretsResolved(1)
+	}
+	return version, err
+}
+
+// ReadString reads a secret and counts it.
+func (s *metricsSecretService) ReadString(path string) (string, error) {
+	value, err := s.SecretService.ReadString(path)
+	if err == nil {
+		s.metrics.addSecretsResolved(1)
+	}
+	return value, err
+}
+
+// Versions returns a SecretVersionService that counts the versions of which the value is fetched.
+func (s *metricsSecretService) Versions() secrethub.SecretVersionService {
+	return &metricsSecretVersionService{
+		SecretVersionService: s.SecretService.Versions(),
+		metrics:              s.metrics,
+	}
+}
+
+type metricsSecretVersionService struct {
+	secrethub.SecretVersionService
+	metrics *Metrics
+}
+
+// GetWithData gets a secret version with its value and counts it.
+func (s *metricsSecretVersionService) GetWithData(path string) (*api.SecretVersion, error) {
+	version, err := s.SecretVersionService.GetWithData(path)
+	if err == nil {
+		s.metrics.addSecretsResolved(1)
+	}
+	return version, err
+}
+
+// ListWithData lists the versions of a secret with their values and counts them.
+func (s *metricsSecretVersionService) ListWithData(path string) ([]*api.SecretVersion, error) {
+	versions, err := s.SecretVersionService.ListWithData(path)
+	if err == nil {
+		s.metrics.addSecretsResolved(len(versions))
+	}
+	return versions, err
+}
@@ -0,0 +1,143 @@
+package secrethub
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+type fakeRoundTripper func(req *http.Request) (*http.Response, error)
+
+func (f fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestMetricsTransport(t *testing.T) {
+	errNetwork := errors.New("connection reset")
+
+	cases := map[string]struct {
+		responses     []int
+		expectedCalls int64
+		expectedRetry int64
+	}{
+		"single request": {
+			responses:     []int{http.StatusOK},
+			expectedCalls: 1,
+		},
+		"retry after server error": {
+			responses:     []int{http.StatusBadGateway, http.StatusOK},
+			expectedCalls: 2,
+			expectedRetry: 1,
+		},
+		"retry after too many requests": {
+			responses:     []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			expectedCalls: 3,
+			expectedRetry: 2,
+		},
+		"retry after network error": {
+			responses:     []int{0, http.StatusOK},
+			expectedCalls: 2,
+			expectedRetry: 1,
+		},
+		"repeat after success is no retry": {
+			responses:     []int{http.StatusOK, http.StatusOK},
+			expectedCalls: 2,
+		},
+		"repeat after client error is no retry": {
+			responses:     []int{http.StatusNotFound, http.StatusNotFound},
+			expectedCalls: 2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			metrics := NewMetrics()
+
+			i := 0
+			transport := metrics.Transport(fakeRoundTripper(func(req *http.Request) (*http.Response, error) {
+				status := tc.responses[i]
+				i++
+				if status == 0 {
+					return nil, errNetwork
+				}
+				return &http.Response{
+					StatusCode: status,
+					Body:       io.NopCloser(strings.NewReader("response")),
+				}, nil
+			}))
+
+			for range tc.responses {
+				req, err := http.NewRequest("POST", "https://api.secrethub.io/repos/dev1/example", strings.NewReader("body"))
+				assert.OK(t, err)
+
+				resp, err := transport.RoundTrip(req)
+				if err == errNetwork {
+					continue
+				}
+				assert.OK(t, err)
+				_, err = io.ReadAll(resp.Body)
+				assert.OK(t, err)
+			}
+
+			successes := int64(0)
+			for _, status := range tc.responses {
+				if status != 0 {
+					successes++
+				}
+			}
+
+			report := metrics.report(nil)
+			assert.Equal(t, report.APICalls, tc.expectedCalls)
+			assert.Equal(t, report.Retries, tc.expectedRetry)
+			assert.Equal(t, report.BytesSent, 4*tc.expectedCalls)
+			assert.Equal(t, report.BytesReceived, 8*successes)
+		})
+	}
+}
+
+func TestMetricsReport(t *testing.T) {
+	start := time.Date(2018, time.July, 30, 10, 49, 18, 0, time.UTC)
+
+	cases := map[string]struct {
+		err      error
+		expected metricsReport
+	}{
+		"success": {
+			expected: metricsReport{
+				Command:         "read",
+				Status:          journalStatusSuccess,
+				DurationSeconds: 1.5,
+				SecretsResolved: 3,
+			},
+		},
+		"failure": {
+			err: errors.New("failed"),
+			expected: metricsReport{
+				Command:         "read",
+				Status:          journalStatusFailure,
+				DurationSeconds: 1.5,
+				SecretsResolved: 3,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			metrics := NewMetrics()
+			metrics.command = "read"
+			metrics.start = start
+			metrics.now = func() time.Time {
+				return start.Add(1500 * time.Millisecond)
+			}
+			metrics.addSecretsResolved(1)
+			metrics.addSecretsResolved(2)
+
+			assert.Equal(t, metrics.report(tc.err), tc.expected)
+		})
+	}
+}
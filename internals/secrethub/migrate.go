@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
@@ -37,11 +38,23 @@ type plan struct {
 	vaults         map[string]*vault
 }
 
+// sortedVaults returns the vaults in the plan, sorted by name.
+func (p *plan) sortedVaults() []*vault {
+	vaults := make([]*vault, 0, len(p.vaults))
+	for _, vault := range p.vaults {
+		vaults = append(vaults, vault)
+	}
+	sort.Slice(vaults, func(i, j int) bool {
+		return vaults[i].Name < vaults[j].Name
+	})
+	return vaults
+}
+
 type referenceMapping map[string]string
 
 func newReferenceMapping(p *plan) referenceMapping {
 	index := make(map[string]string)
-	for _, vault := range p.vaults {
+	for _, vault := range p.sortedVaults() {
 		for _, item := range vault.Items {
 			for _, field := range item.Fields {
 				opPath := fmt.Sprintf("op://%s/%s/%s", vault.Name, item.Name, field.Name)
@@ -54,8 +67,15 @@ func newReferenceMapping(p *plan) referenceMapping {
 
 // addVarPossibilities adds variations to the index for all values in the passed in vars map
 func (m referenceMapping) addVarPossibilities(vars map[string][]string) error {
+	varnames := make([]string, 0, len(vars))
+	for varname := range vars {
+		varnames = append(varnames, varname)
+	}
+	sort.Strings(varnames)
+
 	exists := make(map[string]string)
-	for varname, possibleValues := range vars {
+	for _, varname := range varnames {
+		possibleValues := vars[varname]
 		varname = strings.ToUpper(varname)
 		for _, value := range possibleValues {
 			if otherVarname := exists[value]; otherVarname != "" && otherVarname != varname {
@@ -114,12 +134,50 @@ func (v vault) Validate() error {
 	return nil
 }
 
+// Categories of 1Password items that secrets can be migrated to.
+const (
+	itemCategoryAPICredential = "apicredential"
+	itemCategoryLogin         = "login"
+	itemCategoryDatabase      = "database"
+	itemCategoryServer        = "server"
+	itemCategorySecureNote    = "securenote"
+)
+
+var itemCategories = []string{
+	itemCategoryAPICredential,
+	itemCategoryLogin,
+	itemCategoryDatabase,
+	itemCategoryServer,
+	itemCategorySecureNote,
+}
+
 type item struct {
-	Name   string `yaml:"item-name"`
-	Fields []field
+	Name     string `yaml:"item-name"`
+	Category string `yaml:"category,omitempty"`
+	Fields   []field
+}
+
+// category returns the category the item is created with, which defaults to API Credential.
+func (i item) category() string {
+	if i.Category == "" {
+		return itemCategoryAPICredential
+	}
+	return i.Category
 }
 
 func (i item) Validate() error {
+	if i.Category != "" {
+		valid := false
+		for _, category := range itemCategories {
+			if i.Category == category {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("category: '%s' is not supported, choose one of: %s", i.Category, strings.Join(itemCategories, ", "))
+		}
+	}
 	for _, field := range i.Fields {
 		err := field.Validate()
 		if err != nil {
@@ -170,11 +228,12 @@ func (p *plan) addVault(tree *api.Tree, dirID uuid.UUID) (string, error) {
 	return vaultName, nil
 }
 
-func (p *plan) addItem(vaultName, name string, fields []field) {
+func (p *plan) addItem(vaultName, name, category string, fields []field) {
 	vault := p.vaults[vaultName]
 	vault.Items = append(vault.Items, item{
-		Name:   name,
-		Fields: fields,
+		Name:     name,
+		Category: category,
+		Fields:   fields,
 	})
 }
 
@@ -186,13 +245,7 @@ type planYML struct {
 func (p *plan) MarshalYAML() (interface{}, error) {
 	res := planYML{
 		SignInAddress: p.SignInAddress,
-		Vaults:        make([]*vault, len(p.vaults)),
-	}
-
-	i := 0
-	for _, vault := range p.vaults {
-		res.Vaults[i] = vault
-		i++
+		Vaults:        p.sortedVaults(),
 	}
 
 	return res, nil
@@ -216,7 +269,7 @@ func (p *plan) UnmarshalYAML(unmarshal func(interface{}) error) error {
 }
 
 func (p *plan) Validate() error {
-	for _, vault := range p.vaults {
+	for _, vault := range p.sortedVaults() {
 		err := vault.Validate()
 		if err != nil {
 			return fmt.Errorf("vault '%s': %s", vault.Name, err)
@@ -275,7 +328,7 @@ func (cmd *MigratePlanCommand) Run() error {
 					return err
 				}
 				if orgMember.Role != api.OrgRoleAdmin {
-					fmt.Fprintf(os.Stderr, "WARN: You are not an admin on %s. There may be repositories you do not have access to. Ask an admin to verify all secrets are included in the migration.\n", path)
+					logger.Warningf("You are not an admin on %s. There may be repositories you do not have access to. Ask an admin to verify all secrets are included in the migration.", path)
 				}
 			}
 
@@ -327,7 +380,6 @@ func (cmd *MigratePlanCommand) addDirToPlan(client secrethub.ClientInterface, pa
 
 	tree, err := client.Dirs().GetTree(path, -1, false)
 	if err == api.ErrForbidden || api.IsErrNotFound(err) {
-		fmt.Fprintf(os.Stderr, "WARN: Skipping '%s' because you do not have read access. ", path)
 		accessLevels, err := client.AccessRules().ListLevels(path)
 		if err == nil {
 			var usernames []string
@@ -336,9 +388,9 @@ func (cmd *MigratePlanCommand) addDirToPlan(client secrethub.ClientInterface, pa
 					usernames = append(usernames, level.Account.Name.String())
 				}
 			}
-			fmt.Fprintf(os.Stderr, "Ask any of the following users to migrate the skipped secrets: %s.\n", strings.Join(usernames, ", "))
+			logger.Warningf("Skipping '%s' because you do not have read access. Ask any of the following users to migrate the skipped secrets: %s.", path, strings.Join(usernames, ", "))
 		} else {
-			fmt.Fprint(os.Stderr, "Ask an admin to migrate the skipped secrets.\n")
+			logger.Warningf("Skipping '%s' because you do not have read access. Ask an admin to migrate the skipped secrets.", path)
 		}
 		return nil
 	}
@@ -377,7 +429,7 @@ func addTreeToPlan(tree *api.Tree, plan *plan) error {
 					Concealed: shouldBeConcealed(secretpath.Base(secretPath.Value())),
 				}
 			}
-			plan.addItem(vault, dir.Name, fields)
+			plan.addItem(vault, dir.Name, itemCategoryForFields(dir.Name, fields), fields)
 		} else {
 			vault, err := plan.addVault(tree, dir.DirID)
 			if err != nil {
@@ -388,7 +440,7 @@ func addTreeToPlan(tree *api.Tree, plan *plan) error {
 				if err != nil {
 					return err
 				}
-				plan.addItem(vault, secret.Name, []field{{Name: "secret", Reference: secretReferencePrefix + secretPath.Value(), Concealed: true}})
+				plan.addItem(vault, secret.Name, itemCategoryForSecret(secret.Name), []field{{Name: "secret", Reference: secretReferencePrefix + secretPath.Value(), Concealed: true}})
 			}
 		}
 
@@ -411,6 +463,62 @@ func shouldBeConcealed(secretName string) bool {
 	return true
 }
 
+// itemCategoryForFields returns the category of the item with the given name and fields,
+// based on the names of the fields. Items that look like the credentials of a database are
+// migrated to a Database item, those with a host to a Server item and those with a username
+// and password to a Login item. All other items are migrated to an API Credential item.
+func itemCategoryForFields(itemName string, fields []field) string {
+	hasName := func(names ...string) bool {
+		for _, field := range fields {
+			if nameMatches(field.Name, names...) {
+				return true
+			}
+		}
+		return false
+	}
+
+	hasUser := hasName("user", "username", "login", "email")
+	hasPassword := hasName("password", "pass", "passphrase")
+	hasHost := hasName("host", "hostname", "server")
+	hasDatabase := hasName("database", "db", "db-name", "dbname", "database-name", "schema")
+	isDatabase := nameMatches(itemName, "db", "database", "postgres", "postgresql", "mysql", "mariadb", "mongo", "mongodb", "redis", "mssql") ||
+		strings.HasSuffix(strings.ToLower(itemName), "-db")
+
+	switch {
+	case hasDatabase || (isDatabase && (hasUser || hasPassword)):
+		return itemCategoryDatabase
+	case hasHost && (hasUser || hasPassword):
+		return itemCategoryServer
+	case hasUser && hasPassword:
+		return itemCategoryLogin
+	default:
+		return itemCategoryAPICredential
+	}
+}
+
+// itemCategoryForSecret returns the category of the item that a single secret is migrated to.
+// Secrets that look like files are migrated to a Secure Note, others to an API Credential.
+func itemCategoryForSecret(secretName string) string {
+	lower := strings.ToLower(secretName)
+	for _, ext := range []string{".pem", ".key", ".crt", ".json", ".yml", ".yaml", ".conf", ".ini", ".env", ".txt"} {
+		if strings.HasSuffix(lower, ext) {
+			return itemCategorySecureNote
+		}
+	}
+	return itemCategoryAPICredential
+}
+
+// nameMatches returns whether the name of a secret equals one of the given names,
+// ignoring case and treating underscores as dashes.
+func nameMatches(name string, names ...string) bool {
+	for _, n := range names {
+		if strings.EqualFold(strings.ReplaceAll(name, "_", "-"), n) {
+			return true
+		}
+	}
+	return false
+}
+
 // isSecretItem returns whether the directory itself should be interpreted as a secret item,
 // rather than the secrets that are in the directory.
 func isSecretItem(dir *api.Dir) bool {
@@ -489,6 +597,7 @@ func (c vaultCreation) Print(w io.Writer) {
 type itemCreation struct {
 	vault        string
 	item         string
+	category     string
 	itemTemplate onepassword.ItemTemplate
 	opClient     onepassword.OPCLI
 }
@@ -498,11 +607,11 @@ func (c itemCreation) Vault() string {
 }
 
 func (c itemCreation) Apply() error {
-	return c.opClient.CreateItem(c.vault, c.itemTemplate, c.item)
+	return c.opClient.CreateItem(c.vault, c.category, c.itemTemplate, c.item)
 }
 
 func (c itemCreation) Print(w io.Writer) {
-	fmt.Fprintf(w, "Create item '%s'\n", c.item)
+	fmt.Fprintf(w, "Create %s item '%s'\n", c.category, c.item)
 }
 
 type itemUpdate struct {
@@ -516,9 +625,19 @@ func (c itemUpdate) Vault() string {
 	return c.vault
 }
 
+// sortedFields returns the names of the fields to update in alphabetical order.
+func (c itemUpdate) sortedFields() []string {
+	fields := make([]string, 0, len(c.fieldValues))
+	for field := range c.fieldValues {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
 func (c itemUpdate) Apply() error {
-	for field, value := range c.fieldValues {
-		err := c.opClient.SetField(c.vault, c.item, field, value)
+	for _, field := range c.sortedFields() {
+		err := c.opClient.SetField(c.vault, c.item, field, c.fieldValues[field])
 		if err != nil {
 			return err
 		}
@@ -528,7 +647,7 @@ func (c itemUpdate) Apply() error {
 
 func (c itemUpdate) Print(w io.Writer) {
 	fmt.Fprintf(w, "Update item '%s' fields:\n", c.item)
-	for field := range c.fieldValues {
+	for _, field := range c.sortedFields() {
 		fmt.Fprintf(w, "  '%s'\n", field)
 	}
 }
@@ -572,9 +691,17 @@ func (cmd *MigrateApplyCommand) Run() error {
 
 	var changes []change
 
-	i := 1
+	itemCount := 0
 	for _, vault := range plan.vaults {
-		fmt.Fprintf(cmd.io.Output(), "[%d/%d] Checking vault: %s\n", i, len(plan.vaults), vault.Name)
+		itemCount += len(vault.Items)
+	}
+	progress := ui.NewProgress(cmd.io, "Checking items", itemCount)
+	progress.Start()
+	defer progress.Stop()
+
+	i := 1
+	for _, vault := range plan.sortedVaults() {
+		progress.Printf("[%d/%d] Checking vault: %s\n", i, len(plan.vaults), vault.Name)
 		vaultExists, err := opClient.ExistsVault(vault.Name)
 		if err != nil {
 			return fmt.Errorf("could not check vault existence: %s", err)
@@ -588,6 +715,7 @@ func (cmd *MigrateApplyCommand) Run() error {
 		}
 
 		for _, item := range vault.Items {
+			progress.Add(1)
 			itemExists := false
 			if vaultExists {
 				itemExists, err = opClient.ExistsItemInVault(vault.Name, item.Name)
@@ -609,6 +737,7 @@ func (cmd *MigrateApplyCommand) Run() error {
 				changes = append(changes, itemCreation{
 					vault:        vault.Name,
 					item:         item.Name,
+					category:     item.category(),
 					itemTemplate: template,
 					opClient:     opClient,
 				})
@@ -622,7 +751,7 @@ func (cmd *MigrateApplyCommand) Run() error {
 				for _, field := range item.Fields {
 					opValue, hasField := opFields[field.Name]
 					if !hasField {
-						fmt.Fprintf(os.Stderr, "item %s.%s has missing field %s, please add this field manually to allow the migration tool to update it\n", vault.Name, item.Name, field.Name)
+						logger.Warningf("item %s.%s has missing field %s, please add this field manually to allow the migration tool to update it", vault.Name, item.Name, field.Name)
 						warningCount++
 						skipCount++
 						continue
@@ -649,6 +778,7 @@ func (cmd *MigrateApplyCommand) Run() error {
 		}
 		i++
 	}
+	progress.Stop()
 
 	fmt.Fprintln(cmd.io.Output())
 	if len(changes) == 0 {
@@ -697,13 +827,18 @@ func (cmd *MigrateApplyCommand) Run() error {
 
 	fmt.Fprintln(cmd.io.Output())
 	fmt.Fprintf(cmd.io.Output(), "Applying changes:\n")
+	progress = ui.NewProgress(cmd.io, "Applying changes", len(changes))
+	progress.Start()
+	defer progress.Stop()
 	for i, change := range changes {
-		fmt.Fprintf(cmd.io.Output(), "[%d/%d]\n", i+1, len(changes))
+		progress.Printf("[%d/%d]\n", i+1, len(changes))
 		err := change.Apply()
 		if err != nil {
 			return err
 		}
+		progress.Add(1)
 	}
+	progress.Stop()
 	fmt.Fprintln(cmd.io.Output(), "\n"+
 		"Migration completed successfully.\n"+
 		"Your secrets are now available via 1Password.\n"+
@@ -784,6 +919,9 @@ func (cmd *MigratePlanCommand) Register(r cli.Registerer) {
 	clause.HelpLong("Generate a YAML file to specify which 1Password vaults and items will be used to store your secrets." +
 		" You can review and edit this plan, then apply it with `secrethub migrate apply`.\n" +
 		"\n" +
+		"Every item gets a category based on the names of its fields: " + strings.Join(itemCategories, ", ") + "." +
+		" The category can be changed in the plan before the item is created.\n" +
+		"\n" +
 		"Check out https://secrethub.io/docs/1password/migration/ for detailed instructions.")
 
 	clause.Flags().StringVar(&cmd.outFile, "out-file", defaultPlanPath, "The path where to write the YAML file.")
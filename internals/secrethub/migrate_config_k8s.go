@@ -19,7 +19,7 @@ func (cmd *MigrateConfigK8sCommand) Run() error {
 	k8sSpecs := make([]itemK8sSpec, 0)
 
 	if len(cmd.vaults) == 0 {
-		for _, vault := range plan.vaults {
+		for _, vault := range plan.sortedVaults() {
 			for _, item := range vault.Items {
 				k8sSpecs = append(k8sSpecs, itemK8sSpec{
 					vaultName: vault.Name,
@@ -72,7 +72,7 @@ func (cmd *MigrateConfigK8sCommand) Run() error {
 		}
 	}
 
-	fmt.Fprintln(cmd.io.Output(), "Successfully generated secrets.yml file.")
+	ui.Successf(cmd.io.Output(), "Successfully generated secrets.yml file.\n")
 
 	return nil
 }
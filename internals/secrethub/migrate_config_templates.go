@@ -1,8 +1,12 @@
 package secrethub
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -13,6 +17,11 @@ import (
 var regexpSecretTemplatePath = regexp.MustCompile(`[A-Za-z0-9_\.\-\$\{\}]{2,}\/[A-Za-z0-9_\.\-\$\{\}]{2,}\/[A-Za-z0-9_\.\-\$\{\}\/]{2,}`)
 var regexpSecretTemplateTags = regexp.MustCompile(`{{\s*?(` + regexpSecretTemplatePath.String() + `)\s*?}}`)
 
+// Errors
+var (
+	ErrUnmappedReferences = errMain.Code("unmapped_references").ErrorPref("no 1Password equivalent present in your migration plan for some references, so these files were not migrated: %s")
+)
+
 func (cmd *MigrateConfigTemplatesCommand) Run() error {
 	plan, err := getPlan(cmd.planFile)
 	if err != nil {
@@ -28,34 +37,152 @@ func (cmd *MigrateConfigTemplatesCommand) Run() error {
 
 	refMapping.stripSecretHubURIScheme()
 
-	for _, filepath := range cmd.inFiles {
-		inFileContents, err := os.ReadFile(filepath)
+	formatString := "{{ %s }}"
+	if cmd.stripTags {
+		formatString = "%s"
+	}
+
+	files, err := collectTemplateFiles(cmd.inFiles)
+	if err != nil {
+		return err
+	}
+
+	var unmappedFiles []string
+	for _, file := range files {
+		inFileContents, err := os.ReadFile(file)
 		if err != nil {
-			return ErrReadFile(filepath, err)
+			return ErrReadFile(file, err)
 		}
 
-		output, replaceCount, err := migrateTemplateTags(string(inFileContents), refMapping, "{{ %s }}")
-		if err != nil {
-			return err
+		output, hits, misses := replaceTemplateTags(string(inFileContents), refMapping, formatString)
+		output, refHits, refMisses := replaceReferences(output, refMapping)
+		hits = append(hits, refHits...)
+		misses = append(misses, refMisses...)
+
+		if len(misses) != 0 {
+			fmt.Fprintf(cmd.io.Output(), "Skipped %s: %d op:// references, %d unmapped references:\n- %s\n", file, len(hits), len(misses), strings.Join(misses, "\n- "))
+			unmappedFiles = append(unmappedFiles, file)
+			continue
 		}
 
-		inFileInfo, err := os.Stat(filepath)
+		if len(hits) == 0 {
+			fmt.Fprintf(cmd.io.Output(), "Unchanged %s: no SecretHub references found\n", file)
+			continue
+		}
+
+		inFileInfo, err := os.Stat(file)
 		if err != nil {
-			return ErrReadFile(filepath, err)
+			return ErrReadFile(file, err)
 		}
 
-		err = os.WriteFile(filepath, []byte(output), inFileInfo.Mode())
+		err = os.WriteFile(file, []byte(output), inFileInfo.Mode())
 		if err != nil {
 			return err
 		}
 
-		fmt.Fprintf(cmd.io.Output(), "Updated %s with %d op:// references\n", filepath, replaceCount)
+		fmt.Fprintf(cmd.io.Output(), "Updated %s with %d op:// references\n", file, len(hits))
+	}
+
+	if len(unmappedFiles) != 0 {
+		return ErrUnmappedReferences(strings.Join(unmappedFiles, ", "))
 	}
 
 	return nil
 }
 
-func migrateTemplateTags(inFileContents string, mapping referenceMapping, formatString string) (string, int, error) {
+// collectTemplateFiles returns the given files, with directories replaced by all
+// regular text files they contain, recursively. Files are returned in lexical order
+// per directory. Hidden directories are skipped.
+func collectTemplateFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, ErrReadFile(path, err)
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				if file != path && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if !d.Type().IsRegular() {
+				return nil
+			}
+
+			isText, err := isTextFile(file)
+			if err != nil {
+				return ErrReadFile(file, err)
+			}
+			if isText {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// isTextFile returns whether the first bytes of the file contain no NUL bytes.
+func isTextFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 8000)
+	n, err := f.Read(buf)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	return !bytes.Contains(buf[:n], []byte{0}), nil
+}
+
+// replaceReferences replaces secrethub:// references with their op:// equivalents
+// in the mapping, which should have its secrethub:// scheme stripped. It returns
+// the output, the replaced and the unmapped references.
+func replaceReferences(inFileContents string, mapping referenceMapping) (string, []string, []string) {
+	var hits, misses []string
+	output := regexpSecretsRef.ReplaceAllStringFunc(inFileContents, func(match string) string {
+		submatches := regexpSecretsRef.FindStringSubmatch(match)[1:]
+
+		matchIndexRef := 1
+		secretHubRef := submatches[matchIndexRef]
+
+		opRef, ok := mapping[strings.TrimPrefix(secretHubRef, secretReferencePrefix)]
+		if !ok {
+			misses = append(misses, secretHubRef)
+			return match
+		}
+
+		hits = append(hits, opRef)
+
+		submatches[matchIndexRef] = opRef
+		return strings.Join(submatches, "")
+	})
+	return output, hits, misses
+}
+
+// replaceTemplateTags replaces the secret paths in template tags with their op:// equivalents,
+// formatted with the given format string. It returns the output, the replaced references and
+// the secret paths for which no equivalent is found in the mapping.
+func replaceTemplateTags(inFileContents string, mapping referenceMapping, formatString string) (string, []string, []string) {
 	var hits, misses []string
 	output := regexpSecretTemplateTags.ReplaceAllStringFunc(inFileContents, func(templateTag string) string {
 		path := regexpSecretTemplateTags.FindStringSubmatch(templateTag)[1]
@@ -63,12 +190,17 @@ func migrateTemplateTags(inFileContents string, mapping referenceMapping, format
 		opRef, ok := mapping[path]
 		if !ok {
 			misses = append(misses, path)
-			return path
+			return templateTag
 		}
 
 		hits = append(hits, opRef)
 		return fmt.Sprintf(formatString, opRef)
 	})
+	return output, hits, misses
+}
+
+func migrateTemplateTags(inFileContents string, mapping referenceMapping, formatString string) (string, int, error) {
+	output, hits, misses := replaceTemplateTags(inFileContents, mapping, formatString)
 
 	if len(misses) != 0 {
 		errMsg := fmt.Sprintf("no 1Password equivalent present in your migration plan for the following secrets:\n- %s", strings.Join(misses, "\n- "))
@@ -95,8 +227,9 @@ type MigrateConfigTemplatesCommand struct {
 
 	inFiles cli.StringListValue
 
-	planFile string
-	vars     map[string]string
+	planFile  string
+	vars      map[string]string
+	stripTags bool
 }
 
 func NewMigrateConfigTemplatesCommand(io ui.IO) *MigrateConfigTemplatesCommand {
@@ -106,10 +239,11 @@ func NewMigrateConfigTemplatesCommand(io ui.IO) *MigrateConfigTemplatesCommand {
 }
 
 func (cmd *MigrateConfigTemplatesCommand) Register(r cli.Registerer) {
-	clause := r.Command("templates", "Migrate config file templates by turning SecretHub paths into 1Password op:// references.")
+	clause := r.Command("templates", "Migrate config file templates by turning SecretHub paths and secrethub:// references into 1Password op:// references. Directories are migrated recursively. The file mode of migrated files is preserved.")
 	clause.Flags().StringVar(&cmd.planFile, "plan-file", defaultPlanPath, "Path to the file used to migrate your secrets.")
 	clause.Flags().StringToStringVarP(&cmd.vars, "var", "v", nil, "Define the possible values for a template variable, e.g. --var env=dev,staging,prod --var region=us-east-1,eu-west-1")
-	clause.BindArgumentsArr(cli.Argument{Value: &cmd.inFiles, Name: "in-file", Required: true, Placeholder: "<config-file-path>...", Description: "The paths to one or more config template files you'd like to migrate."})
+	clause.Flags().BoolVar(&cmd.stripTags, "strip-tags", false, "Replace template tags with plain op:// references instead of keeping the {{ op://... }} syntax of op inject.")
+	clause.BindArgumentsArr(cli.Argument{Value: &cmd.inFiles, Name: "in-file", Required: true, Placeholder: "<config-file-or-dir-path>...", Description: "The paths to one or more config template files or directories containing them you'd like to migrate."})
 
 	clause.BindAction(cmd.Run)
 }
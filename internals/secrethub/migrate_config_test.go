@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/secrethub/secrethub-go/internals/assert"
@@ -422,3 +424,42 @@ func TestMigrateEnvfile(t *testing.T) {
 		})
 	}
 }
+
+func TestReplaceReferences(t *testing.T) {
+	m := referenceMapping{
+		"secrethub://org/repo/dir/user": "op://vault/item/user",
+	}
+	m.stripSecretHubURIScheme()
+
+	out, hits, misses := replaceReferences("user: secrethub://org/repo/dir/user\npassword: secrethub://org/repo/dir/password\n", m)
+
+	assert.Equal(t, out, "user: op://vault/item/user\npassword: secrethub://org/repo/dir/password\n")
+	assert.Equal(t, hits, []string{"op://vault/item/user"})
+	assert.Equal(t, misses, []string{"secrethub://org/repo/dir/password"})
+}
+
+func TestCollectTemplateFiles(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	files := map[string][]byte{
+		"app.conf":              []byte("{{ org/repo/dir/user }}"),
+		"nested/db.conf":        []byte("{{ org/repo/dir/password }}"),
+		"nested/binary.dat":     {0x00, 0x01},
+		".git/config":           []byte("ignored"),
+		"nested/.hidden/x.conf": []byte("ignored"),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.OK(t, os.MkdirAll(filepath.Dir(path), 0700))
+		assert.OK(t, os.WriteFile(path, content, 0600))
+	}
+
+	actual, err := collectTemplateFiles([]string{dir})
+	assert.OK(t, err)
+
+	assert.Equal(t, actual, []string{
+		filepath.Join(dir, "app.conf"),
+		filepath.Join(dir, "nested", "db.conf"),
+	})
+}
@@ -2,11 +2,14 @@
 package secrethub
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/api/uuid"
 	"github.com/secrethub/secrethub-go/internals/assert"
+
+	"gopkg.in/yaml.v2"
 )
 
 func TestAddTreeToPlan(t *testing.T) {
@@ -64,7 +67,8 @@ func TestAddTreeToPlan(t *testing.T) {
 						Name: "my-project",
 						Items: []item{
 							{
-								Name: "stripe-api-key",
+								Name:     "stripe-api-key",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "secret",
@@ -74,7 +78,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "aws-access-key-id",
+								Name:     "aws-access-key-id",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "secret",
@@ -84,7 +89,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "aws-secret-access-key",
+								Name:     "aws-secret-access-key",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "secret",
@@ -94,7 +100,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "db-user",
+								Name:     "db-user",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "secret",
@@ -104,7 +111,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "db-password",
+								Name:     "db-password",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "secret",
@@ -186,7 +194,8 @@ func TestAddTreeToPlan(t *testing.T) {
 						Name: "my-project",
 						Items: []item{
 							{
-								Name: "stripe",
+								Name:     "stripe",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "api-key",
@@ -196,7 +205,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "aws",
+								Name:     "aws",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "access-key-id",
@@ -211,7 +221,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "db",
+								Name:     "db",
+								Category: itemCategoryDatabase,
 								Fields: []field{
 									{
 										Name:      "user",
@@ -363,7 +374,8 @@ func TestAddTreeToPlan(t *testing.T) {
 						Name: "my-project-dev",
 						Items: []item{
 							{
-								Name: "stripe",
+								Name:     "stripe",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "api-key",
@@ -373,7 +385,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "aws",
+								Name:     "aws",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "access-key-id",
@@ -388,7 +401,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "db",
+								Name:     "db",
+								Category: itemCategoryDatabase,
 								Fields: []field{
 									{
 										Name:      "user",
@@ -408,7 +422,8 @@ func TestAddTreeToPlan(t *testing.T) {
 						Name: "my-project-prd",
 						Items: []item{
 							{
-								Name: "stripe",
+								Name:     "stripe",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "api-key",
@@ -418,7 +433,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "aws",
+								Name:     "aws",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "access-key-id",
@@ -433,7 +449,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "db",
+								Name:     "db",
+								Category: itemCategoryDatabase,
 								Fields: []field{
 									{
 										Name:      "user",
@@ -495,7 +512,8 @@ func TestAddTreeToPlan(t *testing.T) {
 						Name: "my-project",
 						Items: []item{
 							{
-								Name: "stripe-api-key",
+								Name:     "stripe-api-key",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "secret",
@@ -505,7 +523,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "db",
+								Name:     "db",
+								Category: itemCategoryDatabase,
 								Fields: []field{
 									{
 										Name:      "user",
@@ -567,7 +586,8 @@ func TestAddTreeToPlan(t *testing.T) {
 						Name: "my-project",
 						Items: []item{
 							{
-								Name: "stripe_api_key",
+								Name:     "stripe_api_key",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "secret",
@@ -577,7 +597,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "aws",
+								Name:     "aws",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "secret_access_key",
@@ -685,7 +706,8 @@ func TestAddTreeToPlan(t *testing.T) {
 						Name: "my-project-dev",
 						Items: []item{
 							{
-								Name: "aws",
+								Name:     "aws",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "access-key-id",
@@ -705,7 +727,8 @@ func TestAddTreeToPlan(t *testing.T) {
 						Name: "my-project-prd",
 						Items: []item{
 							{
-								Name: "aws",
+								Name:     "aws",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "access-key-id",
@@ -725,7 +748,8 @@ func TestAddTreeToPlan(t *testing.T) {
 						Name: "my-project",
 						Items: []item{
 							{
-								Name: "db",
+								Name:     "db",
+								Category: itemCategoryDatabase,
 								Fields: []field{
 									{
 										Name:      "user",
@@ -827,7 +851,8 @@ func TestAddTreeToPlan(t *testing.T) {
 						Name: "my-project-dev",
 						Items: []item{
 							{
-								Name: "stripe-api-key",
+								Name:     "stripe-api-key",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "secret",
@@ -837,7 +862,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "aws",
+								Name:     "aws",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "access-key-id",
@@ -857,7 +883,8 @@ func TestAddTreeToPlan(t *testing.T) {
 						Name: "my-project-prd",
 						Items: []item{
 							{
-								Name: "stripe-api-key",
+								Name:     "stripe-api-key",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "secret",
@@ -867,7 +894,8 @@ func TestAddTreeToPlan(t *testing.T) {
 								},
 							},
 							{
-								Name: "aws",
+								Name:     "aws",
+								Category: itemCategoryAPICredential,
 								Fields: []field{
 									{
 										Name:      "access-key-id",
@@ -920,3 +948,87 @@ func addDirToTree(tree *api.Tree, dir *api.Dir) {
 		tree.Secrets[secret.SecretID] = secret
 	}
 }
+
+func TestPlan_MarshalYAML(t *testing.T) {
+	p := newPlan()
+	for _, name := range []string{"c", "a", "b"} {
+		p.vaults[name] = &vault{Name: name}
+	}
+
+	for i := 0; i < 10; i++ {
+		out, err := yaml.Marshal(p)
+		assert.OK(t, err)
+		assert.Equal(t, string(out), "sign-in-address: \"\"\nvaults:\n- vault-name: a\n  items: []\n- vault-name: b\n  items: []\n- vault-name: c\n  items: []\n")
+	}
+}
+
+func TestItemUpdate_Print(t *testing.T) {
+	update := itemUpdate{
+		item: "db",
+		fieldValues: map[string]string{
+			"password": "secret",
+			"host":     "localhost",
+			"user":     "root",
+		},
+	}
+
+	buf := bytes.Buffer{}
+	update.Print(&buf)
+
+	assert.Equal(t, buf.String(), "Update item 'db' fields:\n  'host'\n  'password'\n  'user'\n")
+}
+
+func TestItemCategoryForFields(t *testing.T) {
+	cases := map[string]struct {
+		itemName string
+		fields   []string
+		expected string
+	}{
+		"api credential": {
+			itemName: "stripe",
+			fields:   []string{"api-key"},
+			expected: itemCategoryAPICredential,
+		},
+		"login": {
+			itemName: "admin",
+			fields:   []string{"username", "password"},
+			expected: itemCategoryLogin,
+		},
+		"server": {
+			itemName: "bastion",
+			fields:   []string{"host", "user", "password"},
+			expected: itemCategoryServer,
+		},
+		"database by field": {
+			itemName: "backend",
+			fields:   []string{"host", "db_name", "user", "password"},
+			expected: itemCategoryDatabase,
+		},
+		"database by name": {
+			itemName: "postgres",
+			fields:   []string{"user", "password"},
+			expected: itemCategoryDatabase,
+		},
+		"database name without credentials": {
+			itemName: "redis",
+			fields:   []string{"token"},
+			expected: itemCategoryAPICredential,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fields := make([]field, len(tc.fields))
+			for i, name := range tc.fields {
+				fields[i] = field{Name: name}
+			}
+			assert.Equal(t, itemCategoryForFields(tc.itemName, fields), tc.expected)
+		})
+	}
+}
+
+func TestItemCategoryForSecret(t *testing.T) {
+	assert.Equal(t, itemCategoryForSecret("stripe-api-key"), itemCategoryAPICredential)
+	assert.Equal(t, itemCategoryForSecret("tls.pem"), itemCategorySecureNote)
+	assert.Equal(t, itemCategoryForSecret("config.YAML"), itemCategorySecureNote)
+}
@@ -2,7 +2,6 @@ package secrethub
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/pkg/secrethub"
@@ -51,7 +50,7 @@ func (cmd *MkDirCommand) Run() error {
 	for _, path := range cmd.paths {
 		err := cmd.createDirectory(client, path)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Could not create a new directory at %s: %s\n", path, err)
+			logger.Errorf("Could not create a new directory at %s: %s", path, err)
 		} else {
 			fmt.Fprintf(cmd.io.Output(), "Created a new directory at %s\n", path)
 		}
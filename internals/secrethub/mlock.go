@@ -1,24 +1,22 @@
 package secrethub
 
 import (
+	"strings"
+
 	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/mlock"
 	"github.com/spf13/cobra"
 )
 
-// RegisterMlockFlag registers a mlock flag that enables memory locking when set to true.
+// RegisterMlockFlag registers a mlock flag that sets when memory is locked: always with on,
+// when it is supported and allowed with auto, or never with off. Setting the flag without a
+// value, like the boolean flag it used to be, is the same as setting it to on.
 func RegisterMlockFlag(app *cli.App) {
-	var flag bool
-	app.PersistentFlags().BoolVar(&flag, "mlock", false, "Enable memory locking.").Hidden()
-	app.Root.AddPersistentPreRunE(func(command *cobra.Command, strings []string) error {
-		if flag {
-			if mlock.Supported() {
-				err := mlock.LockMemory()
-				if err != nil {
-					return err
-				}
-			}
-		}
-		return nil
+	var mode string
+	app.PersistentFlags().StringVar(&mode, "mlock", mlock.ModeAuto, "Lock memory, so that decrypted secrets are never written to swap. Options are "+strings.Join(mlock.Modes, ", ")+". "+
+		"With auto, memory is locked when the system supports it and the process is allowed to lock all of its memory. With on, commands fail when memory cannot be locked.")
+	app.PersistentFlags().Lookup("mlock").NoOptDefVal = mlock.ModeOn
+	app.Root.AddPersistentPreRunE(func(_ *cobra.Command, _ []string) error {
+		return mlock.Apply(mode)
 	})
 }
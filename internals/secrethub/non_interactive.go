@@ -0,0 +1,36 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/spf13/cobra"
+)
+
+const nonInteractiveErrCode = "non_interactive"
+
+// Errors
+var (
+	ErrNonInteractive = errMain.Code(nonInteractiveErrCode).ErrorPref("cannot ask for input in non-interactive mode: %s")
+)
+
+// RegisterNonInteractiveFlag registers the flag that disables all prompts for input.
+// The given bool is set to whether non-interactive mode is enabled.
+func RegisterNonInteractiveFlag(app *cli.App, nonInteractive *bool) {
+	app.PersistentFlags().BoolVar(nonInteractive, "non-interactive", false, "Never prompt for input. "+
+		"Commands that need to ask for input fail immediately instead, with an error that names the flag to bypass the prompt. "+
+		"Use this in CI and scripts to make sure the CLI never hangs waiting for input.")
+}
+
+// nonInteractiveError translates errors caused by a prompt in non-interactive mode to
+// ErrNonInteractive, which tells how the prompt can be bypassed for the executed command.
+func nonInteractiveError(command *cobra.Command, err error) error {
+	if err != ui.ErrCannotAsk && err != ErrCannotDoWithoutForce {
+		return err
+	}
+
+	if command != nil && command.Flags().Lookup("force") != nil {
+		return ErrNonInteractive("run the command with the --force flag to skip the confirmation or provide all required input with flags")
+	}
+	return ErrNonInteractive("provide all required input with flags or arguments")
+}
@@ -0,0 +1,63 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+
+	"github.com/spf13/cobra"
+)
+
+func TestNonInteractiveError(t *testing.T) {
+	withForce := &cobra.Command{Use: "rm"}
+	withForce.Flags().BoolP("force", "f", false, "")
+	withoutForce := &cobra.Command{Use: "init"}
+
+	cases := map[string]struct {
+		command      *cobra.Command
+		err          error
+		expected     error
+		expectedCode int
+	}{
+		"no error": {
+			command:      withForce,
+			expectedCode: 0,
+		},
+		"other error": {
+			command:      withForce,
+			err:          errors.New("test"),
+			expected:     errors.New("test"),
+			expectedCode: 1,
+		},
+		"cannot ask with force flag": {
+			command:      withForce,
+			err:          ui.ErrCannotAsk,
+			expected:     ErrNonInteractive("run the command with the --force flag to skip the confirmation or provide all required input with flags"),
+			expectedCode: ExitCodeNonInteractive,
+		},
+		"cannot do without force": {
+			command:      withForce,
+			err:          ErrCannotDoWithoutForce,
+			expected:     ErrNonInteractive("run the command with the --force flag to skip the confirmation or provide all required input with flags"),
+			expectedCode: ExitCodeNonInteractive,
+		},
+		"cannot ask without force flag": {
+			command:      withoutForce,
+			err:          ui.ErrCannotAsk,
+			expected:     ErrNonInteractive("provide all required input with flags or arguments"),
+			expectedCode: ExitCodeNonInteractive,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := nonInteractiveError(tc.command, tc.err)
+
+			assert.Equal(t, err, tc.expected)
+			assert.Equal(t, ExitCode(err), tc.expectedCode)
+		})
+	}
+}
@@ -7,15 +7,19 @@ import (
 
 // OrgCommand handles operations on organizations.
 type OrgCommand struct {
-	io        ui.IO
-	newClient newClientFunc
+	io              ui.IO
+	clientFactory   ClientFactory
+	credentialStore CredentialConfig
+	teams           *TeamStore
 }
 
 // NewOrgCommand creates a new OrgCommand.
-func NewOrgCommand(io ui.IO, newClient newClientFunc) *OrgCommand {
+func NewOrgCommand(io ui.IO, clientFactory ClientFactory, credentialStore CredentialConfig, teams *TeamStore) *OrgCommand {
 	return &OrgCommand{
-		io:        io,
-		newClient: newClient,
+		io:              io,
+		clientFactory:   clientFactory,
+		credentialStore: credentialStore,
+		teams:           teams,
 	}
 }
 
@@ -27,13 +31,16 @@ func (cmd *OrgCommand) Register(r cli.Registerer) {
 	clause.Alias("orgs")
 	clause.Alias("organizations")
 	clause.Alias("organisations")
-	NewOrgInitCommand(cmd.io, cmd.newClient).Register(clause)
-	NewOrgInspectCommand(cmd.io, cmd.newClient).Register(clause)
-	NewOrgInviteCommand(cmd.io, cmd.newClient).Register(clause)
+	NewOrgAuditCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
+	NewOrgInitCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
+	NewOrgInspectCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
+	NewOrgInviteCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
+	NewOrgOnboardCommand(cmd.io, cmd.clientFactory.NewClient, cmd.credentialStore, cmd.teams).Register(clause)
 	NewOrgPurchaseCommand(cmd.io).Register(clause)
-	NewOrgListUsersCommand(cmd.io, cmd.newClient).Register(clause)
-	NewOrgLsCommand(cmd.io, cmd.newClient).Register(clause)
-	NewOrgRevokeCommand(cmd.io, cmd.newClient).Register(clause)
-	NewOrgRmCommand(cmd.io, cmd.newClient).Register(clause)
-	NewOrgSetRoleCommand(cmd.io, cmd.newClient).Register(clause)
+	NewOrgListUsersCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
+	NewOrgLsCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
+	NewOrgRecoveryCommand(cmd.io, cmd.clientFactory, cmd.credentialStore).Register(clause)
+	NewOrgRevokeCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
+	NewOrgRmCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
+	NewOrgSetRoleCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
 }
@@ -0,0 +1,176 @@
+package secrethub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
+)
+
+// Errors
+var (
+	ErrInvalidInactiveDays = errMain.Code("invalid_inactive_days").ErrorPref("invalid value for --inactive-days: %d: must be at least 1")
+)
+
+// OrgAuditCommand lists the members of an organization with their latest activity,
+// to review whether they still need their access.
+type OrgAuditCommand struct {
+	orgName       api.OrgName
+	inactiveDays  int
+	useTimestamps bool
+	io            ui.IO
+	newClient     newClientFunc
+	timeFormatter TimeFormatter
+	now           func() time.Time
+}
+
+// NewOrgAuditCommand creates a new OrgAuditCommand.
+func NewOrgAuditCommand(io ui.IO, newClient newClientFunc) *OrgAuditCommand {
+	return &OrgAuditCommand{
+		io:        io,
+		newClient: newClient,
+		now:       time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *OrgAuditCommand) Register(r cli.Registerer) {
+	clause := r.Command("audit", "List the members of an organization with their role, the number of repositories they are a member of and their latest activity.")
+	clause.HelpLong("The latest activity of a member is the newest event in the audit logs of the repositories of the organization that was performed by the member. " +
+		"Only the audit logs of repositories you are an admin of can be read, so activity in other repositories is not taken into account.\n\n" +
+		"Members without activity in the given number of days are flagged as inactive.")
+	clause.Flags().IntVar(&cmd.inactiveDays, "inactive-days", 90, "The number of days without activity after which a member is flagged as inactive.")
+	registerTimestampFlag(clause, &cmd.useTimestamps)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.orgName, Name: "org-name", Required: true, Description: "The organization name."},
+	})
+}
+
+// Run prints the activity of the members.
+func (cmd *OrgAuditCommand) Run() error {
+	cmd.beforeRun()
+	return cmd.run()
+}
+
+// beforeRun configures the command using the flag values.
+func (cmd *OrgAuditCommand) beforeRun() {
+	cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps)
+}
+
+// run prints the activity of the members.
+func (cmd *OrgAuditCommand) run() error {
+	if cmd.inactiveDays < 1 {
+		return ErrInvalidInactiveDays(cmd.inactiveDays)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	activity, err := orgMemberActivity(client, cmd.orgName)
+	if err != nil {
+		return err
+	}
+
+	cutoff := cmd.now().AddDate(0, 0, -cmd.inactiveDays)
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", "USER", "ROLE", "REPOS", "LAST ACTIVE", "STATUS")
+	for _, member := range activity {
+		lastActive := "never"
+		if !member.lastActive.IsZero() {
+			lastActive = cmd.timeFormatter.Format(member.lastActive.Local())
+		}
+
+		status := "active"
+		if member.lastActive.Before(cutoff) {
+			status = "inactive"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", member.username, member.role, member.repos, lastActive, status)
+	}
+	return w.Flush()
+}
+
+// orgMemberActivityRecord is the activity of a single member of an organization.
+type orgMemberActivityRecord struct {
+	username string
+	role     string
+	// repos is the number of repositories of the organization the member is a member of.
+	repos int
+	// lastActive is the time of the newest audit event performed by the member, or zero when there is none.
+	lastActive time.Time
+}
+
+// orgMemberActivity returns the activity of every member of the organization, sorted by username.
+func orgMemberActivity(client secrethub.ClientInterface, org api.OrgName) ([]*orgMemberActivityRecord, error) {
+	members, err := client.Orgs().Members().List(org.Value())
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(api.SortOrgMemberByUsername(members))
+
+	records := make([]*orgMemberActivityRecord, len(members))
+	byUsername := make(map[string]*orgMemberActivityRecord, len(members))
+	for i, member := range members {
+		records[i] = &orgMemberActivityRecord{
+			username: member.User.Username,
+			role:     member.Role,
+		}
+		byUsername[strings.ToLower(member.User.Username)] = records[i]
+	}
+
+	repos, err := client.Repos().List(org.Value())
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(api.SortRepoByName(repos))
+
+	for _, repo := range repos {
+		users, err := client.Repos().Users().List(repo.Path().Value())
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			if record, ok := byUsername[strings.ToLower(user.Username)]; ok {
+				record.repos++
+			}
+		}
+	}
+
+	// The events are returned from newest to oldest, so the first event of every
+	// member is its latest activity and iterating can stop once all members are found.
+	found := 0
+	iter := newNamespaceAuditEventIterator(client, repos)
+	for found < len(records) {
+		event, err := iter.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if event.Actor.Deleted || event.Actor.Type != "user" {
+			continue
+		}
+
+		record, ok := byUsername[strings.ToLower(event.Actor.User.Username)]
+		if ok && record.lastActive.IsZero() {
+			record.lastActive = event.LoggedAt
+			found++
+		}
+	}
+
+	return records, nil
+}
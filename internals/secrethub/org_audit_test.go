@@ -0,0 +1,114 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/fakes"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestOrgAuditCommand_run(t *testing.T) {
+	now := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	event := func(actorType string, username string, daysAgo int) api.Audit {
+		return api.Audit{
+			Actor: api.AuditActor{
+				Type: actorType,
+				User: &api.User{Username: username},
+			},
+			LoggedAt: now.AddDate(0, 0, -daysAgo),
+		}
+	}
+
+	cases := map[string]struct {
+		inactiveDays int
+		out          string
+		err          error
+	}{
+		"success": {
+			inactiveDays: 90,
+			out: "USER  ROLE    REPOS  LAST ACTIVE                STATUS\n" +
+				"dev1  admin   2      2018-01-01T01:01:01+00:00  active\n" +
+				"dev2  member  1      2018-01-01T01:01:01+00:00  inactive\n" +
+				"dev3  member  0      never                      inactive\n",
+		},
+		"shorter period": {
+			inactiveDays: 1,
+			out: "USER  ROLE    REPOS  LAST ACTIVE                STATUS\n" +
+				"dev1  admin   2      2018-01-01T01:01:01+00:00  inactive\n" +
+				"dev2  member  1      2018-01-01T01:01:01+00:00  inactive\n" +
+				"dev3  member  0      never                      inactive\n",
+		},
+		"invalid period": {
+			inactiveDays: 0,
+			err:          ErrInvalidInactiveDays(0),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			cmd := OrgAuditCommand{
+				orgName:      "company",
+				inactiveDays: tc.inactiveDays,
+				io:           io,
+				timeFormatter: &fakes.TimeFormatter{
+					Response: "2018-01-01T01:01:01+00:00",
+				},
+				now: func() time.Time {
+					return now
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						OrgService: &fakeclient.OrgService{
+							MembersService: &fakeclient.OrgMemberService{
+								ListFunc: func(org string) ([]*api.OrgMember, error) {
+									return []*api.OrgMember{
+										{User: &api.User{Username: "dev3"}, Role: api.OrgRoleMember},
+										{User: &api.User{Username: "dev1"}, Role: api.OrgRoleAdmin},
+										{User: &api.User{Username: "dev2"}, Role: api.OrgRoleMember},
+									}, nil
+								},
+							},
+						},
+						RepoService: &fakeclient.RepoService{
+							ListFunc: func(namespace string) ([]*api.Repo, error) {
+								return []*api.Repo{
+									{Owner: "company", Name: "a"},
+									{Owner: "company", Name: "b"},
+								}, nil
+							},
+							UserService: &fakeclient.RepoUserService{
+								ListFunc: func(path string) ([]*api.User, error) {
+									if path == "company/a" {
+										return []*api.User{{Username: "dev1"}, {Username: "dev2"}}, nil
+									}
+									return []*api.User{{Username: "dev1"}}, nil
+								},
+							},
+							// Both repos share the iterator, so all events are returned for the first repo.
+							AuditEventIterator: &fakeclient.AuditEventIterator{
+								Events: []api.Audit{
+									event("user", "dev1", 2),
+									event("service", "", 10),
+									event("user", "dev2", 100),
+									event("user", "dev1", 200),
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
@@ -85,7 +85,7 @@ func (cmd *OrgInitCommand) Run() error {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Creation complete! The organization %s is now ready to use.\n", resp.Name)
+	ui.Successf(cmd.io.Output(), "Creation complete! The organization %s is now ready to use.\n", resp.Name)
 
 	return nil
 }
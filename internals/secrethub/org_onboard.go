@@ -0,0 +1,261 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+
+	"gopkg.in/yaml.v2"
+)
+
+// onboardingDirName is the directory in the configuration directory that contains
+// the onboarding config of every organization, named <org-name>.yml.
+const onboardingDirName = "onboarding"
+
+// Errors
+var (
+	ErrOnboardingConfigNotFound = errMain.Code("onboarding_config_not_found").ErrorPref("there is no onboarding config for %s: create %s or use --config")
+	ErrInvalidOnboardingConfig  = errMain.Code("invalid_onboarding_config").ErrorPref("invalid onboarding config %s: %s")
+)
+
+// onboardingConfig declares the role, repositories and access rules every new member of an organization gets.
+//
+// Example:
+//
+//	role: member
+//	repos:
+//	  - my-org/docs
+//	access:
+//	  - path: my-org/shared
+//	    permission: read
+type onboardingConfig struct {
+	Role string `yaml:"role"`
+	// Repos are the repositories to invite the member to, in addition
+	// to the repositories of the paths of the access rules.
+	Repos  []string         `yaml:"repos"`
+	Access []teamAccessRule `yaml:"access"`
+}
+
+// readOnboardingConfig reads and validates the onboarding config for the organization at the given path.
+func readOnboardingConfig(path string, org api.OrgName) (*onboardingConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+
+	config := &onboardingConfig{}
+	err = yaml.UnmarshalStrict(raw, config)
+	if err != nil {
+		return nil, ErrInvalidOnboardingConfig(path, err)
+	}
+
+	if config.Role == "" {
+		config.Role = api.OrgRoleMember
+	}
+	if config.Role != api.OrgRoleMember && config.Role != api.OrgRoleAdmin {
+		return nil, ErrInvalidOnboardingConfig(path, fmt.Sprintf("role must be member or admin, got %s", config.Role))
+	}
+
+	for _, repo := range config.Repos {
+		repoPath, err := api.NewRepoPath(repo)
+		if err != nil {
+			return nil, ErrInvalidOnboardingConfig(path, err)
+		}
+		if !strings.EqualFold(repoPath.GetNamespace(), org.Value()) {
+			return nil, ErrInvalidOnboardingConfig(path, fmt.Sprintf("%s is not in the %s organization", repo, org))
+		}
+	}
+
+	for _, rule := range config.Access {
+		_, err := api.NewDirPath(rule.Path)
+		if err != nil {
+			return nil, ErrInvalidOnboardingConfig(path, err)
+		}
+		if !strings.EqualFold(strings.Split(rule.Path, "/")[0], org.Value()) {
+			return nil, ErrInvalidOnboardingConfig(path, fmt.Sprintf("%s is not in the %s organization", rule.Path, org))
+		}
+
+		var permission api.Permission
+		err = permission.Set(rule.Permission)
+		if err != nil {
+			return nil, ErrInvalidOnboardingConfig(path, err)
+		}
+	}
+	return config, nil
+}
+
+// OrgOnboardCommand invites a user to an organization and gives them the repositories
+// and access rules of the onboarding config of the organization and of a team.
+type OrgOnboardCommand struct {
+	orgName    api.OrgName
+	username   cli.StringValue
+	team       string
+	configPath string
+	force      bool
+	io         ui.IO
+	newClient  newClientFunc
+	teams      *TeamStore
+	configDir  func() string
+}
+
+// NewOrgOnboardCommand creates a new OrgOnboardCommand.
+func NewOrgOnboardCommand(io ui.IO, newClient newClientFunc, store CredentialConfig, teams *TeamStore) *OrgOnboardCommand {
+	return &OrgOnboardCommand{
+		io:        io,
+		newClient: newClient,
+		teams:     teams,
+		configDir: func() string {
+			return store.ConfigDir().Path()
+		},
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *OrgOnboardCommand) Register(r cli.Registerer) {
+	clause := r.Command("onboard", "Invite a user to an organization and give them the repositories and access rules of the onboarding config.")
+	clause.HelpLong("The onboarding config of an organization is a YAML file that is read from " + onboardingDirName + "/<org-name>.yml " +
+		"in the configuration directory, unless another file is given with --config:\n\n" +
+		"    role: member\n" +
+		"    repos:\n" +
+		"      - my-org/docs\n" +
+		"    access:\n" +
+		"      - path: my-org/shared\n" +
+		"        permission: read\n\n" +
+		"The user is invited to the organization with the role, which defaults to member, and to the listed repositories " +
+		"and the repositories of the access rules. Then the access rules are set for the user.\n\n" +
+		"When a team is given with --team, the user is also added to that team, " +
+		"invited to the repositories of the access rules of the team and given those access rules.")
+	clause.Flags().StringVar(&cmd.team, "team", "", "The name of a team created with `secrethub team create` to add the user to.")
+	clause.Flags().StringVar(&cmd.configPath, "config", "", "The onboarding config file to use instead of the one in the configuration directory.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.orgName, Name: "org-name", Required: true, Description: "The organization name."},
+		{Value: &cmd.username, Name: "username", Required: true, Description: "The username of the user to onboard."},
+	})
+}
+
+// Run onboards the user.
+func (cmd *OrgOnboardCommand) Run() error {
+	configPath := cmd.configPath
+	if configPath == "" {
+		configPath = filepath.Join(cmd.configDir(), onboardingDirName, cmd.orgName.Value()+".yml")
+		_, err := os.Stat(configPath)
+		if os.IsNotExist(err) {
+			return ErrOnboardingConfigNotFound(cmd.orgName, configPath)
+		}
+	}
+
+	config, err := readOnboardingConfig(configPath, cmd.orgName)
+	if err != nil {
+		return err
+	}
+
+	rules := append([]teamAccessRule{}, config.Access...)
+	var team teamDefinition
+	if cmd.team != "" {
+		team, err = cmd.teams.get(cmd.team)
+		if err != nil {
+			return err
+		}
+		// The teams in the secret can have access in other organizations,
+		// so only the access rules in this organization are applied.
+		for _, rule := range team.Access {
+			if strings.EqualFold(strings.Split(rule.Path, "/")[0], cmd.orgName.Value()) {
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	repos := onboardingRepos(config.Repos, rules)
+
+	out := cmd.io.Output()
+	fmt.Fprintf(out, "Onboarding %s:\n", cmd.username.Value)
+	fmt.Fprintf(out, "  invite to %s as %s\n", cmd.orgName, config.Role)
+	for _, repo := range repos {
+		fmt.Fprintf(out, "  invite to %s\n", repo)
+	}
+	for _, rule := range rules {
+		fmt.Fprintf(out, "  set %s on %s\n", rule.Permission, rule.Path)
+	}
+	if cmd.team != "" {
+		fmt.Fprintf(out, "  add to team %s\n", team.Name)
+	}
+
+	if !cmd.force {
+		confirmed, err := ui.AskYesNo(cmd.io, fmt.Sprintf("Are you sure you want to onboard %s to the %s organization?", cmd.username.Value, cmd.orgName), ui.DefaultNo)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(out, "Aborting.")
+			return nil
+		}
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Orgs().Members().Invite(cmd.orgName.Value(), cmd.username.Value, config.Role)
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range repos {
+		_, err = client.Repos().Users().Invite(repo, cmd.username.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range rules {
+		_, err = client.AccessRules().Set(rule.Path, rule.Permission, cmd.username.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.team != "" && !team.hasMember(cmd.username.Value) {
+		team.Members = append(team.Members, cmd.username.Value)
+		err = cmd.teams.save(team)
+		if err != nil {
+			return err
+		}
+	}
+
+	ui.Successf(out, "Onboarding complete! The user %s is now %s of the %s organization.\n", cmd.username.Value, config.Role, cmd.orgName)
+	return nil
+}
+
+// onboardingRepos returns the given repositories together with the repositories
+// of the paths of the access rules, without duplicates and sorted.
+func onboardingRepos(repos []string, rules []teamAccessRule) []string {
+	seen := make(map[string]bool)
+	var result []string
+	add := func(path string) {
+		repo := strings.Join(strings.SplitN(path, "/", 3)[:2], "/")
+		if !seen[strings.ToLower(repo)] {
+			seen[strings.ToLower(repo)] = true
+			result = append(result, repo)
+		}
+	}
+
+	for _, repo := range repos {
+		add(repo)
+	}
+	for _, rule := range rules {
+		add(rule.Path)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,153 @@
+package secrethub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestOrgOnboardCommand_Run(t *testing.T) {
+	config := "repos:\n" +
+		"  - my-org/docs\n" +
+		"access:\n" +
+		"  - path: my-org/shared\n" +
+		"    permission: read\n"
+
+	cases := map[string]struct {
+		config          string
+		team            string
+		expectedInvites []string
+		expectedSet     []string
+		expectedMembers []string
+		expectedOut     string
+		expectedErr     func(dir string) error
+	}{
+		"without team": {
+			config:          config,
+			expectedInvites: []string{"my-org member", "my-org/docs", "my-org/shared"},
+			expectedSet:     []string{"my-org/shared read"},
+			expectedMembers: []string{"dev1"},
+			expectedOut: "Onboarding dev2:\n" +
+				"  invite to my-org as member\n" +
+				"  invite to my-org/docs\n" +
+				"  invite to my-org/shared\n" +
+				"  set read on my-org/shared\n" +
+				"Onboarding complete! The user dev2 is now member of the my-org organization.\n",
+		},
+		"with team": {
+			config:          config,
+			team:            "backend",
+			expectedInvites: []string{"my-org member", "my-org/backend", "my-org/docs", "my-org/shared"},
+			expectedSet:     []string{"my-org/shared read", "my-org/backend/dev write"},
+			expectedMembers: []string{"dev1", "dev2"},
+			expectedOut: "Onboarding dev2:\n" +
+				"  invite to my-org as member\n" +
+				"  invite to my-org/backend\n" +
+				"  invite to my-org/docs\n" +
+				"  invite to my-org/shared\n" +
+				"  set read on my-org/shared\n" +
+				"  set write on my-org/backend/dev\n" +
+				"  add to team backend\n" +
+				"Onboarding complete! The user dev2 is now member of the my-org organization.\n",
+		},
+		"repo of other org": {
+			config: "repos:\n" +
+				"  - other-org/docs\n",
+			expectedMembers: []string{"dev1"},
+			expectedErr: func(dir string) error {
+				return ErrInvalidOnboardingConfig(filepath.Join(dir, "onboarding", "my-org.yml"), "other-org/docs is not in the my-org organization")
+			},
+		},
+		"no config": {
+			expectedMembers: []string{"dev1"},
+			expectedErr: func(dir string) error {
+				return ErrOnboardingConfigNotFound("my-org", filepath.Join(dir, "onboarding", "my-org.yml"))
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			if tc.config != "" {
+				assert.OK(t, os.MkdirAll(filepath.Join(dir, "onboarding"), 0700))
+				assert.OK(t, os.WriteFile(filepath.Join(dir, "onboarding", "my-org.yml"), []byte(tc.config), 0600))
+			}
+			var expectedErr error
+			if tc.expectedErr != nil {
+				expectedErr = tc.expectedErr(dir)
+			}
+
+			teams := newFakeTeamStore()
+			assert.OK(t, teams.save(teamDefinition{
+				Name:    "backend",
+				Members: []string{"dev1"},
+				Access: []teamAccessRule{
+					{Path: "my-org/backend/dev", Permission: "write"},
+					{Path: "other-org/backend", Permission: "read"},
+				},
+			}))
+
+			var invites, set []string
+			io := fakeui.NewIO(t)
+			cmd := OrgOnboardCommand{
+				orgName:  "my-org",
+				username: cli.StringValue{Value: "dev2"},
+				team:     tc.team,
+				force:    true,
+				io:       io,
+				teams:    teams,
+				configDir: func() string {
+					return dir
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						OrgService: &fakeclient.OrgService{
+							MembersService: &fakeclient.OrgMemberService{
+								InviteFunc: func(org string, username string, role string) (*api.OrgMember, error) {
+									invites = append(invites, org+" "+role)
+									return &api.OrgMember{}, nil
+								},
+							},
+						},
+						RepoService: &fakeclient.RepoService{
+							UserService: &fakeclient.RepoUserService{
+								InviteFunc: func(path string, username string) (*api.RepoMember, error) {
+									invites = append(invites, path)
+									return &api.RepoMember{}, nil
+								},
+							},
+						},
+						AccessRuleService: &fakeclient.AccessRuleService{
+							SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+								set = append(set, path+" "+permission)
+								return nil, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, expectedErr)
+			assert.Equal(t, invites, tc.expectedInvites)
+			assert.Equal(t, set, tc.expectedSet)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+
+			team, err := teams.get("backend")
+			assert.OK(t, err)
+			assert.Equal(t, team.Members, tc.expectedMembers)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package secrethub
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+const (
+	orgRecoveryKeyBits     = 4096
+	orgRecoveryFileVersion = 1
+
+	pemTypeRSAPrivateKey = "RSA PRIVATE KEY"
+	pemTypePublicKey     = "PUBLIC KEY"
+)
+
+// Errors
+var (
+	ErrInvalidRecoveryKey    = errMain.Code("invalid_recovery_key").ErrorPref("%s does not contain a valid recovery key: %s")
+	ErrInvalidRecoveryFile   = errMain.Code("invalid_recovery_file").ErrorPref("could not parse the recovery file %s: %s")
+	ErrRecoveryKeyMismatch   = errMain.Code("recovery_key_mismatch").Error("the recovery file was not created for this recovery key")
+	ErrCannotDecryptRecovery = errMain.Code("cannot_decrypt_recovery").Error("cannot decrypt the recovery file: it is corrupted or was created for another key")
+)
+
+// OrgRecoveryCommand handles the recovery of accounts of organization members.
+type OrgRecoveryCommand struct {
+	io              ui.IO
+	clientFactory   ClientFactory
+	credentialStore CredentialConfig
+}
+
+// NewOrgRecoveryCommand creates a new OrgRecoveryCommand.
+func NewOrgRecoveryCommand(io ui.IO, clientFactory ClientFactory, credentialStore CredentialConfig) *OrgRecoveryCommand {
+	return &OrgRecoveryCommand{
+		io:              io,
+		clientFactory:   clientFactory,
+		credentialStore: credentialStore,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *OrgRecoveryCommand) Register(r cli.Registerer) {
+	clause := r.Command("recovery", "Make sure an organization keeps access to its repositories when a member loses their credential.")
+	NewOrgRecoveryKeygenCommand(cmd.io).Register(clause)
+	NewOrgRecoverySetupCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
+	NewOrgRecoveryRestoreCommand(cmd.io, cmd.clientFactory.NewClientWithCredentials, cmd.credentialStore).Register(clause)
+}
+
+// orgRecoveryFile is the format in which the backup code of an account is stored,
+// encrypted for the recovery key of an organization.
+type orgRecoveryFile struct {
+	Version   int       `json:"version"`
+	Org       string    `json:"org"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+	// KeyFingerprint identifies the recovery key the file was encrypted for.
+	KeyFingerprint string `json:"key_fingerprint"`
+	// WrappedKey is the AES key that encrypts the backup code, encrypted with RSA-OAEP.
+	WrappedKey []byte `json:"wrapped_key"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// recoveryKeyFingerprint returns the hex encoded SHA-256 hash of the public key.
+func recoveryKeyFingerprint(pub *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// generateRecoveryKey returns a new recovery key, PEM encoded as private and public key.
+func generateRecoveryKey(bits int) ([]byte, []byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: pemTypeRSAPrivateKey, Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: pemTypePublicKey, Bytes: publicDER})
+	return privatePEM, publicPEM, nil
+}
+
+// readRecoveryPublicKey reads a PEM encoded recovery public key from the given file.
+func readRecoveryPublicKey(path string) (*rsa.PublicKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil || block.Type != pemTypePublicKey {
+		return nil, ErrInvalidRecoveryKey(path, "expected a PEM encoded public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidRecoveryKey(path, err)
+	}
+	pub, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, ErrInvalidRecoveryKey(path, "expected an RSA key")
+	}
+	return pub, nil
+}
+
+// readRecoveryPrivateKey reads a PEM encoded recovery private key from the given file.
+func readRecoveryPrivateKey(path string) (*rsa.PrivateKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil || block.Type != pemTypeRSAPrivateKey {
+		return nil, ErrInvalidRecoveryKey(path, "expected a PEM encoded RSA private key")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidRecoveryKey(path, err)
+	}
+	return key, nil
+}
+
+// seal encrypts the backup code with a new AES key and wraps that key with the recovery key.
+func (f *orgRecoveryFile) seal(pub *rsa.PublicKey, backupCode []byte) error {
+	fingerprint, err := recoveryKeyFingerprint(pub)
+	if err != nil {
+		return err
+	}
+
+	key := make([]byte, 32)
+	_, err = rand.Read(key)
+	if err != nil {
+		return err
+	}
+	gcm, err := newRecoveryGCM(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+	wrappedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, key, nil)
+	if err != nil {
+		return err
+	}
+
+	f.Version = orgRecoveryFileVersion
+	f.KeyFingerprint = fingerprint
+	f.WrappedKey = wrappedKey
+	f.Nonce = nonce
+	f.Ciphertext = gcm.Seal(nil, nonce, backupCode, f.additionalData())
+	return nil
+}
+
+// open decrypts the backup code with the recovery key.
+func (f *orgRecoveryFile) open(priv *rsa.PrivateKey) ([]byte, error) {
+	fingerprint, err := recoveryKeyFingerprint(&priv.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if fingerprint != f.KeyFingerprint {
+		return nil, ErrRecoveryKeyMismatch
+	}
+
+	key, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, priv, f.WrappedKey, nil)
+	if err != nil {
+		return nil, ErrCannotDecryptRecovery
+	}
+	gcm, err := newRecoveryGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Nonce) != gcm.NonceSize() {
+		return nil, ErrCannotDecryptRecovery
+	}
+	backupCode, err := gcm.Open(nil, f.Nonce, f.Ciphertext, f.additionalData())
+	if err != nil {
+		return nil, ErrCannotDecryptRecovery
+	}
+	return backupCode, nil
+}
+
+// additionalData binds the ciphertext to the organization and account it was created for,
+// so that these cannot be changed without detection.
+func (f *orgRecoveryFile) additionalData() []byte {
+	return []byte(f.Org + "/" + f.Username)
+}
+
+func newRecoveryGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// readOrgRecoveryFile reads and parses the recovery file at the given path.
+func readOrgRecoveryFile(path string) (*orgRecoveryFile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+
+	file := &orgRecoveryFile{}
+	err = json.Unmarshal(raw, file)
+	if err != nil {
+		return nil, ErrInvalidRecoveryFile(path, err)
+	}
+	if file.Version != orgRecoveryFileVersion {
+		return nil, ErrInvalidRecoveryFile(path, "unsupported version")
+	}
+	return file, nil
+}
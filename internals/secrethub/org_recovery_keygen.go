@@ -0,0 +1,65 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// OrgRecoveryKeygenCommand generates a key pair for the recovery admins of an organization.
+type OrgRecoveryKeygenCommand struct {
+	io      ui.IO
+	outFile string
+	force   bool
+}
+
+// NewOrgRecoveryKeygenCommand creates a new OrgRecoveryKeygenCommand.
+func NewOrgRecoveryKeygenCommand(io ui.IO) *OrgRecoveryKeygenCommand {
+	return &OrgRecoveryKeygenCommand{
+		io: io,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *OrgRecoveryKeygenCommand) Register(r cli.Registerer) {
+	clause := r.Command("keygen", "Generate a recovery key pair. The private key is written to the output file and the public key to the same path suffixed with .pub.")
+	clause.Flags().StringVarP(&cmd.outFile, "out-file", "o", "recovery.pem", "The path to write the private key to.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run generates a recovery key pair and writes it to disk.
+func (cmd *OrgRecoveryKeygenCommand) Run() error {
+	publicFile := cmd.outFile + ".pub"
+	if !cmd.force {
+		for _, path := range []string{cmd.outFile, publicFile} {
+			_, err := os.Stat(path)
+			if err == nil {
+				return ErrFileAlreadyExists
+			}
+		}
+	}
+
+	privatePEM, publicPEM, err := generateRecoveryKey(orgRecoveryKeyBits)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(cmd.outFile, privatePEM, 0600)
+	if err != nil {
+		return ErrCannotWrite(cmd.outFile, err)
+	}
+	err = os.WriteFile(publicFile, publicPEM, 0644)
+	if err != nil {
+		return ErrCannotWrite(publicFile, err)
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Wrote the recovery private key to %s and the public key to %s.\n", cmd.outFile, publicFile)
+	fmt.Fprintln(cmd.io.Output(), "Store the private key offline with your recovery admins and share the public key with the members of your organization, "+
+		"so they can run `secrethub org recovery setup`.")
+	return nil
+}
@@ -0,0 +1,76 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+// OrgRecoveryRestoreCommand decrypts a recovery file with the recovery private key of an
+// organization and restores the account on this device.
+type OrgRecoveryRestoreCommand struct {
+	recoveryFile             cli.StringValue
+	privateKeyFile           string
+	printBackupCode          bool
+	force                    bool
+	io                       ui.IO
+	newClientWithCredentials func(credentials.Provider) (secrethub.ClientInterface, error)
+	credentialStore          CredentialConfig
+}
+
+// NewOrgRecoveryRestoreCommand creates a new OrgRecoveryRestoreCommand.
+func NewOrgRecoveryRestoreCommand(io ui.IO, newClientWithCredentials func(credentials.Provider) (secrethub.ClientInterface, error), credentialStore CredentialConfig) *OrgRecoveryRestoreCommand {
+	return &OrgRecoveryRestoreCommand{
+		io:                       io,
+		newClientWithCredentials: newClientWithCredentials,
+		credentialStore:          credentialStore,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *OrgRecoveryRestoreCommand) Register(r cli.Registerer) {
+	clause := r.Command("restore", "Restore an account from a recovery file created with `secrethub org recovery setup`.")
+	clause.Flags().StringVar(&cmd.privateKeyFile, "private-key-file", "recovery.pem", "The path to the recovery private key of the organization.")
+	clause.Flags().BoolVar(&cmd.printBackupCode, "print-backup-code", false, "Print the backup code of the account instead of restoring it on this device, so it can be handed to its owner who restores it with `secrethub init --backup-code`.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.recoveryFile, Name: "recovery-file", Required: true, Description: "The path to the recovery file."},
+	})
+}
+
+// Run decrypts the backup code in the recovery file and uses it to configure the account
+// on this device, or prints it when --print-backup-code is set.
+func (cmd *OrgRecoveryRestoreCommand) Run() error {
+	file, err := readOrgRecoveryFile(cmd.recoveryFile.Value)
+	if err != nil {
+		return err
+	}
+	priv, err := readRecoveryPrivateKey(cmd.privateKeyFile)
+	if err != nil {
+		return err
+	}
+	backupCode, err := file.open(priv)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Decrypted the recovery file of %s for %s, created at %s.\n",
+		file.Username, file.Org, NewTimestampFormatter().Format(file.CreatedAt))
+
+	if cmd.printBackupCode {
+		fmt.Fprintf(cmd.io.Output(), "This is the backup code: \n%s\n", backupCode)
+		fmt.Fprintln(cmd.io.Output(), "It can be used to obtain full access to the account, so only hand it to its owner.")
+		return nil
+	}
+
+	initCmd := NewInitCommand(cmd.io, cmd.newClientWithCredentials, cmd.credentialStore)
+	initCmd.backupCode = string(backupCode)
+	initCmd.force = cmd.force
+	return initCmd.Run()
+}
@@ -0,0 +1,128 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+// OrgRecoverySetupCommand creates a backup code for the account and encrypts it for the
+// recovery key of an organization, so that the recovery admins can restore the account.
+type OrgRecoverySetupCommand struct {
+	name          api.OrgName
+	publicKeyFile string
+	outFile       string
+	force         bool
+	io            ui.IO
+	newClient     newClientFunc
+	now           func() time.Time
+}
+
+// NewOrgRecoverySetupCommand creates a new OrgRecoverySetupCommand.
+func NewOrgRecoverySetupCommand(io ui.IO, newClient newClientFunc) *OrgRecoverySetupCommand {
+	return &OrgRecoverySetupCommand{
+		io:        io,
+		newClient: newClient,
+		now:       time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *OrgRecoverySetupCommand) Register(r cli.Registerer) {
+	clause := r.Command("setup", "Create a recovery file with which the recovery admins of an organization can restore your account.")
+	clause.Flags().StringVar(&cmd.publicKeyFile, "public-key-file", "recovery.pem.pub", "The path to the recovery public key of the organization.")
+	clause.Flags().StringVarP(&cmd.outFile, "out-file", "o", "", "The path to write the recovery file to. Defaults to <username>.<org-name>.recovery.json.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.name, Name: "org-name", Required: true, Description: "The organization whose recovery admins can restore your account."},
+	})
+}
+
+// Run creates a backup code for the currently authenticated account and writes it to
+// a recovery file, encrypted for the recovery public key.
+func (cmd *OrgRecoverySetupCommand) Run() error {
+	pub, err := readRecoveryPublicKey(cmd.publicKeyFile)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	me, err := client.Me().GetUser()
+	if err != nil {
+		return err
+	}
+
+	// Make sure the account is a member of the organization.
+	_, err = client.Orgs().Members().Get(cmd.name.Value(), me.Username)
+	if err != nil {
+		return err
+	}
+
+	outFile := cmd.outFile
+	if outFile == "" {
+		outFile = fmt.Sprintf("%s.%s.recovery.json", me.Username, cmd.name)
+	}
+	_, err = os.Stat(outFile)
+	if err == nil && !cmd.force {
+		return ErrFileAlreadyExists
+	}
+
+	if !cmd.force {
+		question := fmt.Sprintf("This will create a new backup code for %s and encrypt it for the recovery key of %s. "+
+			"Whoever holds the recovery private key can use it to obtain full access to your account.\n"+
+			"Do you want to continue?", me.Username, cmd.name)
+		ok, err := ui.AskYesNo(cmd.io, question, ui.DefaultYes)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return nil
+		}
+	}
+
+	backupCode := credentials.CreateBackupCode()
+	_, err = client.Credentials().Create(backupCode, fmt.Sprintf("Recovery for %s", cmd.name))
+	if err != nil {
+		return err
+	}
+	code, err := backupCode.Code()
+	if err != nil {
+		return err
+	}
+
+	file := &orgRecoveryFile{
+		Org:       cmd.name.Value(),
+		Username:  me.Username,
+		CreatedAt: cmd.now().UTC(),
+	}
+	err = file.seal(pub, []byte(code))
+	if err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(outFile, raw, 0600)
+	if err != nil {
+		return ErrCannotWrite(outFile, err)
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Wrote the recovery file to %s. Hand it to the recovery admins of %s. "+
+		"They can restore your account with `secrethub org recovery restore`.\n", outFile, cmd.name)
+	return nil
+}
@@ -0,0 +1,73 @@
+package secrethub
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func generateTestRecoveryKey(t *testing.T) *rsa.PrivateKey {
+	privatePEM, _, err := generateRecoveryKey(1024)
+	assert.OK(t, err)
+	block, _ := pem.Decode(privatePEM)
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	assert.OK(t, err)
+	return key
+}
+
+func TestOrgRecoveryFile(t *testing.T) {
+	key := generateTestRecoveryKey(t)
+	otherKey := generateTestRecoveryKey(t)
+
+	cases := map[string]struct {
+		key    *rsa.PrivateKey
+		tamper func(f *orgRecoveryFile)
+		err    error
+	}{
+		"success": {
+			key:    key,
+			tamper: func(f *orgRecoveryFile) {},
+		},
+		"other key": {
+			key:    otherKey,
+			tamper: func(f *orgRecoveryFile) {},
+			err:    ErrRecoveryKeyMismatch,
+		},
+		"changed username": {
+			key: key,
+			tamper: func(f *orgRecoveryFile) {
+				f.Username = "mallory"
+			},
+			err: ErrCannotDecryptRecovery,
+		},
+		"changed ciphertext": {
+			key: key,
+			tamper: func(f *orgRecoveryFile) {
+				f.Ciphertext[0] ^= 0xff
+			},
+			err: ErrCannotDecryptRecovery,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			file := &orgRecoveryFile{
+				Org:      "company",
+				Username: "dev1",
+			}
+			err := file.seal(&key.PublicKey, []byte("backup code"))
+			assert.OK(t, err)
+
+			tc.tamper(file)
+
+			actual, err := file.open(tc.key)
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, string(actual), "backup code")
+			}
+		})
+	}
+}
@@ -118,7 +118,7 @@ func (cmd *OrgRevokeCommand) Run() error {
 		failed := revoked.StatusCounts[api.StatusFailed]
 		unaffected := revoked.StatusCounts[api.StatusOK]
 
-		fmt.Fprintf(
+		ui.Successf(
 			cmd.io.Output(),
 			"Revoke complete! Repositories: %d flagged, %d failed, %d OK.\n",
 			flagged,
@@ -126,7 +126,7 @@ func (cmd *OrgRevokeCommand) Run() error {
 			unaffected,
 		)
 	} else {
-		fmt.Fprintln(cmd.io.Output(), "Revoke complete!")
+		ui.Successf(cmd.io.Output(), "Revoke complete!\n")
 	}
 
 	return nil
@@ -67,7 +67,7 @@ func (cmd *OrgRmCommand) Run() error {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Delete complete! The organization %s has been permanently deleted.\n", cmd.name)
+	ui.Successf(cmd.io.Output(), "Delete complete! The organization %s has been permanently deleted.\n", cmd.name)
 
 	return nil
 }
@@ -167,3 +167,8 @@ func (p *fallbackPager) Write(data []byte) (int, error) {
 func (p *fallbackPager) Close() error {
 	return nil
 }
+
+// Command returns the path of the terminal pager that is used, or ErrPagerNotFound when no pager is available.
+func Command() (string, error) {
+	return pagerCommand()
+}
@@ -0,0 +1,155 @@
+package secrethub
+
+import (
+	"sort"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrMissingDirPath = errMain.Code("missing_dir_path").Error("a directory path is required")
+)
+
+// pathKind determines which kinds of paths can be picked.
+type pathKind int
+
+// Path kinds
+const (
+	pathKindDir pathKind = 1 << iota
+	pathKindSecret
+)
+
+// pathPicker lets the user pick a path when a command that takes a path argument is
+// run without one in a terminal. A nil pathPicker never picks a path.
+type pathPicker struct {
+	io        ui.IO
+	newClient newClientFunc
+	kinds     pathKind
+	disabled  bool
+}
+
+// newPathPicker creates a new pathPicker for the given kinds of paths.
+func newPathPicker(io ui.IO, newClient newClientFunc, kinds pathKind) *pathPicker {
+	return &pathPicker{
+		io:        io,
+		newClient: newClient,
+		kinds:     kinds,
+	}
+}
+
+// register registers the flag that disables the picker on the given clause.
+func (p *pathPicker) register(clause *cli.CommandClause) {
+	clause.Flags().BoolVar(&p.disabled, "no-picker", false, "Do not open an interactive picker when no path is given.")
+}
+
+// pick lets the user pick a path. An empty path is returned when the picker is disabled or
+// the input or output is not a terminal, so that the command can continue as if there was no picker.
+func (p *pathPicker) pick(question string) (string, error) {
+	if p == nil || p.disabled || p.io.IsInputPiped() || p.io.IsOutputPiped() {
+		return "", nil
+	}
+	_, _, err := p.io.Prompts()
+	if err != nil {
+		return "", nil
+	}
+
+	client, err := p.newClient()
+	if err != nil {
+		return "", err
+	}
+
+	source := &pathPickerSource{
+		client: client,
+		kinds:  p.kinds,
+	}
+	return ui.Pick(p.io, question, "path", source.next)
+}
+
+// pathPickerSource lists the paths in the repositories of the user, one repository at a time,
+// so that the picker can show the first matches before all repositories have been fetched.
+type pathPickerSource struct {
+	client secrethub.ClientInterface
+	kinds  pathKind
+
+	repos     []*api.Repo
+	listed    bool
+	repoIndex int
+}
+
+// next returns the next batch of paths and whether all paths have been returned.
+// The first batch contains the repositories themselves, when directories can be picked.
+// Every next batch contains the paths in the next repository.
+func (s *pathPickerSource) next() ([]ui.Option, bool, error) {
+	if !s.listed {
+		repos, err := s.client.Repos().ListMine()
+		if err != nil {
+			return nil, false, err
+		}
+		sort.Sort(api.SortRepoByName(repos))
+		s.repos = repos
+		s.listed = true
+
+		if s.kinds&pathKindDir != 0 {
+			options := make([]ui.Option, len(repos))
+			for i, repo := range repos {
+				options[i] = pathOption(repo.Path().Value())
+			}
+			return options, len(repos) == 0, nil
+		}
+	}
+
+	if s.repoIndex >= len(s.repos) {
+		return nil, true, nil
+	}
+	repoPath := s.repos[s.repoIndex].Path().Value()
+	s.repoIndex++
+
+	tree, err := s.client.Dirs().GetTree(repoPath, -1, false)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var options []ui.Option
+	for _, path := range treePaths(tree, repoPath, s.kinds) {
+		options = append(options, pathOption(path))
+	}
+	return options, s.repoIndex >= len(s.repos), nil
+}
+
+// pathOption returns an option to pick the given path.
+func pathOption(path string) ui.Option {
+	return ui.Option{
+		Value:   path,
+		Display: path,
+	}
+}
+
+// treePaths returns the paths of the given kinds in the tree of the repository at repoPath, sorted.
+// The path of the repository itself is not included.
+func treePaths(tree *api.Tree, repoPath string, kinds pathKind) []string {
+	var paths []string
+	var addDir func(dir *api.Dir, path string)
+	addDir = func(dir *api.Dir, path string) {
+		if kinds&pathKindSecret != 0 {
+			for _, secret := range dir.Secrets {
+				paths = append(paths, path+"/"+secret.Name)
+			}
+		}
+		for _, subDir := range dir.SubDirs {
+			subDirPath := path + "/" + subDir.Name
+			if kinds&pathKindDir != 0 {
+				paths = append(paths, subDirPath)
+			}
+			addDir(subDir, subDirPath)
+		}
+	}
+	addDir(tree.RootDir, repoPath)
+
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,162 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestPathPickerSource(t *testing.T) {
+	client := fakeclient.Client{
+		RepoService: &fakeclient.RepoService{
+			ListMineFunc: func() ([]*api.Repo, error) {
+				return []*api.Repo{
+					{Owner: "namespace", Name: "b"},
+					{Owner: "namespace", Name: "a"},
+				}, nil
+			},
+		},
+		DirService: &fakeclient.DirService{
+			GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				if path == "namespace/b" {
+					return &api.Tree{RootDir: &api.Dir{}}, nil
+				}
+				return &api.Tree{
+					RootDir: &api.Dir{
+						Secrets: []*api.Secret{{Name: "key"}},
+						SubDirs: []*api.Dir{
+							{
+								Name:    "db",
+								Secrets: []*api.Secret{{Name: "password"}, {Name: "user"}},
+							},
+						},
+					},
+				}, nil
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		kinds    pathKind
+		expected [][]string
+	}{
+		"secrets": {
+			kinds: pathKindSecret,
+			expected: [][]string{
+				{"namespace/a/db/password", "namespace/a/db/user", "namespace/a/key"},
+				nil,
+			},
+		},
+		"dirs": {
+			kinds: pathKindDir,
+			expected: [][]string{
+				{"namespace/a", "namespace/b"},
+				{"namespace/a/db"},
+				nil,
+			},
+		},
+		"dirs and secrets": {
+			kinds: pathKindDir | pathKindSecret,
+			expected: [][]string{
+				{"namespace/a", "namespace/b"},
+				{"namespace/a/db", "namespace/a/db/password", "namespace/a/db/user", "namespace/a/key"},
+				nil,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			source := &pathPickerSource{
+				client: client,
+				kinds:  tc.kinds,
+			}
+
+			for i, expected := range tc.expected {
+				options, done, err := source.next()
+				assert.OK(t, err)
+
+				var paths []string
+				for _, option := range options {
+					paths = append(paths, option.Value)
+				}
+				assert.Equal(t, paths, expected)
+				assert.Equal(t, done, i == len(tc.expected)-1)
+			}
+		})
+	}
+}
+
+func TestPathPicker_pick(t *testing.T) {
+	cases := map[string]struct {
+		picker      func(io *fakeui.FakeIO) *pathPicker
+		piped       bool
+		promptErr   error
+		expected    string
+		expectedErr error
+	}{
+		"nil": {
+			picker: func(io *fakeui.FakeIO) *pathPicker {
+				return nil
+			},
+		},
+		"disabled": {
+			picker: func(io *fakeui.FakeIO) *pathPicker {
+				p := newPathPicker(io, nil, pathKindSecret)
+				p.disabled = true
+				return p
+			},
+		},
+		"piped": {
+			piped: true,
+			picker: func(io *fakeui.FakeIO) *pathPicker {
+				return newPathPicker(io, nil, pathKindSecret)
+			},
+		},
+		"cannot ask": {
+			promptErr: ui.ErrCannotAsk,
+			picker: func(io *fakeui.FakeIO) *pathPicker {
+				return newPathPicker(io, nil, pathKindSecret)
+			},
+		},
+		"pick": {
+			picker: func(io *fakeui.FakeIO) *pathPicker {
+				return newPathPicker(io, func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						RepoService: &fakeclient.RepoService{
+							ListMineFunc: func() ([]*api.Repo, error) {
+								return []*api.Repo{{Owner: "namespace", Name: "repo"}}, nil
+							},
+						},
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								return &api.Tree{RootDir: &api.Dir{Secrets: []*api.Secret{{Name: "secret"}}}}, nil
+							},
+						},
+					}, nil
+				}, pathKindSecret)
+			},
+			expected: "namespace/repo/secret",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			io.In.Piped = tc.piped
+			io.PromptErr = tc.promptErr
+			io.PromptIn.Reads = []string{"1\n"}
+
+			actual, err := tc.picker(io).pick("Which secret do you want to read?")
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
@@ -1,7 +1,8 @@
 package secrethub
 
 const (
-	repoPathPlaceHolder                  = "<namespace>/<repo>"
+	namespacePlaceHolder                 = "<namespace>"
+	repoPathPlaceHolder                  = namespacePlaceHolder + "/<repo>"
 	dirPathPlaceHolder                   = repoPathPlaceHolder + "/<dir>[/<dir> ...]"
 	dirPathsPlaceHolder                  = dirPathPlaceHolder + "..."
 	optionalDirPathPlaceHolder           = repoPathPlaceHolder + "[/<dir> ...]"
@@ -9,4 +10,5 @@ const (
 	secretPathOptionalVersionPlaceHolder = secretPathPlaceHolder + "[:<version>]"
 	generalPathPlaceHolder               = repoPathPlaceHolder + "/<path>"
 	optionalSecretPathPlaceHolder        = repoPathPlaceHolder + "[[/<dir> ...]/<secret>]"
+	namespaceOrSecretPathPlaceHolder     = namespacePlaceHolder + "[/<repo>[[/<dir> ...]/<secret>]]"
 )
@@ -0,0 +1,166 @@
+package secrethub
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// defaultProjectConfigFilename is the name of the project configuration file
+	// that is looked up in the working directory and its parents.
+	defaultProjectConfigFilename = ".secrethub.yml"
+)
+
+// Errors
+var (
+	ErrInvalidProjectConfig = errMain.Code("invalid_project_config").ErrorPref("could not parse the project configuration file %s: %s")
+)
+
+// projectConfig is the configuration of a project, read from a .secrethub.yml file.
+type projectConfig struct {
+	Hooks []hookConfig `yaml:"hooks"`
+
+	// dir is the directory containing the configuration file.
+	dir string
+}
+
+// ProjectConfig locates and reads the project configuration file.
+//
+// A project configuration file that is found in the working directory or one of its
+// parents can come from any repository the user has cloned. Because its hooks run
+// arbitrary commands, such a file is only used after the user has trusted it with
+// `secrethub config trust`. Untrusted files are not parsed at all, so that a broken or
+// hostile file in a parent directory does not affect commands that are run below it.
+// A file set with --project-config is chosen by the user and is always used.
+type ProjectConfig struct {
+	path   string
+	trust  *ProjectConfigTrust
+	logger cli.Logger
+	getwd  func() (string, error)
+
+	loaded bool
+	cfg    *projectConfig
+	err    error
+}
+
+// NewProjectConfig creates a new ProjectConfig that checks found project configuration
+// files against the given trusted files.
+func NewProjectConfig(trust *ProjectConfigTrust) *ProjectConfig {
+	return &ProjectConfig{
+		trust:  trust,
+		logger: cli.NewLogger(),
+		getwd:  os.Getwd,
+	}
+}
+
+// Register registers the flags for configuring the project configuration file on the provided app.
+func (c *ProjectConfig) Register(app *cli.App) {
+	app.PersistentFlags().StringVar(&c.path, "project-config", "", "The path to the project configuration file. Defaults to the first "+defaultProjectConfigFilename+" file found in the working directory or one of its parents, if it is trusted with `secrethub config trust`.")
+}
+
+// Get returns the project configuration. It is only read on the first call. When no
+// trusted project configuration file is found, an empty configuration is returned.
+func (c *ProjectConfig) Get() (*projectConfig, error) {
+	if !c.loaded {
+		c.cfg, c.err = c.load()
+		c.loaded = true
+	}
+	return c.cfg, c.err
+}
+
+// load reads the project configuration set with --project-config or the trusted
+// project configuration file found in the working directory or one of its parents.
+func (c *ProjectConfig) load() (*projectConfig, error) {
+	if c.path != "" {
+		return readProjectConfig(c.path)
+	}
+
+	wd, err := c.getwd()
+	if err != nil {
+		return nil, ErrCannotGetWorkingDir(err)
+	}
+
+	path := findProjectConfig(wd)
+	if path == "" {
+		return &projectConfig{}, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+
+	trusted, changed, err := c.trust.check(path, raw)
+	if err != nil {
+		return nil, err
+	}
+	if !trusted {
+		reason := "is not trusted"
+		if changed {
+			reason = "has changed since it was trusted"
+		}
+		c.logger.Warningf("the hooks in %s are not run, because the file %s. Review the file and run `secrethub config trust %s` to run its hooks.", path, reason, path)
+		return &projectConfig{}, nil
+	}
+
+	return parseProjectConfig(path, raw)
+}
+
+// findProjectConfig returns the absolute path of the first project configuration file found
+// in the given absolute directory or one of its parents. It returns an empty string when no
+// project configuration file is found.
+func findProjectConfig(dir string) string {
+	for {
+		path := filepath.Join(dir, defaultProjectConfigFilename)
+		info, err := os.Stat(path)
+		if err == nil && !info.IsDir() {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// readProjectConfig reads and parses the project configuration file at the given path.
+func readProjectConfig(path string) (*projectConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+	return parseProjectConfig(path, raw)
+}
+
+// parseProjectConfig parses the contents of the project configuration file at the given path.
+func parseProjectConfig(path string, raw []byte) (*projectConfig, error) {
+	cfg := &projectConfig{}
+	err := yaml.UnmarshalStrict(raw, cfg)
+	if err != nil {
+		return nil, ErrInvalidProjectConfig(path, err)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg.dir = filepath.Dir(absPath)
+
+	for i, hook := range cfg.Hooks {
+		err = hook.validate()
+		if err != nil {
+			return nil, ErrInvalidProjectConfig(path, err)
+		}
+		if hook.When == "" {
+			cfg.Hooks[i].When = hookPost
+		}
+	}
+
+	return cfg, nil
+}
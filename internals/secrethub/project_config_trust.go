@@ -0,0 +1,96 @@
+package secrethub
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+)
+
+const trustedProjectConfigsFileName = "trusted_project_configs.json"
+
+// Errors
+var (
+	ErrInvalidProjectConfigTrust = errMain.Code("invalid_project_config_trust").ErrorPref("could not parse the trusted project configurations file %s: %s")
+)
+
+// ProjectConfigTrust keeps track of the project configuration files that the user has reviewed
+// and allowed to run hooks. A file is trusted by the SHA-256 hash of its contents, so any change
+// to a trusted file has to be reviewed again.
+type ProjectConfigTrust struct {
+	dir func() string
+}
+
+// NewProjectConfigTrust creates a new ProjectConfigTrust that stores the trusted files in the
+// configuration directory of the given credential config.
+func NewProjectConfigTrust(store CredentialConfig) *ProjectConfigTrust {
+	return &ProjectConfigTrust{
+		dir: func() string {
+			return store.ConfigDir().Path()
+		},
+	}
+}
+
+// path returns the location of the file with the trusted project configurations.
+func (t *ProjectConfigTrust) path() string {
+	return filepath.Join(t.dir(), trustedProjectConfigsFileName)
+}
+
+// hashes returns the hash of the trusted contents of every trusted file, keyed by absolute path.
+func (t *ProjectConfigTrust) hashes() (map[string]string, error) {
+	raw, err := os.ReadFile(t.path())
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, ErrCannotReadFile(t.path(), err)
+	}
+
+	hashes := map[string]string{}
+	err = json.Unmarshal(raw, &hashes)
+	if err != nil {
+		return nil, ErrInvalidProjectConfigTrust(t.path(), err)
+	}
+	return hashes, nil
+}
+
+// check returns whether the file at the given absolute path is trusted with the given contents
+// and whether it was trusted before with other contents.
+func (t *ProjectConfigTrust) check(path string, raw []byte) (trusted bool, changed bool, err error) {
+	hashes, err := t.hashes()
+	if err != nil {
+		return false, false, err
+	}
+	hash, ok := hashes[path]
+	if !ok {
+		return false, false, nil
+	}
+	if hash != projectConfigHash(raw) {
+		return false, true, nil
+	}
+	return true, false, nil
+}
+
+// trust allows the file at the given absolute path to run hooks, as long as it has the given contents.
+func (t *ProjectConfigTrust) trust(path string, raw []byte) error {
+	hashes, err := t.hashes()
+	if err != nil {
+		return err
+	}
+	hashes[path] = projectConfigHash(raw)
+
+	encoded, err := json.MarshalIndent(hashes, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(t.path(), encoded, 0600)
+}
+
+// projectConfigHash returns the hash by which the contents of a project configuration file are trusted.
+func projectConfigHash(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,212 @@
+package secrethub
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrMissingProxyAllow      = errMain.Code("missing_proxy_allow").Error("at least one --allow must be given")
+	ErrInvalidProxyAllow      = errMain.Code("invalid_proxy_allow").ErrorPref("invalid value for --allow: %s")
+	ErrProxyListenNotLoopback = errMain.Code("proxy_listen_not_loopback").ErrorPref("invalid value for --listen: %s: the proxy can only listen on a loopback address, such as 127.0.0.1")
+
+	ErrProxyNotLoopback    = errMain.Code("proxy_not_loopback").StatusError("only requests from localhost are accepted", http.StatusForbidden)
+	ErrProxyPathRequired   = errMain.Code("proxy_path_required").StatusError("the path query parameter is required", http.StatusBadRequest)
+	ErrProxyPathNotAllowed = errMain.Code("proxy_path_not_allowed").StatusError("the secret is not allowed by the --allow flags of the proxy", http.StatusForbidden)
+)
+
+// ProxyCommand serves an HTTP API on localhost through which applications can read secrets.
+type ProxyCommand struct {
+	listen    string
+	allow     []string
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewProxyCommand creates a new ProxyCommand.
+func NewProxyCommand(io ui.IO, newClient newClientFunc) *ProxyCommand {
+	return &ProxyCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ProxyCommand) Register(r cli.Registerer) {
+	clause := r.Command("proxy", "Serve an HTTP API on localhost through which applications can read secrets.")
+	clause.HelpLong("The proxy reads secrets with the configured credential, so that applications on the same host or in the same pod can read secrets at runtime without a SecretHub client library. " +
+		"Secrets are read with:\n\n" +
+		"    GET /v1/secret?path=my-org/my-repo/my-secret\n\n" +
+		"which returns {\"path\": \"...\", \"version\": 1, \"data\": \"...\"}. A version can be given with path=my-org/my-repo/my-secret:1.\n\n" +
+		"Only secrets that match an --allow pattern can be read. " +
+		"In a pattern, * matches any sequence of characters except / and a trailing /** matches everything below a directory.\n\n" +
+		"The proxy does not authenticate requests, so it only listens on a loopback address and only accepts requests from localhost. " +
+		"Everyone who can connect to the proxy can read the allowed secrets.")
+	clause.Flags().StringVar(&cmd.listen, "listen", "127.0.0.1:8099", "The loopback address and port to listen on.")
+	clause.Flags().StringArrayVar(&cmd.allow, "allow", nil, "A pattern of secret paths that can be read, e.g. my-org/my-repo/*. Can be repeated.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run serves the API until interrupted.
+func (cmd *ProxyCommand) Run() error {
+	if len(cmd.allow) == 0 {
+		return ErrMissingProxyAllow
+	}
+	for _, pattern := range cmd.allow {
+		_, err := path.Match(strings.TrimSuffix(pattern, "/**"), "")
+		if err != nil {
+			return ErrInvalidProxyAllow(pattern)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(cmd.listen)
+	if err != nil {
+		return ErrProxyListenNotLoopback(cmd.listen)
+	}
+	if !isLoopbackHost(host) {
+		return ErrProxyListenNotLoopback(cmd.listen)
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", cmd.listen)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{
+		Handler: newProxyServer(client, cmd.allow).handler(),
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "The proxy is listening on http://%s.\n", listener.Addr())
+	return serveHTTP(ctx, server, func() error {
+		return server.Serve(listener)
+	})
+}
+
+// proxyResponse is the response of the secret endpoint of the proxy.
+type proxyResponse struct {
+	Path    string `json:"path"`
+	Version int    `json:"version"`
+	Data    string `json:"data"`
+}
+
+// proxyServer serves the API of the proxy.
+type proxyServer struct {
+	client secrethub.ClientInterface
+	allow  []string
+
+	// mutex serializes the requests to the client.
+	mutex sync.Mutex
+}
+
+// newProxyServer creates a server that reads the secrets that match one of the allow patterns with the client.
+func newProxyServer(client secrethub.ClientInterface, allow []string) *proxyServer {
+	return &proxyServer{
+		client: client,
+		allow:  allow,
+	}
+}
+
+// handler returns the handler for all endpoints of the proxy.
+func (s *proxyServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/secret", jsonEndpoint(http.MethodGet, s.handleSecret))
+	return mux
+}
+
+func (s *proxyServer) handleSecret(r *http.Request) (interface{}, error) {
+	// The Host header is checked as well, so that websites cannot read secrets through DNS rebinding.
+	remoteHost, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil || !isLoopbackHost(remoteHost) || !isLoopbackHost(hostWithoutPort(r.Host)) {
+		return nil, ErrProxyNotLoopback
+	}
+
+	secretPath := r.URL.Query().Get("path")
+	if secretPath == "" {
+		return nil, ErrProxyPathRequired
+	}
+
+	_, err = api.NewSecretPath(secretPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// A version suffix is not part of the path that is matched against the patterns.
+	if !s.allowed(strings.SplitN(secretPath, ":", 2)[0]) {
+		return nil, ErrProxyPathNotAllowed
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	version, err := s.client.Secrets().Versions().GetWithData(secretPath)
+	if err != nil {
+		return nil, err
+	}
+	return proxyResponse{
+		Path:    secretPath,
+		Version: version.Version,
+		Data:    string(version.Data),
+	}, nil
+}
+
+// allowed returns whether the secret path matches one of the allow patterns.
+func (s *proxyServer) allowed(secretPath string) bool {
+	for _, pattern := range s.allow {
+		if strings.HasSuffix(pattern, "/**") {
+			if strings.HasPrefix(secretPath, strings.TrimSuffix(pattern, "**")) {
+				return true
+			}
+			continue
+		}
+
+		matched, err := path.Match(pattern, secretPath)
+		if err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// isLoopbackHost returns whether the host is localhost or a loopback IP address.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// hostWithoutPort returns the host of a Host header, which may or may not include a port.
+func hostWithoutPort(hostport string) string {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return strings.Trim(hostport, "[]")
+	}
+	return host
+}
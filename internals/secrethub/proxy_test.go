@@ -0,0 +1,141 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestProxyServer(t *testing.T) {
+	client := fakeclient.Client{
+		SecretService: &fakeclient.SecretService{
+			VersionService: &fakeclient.SecretVersionService{
+				GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+					return &api.SecretVersion{Version: 3, Data: []byte("value of " + path)}, nil
+				},
+			},
+		},
+	}
+	handler := newProxyServer(client, []string{"namespace/repo/*", "namespace/other/dir/**"}).handler()
+
+	cases := map[string]struct {
+		method     string
+		target     string
+		remoteAddr string
+		host       string
+		status     int
+		expected   interface{}
+	}{
+		"success": {
+			target: "/v1/secret?path=namespace/repo/secret",
+			status: http.StatusOK,
+			expected: proxyResponse{
+				Path:    "namespace/repo/secret",
+				Version: 3,
+				Data:    "value of namespace/repo/secret",
+			},
+		},
+		"version": {
+			target: "/v1/secret?path=namespace/repo/secret:3",
+			status: http.StatusOK,
+			expected: proxyResponse{
+				Path:    "namespace/repo/secret:3",
+				Version: 3,
+				Data:    "value of namespace/repo/secret:3",
+			},
+		},
+		"nested": {
+			target: "/v1/secret?path=namespace/other/dir/sub/secret",
+			status: http.StatusOK,
+			expected: proxyResponse{
+				Path:    "namespace/other/dir/sub/secret",
+				Version: 3,
+				Data:    "value of namespace/other/dir/sub/secret",
+			},
+		},
+		"not allowed": {
+			target:   "/v1/secret?path=namespace/repo/dir/secret",
+			status:   http.StatusForbidden,
+			expected: jsonErrorResponse{Code: ErrProxyPathNotAllowed.Code, Message: ErrProxyPathNotAllowed.Message},
+		},
+		"missing path": {
+			target:   "/v1/secret",
+			status:   http.StatusBadRequest,
+			expected: jsonErrorResponse{Code: ErrProxyPathRequired.Code, Message: ErrProxyPathRequired.Message},
+		},
+		"remote": {
+			target:     "/v1/secret?path=namespace/repo/secret",
+			remoteAddr: "192.0.2.1:1234",
+			status:     http.StatusForbidden,
+			expected:   jsonErrorResponse{Code: ErrProxyNotLoopback.Code, Message: ErrProxyNotLoopback.Message},
+		},
+		"rebinding": {
+			target:   "/v1/secret?path=namespace/repo/secret",
+			host:     "example.com",
+			status:   http.StatusForbidden,
+			expected: jsonErrorResponse{Code: ErrProxyNotLoopback.Code, Message: ErrProxyNotLoopback.Message},
+		},
+		"method": {
+			method:   http.MethodPost,
+			target:   "/v1/secret?path=namespace/repo/secret",
+			status:   http.StatusMethodNotAllowed,
+			expected: jsonErrorResponse{Code: "method_not_allowed", Message: "POST is not allowed, use GET"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tc.target, nil)
+			req.RemoteAddr = "127.0.0.1:1234"
+			if tc.remoteAddr != "" {
+				req.RemoteAddr = tc.remoteAddr
+			}
+			req.Host = "localhost:8099"
+			if tc.host != "" {
+				req.Host = tc.host
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, rec.Code, tc.status)
+			switch tc.expected.(type) {
+			case proxyResponse:
+				var actual proxyResponse
+				assert.OK(t, json.NewDecoder(rec.Body).Decode(&actual))
+				assert.Equal(t, actual, tc.expected)
+			case jsonErrorResponse:
+				var actual jsonErrorResponse
+				assert.OK(t, json.NewDecoder(rec.Body).Decode(&actual))
+				assert.Equal(t, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIsLoopbackHost(t *testing.T) {
+	cases := map[string]bool{
+		"localhost":   true,
+		"127.0.0.1":   true,
+		"127.1.2.3":   true,
+		"::1":         true,
+		"0.0.0.0":     false,
+		"192.0.2.1":   false,
+		"example.com": false,
+	}
+
+	for host, expected := range cases {
+		t.Run(host, func(t *testing.T) {
+			assert.Equal(t, isLoopbackHost(host), expected)
+		})
+	}
+}
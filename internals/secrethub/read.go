@@ -7,7 +7,7 @@ import (
 	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/clip"
 	"github.com/secrethub/secrethub-cli/internals/cli/filemode"
-	"github.com/secrethub/secrethub-cli/internals/cli/posix"
+	"github.com/secrethub/secrethub-cli/internals/cli/secval"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 
 	"github.com/secrethub/secrethub-go/internals/api"
@@ -15,21 +15,38 @@ import (
 	"github.com/docker/go-units"
 )
 
+// Errors
+var (
+	ErrBinaryToTerminal = errMain.Code("binary_to_terminal").Error("refusing to print binary data to the terminal: use --out-file, redirect the output or use --force")
+)
+
 // ReadCommand is a command to read a secret.
 type ReadCommand struct {
 	io            ui.IO
 	path          api.SecretPath
+	paths         cli.StringListValue
+	many          bool
+	pathsFile     string
+	format        string
+	concurrency   int
 	useClipboard  bool
 	outFile       string
 	fileMode      filemode.FileMode
 	noNewLine     bool
+	key           string
+	binary        bool
+	force         bool
 	newClient     newClientFunc
 	writeFileFunc func(filename string, data []byte, perm os.FileMode) error
 	clipWriter    ClipboardWriter
+	batch         bool
+	cache         *SecretCache
+	picker        *pathPicker
+	completion    *CompletionCache
 }
 
 // NewReadCommand creates a new ReadCommand.
-func NewReadCommand(io ui.IO, newClient newClientFunc) *ReadCommand {
+func NewReadCommand(io ui.IO, newClient newClientFunc, cache *SecretCache, completion *CompletionCache) *ReadCommand {
 	return &ReadCommand{
 		clipWriter: &ClipboardWriterAutoClear{
 			clipper: clip.NewClipboard(),
@@ -38,6 +55,11 @@ func NewReadCommand(io ui.IO, newClient newClientFunc) *ReadCommand {
 		newClient:     newClient,
 		writeFileFunc: os.WriteFile,
 		fileMode:      filemode.New(0600),
+		cache:         cache,
+		picker:        newPathPicker(io, newClient, pathKindSecret),
+		completion:    completion,
+		format:        readFormatJSON,
+		concurrency:   defaultEnvConcurrency,
 	}
 }
 
@@ -54,26 +76,71 @@ func (cmd *ReadCommand) Register(r cli.Registerer) {
 	)
 	clause.Flags().StringVarP(&cmd.outFile, "out-file", "o", "", "Write the secret value to this file.")
 	clause.Flags().BoolVarP(&cmd.noNewLine, "no-newline", "n", false, "Do not print a new line after the secret")
+	clause.Flags().StringVar(&cmd.key, "key", "", "Only read this field of a secret that contains a JSON or YAML document. Nested fields can be selected with a path like db.password or a JSONPath like $.hosts[0].name.")
+	clause.Flags().BoolVar(&cmd.binary, "binary", false, "Read a secret that was written with `secrethub write --binary`. The data is decoded and its checksum is validated.")
+	clause.Flags().BoolVarP(&cmd.force, "force", "f", false, "With --binary, print the data even when the output is a terminal.")
 	clause.Flags().VarPF(&cmd.fileMode, "file-mode", "", "Set filemode for the output file. It is ignored without the --out-file flag.")
+	clause.Flags().BoolVar(&cmd.many, "many", false, "Read all secrets given as arguments or in --paths-file concurrently. Their values are written in the format set with --format once all of them have been read.")
+	clause.Flags().StringVar(&cmd.pathsFile, "paths-file", "", "Read the secrets at the paths in this file, one per line. Empty lines and lines starting with # are skipped. Implies --many.")
+	clause.Flags().StringVar(&cmd.format, "format", readFormatJSON, "The format in which the secrets are written with --many. The options are json (an object with the values by path), dotenv (a variable for every secret, named after the secret) and tsv (a line with the path and the escaped value for every secret).")
+	clause.Flags().IntVar(&cmd.concurrency, "concurrency", defaultEnvConcurrency, "The maximum number of secrets that are read concurrently with --many.")
+	registerStdinNullDelimitedFlag(clause, &cmd.batch, &cmd.pathsFile)
+	cmd.cache.register(clause)
+	cmd.picker.register(clause)
+	cmd.completion.registerArgument(clause, pathKindSecret)
 
 	clause.BindAction(cmd.Run)
-	clause.BindArguments([]cli.Argument{{Value: &cmd.path, Name: "path", Placeholder: secretPathOptionalVersionPlaceHolder, Required: true, Description: "The path to the secret."}})
+	clause.BindArgumentsArr(cli.Argument{Value: &cmd.paths, Name: "path", Placeholder: secretPathOptionalVersionPlaceHolder, Required: false, Description: "The path to the secret. Multiple paths can be given with --many. Required unless --stdin-null-delimited or --paths-file is set or the command is run in a terminal, in which case a secret can be picked interactively."})
 }
 
 // Run handles the command with the options as specified in the command.
 func (cmd *ReadCommand) Run() error {
-	client, err := cmd.newClient()
+	if cmd.batch {
+		return cmd.runBatch()
+	}
+	if cmd.many || cmd.pathsFile != "" {
+		return cmd.runMany()
+	}
+	if len(cmd.paths) > 1 {
+		return ErrMultiplePathsNotMany
+	}
+	if len(cmd.paths) == 1 {
+		err := cmd.path.Set(cmd.paths[0])
+		if err != nil {
+			return err
+		}
+	}
+	if cmd.path == "" {
+		path, err := cmd.picker.pick("Which secret do you want to read?")
+		if err != nil {
+			return err
+		}
+		if path != "" {
+			err = cmd.path.Set(path)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if cmd.binary {
+		return cmd.runBinary()
+	}
+
+	data, err := cmd.readSecret(cmd.path.Value())
 	if err != nil {
 		return err
 	}
+	secret := secval.New(data)
+	defer secret.Wipe()
 
-	secret, err := client.Secrets().Versions().GetWithData(cmd.path.Value())
+	value, err := cmd.extractKey(secret)
 	if err != nil {
 		return err
 	}
+	defer value.Wipe()
 
 	if cmd.useClipboard {
-		err = cmd.clipWriter.Write(secret.Data)
+		err = cmd.clipWriter.Write(value.Bytes())
 		if err != nil {
 			return err
 		}
@@ -86,21 +153,125 @@ func (cmd *ReadCommand) Run() error {
 		)
 	}
 
-	secretData := secret.Data
+	out := value
 	if !cmd.noNewLine {
-		secretData = posix.AddNewLine(secretData)
+		out = value.WithNewLine()
+		defer out.Wipe()
 	}
 
 	if cmd.outFile != "" {
-		err = cmd.writeFileFunc(cmd.outFile, secretData, cmd.fileMode.FileMode())
+		err = cmd.writeFileFunc(cmd.outFile, out.Bytes(), cmd.fileMode.FileMode())
 		if err != nil {
 			return ErrCannotWrite(cmd.outFile, err)
 		}
 	}
 
 	if cmd.outFile == "" && !cmd.useClipboard {
-		_, _ = fmt.Fprintf(cmd.io.Output(), "%s", string(secretData))
+		_, _ = cmd.io.Output().Write(out.Bytes())
 	}
 
 	return nil
 }
+
+// runBatch reads the secrets at the NUL-delimited paths on stdin and
+// writes their values to the output as lines of JSON.
+func (cmd *ReadCommand) runBatch() error {
+	if cmd.useClipboard {
+		return ErrFlagsConflict("--stdin-null-delimited and --clip")
+	}
+	if cmd.outFile != "" {
+		return ErrFlagsConflict("--stdin-null-delimited and --out-file")
+	}
+	if cmd.binary {
+		return ErrFlagsConflict("--stdin-null-delimited and --binary")
+	}
+
+	return runBatch(cmd.io.Input(), cmd.io.Output(), func(path string) (*string, error) {
+		secretPath, err := api.NewSecretPath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := cmd.readSecret(secretPath.Value())
+		if err != nil {
+			return nil, err
+		}
+		if cmd.key != "" {
+			data, err = extractKey(data, cmd.key)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// The value is written as a string, which cannot be wiped, so it is left to the garbage collector.
+		value := string(data)
+		return &value, nil
+	})
+}
+
+// runBinary reads a secret that was written with --binary and writes the decoded data to
+// the output or file. The data is only printed to a terminal with --force.
+func (cmd *ReadCommand) runBinary() error {
+	if cmd.useClipboard {
+		return ErrFlagsConflict("--binary and --clip")
+	}
+	if cmd.key != "" {
+		return ErrFlagsConflict("--binary and --key")
+	}
+	if cmd.outFile == "" && !cmd.io.IsOutputPiped() && !cmd.force {
+		return ErrBinaryToTerminal
+	}
+
+	value, err := cmd.readSecret(cmd.path.Value())
+	if err != nil {
+		return err
+	}
+	secret := secval.New(value)
+	defer secret.Wipe()
+
+	decoded, err := decodeBinarySecret(secret.Bytes())
+	if err != nil {
+		return err
+	}
+	data := secval.New(decoded)
+	defer data.Wipe()
+
+	if cmd.outFile != "" {
+		err = cmd.writeFileFunc(cmd.outFile, data.Bytes(), cmd.fileMode.FileMode())
+		if err != nil {
+			return ErrCannotWrite(cmd.outFile, err)
+		}
+		return nil
+	}
+
+	_, err = cmd.io.Output().Write(data.Bytes())
+	return err
+}
+
+// extractKey returns the field selected with --key from the secret, or the whole secret when no key is set.
+func (cmd *ReadCommand) extractKey(secret *secval.Secret) (*secval.Secret, error) {
+	if cmd.key == "" {
+		return secret, nil
+	}
+	data, err := extractKey(secret.Bytes(), cmd.key)
+	if err != nil {
+		return nil, err
+	}
+	return secval.New(data), nil
+}
+
+// readSecret reads the secret at the given path, through the secret cache if it is enabled.
+func (cmd *ReadCommand) readSecret(path string) ([]byte, error) {
+	return cmd.cache.read(path, func() ([]byte, error) {
+		client, err := cmd.newClient()
+		if err != nil {
+			return nil, err
+		}
+
+		secret, err := client.Secrets().Versions().GetWithData(path)
+		if err != nil {
+			return nil, err
+		}
+		return secret.Data, nil
+	})
+}
@@ -0,0 +1,172 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Errors
+var (
+	ErrInvalidKeyExpression = errMain.Code("invalid_key_expression").ErrorPref("invalid value for --key: %s: %s")
+	ErrSecretNotStructured  = errMain.Code("secret_not_structured").Error("the secret is not a JSON or YAML object or array, so --key cannot be used")
+	ErrKeyNotFound          = errMain.Code("key_not_found").ErrorPref("%s not found in the secret")
+)
+
+// keySegment is a part of a key expression, which selects either a field of an object or an element of an array.
+type keySegment struct {
+	field   string
+	index   int
+	isIndex bool
+}
+
+func (s keySegment) String() string {
+	if s.isIndex {
+		return "[" + strconv.Itoa(s.index) + "]"
+	}
+	return "." + s.field
+}
+
+// parseKeyExpression parses a key expression, which is either the name of a field, a path of
+// fields separated by dots, e.g. db.password, or a JSONPath of fields and array indices, e.g.
+// $.hosts[0].name or $['field.with.dots'].
+func parseKeyExpression(expression string) ([]keySegment, error) {
+	rest := strings.TrimPrefix(expression, "$")
+	if rest == "" {
+		return nil, ErrInvalidKeyExpression(expression, "no fields are selected")
+	}
+
+	var segments []keySegment
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "['") || strings.HasPrefix(rest, `["`):
+			quote := rest[1:2]
+			end := strings.Index(rest[2:], quote+"]")
+			if end < 0 {
+				return nil, ErrInvalidKeyExpression(expression, "unterminated quoted field")
+			}
+			segments = append(segments, keySegment{field: rest[2 : 2+end]})
+			rest = rest[2+end+2:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, ErrInvalidKeyExpression(expression, "unterminated index")
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, ErrInvalidKeyExpression(expression, "an index must be a non-negative number")
+			}
+			segments = append(segments, keySegment{index: index, isIndex: true})
+			rest = rest[end+1:]
+		default:
+			// The dot is optional before the first field, so that a field name can be given as is.
+			if strings.HasPrefix(rest, ".") {
+				rest = rest[1:]
+			} else if len(segments) > 0 || strings.HasPrefix(expression, "$") {
+				return nil, ErrInvalidKeyExpression(expression, "fields must be separated by dots")
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, ErrInvalidKeyExpression(expression, "empty field name")
+			}
+			segments = append(segments, keySegment{field: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+	return segments, nil
+}
+
+// extractKey returns the value selected by the key expression from the JSON or YAML document.
+// Strings are returned as is and other values are returned as JSON.
+func extractKey(data []byte, expression string) ([]byte, error) {
+	segments, err := parseKeyExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Numbers are decoded as they are written, so that they are not rounded.
+	decoder.UseNumber()
+	err = decoder.Decode(&document)
+	if err != nil {
+		// YAML is a superset of JSON, so it is only tried when the secret is not JSON.
+		err = yaml.Unmarshal(data, &document)
+		if err != nil {
+			return nil, ErrSecretNotStructured
+		}
+	}
+
+	switch document.(type) {
+	case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+	default:
+		return nil, ErrSecretNotStructured
+	}
+
+	value := document
+	selected := "$"
+	for _, segment := range segments {
+		selected += segment.String()
+
+		var found bool
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if !segment.isIndex {
+				value, found = v[segment.field]
+			}
+		case map[interface{}]interface{}:
+			if !segment.isIndex {
+				value, found = v[segment.field]
+			}
+		case []interface{}:
+			if segment.isIndex && segment.index < len(v) {
+				value, found = v[segment.index], true
+			}
+		}
+		if !found {
+			return nil, ErrKeyNotFound(selected)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	case nil:
+		return []byte{}, nil
+	default:
+		return json.Marshal(jsonCompatible(v))
+	}
+}
+
+// jsonCompatible converts the maps that are decoded from YAML to maps that can be encoded as JSON.
+func jsonCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			result[fmt.Sprint(key)] = jsonCompatible(elem)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			result[key] = jsonCompatible(elem)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, elem := range v {
+			result[i] = jsonCompatible(elem)
+		}
+		return result
+	default:
+		return v
+	}
+}
@@ -0,0 +1,91 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestExtractKey(t *testing.T) {
+	const jsonSecret = `{"username": "admin", "password": "secret", "port": 5432, "hosts": [{"name": "db1"}, {"name": "db2"}], "tls.enabled": true, "options": {"ssl": "require"}}`
+	const yamlSecret = "username: admin\nhosts:\n  - name: db1\noptions:\n  ssl: require\n"
+
+	cases := map[string]struct {
+		data        string
+		key         string
+		expected    string
+		expectedErr error
+	}{
+		"field": {
+			data:     jsonSecret,
+			key:      "password",
+			expected: "secret",
+		},
+		"number": {
+			data:     jsonSecret,
+			key:      "port",
+			expected: "5432",
+		},
+		"object": {
+			data:     jsonSecret,
+			key:      "options",
+			expected: `{"ssl":"require"}`,
+		},
+		"nested field": {
+			data:     jsonSecret,
+			key:      "options.ssl",
+			expected: "require",
+		},
+		"jsonpath": {
+			data:     jsonSecret,
+			key:      "$.hosts[1].name",
+			expected: "db2",
+		},
+		"quoted field": {
+			data:     jsonSecret,
+			key:      "$['tls.enabled']",
+			expected: "true",
+		},
+		"yaml": {
+			data:     yamlSecret,
+			key:      "hosts[0].name",
+			expected: "db1",
+		},
+		"yaml object": {
+			data:     yamlSecret,
+			key:      "options",
+			expected: `{"ssl":"require"}`,
+		},
+		"not found": {
+			data:        jsonSecret,
+			key:         "hosts[2].name",
+			expectedErr: ErrKeyNotFound("$.hosts[2]"),
+		},
+		"not structured": {
+			data:        "just a password",
+			key:         "password",
+			expectedErr: ErrSecretNotStructured,
+		},
+		"invalid index": {
+			data:        jsonSecret,
+			key:         "hosts[first]",
+			expectedErr: ErrInvalidKeyExpression("hosts[first]", "an index must be a non-negative number"),
+		},
+		"missing dot": {
+			data:        jsonSecret,
+			key:         "$hosts",
+			expectedErr: ErrInvalidKeyExpression("$hosts", "fields must be separated by dots"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := extractKey([]byte(tc.data), tc.key)
+
+			assert.Equal(t, err, tc.expectedErr)
+			if tc.expectedErr == nil {
+				assert.Equal(t, string(actual), tc.expected)
+			}
+		})
+	}
+}
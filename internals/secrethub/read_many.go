@@ -0,0 +1,258 @@
+package secrethub
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/secval"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrUnknownReadFormat    = errMain.Code("unknown_read_format").ErrorPref("unknown format %s: the options are json, dotenv and tsv")
+	ErrMultiplePathsNotMany = errMain.Code("multiple_paths").Error("only one secret can be read at a time: set --many to read multiple secrets")
+	ErrDuplicateReadName    = errMain.Code("duplicate_read_name").ErrorPref("both %s and %s are written as %s: the names of the secrets must be unique with --format=dotenv")
+)
+
+// Output formats of reading many secrets.
+const (
+	readFormatJSON   = "json"
+	readFormatDotEnv = "dotenv"
+	readFormatTSV    = "tsv"
+)
+
+// readPath is a secret to read and its value, once it has been read.
+type readPath struct {
+	path  string
+	value *secval.Secret
+}
+
+// runMany reads the secrets at the paths given as arguments and in the paths file concurrently.
+// When all secrets have been read, they are written to the output in the selected format.
+// When any secret cannot be read, nothing is written and the first error is returned.
+func (cmd *ReadCommand) runMany() error {
+	if cmd.useClipboard {
+		return ErrFlagsConflict("--many and --clip")
+	}
+	if cmd.binary {
+		return ErrFlagsConflict("--many and --binary")
+	}
+	if cmd.format != readFormatJSON && cmd.format != readFormatDotEnv && cmd.format != readFormatTSV {
+		return ErrUnknownReadFormat(cmd.format)
+	}
+
+	paths := append([]string{}, cmd.paths...)
+	if cmd.pathsFile != "" {
+		fromFile, err := readPathsFile(cmd.pathsFile)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, fromFile...)
+	}
+	if len(paths) == 0 {
+		return ErrPathRequired
+	}
+
+	secrets := make([]*readPath, len(paths))
+	for i, path := range paths {
+		_, err := api.NewSecretPath(path)
+		if err != nil {
+			return err
+		}
+		secrets[i] = &readPath{path: path}
+	}
+	defer func() {
+		for _, secret := range secrets {
+			if secret.value != nil {
+				secret.value.Wipe()
+			}
+		}
+	}()
+
+	err := cmd.readConcurrently(secrets)
+	if err != nil {
+		return err
+	}
+
+	out, err := cmd.formatMany(secrets)
+	if err != nil {
+		return err
+	}
+	defer out.Wipe()
+
+	if cmd.outFile != "" {
+		err = cmd.writeFileFunc(cmd.outFile, out.Bytes(), cmd.fileMode.FileMode())
+		if err != nil {
+			return ErrCannotWrite(cmd.outFile, err)
+		}
+		return nil
+	}
+
+	_, err = cmd.io.Output().Write(out.Bytes())
+	return err
+}
+
+// readConcurrently reads the values of the given secrets, with up to cmd.concurrency secrets at the same time.
+// When a secret cannot be read, no new secrets are read and the first error that occurred is returned.
+func (cmd *ReadCommand) readConcurrently(secrets []*readPath) error {
+	workers := cmd.concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(secrets) {
+		workers = len(secrets)
+	}
+
+	// The client is created once, instead of by every worker.
+	newClient := cmd.newClient
+	var clientOnce sync.Once
+	var client secrethub.ClientInterface
+	var clientErr error
+	read := &ReadCommand{
+		key:   cmd.key,
+		cache: cmd.cache,
+		newClient: func() (secrethub.ClientInterface, error) {
+			clientOnce.Do(func() {
+				client, clientErr = newClient()
+			})
+			return client, clientErr
+		},
+	}
+
+	var firstErr error
+	var failOnce sync.Once
+	failed := make(chan struct{})
+
+	jobs := make(chan *readPath)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for secret := range jobs {
+				value, err := read.readValue(secret.path)
+				if err != nil {
+					failOnce.Do(func() {
+						firstErr = err
+						close(failed)
+					})
+					return
+				}
+				secret.value = value
+			}
+		}()
+	}
+
+dispatch:
+	for _, secret := range secrets {
+		select {
+		case jobs <- secret:
+		case <-failed:
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return firstErr
+}
+
+// readValue reads the secret at the given path and returns the field selected with --key.
+func (cmd *ReadCommand) readValue(path string) (*secval.Secret, error) {
+	data, err := cmd.readSecret(path)
+	if err != nil {
+		return nil, err
+	}
+	secret := secval.New(data)
+
+	value, err := cmd.extractKey(secret)
+	if err != nil {
+		secret.Wipe()
+		return nil, err
+	}
+	if value != secret {
+		secret.Wipe()
+	}
+	return value, nil
+}
+
+// formatMany formats the secrets that have been read in the selected format.
+func (cmd *ReadCommand) formatMany(secrets []*readPath) (*secval.Secret, error) {
+	var buf strings.Builder
+	switch cmd.format {
+	case readFormatJSON:
+		values := make(map[string]string, len(secrets))
+		for _, secret := range secrets {
+			values[secret.path] = string(secret.value.Bytes())
+		}
+		err := exportJSON(&buf, values)
+		if err != nil {
+			return nil, err
+		}
+	case readFormatDotEnv:
+		names := make(map[string]string, len(secrets))
+		for _, secret := range secrets {
+			name := readEnvVarName(secret.path)
+			if other, ok := names[name]; ok && other != secret.path {
+				return nil, ErrDuplicateReadName(other, secret.path, name)
+			}
+			names[name] = secret.path
+			fmt.Fprintln(&buf, exportDotEnv(name, string(secret.value.Bytes())))
+		}
+	case readFormatTSV:
+		escaper := strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+		for _, secret := range secrets {
+			fmt.Fprintf(&buf, "%s\t%s\n", secret.path, escaper.Replace(string(secret.value.Bytes())))
+		}
+	}
+	return secval.New([]byte(buf.String())), nil
+}
+
+// readEnvVarName returns the name of the variable that the secret at the given path is written as
+// with --format=dotenv: the name of the secret in upper case, with dashes and dots replaced by underscores.
+func readEnvVarName(path string) string {
+	name := path
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.NewReplacer("-", "_", ".", "_").Replace(name)
+	return strings.ToUpper(name)
+}
+
+// readPathsFile returns the paths in the given file, one per line. Empty lines and lines starting with # are skipped.
+func readPathsFile(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, ErrReadFile(filename, err)
+	}
+	defer f.Close()
+	return parsePaths(f, filename)
+}
+
+// parsePaths returns the paths read from r, one per line. Empty lines and lines starting with # are skipped.
+// The name of the source is used in errors.
+func parsePaths(r io.Reader, name string) ([]string, error) {
+	var paths []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, ErrReadFile(name, err)
+	}
+	return paths, nil
+}
@@ -0,0 +1,103 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestReadCommand_runMany(t *testing.T) {
+	secrets := map[string]string{
+		"test/repo/db-user":     "admin",
+		"test/repo/db-password": "pa\"ss\tword\n",
+		"test/other/db-user":    "root",
+	}
+
+	cases := map[string]struct {
+		paths       cli.StringListValue
+		format      string
+		expectedOut string
+		expectedErr error
+	}{
+		"json": {
+			paths:       cli.StringListValue{"test/repo/db-user", "test/repo/db-password"},
+			format:      readFormatJSON,
+			expectedOut: "{\n  \"test/repo/db-password\": \"pa\\\"ss\\tword\\n\",\n  \"test/repo/db-user\": \"admin\"\n}\n",
+		},
+		"dotenv": {
+			paths:       cli.StringListValue{"test/repo/db-user", "test/repo/db-password"},
+			format:      readFormatDotEnv,
+			expectedOut: "DB_USER=\"admin\"\nDB_PASSWORD=\"pa\\\"ss\tword\\n\"\n",
+		},
+		"tsv": {
+			paths:       cli.StringListValue{"test/repo/db-user", "test/repo/db-password"},
+			format:      readFormatTSV,
+			expectedOut: "test/repo/db-user\tadmin\ntest/repo/db-password\tpa\"ss\\tword\\n\n",
+		},
+		"dotenv duplicate name": {
+			paths:       cli.StringListValue{"test/repo/db-user", "test/other/db-user"},
+			format:      readFormatDotEnv,
+			expectedErr: ErrDuplicateReadName("test/repo/db-user", "test/other/db-user", "DB_USER"),
+		},
+		"unknown format": {
+			paths:       cli.StringListValue{"test/repo/db-user"},
+			format:      "xml",
+			expectedErr: ErrUnknownReadFormat("xml"),
+		},
+		"not found": {
+			paths:       cli.StringListValue{"test/repo/db-user", "test/repo/missing"},
+			format:      readFormatJSON,
+			expectedErr: api.ErrSecretNotFound,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			testIO := fakeui.NewIO(t)
+			cmd := ReadCommand{
+				io:          testIO,
+				paths:       tc.paths,
+				many:        true,
+				format:      tc.format,
+				concurrency: 2,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									value, ok := secrets[path]
+									if !ok {
+										return nil, api.ErrSecretNotFound
+									}
+									return &api.SecretVersion{Data: []byte(value)}, nil
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, testIO.Out.String(), tc.expectedOut)
+		})
+	}
+}
+
+func TestReadCommand_Run_MultiplePathsNotMany(t *testing.T) {
+	cmd := ReadCommand{
+		io:    fakeui.NewIO(t),
+		paths: cli.StringListValue{"test/repo/db-user", "test/repo/db-password"},
+	}
+
+	err := cmd.Run()
+
+	assert.Equal(t, err, ErrMultiplePathsNotMany)
+}
@@ -95,7 +95,10 @@ func TestReadCommand_Run(t *testing.T) {
 					SecretService: &fakeclient.SecretService{
 						VersionService: &fakeclient.SecretVersionService{
 							GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
-								return &tc.secretVersion, tc.serviceErr
+								// The data is copied, because the command wipes it after use.
+								version := tc.secretVersion
+								version.Data = append([]byte(nil), tc.secretVersion.Data...)
+								return &version, tc.serviceErr
 							},
 						},
 					},
@@ -103,7 +106,7 @@ func TestReadCommand_Run(t *testing.T) {
 			}
 			tc.cmd.writeFileFunc = func(filename string, data []byte, perm os.FileMode) error {
 				if tc.fileErr == nil {
-					fileOut = data
+					fileOut = append([]byte(nil), data...)
 				}
 				return tc.fileErr
 			}
@@ -9,13 +9,15 @@ import (
 type RepoCommand struct {
 	io        ui.IO
 	newClient newClientFunc
+	store     CredentialConfig
 }
 
 // NewRepoCommand creates a new RepoCommand.
-func NewRepoCommand(io ui.IO, newClient newClientFunc) *RepoCommand {
+func NewRepoCommand(io ui.IO, newClient newClientFunc, store CredentialConfig) *RepoCommand {
 	return &RepoCommand{
 		io:        io,
 		newClient: newClient,
+		store:     store,
 	}
 }
 
@@ -25,11 +27,16 @@ func (cmd *RepoCommand) Register(r cli.Registerer) {
 	clause.Alias("repository")
 	clause.Alias("repos")
 	clause.Alias("repositories")
+	NewRepoArchiveCommand(cmd.io, cmd.newClient).Register(clause)
+	NewRepoCloneCommand(cmd.io, cmd.newClient, cmd.store).Register(clause)
 	NewRepoInitCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoInspectCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoInviteCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoExportCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoLSCommand(cmd.io, cmd.newClient).Register(clause)
+	NewRepoPushCommand(cmd.io, cmd.newClient, cmd.store).Register(clause)
 	NewRepoRevokeCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoRmCommand(cmd.io, cmd.newClient).Register(clause)
+	NewRepoStatusCommand(cmd.io, cmd.newClient, cmd.store).Register(clause)
+	NewRepoUnarchiveCommand(cmd.io, cmd.newClient).Register(clause)
 }
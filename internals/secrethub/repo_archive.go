@@ -0,0 +1,193 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// repoArchiveSecretName is the name of the secret in the root of an archived repo
+// that records the access rules that were revoked when the repo was archived.
+const repoArchiveSecretName = ".archived"
+
+// Errors
+var (
+	ErrRepoAlreadyArchived = errMain.Code("repo_already_archived").ErrorPref("the repository %s is already archived")
+	ErrRepoNotArchived     = errMain.Code("repo_not_archived").ErrorPref("the repository %s is not archived")
+	ErrInvalidRepoArchive  = errMain.Code("invalid_repo_archive").ErrorPref("could not read the archived access rules in %s: %s")
+)
+
+// repoArchive is the content of the archive secret of a repo.
+type repoArchive struct {
+	ArchivedAt time.Time         `json:"archived_at"`
+	Rules      []repoArchiveRule `json:"rules"`
+}
+
+// repoArchiveRule is an access rule that was revoked when the repo was archived.
+type repoArchiveRule struct {
+	Path       string `json:"path"`
+	Account    string `json:"account"`
+	Permission string `json:"permission"`
+}
+
+// repoArchiveSecretPath returns the path of the archive secret of the repo.
+func repoArchiveSecretPath(repo api.RepoPath) string {
+	return repo.Value() + "/" + repoArchiveSecretName
+}
+
+// loadRepoArchive reads the archive secret of the repo.
+// It returns nil when the repo is not archived.
+func loadRepoArchive(client secrethub.ClientInterface, repo api.RepoPath) (*repoArchive, error) {
+	path := repoArchiveSecretPath(repo)
+	version, err := client.Secrets().Versions().GetWithData(path)
+	if err == api.ErrSecretNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	archive := &repoArchive{}
+	err = json.Unmarshal(version.Data, archive)
+	if err != nil {
+		return nil, ErrInvalidRepoArchive(path, err)
+	}
+	return archive, nil
+}
+
+// RepoArchiveCommand revokes all non-admin access to a repo, recording the revoked
+// access rules in the repo so that they can be restored with RepoUnarchiveCommand.
+type RepoArchiveCommand struct {
+	path      api.RepoPath
+	force     bool
+	io        ui.IO
+	newClient newClientFunc
+	now       func() time.Time
+}
+
+// NewRepoArchiveCommand creates a new RepoArchiveCommand.
+func NewRepoArchiveCommand(io ui.IO, newClient newClientFunc) *RepoArchiveCommand {
+	return &RepoArchiveCommand{
+		io:        io,
+		newClient: newClient,
+		now:       time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RepoArchiveCommand) Register(r cli.Registerer) {
+	clause := r.Command("archive", "Revoke all access to a repository except for admins, so that it can be safely deleted later.")
+	clause.HelpLong("The revoked access rules are recorded in the " + repoArchiveSecretName + " secret in the root of the repository, " +
+		"which also marks the repository as archived. Use `secrethub repo unarchive` to restore them.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.path, Name: "repo-path", Required: true, Placeholder: repoPathPlaceHolder, Description: "The repository to archive."},
+	})
+}
+
+// Run archives the repo.
+func (cmd *RepoArchiveCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	existing, err := loadRepoArchive(client, cmd.path)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrRepoAlreadyArchived(cmd.path)
+	}
+
+	rules, err := client.AccessRules().List(cmd.path.Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	tree, err := client.Dirs().GetTree(cmd.path.Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	archive := repoArchive{
+		ArchivedAt: cmd.now().UTC(),
+		Rules:      []repoArchiveRule{},
+	}
+	for _, rule := range rules {
+		if rule.Permission == api.PermissionAdmin {
+			continue
+		}
+
+		dirPath, err := tree.AbsDirPath(rule.DirID)
+		if err != nil {
+			return err
+		}
+
+		archive.Rules = append(archive.Rules, repoArchiveRule{
+			Path:       dirPath.Value(),
+			Account:    rule.Account.Name.String(),
+			Permission: rule.Permission.String(),
+		})
+	}
+	sort.Slice(archive.Rules, func(i, j int) bool {
+		if archive.Rules[i].Path != archive.Rules[j].Path {
+			return archive.Rules[i].Path < archive.Rules[j].Path
+		}
+		return archive.Rules[i].Account < archive.Rules[j].Account
+	})
+
+	out := cmd.io.Output()
+	if len(archive.Rules) > 0 {
+		fmt.Fprintln(out, "The following access rules will be revoked:")
+		printRepoArchiveRules(cmd.io, archive.Rules)
+	}
+
+	if !cmd.force {
+		confirmed, err := ui.AskYesNo(cmd.io, fmt.Sprintf("Are you sure you want to archive %s?", cmd.path), ui.DefaultNo)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(out, "Aborting.")
+			return nil
+		}
+	}
+
+	raw, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// The access rules are recorded before they are revoked, so that they
+	// can still be restored when revoking fails halfway.
+	_, err = client.Secrets().Write(repoArchiveSecretPath(cmd.path), raw)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range archive.Rules {
+		err = client.AccessRules().Delete(rule.Path, rule.Account)
+		if err != nil && err != api.ErrAccessRuleNotFound {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, "Archived %s and revoked %s.\n", cmd.path, pluralize("access rule", "access rules", len(archive.Rules)))
+	return nil
+}
+
+// printRepoArchiveRules prints one line for every access rule.
+func printRepoArchiveRules(io ui.IO, rules []repoArchiveRule) {
+	for _, rule := range rules {
+		fmt.Fprintf(io.Output(), "  %s: %s on %s\n", rule.Account, rule.Permission, rule.Path)
+	}
+}
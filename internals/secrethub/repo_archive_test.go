@@ -0,0 +1,202 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestRepoArchiveCommand_Run(t *testing.T) {
+	repoID := uuid.New()
+	dirID := uuid.New()
+	tree := &api.Tree{
+		ParentPath: "namespace",
+		RootDir: &api.Dir{
+			Name:  "repo",
+			DirID: repoID,
+		},
+		Dirs: map[uuid.UUID]*api.Dir{
+			repoID: {
+				Name:  "repo",
+				DirID: repoID,
+			},
+			dirID: {
+				Name:     "dir",
+				DirID:    dirID,
+				ParentID: &repoID,
+			},
+		},
+	}
+	rules := []*api.AccessRule{
+		{DirID: repoID, Account: &api.Account{Name: "admin"}, Permission: api.PermissionAdmin},
+		{DirID: repoID, Account: &api.Account{Name: "dev"}, Permission: api.PermissionRead},
+		{DirID: dirID, Account: &api.Account{Name: "s-service"}, Permission: api.PermissionWrite},
+	}
+	archivedAt := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		archive         *api.SecretVersion
+		expectedWritten string
+		expectedDeleted []string
+		expectedOut     string
+		expectedErr     error
+	}{
+		"archive": {
+			expectedWritten: "{\n" +
+				"  \"archived_at\": \"2018-01-01T00:00:00Z\",\n" +
+				"  \"rules\": [\n" +
+				"    {\n" +
+				"      \"path\": \"namespace/repo\",\n" +
+				"      \"account\": \"dev\",\n" +
+				"      \"permission\": \"read\"\n" +
+				"    },\n" +
+				"    {\n" +
+				"      \"path\": \"namespace/repo/dir\",\n" +
+				"      \"account\": \"s-service\",\n" +
+				"      \"permission\": \"write\"\n" +
+				"    }\n" +
+				"  ]\n" +
+				"}",
+			expectedDeleted: []string{"namespace/repo dev", "namespace/repo/dir s-service"},
+			expectedOut: "The following access rules will be revoked:\n" +
+				"  dev: read on namespace/repo\n" +
+				"  s-service: write on namespace/repo/dir\n" +
+				"Archived namespace/repo and revoked 2 access rules.\n",
+		},
+		"already archived": {
+			archive:     &api.SecretVersion{Data: []byte(`{"rules":[]}`)},
+			expectedErr: ErrRepoAlreadyArchived("namespace/repo"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written string
+			var deleted []string
+			io := fakeui.NewIO(t)
+			cmd := RepoArchiveCommand{
+				path:  "namespace/repo",
+				force: true,
+				io:    io,
+				now: func() time.Time {
+					return archivedAt
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						AccessRuleService: &fakeclient.AccessRuleService{
+							ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+								return rules, nil
+							},
+							DeleteFunc: func(path string, accountName string) error {
+								deleted = append(deleted, path+" "+accountName)
+								return nil
+							},
+						},
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								return tree, nil
+							},
+						},
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									if tc.archive == nil {
+										return nil, api.ErrSecretNotFound
+									}
+									return tc.archive, nil
+								},
+							},
+							WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+								assert.Equal(t, path, "namespace/repo/.archived")
+								written = string(data)
+								return &api.SecretVersion{}, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, written, tc.expectedWritten)
+			assert.Equal(t, deleted, tc.expectedDeleted)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+		})
+	}
+}
+
+func TestRepoUnarchiveCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		archive         *api.SecretVersion
+		expectedSet     []string
+		expectedDeleted []string
+		expectedOut     string
+		expectedErr     error
+	}{
+		"unarchive": {
+			archive: &api.SecretVersion{Data: []byte(`{"rules":[` +
+				`{"path":"namespace/repo","account":"dev","permission":"read"},` +
+				`{"path":"namespace/repo/removed","account":"s-service","permission":"write"}]}`)},
+			expectedSet:     []string{"namespace/repo read dev", "namespace/repo/removed write s-service"},
+			expectedDeleted: []string{"namespace/repo/.archived"},
+			expectedOut: "Skipping s-service on namespace/repo/removed: the directory no longer exists\n" +
+				"Unarchived namespace/repo and restored 1 access rule.\n",
+		},
+		"not archived": {
+			expectedErr: ErrRepoNotArchived("namespace/repo"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var set, deleted []string
+			io := fakeui.NewIO(t)
+			cmd := RepoUnarchiveCommand{
+				path: "namespace/repo",
+				io:   io,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						AccessRuleService: &fakeclient.AccessRuleService{
+							SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+								set = append(set, path+" "+permission+" "+accountName)
+								if path == "namespace/repo/removed" {
+									return nil, api.ErrDirNotFound
+								}
+								return nil, nil
+							},
+						},
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									if tc.archive == nil {
+										return nil, api.ErrSecretNotFound
+									}
+									return tc.archive, nil
+								},
+							},
+							DeleteFunc: func(path string) error {
+								deleted = append(deleted, path)
+								return nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, set, tc.expectedSet)
+			assert.Equal(t, deleted, tc.expectedDeleted)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package secrethub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// RepoCloneCommand creates a local encrypted working copy of a repository.
+type RepoCloneCommand struct {
+	path      api.RepoPath
+	dir       string
+	io        ui.IO
+	newClient newClientFunc
+	importKey func() (keyWrapper, error)
+}
+
+// NewRepoCloneCommand creates a new RepoCloneCommand.
+func NewRepoCloneCommand(io ui.IO, newClient newClientFunc, store CredentialConfig) *RepoCloneCommand {
+	return &RepoCloneCommand{
+		io:        io,
+		newClient: newClient,
+		importKey: func() (keyWrapper, error) {
+			return newCredentialKeyWrapper(store)
+		},
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RepoCloneCommand) Register(r cli.Registerer) {
+	clause := r.Command("clone", "Create a local encrypted working copy of a repository to edit its secrets offline.")
+	clause.HelpLong("Every secret of the repository is written to a file in the directory with the path of the secret and the .secret extension. " +
+		"The values are encrypted with a key that can only be decrypted with your account key.\n\n" +
+		"To change a secret, create a file with the path of the secret without the .secret extension that contains the new value. " +
+		"To add a secret, create such a file for a new path. To delete a secret, remove its .secret file. " +
+		"Use `secrethub repo status` to review the changes and `secrethub repo push` to write them to SecretHub.\n\n" +
+		"Note that the files with new values are stored as plaintext until they are pushed, so anyone who can read the directory can read them. " +
+		"A .gitignore file is written that keeps them out of git. " +
+		"Files and directories of which the name starts with a dot and backup files of editors, such as foo~ and foo.swp, are ignored, " +
+		"so new secrets with such names cannot be added in a working copy.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.path, Name: "repo-path", Required: true, Placeholder: repoPathPlaceHolder, Description: "The path of the repository to clone."},
+		{Value: &cmd.dir, Name: "dir", Required: false, Description: "The directory to clone the repository into. Defaults to the name of the repository."},
+	})
+}
+
+// Run clones the repository.
+func (cmd *RepoCloneCommand) Run() error {
+	dir := cmd.dir
+	if dir == "" {
+		dir = cmd.path.GetRepo()
+	}
+
+	wrapper, err := cmd.importKey()
+	if err != nil {
+		return err
+	}
+
+	wc, err := newWorkingCopy(dir, cmd.path, wrapper)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	tree, err := client.Dirs().GetTree(cmd.path.GetDirPath().Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(tree.Secrets))
+	for _, secret := range tree.Secrets {
+		secretPath, err := tree.AbsSecretPath(secret.SecretID)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, secretPath.Value())
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		version, err := client.Secrets().Versions().GetWithData(path)
+		if err != nil {
+			return err
+		}
+
+		err = wc.set(strings.TrimPrefix(path, cmd.path.Value()+"/"), version.Data, version.Version)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = wc.save()
+	if err != nil {
+		return err
+	}
+
+	err = wc.writeGitignore()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Cloned %s into %s (%s).\n", cmd.path, dir, pluralize("secret", "secrets", len(paths)))
+	return nil
+}
+
+// workingCopyConflicts returns the paths of the changed secrets that have also been changed in SecretHub
+// since they were cloned or last pushed: modified or deleted secrets of which a newer version exists and
+// added secrets that already exist.
+func workingCopyConflicts(client secrethub.ClientInterface, wc *workingCopy, changes []workingCopyChange) (map[string]bool, error) {
+	conflicts := make(map[string]bool)
+	for _, change := range changes {
+		remote, err := client.Secrets().Versions().GetWithoutData(wc.secretPath(change.path))
+		if err == api.ErrSecretNotFound {
+			// A secret that was deleted remotely only conflicts with a modification.
+			if change.kind == workingCopyModified {
+				conflicts[change.path] = true
+			}
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if change.kind == workingCopyAdded || remote.Version != wc.meta.Secrets[change.path] {
+			conflicts[change.path] = true
+		}
+	}
+	return conflicts, nil
+}
+
+// printWorkingCopyChanges prints one line for every change, marking the conflicts.
+func printWorkingCopyChanges(io ui.IO, changes []workingCopyChange, conflicts map[string]bool) {
+	for _, change := range changes {
+		line := fmt.Sprintf("  %-10s%s", change.kind+":", change.path)
+		if conflicts[change.path] {
+			line += " (changed in SecretHub since it was cloned)"
+		}
+		fmt.Fprintln(io.Output(), line)
+	}
+}
@@ -1,10 +1,13 @@
 package secrethub
 
 import (
-	"archive/zip"
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
@@ -12,19 +15,34 @@ import (
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
 )
 
 // Error
 var (
-	ErrExportAlreadyExists = errMain.Code("export_file_already_exists").Error("the export file already exists")
+	ErrExportAlreadyExists   = errMain.Code("export_file_already_exists").Error("the export file already exists")
+	ErrExportInterrupted     = errMain.Code("export_interrupted").Error("the export was interrupted")
+	ErrResumeWithoutFileName = errMain.Code("resume_without_file_name").Error("the file name of the export to resume must be given when using --resume")
+	ErrNoExportToResume      = errMain.Code("no_export_to_resume").ErrorPref("there is no interrupted export to %s to resume")
+	ErrExportRepoMismatch    = errMain.Code("export_repo_mismatch").ErrorPref("the export to %s was started for another repository: %s")
+	ErrInvalidExportProgress = errMain.Code("invalid_export_progress").ErrorPref("could not read the export progress file %s: %s")
+	ErrInvalidExportManifest = errMain.Code("invalid_export_manifest").ErrorPref("could not read the export manifest %s: %s")
+	ErrManifestRepoMismatch  = errMain.Code("export_manifest_repo_mismatch").ErrorPref("the export manifest %s was written for another repository: %s")
+	ErrResumeNotSupported    = errMain.Code("resume_not_supported").ErrorPref("an interrupted export cannot be resumed with --format=%s: only zip exports can be resumed")
+	ErrInvalidExportVersions = errMain.Code("invalid_export_versions").ErrorPref("invalid value for --versions: %s: must be all or latest")
 )
 
-// RepoExportCommand exports a repo to a zip file.
+// RepoExportCommand exports a repo to a zip file, a tar file or a JSON document.
 type RepoExportCommand struct {
-	path      api.RepoPath
-	zipName   cli.StringValue
-	io        ui.IO
-	newClient newClientFunc
+	path          api.RepoPath
+	zipName       cli.StringValue
+	format        string
+	versions      string
+	resume        bool
+	since         timeValue
+	sinceManifest string
+	io            ui.IO
+	newClient     newClientFunc
 }
 
 // NewRepoExportCommand creates a new RepoExportCommand.
@@ -37,25 +55,92 @@ func NewRepoExportCommand(io ui.IO, newClient newClientFunc) *RepoExportCommand
 
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *RepoExportCommand) Register(r cli.Registerer) {
-	clause := r.Command("export", "Export the repository to a zip file.")
+	clause := r.Command("export", "Export the repository to a zip file, a tar file or a JSON document.")
+	clause.HelpLong("Every version of a secret is exported to a file named <path-of-the-secret>/<version-number>. " +
+		"A completed export also contains a manifest.json that lists the directories of the repository and the exported versions " +
+		"of every secret with the SHA256 checksum of their file, so the export can be verified and secrets can be restored from it selectively.\n\n" +
+		"With --format=json, the export is a single JSON document with a manifest field containing the manifest and a files field " +
+		"that maps the name of every file to its contents.\n\n" +
+		"An export to a zip file that is interrupted, or that stops unexpectedly, can be continued with --resume. " +
+		"The progress is saved after every secret, so only the secret that was being exported is exported again. " +
+		"Exports to a tar file or a JSON document cannot be resumed.")
+	clause.Flags().StringVar(&cmd.format, "format", exportFormatZIP, "The format of the export: zip, tar or json.")
+	clause.Flags().StringVar(&cmd.versions, "versions", exportVersionsAll, "The versions of every secret to export: all or latest.")
+	clause.Flags().BoolVar(&cmd.resume, "resume", false, "Resume an interrupted export to the given zip file. Secrets that were already exported are verified and not downloaded again. Only zip exports can be resumed.")
+	clause.Flags().Var(&cmd.since, "since", "Only export the secret versions created after the given date (2006-01-02) or timestamp (RFC3339).")
+	clause.Flags().StringVar(&cmd.sinceManifest, "since-manifest", "", "Only export the secret versions created since the export with the given manifest. "+
+		"Every completed export writes a manifest to <zip-file-name>.manifest.json.")
 
 	clause.BindAction(cmd.Run)
 	clause.BindArguments([]cli.Argument{
 		{Value: &cmd.path, Name: "repo-path", Required: true, Placeholder: repoPathPlaceHolder, Description: "The repository to export."},
-		{Value: &cmd.zipName, Name: "zip-file-name", Required: false, Description: "The file name to assign to the exported .zip file. Defaults to secrethub_export_<namespace>_<repo>_<timestamp>.zip with the timestamp formatted as YYYYMMDD_HHMMSS"},
+		{Value: &cmd.zipName, Name: "zip-file-name", Required: false, Description: "The file name to assign to the export. Defaults to secrethub_export_<namespace>_<repo>_<timestamp>.<format> with the timestamp formatted as YYYYMMDD_HHMMSS"},
 	})
 }
 
 // Run exports a repo to a zip file
 func (cmd *RepoExportCommand) Run() error {
+	switch cmd.format {
+	case exportFormatZIP:
+	case exportFormatTAR, exportFormatJSON:
+		if cmd.resume {
+			return ErrResumeNotSupported(cmd.format)
+		}
+	default:
+		return errNoSuchFormat(cmd.format)
+	}
+
+	if cmd.versions != exportVersionsAll && cmd.versions != exportVersionsLatest {
+		return ErrInvalidExportVersions(cmd.versions)
+	}
+
 	if cmd.zipName.Value == "" {
+		if cmd.resume {
+			return ErrResumeWithoutFileName
+		}
 		// secrethub_export_repo_date_time.zip
-		cmd.zipName.Value = fmt.Sprintf("%s_export_%s_%s.zip", ApplicationName, cmd.path.GetRepo(), time.Now().Format("20060102_150405"))
+		cmd.zipName.Value = fmt.Sprintf("%s_export_%s_%s.%s", ApplicationName, cmd.path.GetRepo(), time.Now().Format("20060102_150405"), cmd.format)
+	}
+
+	filter := exportVersionFilter{
+		since:  cmd.since.Time,
+		latest: cmd.versions == exportVersionsLatest,
+	}
+	if cmd.sinceManifest != "" {
+		manifest, err := loadExportManifest(cmd.sinceManifest)
+		if err != nil {
+			return err
+		}
+		if manifest.Repo != cmd.path.String() {
+			return ErrManifestRepoMismatch(cmd.sinceManifest, manifest.Repo)
+		}
+		filter.manifest = manifest
 	}
 
-	_, err := os.Stat(cmd.zipName.Value)
-	if err == nil {
-		return ErrExportAlreadyExists
+	var progress *exportProgress
+	if cmd.resume {
+		var err error
+		progress, err = loadExportProgress(cmd.zipName.Value)
+		if os.IsNotExist(err) {
+			return ErrNoExportToResume(cmd.zipName.Value)
+		} else if err != nil {
+			return err
+		}
+
+		if progress.Repo != cmd.path.String() {
+			return ErrExportRepoMismatch(cmd.zipName.Value, progress.Repo)
+		}
+	} else {
+		_, err := os.Stat(cmd.zipName.Value)
+		if err == nil {
+			return ErrExportAlreadyExists
+		}
+
+		progress = &exportProgress{
+			Repo:     cmd.path.String(),
+			Secrets:  make(map[string][]exportEntry),
+			Versions: make(map[string]int),
+		}
 	}
 
 	confirmed, err := ui.ConfirmCaseInsensitive(
@@ -87,57 +172,245 @@ func (cmd *RepoExportCommand) Run() error {
 		return err
 	}
 
-	zipFile, err := os.Create(cmd.zipName.Value)
+	// The export is written to a temporary file first, so that an interrupted
+	// run never leaves behind a corrupt export file to resume from.
+	tmpName := cmd.zipName.Value + ".tmp"
+
+	// The temporary file of an export that did not stop cleanly contains the secrets that were exported
+	// since the export file was last written. It is kept aside to recover them, as a new one is written.
+	// When a file is already kept aside, the previous resume did not get to save its progress, so the
+	// progress still refers to that file.
+	recoverName := cmd.zipName.Value + ".recover"
+	if cmd.resume {
+		_, err := os.Stat(recoverName)
+		if os.IsNotExist(err) {
+			err = os.Rename(tmpName, recoverName)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	exportFile, err := os.Create(tmpName)
+	if err != nil {
+		return err
+	}
+
+	archive, err := newExportArchive(cmd.format, exportFile)
 	if err != nil {
+		_ = exportFile.Close()
+		_ = os.Remove(tmpName)
 		return err
 	}
 
-	writer := zip.NewWriter(zipFile)
-	defer func() {
-		err := writer.Close()
+	// The progress of zip exports is saved after every secret, so that they can be resumed.
+	var checkpoint func() error
+	if cmd.format == exportFormatZIP {
+		checkpoint = func() error {
+			return progress.save(cmd.zipName.Value)
+		}
+	}
+
+	if cmd.resume {
+		// Only zip exports can be resumed, which is checked above.
+		err = recoverExportEntries(archive.(*zipExportArchive), progress, recoverName, cmd.zipName.Value)
+		if err == nil {
+			err = checkpoint()
+		}
 		if err != nil {
-			panic(fmt.Errorf("could not close zip file: %s", err))
+			_ = archive.Close()
+			_ = exportFile.Close()
+			_ = os.Remove(tmpName)
+			return err
+		}
+		// The progress now refers to the new temporary file, so the old one is not needed anymore.
+		err = os.Remove(recoverName)
+		if err != nil && !os.IsNotExist(err) {
+			return err
 		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-		err = zipFile.Close()
+	exportErr := cmd.exportSecrets(ctx, client, rootDir, archive, progress, filter, checkpoint)
+	if exportErr == nil {
+		manifest, err := newExportArchiveManifest(cmd.path, rootDir, progress, cmd.versions)
+		if err == nil {
+			err = archive.writeManifest(manifest)
+		}
 		if err != nil {
-			panic(fmt.Errorf("could not close zip file: %s", err))
+			_ = archive.Close()
+			_ = exportFile.Close()
+			_ = os.Remove(tmpName)
+			return err
 		}
-	}()
+	}
+
+	err = archive.Close()
+	if err != nil {
+		return fmt.Errorf("could not close export file: %s", err)
+	}
+
+	err = exportFile.Close()
+	if err != nil {
+		return fmt.Errorf("could not close export file: %s", err)
+	}
 
+	if exportErr != nil && cmd.format != exportFormatZIP {
+		// The export cannot be resumed, so the incomplete export is discarded.
+		_ = os.Remove(tmpName)
+		return exportErr
+	}
+
+	err = os.Rename(tmpName, cmd.zipName.Value)
+	if err != nil {
+		return err
+	}
+
+	if exportErr != nil {
+		err = progress.save(cmd.zipName.Value)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.io.Output(), "Exported %d of %d secrets to %s. Run the command again with --resume to continue the export.\n", len(progress.Secrets), len(rootDir.Secrets), cmd.zipName.Value)
+		return exportErr
+	}
+
+	err = cmd.writeManifest(progress, filter)
+	if err != nil {
+		return err
+	}
+
+	return progress.remove(cmd.zipName.Value)
+}
+
+// writeManifest writes the manifest of the completed export, with the latest version of every
+// exported secret. The versions of secrets that are in the manifest of a previous export, but
+// had no new versions, are carried over, so that incremental exports can be chained.
+func (cmd *RepoExportCommand) writeManifest(progress *exportProgress, filter exportVersionFilter) error {
+	manifest := &exportManifest{
+		Repo:      cmd.path.String(),
+		CreatedAt: time.Now().UTC(),
+		Secrets:   make(map[string]int, len(progress.Versions)),
+	}
+	if filter.manifest != nil {
+		for path, version := range filter.manifest.Secrets {
+			manifest.Secrets[path] = version
+		}
+	}
+	for path, version := range progress.Versions {
+		manifest.Secrets[path] = version
+	}
+	return manifest.save(cmd.zipName.Value)
+}
+
+// exportSecrets writes all secrets in the tree that are not yet recorded in the progress
+// to the archive, limited to the versions selected by the filter. It stops after the secret
+// that is being exported when the context is canceled. When checkpoint is set, it is called
+// after every secret that is recorded in the progress.
+func (cmd *RepoExportCommand) exportSecrets(ctx context.Context, client secrethub.ClientInterface, rootDir *api.Tree, archive exportArchive, progress *exportProgress, filter exportVersionFilter, checkpoint func() error) error {
+	secretPaths := make([]*api.SecretPath, 0, len(rootDir.Secrets))
 	for _, secret := range rootDir.Secrets {
 		secretPath, err := rootDir.AbsSecretPath(secret.SecretID)
 		if err != nil {
 			return err
 		}
+		secretPaths = append(secretPaths, secretPath)
+	}
+	sort.Slice(secretPaths, func(i, j int) bool {
+		return secretPaths[i].Value() < secretPaths[j].Value()
+	})
+
+	bar := ui.NewProgress(cmd.io, "Exporting secrets", len(secretPaths))
+	bar.Start()
+	defer bar.Stop()
+
+	for _, secretPath := range secretPaths {
+		if ctx.Err() != nil {
+			return ErrExportInterrupted
+		}
+
+		if _, done := progress.Secrets[secretPath.Value()]; done {
+			bar.Add(1)
+			continue
+		}
 
-		versions, err := client.Secrets().Versions().ListWithData(secretPath.Value())
+		versions, err := listExportVersions(client, secretPath.Value(), filter)
 		if err != nil {
 			return err
 		}
 
+		latest := 0
+		for _, version := range versions {
+			if version.Version > latest {
+				latest = version.Version
+			}
+		}
+		if latest > progress.Versions[secretPath.Value()] {
+			progress.Versions[secretPath.Value()] = latest
+		}
+
+		entries := make([]exportEntry, 0, len(versions))
 		for _, version := range versions {
+			if filter.latest && version.Version != latest {
+				continue
+			}
+			if !filter.include(secretPath.Value(), version) {
+				continue
+			}
+
 			versionPath, err := secretPath.AddVersion(version.Version)
 			if err != nil {
 				return err
 			}
 
+			if filter.selective() {
+				version, err = client.Secrets().Versions().GetWithData(versionPath.Value())
+				if err != nil {
+					return err
+				}
+			}
+
 			// Replace the : for / to create a directory for every secret containing versions.
 			zipSecretPath := strings.Replace(versionPath.String(), ":", "/", -1)
 			// Remove the repo path from the zipfile.
 			zipSecretPath = strings.TrimPrefix(zipSecretPath, versionPath.GetRepoPath().String()+"/")
 
-			zipNode, err := writer.Create(zipSecretPath)
+			data := posix.AddNewLine(version.Data)
+			entry := newExportEntry(zipSecretPath, data)
+			if zipArchive, ok := archive.(*zipExportArchive); ok {
+				entry.Offset, err = zipArchive.writeEntry(zipSecretPath, data)
+			} else {
+				err = archive.writeFile(zipSecretPath, data)
+			}
 			if err != nil {
 				return err
 			}
 
-			_, err = zipNode.Write(posix.AddNewLine(version.Data))
+			entries = append(entries, entry)
+		}
+
+		progress.Secrets[secretPath.Value()] = entries
+		if checkpoint != nil {
+			err = checkpoint()
 			if err != nil {
 				return err
 			}
 		}
+		bar.Add(1)
 	}
 
 	return nil
 }
+
+// listExportVersions returns the versions of the secret at the given path. When not all versions
+// are exported, the versions are listed without their data, so that only the data of the versions
+// that are exported has to be downloaded.
+func listExportVersions(client secrethub.ClientInterface, path string, filter exportVersionFilter) ([]*api.SecretVersion, error) {
+	if filter.selective() {
+		return client.Secrets().Versions().ListWithoutData(path)
+	}
+	return client.Secrets().Versions().ListWithData(path)
+}
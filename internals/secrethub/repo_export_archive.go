@@ -0,0 +1,264 @@
+package secrethub
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/posix"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// The formats an export can be written in.
+const (
+	exportFormatZIP  = "zip"
+	exportFormatTAR  = "tar"
+	exportFormatJSON = "json"
+)
+
+// The versions of every secret that can be exported.
+const (
+	exportVersionsAll    = "all"
+	exportVersionsLatest = "latest"
+)
+
+// exportArchiveManifestName is the name of the manifest that is included in every completed export.
+const exportArchiveManifestName = "manifest.json"
+
+// exportArchive writes the files of an export.
+type exportArchive interface {
+	// writeFile adds a file with the given name and contents to the archive.
+	writeFile(name string, data []byte) error
+	// writeManifest adds the manifest describing the contents to the archive.
+	writeManifest(manifest *exportArchiveManifest) error
+	// Close finishes writing the archive. It does not close the underlying writer.
+	Close() error
+}
+
+// newExportArchive returns an exportArchive that writes the given format to w.
+func newExportArchive(format string, w io.Writer) (exportArchive, error) {
+	switch format {
+	case exportFormatZIP:
+		counter := &offsetWriter{w: w}
+		return &zipExportArchive{Writer: zip.NewWriter(counter), written: counter}, nil
+	case exportFormatTAR:
+		return &tarExportArchive{writer: tar.NewWriter(w), modTime: time.Now()}, nil
+	case exportFormatJSON:
+		return &jsonExportArchive{w: w, files: make(map[string]string)}, nil
+	default:
+		return nil, errNoSuchFormat(format)
+	}
+}
+
+// exportArchiveManifest describes the contents of an export, so that the export
+// can be verified and secrets can be restored from it selectively.
+type exportArchiveManifest struct {
+	Repo      string    `json:"repo"`
+	CreatedAt time.Time `json:"created_at"`
+	// Versions is either all or latest.
+	Versions string `json:"versions"`
+	// Dirs contains the paths of all directories in the repo, relative to the repo.
+	Dirs    []string              `json:"dirs"`
+	Secrets []exportArchiveSecret `json:"secrets"`
+}
+
+// exportArchiveSecret describes the exported versions of a single secret.
+type exportArchiveSecret struct {
+	// Name is the path of the secret relative to the repo.
+	Name     string                 `json:"name"`
+	Versions []exportArchiveVersion `json:"versions"`
+}
+
+// exportArchiveVersion describes a single exported secret version.
+type exportArchiveVersion struct {
+	Version int    `json:"version"`
+	File    string `json:"file"`
+	SHA256  string `json:"sha256"`
+}
+
+// newExportArchiveManifest returns the manifest of an export of the given tree
+// containing the entries recorded in the progress.
+func newExportArchiveManifest(repo api.RepoPath, tree *api.Tree, progress *exportProgress, versions string) (*exportArchiveManifest, error) {
+	manifest := &exportArchiveManifest{
+		Repo:      repo.Value(),
+		CreatedAt: time.Now().UTC(),
+		Versions:  versions,
+		Dirs:      []string{},
+		Secrets:   []exportArchiveSecret{},
+	}
+
+	for dirID := range tree.Dirs {
+		dirPath, err := tree.AbsDirPath(dirID)
+		if err != nil {
+			return nil, err
+		}
+		if dirPath.Value() == repo.Value() {
+			continue
+		}
+		manifest.Dirs = append(manifest.Dirs, strings.TrimPrefix(dirPath.Value(), repo.Value()+"/"))
+	}
+	sort.Strings(manifest.Dirs)
+
+	for path, entries := range progress.Secrets {
+		if len(entries) == 0 {
+			continue
+		}
+
+		secret := exportArchiveSecret{
+			Name:     strings.TrimPrefix(path, repo.Value()+"/"),
+			Versions: make([]exportArchiveVersion, 0, len(entries)),
+		}
+		for _, entry := range entries {
+			// Every version is exported to a file named after its version number.
+			version, err := strconv.Atoi(entry.Name[strings.LastIndex(entry.Name, "/")+1:])
+			if err != nil {
+				return nil, err
+			}
+			secret.Versions = append(secret.Versions, exportArchiveVersion{
+				Version: version,
+				File:    entry.Name,
+				SHA256:  entry.SHA256,
+			})
+		}
+		sort.Slice(secret.Versions, func(i, j int) bool {
+			return secret.Versions[i].Version < secret.Versions[j].Version
+		})
+		manifest.Secrets = append(manifest.Secrets, secret)
+	}
+	sort.Slice(manifest.Secrets, func(i, j int) bool {
+		return manifest.Secrets[i].Name < manifest.Secrets[j].Name
+	})
+
+	return manifest, nil
+}
+
+// zipExportArchive writes an export to a zip archive.
+type zipExportArchive struct {
+	*zip.Writer
+	written *offsetWriter
+}
+
+func (a *zipExportArchive) writeFile(name string, data []byte) error {
+	_, err := a.writeEntry(name, data)
+	return err
+}
+
+// writeEntry adds a file to the archive and returns the offset of its contents in the zip file.
+// The contents are stored uncompressed and flushed to the underlying writer, so that they can be
+// read back at the offset, even when the archive is never closed.
+func (a *zipExportArchive) writeEntry(name string, data []byte) (int64, error) {
+	w, err := a.CreateHeader(&zip.FileHeader{
+		Name:   name,
+		Method: zip.Store,
+	})
+	if err != nil {
+		return 0, err
+	}
+	err = a.Flush()
+	if err != nil {
+		return 0, err
+	}
+
+	offset := a.written.n
+	_, err = w.Write(data)
+	if err != nil {
+		return 0, err
+	}
+	return offset, a.Flush()
+}
+
+// offsetWriter counts the bytes that are written to the underlying writer.
+type offsetWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *offsetWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+func (a *zipExportArchive) writeManifest(manifest *exportArchiveManifest) error {
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return a.writeFile(exportArchiveManifestName, raw)
+}
+
+// tarExportArchive writes an export to a tar archive.
+type tarExportArchive struct {
+	writer  *tar.Writer
+	modTime time.Time
+}
+
+func (a *tarExportArchive) writeFile(name string, data []byte) error {
+	err := a.writer.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: a.modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = a.writer.Write(data)
+	return err
+}
+
+func (a *tarExportArchive) writeManifest(manifest *exportArchiveManifest) error {
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return a.writeFile(exportArchiveManifestName, raw)
+}
+
+func (a *tarExportArchive) Close() error {
+	return a.writer.Close()
+}
+
+// jsonExportArchive writes an export to a single JSON document containing the manifest
+// and the contents of every file. Because the document is only written on Close,
+// an interrupted JSON export cannot be resumed.
+type jsonExportArchive struct {
+	w        io.Writer
+	manifest *exportArchiveManifest
+	files    map[string]string
+}
+
+// jsonExport is the document written by a jsonExportArchive.
+type jsonExport struct {
+	Manifest *exportArchiveManifest `json:"manifest,omitempty"`
+	// Files maps the name of every file to its contents.
+	Files map[string]string `json:"files"`
+}
+
+func (a *jsonExportArchive) writeFile(name string, data []byte) error {
+	a.files[name] = string(data)
+	return nil
+}
+
+func (a *jsonExportArchive) writeManifest(manifest *exportArchiveManifest) error {
+	a.manifest = manifest
+	return nil
+}
+
+func (a *jsonExportArchive) Close() error {
+	raw, err := json.MarshalIndent(jsonExport{
+		Manifest: a.manifest,
+		Files:    a.files,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = a.w.Write(posix.AddNewLine(raw))
+	return err
+}
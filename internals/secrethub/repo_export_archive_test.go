@@ -0,0 +1,156 @@
+package secrethub
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestNewExportArchiveManifest(t *testing.T) {
+	repoID := uuid.New()
+	dirID := uuid.New()
+	subDirID := uuid.New()
+
+	tree := &api.Tree{
+		ParentPath: "namespace",
+		RootDir: &api.Dir{
+			Name:  "repo",
+			DirID: repoID,
+		},
+		Dirs: map[uuid.UUID]*api.Dir{
+			repoID: {
+				Name:  "repo",
+				DirID: repoID,
+			},
+			dirID: {
+				Name:     "dir",
+				DirID:    dirID,
+				ParentID: &repoID,
+			},
+			subDirID: {
+				Name:     "empty",
+				DirID:    subDirID,
+				ParentID: &dirID,
+			},
+		},
+	}
+
+	progress := &exportProgress{
+		Secrets: map[string][]exportEntry{
+			"namespace/repo/foo": {
+				newExportEntry("foo/2", []byte("bar\n")),
+				newExportEntry("foo/1", []byte("foo\n")),
+			},
+			"namespace/repo/dir/baz": {
+				newExportEntry("dir/baz/1", []byte("baz\n")),
+			},
+			// Secrets without exported versions are left out.
+			"namespace/repo/unchanged": {},
+		},
+	}
+
+	manifest, err := newExportArchiveManifest("namespace/repo", tree, progress, exportVersionsAll)
+	assert.OK(t, err)
+
+	assert.Equal(t, manifest.Repo, "namespace/repo")
+	assert.Equal(t, manifest.Versions, exportVersionsAll)
+	assert.Equal(t, manifest.Dirs, []string{"dir", "dir/empty"})
+	assert.Equal(t, manifest.Secrets, []exportArchiveSecret{
+		{
+			Name: "dir/baz",
+			Versions: []exportArchiveVersion{
+				{Version: 1, File: "dir/baz/1", SHA256: newExportEntry("", []byte("baz\n")).SHA256},
+			},
+		},
+		{
+			Name: "foo",
+			Versions: []exportArchiveVersion{
+				{Version: 1, File: "foo/1", SHA256: newExportEntry("", []byte("foo\n")).SHA256},
+				{Version: 2, File: "foo/2", SHA256: newExportEntry("", []byte("bar\n")).SHA256},
+			},
+		},
+	})
+}
+
+func TestExportArchive(t *testing.T) {
+	manifest := &exportArchiveManifest{
+		Repo:     "namespace/repo",
+		Versions: exportVersionsLatest,
+		Dirs:     []string{},
+		Secrets: []exportArchiveSecret{
+			{
+				Name:     "foo",
+				Versions: []exportArchiveVersion{{Version: 1, File: "foo/1"}},
+			},
+		},
+	}
+	expectedManifest, err := json.MarshalIndent(manifest, "", "  ")
+	assert.OK(t, err)
+
+	cases := map[string]struct {
+		format string
+		read   func(t *testing.T, raw []byte) map[string]string
+	}{
+		"tar": {
+			format: exportFormatTAR,
+			read: func(t *testing.T, raw []byte) map[string]string {
+				files := make(map[string]string)
+				r := tar.NewReader(bytes.NewReader(raw))
+				for {
+					header, err := r.Next()
+					if err == io.EOF {
+						return files
+					}
+					assert.OK(t, err)
+
+					data, err := io.ReadAll(r)
+					assert.OK(t, err)
+					files[header.Name] = string(data)
+				}
+			},
+		},
+		"json": {
+			format: exportFormatJSON,
+			read: func(t *testing.T, raw []byte) map[string]string {
+				var export struct {
+					Manifest json.RawMessage   `json:"manifest"`
+					Files    map[string]string `json:"files"`
+				}
+				assert.OK(t, json.Unmarshal(raw, &export))
+
+				var indented bytes.Buffer
+				assert.OK(t, json.Indent(&indented, export.Manifest, "", "  "))
+				export.Files[exportArchiveManifestName] = indented.String()
+				return export.Files
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			archive, err := newExportArchive(tc.format, &buf)
+			assert.OK(t, err)
+
+			assert.OK(t, archive.writeFile("foo/1", []byte("bar\n")))
+			assert.OK(t, archive.writeManifest(manifest))
+			assert.OK(t, archive.Close())
+
+			assert.Equal(t, tc.read(t, buf.Bytes()), map[string]string{
+				"foo/1":                   "bar\n",
+				exportArchiveManifestName: string(expectedManifest),
+			})
+		})
+	}
+}
+
+func TestNewExportArchive_InvalidFormat(t *testing.T) {
+	_, err := newExportArchive("xml", io.Discard)
+	assert.Equal(t, err, errNoSuchFormat("xml"))
+}
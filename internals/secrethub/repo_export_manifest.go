@@ -0,0 +1,79 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// exportManifest records the latest version of every secret in a completed export,
+// so that a later export can be limited to the versions that were created since.
+type exportManifest struct {
+	Repo      string    `json:"repo"`
+	CreatedAt time.Time `json:"created_at"`
+	// Secrets maps the path of every secret to its latest version at the time of the export.
+	Secrets map[string]int `json:"secrets"`
+}
+
+// exportManifestPath returns the path of the manifest that belongs to the given export file.
+func exportManifestPath(exportPath string) string {
+	return exportPath + ".manifest.json"
+}
+
+// loadExportManifest reads the export manifest at the given path.
+func loadExportManifest(path string) (*exportManifest, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+
+	manifest := &exportManifest{}
+	err = json.Unmarshal(raw, manifest)
+	if err != nil {
+		return nil, ErrInvalidExportManifest(path, err)
+	}
+	if manifest.Secrets == nil {
+		manifest.Secrets = make(map[string]int)
+	}
+	return manifest, nil
+}
+
+// save writes the manifest next to the given export file.
+func (m *exportManifest) save(exportPath string) error {
+	raw, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(exportManifestPath(exportPath), raw, 0600)
+}
+
+// exportVersionFilter selects the secret versions to include in an export.
+// The zero value includes all versions.
+type exportVersionFilter struct {
+	// since excludes versions created at or before this time, unless it is zero.
+	since time.Time
+	// manifest excludes the versions that were recorded in a previous export, unless it is nil.
+	manifest *exportManifest
+	// latest excludes all but the latest version of every secret.
+	latest bool
+}
+
+// selective returns whether the filter can exclude versions.
+func (f exportVersionFilter) selective() bool {
+	return !f.since.IsZero() || f.manifest != nil || f.latest
+}
+
+// include returns whether the version of the secret at the given path should be exported.
+func (f exportVersionFilter) include(secretPath string, version *api.SecretVersion) bool {
+	if f.manifest != nil {
+		if exported, ok := f.manifest.Secrets[secretPath]; ok && version.Version <= exported {
+			return false
+		}
+	}
+	if !f.since.IsZero() && !version.CreatedAt.After(f.since) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestExportVersionFilter_include(t *testing.T) {
+	since := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	manifest := &exportManifest{
+		Secrets: map[string]int{
+			"namespace/repo/foo": 2,
+		},
+	}
+
+	cases := map[string]struct {
+		filter   exportVersionFilter
+		path     string
+		version  *api.SecretVersion
+		expected bool
+	}{
+		"no filter": {
+			path:     "namespace/repo/foo",
+			version:  &api.SecretVersion{Version: 1},
+			expected: true,
+		},
+		"created before since": {
+			filter:   exportVersionFilter{since: since},
+			path:     "namespace/repo/foo",
+			version:  &api.SecretVersion{Version: 1, CreatedAt: since.Add(-time.Hour)},
+			expected: false,
+		},
+		"created after since": {
+			filter:   exportVersionFilter{since: since},
+			path:     "namespace/repo/foo",
+			version:  &api.SecretVersion{Version: 1, CreatedAt: since.Add(time.Hour)},
+			expected: true,
+		},
+		"version in manifest": {
+			filter:   exportVersionFilter{manifest: manifest},
+			path:     "namespace/repo/foo",
+			version:  &api.SecretVersion{Version: 2},
+			expected: false,
+		},
+		"version newer than manifest": {
+			filter:   exportVersionFilter{manifest: manifest},
+			path:     "namespace/repo/foo",
+			version:  &api.SecretVersion{Version: 3},
+			expected: true,
+		},
+		"secret not in manifest": {
+			filter:   exportVersionFilter{manifest: manifest},
+			path:     "namespace/repo/bar",
+			version:  &api.SecretVersion{Version: 1},
+			expected: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.filter.include(tc.path, tc.version), tc.expected)
+		})
+	}
+}
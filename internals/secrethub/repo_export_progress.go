@@ -0,0 +1,151 @@
+package secrethub
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+)
+
+// exportProgress records which secrets have been written to an export file,
+// so an interrupted export can be resumed without exporting them again.
+// It is saved after every exported secret, so that an export that did not
+// stop cleanly, e.g. because it was killed, can be resumed as well.
+type exportProgress struct {
+	Repo string `json:"repo"`
+	// Secrets maps the path of every completely exported secret to the entries
+	// that have been written for it.
+	Secrets map[string][]exportEntry `json:"secrets"`
+	// Versions maps the path of every secret that has been listed to its latest version.
+	// It is used to write the manifest of the export.
+	Versions map[string]int `json:"versions,omitempty"`
+}
+
+// exportEntry is a single file written to an export, together with the checksum of its contents.
+type exportEntry struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	// Offset is the position of the contents in the export file and Size is their length. The contents
+	// are stored uncompressed, so they can be read back when the zip archive was never completed.
+	Offset int64 `json:"offset"`
+	Size   int64 `json:"size"`
+}
+
+// newExportEntry returns an exportEntry for the given file name and contents.
+func newExportEntry(name string, data []byte) exportEntry {
+	sum := sha256.Sum256(data)
+	return exportEntry{
+		Name:   name,
+		SHA256: hex.EncodeToString(sum[:]),
+		Size:   int64(len(data)),
+	}
+}
+
+// exportProgressPath returns the path of the progress file that belongs to the given export file.
+func exportProgressPath(exportPath string) string {
+	return exportPath + ".progress"
+}
+
+// loadExportProgress reads the progress of the export to the given file.
+// If no progress was recorded, os.ErrNotExist is returned.
+func loadExportProgress(exportPath string) (*exportProgress, error) {
+	raw, err := os.ReadFile(exportProgressPath(exportPath))
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &exportProgress{}
+	err = json.Unmarshal(raw, progress)
+	if err != nil {
+		return nil, ErrInvalidExportProgress(exportProgressPath(exportPath), err)
+	}
+	if progress.Secrets == nil {
+		progress.Secrets = make(map[string][]exportEntry)
+	}
+	if progress.Versions == nil {
+		progress.Versions = make(map[string]int)
+	}
+	return progress, nil
+}
+
+// save atomically writes the progress to the progress file of the given export file.
+func (p *exportProgress) save(exportPath string) error {
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(exportProgressPath(exportPath), raw, 0600)
+}
+
+// remove deletes the progress file of the given export file.
+func (p *exportProgress) remove(exportPath string) error {
+	err := atomicfile.Remove(exportProgressPath(exportPath))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// recoverExportEntries writes the entries of the secrets that were already exported to the archive.
+// The contents of every entry are read at their recorded offset from the first of the given files
+// in which they match their checksum. Those are the export file of an interrupted export and the
+// temporary file of an export that did not stop cleanly, of which the zip archive is not complete.
+// Secrets of which an entry cannot be recovered are removed from the progress, so they are exported again.
+func recoverExportEntries(archive *zipExportArchive, progress *exportProgress, sources ...string) error {
+	var files []*os.File
+	for _, source := range sources {
+		f, err := os.Open(source)
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+		files = append(files, f)
+	}
+
+	paths := make([]string, 0, len(progress.Secrets))
+	for path := range progress.Secrets {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		entries := progress.Secrets[path]
+		contents := make([][]byte, 0, len(entries))
+		for _, entry := range entries {
+			data, ok := readExportEntry(files, entry)
+			if !ok {
+				break
+			}
+			contents = append(contents, data)
+		}
+
+		if len(contents) != len(entries) {
+			delete(progress.Secrets, path)
+			continue
+		}
+		for i, data := range contents {
+			offset, err := archive.writeEntry(entries[i].Name, data)
+			if err != nil {
+				return err
+			}
+			entries[i].Offset = offset
+		}
+	}
+	return nil
+}
+
+// readExportEntry reads the contents of the entry from the first file in which they match its checksum.
+func readExportEntry(files []*os.File, entry exportEntry) ([]byte, bool) {
+	for _, f := range files {
+		data := make([]byte, entry.Size)
+		_, err := f.ReadAt(data, entry.Offset)
+		if err == nil && newExportEntry(entry.Name, data).SHA256 == entry.SHA256 {
+			return data, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,137 @@
+package secrethub
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestExportProgress_SaveAndLoad(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	exportPath := filepath.Join(dir, "export.zip")
+
+	_, err := loadExportProgress(exportPath)
+	assert.Equal(t, os.IsNotExist(err), true)
+
+	progress := &exportProgress{
+		Repo: "namespace/repo",
+		Secrets: map[string][]exportEntry{
+			"namespace/repo/foo": {newExportEntry("foo/1", []byte("bar\n"))},
+		},
+		Versions: map[string]int{
+			"namespace/repo/foo": 1,
+		},
+	}
+	err = progress.save(exportPath)
+	assert.OK(t, err)
+
+	actual, err := loadExportProgress(exportPath)
+	assert.OK(t, err)
+	assert.Equal(t, actual, progress)
+
+	err = progress.remove(exportPath)
+	assert.OK(t, err)
+
+	_, err = loadExportProgress(exportPath)
+	assert.Equal(t, os.IsNotExist(err), true)
+}
+
+func TestRecoverExportEntries(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	// writeSource writes the files to a zip archive that is not closed, like the temporary file
+	// of an export that did not stop cleanly, and returns their entries.
+	writeSource := func(name string, files map[string]string) (string, map[string]exportEntry) {
+		path := filepath.Join(dir, name)
+		f, err := os.Create(path)
+		assert.OK(t, err)
+		defer f.Close()
+
+		archive, err := newExportArchive(exportFormatZIP, f)
+		assert.OK(t, err)
+
+		entries := make(map[string]exportEntry, len(files))
+		for _, name := range []string{"bar/1", "baz/1", "foo/1", "foo/2"} {
+			content, ok := files[name]
+			if !ok {
+				continue
+			}
+			entry := newExportEntry(name, []byte(content))
+			entry.Offset, err = archive.(*zipExportArchive).writeEntry(name, []byte(content))
+			assert.OK(t, err)
+			entries[name] = entry
+		}
+		return path, entries
+	}
+
+	first, firstEntries := writeSource("export.zip.recover", map[string]string{
+		"foo/1": "foo\n",
+		"bar/1": "tampered\n",
+	})
+	second, secondEntries := writeSource("export.zip", map[string]string{
+		"foo/2": "foo2\n",
+		"baz/1": "baz\n",
+	})
+
+	missing := newExportEntry("qux/1", []byte("qux\n"))
+	missing.Offset = 1 << 20
+	tampered := firstEntries["bar/1"]
+	tampered.SHA256 = newExportEntry("bar/1", []byte("bar\n")).SHA256
+
+	progress := &exportProgress{
+		Secrets: map[string][]exportEntry{
+			"namespace/repo/foo": {firstEntries["foo/1"], secondEntries["foo/2"]},
+			"namespace/repo/bar": {tampered},
+			"namespace/repo/baz": {secondEntries["baz/1"]},
+			"namespace/repo/qux": {missing},
+			"namespace/repo/new": {},
+		},
+	}
+
+	var buf bytes.Buffer
+	archive, err := newExportArchive(exportFormatZIP, &buf)
+	assert.OK(t, err)
+	err = recoverExportEntries(archive.(*zipExportArchive), progress, first, second, filepath.Join(dir, "does-not-exist"))
+	assert.OK(t, err)
+
+	// The recovered entries can be read at their new offsets before the archive is closed.
+	for _, entries := range progress.Secrets {
+		for _, entry := range entries {
+			assert.Equal(t, newExportEntry(entry.Name, buf.Bytes()[entry.Offset:entry.Offset+entry.Size]).SHA256, entry.SHA256)
+		}
+	}
+	assert.OK(t, archive.Close())
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.OK(t, err)
+	files := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		assert.OK(t, err)
+		data, err := io.ReadAll(rc)
+		assert.OK(t, err)
+		assert.OK(t, rc.Close())
+		files[f.Name] = string(data)
+	}
+	assert.Equal(t, files, map[string]string{
+		"baz/1": "baz\n",
+		"foo/1": "foo\n",
+		"foo/2": "foo2\n",
+	})
+
+	remaining := make([]string, 0, len(progress.Secrets))
+	for path := range progress.Secrets {
+		remaining = append(remaining, path)
+	}
+	sort.Strings(remaining)
+	assert.Equal(t, remaining, []string{"namespace/repo/baz", "namespace/repo/foo", "namespace/repo/new"})
+}
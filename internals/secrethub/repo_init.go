@@ -46,7 +46,7 @@ func (cmd *RepoInitCommand) Run() error {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Create complete! The repository %s is now ready to use.\n", cmd.path.String())
+	ui.Successf(cmd.io.Output(), "Create complete! The repository %s is now ready to use.\n", cmd.path.String())
 
 	return nil
 }
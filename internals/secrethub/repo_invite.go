@@ -72,7 +72,7 @@ func (cmd *RepoInviteCommand) Run() error {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Invite complete! The user %s is now a member of the %s repository.\n", cmd.username.Value, cmd.path)
+	ui.Successf(cmd.io.Output(), "Invite complete! The user %s is now a member of the %s repository.\n", cmd.username.Value, cmd.path)
 
 	return nil
 }
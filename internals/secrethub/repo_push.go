@@ -0,0 +1,142 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrPushConflicts = errMain.Code("push_conflicts").ErrorPref("%s changed in SecretHub since the working copy was cloned: use --force to overwrite these changes")
+)
+
+// RepoPushCommand writes the local changes in a working copy of a repository to SecretHub.
+type RepoPushCommand struct {
+	dir       string
+	force     bool
+	io        ui.IO
+	newClient newClientFunc
+	importKey func() (keyWrapper, error)
+}
+
+// NewRepoPushCommand creates a new RepoPushCommand.
+func NewRepoPushCommand(io ui.IO, newClient newClientFunc, store CredentialConfig) *RepoPushCommand {
+	return &RepoPushCommand{
+		io:        io,
+		newClient: newClient,
+		importKey: func() (keyWrapper, error) {
+			return newCredentialKeyWrapper(store)
+		},
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RepoPushCommand) Register(r cli.Registerer) {
+	clause := r.Command("push", "Write the secrets that were added, modified or deleted in a working copy created with `secrethub repo clone` to SecretHub.")
+	clause.Flags().BoolVarP(&cmd.force, "force", "f", false, "Do not ask for confirmation and overwrite secrets that were also changed in SecretHub since they were cloned.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.dir, Name: "dir", Required: false, Description: "The directory of the working copy. Defaults to the current directory."},
+	})
+}
+
+// Run writes the changes.
+func (cmd *RepoPushCommand) Run() error {
+	dir := cmd.dir
+	if dir == "" {
+		dir = "."
+	}
+
+	wrapper, err := cmd.importKey()
+	if err != nil {
+		return err
+	}
+
+	wc, err := openWorkingCopy(dir, wrapper)
+	if err != nil {
+		return err
+	}
+
+	changes, err := wc.changes()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.io.Output()
+	if len(changes) == 0 {
+		fmt.Fprintf(out, "No changes to %s.\n", wc.repo())
+		return nil
+	}
+
+	for _, change := range changes {
+		if change.kind != workingCopyDeleted && len(change.value) == 0 {
+			return errEmptySecret
+		}
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	conflicts, err := workingCopyConflicts(client, wc, changes)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Changes to %s:\n", wc.repo())
+	printWorkingCopyChanges(cmd.io, changes, conflicts)
+
+	if len(conflicts) > 0 && !cmd.force {
+		return ErrPushConflicts(pluralize("secret", "secrets", len(conflicts)))
+	}
+
+	if !cmd.force {
+		confirmed, err := ui.AskYesNo(cmd.io, "Are you sure you want to write these changes to SecretHub?", ui.DefaultNo)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(out, "Aborting.")
+			return nil
+		}
+	}
+
+	// The working copy is saved after every change, so that a failure halfway
+	// leaves the working copy consistent with what has been written.
+	for _, change := range changes {
+		path := wc.secretPath(change.path)
+		if change.kind == workingCopyDeleted {
+			err = client.Secrets().Delete(path)
+			if err != nil && err != api.ErrSecretNotFound {
+				return err
+			}
+			err = wc.remove(change.path)
+			if err != nil {
+				return err
+			}
+		} else {
+			version, err := client.Secrets().Write(path, change.value)
+			if err != nil {
+				return err
+			}
+			err = wc.set(change.path, change.value, version.Version)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = wc.save()
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, "Pushed %s to %s.\n", pluralize("change", "changes", len(changes)), wc.repo())
+	return nil
+}
@@ -71,7 +71,7 @@ func (cmd *RepoRmCommand) Run() error {
 		return err
 	}
 
-	fmt.Fprintf(cmd.io.Output(), "Removal complete! The repository %s has been permanently removed.\n", cmd.path)
+	ui.Successf(cmd.io.Output(), "Removal complete! The repository %s has been permanently removed.\n", cmd.path)
 
 	return nil
 }
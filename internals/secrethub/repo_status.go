@@ -0,0 +1,84 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// RepoStatusCommand prints the local changes in a working copy of a repository.
+type RepoStatusCommand struct {
+	dir       string
+	offline   bool
+	io        ui.IO
+	newClient newClientFunc
+	importKey func() (keyWrapper, error)
+}
+
+// NewRepoStatusCommand creates a new RepoStatusCommand.
+func NewRepoStatusCommand(io ui.IO, newClient newClientFunc, store CredentialConfig) *RepoStatusCommand {
+	return &RepoStatusCommand{
+		io:        io,
+		newClient: newClient,
+		importKey: func() (keyWrapper, error) {
+			return newCredentialKeyWrapper(store)
+		},
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RepoStatusCommand) Register(r cli.Registerer) {
+	clause := r.Command("status", "Show the secrets that were added, modified or deleted in a working copy created with `secrethub repo clone`.")
+	clause.Flags().BoolVar(&cmd.offline, "offline", false, "Do not check whether the changed secrets were also changed in SecretHub.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.dir, Name: "dir", Required: false, Description: "The directory of the working copy. Defaults to the current directory."},
+	})
+}
+
+// Run prints the changes.
+func (cmd *RepoStatusCommand) Run() error {
+	dir := cmd.dir
+	if dir == "" {
+		dir = "."
+	}
+
+	wrapper, err := cmd.importKey()
+	if err != nil {
+		return err
+	}
+
+	wc, err := openWorkingCopy(dir, wrapper)
+	if err != nil {
+		return err
+	}
+
+	changes, err := wc.changes()
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintf(cmd.io.Output(), "No changes to %s.\n", wc.repo())
+		return nil
+	}
+
+	conflicts := make(map[string]bool)
+	if !cmd.offline {
+		client, err := cmd.newClient()
+		if err != nil {
+			return err
+		}
+
+		conflicts, err = workingCopyConflicts(client, wc, changes)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Changes to %s:\n", wc.repo())
+	printWorkingCopyChanges(cmd.io, changes, conflicts)
+	return nil
+}
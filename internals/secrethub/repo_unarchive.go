@@ -0,0 +1,74 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// RepoUnarchiveCommand restores the access rules that were revoked when a repo was archived.
+type RepoUnarchiveCommand struct {
+	path      api.RepoPath
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewRepoUnarchiveCommand creates a new RepoUnarchiveCommand.
+func NewRepoUnarchiveCommand(io ui.IO, newClient newClientFunc) *RepoUnarchiveCommand {
+	return &RepoUnarchiveCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RepoUnarchiveCommand) Register(r cli.Registerer) {
+	clause := r.Command("unarchive", "Restore the access rules of a repository that was archived with `secrethub repo archive`.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.path, Name: "repo-path", Required: true, Placeholder: repoPathPlaceHolder, Description: "The repository to unarchive."},
+	})
+}
+
+// Run unarchives the repo.
+func (cmd *RepoUnarchiveCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	archive, err := loadRepoArchive(client, cmd.path)
+	if err != nil {
+		return err
+	}
+	if archive == nil {
+		return ErrRepoNotArchived(cmd.path)
+	}
+
+	out := cmd.io.Output()
+	restored := 0
+	for _, rule := range archive.Rules {
+		_, err = client.AccessRules().Set(rule.Path, rule.Permission, rule.Account)
+		if err == api.ErrDirNotFound {
+			fmt.Fprintf(out, "Skipping %s on %s: the directory no longer exists\n", rule.Account, rule.Path)
+			continue
+		} else if err != nil {
+			return err
+		}
+		restored++
+	}
+
+	// The archive secret is only removed after all access rules have been
+	// restored, so that the command can be run again when it fails halfway.
+	err = client.Secrets().Delete(repoArchiveSecretPath(cmd.path))
+	if err != nil && err != api.ErrSecretNotFound {
+		return err
+	}
+
+	fmt.Fprintf(out, "Unarchived %s and restored %s.\n", cmd.path, pluralize("access rule", "access rules", restored))
+	return nil
+}
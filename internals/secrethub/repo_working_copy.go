@@ -0,0 +1,368 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+)
+
+const (
+	// workingCopyMetadataFileName is the name of the file in the root of a working copy
+	// that records the repo it was cloned from and the versions of the cloned secrets.
+	workingCopyMetadataFileName = ".secrethub-clone.json"
+	// workingCopySecretExtension is the extension of the files containing the encrypted secret values.
+	workingCopySecretExtension = ".secret"
+	// workingCopyGitignoreFileName is the name of the .gitignore file that is written in the root of
+	// a working copy, so that the plaintext files with edited values are not committed to git.
+	workingCopyGitignoreFileName = ".gitignore"
+	// workingCopyGitignore ignores everything in the working copy except the encrypted secrets and the metadata.
+	workingCopyGitignore = "# Written by `secrethub repo clone`.\n" +
+		"# Files without the .secret extension contain plaintext values that have not been pushed yet.\n" +
+		"*\n" +
+		"!*/\n" +
+		"!*" + workingCopySecretExtension + "\n" +
+		"!" + workingCopyMetadataFileName + "\n" +
+		"!" + workingCopyGitignoreFileName + "\n"
+)
+
+// workingCopyBackupSuffixes are the suffixes of the backup and swap files that editors write
+// next to the edited file. These files are not secrets, so they are ignored.
+var workingCopyBackupSuffixes = []string{"~", ".swp", ".swo", ".swx", ".bak", ".orig"}
+
+// Errors
+var (
+	ErrNotAWorkingCopy       = errMain.Code("not_a_working_copy").ErrorPref("%s is not a working copy of a repository: run `secrethub repo clone` first")
+	ErrInvalidWorkingCopy    = errMain.Code("invalid_working_copy").ErrorPref("could not parse the working copy metadata file %s: %s")
+	ErrWorkingCopyKeyInvalid = errMain.Code("working_copy_key_invalid").Error("the working copy was cloned with another credential and cannot be decrypted")
+	ErrCloneDirNotEmpty      = errMain.Code("clone_dir_not_empty").ErrorPref("cannot clone into %s: the directory already exists and is not empty")
+)
+
+// workingCopy is a local copy of the secrets of a repo. Every secret is stored in a file with
+// the path of the secret relative to the repo and the .secret extension, encrypted with a key
+// that is stored in the metadata file, wrapped by the account key.
+//
+// Secrets are edited by creating a file next to the encrypted file, without the extension,
+// that contains the new plaintext value. New secrets are added by creating such a file for a
+// path that does not exist yet and secrets are deleted by removing their encrypted file.
+// The edited values are stored as plaintext until they are pushed.
+//
+// Files and directories of which the name starts with a dot, such as .git and .DS_Store,
+// and backup files of editors, such as foo~ and foo.swp, are ignored unless they belong
+// to a cloned secret.
+type workingCopy struct {
+	dir  string
+	meta workingCopyMetadata
+	key  *crypto.SymmetricKey
+}
+
+// workingCopyMetadata is the content of the metadata file of a working copy.
+type workingCopyMetadata struct {
+	Repo string `json:"repo"`
+	// Key is the symmetric key that encrypts the secrets, wrapped by the account key.
+	Key *api.EncryptedData `json:"key"`
+	// Secrets maps the path of every secret relative to the repo to the version it was cloned at.
+	Secrets map[string]int `json:"secrets"`
+}
+
+// workingCopyChange is a change to a secret in the working copy.
+type workingCopyChange struct {
+	// path is the path of the secret relative to the repo.
+	path string
+	kind string
+	// value is the new value of an added or modified secret.
+	value []byte
+}
+
+const (
+	workingCopyAdded    = "added"
+	workingCopyModified = "modified"
+	workingCopyDeleted  = "deleted"
+)
+
+// newWorkingCopy creates an empty working copy of the repo in the given directory.
+func newWorkingCopy(dir string, repo api.RepoPath, wrapper keyWrapper) (*workingCopy, error) {
+	entries, err := os.ReadDir(dir)
+	if err == nil && len(entries) > 0 {
+		return nil, ErrCloneDirNotEmpty(dir)
+	} else if err != nil && !os.IsNotExist(err) {
+		return nil, ErrCannotReadFile(dir, err)
+	}
+
+	key, err := crypto.GenerateSymmetricKey()
+	if err != nil {
+		return nil, err
+	}
+	wrappedKey, err := wrapper.Wrap(key.Export())
+	if err != nil {
+		return nil, err
+	}
+
+	return &workingCopy{
+		dir: dir,
+		meta: workingCopyMetadata{
+			Repo:    repo.Value(),
+			Key:     wrappedKey,
+			Secrets: make(map[string]int),
+		},
+		key: key,
+	}, nil
+}
+
+// openWorkingCopy opens the working copy in the given directory.
+func openWorkingCopy(dir string, wrapper keyWrapper) (*workingCopy, error) {
+	path := filepath.Join(dir, workingCopyMetadataFileName)
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotAWorkingCopy(dir)
+	} else if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+
+	wc := &workingCopy{dir: dir}
+	err = json.Unmarshal(raw, &wc.meta)
+	if err != nil {
+		return nil, ErrInvalidWorkingCopy(path, err)
+	}
+	if wc.meta.Key == nil {
+		return nil, ErrInvalidWorkingCopy(path, "missing key")
+	}
+	if wc.meta.Secrets == nil {
+		wc.meta.Secrets = make(map[string]int)
+	}
+
+	rawKey, err := wrapper.Unwrap(wc.meta.Key)
+	if err != nil {
+		return nil, ErrWorkingCopyKeyInvalid
+	}
+	wc.key = crypto.NewSymmetricKey(rawKey)
+	return wc, nil
+}
+
+// repo returns the path of the repo the working copy was cloned from.
+func (wc *workingCopy) repo() api.RepoPath {
+	return api.RepoPath(wc.meta.Repo)
+}
+
+// secretPath returns the path of the secret in the repo.
+func (wc *workingCopy) secretPath(path string) string {
+	return wc.meta.Repo + "/" + path
+}
+
+// file returns the location of the plaintext file of the secret.
+func (wc *workingCopy) file(path string) string {
+	return filepath.Join(wc.dir, filepath.FromSlash(path))
+}
+
+// encryptedFile returns the location of the encrypted file of the secret.
+func (wc *workingCopy) encryptedFile(path string) string {
+	return wc.file(path) + workingCopySecretExtension
+}
+
+// set stores the value of the secret at the given version and removes its plaintext file.
+func (wc *workingCopy) set(path string, value []byte, version int) error {
+	ciphertext, err := wc.key.Encrypt(value)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(ciphertext)
+	if err != nil {
+		return err
+	}
+
+	file := wc.encryptedFile(path)
+	err = os.MkdirAll(filepath.Dir(file), 0700)
+	if err != nil {
+		return ErrCannotWrite(file, err)
+	}
+	err = os.WriteFile(file, raw, 0600)
+	if err != nil {
+		return ErrCannotWrite(file, err)
+	}
+
+	err = os.Remove(wc.file(path))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	wc.meta.Secrets[path] = version
+	return nil
+}
+
+// remove removes the secret from the working copy.
+func (wc *workingCopy) remove(path string) error {
+	for _, file := range []string{wc.file(path), wc.encryptedFile(path)} {
+		err := os.Remove(file)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	delete(wc.meta.Secrets, path)
+	return nil
+}
+
+// get returns the value of the secret as it was cloned or last pushed.
+func (wc *workingCopy) get(path string) ([]byte, error) {
+	file := wc.encryptedFile(path)
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, ErrCannotReadFile(file, err)
+	}
+
+	var ciphertext crypto.CiphertextAES
+	err = json.Unmarshal(raw, &ciphertext)
+	if err != nil {
+		return nil, ErrInvalidWorkingCopy(file, err)
+	}
+	return wc.key.Decrypt(ciphertext)
+}
+
+// save writes the metadata file.
+func (wc *workingCopy) save() error {
+	raw, err := json.MarshalIndent(wc.meta, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(wc.dir, workingCopyMetadataFileName)
+	err = atomicfile.Write(path, raw, 0600)
+	if err != nil {
+		return ErrCannotWrite(path, err)
+	}
+	return nil
+}
+
+// writeGitignore writes a .gitignore file in the root of the working copy that excludes the
+// plaintext files from git. An existing .gitignore file is left untouched.
+func (wc *workingCopy) writeGitignore() error {
+	path := filepath.Join(wc.dir, workingCopyGitignoreFileName)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return nil
+	} else if err != nil {
+		return ErrCannotWrite(path, err)
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(workingCopyGitignore)
+	if err != nil {
+		return ErrCannotWrite(path, err)
+	}
+	return nil
+}
+
+// isIgnoredWorkingCopyFile returns whether the file or directory with the given name is not part of the working copy.
+func isIgnoredWorkingCopyFile(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	for _, suffix := range workingCopyBackupSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// tracks returns whether the file or directory at the path relative to the working copy belongs to
+// a cloned secret, so that secrets of which the name looks like an ignored file are not missed.
+func (wc *workingCopy) tracks(rel string, isDir bool) bool {
+	for path := range wc.meta.Secrets {
+		if isDir && strings.HasPrefix(path, rel+"/") {
+			return true
+		}
+		if !isDir && (rel == path || rel == path+workingCopySecretExtension) {
+			return true
+		}
+	}
+	return false
+}
+
+// changes returns the local changes to the secrets in the working copy, sorted by path.
+func (wc *workingCopy) changes() ([]workingCopyChange, error) {
+	encrypted := make(map[string]bool)
+	var plaintext []string
+	err := filepath.WalkDir(wc.dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(wc.dir, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if rel != "." && isIgnoredWorkingCopyFile(d.Name()) && !wc.tracks(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		switch {
+		case strings.HasSuffix(rel, workingCopySecretExtension):
+			encrypted[strings.TrimSuffix(rel, workingCopySecretExtension)] = true
+		default:
+			plaintext = append(plaintext, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []workingCopyChange
+	edited := make(map[string]bool)
+	for _, path := range plaintext {
+		edited[path] = true
+
+		file := wc.file(path)
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, ErrCannotReadFile(file, err)
+		}
+		// Values are trimmed in the same way as with the write command,
+		// so that a newline added by an editor is not counted as a change.
+		value := bytes.TrimSpace(raw)
+
+		if _, ok := wc.meta.Secrets[path]; !ok {
+			changes = append(changes, workingCopyChange{path: path, kind: workingCopyAdded, value: value})
+			continue
+		}
+
+		if !encrypted[path] {
+			changes = append(changes, workingCopyChange{path: path, kind: workingCopyModified, value: value})
+			continue
+		}
+
+		current, err := wc.get(path)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(current, value) {
+			changes = append(changes, workingCopyChange{path: path, kind: workingCopyModified, value: value})
+		}
+	}
+
+	for path := range wc.meta.Secrets {
+		if !encrypted[path] && !edited[path] {
+			changes = append(changes, workingCopyChange{path: path, kind: workingCopyDeleted})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].path < changes[j].path
+	})
+	return changes, nil
+}
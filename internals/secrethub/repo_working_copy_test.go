@@ -0,0 +1,100 @@
+package secrethub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+func TestWorkingCopy_changes(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+	dir = filepath.Join(dir, "repo")
+
+	key, err := credentials.GenerateRSACredential(1024)
+	assert.OK(t, err)
+
+	wc, err := newWorkingCopy(dir, "namespace/repo", key)
+	assert.OK(t, err)
+	assert.OK(t, wc.set("unchanged", []byte("foo"), 1))
+	assert.OK(t, wc.set("modified", []byte("foo"), 2))
+	assert.OK(t, wc.set("dir/deleted", []byte("foo"), 3))
+	assert.OK(t, wc.set(".env", []byte("foo"), 1))
+	assert.OK(t, wc.save())
+	assert.OK(t, wc.writeGitignore())
+
+	// A new working copy cannot be created in the same directory.
+	_, err = newWorkingCopy(dir, "namespace/repo", key)
+	assert.Equal(t, err, ErrCloneDirNotEmpty(dir))
+
+	wc, err = openWorkingCopy(dir, key)
+	assert.OK(t, err)
+	assert.Equal(t, wc.meta.Secrets, map[string]int{"unchanged": 1, "modified": 2, "dir/deleted": 3, ".env": 1})
+
+	changes, err := wc.changes()
+	assert.OK(t, err)
+	assert.Equal(t, len(changes), 0)
+
+	// A trailing newline added by an editor is not a change.
+	assert.OK(t, os.WriteFile(filepath.Join(dir, "unchanged"), []byte("foo\n"), 0600))
+	assert.OK(t, os.WriteFile(filepath.Join(dir, "modified"), []byte("bar\n"), 0600))
+	assert.OK(t, os.MkdirAll(filepath.Join(dir, "dir", "new"), 0700))
+	assert.OK(t, os.WriteFile(filepath.Join(dir, "dir", "new", "added"), []byte("baz"), 0600))
+	assert.OK(t, os.Remove(filepath.Join(dir, "dir", "deleted.secret")))
+
+	// Files of editors, the OS and git are ignored.
+	assert.OK(t, os.WriteFile(filepath.Join(dir, ".modified.swp"), []byte("bar"), 0600))
+	assert.OK(t, os.WriteFile(filepath.Join(dir, "modified~"), []byte("foo"), 0600))
+	assert.OK(t, os.WriteFile(filepath.Join(dir, "dir", ".DS_Store"), []byte("x"), 0600))
+	assert.OK(t, os.MkdirAll(filepath.Join(dir, ".git"), 0700))
+	assert.OK(t, os.WriteFile(filepath.Join(dir, ".git", "config"), []byte("x"), 0600))
+
+	changes, err = wc.changes()
+	assert.OK(t, err)
+	assert.Equal(t, changes, []workingCopyChange{
+		{path: "dir/deleted", kind: workingCopyDeleted},
+		{path: "dir/new/added", kind: workingCopyAdded, value: []byte("baz")},
+		{path: "modified", kind: workingCopyModified, value: []byte("bar")},
+	})
+
+	// Storing a pushed value removes the plaintext file.
+	assert.OK(t, wc.set("modified", []byte("bar"), 4))
+	_, err = os.Stat(filepath.Join(dir, "modified"))
+	assert.Equal(t, os.IsNotExist(err), true)
+	value, err := wc.get("modified")
+	assert.OK(t, err)
+	assert.Equal(t, string(value), "bar")
+}
+
+func TestWorkingCopy_writeGitignore(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	wc := &workingCopy{dir: dir}
+	assert.OK(t, wc.writeGitignore())
+
+	raw, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	assert.OK(t, err)
+	assert.Equal(t, string(raw), workingCopyGitignore)
+
+	// An existing .gitignore is not overwritten.
+	assert.OK(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("custom\n"), 0600))
+	assert.OK(t, wc.writeGitignore())
+	raw, err = os.ReadFile(filepath.Join(dir, ".gitignore"))
+	assert.OK(t, err)
+	assert.Equal(t, string(raw), "custom\n")
+}
+
+func TestOpenWorkingCopy_notAWorkingCopy(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	key, err := credentials.GenerateRSACredential(1024)
+	assert.OK(t, err)
+
+	_, err = openWorkingCopy(dir, key)
+	assert.Equal(t, err, ErrNotAWorkingCopy(dir))
+}
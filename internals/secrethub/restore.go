@@ -0,0 +1,64 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// RestoreCommand restores a secret that was removed with rm from the trash.
+type RestoreCommand struct {
+	io        ui.IO
+	path      api.SecretPath
+	force     bool
+	newClient newClientFunc
+	trash     *SecretTrash
+}
+
+// NewRestoreCommand creates a new RestoreCommand.
+func NewRestoreCommand(io ui.IO, newClient newClientFunc, trash *SecretTrash) *RestoreCommand {
+	return &RestoreCommand{
+		io:        io,
+		newClient: newClient,
+		trash:     trash,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RestoreCommand) Register(r cli.Registerer) {
+	clause := r.Command("restore", "Restore a secret that was removed with rm.")
+	clause.HelpLong("When a secret is removed with `secrethub rm`, the values of all of its versions are first copied to an encrypted trash on this machine. " +
+		"For 7 days after the removal, the secret can be restored from the trash with this command. " +
+		"The versions are written again from the oldest to the newest, so they get new version numbers. " +
+		"Secrets removed with --permanent, on another machine or with another credential cannot be restored.")
+	clause.Flags().BoolVarP(&cmd.force, "force", "f", false, "Restore the secret even when a secret exists at the path. The restored versions are added to it as new versions.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{{Value: &cmd.path, Name: "path", Required: true, Placeholder: secretPathPlaceHolder, Description: "The path of the removed secret."}})
+}
+
+// Run restores the secret from the trash.
+func (cmd *RestoreCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Secrets().Get(cmd.path.Value())
+	if err == nil && !cmd.force {
+		return ErrSecretAlreadyExists
+	} else if err != nil && !api.IsErrNotFound(err) {
+		return err
+	}
+
+	versions, err := cmd.trash.restore(client, cmd.path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Restore complete! %d versions of %s have been restored.\n", versions, cmd.path)
+	return nil
+}
@@ -0,0 +1,150 @@
+package secrethub
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRetryBackoff is the longest time that is waited before a request is retried.
+const maxRetryBackoff = 30 * time.Second
+
+// retryTransport retries requests that failed with a network error, a server error or because
+// of rate limiting. Every attempt can be given a timeout. Between attempts, it waits for an
+// exponentially increasing backoff with jitter, or for the time the server asks for in the
+// Retry-After header.
+type retryTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// newRetryTransport creates a new retryTransport that retries a request at most the given
+// number of times and gives up on an attempt after the given timeout, unless it is 0.
+func newRetryTransport(base http.RoundTripper, timeout time.Duration, retries int, backoff time.Duration) *retryTransport {
+	return &retryTransport{
+		base:    base,
+		timeout: timeout,
+		retries: retries,
+		backoff: backoff,
+		sleep:   sleepContext,
+	}
+}
+
+// RoundTrip executes the request and retries it when it fails with a retryable error.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(req)
+
+		// A request with a body can only be retried when the body can be read again.
+		canRetry := attempt < t.retries && (req.Body == nil || req.GetBody != nil) && req.Context().Err() == nil
+		if !canRetry || !isRetryable(resp, err) {
+			return resp, err
+		}
+
+		wait := t.wait(attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		logger.Debugf("retrying %s %s in %s", req.Method, req.URL.Path, wait)
+
+		err = t.sleep(req.Context(), wait)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// attempt executes the request once, within the timeout when it is set.
+func (t *retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The timeout also covers reading the body, so it is only released when the body is closed.
+	resp.Body = &cancelReadCloser{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// wait returns the time to wait before the next attempt. The backoff doubles with every attempt
+// and a random jitter of up to half the backoff is subtracted, so that clients that failed at the
+// same time do not retry at the same time.
+func (t *retryTransport) wait(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			wait := time.Duration(seconds) * time.Second
+			if wait > maxRetryBackoff {
+				return maxRetryBackoff
+			}
+			return wait
+		}
+	}
+
+	if t.backoff <= 0 {
+		return 0
+	}
+	backoff := t.backoff
+	for i := 0; i < attempt && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	return backoff - time.Duration(rand.Int63n(int64(backoff)/2+1))
+}
+
+// isRetryable returns whether a request that resulted in the given response or error can be retried.
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// sleepContext waits for the given duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cancelReadCloser cancels a context when it is closed.
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.cancel()
+	return err
+}
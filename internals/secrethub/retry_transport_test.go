@@ -0,0 +1,157 @@
+package secrethub
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestRetryTransport(t *testing.T) {
+	errNetwork := errors.New("connection reset")
+
+	cases := map[string]struct {
+		retries        int
+		responses      []int
+		expectedStatus int
+		expectedErr    error
+		expectedCalls  int
+	}{
+		"success": {
+			retries:        3,
+			responses:      []int{http.StatusOK},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  1,
+		},
+		"retry after server error": {
+			retries:        3,
+			responses:      []int{http.StatusBadGateway, http.StatusOK},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+		},
+		"retry after too many requests": {
+			retries:        3,
+			responses:      []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  3,
+		},
+		"retry after network error": {
+			retries:        3,
+			responses:      []int{0, http.StatusOK},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+		},
+		"no retry after client error": {
+			retries:        3,
+			responses:      []int{http.StatusNotFound},
+			expectedStatus: http.StatusNotFound,
+			expectedCalls:  1,
+		},
+		"retries exhausted": {
+			retries:        2,
+			responses:      []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusServiceUnavailable},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCalls:  3,
+		},
+		"retries disabled": {
+			retries:       0,
+			responses:     []int{0},
+			expectedErr:   errNetwork,
+			expectedCalls: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			var bodies []string
+			transport := newRetryTransport(fakeRoundTripper(func(req *http.Request) (*http.Response, error) {
+				body, err := io.ReadAll(req.Body)
+				assert.OK(t, err)
+				bodies = append(bodies, string(body))
+
+				status := tc.responses[calls]
+				calls++
+				if status == 0 {
+					return nil, errNetwork
+				}
+				return &http.Response{
+					StatusCode: status,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader("response")),
+				}, nil
+			}), 0, tc.retries, time.Second)
+			transport.sleep = func(ctx context.Context, d time.Duration) error {
+				return nil
+			}
+
+			req, err := http.NewRequest("POST", "https://api.secrethub.io/repos/dev1/example", strings.NewReader("body"))
+			assert.OK(t, err)
+
+			resp, err := transport.RoundTrip(req)
+
+			assert.Equal(t, err, tc.expectedErr)
+			if err == nil {
+				assert.Equal(t, resp.StatusCode, tc.expectedStatus)
+			}
+			assert.Equal(t, calls, tc.expectedCalls)
+			for _, body := range bodies {
+				assert.Equal(t, body, "body")
+			}
+		})
+	}
+}
+
+func TestRetryTransport_wait(t *testing.T) {
+	cases := map[string]struct {
+		backoff    time.Duration
+		attempt    int
+		retryAfter string
+		min        time.Duration
+		max        time.Duration
+	}{
+		"first attempt": {
+			backoff: time.Second,
+			min:     500 * time.Millisecond,
+			max:     time.Second,
+		},
+		"third attempt": {
+			backoff: time.Second,
+			attempt: 2,
+			min:     2 * time.Second,
+			max:     4 * time.Second,
+		},
+		"capped": {
+			backoff: time.Second,
+			attempt: 100,
+			min:     maxRetryBackoff / 2,
+			max:     maxRetryBackoff,
+		},
+		"retry after": {
+			backoff:    time.Second,
+			retryAfter: "7",
+			min:        7 * time.Second,
+			max:        7 * time.Second,
+		},
+		"no backoff": {},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			transport := newRetryTransport(nil, 0, 3, tc.backoff)
+			resp := &http.Response{Header: http.Header{}}
+			if tc.retryAfter != "" {
+				resp.Header.Set("Retry-After", tc.retryAfter)
+			}
+
+			wait := transport.wait(tc.attempt, resp)
+
+			assert.Equal(t, wait >= tc.min && wait <= tc.max, true)
+		})
+	}
+}
@@ -20,18 +20,27 @@ var (
 
 // RmCommand handles removing a resource.
 type RmCommand struct {
-	path      api.Path
-	recursive bool
-	force     bool
-	io        ui.IO
-	newClient newClientFunc
+	path       api.Path
+	recursive  bool
+	force      bool
+	io         ui.IO
+	newClient  newClientFunc
+	batch      bool
+	fromFile   string
+	dryRun     bool
+	failFast   bool
+	permanent  bool
+	trash      *SecretTrash
+	completion *CompletionCache
 }
 
 // NewRmCommand creates a new RmCommand.
-func NewRmCommand(io ui.IO, newClient newClientFunc) *RmCommand {
+func NewRmCommand(io ui.IO, newClient newClientFunc, trash *SecretTrash, completion *CompletionCache) *RmCommand {
 	return &RmCommand{
-		io:        io,
-		newClient: newClient,
+		io:         io,
+		newClient:  newClient,
+		trash:      trash,
+		completion: completion,
 	}
 }
 
@@ -41,22 +50,72 @@ func (cmd *RmCommand) Register(r cli.Registerer) {
 	clause.Alias("remove")
 	clause.Flags().BoolVarP(&cmd.recursive, "recursive", "r", false, "Remove directories and their contents recursively.")
 	registerForceFlag(clause, &cmd.force)
+	clause.Flags().BoolVar(&cmd.permanent, "permanent", false, "Do not keep a copy of removed secrets in the trash. By default, removed secrets are kept in an encrypted trash on this machine for 7 days, so that they can be restored with `secrethub restore`.")
+	clause.Flags().StringVar(&cmd.fromFile, "from-file", "", "Remove the resources at the paths in this file, one per line, or on stdin when set to -. Empty lines and lines starting with # are skipped. "+
+		"A summary is shown first and the removal has to be confirmed by typing the number of resources, unless --force is set.")
+	clause.Flags().BoolVar(&cmd.dryRun, "dry-run", false, "With --from-file, only show what would be removed.")
+	clause.Flags().BoolVar(&cmd.failFast, "fail-fast", false, "With --from-file, stop at the first path that cannot be removed. By default, the other paths are still removed.")
+	registerStdinNullDelimitedFlag(clause, &cmd.batch, &cmd.fromFile)
+	cmd.completion.registerArgument(clause, pathKindDir|pathKindSecret)
 
 	clause.BindAction(cmd.Run)
-	clause.BindArguments([]cli.Argument{{Value: &cmd.path, Name: "path", Required: true, Placeholder: generalPathPlaceHolder, Description: "The path to the resource to remove."}})
+	clause.BindArguments([]cli.Argument{{Value: &cmd.path, Name: "path", Required: false, Placeholder: generalPathPlaceHolder, Description: "The path to the resource to remove. Required unless --stdin-null-delimited or --from-file is set."}})
 }
 
 // Run removes the resource at the given path.
 // Removes a secret, secret-version or directory.
 // To remove a directory the -r flag must be set.
 func (cmd *RmCommand) Run() error {
+	if cmd.fromFile != "" {
+		return cmd.runFromFile()
+	}
+	if cmd.batch {
+		return cmd.runBatch()
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	return rmPath(client, cmd.path, cmd.recursive, cmd.force, cmd.secretTrash(), cmd.io)
+}
+
+// runBatch removes the resources at the NUL-delimited paths on stdin and
+// writes the result for every path to the output as a line of JSON.
+func (cmd *RmCommand) runBatch() error {
+	if !cmd.force {
+		return ErrBatchRequiresForce
+	}
+
 	client, err := cmd.newClient()
 	if err != nil {
 		return err
 	}
 
-	if !cmd.path.HasVersion() {
-		dirPath, err := cmd.path.ToDirPath()
+	return runBatch(cmd.io.Input(), cmd.io.Output(), func(path string) (*string, error) {
+		p, err := api.NewPath(path)
+		if err != nil {
+			return nil, err
+		}
+		return nil, rmPath(client, p, cmd.recursive, true, cmd.secretTrash(), discardOutputIO{cmd.io})
+	})
+}
+
+// secretTrash returns the trash that removed secrets are copied to, or nil when --permanent is set.
+func (cmd *RmCommand) secretTrash() *SecretTrash {
+	if cmd.permanent {
+		return nil
+	}
+	return cmd.trash
+}
+
+// rmPath removes the secret, secret version or directory at the given path.
+// Directories are only removed when recursive is set. A secret is copied to
+// the trash before it is removed, unless trash is nil.
+func rmPath(client secrethub.ClientInterface, path api.Path, recursive bool, force bool, trash *SecretTrash, io ui.IO) error {
+	if !path.HasVersion() {
+		dirPath, err := path.ToDirPath()
 		if err != nil {
 			return err
 		}
@@ -67,31 +126,31 @@ func (cmd *RmCommand) Run() error {
 
 		_, err = client.Dirs().GetTree(dirPath.Value(), -1, false)
 		if err == nil {
-			if !cmd.recursive {
+			if !recursive {
 				return ErrCannotRemoveDir
 			}
-			return rmDir(client, dirPath, cmd.force, cmd.io)
+			return rmDir(client, dirPath, force, io)
 		} else if !api.IsErrNotFound(err) {
 			return err
 		}
 	}
 
-	secretPath, err := cmd.path.ToSecretPath()
+	secretPath, err := path.ToSecretPath()
 	if err != nil {
 		return err
 	}
 
-	if cmd.path.HasVersion() {
-		return rmSecretVersion(client, secretPath, cmd.force, cmd.io)
+	if path.HasVersion() {
+		return rmSecretVersion(client, secretPath, force, io)
 	}
 
 	// Check if the secret exists first so we can return a generic error here instead of ErrSecretNotFound.
 	_, err = client.Secrets().Get(secretPath.Value())
 	if api.IsErrNotFound(err) {
-		return ErrResourceNotFound(cmd.path)
+		return ErrResourceNotFound(path)
 	}
 
-	return rmSecret(client, secretPath, cmd.force, cmd.io)
+	return rmSecret(client, secretPath, force, trash, io)
 }
 
 func rmSecretVersion(client secrethub.ClientInterface, secretPath api.SecretPath, force bool, io ui.IO) error {
@@ -129,7 +188,7 @@ func rmSecretVersion(client secrethub.ClientInterface, secretPath api.SecretPath
 	return nil
 }
 
-func rmSecret(client secrethub.ClientInterface, secretPath api.SecretPath, force bool, io ui.IO) error {
+func rmSecret(client secrethub.ClientInterface, secretPath api.SecretPath, force bool, trash *SecretTrash, io ui.IO) error {
 	ok, err := askRmConfirmation(
 		io,
 		fmt.Sprintf("This will permanently remove the %s secret and all its versions. "+
@@ -145,11 +204,28 @@ func rmSecret(client secrethub.ClientInterface, secretPath api.SecretPath, force
 		return nil
 	}
 
+	if trash != nil {
+		err = trash.add(client, secretPath)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = client.Secrets().Delete(secretPath.Value())
 	if err != nil {
 		return err
 	}
 
+	if trash != nil {
+		fmt.Fprintf(
+			io.Output(),
+			"Removal complete! The secret %s has been removed. It can be restored with `secrethub restore %s` for 7 days.\n",
+			secretPath,
+			secretPath,
+		)
+		return nil
+	}
+
 	fmt.Fprintf(
 		io.Output(),
 		"Removal complete! The secret %s has been permanently removed.\n",
@@ -0,0 +1,182 @@
+package secrethub
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrNothingToRemove = errMain.Code("nothing_to_remove").Error("none of the paths can be removed")
+)
+
+// rmTarget is a resource that is removed from a file of paths.
+type rmTarget struct {
+	path api.Path
+	kind string
+	err  error
+}
+
+// runFromFile removes the resources at the paths in the file set with --from-file, or on stdin when it is -.
+// It first shows a summary of what will be removed and asks to confirm by typing the number of resources.
+// A path that cannot be removed is reported, but does not stop the removal of the other paths unless --fail-fast is set.
+func (cmd *RmCommand) runFromFile() error {
+	if cmd.batch {
+		return ErrFlagsConflict("--from-file and --stdin-null-delimited")
+	}
+	if cmd.path != "" {
+		return ErrFlagsConflict("--from-file and the path argument")
+	}
+
+	var paths []string
+	var err error
+	if cmd.fromFile == "-" {
+		paths, err = parsePaths(cmd.io.Input(), "stdin")
+	} else {
+		paths, err = readPathsFile(cmd.fromFile)
+	}
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return ErrPathRequired
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	targets := make([]rmTarget, len(paths))
+	removable := 0
+	for i, path := range paths {
+		targets[i] = cmd.resolveTarget(client, path)
+		if targets[i].err != nil {
+			if cmd.failFast {
+				return targets[i].err
+			}
+			continue
+		}
+		removable++
+	}
+
+	w := cmd.io.Output()
+	fmt.Fprintf(w, "%d of %d paths can be removed:\n", removable, len(targets))
+	for _, target := range targets {
+		if target.err != nil {
+			fmt.Fprintf(w, "  skip    %s: %s\n", target.path, target.err)
+			continue
+		}
+		fmt.Fprintf(w, "  remove  %s %s\n", target.kind, target.path)
+	}
+
+	if removable == 0 {
+		return ErrNothingToRemove
+	}
+	if cmd.dryRun {
+		fmt.Fprintln(w, "Nothing has been removed, because --dry-run is set.")
+		return nil
+	}
+
+	if !cmd.force {
+		confirmed, err := ui.ConfirmCaseInsensitive(
+			cmd.io,
+			fmt.Sprintf("[WARNING] This action cannot be undone. This will permanently remove %d resources. "+
+				"Please type in the number of resources to confirm", removable),
+			strconv.Itoa(removable),
+		)
+		if err == ui.ErrCannotAsk {
+			return ErrCannotDoWithoutForce
+		} else if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(w, "Number does not match. Aborting.")
+			return nil
+		}
+	}
+
+	failed := len(targets) - removable
+	removed := 0
+	for _, target := range targets {
+		if target.err != nil {
+			continue
+		}
+
+		err = rmPath(client, target.path, cmd.recursive, true, cmd.secretTrash(), discardOutputIO{cmd.io})
+		if err != nil {
+			failed++
+			fmt.Fprintf(w, "Could not remove %s: %s\n", target.path, err)
+			if cmd.failFast {
+				return err
+			}
+			continue
+		}
+		removed++
+		fmt.Fprintf(w, "Removed %s\n", target.path)
+	}
+
+	fmt.Fprintf(w, "Removal complete! %d of %d paths have been permanently removed.\n", removed, len(targets))
+	if failed > 0 {
+		return ErrBatchFailed(failed, len(targets))
+	}
+	return nil
+}
+
+// resolveTarget determines what is at the given path, with the same checks as rmPath.
+func (cmd *RmCommand) resolveTarget(client secrethub.ClientInterface, path string) rmTarget {
+	p, err := api.NewPath(path)
+	if err != nil {
+		return rmTarget{path: api.Path(path), err: err}
+	}
+	target := rmTarget{path: p}
+
+	if p.HasVersion() {
+		target.kind = "secret version"
+		return target
+	}
+
+	dirPath, err := p.ToDirPath()
+	if err != nil {
+		target.err = err
+		return target
+	}
+	if dirPath.IsRepoPath() {
+		target.err = ErrCannotRemoveRootDir
+		return target
+	}
+
+	_, err = client.Dirs().GetTree(dirPath.Value(), -1, false)
+	if err == nil {
+		if !cmd.recursive {
+			target.err = ErrCannotRemoveDir
+			return target
+		}
+		target.kind = "directory"
+		return target
+	} else if !api.IsErrNotFound(err) {
+		target.err = err
+		return target
+	}
+
+	secretPath, err := p.ToSecretPath()
+	if err != nil {
+		target.err = err
+		return target
+	}
+	_, err = client.Secrets().Get(secretPath.Value())
+	if api.IsErrNotFound(err) {
+		target.err = ErrResourceNotFound(p)
+		return target
+	} else if err != nil {
+		target.err = err
+		return target
+	}
+	target.kind = "secret"
+	return target
+}
@@ -0,0 +1,134 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestRmCommand_runFromFile(t *testing.T) {
+	testErr := errors.New("test")
+
+	const paths = "# old secrets\nnamespace/repo/dir\n\nnamespace/repo/secret\nnamespace/repo/missing\n"
+	summary := "2 of 3 paths can be removed:\n" +
+		"  remove  directory namespace/repo/dir\n" +
+		"  remove  secret namespace/repo/secret\n" +
+		"  skip    namespace/repo/missing: " + ErrResourceNotFound("namespace/repo/missing").Error() + "\n"
+
+	cases := map[string]struct {
+		cmd             RmCommand
+		in              string
+		deleteSecretErr error
+		expectedRemoved []string
+		expectedOut     string
+		expectedErr     error
+	}{
+		"dry run": {
+			cmd: RmCommand{
+				recursive: true,
+				dryRun:    true,
+			},
+			expectedOut: summary + "Nothing has been removed, because --dry-run is set.\n",
+		},
+		"confirmed": {
+			cmd: RmCommand{
+				recursive: true,
+			},
+			in:              "2",
+			expectedRemoved: []string{"namespace/repo/dir", "namespace/repo/secret"},
+			expectedOut: summary +
+				"Removed namespace/repo/dir\n" +
+				"Removed namespace/repo/secret\n" +
+				"Removal complete! 2 of 3 paths have been permanently removed.\n",
+			expectedErr: ErrBatchFailed(1, 3),
+		},
+		"wrong count": {
+			cmd: RmCommand{
+				recursive: true,
+			},
+			in:          "3",
+			expectedOut: summary + "Number does not match. Aborting.\n",
+		},
+		"failure continues": {
+			cmd: RmCommand{
+				recursive: true,
+				force:     true,
+			},
+			deleteSecretErr: testErr,
+			expectedRemoved: []string{"namespace/repo/dir", "namespace/repo/secret"},
+			expectedOut: summary +
+				"Removed namespace/repo/dir\n" +
+				"Could not remove namespace/repo/secret: " + testErr.Error() + "\n" +
+				"Removal complete! 1 of 3 paths have been permanently removed.\n",
+			expectedErr: ErrBatchFailed(2, 3),
+		},
+		"fail fast": {
+			cmd: RmCommand{
+				recursive: true,
+				force:     true,
+				failFast:  true,
+			},
+			expectedErr: ErrResourceNotFound("namespace/repo/missing"),
+		},
+		"directory without recursive": {
+			cmd: RmCommand{
+				force:    true,
+				failFast: true,
+			},
+			expectedErr: ErrCannotRemoveDir,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			io.In.Buffer = bytes.NewBufferString(paths)
+			io.PromptIn.Buffer = bytes.NewBufferString(tc.in)
+			tc.cmd.io = io
+			tc.cmd.fromFile = "-"
+
+			var removed []string
+			tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					SecretService: &fakeclient.SecretService{
+						GetFunc: func(path string) (*api.Secret, error) {
+							if path == "namespace/repo/secret" {
+								return &api.Secret{}, nil
+							}
+							return nil, api.ErrSecretNotFound
+						},
+						DeleteFunc: func(path string) error {
+							removed = append(removed, path)
+							return tc.deleteSecretErr
+						},
+					},
+					DirService: &fakeclient.DirService{
+						GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+							if path == "namespace/repo/dir" {
+								return &api.Tree{}, nil
+							}
+							return nil, api.ErrDirNotFound
+						},
+						DeleteFunc: func(path string) error {
+							removed = append(removed, path)
+							return nil
+						},
+					},
+				}, nil
+			}
+
+			err := tc.cmd.Run()
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+			assert.Equal(t, removed, tc.expectedRemoved)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+const (
+	// rotationPolicyLabel is the label that sets how often a secret is rotated and how its new value is
+	// generated, as `<interval>` or `<interval> profile:<name>`, e.g. `90d profile:alnum32`.
+	rotationPolicyLabel = "rotation-policy"
+	// rotationProfileLabel is the label that stores the profile of a rotation policy as JSON when the profile
+	// comes from a profile file, so that the file is not needed to rotate the secret.
+	rotationProfileLabel = "rotation-profile"
+
+	defaultRotationGenerator = "profile:alnum32"
+)
+
+// Errors
+var (
+	ErrInvalidRotationPolicy    = errMain.Code("invalid_rotation_policy").ErrorPref("invalid rotation policy %q of %s: must be of the form <interval> or <interval> profile:<name>")
+	ErrInvalidRotationProfile   = errMain.Code("invalid_rotation_profile").ErrorPref("could not parse the rotation profile of %s: %s")
+	ErrInvalidRotationGenerator = errMain.Code("invalid_rotation_generator").ErrorPref("invalid generator %s: must be of the form profile:<name>")
+	ErrRotationPolicyNotFound   = errMain.Code("rotation_policy_not_found").ErrorPref("no rotation policy is set for %s")
+)
+
+// RotateCommand handles the scheduled rotation of secrets.
+type RotateCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewRotateCommand creates a new RotateCommand.
+func NewRotateCommand(io ui.IO, newClient newClientFunc) *RotateCommand {
+	return &RotateCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *RotateCommand) Register(r cli.Registerer) {
+	clause := r.Command("rotate", "Rotate secrets on a schedule.")
+	clause.HelpLong("A rotation policy sets how often a secret has to be rotated and how its new value is generated. " +
+		"The policy is stored in the " + rotationPolicyLabel + " label of the secret, as `<interval>` or `<interval> profile:<name>`, " +
+		"so it applies to everyone who can read the directory of the secret. It can also be set with `secrethub meta set`. " +
+		"A secret is overdue when its latest version is older than the interval of its policy.\n\n" +
+		"Run `secrethub rotate run <dir-path>` periodically, e.g. from cron or a CI pipeline, to rotate the overdue secrets.")
+
+	policies := NewRotationPolicyStore(cmd.newClient)
+	NewRotateSetPolicyCommand(cmd.io, policies).Register(clause)
+	NewRotateRemovePolicyCommand(cmd.io, policies).Register(clause)
+	NewRotateStatusCommand(cmd.io, cmd.newClient, policies).Register(clause)
+	NewRotateRunCommand(cmd.io, cmd.newClient, policies).Register(clause)
+}
+
+// rotationPolicy sets how often the secret at Path is rotated and how its new value is generated.
+type rotationPolicy struct {
+	Path      string
+	Every     string
+	Generator string
+	Profile   generateProfile
+	// custom is set when the profile does not come from the built-in profiles,
+	// so that it has to be stored with the policy.
+	custom bool
+}
+
+// RotationPolicyStore reads and writes the rotation policies in the labels of the secrets.
+// The API does not support rotation policies, so they are enforced by the CLI.
+type RotationPolicyStore struct {
+	newClient newClientFunc
+}
+
+// NewRotationPolicyStore creates a new RotationPolicyStore.
+func NewRotationPolicyStore(newClient newClientFunc) *RotationPolicyStore {
+	return &RotationPolicyStore{
+		newClient: newClient,
+	}
+}
+
+// list returns the rotation policies of the secrets in the directory and its subdirectories, sorted by path.
+func (s *RotationPolicyStore) list(dirPath api.DirPath) ([]rotationPolicy, error) {
+	client, err := s.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := findLabeledSecrets(client, dirPath, labelFilter{rotationPolicyLabel: ""})
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]rotationPolicy, len(secrets))
+	for i, secret := range secrets {
+		policies[i], err = parseRotationPolicy(secret.path, secret.labels)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return policies, nil
+}
+
+// set stores the policy in the labels of its secret, replacing an earlier policy.
+func (s *RotationPolicyStore) set(policy rotationPolicy) error {
+	client, err := s.newClient()
+	if err != nil {
+		return err
+	}
+
+	path := api.SecretPath(policy.Path)
+	if isSecretMetaName(path.Value()) {
+		return ErrMetaOfMetaSecret(path)
+	}
+	labels, err := readSecretLabels(client, path)
+	if err != nil {
+		return err
+	}
+
+	labels[rotationPolicyLabel] = policy.Every
+	if policy.Generator != defaultRotationGenerator {
+		labels[rotationPolicyLabel] += " " + policy.Generator
+	}
+	delete(labels, rotationProfileLabel)
+	if policy.custom {
+		profile, err := json.Marshal(policy.Profile)
+		if err != nil {
+			return err
+		}
+		labels[rotationProfileLabel] = string(profile)
+	}
+	return writeSecretLabels(client, path, labels)
+}
+
+// remove removes the policy from the labels of the secret at the given path.
+func (s *RotationPolicyStore) remove(path api.SecretPath) error {
+	client, err := s.newClient()
+	if err != nil {
+		return err
+	}
+
+	labels, err := readSecretLabels(client, path)
+	if err != nil {
+		return err
+	}
+	if _, ok := labels[rotationPolicyLabel]; !ok {
+		return ErrRotationPolicyNotFound(path)
+	}
+
+	delete(labels, rotationPolicyLabel)
+	delete(labels, rotationProfileLabel)
+	return writeSecretLabels(client, path, labels)
+}
+
+// parseRotationPolicy parses the rotation policy from the labels of the secret at the given path.
+func parseRotationPolicy(path api.SecretPath, labels map[string]string) (rotationPolicy, error) {
+	value := labels[rotationPolicyLabel]
+	fields := strings.Fields(value)
+	if len(fields) < 1 || len(fields) > 2 {
+		return rotationPolicy{}, ErrInvalidRotationPolicy(value, path)
+	}
+
+	policy := rotationPolicy{
+		Path:      path.Value(),
+		Every:     fields[0],
+		Generator: defaultRotationGenerator,
+	}
+	if len(fields) == 2 {
+		policy.Generator = fields[1]
+	}
+
+	_, err := parseDayDuration(policy.Every)
+	if err != nil {
+		return rotationPolicy{}, ErrInvalidRotationPolicy(value, path)
+	}
+	profileName := strings.TrimPrefix(policy.Generator, "profile:")
+	if profileName == policy.Generator || profileName == "" {
+		return rotationPolicy{}, ErrInvalidRotationPolicy(value, path)
+	}
+
+	if raw, ok := labels[rotationProfileLabel]; ok {
+		err = json.Unmarshal([]byte(raw), &policy.Profile)
+		if err != nil {
+			return rotationPolicy{}, ErrInvalidRotationProfile(path, err)
+		}
+		policy.custom = true
+		err = policy.Profile.validate(profileName)
+		if err != nil {
+			return rotationPolicy{}, err
+		}
+		return policy, nil
+	}
+
+	policy.Profile, err = loadProfile(profileName, "")
+	if err != nil {
+		return rotationPolicy{}, err
+	}
+	return policy, nil
+}
+
+// rotationStatus is the state of the secret that a rotation policy applies to.
+type rotationStatus struct {
+	policy rotationPolicy
+	// lastRotated is the time the latest version of the secret was written.
+	// It is the zero time when the secret does not exist yet.
+	lastRotated time.Time
+	due         time.Time
+}
+
+// overdue returns whether the secret has to be rotated at the given time.
+func (s rotationStatus) overdue(now time.Time) bool {
+	return s.lastRotated.IsZero() || !now.Before(s.due)
+}
+
+// getRotationStatuses returns the status of the secret of every policy.
+func getRotationStatuses(newClient newClientFunc, policies []rotationPolicy) ([]rotationStatus, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]rotationStatus, len(policies))
+	for i, policy := range policies {
+		interval, err := parseDayDuration(policy.Every)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses[i] = rotationStatus{policy: policy}
+		version, err := client.Secrets().Versions().GetWithoutData(policy.Path)
+		if err == api.ErrSecretNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		statuses[i].lastRotated = version.CreatedAt
+		statuses[i].due = version.CreatedAt.Add(interval)
+	}
+	return statuses, nil
+}
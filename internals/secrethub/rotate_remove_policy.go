@@ -0,0 +1,46 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// RotateRemovePolicyCommand removes the rotation policy of a secret.
+type RotateRemovePolicyCommand struct {
+	path     api.SecretPath
+	io       ui.IO
+	policies *RotationPolicyStore
+}
+
+// NewRotateRemovePolicyCommand creates a new RotateRemovePolicyCommand.
+func NewRotateRemovePolicyCommand(io ui.IO, policies *RotationPolicyStore) *RotateRemovePolicyCommand {
+	return &RotateRemovePolicyCommand{
+		io:       io,
+		policies: policies,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RotateRemovePolicyCommand) Register(r cli.Registerer) {
+	clause := r.Command("rm-policy", "Stop rotating a secret. The secret itself is not removed.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.path, Name: "path", Required: true, Placeholder: secretPathPlaceHolder, Description: "The path to the secret to stop rotating."},
+	})
+}
+
+// Run removes the rotation policy.
+func (cmd *RotateRemovePolicyCommand) Run() error {
+	err := cmd.policies.remove(cmd.path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "%s will no longer be rotated.\n", cmd.path.Value())
+	return nil
+}
@@ -0,0 +1,111 @@
+package secrethub
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// RotateRunCommand rotates the secrets that are overdue.
+type RotateRunCommand struct {
+	path      api.DirPath
+	dryRun    bool
+	io        ui.IO
+	newClient newClientFunc
+	policies  *RotationPolicyStore
+	now       func() time.Time
+}
+
+// NewRotateRunCommand creates a new RotateRunCommand.
+func NewRotateRunCommand(io ui.IO, newClient newClientFunc, policies *RotationPolicyStore) *RotateRunCommand {
+	return &RotateRunCommand{
+		io:        io,
+		newClient: newClient,
+		policies:  policies,
+		now:       time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RotateRunCommand) Register(r cli.Registerer) {
+	clause := r.Command("run", "Rotate the secrets that are overdue.")
+	clause.HelpLong("Every overdue secret is rotated by writing a new version that is generated with the generator of its policy. " +
+		"The parts of a key pair are written to the sibling paths, like `secrethub generate --profile` does. " +
+		"Note that the applications that use a secret have to read it again to use its new version.")
+	clause.Flags().BoolVar(&cmd.dryRun, "dry-run", false, "Only print the secrets that would be rotated.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.path, Name: "dir-path", Required: true, Placeholder: dirPathPlaceHolder, Description: "The path of the directory to rotate the overdue secrets of, including its subdirectories."},
+	})
+}
+
+// Run rotates the secrets that are overdue.
+func (cmd *RotateRunCommand) Run() error {
+	policies, err := cmd.policies.list(cmd.path)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := getRotationStatuses(cmd.newClient, policies)
+	if err != nil {
+		return err
+	}
+
+	now := cmd.now()
+	rotated := 0
+	for _, status := range statuses {
+		if !status.overdue(now) {
+			continue
+		}
+
+		if cmd.dryRun {
+			fmt.Fprintf(cmd.io.Output(), "Would rotate %s.\n", status.policy.Path)
+			continue
+		}
+
+		err = cmd.rotate(status.policy)
+		if err != nil {
+			return err
+		}
+		rotated++
+	}
+
+	if !cmd.dryRun {
+		fmt.Fprintf(cmd.io.Output(), "Rotated %d %s.\n", rotated, pluralize("secret", "secrets", rotated))
+	}
+	return nil
+}
+
+// rotate writes a new version of the secret of the policy.
+func (cmd *RotateRunCommand) rotate(policy rotationPolicy) error {
+	parts, err := policy.Profile.generate(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range parts[1:] {
+		err = api.ValidateSecretPath(policy.Path + part.suffix)
+		if err != nil {
+			return err
+		}
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	for _, part := range parts {
+		version, err := client.Secrets().Write(policy.Path+part.suffix, part.data)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Rotated %s:%d.\n", policy.Path+part.suffix, version.Version)
+	}
+	return nil
+}
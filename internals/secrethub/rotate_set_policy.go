@@ -0,0 +1,75 @@
+package secrethub
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// RotateSetPolicyCommand sets the rotation policy of a secret.
+type RotateSetPolicyCommand struct {
+	path        api.SecretPath
+	every       string
+	generator   string
+	profileFile string
+	io          ui.IO
+	policies    *RotationPolicyStore
+}
+
+// NewRotateSetPolicyCommand creates a new RotateSetPolicyCommand.
+func NewRotateSetPolicyCommand(io ui.IO, policies *RotationPolicyStore) *RotateSetPolicyCommand {
+	return &RotateSetPolicyCommand{
+		io:       io,
+		policies: policies,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RotateSetPolicyCommand) Register(r cli.Registerer) {
+	clause := r.Command("set-policy", "Set how often a secret is rotated and how its new value is generated.")
+	clause.HelpLong("The policy is stored in the " + rotationPolicyLabel + " label of the secret and replaces an earlier policy. " +
+		"The secret does not have to exist yet: it is created by the next `secrethub rotate run`.")
+	clause.Flags().StringVar(&cmd.every, "every", "", "How often the secret is rotated, in days (90d), weeks (12w) or as a duration (36h).")
+	clause.Flags().StringVar(&cmd.generator, "generator", defaultRotationGenerator, "How the new value of the secret is generated, as profile:<name> with one of the profiles of `secrethub generate --profile`.")
+	clause.Flags().StringVar(&cmd.profileFile, "profile-file", "", "A YAML file with additional profiles, in the format of `secrethub generate --profile-file`. The profile is stored in the "+rotationProfileLabel+" label of the secret, so the file is not needed to rotate the secret.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.path, Name: "path", Required: true, Placeholder: secretPathPlaceHolder, Description: "The path to the secret to rotate."},
+	})
+}
+
+// Run validates and stores the rotation policy.
+func (cmd *RotateSetPolicyCommand) Run() error {
+	_, err := parseDayDuration(cmd.every)
+	if err != nil {
+		return err
+	}
+
+	profileName := strings.TrimPrefix(cmd.generator, "profile:")
+	if profileName == cmd.generator || profileName == "" {
+		return ErrInvalidRotationGenerator(cmd.generator)
+	}
+	profile, err := loadProfile(profileName, cmd.profileFile)
+	if err != nil {
+		return err
+	}
+
+	err = cmd.policies.set(rotationPolicy{
+		Path:      cmd.path.Value(),
+		Every:     cmd.every,
+		Generator: cmd.generator,
+		Profile:   profile,
+		custom:    cmd.profileFile != "",
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "%s will be rotated every %s.\n", cmd.path.Value(), cmd.every)
+	return nil
+}
@@ -0,0 +1,105 @@
+package secrethub
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// RotateStatusCommand lists the secrets with a rotation policy and whether they are overdue.
+type RotateStatusCommand struct {
+	path          api.DirPath
+	overdueOnly   bool
+	useTimestamps bool
+	io            ui.IO
+	newClient     newClientFunc
+	policies      *RotationPolicyStore
+	now           func() time.Time
+}
+
+// NewRotateStatusCommand creates a new RotateStatusCommand.
+func NewRotateStatusCommand(io ui.IO, newClient newClientFunc, policies *RotationPolicyStore) *RotateStatusCommand {
+	return &RotateStatusCommand{
+		io:        io,
+		newClient: newClient,
+		policies:  policies,
+		now:       time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RotateStatusCommand) Register(r cli.Registerer) {
+	clause := r.Command("status", "List the secrets with a rotation policy and whether they are overdue.")
+	clause.Flags().BoolVar(&cmd.overdueOnly, "overdue", false, "Only list the secrets that are overdue.")
+	registerTimestampFlag(clause, &cmd.useTimestamps)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.path, Name: "dir-path", Required: true, Placeholder: dirPathPlaceHolder, Description: "The path of the directory to list the rotated secrets of, including its subdirectories."},
+	})
+}
+
+// Run lists the secrets with a rotation policy.
+func (cmd *RotateStatusCommand) Run() error {
+	policies, err := cmd.policies.list(cmd.path)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := getRotationStatuses(cmd.newClient, policies)
+	if err != nil {
+		return err
+	}
+
+	timeFormatter := NewTimeFormatter(cmd.useTimestamps)
+	now := cmd.now()
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w,
+		"PATH\t"+
+			"EVERY\t"+
+			"GENERATOR\t"+
+			"LAST ROTATED\t"+
+			"DUE\t"+
+			"STATUS")
+
+	for _, status := range statuses {
+		overdue := status.overdue(now)
+		if cmd.overdueOnly && !overdue {
+			continue
+		}
+
+		lastRotated := "never"
+		due := "now"
+		if !status.lastRotated.IsZero() {
+			lastRotated = timeFormatter.Format(status.lastRotated.Local())
+			due = status.due.Local().Format("2006-01-02")
+			if cmd.useTimestamps {
+				due = status.due.Local().Format(time.RFC3339)
+			}
+		}
+
+		state := "ok"
+		if overdue {
+			state = "overdue"
+		}
+
+		row := []string{
+			status.policy.Path,
+			status.policy.Every,
+			status.policy.Generator,
+			lastRotated,
+			due,
+			state,
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,148 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestRotationPolicyStore(t *testing.T) {
+	secrets := newFakeSecretStore()
+	secrets.data["namespace/repo/a.meta"] = []byte(`{"owner":"team-a"}`)
+
+	store := NewRotationPolicyStore(secrets.newClient)
+	assert.OK(t, store.set(rotationPolicy{Path: "namespace/repo/b", Every: "90d", Generator: defaultRotationGenerator}))
+	assert.OK(t, store.set(rotationPolicy{Path: "namespace/repo/a", Every: "30d", Generator: "profile:uuid"}))
+	assert.OK(t, store.set(rotationPolicy{Path: "namespace/repo/b", Every: "7d", Generator: "profile:custom", Profile: builtinProfiles["hex32"], custom: true}))
+
+	// Other labels are kept.
+	assert.Equal(t, string(secrets.data["namespace/repo/a.meta"]), `{"owner":"team-a","rotation-policy":"30d profile:uuid"}`)
+
+	policies, err := store.list("namespace/repo")
+	assert.OK(t, err)
+	assert.Equal(t, policies, []rotationPolicy{
+		{Path: "namespace/repo/a", Every: "30d", Generator: "profile:uuid", Profile: builtinProfiles["uuid"]},
+		{Path: "namespace/repo/b", Every: "7d", Generator: "profile:custom", Profile: builtinProfiles["hex32"], custom: true},
+	})
+
+	assert.OK(t, store.remove("namespace/repo/a"))
+	assert.Equal(t, store.remove("namespace/repo/a"), ErrRotationPolicyNotFound(api.SecretPath("namespace/repo/a")))
+	assert.Equal(t, string(secrets.data["namespace/repo/a.meta"]), `{"owner":"team-a"}`)
+
+	policies, err = store.list("namespace/repo")
+	assert.OK(t, err)
+	assert.Equal(t, len(policies), 1)
+	assert.Equal(t, policies[0].Path, "namespace/repo/b")
+}
+
+func TestParseRotationPolicy(t *testing.T) {
+	cases := map[string]struct {
+		labels      map[string]string
+		expected    rotationPolicy
+		expectedErr error
+	}{
+		"interval": {
+			labels:   map[string]string{rotationPolicyLabel: "90d"},
+			expected: rotationPolicy{Path: "namespace/repo/secret", Every: "90d", Generator: defaultRotationGenerator, Profile: builtinProfiles["alnum32"]},
+		},
+		"interval and profile": {
+			labels:   map[string]string{rotationPolicyLabel: "12w profile:ec-p256"},
+			expected: rotationPolicy{Path: "namespace/repo/secret", Every: "12w", Generator: "profile:ec-p256", Profile: builtinProfiles["ec-p256"]},
+		},
+		"stored profile": {
+			labels:   map[string]string{rotationPolicyLabel: "30d profile:custom", rotationProfileLabel: `{"type":"chars","length":16,"charset":"numeric"}`},
+			expected: rotationPolicy{Path: "namespace/repo/secret", Every: "30d", Generator: "profile:custom", Profile: generateProfile{Type: profileTypeChars, Length: 16, Charset: "numeric"}, custom: true},
+		},
+		"invalid interval": {
+			labels:      map[string]string{rotationPolicyLabel: "often"},
+			expectedErr: ErrInvalidRotationPolicy("often", api.SecretPath("namespace/repo/secret")),
+		},
+		"invalid generator": {
+			labels:      map[string]string{rotationPolicyLabel: "90d alnum32"},
+			expectedErr: ErrInvalidRotationPolicy("90d alnum32", api.SecretPath("namespace/repo/secret")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseRotationPolicy("namespace/repo/secret", tc.labels)
+
+			assert.Equal(t, err, tc.expectedErr)
+			if tc.expectedErr == nil {
+				assert.Equal(t, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRotateRunCommand_Run(t *testing.T) {
+	now := time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		dryRun          bool
+		expectedWritten []string
+		expectedOut     string
+	}{
+		"rotate": {
+			expectedWritten: []string{"namespace/repo/key", "namespace/repo/key.pub", "namespace/repo/new", "namespace/repo/old"},
+			expectedOut: "Rotated namespace/repo/key:2.\n" +
+				"Rotated namespace/repo/key.pub:2.\n" +
+				"Rotated namespace/repo/new:2.\n" +
+				"Rotated namespace/repo/old:2.\n" +
+				"Rotated 3 secrets.\n",
+		},
+		"dry run": {
+			dryRun: true,
+			expectedOut: "Would rotate namespace/repo/key.\n" +
+				"Would rotate namespace/repo/new.\n" +
+				"Would rotate namespace/repo/old.\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			secrets := newFakeSecretStore()
+			// The policies are read from the labels, like they are set with `secrethub meta set`.
+			secrets.data["namespace/repo/old.meta"] = []byte(`{"rotation-policy":"30d"}`)
+			secrets.data["namespace/repo/recent.meta"] = []byte(`{"rotation-policy":"30d profile:alnum32"}`)
+			secrets.data["namespace/repo/new.meta"] = []byte(`{"rotation-policy":"30d profile:uuid"}`)
+			secrets.data["namespace/repo/key.meta"] = []byte(`{"rotation-policy":"90d profile:ec-p256"}`)
+			secrets.data["namespace/repo/other.meta"] = []byte(`{"owner":"team-a"}`)
+
+			for path, created := range map[string]time.Time{
+				"namespace/repo/old":    now.Add(-31 * 24 * time.Hour),
+				"namespace/repo/recent": now.Add(-29 * 24 * time.Hour),
+				"namespace/repo/key":    now.Add(-90 * 24 * time.Hour),
+				"namespace/repo/other":  now.Add(-365 * 24 * time.Hour),
+			} {
+				secrets.data[path] = []byte("secret")
+				secrets.createdAt[path] = created
+			}
+
+			io := fakeui.NewIO(t)
+			cmd := RotateRunCommand{
+				path:      "namespace/repo",
+				dryRun:    tc.dryRun,
+				io:        io,
+				newClient: secrets.newClient,
+				policies:  NewRotationPolicyStore(secrets.newClient),
+				now: func() time.Time {
+					return now
+				},
+			}
+
+			// Act
+			err := cmd.Run()
+
+			// Assert
+			assert.OK(t, err)
+			assert.Equal(t, secrets.written, tc.expectedWritten)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+		})
+	}
+}
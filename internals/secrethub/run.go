@@ -1,10 +1,11 @@
 package secrethub
 
 import (
-	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"os/signal"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
@@ -32,17 +33,30 @@ var (
 	ErrParsingTemplate        = errRun.Code("template_parsing_failed").ErrorPref("error while processing template file '%s': %s")
 	ErrInvalidTemplateVar     = errRun.Code("invalid_template_var").ErrorPref("template variable '%s' is invalid: template variables may only contain uppercase letters, digits, and the '_' (underscore) and are not allowed to start with a number")
 	ErrSecretsNotAllowedInKey = errRun.Code("secret_in_key").Error("secrets are not allowed in run template keys")
+	ErrUnknownEnvSource       = errRun.Code("unknown_env_source").ErrorPref("unknown source of environment variables %s: the sources are %s")
+	ErrEnvCollisions          = errRun.Code("env_collisions").ErrorPref("environment variables are supplied by more than one source: %s. Remove them from all but one source or leave out --strict-collisions to use the source with the highest priority")
+	ErrInvalidSecretsDirCase  = errRun.Code("invalid_secrets_dir_case").ErrorPref("invalid value for --secrets-dir-case %s: it must be upper, lower or preserve")
+	ErrDuplicateEnvSource     = errRun.Code("duplicate_env_source").ErrorPref("the source of environment variables %s is listed more than once")
+	ErrEnvFileTooLarge        = errRun.Code("env_file_too_large").ErrorPref("the environment file %s is larger than the maximum of %s. Use --env-dir-max-file-size to change the maximum")
 )
 
 const (
 	defaultEnvFile = "secrethub.env"
-	maskString     = "<redacted by SecretHub>"
+	// envFileStdin is the value of --env-file for reading the env-file from stdin.
+	envFileStdin = "-"
+	maskString   = "<redacted by SecretHub>"
 	// templateVarEnvVarPrefix is used to prefix environment variables
 	// that should be used as template variables.
 	templateVarEnvVarPrefix = "SECRETHUB_VAR_"
 	// prefix of the values of environment variables that will be
 	// substituted with secrets
 	secretReferencePrefix = "secrethub://"
+	// defaultEnvConcurrency is the default number of environment
+	// variables that are resolved concurrently.
+	defaultEnvConcurrency = 10
+	// defaultEnvDirMaxFileSize is the default maximum size of a file in
+	// an environment directory.
+	defaultEnvDirMaxFileSize = 1000 * 1000
 )
 
 // RunCommand runs a program and passes environment variables to it that are
@@ -57,15 +71,20 @@ type RunCommand struct {
 	maskerOptions        masker.Options
 	newClient            newClientFunc
 	ignoreMissingSecrets bool
+	cache                *SecretCache
+	ssh                  string
+	sshArgs              []string
+	pty                  bool
 }
 
 // NewRunCommand creates a new RunCommand.
-func NewRunCommand(io ui.IO, newClient newClientFunc) *RunCommand {
+func NewRunCommand(io ui.IO, newClient newClientFunc, cache *SecretCache) *RunCommand {
 	return &RunCommand{
 		io:          io,
 		osEnv:       os.Environ(),
 		environment: newEnvironment(io, newClient),
 		newClient:   newClient,
+		cache:       cache,
 	}
 }
 
@@ -73,6 +92,7 @@ func NewRunCommand(io ui.IO, newClient newClientFunc) *RunCommand {
 func (cmd *RunCommand) Register(r cli.Registerer) {
 	const helpShort = "Pass secrets as environment variables to a process."
 	const helpLong = "To protect against secrets leaking via stdout and stderr, those output streams are monitored for secrets. Detected secrets are automatically masked by replacing them with \"" + maskString + "\". " +
+		"Their base64, hex, URL and JSON encoded forms are masked too, unless the no-masking-encodings flag is set. " +
 		"The output is buffered to scan for secrets and can be adjusted using the masking-buffer-period flag. " +
 		"You should regard the masking as a best effort attempt and should always prevent secrets ending up on stdout and stderr in the first place."
 
@@ -82,8 +102,13 @@ func (cmd *RunCommand) Register(r cli.Registerer) {
 	clause.Flags().BoolVar(&cmd.noMasking, "no-masking", false, "Disable masking of secrets on stdout and stderr")
 	clause.Flags().BoolVar(&cmd.maskerOptions.DisableBuffer, "no-output-buffering", false, "Disable output buffering. This increases output responsiveness, but decreases the probability that secrets get masked.")
 	clause.Flags().DurationVar(&cmd.maskerOptions.BufferDelay, "masking-buffer-period", time.Millisecond*50, "The time period for which output is buffered. A higher value increases the probability that secrets get masked but decreases output responsiveness.")
+	clause.Flags().BoolVar(&cmd.maskerOptions.DisableEncodings, "no-masking-encodings", false, "Only mask secrets as they are and not their base64, hex, URL and JSON encoded forms. This reduces the overhead of masking when many secrets are passed.")
 	clause.Flags().BoolVar(&cmd.ignoreMissingSecrets, "ignore-missing-secrets", false, "Do not return an error when a secret does not exist and use an empty value instead.")
 	cmd.environment.register(clause)
+	cmd.cache.register(clause)
+	clause.Flags().StringVar(&cmd.ssh, "ssh", "", "Run the command on a remote host over SSH, e.g. --ssh user@host. The secrets are read locally and passed to the remote command over the SSH connection. Output is masked locally.")
+	clause.Flags().StringArrayVar(&cmd.sshArgs, "ssh-arg", nil, "An extra argument to pass to ssh, e.g. --ssh-arg=-p2222. Can be repeated. It is ignored without the --ssh flag.")
+	clause.Flags().BoolVar(&cmd.pty, "pty", false, "Run the command in a pseudo-terminal, so that programs that detect a terminal, like interactive tools, still have their output masked. Stdout and stderr of the command are combined and written to stdout. Supported on Linux and macOS.")
 	clause.BindAction(cmd.Run)
 	clause.BindArgumentsArr(cli.Argument{Value: &cmd.command, Name: "command", Required: true, Description: "The command to execute"})
 }
@@ -91,16 +116,15 @@ func (cmd *RunCommand) Register(r cli.Registerer) {
 // Run reads files from the .secretsenv/<env-name> directory, sets them as environment variables and runs the given command.
 // Note that the environment variables are only passed to the child process and not exported globally, which is nice.
 func (cmd *RunCommand) Run() error {
+	if cmd.pty && cmd.ssh != "" {
+		return ErrFlagsConflict("--pty and --ssh")
+	}
+
 	environment, secrets, err := cmd.sourceEnvironment()
 	if err != nil {
 		return err
 	}
 
-	// This makes sure commands encapsulated in quotes also work.
-	if len(cmd.command) == 1 {
-		cmd.command = strings.Split(cmd.command[0], " ")
-	}
-
 	sequences := make([][]byte, 0, len(secrets))
 	for _, val := range secrets {
 		if val != "" {
@@ -109,22 +133,50 @@ func (cmd *RunCommand) Run() error {
 	}
 	m := masker.New(sequences, &cmd.maskerOptions)
 
-	command := exec.Command(cmd.command[0], cmd.command[1:]...)
-	command.Env = environment
-	command.Stdin = os.Stdin
-	if cmd.noMasking {
-		command.Stdout = cmd.io.Stdout()
-		command.Stderr = os.Stderr
+	var command *exec.Cmd
+	if cmd.ssh != "" {
+		command, err = cmd.newSSHCommand(environment)
+		if err != nil {
+			return err
+		}
 	} else {
-		command.Stdout = m.AddStream(cmd.io.Stdout())
-		command.Stderr = m.AddStream(os.Stderr)
+		// This makes sure commands encapsulated in quotes also work.
+		if len(cmd.command) == 1 {
+			cmd.command = strings.Split(cmd.command[0], " ")
+		}
 
-		go m.Start()
+		command = exec.Command(cmd.command[0], cmd.command[1:]...)
+		command.Env = environment
+		command.Stdin = os.Stdin
 	}
 
-	err = command.Start()
-	if err != nil {
-		return ErrStartFailed(err)
+	var session *ptySession
+	if cmd.pty {
+		var output io.Writer = cmd.io.Stdout()
+		if !cmd.noMasking {
+			output = m.AddStream(output)
+			go m.Start()
+		}
+
+		session, err = startPTY(command, cmd.io.Stdin(), output)
+		if err != nil {
+			return ErrStartFailed(err)
+		}
+	} else {
+		if cmd.noMasking {
+			command.Stdout = cmd.io.Stdout()
+			command.Stderr = os.Stderr
+		} else {
+			command.Stdout = m.AddStream(cmd.io.Stdout())
+			command.Stderr = m.AddStream(os.Stderr)
+
+			go m.Start()
+		}
+
+		err = command.Start()
+		if err != nil {
+			return ErrStartFailed(err)
+		}
 	}
 
 	done := make(chan bool, 1)
@@ -134,21 +186,28 @@ func (cmd *RunCommand) Run() error {
 	signal.Notify(signals)
 
 	go func() {
-		select {
-		case s := <-signals:
-			err := command.Process.Signal(s)
-			if err != nil && !strings.Contains(err.Error(), "process already finished") {
-				fmt.Fprintln(os.Stderr, ErrSignalFailed(err))
+		for {
+			select {
+			case s := <-signals:
+				err := command.Process.Signal(s)
+				if err != nil && !strings.Contains(err.Error(), "process already finished") {
+					logger.Errorf("%s", ErrSignalFailed(err))
+				}
+			case <-done:
+				signal.Stop(signals)
+				return
 			}
-		case <-done:
-			signal.Stop(signals)
-			return
 		}
 	}()
 
 	commandErr := command.Wait()
 	done <- true
 
+	if session != nil {
+		session.wait()
+		session.close()
+	}
+
 	if !cmd.noMasking {
 		err := m.Stop()
 		if err != nil {
@@ -163,8 +222,7 @@ func (cmd *RunCommand) Run() error {
 			waitStatus, ok := exitErr.Sys().(syscall.WaitStatus)
 			if ok {
 				// Return the status code returned by the process
-				os.Exit(waitStatus.ExitStatus())
-				return nil
+				return ExitError{Code: waitStatus.ExitStatus()}
 			}
 
 		}
@@ -178,24 +236,21 @@ func (cmd *RunCommand) Run() error {
 // and the secret values that need to be masked.
 func (cmd *RunCommand) sourceEnvironment() ([]string, []string, error) {
 	_, passthroughEnv := parseKeyValueStringsToMap(cmd.osEnv)
-	newEnv := map[string]string{}
 
 	envValues, err := cmd.environment.env()
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var sr tpl.SecretReader = newSecretReader(cmd.newClient)
+	var sr tpl.SecretReader = newSecretCacheReader(newSecretReader(cmd.newClient), cmd.cache)
 	if cmd.ignoreMissingSecrets {
 		sr = newIgnoreMissingSecretReader(sr)
 	}
-	secretReader := newBufferedSecretReader(sr)
+	secretReader := newBufferedSecretReader(newCachingSecretReader(sr))
 
-	for name, value := range envValues {
-		newEnv[name], err = value.resolve(secretReader)
-		if err != nil {
-			return nil, nil, err
-		}
+	newEnv, err := cmd.environment.resolve(envValues, secretReader)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Finally add the unparsed variables
@@ -204,7 +259,7 @@ func (cmd *RunCommand) sourceEnvironment() ([]string, []string, error) {
 	return processedOsEnv, secretReader.Values(), nil
 }
 
-// mapToKeyValueStrings converts a map to a slice of key=value pairs.
+// mapToKeyValueStrings converts a map to a slice of key=value pairs, sorted by key.
 func mapToKeyValueStrings(pairs map[string]string) []string {
 	result := make([]string, len(pairs))
 	i := 0
@@ -212,6 +267,7 @@ func mapToKeyValueStrings(pairs map[string]string) []string {
 		result[i] = key + "=" + value
 		i++
 	}
+	sort.Strings(result)
 
 	return result
 }
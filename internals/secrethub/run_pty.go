@@ -0,0 +1,86 @@
+package secrethub
+
+import (
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/pty"
+
+	"golang.org/x/term"
+)
+
+// ptySession is a process that runs in a pseudo-terminal. Its input is read from stdin
+// and its output, which combines stdout and stderr, is written to the given writer.
+type ptySession struct {
+	pty        *os.File
+	stdin      *os.File
+	oldState   *term.State
+	stopResize func()
+	outputDone chan struct{}
+}
+
+// startPTY starts the command in a new pseudo-terminal. When stdin is a terminal, it is put in raw mode,
+// so that all input, including control characters, is passed on to the process and its terminal handles
+// them instead. Window size changes of stdin are propagated to the pseudo-terminal.
+func startPTY(command *exec.Cmd, stdin *os.File, output io.Writer) (*ptySession, error) {
+	isTerminal := term.IsTerminal(int(stdin.Fd()))
+
+	var oldState *term.State
+	if isTerminal {
+		var err error
+		oldState, err = term.MakeRaw(int(stdin.Fd()))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ptmx, err := pty.Start(command)
+	if err != nil {
+		if oldState != nil {
+			_ = term.Restore(int(stdin.Fd()), oldState)
+		}
+		return nil, err
+	}
+
+	session := &ptySession{
+		pty:        ptmx,
+		stdin:      stdin,
+		oldState:   oldState,
+		stopResize: func() {},
+		outputDone: make(chan struct{}),
+	}
+
+	if isTerminal {
+		// Window sizes are a best effort, the process keeps the default size when it fails.
+		_ = pty.CopySize(ptmx, stdin)
+		session.stopResize = pty.NotifyResize(ptmx, stdin)
+	}
+
+	go func() {
+		_, _ = io.Copy(ptmx, stdin)
+	}()
+	go func() {
+		// Reading from the pseudo-terminal fails once the process has exited
+		// and closed its terminal, which marks the end of the output.
+		_, _ = io.Copy(output, ptmx)
+		close(session.outputDone)
+	}()
+
+	return session, nil
+}
+
+// wait blocks until all output of the process has been written.
+// It should only be called after the process has exited.
+func (s *ptySession) wait() {
+	<-s.outputDone
+}
+
+// close restores the terminal to its state before the session started and closes the pseudo-terminal.
+func (s *ptySession) close() {
+	s.stopResize()
+	if s.oldState != nil {
+		_ = term.Restore(int(s.stdin.Fd()), s.oldState)
+	}
+	s.pty.Close()
+}
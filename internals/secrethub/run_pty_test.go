@@ -0,0 +1,43 @@
+//go:build linux || darwin
+
+package secrethub
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/masker"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestStartPTY(t *testing.T) {
+	// Setup
+	stdin, stdinWriter, err := os.Pipe()
+	assert.OK(t, err)
+	defer stdin.Close()
+	defer stdinWriter.Close()
+
+	var out bytes.Buffer
+	m := masker.New([][]byte{[]byte("secret value")}, &masker.Options{DisableBuffer: true})
+	output := m.AddStream(&out)
+	go m.Start()
+
+	// The command only prints the secret when it runs in a terminal.
+	command := exec.Command("sh", "-c", "test -t 1 && echo 'secret value'")
+
+	// Act
+	session, err := startPTY(command, stdin, output)
+	assert.OK(t, err)
+	err = command.Wait()
+	assert.OK(t, err)
+	session.wait()
+	session.close()
+	err = m.Stop()
+	assert.OK(t, err)
+
+	// Assert
+	assert.Equal(t, out.String(), maskString+"\r\n")
+}
@@ -0,0 +1,147 @@
+package secrethub
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Errors
+var (
+	ErrInvalidSSHDestination = errMain.Code("invalid_ssh_destination").ErrorPref("invalid SSH destination %s")
+	ErrInvalidRemoteEnvVar   = errMain.Code("invalid_remote_env_var").ErrorPref("cannot pass environment variable %s to a remote host: the name is not valid for a POSIX shell")
+)
+
+var shellVariableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// sshEnvScript is the script that runs on the remote host. It reads the
+// statements that export the environment from stdin up to the end marker,
+// evaluates them and then executes the command. This way, the secrets are never
+// written to disk or passed as arguments on the remote host. The remainder of
+// stdin is left for the command.
+const sshEnvScript = `env=''
+nl='
+'
+while IFS= read -r line; do
+  [ "$line" = %s ] && break
+  env="$env$line$nl"
+done
+eval "$env"
+unset env line nl
+%s`
+
+// newSSHCommand returns a command that runs the command on the remote host over SSH,
+// with the variables of the given environment that differ from the local environment.
+// The local ssh process itself only gets the local environment.
+func (cmd *RunCommand) newSSHCommand(environment []string) (*exec.Cmd, error) {
+	if strings.HasPrefix(cmd.ssh, "-") {
+		return nil, ErrInvalidSSHDestination(cmd.ssh)
+	}
+
+	remoteEnv, err := cmd.remoteEnv(environment)
+	if err != nil {
+		return nil, err
+	}
+
+	marker, err := newSSHEnvMarker()
+	if err != nil {
+		return nil, err
+	}
+
+	args := append([]string{}, cmd.sshArgs...)
+	args = append(args, cmd.ssh, "sh -c "+shellQuote(fmt.Sprintf(sshEnvScript, shellQuote(marker), remoteCommand(cmd.command))))
+
+	command := exec.Command("ssh", args...)
+	command.Env = cmd.osEnv
+
+	stdin, err := command.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		defer stdin.Close()
+		_, err := io.WriteString(stdin, sshEnvInput(remoteEnv, marker))
+		if err != nil {
+			return
+		}
+		_, _ = io.Copy(stdin, os.Stdin)
+	}()
+
+	return command, nil
+}
+
+// remoteEnv returns the variables of the given environment that are not set to the same value in the
+// local environment, as those are the variables sourced by the run command.
+func (cmd *RunCommand) remoteEnv(environment []string) (map[string]string, error) {
+	local := make(map[string]struct{}, len(cmd.osEnv))
+	for _, kv := range cmd.osEnv {
+		local[kv] = struct{}{}
+	}
+
+	remoteEnv := make(map[string]string)
+	for _, kv := range environment {
+		if _, found := local[kv]; found {
+			continue
+		}
+
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if !shellVariableNameRegexp.MatchString(parts[0]) {
+			return nil, ErrInvalidRemoteEnvVar(parts[0])
+		}
+		remoteEnv[parts[0]] = parts[1]
+	}
+	return remoteEnv, nil
+}
+
+// remoteCommand returns the command line to execute on the remote host. A command given
+// as a single argument is passed as is, so it can use shell syntax. Otherwise, every argument
+// is quoted and the command replaces the remote shell.
+func remoteCommand(command []string) string {
+	if len(command) == 1 {
+		return command[0]
+	}
+
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = shellQuote(arg)
+	}
+	return "exec " + strings.Join(quoted, " ")
+}
+
+// newSSHEnvMarker returns a random line that marks the end of the environment on stdin.
+func newSSHEnvMarker() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return "SECRETHUB_ENV_END_" + hex.EncodeToString(b), nil
+}
+
+// sshEnvInput returns the statements exporting the environment, followed by the end marker.
+func sshEnvInput(env map[string]string, marker string) string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(exportBash(name, env[name]))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(marker)
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,46 @@
+package secrethub
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestSSHEnvScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	env := map[string]string{
+		"FOO":       "foo",
+		"MULTILINE": "it's\n  a \"$secret\"\\\n",
+	}
+	marker := "SECRETHUB_ENV_END_test"
+
+	command := remoteCommand([]string{"sh", "-c", `printf '%s|%s|' "$FOO" "$MULTILINE"; cat`})
+	script := fmt.Sprintf(sshEnvScript, shellQuote(marker), command)
+
+	sh := exec.Command("sh", "-c", script)
+	sh.Stdin = strings.NewReader(sshEnvInput(env, marker) + "remaining input")
+	out, err := sh.CombinedOutput()
+	assert.OK(t, err)
+
+	assert.Equal(t, string(out), "foo|it's\n  a \"$secret\"\\\n|remaining input")
+}
+
+func TestRunCommand_RemoteEnv(t *testing.T) {
+	cmd := RunCommand{
+		osEnv: []string{"HOME=/home/user", "FOO=local"},
+	}
+
+	actual, err := cmd.remoteEnv([]string{"HOME=/home/user", "FOO=remote", "BAR=bar"})
+	assert.OK(t, err)
+	assert.Equal(t, actual, map[string]string{"FOO": "remote", "BAR": "bar"})
+
+	_, err = cmd.remoteEnv([]string{"FOO.BAR=bar"})
+	assert.Equal(t, err, ErrInvalidRemoteEnvVar("FOO.BAR"))
+}
@@ -1,6 +1,7 @@
 package secrethub
 
 import (
+	"bytes"
 	"errors"
 	"log"
 	"os"
@@ -606,6 +607,14 @@ func TestRunCommand_Run(t *testing.T) {
 			},
 			err: api.ErrSecretNotFound,
 		},
+		"pty over ssh": {
+			command: RunCommand{
+				command: cli.StringListValue{"echo", "test"},
+				pty:     true,
+				ssh:     "user@host",
+			},
+			err: ErrFlagsConflict("--pty and --ssh"),
+		},
 		"os env secret not found ignored": {
 			command: RunCommand{
 				ignoreMissingSecrets: true,
@@ -720,6 +729,29 @@ func TestRunCommand_environment(t *testing.T) {
 			},
 			expectedEnv: []string{"TEST=test"},
 		},
+		"env file from stdin": {
+			command: RunCommand{
+				environment: &environment{
+					io: &fakeui.FakeIO{
+						In: &fakeui.FakeReader{Buffer: bytes.NewBufferString("TEST=test"), Piped: true},
+					},
+					envFile:         "-",
+					templateVersion: "2",
+				},
+			},
+			expectedEnv: []string{"TEST=test"},
+		},
+		"env file from stdin without piped input": {
+			command: RunCommand{
+				environment: &environment{
+					io: &fakeui.FakeIO{
+						In: &fakeui.FakeReader{Buffer: &bytes.Buffer{}},
+					},
+					envFile: "-",
+				},
+			},
+			err: ErrNoDataOnStdin,
+		},
 		"env file secret does not exist": {
 			command: RunCommand{
 				command: cli.StringListValue{"echo", "test"},
@@ -795,6 +827,7 @@ func TestRunCommand_environment(t *testing.T) {
 						}, nil
 					},
 					secretsDir:                   "namespace/repo",
+					secretsDirNaming:             secretsDirNaming{separator: "_", nameCase: secretsDirCaseUpper},
 					dontPromptMissingTemplateVar: true,
 					templateVersion:              "2",
 					osEnv:                        []string{"FOO=bbb"},
@@ -847,6 +880,7 @@ func TestRunCommand_environment(t *testing.T) {
 						}, nil
 					},
 					secretsDir:                   "namespace/repo",
+					secretsDirNaming:             secretsDirNaming{separator: "_", nameCase: secretsDirCaseUpper},
 					dontPromptMissingTemplateVar: true,
 					templateVersion:              "2",
 					osEnv:                        []string{"FOO=bbb"},
@@ -897,6 +931,7 @@ func TestRunCommand_environment(t *testing.T) {
 						}, nil
 					},
 					secretsDir:                   "namespace/repo",
+					secretsDirNaming:             secretsDirNaming{separator: "_", nameCase: secretsDirCaseUpper},
 					dontPromptMissingTemplateVar: true,
 					templateVersion:              "2",
 					osEnv:                        []string{"FOO=secrethub://test/test/test"},
@@ -1378,3 +1413,13 @@ func Test_parseKeyValueStringsToMap(t *testing.T) {
 		"=::=::\\",
 	})
 }
+
+func Test_mapToKeyValueStrings(t *testing.T) {
+	actual := mapToKeyValueStrings(map[string]string{
+		"C": "3",
+		"A": "1",
+		"B": "2",
+	})
+
+	assert.Equal(t, actual, []string{"A=1", "B=2", "C=3"})
+}
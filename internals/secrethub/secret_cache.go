@@ -0,0 +1,340 @@
+package secrethub
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+const (
+	secretCacheDirName  = "cache"
+	secretCacheFileName = "secrets.json"
+)
+
+// Errors
+var (
+	ErrCannotClearSecretCache = errMain.Code("cannot_clear_secret_cache").ErrorPref("cannot clear the secret cache: %s")
+	ErrInvalidSecretCache     = errMain.Code("invalid_secret_cache").ErrorPref("could not parse the secret cache file %s: %s")
+)
+
+// keyWrapper encrypts and decrypts the key of the secret cache.
+type keyWrapper interface {
+	credentials.Encrypter
+	credentials.Decrypter
+}
+
+// credentialKeyWrapper wraps the key of the secret cache with the account key of a credential.
+type credentialKeyWrapper struct {
+	credentials.Encrypter
+	credentials.Decrypter
+}
+
+// newCredentialKeyWrapper returns a keyWrapper for the account key of the credential in the given store.
+func newCredentialKeyWrapper(store CredentialConfig) (keyWrapper, error) {
+	key, err := store.Import()
+	if err != nil {
+		return nil, err
+	}
+	_, decrypter, err := key.Provide(nil)
+	if err != nil {
+		return nil, err
+	}
+	return credentialKeyWrapper{
+		Encrypter: key.Encrypter(),
+		Decrypter: decrypter,
+	}, nil
+}
+
+// SecretCache is a local cache of secret values. The values are encrypted with a
+// key that is stored next to them, encrypted with the account key of the credential.
+// The cache is disabled unless a TTL is configured with the --cache-ttl flag.
+type SecretCache struct {
+	ttl       time.Duration
+	dir       func() string
+	importKey func() (keyWrapper, error)
+	logger    cli.Logger
+	now       func() time.Time
+
+	mutex  sync.Mutex
+	loaded bool
+	file   *secretCacheFile
+	key    *crypto.SymmetricKey
+}
+
+// secretCacheFile is the format in which the secret cache is stored on disk.
+type secretCacheFile struct {
+	// Key is the symmetric key that encrypts the secrets, wrapped by the account key.
+	Key *api.EncryptedData `json:"key"`
+	// Secrets maps the HMAC of every cached secret path to its entry.
+	Secrets map[string]secretCacheEntry `json:"secrets"`
+}
+
+// secretCacheEntry is a single cached secret value.
+type secretCacheEntry struct {
+	FetchedAt time.Time            `json:"fetched_at"`
+	Value     crypto.CiphertextAES `json:"value"`
+}
+
+// NewSecretCache creates a new SecretCache that stores its contents in the configuration
+// directory of the given credential config.
+func NewSecretCache(store CredentialConfig) *SecretCache {
+	return &SecretCache{
+		dir: func() string {
+			return filepath.Join(store.ConfigDir().Path(), secretCacheDirName)
+		},
+		importKey: func() (keyWrapper, error) {
+			return newCredentialKeyWrapper(store)
+		},
+		logger: cli.NewLogger(),
+		now:    time.Now,
+	}
+}
+
+// register registers the flag for enabling the cache on the given command.
+func (c *SecretCache) register(clause *cli.CommandClause) {
+	clause.Flags().DurationVar(&c.ttl, "cache-ttl", 0, "Serve secrets from an encrypted local cache when they were fetched less than this duration ago, e.g. --cache-ttl=5m. When the API cannot be reached, expired cached secrets are used instead. The cache requires a key credential and is disabled when set to 0.")
+}
+
+// enabled returns whether secrets should be read from and written to the cache.
+func (c *SecretCache) enabled() bool {
+	return c != nil && c.ttl > 0
+}
+
+// path returns the location of the cache file.
+func (c *SecretCache) path() string {
+	return filepath.Join(c.dir(), secretCacheFileName)
+}
+
+// read returns the cached value of the secret at the given path if it was fetched
+// less than the TTL ago. Otherwise, the secret is fetched with the given function and
+// the result is cached. When fetching fails because the API cannot be reached, an
+// expired cached value is returned instead. When the cache is disabled, the secret
+// is always fetched.
+func (c *SecretCache) read(path string, fetch func() ([]byte, error)) ([]byte, error) {
+	if !c.enabled() {
+		return fetch()
+	}
+
+	c.mutex.Lock()
+	err := c.load()
+	if err != nil {
+		c.mutex.Unlock()
+		return nil, err
+	}
+	id, err := c.entryID(path)
+	if err != nil {
+		c.mutex.Unlock()
+		return nil, err
+	}
+	cached, cachedErr := c.get(id)
+	fetchedAt := c.file.Secrets[id].FetchedAt
+	c.mutex.Unlock()
+
+	if cachedErr == nil && c.now().Sub(fetchedAt) < c.ttl {
+		return cached, nil
+	}
+
+	data, err := fetch()
+	if err != nil {
+		if cachedErr == nil && isAPIUnavailable(err) {
+			c.logger.Warningf("%s. Using the value of %s cached %s.", err, path, NewTimeFormatter(false).Format(fetchedAt))
+			return cached, nil
+		}
+		if cachedErr == nil {
+			c.mutex.Lock()
+			delete(c.file.Secrets, id)
+			c.save()
+			c.mutex.Unlock()
+		}
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	err = c.set(id, data)
+	if err != nil {
+		return nil, err
+	}
+	c.save()
+	return data, nil
+}
+
+// load reads the cache file and unwraps its key, if that has not been done yet.
+// When there is no usable cache file, a new cache with a new key is started.
+func (c *SecretCache) load() error {
+	if c.loaded {
+		return nil
+	}
+
+	wrapper, err := c.importKey()
+	if err != nil {
+		return err
+	}
+
+	file, err := readSecretCacheFile(c.path())
+	if err == nil && file.Key != nil {
+		rawKey, err := wrapper.Unwrap(file.Key)
+		if err == nil {
+			c.file = file
+			c.key = crypto.NewSymmetricKey(rawKey)
+			c.loaded = true
+			return nil
+		}
+	}
+
+	// The cache does not exist, cannot be read or was encrypted for another credential.
+	key, err := crypto.GenerateSymmetricKey()
+	if err != nil {
+		return err
+	}
+	wrappedKey, err := wrapper.Wrap(key.Export())
+	if err != nil {
+		return err
+	}
+	c.file = &secretCacheFile{
+		Key:     wrappedKey,
+		Secrets: make(map[string]secretCacheEntry),
+	}
+	c.key = key
+	c.loaded = true
+	return nil
+}
+
+// entryID returns the key under which the secret at the given path is stored,
+// so that the cache file does not reveal which secrets are cached.
+func (c *SecretCache) entryID(path string) (string, error) {
+	mac, err := c.key.HMAC([]byte(path))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(mac), nil
+}
+
+// get decrypts the cached value with the given ID.
+func (c *SecretCache) get(id string) ([]byte, error) {
+	entry, found := c.file.Secrets[id]
+	if !found {
+		return nil, os.ErrNotExist
+	}
+	return c.key.Decrypt(entry.Value)
+}
+
+// set encrypts the value and stores it in the cache under the given ID.
+func (c *SecretCache) set(id string, value []byte) error {
+	ciphertext, err := c.key.Encrypt(value)
+	if err != nil {
+		return err
+	}
+	c.file.Secrets[id] = secretCacheEntry{
+		FetchedAt: c.now().UTC(),
+		Value:     ciphertext,
+	}
+	return nil
+}
+
+// save writes the cache to disk. A secret that is not cached is fetched from the API
+// again the next time it is read, so a cache that cannot be written is logged as a
+// warning and the command continues with the secrets it already fetched.
+func (c *SecretCache) save() {
+	err := c.file.write(c.path())
+	if err != nil {
+		c.logger.Warningf("could not write the secret cache: %s", err)
+	}
+}
+
+// clear removes the cache file.
+func (c *SecretCache) clear() error {
+	err := os.Remove(c.path())
+	if err != nil && !os.IsNotExist(err) {
+		return ErrCannotClearSecretCache(err)
+	}
+
+	c.mutex.Lock()
+	c.loaded = false
+	c.file = nil
+	c.key = nil
+	c.mutex.Unlock()
+	return nil
+}
+
+// readSecretCacheFile reads and parses the cache file at the given path.
+func readSecretCacheFile(path string) (*secretCacheFile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &secretCacheFile{}
+	err = json.Unmarshal(raw, file)
+	if err != nil {
+		return nil, ErrInvalidSecretCache(path, err)
+	}
+	if file.Secrets == nil {
+		file.Secrets = make(map[string]secretCacheEntry)
+	}
+	return file, nil
+}
+
+// write atomically writes the cache file to the given path.
+func (f *secretCacheFile) write(path string) error {
+	raw, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(path, raw, 0600)
+}
+
+// isAPIUnavailable returns whether the error indicates that the API could not be
+// reached or failed to handle the request, as opposed to rejecting it.
+func isAPIUnavailable(err error) bool {
+	switch e := err.(type) {
+	case errio.PublicStatusError:
+		return e.StatusCode >= 500
+	case errio.PublicError:
+		return e.Namespace == "http" && (e.Code == "request_failed" || e.Code == "timeout")
+	}
+	return false
+}
+
+// secretCacheReader reads secrets through a SecretCache.
+type secretCacheReader struct {
+	secretReader tpl.SecretReader
+	cache        *SecretCache
+}
+
+// newSecretCacheReader wraps a secret reader so that secrets are read through the given cache.
+func newSecretCacheReader(sr tpl.SecretReader, cache *SecretCache) *secretCacheReader {
+	return &secretCacheReader{
+		secretReader: sr,
+		cache:        cache,
+	}
+}
+
+// ReadSecret reads the secret through the cache, using the underlying secret reader
+// to read secrets that are not cached.
+func (sr *secretCacheReader) ReadSecret(path string) (string, error) {
+	data, err := sr.cache.read(path, func() ([]byte, error) {
+		secret, err := sr.secretReader.ReadSecret(path)
+		return []byte(secret), err
+	})
+	return string(data), err
+}
+
+// ListSecrets uses the underlying secret reader to list the secrets in a directory.
+// Directory listings are not cached.
+func (sr *secretCacheReader) ListSecrets(dirPath string) ([]string, error) {
+	return listSecrets(sr.secretReader, dirPath)
+}
@@ -0,0 +1,156 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+func newTestSecretCache(dir string, ttl time.Duration, key keyWrapper, now *time.Time) (*SecretCache, *bytes.Buffer) {
+	warnings := &bytes.Buffer{}
+	return &SecretCache{
+		ttl: ttl,
+		dir: func() string {
+			return dir
+		},
+		importKey: func() (keyWrapper, error) {
+			return key, nil
+		},
+		logger: cli.NewLoggerWithOutput(warnings),
+		now: func() time.Time {
+			return *now
+		},
+	}, warnings
+}
+
+func TestSecretCache_Read(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	key, err := credentials.GenerateRSACredential(1024)
+	assert.OK(t, err)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache, warnings := newTestSecretCache(dir, time.Minute, key, &now)
+
+	fetches := 0
+	fetch := func(value string, err error) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			fetches++
+			if err != nil {
+				return nil, err
+			}
+			return []byte(value), nil
+		}
+	}
+
+	// Not cached yet.
+	value, err := cache.read("namespace/repo/foo", fetch("foo", nil))
+	assert.OK(t, err)
+	assert.Equal(t, string(value), "foo")
+	assert.Equal(t, fetches, 1)
+
+	// Served from the cache within the TTL, also by a new instance.
+	now = now.Add(30 * time.Second)
+	cache, warnings = newTestSecretCache(dir, time.Minute, key, &now)
+	value, err = cache.read("namespace/repo/foo", fetch("bar", nil))
+	assert.OK(t, err)
+	assert.Equal(t, string(value), "foo")
+	assert.Equal(t, fetches, 1)
+
+	// Fetched again after the TTL.
+	now = now.Add(time.Minute)
+	value, err = cache.read("namespace/repo/foo", fetch("bar", nil))
+	assert.OK(t, err)
+	assert.Equal(t, string(value), "bar")
+	assert.Equal(t, fetches, 2)
+
+	// Expired value is served when the API is unavailable.
+	now = now.Add(time.Minute)
+	unavailable := errio.Namespace("http").Code("request_failed").ErrorPref("request to API server failed: %v")("connection refused")
+	value, err = cache.read("namespace/repo/foo", fetch("", unavailable))
+	assert.OK(t, err)
+	assert.Equal(t, string(value), "bar")
+	assert.Equal(t, fetches, 3)
+	assert.Equal(t, warnings.Len() > 0, true)
+
+	// Expired value is removed when the API rejects the request.
+	value, err = cache.read("namespace/repo/foo", fetch("", api.ErrSecretNotFound))
+	assert.Equal(t, err, api.ErrSecretNotFound)
+	assert.Equal(t, value, []byte(nil))
+	value, err = cache.read("namespace/repo/foo", fetch("", unavailable))
+	assert.Equal(t, err, unavailable)
+	assert.Equal(t, value, []byte(nil))
+	assert.Equal(t, fetches, 5)
+
+	// A cache written for another credential is not used.
+	_, err = cache.read("namespace/repo/foo", fetch("foo", nil))
+	assert.OK(t, err)
+	otherKey, err := credentials.GenerateRSACredential(1024)
+	assert.OK(t, err)
+	cache, _ = newTestSecretCache(dir, time.Minute, otherKey, &now)
+	value, err = cache.read("namespace/repo/foo", fetch("baz", nil))
+	assert.OK(t, err)
+	assert.Equal(t, string(value), "baz")
+	assert.Equal(t, fetches, 7)
+
+	// Clearing the cache removes all values.
+	err = cache.clear()
+	assert.OK(t, err)
+	value, err = cache.read("namespace/repo/foo", fetch("qux", nil))
+	assert.OK(t, err)
+	assert.Equal(t, string(value), "qux")
+	assert.Equal(t, fetches, 8)
+}
+
+func TestSecretCache_Disabled(t *testing.T) {
+	var cache *SecretCache
+	value, err := cache.read("namespace/repo/foo", func() ([]byte, error) {
+		return []byte("foo"), nil
+	})
+	assert.OK(t, err)
+	assert.Equal(t, string(value), "foo")
+}
+
+func TestIsAPIUnavailable(t *testing.T) {
+	cases := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"request failed": {
+			err:      errio.Namespace("http").Code("request_failed").ErrorPref("request to API server failed: %v")("connection refused"),
+			expected: true,
+		},
+		"timeout": {
+			err:      errio.Namespace("http").Code("timeout").Error("client timed out during request"),
+			expected: true,
+		},
+		"server error": {
+			err:      errio.Namespace("server").Code("unknown").StatusError("internal server error", http.StatusInternalServerError),
+			expected: true,
+		},
+		"not found": {
+			err:      api.ErrSecretNotFound,
+			expected: false,
+		},
+		"other": {
+			err:      errors.New("test"),
+			expected: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, isAPIUnavailable(tc.err), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// secretMetaSuffix is appended to the name of a secret to get the name of the sibling
+// secret that stores its labels. The API has no metadata on secrets, so the labels
+// are stored as an encrypted JSON object next to the secret.
+const secretMetaSuffix = ".meta"
+
+// wellKnownLabels are the labels with a conventional meaning. Other labels can be used too.
+var wellKnownLabels = []string{"owner", rotationPolicyLabel, secretExpiryLabel, "ticket", "environment"}
+
+var labelKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Errors
+var (
+	ErrInvalidLabel       = errMain.Code("invalid_label").ErrorPref("invalid label %q: use key=value, where the key consists of lowercase letters, digits, dots, dashes and underscores")
+	ErrInvalidLabelFilter = errMain.Code("invalid_label_filter").ErrorPref("invalid label filter %q: use key=value or key")
+	ErrInvalidSecretMeta  = errMain.Code("invalid_secret_meta").ErrorPref("the labels of %s in %s cannot be parsed: %s")
+	ErrNoLabels           = errMain.Code("no_labels").Error("no labels given: give the labels to set as key=value after the path")
+	ErrLabelNotSet        = errMain.Code("label_not_set").ErrorPref("label %s is not set on %s")
+	ErrMetaOfMetaSecret   = errMain.Code("meta_of_meta_secret").ErrorPref("%s stores the labels of another secret and cannot have labels itself")
+)
+
+// secretMetaPath returns the path of the secret that stores the labels of the given secret.
+func secretMetaPath(path api.SecretPath) string {
+	return path.Value() + secretMetaSuffix
+}
+
+// isSecretMetaName returns whether a secret with the given name stores the labels of another secret.
+func isSecretMetaName(name string) bool {
+	return strings.HasSuffix(name, secretMetaSuffix)
+}
+
+// readSecretLabels returns the labels of the secret at the given path.
+// A secret without labels has an empty set of labels.
+func readSecretLabels(client secrethub.ClientInterface, path api.SecretPath) (map[string]string, error) {
+	metaPath := secretMetaPath(path)
+	version, err := client.Secrets().Versions().GetWithData(metaPath)
+	if api.IsErrNotFound(err) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	labels := map[string]string{}
+	err = json.Unmarshal(version.Data, &labels)
+	if err != nil {
+		return nil, ErrInvalidSecretMeta(path, metaPath, err)
+	}
+	return labels, nil
+}
+
+// writeSecretLabels stores the labels of the secret at the given path as a new version of its meta secret.
+func writeSecretLabels(client secrethub.ClientInterface, path api.SecretPath, labels map[string]string) error {
+	data, err := json.Marshal(labels)
+	if err != nil {
+		return err
+	}
+	_, err = client.Secrets().Write(secretMetaPath(path), data)
+	return err
+}
+
+// parseLabel parses a label given as key=value. An empty value is allowed and means the label is removed.
+func parseLabel(raw string) (string, string, error) {
+	parts := strings.SplitN(raw, "=", 2)
+	if len(parts) != 2 || !labelKeyPattern.MatchString(parts[0]) {
+		return "", "", ErrInvalidLabel(raw)
+	}
+	return parts[0], parts[1], nil
+}
+
+// formatLabels formats labels as a comma separated list of key=value pairs, sorted by key.
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + labels[key]
+	}
+	return strings.Join(pairs, ",")
+}
+
+// labelFilter selects secrets by their labels. A condition with an empty value
+// only requires the label to be set.
+type labelFilter map[string]string
+
+// parseLabelFilter parses a list of key=value or key conditions that must all hold.
+func parseLabelFilter(conditions []string) (labelFilter, error) {
+	filter := labelFilter{}
+	for _, condition := range conditions {
+		parts := strings.SplitN(condition, "=", 2)
+		if !labelKeyPattern.MatchString(parts[0]) {
+			return nil, ErrInvalidLabelFilter(condition)
+		}
+		if len(parts) == 2 {
+			filter[parts[0]] = parts[1]
+		} else {
+			filter[parts[0]] = ""
+		}
+	}
+	return filter, nil
+}
+
+// matches returns whether the given labels satisfy all conditions of the filter.
+func (f labelFilter) matches(labels map[string]string) bool {
+	for key, value := range f {
+		actual, ok := labels[key]
+		if !ok || (value != "" && actual != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// labeledSecret is a secret with its labels.
+type labeledSecret struct {
+	*api.Secret
+	labels map[string]string
+}
+
+// filterSecretsByLabel returns the secrets directly in the directory whose labels match the filter,
+// sorted by name. Secrets that store labels are left out. Only the labels of secrets with a meta
+// secret next to them are read.
+func filterSecretsByLabel(client secrethub.ClientInterface, dirPath api.DirPath, secrets []*api.Secret, filter labelFilter) ([]labeledSecret, error) {
+	names := make(map[string]bool, len(secrets))
+	for _, secret := range secrets {
+		names[secret.Name] = true
+	}
+
+	sorted := make([]*api.Secret, len(secrets))
+	copy(sorted, secrets)
+	sort.Sort(api.SortSecretByName(sorted))
+
+	var matching []labeledSecret
+	for _, secret := range sorted {
+		if isSecretMetaName(secret.Name) {
+			continue
+		}
+
+		labels := map[string]string{}
+		if names[secret.Name+secretMetaSuffix] {
+			var err error
+			labels, err = readSecretLabels(client, api.SecretPath(dirPath.Value()+"/"+secret.Name))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if filter.matches(labels) {
+			matching = append(matching, labeledSecret{Secret: secret, labels: labels})
+		}
+	}
+	return matching, nil
+}
+
+// labeledPath is the path of a secret with its labels.
+type labeledPath struct {
+	path   api.SecretPath
+	labels map[string]string
+}
+
+// findLabeledSecrets returns the secrets in the directory and its subdirectories whose labels match
+// the filter, sorted by path. The secrets are found by their meta secrets, so a secret that has
+// labels but does not exist (yet) is returned as well.
+func findLabeledSecrets(client secrethub.ClientInterface, dirPath api.DirPath, filter labelFilter) ([]labeledPath, error) {
+	tree, err := client.Dirs().GetTree(dirPath.Value(), -1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for id, secret := range tree.Secrets {
+		if !isSecretMetaName(secret.Name) {
+			continue
+		}
+		metaPath, err := tree.AbsSecretPath(id)
+		if err != nil {
+			return nil, err
+		}
+		path := strings.TrimSuffix(metaPath.String(), secretMetaSuffix)
+		if isSecretMetaName(path) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var matching []labeledPath
+	for _, path := range paths {
+		labels, err := readSecretLabels(client, api.SecretPath(path))
+		if err != nil {
+			return nil, err
+		}
+		if filter.matches(labels) {
+			matching = append(matching, labeledPath{path: api.SecretPath(path), labels: labels})
+		}
+	}
+	return matching, nil
+}
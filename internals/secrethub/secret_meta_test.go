@@ -0,0 +1,244 @@
+package secrethub
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestLabelFilter(t *testing.T) {
+	labels := map[string]string{
+		"owner":       "team-a",
+		"environment": "production",
+	}
+
+	cases := map[string]struct {
+		conditions []string
+		expected   bool
+		err        error
+	}{
+		"empty filter": {
+			expected: true,
+		},
+		"equal value": {
+			conditions: []string{"owner=team-a"},
+			expected:   true,
+		},
+		"other value": {
+			conditions: []string{"owner=team-b"},
+			expected:   false,
+		},
+		"key only": {
+			conditions: []string{"environment"},
+			expected:   true,
+		},
+		"missing key": {
+			conditions: []string{"ticket"},
+			expected:   false,
+		},
+		"all conditions must hold": {
+			conditions: []string{"owner=team-a", "environment=staging"},
+			expected:   false,
+		},
+		"invalid key": {
+			conditions: []string{"Owner=team-a"},
+			err:        ErrInvalidLabelFilter("Owner=team-a"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			filter, err := parseLabelFilter(tc.conditions)
+			assert.Equal(t, err, tc.err)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, filter.matches(labels), tc.expected)
+		})
+	}
+}
+
+func TestMetaSetCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		args          []string
+		current       string
+		expectedWrite string
+		expectedErr   error
+	}{
+		"new labels": {
+			args:          []string{"namespace/repo/secret", "owner=team-a", "ticket=SEC-1"},
+			expectedWrite: `{"owner":"team-a","ticket":"SEC-1"}`,
+		},
+		"update and remove labels": {
+			args:          []string{"namespace/repo/secret", "owner=team-b", "ticket="},
+			current:       `{"environment":"production","owner":"team-a","ticket":"SEC-1"}`,
+			expectedWrite: `{"environment":"production","owner":"team-b"}`,
+		},
+		"no labels": {
+			args:        []string{"namespace/repo/secret"},
+			expectedErr: ErrNoLabels,
+		},
+		"invalid label": {
+			args:        []string{"namespace/repo/secret", "owner"},
+			expectedErr: ErrInvalidLabel("owner"),
+		},
+		"meta secret": {
+			args:        []string{"namespace/repo/secret.meta", "owner=team-a"},
+			expectedErr: ErrMetaOfMetaSecret("namespace/repo/secret.meta"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written string
+			cmd := MetaSetCommand{
+				io:   fakeui.NewIO(t),
+				args: tc.args,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							GetFunc: func(path string) (*api.Secret, error) {
+								return &api.Secret{}, nil
+							},
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									assert.Equal(t, path, "namespace/repo/secret.meta")
+									if tc.current == "" {
+										return nil, api.ErrSecretNotFound
+									}
+									return &api.SecretVersion{Data: []byte(tc.current)}, nil
+								},
+							},
+							WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+								assert.Equal(t, path, "namespace/repo/secret.meta")
+								written = string(data)
+								return &api.SecretVersion{}, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, written, tc.expectedWrite)
+		})
+	}
+}
+
+// fakeSecretStore keeps the secrets of the repository namespace/repo in memory,
+// for testing commands that read and write labels. It has no subdirectories.
+type fakeSecretStore struct {
+	data      map[string][]byte
+	createdAt map[string]time.Time
+	written   []string
+}
+
+func newFakeSecretStore() *fakeSecretStore {
+	return &fakeSecretStore{
+		data:      map[string][]byte{},
+		createdAt: map[string]time.Time{},
+	}
+}
+
+// newClient returns a client that reads and writes the secrets in the store.
+func (s *fakeSecretStore) newClient() (secrethub.ClientInterface, error) {
+	return fakeclient.Client{
+		DirService: &fakeclient.DirService{
+			GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				return s.tree(), nil
+			},
+		},
+		SecretService: &fakeclient.SecretService{
+			ExistsFunc: func(path string) (bool, error) {
+				_, ok := s.data[path]
+				return ok, nil
+			},
+			GetFunc: func(path string) (*api.Secret, error) {
+				if _, ok := s.data[path]; !ok {
+					return nil, api.ErrSecretNotFound
+				}
+				return &api.Secret{Name: api.SecretPath(path).GetSecret()}, nil
+			},
+			VersionService: &fakeclient.SecretVersionService{
+				GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+					data, ok := s.data[path]
+					if !ok {
+						return nil, api.ErrSecretNotFound
+					}
+					return &api.SecretVersion{Version: 1, Data: data, CreatedAt: s.createdAt[path]}, nil
+				},
+				GetWithoutDataFunc: func(path string) (*api.SecretVersion, error) {
+					if _, ok := s.data[path]; !ok {
+						return nil, api.ErrSecretNotFound
+					}
+					return &api.SecretVersion{Version: 1, CreatedAt: s.createdAt[path]}, nil
+				},
+			},
+			WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+				s.data[path] = data
+				s.written = append(s.written, path)
+				return &api.SecretVersion{Version: 2}, nil
+			},
+		},
+	}, nil
+}
+
+// tree returns the tree of the repository with all secrets in the store.
+func (s *fakeSecretStore) tree() *api.Tree {
+	rootID := uuid.New()
+	tree := &api.Tree{
+		ParentPath: "namespace",
+		RootDir: &api.Dir{
+			Name:  "repo",
+			DirID: rootID,
+		},
+		Dirs: map[uuid.UUID]*api.Dir{
+			rootID: {
+				Name:  "repo",
+				DirID: rootID,
+			},
+		},
+		Secrets: map[uuid.UUID]*api.Secret{},
+	}
+	for path := range s.data {
+		secretID := uuid.New()
+		tree.Secrets[secretID] = &api.Secret{SecretID: secretID, DirID: rootID, Name: strings.TrimPrefix(path, "namespace/repo/")}
+	}
+	return tree
+}
+
+func TestFindLabeledSecrets(t *testing.T) {
+	store := newFakeSecretStore()
+	store.data["namespace/repo/b"] = []byte("secret")
+	store.data["namespace/repo/b.meta"] = []byte(`{"owner":"team-b"}`)
+	store.data["namespace/repo/a.meta"] = []byte(`{"owner":"team-a","ticket":"SEC-1"}`)
+	store.data["namespace/repo/c"] = []byte("secret")
+	store.data["namespace/repo/c.meta.meta"] = []byte(`{"owner":"team-c"}`)
+
+	client, err := store.newClient()
+	assert.OK(t, err)
+
+	actual, err := findLabeledSecrets(client, "namespace/repo", labelFilter{"owner": ""})
+	assert.OK(t, err)
+	assert.Equal(t, actual, []labeledPath{
+		{path: "namespace/repo/a", labels: map[string]string{"owner": "team-a", "ticket": "SEC-1"}},
+		{path: "namespace/repo/b", labels: map[string]string{"owner": "team-b"}},
+	})
+
+	actual, err = findLabeledSecrets(client, "namespace/repo", labelFilter{"ticket": "SEC-1"})
+	assert.OK(t, err)
+	assert.Equal(t, actual, []labeledPath{
+		{path: "namespace/repo/a", labels: map[string]string{"owner": "team-a", "ticket": "SEC-1"}},
+	})
+}
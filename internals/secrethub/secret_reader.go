@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"sync"
+
 	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl"
 	"github.com/secrethub/secrethub-go/internals/api"
 )
@@ -16,28 +18,66 @@ func newSecretReader(newClient newClientFunc) *secretReader {
 	}
 }
 
-// ReadSecret reads the secret using the provided client.
+// ReadSecret reads the secret using the provided client. When the version of
+// the path is a constraint, such as :>=3 <5, the highest matching version is read.
 func (sr secretReader) ReadSecret(path string) (string, error) {
 	client, err := sr.newClient()
 	if err != nil {
 		return "", err
 	}
 
+	path, err = resolveVersionConstraint(client, path)
+	if err != nil {
+		return "", err
+	}
+
 	secret, err := client.Secrets().Versions().GetWithData(path)
 	if err != nil {
 		return "", err
 	}
 
+	// The value is returned as a string, which cannot be wiped, so the data is left to the garbage collector.
 	return string(secret.Data), nil
 }
 
+// ListSecrets returns the names of the secrets directly inside the given directory.
+func (sr secretReader) ListSecrets(dirPath string) ([]string, error) {
+	client, err := sr.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := client.Dirs().GetTree(dirPath, 1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(tree.RootDir.Secrets))
+	for i, secret := range tree.RootDir.Secrets {
+		names[i] = secret.Name
+	}
+	return names, nil
+}
+
+// listSecrets lists the secrets in a directory with the given secret reader,
+// if it supports listing directories.
+func listSecrets(sr tpl.SecretReader, dirPath string) ([]string, error) {
+	dirReader, ok := sr.(tpl.DirReader)
+	if !ok {
+		return nil, tpl.ErrDirListingNotSupported
+	}
+	return dirReader.ListSecrets(dirPath)
+}
+
 type bufferedSecretReader struct {
 	secretReader tpl.SecretReader
 	secretsRead  []string
+	mutex        sync.Mutex
 }
 
 // newBufferedSecretReader wraps a secret reader and stores the retrieved
 // secret values for retrieval with the Values function.
+// It is safe for concurrent use.
 func newBufferedSecretReader(sr tpl.SecretReader) *bufferedSecretReader {
 	return &bufferedSecretReader{
 		secretReader: sr,
@@ -51,12 +91,19 @@ func (sr *bufferedSecretReader) ReadSecret(path string) (string, error) {
 	secret, err := sr.secretReader.ReadSecret(path)
 
 	if err == nil {
+		sr.mutex.Lock()
 		sr.secretsRead = append(sr.secretsRead, secret)
+		sr.mutex.Unlock()
 	}
 
 	return secret, err
 }
 
+// ListSecrets uses the underlying secret reader to list the secrets in a directory.
+func (sr *bufferedSecretReader) ListSecrets(dirPath string) ([]string, error) {
+	return listSecrets(sr.secretReader, dirPath)
+}
+
 type secretReaderNotAllowed struct{}
 
 func (sr secretReaderNotAllowed) ReadSecret(path string) (string, error) {
@@ -64,7 +111,9 @@ func (sr secretReaderNotAllowed) ReadSecret(path string) (string, error) {
 }
 
 // Values returns a list of values read with this secret reader.
-func (sr bufferedSecretReader) Values() []string {
+func (sr *bufferedSecretReader) Values() []string {
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
 	return sr.secretsRead
 }
 
@@ -87,3 +136,84 @@ func (sr *ignoreMissingSecretReader) ReadSecret(path string) (string, error) {
 	}
 	return secret, err
 }
+
+// ListSecrets uses the underlying secret reader to list the secrets in a directory.
+func (sr *ignoreMissingSecretReader) ListSecrets(dirPath string) ([]string, error) {
+	return listSecrets(sr.secretReader, dirPath)
+}
+
+type cachingSecretReader struct {
+	secretReader tpl.SecretReader
+	secrets      map[string]*cachedSecret
+	dirs         map[string][]string
+	mutex        sync.Mutex
+}
+
+// cachedSecret is the result of reading a secret. The ready channel is
+// closed once value and err are set.
+type cachedSecret struct {
+	ready chan struct{}
+	value string
+	err   error
+}
+
+// newCachingSecretReader wraps a secret reader so that every secret path
+// (including its version, if given) is only read once during its lifetime.
+// It is safe for concurrent use: concurrent reads of the same path wait for
+// the first read to complete.
+func newCachingSecretReader(sr tpl.SecretReader) *cachingSecretReader {
+	return &cachingSecretReader{
+		secretReader: sr,
+		secrets:      make(map[string]*cachedSecret),
+		dirs:         make(map[string][]string),
+	}
+}
+
+// ReadSecret returns the cached value of the secret if it was read before.
+// Otherwise, it uses the underlying secret reader to read the secret and caches
+// the result. Errors are only returned to concurrent reads of the same path and
+// are not cached.
+func (sr *cachingSecretReader) ReadSecret(path string) (string, error) {
+	sr.mutex.Lock()
+	cached, ok := sr.secrets[path]
+	if ok {
+		sr.mutex.Unlock()
+		<-cached.ready
+		return cached.value, cached.err
+	}
+	cached = &cachedSecret{ready: make(chan struct{})}
+	sr.secrets[path] = cached
+	sr.mutex.Unlock()
+
+	cached.value, cached.err = sr.secretReader.ReadSecret(path)
+	if cached.err != nil {
+		sr.mutex.Lock()
+		delete(sr.secrets, path)
+		sr.mutex.Unlock()
+	}
+	close(cached.ready)
+
+	return cached.value, cached.err
+}
+
+// ListSecrets returns the cached listing of the directory if it was listed before.
+// Otherwise, it uses the underlying secret reader to list the directory and caches
+// the result.
+func (sr *cachingSecretReader) ListSecrets(dirPath string) ([]string, error) {
+	sr.mutex.Lock()
+	names, ok := sr.dirs[dirPath]
+	sr.mutex.Unlock()
+	if ok {
+		return names, nil
+	}
+
+	names, err := listSecrets(sr.secretReader, dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	sr.mutex.Lock()
+	sr.dirs[dirPath] = names
+	sr.mutex.Unlock()
+	return names, nil
+}
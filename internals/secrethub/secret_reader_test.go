@@ -0,0 +1,57 @@
+package secrethub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+type countingSecretReader struct {
+	secrets map[string]string
+	reads   map[string]int
+}
+
+func (sr *countingSecretReader) ReadSecret(path string) (string, error) {
+	sr.reads[path]++
+	secret, ok := sr.secrets[path]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return secret, nil
+}
+
+func TestCachingSecretReader(t *testing.T) {
+	counter := &countingSecretReader{
+		secrets: map[string]string{
+			"namespace/repo/foo":   "foo",
+			"namespace/repo/foo:1": "foo1",
+		},
+		reads: make(map[string]int),
+	}
+	sr := newCachingSecretReader(counter)
+
+	for i := 0; i < 3; i++ {
+		secret, err := sr.ReadSecret("namespace/repo/foo")
+		assert.OK(t, err)
+		assert.Equal(t, secret, "foo")
+
+		secret, err = sr.ReadSecret("namespace/repo/foo:1")
+		assert.OK(t, err)
+		assert.Equal(t, secret, "foo1")
+
+		_, err = sr.ReadSecret("namespace/repo/missing")
+		assert.Equal(t, err, errors.New("secret not found"))
+	}
+
+	assert.Equal(t, counter.reads, map[string]int{
+		"namespace/repo/foo":     1,
+		"namespace/repo/foo:1":   1,
+		"namespace/repo/missing": 3,
+	})
+
+	_, err := sr.ListSecrets("namespace/repo")
+	assert.Equal(t, err, tpl.ErrDirListingNotSupported)
+}
@@ -0,0 +1,248 @@
+package secrethub
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+const (
+	secretTrashDirName  = "trash"
+	secretTrashFileName = "secrets.json"
+
+	// secretTrashRetention is how long removed secrets are kept in the trash.
+	secretTrashRetention = 7 * 24 * time.Hour
+)
+
+// Errors
+var (
+	ErrCannotTrashSecret  = errMain.Code("cannot_trash_secret").ErrorPref("could not move %s to the trash: %s. Use --permanent to remove it without keeping a copy")
+	ErrNotInTrash         = errMain.Code("not_in_trash").ErrorPref("%s is not in the trash: only secrets removed in the last 7 days on this machine can be restored")
+	ErrInvalidSecretTrash = errMain.Code("invalid_secret_trash").ErrorPref("could not parse the trash file %s: %s")
+)
+
+// SecretTrash keeps a local copy of the secrets that are removed with rm, so that they can be restored
+// with the restore command within the retention period. Like the SecretCache, the values are encrypted
+// with a key that is stored next to them, encrypted with the account key of the credential.
+type SecretTrash struct {
+	dir       func() string
+	importKey func() (keyWrapper, error)
+	now       func() time.Time
+
+	file *secretTrashFile
+	key  *crypto.SymmetricKey
+}
+
+// secretTrashFile is the format in which the trash is stored on disk.
+type secretTrashFile struct {
+	// Key is the symmetric key that encrypts the secrets, wrapped by the account key.
+	Key *api.EncryptedData `json:"key"`
+	// Secrets maps the HMAC of the path of every removed secret to its entry.
+	Secrets map[string]secretTrashEntry `json:"secrets"`
+}
+
+// secretTrashEntry is a removed secret with the values of all of its versions.
+type secretTrashEntry struct {
+	RemovedAt time.Time            `json:"removed_at"`
+	Versions  []secretTrashVersion `json:"versions"`
+}
+
+// secretTrashVersion is a single version of a removed secret.
+type secretTrashVersion struct {
+	Version   int                  `json:"version"`
+	CreatedAt time.Time            `json:"created_at"`
+	Value     crypto.CiphertextAES `json:"value"`
+}
+
+// NewSecretTrash creates a new SecretTrash that stores its contents in the configuration
+// directory of the given credential config.
+func NewSecretTrash(store CredentialConfig) *SecretTrash {
+	return &SecretTrash{
+		dir: func() string {
+			return filepath.Join(store.ConfigDir().Path(), secretTrashDirName)
+		},
+		importKey: func() (keyWrapper, error) {
+			return newCredentialKeyWrapper(store)
+		},
+		now: time.Now,
+	}
+}
+
+// path returns the location of the trash file.
+func (t *SecretTrash) path() string {
+	return filepath.Join(t.dir(), secretTrashFileName)
+}
+
+// add copies all versions of the secret at the given path to the trash.
+func (t *SecretTrash) add(client secrethub.ClientInterface, path api.SecretPath) error {
+	versions, err := client.Secrets().Versions().ListWithData(path.Value())
+	if err != nil {
+		return ErrCannotTrashSecret(path, err)
+	}
+
+	err = t.load()
+	if err != nil {
+		return ErrCannotTrashSecret(path, err)
+	}
+
+	entry := secretTrashEntry{
+		RemovedAt: t.now().UTC(),
+	}
+	for _, version := range versions {
+		if version.Status != api.StatusOK {
+			continue
+		}
+		ciphertext, err := t.key.Encrypt(version.Data)
+		if err != nil {
+			return ErrCannotTrashSecret(path, err)
+		}
+		entry.Versions = append(entry.Versions, secretTrashVersion{
+			Version:   version.Version,
+			CreatedAt: version.CreatedAt,
+			Value:     ciphertext,
+		})
+	}
+	sort.Slice(entry.Versions, func(i, j int) bool {
+		return entry.Versions[i].Version < entry.Versions[j].Version
+	})
+
+	id, err := t.entryID(path)
+	if err != nil {
+		return ErrCannotTrashSecret(path, err)
+	}
+	t.file.Secrets[id] = entry
+
+	err = t.file.write(t.path())
+	if err != nil {
+		return ErrCannotTrashSecret(path, err)
+	}
+	return nil
+}
+
+// restore writes the versions of the secret at the given path that is in the trash back, from the
+// oldest to the newest version, and removes it from the trash. The number of versions is returned.
+func (t *SecretTrash) restore(client secrethub.ClientInterface, path api.SecretPath) (int, error) {
+	err := t.load()
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := t.entryID(path)
+	if err != nil {
+		return 0, err
+	}
+	entry, ok := t.file.Secrets[id]
+	if !ok {
+		return 0, ErrNotInTrash(path)
+	}
+
+	for _, version := range entry.Versions {
+		data, err := t.key.Decrypt(version.Value)
+		if err != nil {
+			return 0, err
+		}
+		_, err = client.Secrets().Write(path.Value(), data)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	delete(t.file.Secrets, id)
+	return len(entry.Versions), t.file.write(t.path())
+}
+
+// load reads the trash file, unwraps its key and removes the secrets that were removed longer
+// than the retention period ago. When there is no usable trash file, a new trash with a new key is started.
+func (t *SecretTrash) load() error {
+	if t.file != nil {
+		return nil
+	}
+
+	wrapper, err := t.importKey()
+	if err != nil {
+		return err
+	}
+
+	file, err := readSecretTrashFile(t.path())
+	if err == nil && file.Key != nil {
+		rawKey, err := wrapper.Unwrap(file.Key)
+		if err == nil {
+			t.file = file
+			t.key = crypto.NewSymmetricKey(rawKey)
+			t.purgeExpired()
+			return nil
+		}
+	}
+
+	// The trash does not exist, cannot be read or was encrypted for another credential.
+	key, err := crypto.GenerateSymmetricKey()
+	if err != nil {
+		return err
+	}
+	wrappedKey, err := wrapper.Wrap(key.Export())
+	if err != nil {
+		return err
+	}
+	t.file = &secretTrashFile{
+		Key:     wrappedKey,
+		Secrets: make(map[string]secretTrashEntry),
+	}
+	t.key = key
+	return nil
+}
+
+// purgeExpired removes the secrets that were removed longer than the retention period ago.
+func (t *SecretTrash) purgeExpired() {
+	for id, entry := range t.file.Secrets {
+		if t.now().Sub(entry.RemovedAt) > secretTrashRetention {
+			delete(t.file.Secrets, id)
+		}
+	}
+}
+
+// entryID returns the key under which the secret at the given path is stored,
+// so that the trash file does not reveal which secrets were removed.
+func (t *SecretTrash) entryID(path api.SecretPath) (string, error) {
+	mac, err := t.key.HMAC([]byte(path.Value()))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(mac), nil
+}
+
+// readSecretTrashFile reads and parses the trash file at the given path.
+func readSecretTrashFile(path string) (*secretTrashFile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &secretTrashFile{}
+	err = json.Unmarshal(raw, file)
+	if err != nil {
+		return nil, ErrInvalidSecretTrash(path, err)
+	}
+	if file.Secrets == nil {
+		file.Secrets = make(map[string]secretTrashEntry)
+	}
+	return file, nil
+}
+
+// write atomically writes the trash file to the given path.
+func (f *secretTrashFile) write(path string) error {
+	raw, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(path, raw, 0600)
+}
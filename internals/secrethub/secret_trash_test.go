@@ -0,0 +1,78 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func newTestSecretTrash(dir string, key keyWrapper, now *time.Time) *SecretTrash {
+	return &SecretTrash{
+		dir: func() string {
+			return dir
+		},
+		importKey: func() (keyWrapper, error) {
+			return key, nil
+		},
+		now: func() time.Time {
+			return *now
+		},
+	}
+}
+
+func TestSecretTrash(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	key, err := credentials.GenerateRSACredential(1024)
+	assert.OK(t, err)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := api.SecretPath("namespace/repo/secret")
+
+	var written []string
+	client := fakeclient.Client{
+		SecretService: &fakeclient.SecretService{
+			VersionService: &fakeclient.SecretVersionService{
+				ListWithDataFunc: func(path string) ([]*api.SecretVersion, error) {
+					return []*api.SecretVersion{
+						{Version: 3, Status: api.StatusOK, Data: []byte("third")},
+						{Version: 1, Status: api.StatusOK, Data: []byte("first")},
+						{Version: 2, Status: "flagged"},
+					}, nil
+				},
+			},
+			WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+				written = append(written, string(data))
+				return &api.SecretVersion{}, nil
+			},
+		},
+	}
+
+	trash := newTestSecretTrash(dir, key, &now)
+	assert.OK(t, trash.add(client, path))
+
+	// Restored by a new instance, from the oldest to the newest version.
+	now = now.Add(24 * time.Hour)
+	trash = newTestSecretTrash(dir, key, &now)
+	versions, err := trash.restore(client, path)
+	assert.OK(t, err)
+	assert.Equal(t, versions, 2)
+	assert.Equal(t, written, []string{"first", "third"})
+
+	// Restoring removes the secret from the trash.
+	trash = newTestSecretTrash(dir, key, &now)
+	_, err = trash.restore(client, path)
+	assert.Equal(t, err, ErrNotInTrash(path))
+
+	// Secrets are removed from the trash after the retention period.
+	assert.OK(t, trash.add(client, path))
+	now = now.Add(secretTrashRetention + time.Minute)
+	trash = newTestSecretTrash(dir, key, &now)
+	_, err = trash.restore(client, path)
+	assert.Equal(t, err, ErrNotInTrash(path))
+}
@@ -0,0 +1,537 @@
+package secrethub
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+const (
+	// defaultReleasesURL lists the releases of the CLI, newest first.
+	defaultReleasesURL = "https://api.github.com/repos/secrethub/secrethub-cli/releases"
+
+	releaseChannelStable = "stable"
+	releaseChannelBeta   = "beta"
+
+	// maxReleaseDownloadSize limits the size of downloaded release files.
+	maxReleaseDownloadSize = 200 << 20
+)
+
+// Errors
+var (
+	ErrUnknownReleaseChannel           = errMain.Code("unknown_release_channel").ErrorPref("unknown release channel %s: must be stable or beta")
+	ErrNoRelease                       = errMain.Code("no_release").ErrorPref("no release found on the %s channel")
+	ErrReleaseAssetNotFound            = errMain.Code("release_asset_not_found").ErrorPref("release %s has no %s")
+	ErrReleaseDownloadFailed           = errMain.Code("release_download_failed").ErrorPref("could not download %s: %s")
+	ErrNoReleaseSigningKey             = errMain.Code("no_release_signing_key").Error("this build of the CLI has no release signing key, so the signature of a release cannot be verified. Use --insecure-skip-signature to only verify the checksum")
+	ErrInvalidReleaseSignature         = errMain.Code("invalid_release_signature").ErrorPref("the signature of the checksums of release %s is invalid")
+	ErrReleaseChecksumMismatch         = errMain.Code("release_checksum_mismatch").ErrorPref("the checksum of %s does not match the checksum of the release")
+	ErrReleaseBinaryNotFound           = errMain.Code("release_binary_not_found").ErrorPref("%s does not contain the secrethub binary")
+	ErrReleaseDowngrade                = errMain.Code("release_downgrade").ErrorPref("the latest release on the %s channel (%s) is older than the current version (%s). Use --allow-downgrade to downgrade the CLI anyway")
+	ErrCannotUpdateWithoutConfirmation = errMain.Code("cannot_update_without_confirmation").Error("cannot ask for confirmation to update the CLI. Run the same command with the --yes or -y flag to update without confirmation")
+)
+
+// githubRelease is a release as returned by the GitHub releases API.
+type githubRelease struct {
+	TagName string               `json:"tag_name"`
+	Draft   bool                 `json:"draft"`
+	Assets  []githubReleaseAsset `json:"assets"`
+}
+
+// githubReleaseAsset is a file attached to a release.
+type githubReleaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// asset returns the download URL of the file with the given name.
+func (r githubRelease) asset(name string) (string, error) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset.URL, nil
+		}
+	}
+	return "", ErrReleaseAssetNotFound(r.TagName, name)
+}
+
+// SelfUpdateCommand replaces the running CLI binary with the latest release.
+type SelfUpdateCommand struct {
+	io                    ui.IO
+	clientFactory         ClientFactory
+	channel               string
+	check                 bool
+	yes                   bool
+	allowDowngrade        bool
+	insecureSkipSignature bool
+	releasesURL           string
+	version               string
+	signingKey            string
+	platform              string
+	executable            func() (string, error)
+}
+
+// NewSelfUpdateCommand creates a new SelfUpdateCommand.
+func NewSelfUpdateCommand(io ui.IO, clientFactory ClientFactory) *SelfUpdateCommand {
+	return &SelfUpdateCommand{
+		io:            io,
+		clientFactory: clientFactory,
+		releasesURL:   defaultReleasesURL,
+		version:       Version,
+		signingKey:    ReleaseSigningKey,
+		platform:      releasePlatform(),
+		executable:    os.Executable,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *SelfUpdateCommand) Register(r cli.Registerer) {
+	clause := r.Command("self-update", "Update the CLI to the latest release.")
+	clause.HelpLong("Self-update downloads the latest release of the CLI for this platform and replaces the running binary with it. " +
+		"The stable channel only contains final releases, while the beta channel also contains pre-releases.\n\n" +
+		"The checksums of a release are signed by the maintainers, together with the version of the release. " +
+		"Before the binary is installed, the signature of the checksums and the checksum of the downloaded archive are verified. " +
+		"The binary is replaced atomically, so an interrupted update leaves the current binary in place. " +
+		"A release that is older than the running binary is only installed with --allow-downgrade, which also reinstalls the current version. Use --yes to update without asking for confirmation.\n\n" +
+		"The downloads go through the proxy set with --proxy-address or the HTTPS_PROXY environment variable. " +
+		"When the CLI was installed with a package manager, use the package manager to update it instead.")
+	clause.Flags().StringVar(&cmd.channel, "channel", releaseChannelStable, "The release channel to update from: stable or beta.")
+	clause.Flags().BoolVar(&cmd.check, "check", false, "Only check whether an update is available.")
+	clause.Flags().BoolVar(&cmd.insecureSkipSignature, "insecure-skip-signature", false, "Do not verify the signature of the release, only its checksum. Only use this for builds without a release signing key.")
+	clause.Flags().BoolVarP(&cmd.yes, "yes", "y", false, "Do not ask for confirmation before updating.")
+	clause.Flags().BoolVar(&cmd.allowDowngrade, "allow-downgrade", false, "Install the latest release even when it is the current version or older than the current version.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run checks for the latest release and installs it.
+func (cmd *SelfUpdateCommand) Run() error {
+	if cmd.channel != releaseChannelStable && cmd.channel != releaseChannelBeta {
+		return ErrUnknownReleaseChannel(cmd.channel)
+	}
+	if cmd.signingKey == "" && !cmd.insecureSkipSignature && !cmd.check {
+		return ErrNoReleaseSigningKey
+	}
+
+	client := &http.Client{Transport: cmd.clientFactory.Transport()}
+
+	release, err := cmd.latestRelease(client)
+	if err != nil {
+		return err
+	}
+
+	current := strings.TrimPrefix(cmd.version, "v")
+	latest := strings.TrimPrefix(release.TagName, "v")
+	w := cmd.io.Output()
+	// The version of a development build is unknown, so any release is an update.
+	cmp, known := compareVersions(current, latest)
+	if known && cmp == 0 && !cmd.allowDowngrade {
+		fmt.Fprintf(w, "The CLI is up to date (%s).\n", latest)
+		return nil
+	}
+	if known && cmp > 0 && !cmd.allowDowngrade {
+		if cmd.check {
+			fmt.Fprintf(w, "The CLI is newer than the latest release on the %s channel (current version: %s, latest release: %s).\n", cmd.channel, current, latest)
+			return nil
+		}
+		return ErrReleaseDowngrade(cmd.channel, latest, current)
+	}
+	if cmd.check {
+		fmt.Fprintf(w, "An update is available: %s (current version: %s).\n", latest, currentVersionName(current))
+		return nil
+	}
+
+	if !cmd.yes {
+		confirmed, err := ui.AskYesNo(cmd.io, fmt.Sprintf("Update the CLI from %s to %s?", currentVersionName(current), latest), ui.DefaultYes)
+		if err == ui.ErrCannotAsk {
+			return ErrCannotUpdateWithoutConfirmation
+		} else if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(w, "Aborting.")
+			return nil
+		}
+	}
+
+	binary, err := cmd.download(client, release)
+	if err != nil {
+		return err
+	}
+
+	executable, err := cmd.executable()
+	if err != nil {
+		return err
+	}
+	executable, err = filepath.EvalSymlinks(executable)
+	if err != nil {
+		return err
+	}
+	err = replaceExecutable(executable, binary)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "The CLI has been updated to %s.\n", latest)
+	return nil
+}
+
+// latestRelease returns the newest release on the channel. Final releases are tagged
+// with a version without a pre-release suffix, e.g. v0.42.0 and not v0.42.0-beta.1.
+func (cmd *SelfUpdateCommand) latestRelease(client *http.Client) (githubRelease, error) {
+	raw, err := downloadReleaseFile(client, cmd.releasesURL)
+	if err != nil {
+		return githubRelease{}, err
+	}
+
+	var releases []githubRelease
+	err = json.Unmarshal(raw, &releases)
+	if err != nil {
+		return githubRelease{}, ErrReleaseDownloadFailed(cmd.releasesURL, err)
+	}
+
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+		if cmd.channel == releaseChannelStable && strings.Contains(release.TagName, "-") {
+			continue
+		}
+		return release, nil
+	}
+	return githubRelease{}, ErrNoRelease(cmd.channel)
+}
+
+// download downloads the archive of the release for this platform, verifies it and returns the binary in it.
+func (cmd *SelfUpdateCommand) download(client *http.Client, release githubRelease) ([]byte, error) {
+	checksumsName := "secrethub-" + release.TagName + "-checksums.txt"
+	checksumsURL, err := release.asset(checksumsName)
+	if err != nil {
+		return nil, err
+	}
+	checksums, err := downloadReleaseFile(client, checksumsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cmd.insecureSkipSignature {
+		signatureURL, err := release.asset(checksumsName + ".sig")
+		if err != nil {
+			return nil, err
+		}
+		signature, err := downloadReleaseFile(client, signatureURL)
+		if err != nil {
+			return nil, err
+		}
+		err = verifyReleaseSignature(cmd.signingKey, releaseSignaturePayload(release.TagName, checksums), signature)
+		if err != nil {
+			return nil, ErrInvalidReleaseSignature(release.TagName)
+		}
+	} else {
+		logger.Warningf("the signature of release %s is not verified, because --insecure-skip-signature is set", release.TagName)
+	}
+
+	archiveName := "secrethub-" + release.TagName + "-" + cmd.platform + ".tar.gz"
+	if strings.HasPrefix(cmd.platform, "windows-") {
+		archiveName = "secrethub-" + release.TagName + "-" + cmd.platform + ".zip"
+	}
+	expected, err := findReleaseChecksum(checksums, archiveName)
+	if err != nil {
+		return nil, ErrReleaseAssetNotFound(release.TagName, archiveName)
+	}
+
+	archiveURL, err := release.asset(archiveName)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := downloadReleaseFile(client, archiveURL)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(archive)
+	if hex.EncodeToString(sum[:]) != expected {
+		return nil, ErrReleaseChecksumMismatch(archiveName)
+	}
+
+	return extractReleaseBinary(archiveName, archive)
+}
+
+// downloadReleaseFile downloads the file at the given URL.
+func downloadReleaseFile(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, ErrReleaseDownloadFailed(url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrReleaseDownloadFailed(url, resp.Status)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxReleaseDownloadSize))
+	if err != nil {
+		return nil, ErrReleaseDownloadFailed(url, err)
+	}
+	return raw, nil
+}
+
+// releaseSignaturePayload returns the data that is signed for a release: the tag of the release followed by
+// its checksums file. The tag is part of the signature, so that the signed checksums of an older release
+// cannot be served as a newer release. scripts/sign-release.sh creates the signature when a release is made.
+func releaseSignaturePayload(tag string, checksums []byte) []byte {
+	payload := []byte("secrethub-cli " + tag + "\n")
+	return append(payload, checksums...)
+}
+
+// verifyReleaseSignature verifies the base64 encoded Ed25519 signature of the payload.
+func verifyReleaseSignature(key string, payload []byte, signature []byte) error {
+	publicKey, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid release signing key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, payload, sig) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// findReleaseChecksum returns the SHA-256 checksum of the file with the given name from a
+// checksums file, which has a line with the hex encoded checksum and the name for every file.
+func findReleaseChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum for %s", name)
+}
+
+// extractReleaseBinary returns the contents of the secrethub binary in a .tar.gz or .zip archive.
+func extractReleaseBinary(name string, archive []byte) ([]byte, error) {
+	if strings.HasSuffix(name, ".zip") {
+		r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range r.File {
+			if path.Base(file.Name) != "secrethub.exe" {
+				continue
+			}
+			f, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			return io.ReadAll(io.LimitReader(f, maxReleaseDownloadSize))
+		}
+		return nil, ErrReleaseBinaryNotFound(name)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	r := tar.NewReader(gz)
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			return nil, ErrReleaseBinaryNotFound(name)
+		} else if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && path.Base(header.Name) == "secrethub" {
+			return io.ReadAll(io.LimitReader(r, maxReleaseDownloadSize))
+		}
+	}
+}
+
+// replaceExecutable atomically replaces the executable with the given binary, keeping its file mode.
+// The new binary is written next to the executable and renamed over it, so that the executable
+// is never partially written. Windows does not allow replacing a running executable, so there
+// the executable is first moved aside.
+func replaceExecutable(executable string, binary []byte) error {
+	info, err := os.Stat(executable)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(executable)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(executable)+".new-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	_, err = tmp.Write(binary)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmpPath, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		oldPath := executable + ".old"
+		_ = os.Remove(oldPath)
+		err = os.Rename(executable, oldPath)
+		if err != nil {
+			return err
+		}
+		err = os.Rename(tmpPath, executable)
+		if err != nil {
+			_ = os.Rename(oldPath, executable)
+			return err
+		}
+		return nil
+	}
+	return os.Rename(tmpPath, executable)
+}
+
+// releasePlatform returns the platform of the running binary as used in the names of release archives, e.g. linux-arm64 or linux-armv7.
+func releasePlatform() string {
+	platform := runtime.GOOS + "-" + runtime.GOARCH
+	if runtime.GOARCH == "arm" {
+		goarm := "7"
+		if info, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range info.Settings {
+				if setting.Key == "GOARM" && setting.Value != "" {
+					goarm = setting.Value
+				}
+			}
+		}
+		platform += "v" + goarm
+	}
+	return platform
+}
+
+// compareVersions compares two semantic versions without a v prefix, e.g. 0.42.0 and 0.43.0-beta.1, by
+// their precedence. It returns -1, 0 or 1 when a is older than, the same as or newer than b, and whether
+// both versions could be parsed.
+func compareVersions(a string, b string) (int, bool) {
+	coreA, preA, ok := parseVersion(a)
+	if !ok {
+		return 0, false
+	}
+	coreB, preB, ok := parseVersion(b)
+	if !ok {
+		return 0, false
+	}
+
+	for i := range coreA {
+		if coreA[i] != coreB[i] {
+			return compareInts(coreA[i], coreB[i]), true
+		}
+	}
+
+	// A pre-release is older than the release it precedes.
+	switch {
+	case len(preA) == 0 && len(preB) == 0:
+		return 0, true
+	case len(preA) == 0:
+		return 1, true
+	case len(preB) == 0:
+		return -1, true
+	}
+
+	for i := 0; i < len(preA) && i < len(preB); i++ {
+		if preA[i] == preB[i] {
+			continue
+		}
+		numA, errA := strconv.Atoi(preA[i])
+		numB, errB := strconv.Atoi(preB[i])
+		switch {
+		case errA == nil && errB == nil:
+			return compareInts(numA, numB), true
+		case errA == nil:
+			// Numeric identifiers are older than alphanumeric identifiers.
+			return -1, true
+		case errB == nil:
+			return 1, true
+		case preA[i] < preB[i]:
+			return -1, true
+		default:
+			return 1, true
+		}
+	}
+	return compareInts(len(preA), len(preB)), true
+}
+
+// parseVersion splits a semantic version into its major, minor and patch numbers and its pre-release identifiers.
+func parseVersion(version string) ([3]int, []string, bool) {
+	var core [3]int
+
+	version = strings.SplitN(version, "+", 2)[0]
+	parts := strings.SplitN(version, "-", 2)
+	numbers := strings.Split(parts[0], ".")
+	if len(numbers) != 3 {
+		return core, nil, false
+	}
+	for i, number := range numbers {
+		n, err := strconv.Atoi(number)
+		if err != nil || n < 0 {
+			return core, nil, false
+		}
+		core[i] = n
+	}
+
+	var pre []string
+	if len(parts) == 2 {
+		pre = strings.Split(parts[1], ".")
+	}
+	return core, pre, true
+}
+
+func compareInts(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// currentVersionName returns the version of the running binary, or a description when it is a development build.
+func currentVersionName(version string) string {
+	if version == "" {
+		return "development build"
+	}
+	return version
+}
@@ -0,0 +1,301 @@
+package secrethub
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func testReleaseArchive(t *testing.T, binary []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string][]byte{"LICENSE": []byte("license"), "bin/secrethub": binary} {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		assert.OK(t, err)
+		_, err = tw.Write(content)
+		assert.OK(t, err)
+	}
+	assert.OK(t, tw.Close())
+	assert.OK(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestSelfUpdateCommand_Run(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.OK(t, err)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.OK(t, err)
+
+	archive := testReleaseArchive(t, []byte("new binary"))
+	sum := sha256.Sum256(archive)
+
+	cases := map[string]struct {
+		channel        string
+		version        string
+		yes            bool
+		allowDowngrade bool
+		promptErr      error
+		signingKey     ed25519.PrivateKey
+		signedTag      string
+		corruptArchive bool
+		expectedBinary string
+		expectedOut    string
+		expectedErr    error
+	}{
+		"update": {
+			channel:        releaseChannelStable,
+			version:        "v0.41.0",
+			yes:            true,
+			signingKey:     privateKey,
+			expectedBinary: "new binary",
+			expectedOut:    "The CLI has been updated to 0.42.0.\n",
+		},
+		"development build": {
+			channel:        releaseChannelStable,
+			yes:            true,
+			signingKey:     privateKey,
+			expectedBinary: "new binary",
+			expectedOut:    "The CLI has been updated to 0.42.0.\n",
+		},
+		"downgrade": {
+			channel:     releaseChannelStable,
+			version:     "v0.43.0-beta.1",
+			signingKey:  privateKey,
+			expectedErr: ErrReleaseDowngrade(releaseChannelStable, "0.42.0", "0.43.0-beta.1"),
+		},
+		"allow downgrade": {
+			channel:        releaseChannelStable,
+			version:        "v0.43.0-beta.1",
+			yes:            true,
+			allowDowngrade: true,
+			signingKey:     privateKey,
+			expectedBinary: "new binary",
+			expectedOut:    "The CLI has been updated to 0.42.0.\n",
+		},
+		"reinstall without allow downgrade": {
+			channel:     releaseChannelStable,
+			version:     "v0.42.0",
+			yes:         true,
+			signingKey:  privateKey,
+			expectedOut: "The CLI is up to date (0.42.0).\n",
+		},
+		"cannot ask for confirmation": {
+			channel:     releaseChannelStable,
+			version:     "v0.41.0",
+			signingKey:  privateKey,
+			promptErr:   ui.ErrCannotAsk,
+			expectedErr: ErrCannotUpdateWithoutConfirmation,
+		},
+		"beta channel": {
+			channel:     releaseChannelBeta,
+			version:     "v0.43.0-beta.1",
+			signingKey:  privateKey,
+			expectedOut: "The CLI is up to date (0.43.0-beta.1).\n",
+		},
+		"up to date": {
+			channel:     releaseChannelStable,
+			version:     "v0.42.0",
+			signingKey:  privateKey,
+			expectedOut: "The CLI is up to date (0.42.0).\n",
+		},
+		"invalid signature": {
+			channel:     releaseChannelStable,
+			version:     "v0.41.0",
+			yes:         true,
+			signingKey:  otherKey,
+			expectedErr: ErrInvalidReleaseSignature("v0.42.0"),
+		},
+		"signature of another release": {
+			channel:     releaseChannelStable,
+			version:     "v0.41.0",
+			yes:         true,
+			signingKey:  privateKey,
+			signedTag:   "v0.41.0",
+			expectedErr: ErrInvalidReleaseSignature("v0.42.0"),
+		},
+		"checksum mismatch": {
+			channel:        releaseChannelStable,
+			version:        "v0.41.0",
+			yes:            true,
+			signingKey:     privateKey,
+			corruptArchive: true,
+			expectedErr:    ErrReleaseChecksumMismatch("secrethub-v0.42.0-linux-amd64.tar.gz"),
+		},
+		"unknown channel": {
+			channel:     "nightly",
+			expectedErr: ErrUnknownReleaseChannel("nightly"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			checksums := []byte(hex.EncodeToString(sum[:]) + "  secrethub-v0.42.0-linux-amd64.tar.gz\n")
+			signedTag := tc.signedTag
+			if signedTag == "" {
+				signedTag = "v0.42.0"
+			}
+			var signature []byte
+			if tc.signingKey != nil {
+				signature = []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(tc.signingKey, releaseSignaturePayload(signedTag, checksums))))
+			}
+			served := archive
+			if tc.corruptArchive {
+				served = append([]byte{}, archive...)
+				served[len(served)-1]++
+			}
+
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				asset := func(name string) githubReleaseAsset {
+					return githubReleaseAsset{Name: name, URL: server.URL + "/" + name}
+				}
+				switch r.URL.Path {
+				case "/releases":
+					_ = json.NewEncoder(w).Encode([]githubRelease{
+						{TagName: "v0.44.0", Draft: true},
+						{TagName: "v0.43.0-beta.1"},
+						{TagName: "v0.42.0", Assets: []githubReleaseAsset{
+							asset("secrethub-v0.42.0-checksums.txt"),
+							asset("secrethub-v0.42.0-checksums.txt.sig"),
+							asset("secrethub-v0.42.0-linux-amd64.tar.gz"),
+						}},
+					})
+				case "/secrethub-v0.42.0-checksums.txt":
+					_, _ = w.Write(checksums)
+				case "/secrethub-v0.42.0-checksums.txt.sig":
+					_, _ = w.Write(signature)
+				case "/secrethub-v0.42.0-linux-amd64.tar.gz":
+					_, _ = w.Write(served)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+			executable := filepath.Join(dir, "secrethub")
+			assert.OK(t, os.WriteFile(executable, []byte("old binary"), 0755))
+
+			io := fakeui.NewIO(t)
+			io.PromptErr = tc.promptErr
+			cmd := SelfUpdateCommand{
+				io:             io,
+				clientFactory:  doctorClientFactory{},
+				channel:        tc.channel,
+				yes:            tc.yes,
+				allowDowngrade: tc.allowDowngrade,
+				releasesURL:    server.URL + "/releases",
+				version:        tc.version,
+				signingKey:     base64.StdEncoding.EncodeToString(publicKey),
+				platform:       "linux-amd64",
+				executable: func() (string, error) {
+					return executable, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+
+			expectedBinary := tc.expectedBinary
+			if expectedBinary == "" {
+				expectedBinary = "old binary"
+			}
+			actual, err := os.ReadFile(executable)
+			assert.OK(t, err)
+			assert.Equal(t, string(actual), expectedBinary)
+
+			info, err := os.Stat(executable)
+			assert.OK(t, err)
+			assert.Equal(t, info.Mode().Perm(), os.FileMode(0755))
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := map[string]struct {
+		a             string
+		b             string
+		expected      int
+		expectedKnown bool
+	}{
+		"older": {
+			a:             "0.41.0",
+			b:             "0.42.0",
+			expected:      -1,
+			expectedKnown: true,
+		},
+		"same": {
+			a:             "0.42.0",
+			b:             "0.42.0",
+			expected:      0,
+			expectedKnown: true,
+		},
+		"newer minor": {
+			a:             "0.43.0-beta.1",
+			b:             "0.42.0",
+			expected:      1,
+			expectedKnown: true,
+		},
+		"release is newer than pre-release": {
+			a:             "0.42.0",
+			b:             "0.42.0-beta.1",
+			expected:      1,
+			expectedKnown: true,
+		},
+		"numeric pre-release identifiers": {
+			a:             "0.42.0-beta.2",
+			b:             "0.42.0-beta.11",
+			expected:      -1,
+			expectedKnown: true,
+		},
+		"alphanumeric pre-release identifiers": {
+			a:             "0.42.0-rc.1",
+			b:             "0.42.0-beta.11",
+			expected:      1,
+			expectedKnown: true,
+		},
+		"build metadata": {
+			a:             "0.42.0+linux",
+			b:             "0.42.0",
+			expected:      0,
+			expectedKnown: true,
+		},
+		"development build": {
+			a: "",
+			b: "0.42.0",
+		},
+		"commit": {
+			a: "3f2a1bc",
+			b: "0.42.0",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, known := compareVersions(tc.a, tc.b)
+
+			assert.Equal(t, actual, tc.expected)
+			assert.Equal(t, known, tc.expectedKnown)
+		})
+	}
+}
@@ -7,24 +7,26 @@ import (
 
 // ServiceCommand handles operations on services.
 type ServiceCommand struct {
-	io        ui.IO
-	newClient newClientFunc
+	io            ui.IO
+	clientFactory ClientFactory
 }
 
 // NewServiceCommand creates a new ServiceCommand.
-func NewServiceCommand(io ui.IO, newClient newClientFunc) *ServiceCommand {
+func NewServiceCommand(io ui.IO, clientFactory ClientFactory) *ServiceCommand {
 	return &ServiceCommand{
-		io:        io,
-		newClient: newClient,
+		io:            io,
+		clientFactory: clientFactory,
 	}
 }
 
 // Register registers the command and its sub-commands on the provided Registerer.
 func (cmd *ServiceCommand) Register(r cli.Registerer) {
 	clause := r.Command("service", "Manage service accounts.")
-	NewServiceAWSCommand(cmd.io, cmd.newClient).Register(clause)
-	NewServiceGCPCommand(cmd.io, cmd.newClient).Register(clause)
+	NewServiceAWSCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
+	NewServiceGCPCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
 	NewServiceDeployCommand(cmd.io).Register(clause)
-	NewServiceInitCommand(cmd.io, cmd.newClient).Register(clause)
-	NewServiceLsCommand(cmd.io, cmd.newClient).Register(clause)
+	NewServiceInitCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
+	NewServiceInspectCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
+	NewServiceLsCommand(cmd.io, cmd.clientFactory.NewClient).Register(clause)
+	NewServiceRotateCommand(cmd.io, cmd.clientFactory.NewClient, cmd.clientFactory.NewClientWithCredentials).Register(clause)
 }
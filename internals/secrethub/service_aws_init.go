@@ -148,7 +148,7 @@ func (cmd *ServiceAWSInitCommand) Run() error {
 		}
 	}
 
-	fmt.Fprintln(cmd.io.Output(), "Successfully created a new service account with ID: "+service.ServiceID)
+	ui.Successf(cmd.io.Output(), "Successfully created a new service account with ID: %s\n", service.ServiceID)
 	fmt.Fprintf(cmd.io.Output(), "Any host that assumes the IAM role %s can now automatically authenticate to SecretHub and fetch the secrets the service has been given access to.\n", roleNameFromRole(cmd.role))
 
 	return nil
@@ -199,7 +199,7 @@ func (cmd *ServiceDeployWinRmCommand) Run() error {
 		return err
 	}
 
-	fmt.Fprintln(cmd.io.Output(), "Deploy complete! The service account can now be used to connect to SecretHub from the host.")
+	ui.Successf(cmd.io.Output(), "Deploy complete! The service account can now be used to connect to SecretHub from the host.\n")
 
 	return nil
 }
@@ -207,7 +207,7 @@ func (cmd *ServiceDeployWinRmCommand) Run() error {
 // checkWinRMTLS checks if the given schema corresponds to the given CLI flags.
 func (cmd *ServiceDeployWinRmCommand) checkWinRMTLS() (bool, error) {
 	if cmd.resourceURI.Scheme == "http" {
-		fmt.Fprintln(cmd.io.Output(), "WARNING: insecure no tls flag is set! We recommend to always use TLS.")
+		ui.Warningf(cmd.io.Output(), "insecure no tls flag is set! We recommend to always use TLS.\n")
 		return false, nil
 	}
 
@@ -225,7 +225,7 @@ func (cmd *ServiceDeployWinRmCommand) checkWinRMTLS() (bool, error) {
 // checkWinRMVerifyCert checks if the given schema corresponds to the given CLI flags.
 func (cmd *ServiceDeployWinRmCommand) checkWinRMVerifyCert() bool {
 	if cmd.noVerify {
-		fmt.Fprintln(cmd.io.Output(), "WARNING: insecure no verify cert flag is set! We recommend to always verify the certificate.")
+		ui.Warningf(cmd.io.Output(), "insecure no verify cert flag is set! We recommend to always verify the certificate.\n")
 		return true
 	}
 
@@ -149,7 +149,7 @@ func (cmd *ServiceGCPInitCommand) Run() error {
 		}
 	}
 
-	fmt.Fprintln(cmd.io.Stdout(), "Successfully created a new service account with ID: "+service.ServiceID)
+	ui.Successf(cmd.io.Stdout(), "Successfully created a new service account with ID: %s\n", service.ServiceID)
 	fmt.Fprintf(cmd.io.Stdout(), "Any host using the Service Account %s can now automatically authenticate to SecretHub and fetch the secrets the service has been given access to.\n", cmd.serviceAccountEmail)
 
 	return nil
@@ -150,7 +150,7 @@ func givePermission(service *api.Service, repo api.RepoPath, permissionFlagValue
 		if err != nil {
 			_, delErr := client.Services().Delete(service.ServiceID)
 			if delErr != nil {
-				fmt.Fprintf(os.Stderr, "Failed to cleanup after creating an access rule for %s failed. Be sure to manually remove the created service account %s: %s\n", service.ServiceID, service.ServiceID, err)
+				logger.Errorf("Failed to cleanup after creating an access rule for %s failed. Be sure to manually remove the created service account %s: %s", service.ServiceID, service.ServiceID, err)
 				return delErr
 			}
 
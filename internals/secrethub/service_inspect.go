@@ -0,0 +1,183 @@
+package secrethub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
+)
+
+// ServiceInspectCommand handles printing out the details of a service account in a JSON format.
+type ServiceInspectCommand struct {
+	serviceID     cli.StringValue
+	io            ui.IO
+	newClient     newClientFunc
+	timeFormatter TimeFormatter
+}
+
+// NewServiceInspectCommand creates a new ServiceInspectCommand.
+func NewServiceInspectCommand(io ui.IO, newClient newClientFunc) *ServiceInspectCommand {
+	return &ServiceInspectCommand{
+		io:            io,
+		newClient:     newClient,
+		timeFormatter: NewTimestampFormatter(),
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ServiceInspectCommand) Register(r cli.Registerer) {
+	clause := r.Command("inspect", "Show the details of a service account, including its access rules and when it was last used.")
+	clause.HelpLong("The creator of the service account and the time it was last used are read from the audit log of its repository. " +
+		"They are left out when you cannot read the audit log, because you are not an admin of the repository.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.serviceID, Name: "service-id", Required: true, Description: "The ID of the service account to inspect."},
+	})
+}
+
+// Run prints out the details of a service account.
+func (cmd *ServiceInspectCommand) Run() error {
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	service, err := client.Services().Get(cmd.serviceID.Value)
+	if err != nil {
+		return err
+	}
+	repo := service.Repo.Path()
+
+	rules, err := client.AccessRules().List(repo.Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	tree, err := client.Dirs().GetTree(repo.Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	activity, err := serviceActivity(client, repo, service.ServiceID)
+	if err != nil {
+		return err
+	}
+
+	out, err := newServiceInspectOutput(service, rules, tree, activity, cmd.timeFormatter)
+	if err != nil {
+		return err
+	}
+
+	output, err := cli.PrettyJSON(out)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.io.Output(), output)
+
+	return nil
+}
+
+// serviceActivityRecord contains the details of a service account that are read from the audit log.
+type serviceActivityRecord struct {
+	// createdBy is the name of the account that created the service account, or empty when unknown.
+	createdBy string
+	// lastUsed is the newest event performed by the service account, or nil when there is none.
+	lastUsed *api.Audit
+}
+
+// serviceActivity reads the creator of the service account and the last time it was used from the
+// audit log of its repo. When the audit log cannot be read, an empty record is returned.
+func serviceActivity(client secrethub.ClientInterface, repo api.RepoPath, serviceID string) (serviceActivityRecord, error) {
+	var record serviceActivityRecord
+
+	iter := client.Repos().EventIterator(repo.Value(), &secrethub.AuditEventIteratorParams{})
+	for started := false; record.createdBy == "" || record.lastUsed == nil; started = true {
+		event, err := iter.Next()
+		if err == iterator.Done || (err == api.ErrForbidden && !started) {
+			break
+		} else if err != nil {
+			return serviceActivityRecord{}, err
+		}
+
+		// The events are returned from newest to oldest.
+		if record.lastUsed == nil && !event.Actor.Deleted && event.Actor.Type == accountTypeService &&
+			strings.EqualFold(event.Actor.Service.ServiceID, serviceID) {
+			record.lastUsed = &event
+		}
+
+		if event.Action == "create" && !event.Subject.Deleted && event.Subject.Type == api.AuditSubjectService &&
+			strings.EqualFold(event.Subject.Service.ServiceID, serviceID) {
+			record.createdBy, err = getAuditActor(event)
+			if err != nil {
+				return serviceActivityRecord{}, err
+			}
+		}
+	}
+	return record, nil
+}
+
+// ServiceInspectOutput is the json format to print out with all the details of a service account.
+type ServiceInspectOutput struct {
+	ServiceID      string
+	Description    string
+	Repo           api.RepoPath
+	CredentialType string
+	CreatedAt      string
+	CreatedBy      string `json:",omitempty"`
+	LastUsedAt     string `json:",omitempty"`
+	AccessRules    []ServiceAccessRuleOutput
+}
+
+// ServiceAccessRuleOutput is the json format to print out an access rule of a service account.
+type ServiceAccessRuleOutput struct {
+	Path       string
+	Permission string
+}
+
+func newServiceInspectOutput(service *api.Service, rules []*api.AccessRule, tree *api.Tree, activity serviceActivityRecord, timeFormatter TimeFormatter) (ServiceInspectOutput, error) {
+	out := ServiceInspectOutput{
+		ServiceID:   service.ServiceID,
+		Description: service.Description,
+		Repo:        service.Repo.Path(),
+		CreatedAt:   timeFormatter.Format(service.CreatedAt.Local()),
+		CreatedBy:   activity.createdBy,
+		AccessRules: []ServiceAccessRuleOutput{},
+	}
+
+	if service.Credential != nil {
+		out.CredentialType = string(service.Credential.Type)
+	}
+
+	if activity.lastUsed != nil {
+		out.LastUsedAt = timeFormatter.Format(activity.lastUsed.LoggedAt.Local())
+	}
+
+	for _, rule := range rules {
+		if !strings.EqualFold(rule.Account.Name.String(), service.ServiceID) {
+			continue
+		}
+
+		dirPath, err := tree.AbsDirPath(rule.DirID)
+		if err != nil {
+			return ServiceInspectOutput{}, err
+		}
+
+		out.AccessRules = append(out.AccessRules, ServiceAccessRuleOutput{
+			Path:       dirPath.String(),
+			Permission: rule.Permission.String(),
+		})
+	}
+	sort.Slice(out.AccessRules, func(i, j int) bool {
+		return out.AccessRules[i].Path < out.AccessRules[j].Path
+	})
+
+	return out, nil
+}
@@ -0,0 +1,152 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/fakes"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestServiceInspectCommand_Run(t *testing.T) {
+	repoID := uuid.New()
+	dirID := uuid.New()
+	tree := &api.Tree{
+		ParentPath: "company",
+		RootDir: &api.Dir{
+			Name:  "repo",
+			DirID: repoID,
+		},
+		Dirs: map[uuid.UUID]*api.Dir{
+			repoID: {
+				Name:  "repo",
+				DirID: repoID,
+			},
+			dirID: {
+				Name:     "dir",
+				DirID:    dirID,
+				ParentID: &repoID,
+			},
+		},
+	}
+	service := &api.Service{
+		ServiceID:   "s-abcdef",
+		Description: "deploy service",
+		Repo:        &api.Repo{Owner: "company", Name: "repo"},
+		CreatedAt:   time.Date(2018, 1, 1, 1, 1, 1, 1, time.UTC),
+		Credential:  &api.Credential{Type: api.CredentialTypeKey},
+	}
+	rules := []*api.AccessRule{
+		{DirID: repoID, Account: &api.Account{Name: "dev"}, Permission: api.PermissionAdmin},
+		{DirID: dirID, Account: &api.Account{Name: "s-abcdef"}, Permission: api.PermissionRead},
+	}
+	serviceActor := api.AuditActor{
+		Type:    "service",
+		Service: &api.Service{ServiceID: "s-abcdef"},
+	}
+
+	cases := map[string]struct {
+		events []api.Audit
+		out    string
+	}{
+		"success": {
+			events: []api.Audit{
+				{Action: "read", Actor: serviceActor, LoggedAt: time.Date(2018, 3, 1, 1, 1, 1, 1, time.UTC)},
+				{Action: "read", Actor: serviceActor, LoggedAt: time.Date(2018, 2, 1, 1, 1, 1, 1, time.UTC)},
+				{
+					Action: "create",
+					Actor: api.AuditActor{
+						Type: "user",
+						User: &api.User{Username: "dev"},
+					},
+					Subject: api.AuditSubject{
+						Type:    api.AuditSubjectService,
+						Service: &api.Service{ServiceID: "s-abcdef"},
+					},
+				},
+			},
+			out: "{\n" +
+				"    \"ServiceID\": \"s-abcdef\",\n" +
+				"    \"Description\": \"deploy service\",\n" +
+				"    \"Repo\": \"company/repo\",\n" +
+				"    \"CredentialType\": \"key\",\n" +
+				"    \"CreatedAt\": \"2018-01-01T01:01:01+00:00\",\n" +
+				"    \"CreatedBy\": \"dev\",\n" +
+				"    \"LastUsedAt\": \"2018-01-01T01:01:01+00:00\",\n" +
+				"    \"AccessRules\": [\n" +
+				"        {\n" +
+				"            \"Path\": \"company/repo/dir\",\n" +
+				"            \"Permission\": \"read\"\n" +
+				"        }\n" +
+				"    ]\n" +
+				"}\n",
+		},
+		"never used": {
+			out: "{\n" +
+				"    \"ServiceID\": \"s-abcdef\",\n" +
+				"    \"Description\": \"deploy service\",\n" +
+				"    \"Repo\": \"company/repo\",\n" +
+				"    \"CredentialType\": \"key\",\n" +
+				"    \"CreatedAt\": \"2018-01-01T01:01:01+00:00\",\n" +
+				"    \"AccessRules\": [\n" +
+				"        {\n" +
+				"            \"Path\": \"company/repo/dir\",\n" +
+				"            \"Permission\": \"read\"\n" +
+				"        }\n" +
+				"    ]\n" +
+				"}\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			io := fakeui.NewIO(t)
+			cmd := ServiceInspectCommand{
+				serviceID: cli.StringValue{Value: "s-abcdef"},
+				io:        io,
+				timeFormatter: &fakes.TimeFormatter{
+					Response: "2018-01-01T01:01:01+00:00",
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						ServiceService: &fakeclient.ServiceService{
+							GetFunc: func(id string) (*api.Service, error) {
+								return service, nil
+							},
+						},
+						AccessRuleService: &fakeclient.AccessRuleService{
+							ListFunc: func(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+								return rules, nil
+							},
+						},
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								return tree, nil
+							},
+						},
+						RepoService: &fakeclient.RepoService{
+							AuditEventIterator: &fakeclient.AuditEventIterator{
+								Events: tc.events,
+							},
+						},
+					}, nil
+				},
+			}
+
+			// Run
+			err := cmd.Run()
+
+			// Assert
+			assert.Equal(t, err, nil)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
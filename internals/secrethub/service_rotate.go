@@ -0,0 +1,252 @@
+package secrethub
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/filemode"
+	"github.com/secrethub/secrethub-cli/internals/cli/posix"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
+)
+
+// Errors
+var (
+	ErrNoServiceCredentialSource  = errMain.Code("no_service_credential_source").Error("either --secret or --credential-file must be set to read the current credential of the service account")
+	ErrServiceCredentialNotKey    = errMain.Code("service_credential_not_key").ErrorPref("the credential of %s cannot be rotated: only key credentials can be rotated, not %s credentials")
+	ErrCredentialNotOfService     = errMain.Code("credential_not_of_service").ErrorPref("the given credential does not belong to %s")
+	ErrInvalidGracePeriod         = errMain.Code("invalid_grace_period").ErrorPref("invalid value for --grace-period: %s: must not be negative")
+	ErrServiceRotationInterrupted = errMain.Code("service_rotation_interrupted").ErrorPref("interrupted before the old credential was disabled: disable it with `secrethub credential disable %s` using the old credential")
+)
+
+// ServiceRotateCommand creates a new credential for a service account and disables the old one.
+type ServiceRotateCommand struct {
+	serviceID        cli.StringValue
+	secretPath       string
+	credentialFile   string
+	outFile          string
+	fileMode         filemode.FileMode
+	description      string
+	gracePeriod      time.Duration
+	force            bool
+	io               ui.IO
+	newClient        newClientFunc
+	newServiceClient func(credentials.Provider) (secrethub.ClientInterface, error)
+	newCredential    func() *credentials.KeyCreator
+	sleep            func(ctx context.Context, d time.Duration) error
+}
+
+// NewServiceRotateCommand creates a new ServiceRotateCommand.
+func NewServiceRotateCommand(io ui.IO, newClient newClientFunc, newServiceClient func(credentials.Provider) (secrethub.ClientInterface, error)) *ServiceRotateCommand {
+	return &ServiceRotateCommand{
+		io:               io,
+		newClient:        newClient,
+		newServiceClient: newServiceClient,
+		newCredential:    credentials.CreateKey,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			select {
+			case <-time.After(d):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *ServiceRotateCommand) Register(r cli.Registerer) {
+	clause := r.Command("rotate", "Create a new credential for a service account and disable its current credential.")
+	clause.HelpLong("The current credential of the service account is read from the secret given with --secret or the file given with --credential-file. " +
+		"It is used to create a new credential for the service account, after which it is disabled.\n\n" +
+		"With --secret, the new credential is written to the same secret. " +
+		"Otherwise, it is written to the file given with --out-file or to stdout.\n\n" +
+		"Use --grace-period to keep the old credential enabled for a while, so that running deployments can switch to the new credential first. " +
+		"The command keeps running until the grace period has passed.")
+	clause.Flags().StringVar(&cmd.secretPath, "secret", "", "The path of the secret that contains the current credential and to which the new credential is written.")
+	clause.Flags().StringVar(&cmd.credentialFile, "credential-file", "", "The file that contains the current credential.")
+	clause.Flags().StringVar(&cmd.outFile, "out-file", "", "Write the new credential to a file instead of stdout. It cannot be used together with --secret.")
+	cmd.fileMode = filemode.New(0440)
+	clause.Flags().Var(&cmd.fileMode, "file-mode", "Set filemode for the written file. It is ignored without the --out-file flag.")
+	clause.Flags().StringVar(&cmd.description, "description", "", "A description for the new credential.")
+	clause.Flags().DurationVar(&cmd.gracePeriod, "grace-period", 0, "How long to wait before disabling the old credential, e.g. 1h.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.serviceID, Name: "service-id", Required: true, Description: "The ID of the service account to rotate the credential of."},
+	})
+}
+
+// Run rotates the credential.
+func (cmd *ServiceRotateCommand) Run() error {
+	if cmd.secretPath == "" && cmd.credentialFile == "" {
+		return ErrNoServiceCredentialSource
+	}
+	if cmd.secretPath != "" && cmd.credentialFile != "" {
+		return ErrFlagsConflict("--secret and --credential-file")
+	}
+	if cmd.secretPath != "" && cmd.outFile != "" {
+		return ErrFlagsConflict("--secret and --out-file")
+	}
+	if cmd.gracePeriod < 0 {
+		return ErrInvalidGracePeriod(cmd.gracePeriod)
+	}
+	if cmd.secretPath != "" {
+		err := api.ValidateSecretPath(cmd.secretPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// When the new credential is printed, it is the only output,
+	// so that it can be piped to another command.
+	out := cmd.io.Output()
+	if cmd.secretPath == "" && cmd.outFile == "" {
+		out = io.Discard
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	service, err := client.Services().Get(cmd.serviceID.Value)
+	if err != nil {
+		return err
+	}
+	if service.Credential != nil && service.Credential.Type != api.CredentialTypeKey {
+		return ErrServiceCredentialNotKey(service.ServiceID, service.Credential.Type)
+	}
+
+	oldCredential, err := cmd.readCredential(client)
+	if err != nil {
+		return err
+	}
+
+	oldKey, err := credentials.ImportKey(credentials.FromString(oldCredential), nil)
+	if err != nil {
+		return err
+	}
+	_, oldFingerprint, err := oldKey.Verifier().Export()
+	if err != nil {
+		return err
+	}
+
+	serviceClient, err := cmd.newServiceClient(credentials.UseKey(credentials.FromString(oldCredential)))
+	if err != nil {
+		return err
+	}
+
+	// The credentials are listed with the old credential to check that it is valid
+	// and belongs to the service account, before a new credential is created.
+	cred, err := serviceClient.Credentials().List(&secrethub.CredentialListParams{}).Next()
+	if err == iterator.Done || (err == nil && cred.AccountID != service.AccountID) {
+		return ErrCredentialNotOfService(service.ServiceID)
+	} else if err != nil {
+		return err
+	}
+
+	if !cmd.force {
+		msg := fmt.Sprintf("This will create a new credential for %s and disable the credential with fingerprint %s", service.ServiceID, oldFingerprint[:16])
+		if cmd.gracePeriod > 0 {
+			msg += fmt.Sprintf(" after %s", cmd.gracePeriod)
+		}
+		confirmed, err := ui.AskYesNo(cmd.io, msg+". Do you want to continue?", ui.DefaultNo)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return nil
+		}
+	}
+
+	newKey := cmd.newCredential()
+	_, err = serviceClient.Credentials().Create(newKey, cmd.description)
+	if err != nil {
+		return err
+	}
+
+	exported, err := newKey.Export()
+	if err != nil {
+		return err
+	}
+
+	err = cmd.writeCredential(client, exported)
+	if err != nil {
+		// The old credential is still enabled, but the new credential
+		// would be lost if it is not printed.
+		fmt.Fprintf(cmd.io.Output(), "Could not store the new credential, so it is printed instead:\n%s", posix.AddNewLine(exported))
+		return err
+	}
+
+	if cmd.gracePeriod > 0 {
+		fmt.Fprintf(out, "Waiting %s before disabling the old credential...\n", cmd.gracePeriod)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		err = cmd.sleep(ctx, cmd.gracePeriod)
+		if err != nil {
+			return ErrServiceRotationInterrupted(oldFingerprint[:16])
+		}
+	}
+
+	err = serviceClient.Credentials().Disable(oldFingerprint)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Rotated the credential of %s and disabled the old credential.\n", service.ServiceID)
+	return nil
+}
+
+// readCredential returns the current credential of the service account.
+func (cmd *ServiceRotateCommand) readCredential(client secrethub.ClientInterface) (string, error) {
+	if cmd.secretPath != "" {
+		version, err := client.Secrets().Versions().GetWithData(cmd.secretPath)
+		if err != nil {
+			return "", err
+		}
+		return string(bytes.TrimSpace(version.Data)), nil
+	}
+
+	raw, err := os.ReadFile(cmd.credentialFile)
+	if err != nil {
+		return "", ErrCannotReadFile(cmd.credentialFile, err)
+	}
+	return string(bytes.TrimSpace(raw)), nil
+}
+
+// writeCredential writes the new credential to the secret, the output file or stdout.
+func (cmd *ServiceRotateCommand) writeCredential(client secrethub.ClientInterface, credential []byte) error {
+	switch {
+	case cmd.secretPath != "":
+		_, err := client.Secrets().Write(cmd.secretPath, credential)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Written the new credential to %s.\n", cmd.secretPath)
+	case cmd.outFile != "":
+		err := os.WriteFile(cmd.outFile, posix.AddNewLine(credential), cmd.fileMode.FileMode())
+		if err != nil {
+			return ErrCannotWrite(cmd.outFile, err)
+		}
+		fmt.Fprintf(cmd.io.Output(), "Written the new credential to %s. Be sure to remove it when you're done.\n", cmd.outFile)
+	default:
+		fmt.Fprintf(cmd.io.Output(), "%s", posix.AddNewLine(credential))
+	}
+	return nil
+}
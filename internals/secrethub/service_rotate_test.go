@@ -0,0 +1,176 @@
+package secrethub
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestServiceRotateCommand_Run(t *testing.T) {
+	oldKey := credentials.CreateKey()
+	assert.OK(t, oldKey.Create())
+	oldCredential, err := oldKey.Export()
+	assert.OK(t, err)
+	_, oldFingerprint, err := oldKey.Verifier().Export()
+	assert.OK(t, err)
+
+	newKey := credentials.CreateKey()
+	assert.OK(t, newKey.Create())
+	newCredential, err := newKey.Export()
+	assert.OK(t, err)
+
+	accountID := uuid.New()
+
+	cases := map[string]struct {
+		cmd              ServiceRotateCommand
+		credentialType   api.CredentialType
+		accountID        uuid.UUID
+		sleepErr         error
+		expectedWritten  string
+		expectedDisabled string
+		expectedOut      string
+		expectedErr      error
+	}{
+		"secret": {
+			cmd: ServiceRotateCommand{
+				secretPath:  "namespace/repo/credential",
+				gracePeriod: time.Hour,
+			},
+			credentialType:   api.CredentialTypeKey,
+			accountID:        accountID,
+			expectedWritten:  string(newCredential),
+			expectedDisabled: oldFingerprint,
+			expectedOut: "Written the new credential to namespace/repo/credential.\n" +
+				"Waiting 1h0m0s before disabling the old credential...\n" +
+				"Rotated the credential of s-abc and disabled the old credential.\n",
+		},
+		"interrupted": {
+			cmd: ServiceRotateCommand{
+				secretPath:  "namespace/repo/credential",
+				gracePeriod: time.Hour,
+			},
+			credentialType:  api.CredentialTypeKey,
+			accountID:       accountID,
+			sleepErr:        context.Canceled,
+			expectedWritten: string(newCredential),
+			expectedOut: "Written the new credential to namespace/repo/credential.\n" +
+				"Waiting 1h0m0s before disabling the old credential...\n",
+			expectedErr: ErrServiceRotationInterrupted(oldFingerprint[:16]),
+		},
+		"credential of other account": {
+			cmd: ServiceRotateCommand{
+				secretPath: "namespace/repo/credential",
+			},
+			credentialType: api.CredentialTypeKey,
+			accountID:      uuid.New(),
+			expectedErr:    ErrCredentialNotOfService("s-abc"),
+		},
+		"aws service": {
+			cmd: ServiceRotateCommand{
+				secretPath: "namespace/repo/credential",
+			},
+			credentialType: api.CredentialTypeAWS,
+			expectedErr:    ErrServiceCredentialNotKey("s-abc", api.CredentialTypeAWS),
+		},
+		"no credential source": {
+			expectedErr: ErrNoServiceCredentialSource,
+		},
+		"secret and out file": {
+			cmd: ServiceRotateCommand{
+				secretPath: "namespace/repo/credential",
+				outFile:    "credential.txt",
+			},
+			expectedErr: ErrFlagsConflict("--secret and --out-file"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written, disabled string
+			io := fakeui.NewIO(t)
+			tc.cmd.serviceID = cli.StringValue{Value: "s-abc"}
+			tc.cmd.force = true
+			tc.cmd.io = io
+			tc.cmd.newCredential = func() *credentials.KeyCreator {
+				return newKey
+			}
+			tc.cmd.sleep = func(ctx context.Context, d time.Duration) error {
+				return tc.sleepErr
+			}
+			tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					ServiceService: &fakeclient.ServiceService{
+						GetFunc: func(id string) (*api.Service, error) {
+							return &api.Service{
+								ServiceID:  id,
+								AccountID:  accountID,
+								Credential: &api.Credential{Type: tc.credentialType},
+							}, nil
+						},
+					},
+					SecretService: &fakeclient.SecretService{
+						VersionService: &fakeclient.SecretVersionService{
+							GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+								return &api.SecretVersion{Data: oldCredential}, nil
+							},
+						},
+						WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+							written = string(data)
+							return &api.SecretVersion{}, nil
+						},
+					},
+				}, nil
+			}
+			tc.cmd.newServiceClient = func(provider credentials.Provider) (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					CredentialService: &fakeclient.CredentialService{
+						ListFunc: func(_ *secrethub.CredentialListParams) secrethub.CredentialIterator {
+							return &fakeclient.CredentialIterator{
+								Credentials: []*api.Credential{{AccountID: tc.accountID}},
+							}
+						},
+						CreateFunc: func(creator credentials.Creator, description string) (*api.Credential, error) {
+							return &api.Credential{}, nil
+						},
+						DisableFunc: func(fingerprint string) error {
+							disabled = fingerprint
+							return nil
+						},
+					},
+				}, nil
+			}
+
+			err := tc.cmd.Run()
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, written, tc.expectedWritten)
+			assert.Equal(t, disabled, tc.expectedDisabled)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+		})
+	}
+}
+
+func TestServiceRotateCommand_readCredential(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	path := filepath.Join(dir, "credential")
+	assert.OK(t, os.WriteFile(path, []byte("credential\n"), 0600))
+
+	cmd := ServiceRotateCommand{credentialFile: path}
+	credential, err := cmd.readCredential(nil)
+	assert.OK(t, err)
+	assert.Equal(t, credential, "credential")
+}
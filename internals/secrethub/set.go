@@ -41,6 +41,9 @@ func (cmd *SetCommand) Register(r cli.Registerer) {
 
 	clause.BindAction(cmd.Run)
 	clause.BindArguments(nil)
+
+	NewSetStatusCommand(cmd.io, cmd.newClient).Register(clause)
+	NewClearCommand(cmd.io).Register(clause)
 }
 
 // Run parses a secret spec file and presents secrets on the system.
@@ -76,7 +79,7 @@ func (cmd *SetCommand) Run() error {
 	}
 
 	for _, c := range presenter.EmptyConsumables() {
-		fmt.Fprintf(cmd.io.Output(), "Warning: %s contains no secret declarations.\n", c)
+		ui.Warningf(cmd.io.Output(), "%s contains no secret declarations.\n", c)
 	}
 
 	secrets := make(map[string]api.SecretVersion)
@@ -95,7 +98,7 @@ func (cmd *SetCommand) Run() error {
 		return err
 	}
 
-	fmt.Fprintln(cmd.io.Output(), "Set complete! The secrets are now available on your system.")
+	ui.Successf(cmd.io.Output(), "Set complete! The secrets are now available on your system.\n")
 
 	return nil
 }
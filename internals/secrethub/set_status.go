@@ -0,0 +1,160 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secretspec"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Statuses of a file in an environment prepared by the set command.
+const (
+	setStatusUpToDate      = "up to date"
+	setStatusStale         = "stale"
+	setStatusNotInSpec     = "not in spec"
+	setStatusSecretDeleted = "secret deleted"
+)
+
+// SetStatusCommand shows the environments that were prepared by the set command.
+type SetStatusCommand struct {
+	io            ui.IO
+	newClient     newClientFunc
+	in            string
+	useTimestamps bool
+}
+
+// NewSetStatusCommand creates a new SetStatusCommand.
+func NewSetStatusCommand(io ui.IO, newClient newClientFunc) *SetStatusCommand {
+	return &SetStatusCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *SetStatusCommand) Register(r cli.Registerer) {
+	clause := r.Command("status", "Show the environments prepared by the set command and whether their files are stale.")
+	clause.HelpLong("Every file in the " + secretspec.SecretEnvPath + " directory contains the plaintext value of a secret. " +
+		"A file is stale when a newer version of its secret has been written after the file was set. " +
+		"Files that are not declared in the secrets.yml file are no longer updated by the set command.")
+	clause.Flags().StringVarP(&cmd.in, "in", "i", "secrets.yml", "The path to a secrets.yml file to read")
+	registerTimestampFlag(clause, &cmd.useTimestamps)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run prints every file in the prepared environments with the secret it was set from.
+func (cmd *SetStatusCommand) Run() error {
+	envs, err := os.ReadDir(secretspec.SecretEnvPath)
+	if os.IsNotExist(err) {
+		fmt.Fprintln(cmd.io.Output(), "There are no environments prepared by the set command.")
+		return nil
+	} else if err != nil {
+		return ErrReadEnvDir(err)
+	}
+
+	presenter, err := cmd.readSpec()
+	if err != nil {
+		return err
+	}
+
+	timeFormatter := NewTimeFormatter(cmd.useTimestamps)
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", "ENVIRONMENT", "VARIABLE", "SECRET", "SET", "STATUS")
+	for _, env := range envs {
+		if !env.IsDir() {
+			continue
+		}
+
+		var vars map[string]string
+		if presenter != nil {
+			vars, _ = presenter.EnvVars(env.Name())
+		}
+
+		dirPath := filepath.Join(secretspec.SecretEnvPath, env.Name())
+		files, err := os.ReadDir(dirPath)
+		if err != nil {
+			return ErrReadEnvDir(err)
+		}
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			info, err := file.Info()
+			if err != nil {
+				return ErrReadEnvFile(file.Name(), err)
+			}
+
+			secretPath, declared := vars[file.Name()]
+			status, err := cmd.status(secretPath, declared, info)
+			if err != nil {
+				return err
+			}
+			if !declared {
+				secretPath = "-"
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				env.Name(),
+				file.Name(),
+				secretPath,
+				timeFormatter.Format(info.ModTime().Local()),
+				status,
+			)
+		}
+	}
+	return w.Flush()
+}
+
+// status returns whether the file that was set from the secret at the given path is stale.
+func (cmd *SetStatusCommand) status(secretPath string, declared bool, info os.FileInfo) (string, error) {
+	if !declared {
+		return setStatusNotInSpec, nil
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return "", err
+	}
+
+	version, err := client.Secrets().Versions().GetWithoutData(secretPath)
+	if err == api.ErrSecretNotFound {
+		return setStatusSecretDeleted, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if version.CreatedAt.After(info.ModTime()) {
+		return fmt.Sprintf("%s: version %d is newer", setStatusStale, version.Version), nil
+	}
+	return setStatusUpToDate, nil
+}
+
+// readSpec returns a presenter for the secrets.yml file, or nil when the file does not exist.
+func (cmd *SetStatusCommand) readSpec() (*secretspec.Presenter, error) {
+	spec, err := os.ReadFile(cmd.in)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(cmd.io.Output(), "WARN: %s does not exist, so the files cannot be mapped to secrets.\n", cmd.in)
+		return nil, nil
+	} else if err != nil {
+		return nil, ErrCannotReadFile(cmd.in, err)
+	}
+
+	presenter, err := secretspec.NewPresenter("", true, secretspec.DefaultParsers...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = presenter.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	return presenter, nil
+}
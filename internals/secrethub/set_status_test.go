@@ -0,0 +1,74 @@
+package secrethub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestSetStatusCommand_status(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	path := filepath.Join(dir, "DB_PASSWORD")
+	assert.OK(t, os.WriteFile(path, []byte("secret"), 0600))
+	setAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.OK(t, os.Chtimes(path, setAt, setAt))
+	info, err := os.Stat(path)
+	assert.OK(t, err)
+
+	cases := map[string]struct {
+		declared bool
+		version  *api.SecretVersion
+		err      error
+		expected string
+	}{
+		"not in spec": {
+			declared: false,
+			expected: setStatusNotInSpec,
+		},
+		"up to date": {
+			declared: true,
+			version:  &api.SecretVersion{Version: 2, CreatedAt: setAt.Add(-time.Hour)},
+			expected: setStatusUpToDate,
+		},
+		"stale": {
+			declared: true,
+			version:  &api.SecretVersion{Version: 3, CreatedAt: setAt.Add(time.Hour)},
+			expected: "stale: version 3 is newer",
+		},
+		"secret deleted": {
+			declared: true,
+			err:      api.ErrSecretNotFound,
+			expected: setStatusSecretDeleted,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := SetStatusCommand{
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithoutDataFunc: func(path string) (*api.SecretVersion, error) {
+									return tc.version, tc.err
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			status, err := cmd.status("company/app/db/password", tc.declared, info)
+			assert.OK(t, err)
+			assert.Equal(t, status, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package secrethub
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// StatsCommand enables, disables and prints the local usage statistics of the CLI.
+type StatsCommand struct {
+	io            ui.IO
+	stats         *UsageStats
+	enable        bool
+	disable       bool
+	useTimestamps bool
+}
+
+// NewStatsCommand creates a new StatsCommand.
+func NewStatsCommand(io ui.IO, stats *UsageStats) *StatsCommand {
+	return &StatsCommand{
+		io:    io,
+		stats: stats,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *StatsCommand) Register(r cli.Registerer) {
+	clause := r.Command("stats", "Show how often and how long every command has been used on this device.")
+	clause.HelpLong("Usage statistics are only recorded after they are enabled with --enable. " +
+		"They are stored in the configuration directory and are never sent anywhere. " +
+		"Only the name of a command, whether it failed and how long it took are recorded, never its arguments or flags.\n\n" +
+		"Use --disable to stop recording and remove the recorded statistics.")
+	clause.Flags().BoolVar(&cmd.enable, "enable", false, "Start recording usage statistics on this device.")
+	clause.Flags().BoolVar(&cmd.disable, "disable", false, "Stop recording usage statistics and remove the recorded statistics.")
+	registerTimestampFlag(clause, &cmd.useTimestamps)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run enables or disables the usage statistics, or prints a summary of them.
+func (cmd *StatsCommand) Run() error {
+	if cmd.enable && cmd.disable {
+		return ErrFlagsConflict("--enable and --disable")
+	}
+
+	if cmd.enable {
+		err := cmd.stats.enable()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.io.Output(), "Usage statistics are now recorded on this device. Run `secrethub stats` to see them.")
+		return nil
+	}
+
+	if cmd.disable {
+		err := cmd.stats.disable()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.io.Output(), "Usage statistics are no longer recorded and the recorded statistics have been removed.")
+		return nil
+	}
+
+	stats, err := cmd.stats.read()
+	if err != nil {
+		return err
+	}
+	if stats == nil {
+		fmt.Fprintln(cmd.io.Output(), "Usage statistics are not recorded. Run `secrethub stats --enable` to start recording them.")
+		return nil
+	}
+
+	commands := make([]string, 0, len(stats.Commands))
+	for command := range stats.Commands {
+		commands = append(commands, command)
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		a, b := stats.Commands[commands[i]], stats.Commands[commands[j]]
+		if a.Invocations != b.Invocations {
+			return a.Invocations > b.Invocations
+		}
+		return commands[i] < commands[j]
+	})
+
+	timeFormatter := NewTimeFormatter(cmd.useTimestamps)
+	fmt.Fprintf(cmd.io.Output(), "Recorded since %s.\n\n", timeFormatter.Format(stats.Since.Local()))
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", "COMMAND", "RUNS", "FAILURES", "AVG DURATION", "TOTAL DURATION", "LAST USED")
+	for _, command := range commands {
+		usage := stats.Commands[command]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\n",
+			command,
+			usage.Invocations,
+			usage.Failures,
+			usage.averageDuration().Round(time.Millisecond),
+			time.Duration(usage.DurationSeconds*float64(time.Second)).Round(time.Millisecond),
+			timeFormatter.Format(usage.LastUsed.Local()),
+		)
+	}
+	return w.Flush()
+}
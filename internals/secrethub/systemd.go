@@ -0,0 +1,24 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// SystemdCommand handles the integration with systemd.
+type SystemdCommand struct {
+	io ui.IO
+}
+
+// NewSystemdCommand creates a new SystemdCommand.
+func NewSystemdCommand(io ui.IO) *SystemdCommand {
+	return &SystemdCommand{
+		io: io,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *SystemdCommand) Register(r cli.Registerer) {
+	clause := r.Command("systemd", "Pass secrets to systemd services.")
+	NewSystemdInstallCommand(cmd.io).Register(clause)
+}
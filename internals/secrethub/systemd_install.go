@@ -0,0 +1,242 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrInvalidSystemdUnit           = errMain.Code("invalid_systemd_unit").ErrorPref("invalid unit name %s: must be the name of a service, e.g. my-app.service")
+	ErrInvalidSystemdCredentialName = errMain.Code("invalid_systemd_credential_name").ErrorPref("invalid credential name %s: must consist of letters, digits, '_', '-' and '.'")
+	ErrMissingSystemdSecrets        = errMain.Code("missing_systemd_secrets").Error("at least one --secret must be given, or --env-file together with --exec")
+	ErrSystemdEnvFileWithoutExec    = errMain.Code("systemd_env_file_without_exec").Error("--env-file can only be used together with --exec")
+	ErrSystemdFileExists            = errMain.Code("systemd_file_exists").ErrorPref("%s already exists: use --force to overwrite it")
+)
+
+var (
+	systemdUnitPattern           = regexp.MustCompile(`^[a-zA-Z0-9:_.@\\-]+\.service$`)
+	systemdCredentialNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.\-]+$`)
+)
+
+// systemdFile is a unit file or drop-in that is generated for a service.
+type systemdFile struct {
+	path    string
+	content string
+}
+
+// SystemdInstallCommand generates the systemd configuration that passes secrets to a service.
+type SystemdInstallCommand struct {
+	unit       cli.StringValue
+	secrets    map[string]string
+	envFile    string
+	exec       string
+	dir        string
+	dryRun     bool
+	force      bool
+	io         ui.IO
+	executable func() (string, error)
+}
+
+// NewSystemdInstallCommand creates a new SystemdInstallCommand.
+func NewSystemdInstallCommand(io ui.IO) *SystemdInstallCommand {
+	return &SystemdInstallCommand{
+		io:         io,
+		executable: os.Executable,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *SystemdInstallCommand) Register(r cli.Registerer) {
+	clause := r.Command("install", "Generate the systemd configuration that passes secrets to a service.")
+	clause.HelpLong("By default, the secrets are passed as systemd credentials. " +
+		"A secrethub-<unit>.service unit is generated that reads the secrets into /run/secrethub/<unit> before the service starts, " +
+		"and a drop-in for the service loads them with LoadCredential=. " +
+		"The service reads a secret from the file $CREDENTIALS_DIRECTORY/<name>. " +
+		"SetCredential= is not used, because it would store the secret in the unit file.\n\n" +
+		"With --exec, a drop-in is generated that replaces the ExecStart= of the service with `secrethub run`, " +
+		"which passes the secrets as environment variables to the given command.\n\n" +
+		"The secrethub-<unit>.service unit runs secrethub as root and the drop-in of --exec as the user of the service, so that user must have a configured credential, " +
+		"or SECRETHUB_CREDENTIAL must be set in its environment. " +
+		"Run `systemctl daemon-reload` and restart the service to apply the configuration.")
+	clause.Flags().StringToStringVar(&cmd.secrets, "secret", nil, "A secret to pass to the service with `NAME=<path>`. The name is the name of the credential, or of the environment variable with --exec. Can be repeated.")
+	clause.Flags().StringVar(&cmd.envFile, "env-file", "", "With --exec, the path to a file with environment variable mappings to pass to `secrethub run`.")
+	clause.Flags().StringVar(&cmd.exec, "exec", "", "The command line of the service, e.g. \"/usr/bin/my-app --port 8080\". When set, the service is started with `secrethub run` instead of using systemd credentials.")
+	clause.Flags().StringVar(&cmd.dir, "dir", "/etc/systemd/system", "The directory to write the unit files and drop-ins to.")
+	clause.Flags().BoolVar(&cmd.dryRun, "dry-run", false, "Print the configuration instead of writing it.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.unit, Name: "unit", Required: true, Description: "The name of the service, e.g. my-app.service."},
+	})
+}
+
+// Run generates the configuration and writes or prints it.
+func (cmd *SystemdInstallCommand) Run() error {
+	unit := cmd.unit.Value
+	if !strings.HasSuffix(unit, ".service") {
+		unit += ".service"
+	}
+	if !systemdUnitPattern.MatchString(unit) {
+		return ErrInvalidSystemdUnit(unit)
+	}
+
+	if len(cmd.secrets) == 0 && (cmd.exec == "" || cmd.envFile == "") {
+		return ErrMissingSystemdSecrets
+	}
+	if cmd.envFile != "" && cmd.exec == "" {
+		return ErrSystemdEnvFileWithoutExec
+	}
+
+	for name, path := range cmd.secrets {
+		if cmd.exec == "" && !systemdCredentialNamePattern.MatchString(name) {
+			return ErrInvalidSystemdCredentialName(name)
+		}
+		_, err := api.NewSecretPath(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	executable, err := cmd.executable()
+	if err != nil {
+		return err
+	}
+
+	var files []systemdFile
+	if cmd.exec == "" {
+		files = cmd.credentialFiles(executable, unit)
+	} else {
+		files = cmd.runFiles(executable, unit)
+	}
+
+	if cmd.dryRun {
+		for _, file := range files {
+			fmt.Fprintf(cmd.io.Output(), "# %s\n%s\n", file.path, file.content)
+		}
+		return nil
+	}
+
+	if !cmd.force {
+		for _, file := range files {
+			_, err := os.Stat(file.path)
+			if err == nil {
+				return ErrSystemdFileExists(file.path)
+			}
+		}
+	}
+
+	for _, file := range files {
+		err = os.MkdirAll(filepath.Dir(file.path), 0755)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(file.path, []byte(file.content), 0644)
+		if err != nil {
+			return ErrCannotWrite(file.path, err)
+		}
+		fmt.Fprintf(cmd.io.Output(), "Wrote %s\n", file.path)
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Run `systemctl daemon-reload` and restart %s to apply the configuration.\n", unit)
+	return nil
+}
+
+// credentialFiles returns the unit that reads the secrets into files and the drop-in that loads them as credentials.
+func (cmd *SystemdInstallCommand) credentialFiles(executable string, unit string) []systemdFile {
+	name := strings.TrimSuffix(unit, ".service")
+	readUnit := "secrethub-" + name + ".service"
+	runtimeDir := "secrethub/" + name
+
+	var reader strings.Builder
+	fmt.Fprintf(&reader, "# Generated by `secrethub systemd install`.\n")
+	fmt.Fprintf(&reader, "[Unit]\n")
+	fmt.Fprintf(&reader, "Description=Read the secrets of %s from SecretHub\n", unit)
+	fmt.Fprintf(&reader, "Before=%s\n", unit)
+	fmt.Fprintf(&reader, "PartOf=%s\n", unit)
+	fmt.Fprintf(&reader, "Wants=network-online.target\n")
+	fmt.Fprintf(&reader, "After=network-online.target\n\n")
+	fmt.Fprintf(&reader, "[Service]\n")
+	fmt.Fprintf(&reader, "Type=oneshot\n")
+	fmt.Fprintf(&reader, "RemainAfterExit=yes\n")
+	fmt.Fprintf(&reader, "UMask=0077\n")
+	// The runtime directory is removed, together with the secrets, when the service is stopped.
+	fmt.Fprintf(&reader, "RuntimeDirectory=%s\n", runtimeDir)
+	for _, secretName := range sortedKeys(cmd.secrets) {
+		fmt.Fprintf(&reader, "ExecStart=%s\n", systemdCommandLine(executable, "read", "--out-file", "/run/"+runtimeDir+"/"+secretName, cmd.secrets[secretName]))
+	}
+
+	var dropIn strings.Builder
+	fmt.Fprintf(&dropIn, "# Generated by `secrethub systemd install`.\n")
+	fmt.Fprintf(&dropIn, "[Unit]\n")
+	fmt.Fprintf(&dropIn, "Requires=%s\n", readUnit)
+	fmt.Fprintf(&dropIn, "After=%s\n\n", readUnit)
+	fmt.Fprintf(&dropIn, "[Service]\n")
+	for _, secretName := range sortedKeys(cmd.secrets) {
+		fmt.Fprintf(&dropIn, "LoadCredential=%s:/run/%s/%s\n", secretName, runtimeDir, secretName)
+	}
+
+	return []systemdFile{
+		{path: filepath.Join(cmd.dir, readUnit), content: reader.String()},
+		{path: filepath.Join(cmd.dir, unit+".d", "secrethub.conf"), content: dropIn.String()},
+	}
+}
+
+// runFiles returns the drop-in that starts the service with `secrethub run`.
+func (cmd *SystemdInstallCommand) runFiles(executable string, unit string) []systemdFile {
+	args := []string{executable, "run"}
+	for _, name := range sortedKeys(cmd.secrets) {
+		args = append(args, "--envar", name+"="+cmd.secrets[name])
+	}
+	if cmd.envFile != "" {
+		args = append(args, "--env-file", cmd.envFile)
+	}
+
+	var dropIn strings.Builder
+	fmt.Fprintf(&dropIn, "# Generated by `secrethub systemd install`.\n")
+	fmt.Fprintf(&dropIn, "[Unit]\n")
+	fmt.Fprintf(&dropIn, "Wants=network-online.target\n")
+	fmt.Fprintf(&dropIn, "After=network-online.target\n\n")
+	fmt.Fprintf(&dropIn, "[Service]\n")
+	// An empty ExecStart= clears the command of the service, so that it is replaced instead of added.
+	fmt.Fprintf(&dropIn, "ExecStart=\n")
+	fmt.Fprintf(&dropIn, "ExecStart=%s -- %s\n", systemdCommandLine(args...), cmd.exec)
+
+	return []systemdFile{
+		{path: filepath.Join(cmd.dir, unit+".d", "secrethub.conf"), content: dropIn.String()},
+	}
+}
+
+// systemdCommandLine joins the arguments into a command line for an Exec directive of a unit file.
+func systemdCommandLine(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		// Specifiers and environment variables are expanded by systemd, so their prefixes are escaped.
+		arg = strings.NewReplacer("%", "%%", "$", "$$").Replace(arg)
+		if strings.ContainsAny(arg, " \t\"'\\;") {
+			arg = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+// sortedKeys returns the keys of the map in alphabetical order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
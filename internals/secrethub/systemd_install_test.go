@@ -0,0 +1,142 @@
+package secrethub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestSystemdInstallCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		cmd         SystemdInstallCommand
+		expectedOut string
+		expectedErr error
+	}{
+		"credentials": {
+			cmd: SystemdInstallCommand{
+				unit:    cli.StringValue{Value: "my-app"},
+				secrets: map[string]string{"db_password": "namespace/repo/db/password", "api_key": "namespace/repo/api-key"},
+				dir:     "/etc/systemd/system",
+			},
+			expectedOut: "# /etc/systemd/system/secrethub-my-app.service\n" +
+				"# Generated by `secrethub systemd install`.\n" +
+				"[Unit]\n" +
+				"Description=Read the secrets of my-app.service from SecretHub\n" +
+				"Before=my-app.service\n" +
+				"PartOf=my-app.service\n" +
+				"Wants=network-online.target\n" +
+				"After=network-online.target\n\n" +
+				"[Service]\n" +
+				"Type=oneshot\n" +
+				"RemainAfterExit=yes\n" +
+				"UMask=0077\n" +
+				"RuntimeDirectory=secrethub/my-app\n" +
+				"ExecStart=/usr/bin/secrethub read --out-file /run/secrethub/my-app/api_key namespace/repo/api-key\n" +
+				"ExecStart=/usr/bin/secrethub read --out-file /run/secrethub/my-app/db_password namespace/repo/db/password\n\n" +
+				"# /etc/systemd/system/my-app.service.d/secrethub.conf\n" +
+				"# Generated by `secrethub systemd install`.\n" +
+				"[Unit]\n" +
+				"Requires=secrethub-my-app.service\n" +
+				"After=secrethub-my-app.service\n\n" +
+				"[Service]\n" +
+				"LoadCredential=api_key:/run/secrethub/my-app/api_key\n" +
+				"LoadCredential=db_password:/run/secrethub/my-app/db_password\n\n",
+		},
+		"exec": {
+			cmd: SystemdInstallCommand{
+				unit:    cli.StringValue{Value: "my-app.service"},
+				secrets: map[string]string{"DB_PASSWORD": "namespace/repo/db/password"},
+				envFile: "/etc/my app/secrethub.env",
+				exec:    "/usr/bin/my-app --port 8080",
+				dir:     "/etc/systemd/system",
+			},
+			expectedOut: "# /etc/systemd/system/my-app.service.d/secrethub.conf\n" +
+				"# Generated by `secrethub systemd install`.\n" +
+				"[Unit]\n" +
+				"Wants=network-online.target\n" +
+				"After=network-online.target\n\n" +
+				"[Service]\n" +
+				"ExecStart=\n" +
+				"ExecStart=/usr/bin/secrethub run --envar DB_PASSWORD=namespace/repo/db/password --env-file \"/etc/my app/secrethub.env\" -- /usr/bin/my-app --port 8080\n\n",
+		},
+		"no secrets": {
+			cmd: SystemdInstallCommand{
+				unit: cli.StringValue{Value: "my-app"},
+			},
+			expectedErr: ErrMissingSystemdSecrets,
+		},
+		"env file without exec": {
+			cmd: SystemdInstallCommand{
+				unit:    cli.StringValue{Value: "my-app"},
+				secrets: map[string]string{"db_password": "namespace/repo/db/password"},
+				envFile: "secrethub.env",
+			},
+			expectedErr: ErrSystemdEnvFileWithoutExec,
+		},
+		"invalid unit": {
+			cmd: SystemdInstallCommand{
+				unit:    cli.StringValue{Value: "my app"},
+				secrets: map[string]string{"db_password": "namespace/repo/db/password"},
+			},
+			expectedErr: ErrInvalidSystemdUnit("my app.service"),
+		},
+		"invalid credential name": {
+			cmd: SystemdInstallCommand{
+				unit:    cli.StringValue{Value: "my-app"},
+				secrets: map[string]string{"db/password": "namespace/repo/db/password"},
+			},
+			expectedErr: ErrInvalidSystemdCredentialName("db/password"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			tc.cmd.io = io
+			tc.cmd.dryRun = true
+			tc.cmd.executable = func() (string, error) {
+				return "/usr/bin/secrethub", nil
+			}
+
+			err := tc.cmd.Run()
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+		})
+	}
+}
+
+func TestSystemdInstallCommand_Run_Write(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	cmd := SystemdInstallCommand{
+		unit:    cli.StringValue{Value: "my-app"},
+		secrets: map[string]string{"db_password": "namespace/repo/db/password"},
+		dir:     dir,
+		io:      fakeui.NewIO(t),
+		executable: func() (string, error) {
+			return "/usr/bin/secrethub", nil
+		},
+	}
+
+	err := cmd.Run()
+	assert.OK(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, "secrethub-my-app.service"))
+	assert.OK(t, err)
+	_, err = os.Stat(filepath.Join(dir, "my-app.service.d", "secrethub.conf"))
+	assert.OK(t, err)
+
+	err = cmd.Run()
+	assert.Equal(t, err, ErrSystemdFileExists(filepath.Join(dir, "secrethub-my-app.service")))
+
+	cmd.force = true
+	err = cmd.Run()
+	assert.OK(t, err)
+}
@@ -0,0 +1,291 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	errTeam               = errio.Namespace("team")
+	ErrTeamNotFound       = errTeam.Code("not_found").ErrorPref("team %s does not exist")
+	ErrTeamAlreadyExists  = errTeam.Code("already_exists").ErrorPref("team %s already exists")
+	ErrInvalidTeamsSecret = errTeam.Code("invalid_teams_secret").ErrorPref("could not parse the teams in %s: %s")
+	ErrTeamsPathNotSet    = errTeam.Code("teams_path_not_set").Error("no secret to store the teams in is set: set --teams-path or the SECRETHUB_TEAMS_PATH environment variable to the path of a secret, e.g. my-org/admin/teams")
+)
+
+// TeamCommand handles operations on teams.
+type TeamCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+	teams     *TeamStore
+}
+
+// NewTeamCommand creates a new TeamCommand.
+func NewTeamCommand(io ui.IO, newClient newClientFunc, teams *TeamStore) *TeamCommand {
+	return &TeamCommand{
+		io:        io,
+		newClient: newClient,
+		teams:     teams,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *TeamCommand) Register(r cli.Registerer) {
+	clause := r.Command("team", "Manage teams of accounts that get the same access rules.")
+	clause.HelpLong("The teams are stored as JSON in a secret, which is set with --teams-path or the SECRETHUB_TEAMS_PATH environment variable. " +
+		"Everyone who manages the teams, including CI, must use the same secret, e.g. my-org/admin/teams. " +
+		"Only accounts with read access to the secret can use the teams and only accounts with write access can change them.\n\n" +
+		"The access of a team is applied by setting an access rule for every member of the team, " +
+		"so the access rules on the server are always set per account.")
+	NewTeamAddCommand(cmd.io, cmd.newClient, cmd.teams).Register(clause)
+	NewTeamCreateCommand(cmd.io, cmd.teams).Register(clause)
+	NewTeamGrantCommand(cmd.io, cmd.newClient, cmd.teams).Register(clause)
+	NewTeamListCommand(cmd.io, cmd.teams).Register(clause)
+	NewTeamSyncCommand(cmd.io, cmd.newClient, cmd.teams).Register(clause)
+}
+
+// teamDefinition is a named group of accounts with the access rules that apply to all of them.
+type teamDefinition struct {
+	Name    string           `json:"name"`
+	Members []string         `json:"members"`
+	Access  []teamAccessRule `json:"access"`
+}
+
+// teamAccessRule is a permission on a directory that is given to every member of a team.
+type teamAccessRule struct {
+	Path       string `json:"path" yaml:"path"`
+	Permission string `json:"permission" yaml:"permission"`
+}
+
+// hasMember returns whether the account is a member of the team.
+func (t teamDefinition) hasMember(accountName string) bool {
+	for _, member := range t.Members {
+		if strings.EqualFold(member, accountName) {
+			return true
+		}
+	}
+	return false
+}
+
+// TeamStore reads and writes the team definitions in a secret, so that they are shared
+// by everyone who has access to that secret.
+type TeamStore struct {
+	path      string
+	newClient newClientFunc
+	client    secrethub.ClientInterface
+}
+
+// NewTeamStore creates a new TeamStore that uses the given client to read and write the teams.
+func NewTeamStore(newClient newClientFunc) *TeamStore {
+	return &TeamStore{
+		newClient: newClient,
+	}
+}
+
+// Register registers the flag for the secret that stores the teams.
+func (s *TeamStore) Register(app *cli.App) {
+	app.PersistentFlags().StringVar(&s.path, "teams-path", "", "The path of the secret that stores the teams of `secrethub team`, e.g. my-org/admin/teams.")
+}
+
+// secretPath returns the path of the secret that stores the teams.
+func (s *TeamStore) secretPath() (string, error) {
+	if s.path == "" {
+		return "", ErrTeamsPathNotSet
+	}
+	err := api.ValidateSecretPath(s.path)
+	if err != nil {
+		return "", err
+	}
+	return s.path, nil
+}
+
+// getClient returns the client to read and write the teams with.
+func (s *TeamStore) getClient() (secrethub.ClientInterface, error) {
+	if s.client == nil {
+		client, err := s.newClient()
+		if err != nil {
+			return nil, err
+		}
+		s.client = client
+	}
+	return s.client, nil
+}
+
+// list returns all teams, sorted by name.
+func (s *TeamStore) list() ([]teamDefinition, error) {
+	path, err := s.secretPath()
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := client.Secrets().Versions().GetWithData(path)
+	if api.IsErrNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var teams []teamDefinition
+	err = json.Unmarshal(version.Data, &teams)
+	if err != nil {
+		return nil, ErrInvalidTeamsSecret(path, err)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].Name < teams[j].Name
+	})
+	return teams, nil
+}
+
+// get returns the team with the given name.
+func (s *TeamStore) get(name string) (teamDefinition, error) {
+	team, ok, err := s.find(name)
+	if err != nil {
+		return teamDefinition{}, err
+	}
+	if !ok {
+		return teamDefinition{}, ErrTeamNotFound(name)
+	}
+	return team, nil
+}
+
+// find returns the team with the given name and whether it exists.
+func (s *TeamStore) find(name string) (teamDefinition, bool, error) {
+	teams, err := s.list()
+	if err != nil {
+		return teamDefinition{}, false, err
+	}
+	for _, team := range teams {
+		if strings.EqualFold(team.Name, name) {
+			return team, true, nil
+		}
+	}
+	return teamDefinition{}, false, nil
+}
+
+// save adds the team or replaces the team with the same name.
+func (s *TeamStore) save(team teamDefinition) error {
+	teams, err := s.list()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range teams {
+		if strings.EqualFold(teams[i].Name, team.Name) {
+			teams[i] = team
+			replaced = true
+		}
+	}
+	if !replaced {
+		teams = append(teams, team)
+	}
+
+	raw, err := json.MarshalIndent(teams, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path, err := s.secretPath()
+	if err != nil {
+		return err
+	}
+	client, err := s.getClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.Secrets().Write(path, raw)
+	return err
+}
+
+// teamAccessChange is an access rule that has to be set for a member of a team.
+type teamAccessChange struct {
+	accountName string
+	rule        teamAccessRule
+	// current is the permission the account currently has on the path, or empty when the account has no rule.
+	current string
+}
+
+// String returns a description of the change.
+func (c teamAccessChange) String() string {
+	if c.current == "" {
+		return fmt.Sprintf("%s: set %s on %s", c.accountName, c.rule.Permission, c.rule.Path)
+	}
+	return fmt.Sprintf("%s: change %s to %s on %s", c.accountName, c.current, c.rule.Permission, c.rule.Path)
+}
+
+// teamAccessChanges returns the access rules that have to be set so that every
+// given member has the given permissions. Rules that are already set are skipped.
+func teamAccessChanges(client secrethub.ClientInterface, members []string, rules []teamAccessRule) ([]teamAccessChange, error) {
+	var changes []teamAccessChange
+	for _, rule := range rules {
+		for _, member := range members {
+			current, err := client.AccessRules().Get(rule.Path, member)
+			if err == api.ErrAccessRuleNotFound {
+				changes = append(changes, teamAccessChange{accountName: member, rule: rule})
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			if current.Permission.String() != rule.Permission {
+				changes = append(changes, teamAccessChange{accountName: member, rule: rule, current: current.Permission.String()})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// applyTeamAccessChanges sets the access rules, after asking for confirmation when force is false.
+// It returns whether the changes were applied.
+func applyTeamAccessChanges(io ui.IO, client secrethub.ClientInterface, changes []teamAccessChange, force bool) (bool, error) {
+	out := io.Output()
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "All access rules are up to date.")
+		return true, nil
+	}
+
+	printTeamAccessChanges(out, changes)
+
+	if !force {
+		confirmed, err := ui.AskYesNo(io, "[WARNING] Are you sure you want to set these access rules?", ui.DefaultNo)
+		if err != nil {
+			return false, err
+		}
+		if !confirmed {
+			fmt.Fprintln(out, "Aborting.")
+			return false, nil
+		}
+	}
+
+	for _, change := range changes {
+		_, err := client.AccessRules().Set(change.rule.Path, change.rule.Permission, change.accountName)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	fmt.Fprintln(out, "Access rules set!")
+	return true, nil
+}
+
+// printTeamAccessChanges prints one line for every change.
+func printTeamAccessChanges(w io.Writer, changes []teamAccessChange) {
+	for _, change := range changes {
+		fmt.Fprintln(w, change)
+	}
+}
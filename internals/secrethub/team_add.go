@@ -0,0 +1,78 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// TeamAddCommand adds an account to a team and gives it the access of the team.
+type TeamAddCommand struct {
+	io          ui.IO
+	newClient   newClientFunc
+	teams       *TeamStore
+	name        cli.StringValue
+	accountName api.AccountName
+	force       bool
+}
+
+// NewTeamAddCommand creates a new TeamAddCommand.
+func NewTeamAddCommand(io ui.IO, newClient newClientFunc, teams *TeamStore) *TeamAddCommand {
+	return &TeamAddCommand{
+		io:        io,
+		newClient: newClient,
+		teams:     teams,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *TeamAddCommand) Register(r cli.Registerer) {
+	clause := r.Command("add", "Add a user or service to a team and set the access rules of the team for it.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.name, Name: "team-name", Required: true, Description: "The name of the team."},
+		{Value: &cmd.accountName, Name: "account-name", Required: true, Description: "The account name (username or service name) to add to the team."},
+	})
+}
+
+// Run adds the account to the team and sets the access rules of the team for it.
+func (cmd *TeamAddCommand) Run() error {
+	team, err := cmd.teams.get(cmd.name.Value)
+	if err != nil {
+		return err
+	}
+
+	if !team.hasMember(cmd.accountName.Value()) {
+		team.Members = append(team.Members, cmd.accountName.Value())
+	}
+
+	if len(team.Access) > 0 {
+		client, err := cmd.newClient()
+		if err != nil {
+			return err
+		}
+
+		changes, err := teamAccessChanges(client, []string{cmd.accountName.Value()}, team.Access)
+		if err != nil {
+			return err
+		}
+
+		applied, err := applyTeamAccessChanges(cmd.io, client, changes, cmd.force)
+		if err != nil || !applied {
+			return err
+		}
+	}
+
+	err = cmd.teams.save(team)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Added %s to team %s.\n", cmd.accountName, team.Name)
+	return nil
+}
@@ -0,0 +1,52 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// TeamCreateCommand creates a new team without members or access.
+type TeamCreateCommand struct {
+	io    ui.IO
+	teams *TeamStore
+	name  cli.StringValue
+}
+
+// NewTeamCreateCommand creates a new TeamCreateCommand.
+func NewTeamCreateCommand(io ui.IO, teams *TeamStore) *TeamCreateCommand {
+	return &TeamCreateCommand{
+		io:    io,
+		teams: teams,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *TeamCreateCommand) Register(r cli.Registerer) {
+	clause := r.Command("create", "Create a new team.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.name, Name: "team-name", Required: true, Description: "The name of the team to create."},
+	})
+}
+
+// Run creates the team.
+func (cmd *TeamCreateCommand) Run() error {
+	_, exists, err := cmd.teams.find(cmd.name.Value)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrTeamAlreadyExists(cmd.name.Value)
+	}
+
+	err = cmd.teams.save(teamDefinition{Name: cmd.name.Value})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Created team %s. Add members with `%s team add` and give access with `%s team grant`.\n", cmd.name.Value, ApplicationName, ApplicationName)
+	return nil
+}
@@ -0,0 +1,89 @@
+package secrethub
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// TeamGrantCommand gives a team a permission on a directory.
+type TeamGrantCommand struct {
+	io         ui.IO
+	newClient  newClientFunc
+	teams      *TeamStore
+	name       cli.StringValue
+	path       api.DirPath
+	permission api.Permission
+	force      bool
+}
+
+// NewTeamGrantCommand creates a new TeamGrantCommand.
+func NewTeamGrantCommand(io ui.IO, newClient newClientFunc, teams *TeamStore) *TeamGrantCommand {
+	return &TeamGrantCommand{
+		io:        io,
+		newClient: newClient,
+		teams:     teams,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *TeamGrantCommand) Register(r cli.Registerer) {
+	clause := r.Command("grant", "Give a team a permission on a directory by setting an access rule for every member.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{
+		{Value: &cmd.name, Name: "team-name", Required: true, Description: "The name of the team."},
+		{Value: &cmd.path, Name: "dir-path", Placeholder: dirPathPlaceHolder, Required: true, Description: "The path of the directory to give the team access to."},
+		{Value: &cmd.permission, Name: "permission", Required: true, Description: "The permission to give the team."},
+	})
+}
+
+// Run adds the permission to the team and sets it for all members.
+func (cmd *TeamGrantCommand) Run() error {
+	team, err := cmd.teams.get(cmd.name.Value)
+	if err != nil {
+		return err
+	}
+
+	rule := teamAccessRule{Path: cmd.path.Value(), Permission: cmd.permission.String()}
+	replaced := false
+	for i, existing := range team.Access {
+		if strings.EqualFold(existing.Path, rule.Path) {
+			team.Access[i] = rule
+			replaced = true
+		}
+	}
+	if !replaced {
+		team.Access = append(team.Access, rule)
+	}
+
+	if len(team.Members) > 0 {
+		client, err := cmd.newClient()
+		if err != nil {
+			return err
+		}
+
+		changes, err := teamAccessChanges(client, team.Members, []teamAccessRule{rule})
+		if err != nil {
+			return err
+		}
+
+		applied, err := applyTeamAccessChanges(cmd.io, client, changes, cmd.force)
+		if err != nil || !applied {
+			return err
+		}
+	}
+
+	err = cmd.teams.save(team)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Team %s has %s access on %s.\n", team.Name, rule.Permission, rule.Path)
+	return nil
+}
@@ -0,0 +1,62 @@
+package secrethub
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// TeamListCommand lists the teams with their members and access.
+type TeamListCommand struct {
+	io    ui.IO
+	teams *TeamStore
+	quiet bool
+}
+
+// NewTeamListCommand creates a new TeamListCommand.
+func NewTeamListCommand(io ui.IO, teams *TeamStore) *TeamListCommand {
+	return &TeamListCommand{
+		io:    io,
+		teams: teams,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *TeamListCommand) Register(r cli.Registerer) {
+	clause := r.Command("ls", "List all teams with their members and access.")
+	clause.Alias("list")
+	clause.Flags().BoolVarP(&cmd.quiet, "quiet", "q", false, "Only print team names.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run lists the teams.
+func (cmd *TeamListCommand) Run() error {
+	teams, err := cmd.teams.list()
+	if err != nil {
+		return err
+	}
+
+	if cmd.quiet {
+		for _, team := range teams {
+			fmt.Fprintln(cmd.io.Output(), team.Name)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\n", "NAME", "MEMBERS", "ACCESS")
+	for _, team := range teams {
+		access := make([]string, len(team.Access))
+		for i, rule := range team.Access {
+			access[i] = rule.Path + ":" + rule.Permission
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", team.Name, strings.Join(team.Members, ","), strings.Join(access, ","))
+	}
+	return w.Flush()
+}
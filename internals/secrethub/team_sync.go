@@ -0,0 +1,71 @@
+package secrethub
+
+import (
+	"fmt"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// TeamSyncCommand sets the access rules that are missing or different from the team definitions.
+type TeamSyncCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+	teams     *TeamStore
+	dryRun    bool
+	force     bool
+}
+
+// NewTeamSyncCommand creates a new TeamSyncCommand.
+func NewTeamSyncCommand(io ui.IO, newClient newClientFunc, teams *TeamStore) *TeamSyncCommand {
+	return &TeamSyncCommand{
+		io:        io,
+		newClient: newClient,
+		teams:     teams,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *TeamSyncCommand) Register(r cli.Registerer) {
+	clause := r.Command("sync", "Set the access rules that are missing or changed for the members of all teams.")
+	clause.HelpLong("Access rules can drift from the team definitions when they are changed with `" + ApplicationName + " acl`. " +
+		"Sync only sets access rules for members of a team. Access rules of accounts outside a team are left untouched.")
+	clause.Flags().BoolVar(&cmd.dryRun, "dry-run", false, "Only print the access rules that would be set.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run sets the access rules that are missing or changed.
+func (cmd *TeamSyncCommand) Run() error {
+	teams, err := cmd.teams.list()
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	var changes []teamAccessChange
+	for _, team := range teams {
+		teamChanges, err := teamAccessChanges(client, team.Members, team.Access)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, teamChanges...)
+	}
+
+	if cmd.dryRun {
+		if len(changes) == 0 {
+			fmt.Fprintln(cmd.io.Output(), "All access rules are up to date.")
+		}
+		printTeamAccessChanges(cmd.io.Output(), changes)
+		return nil
+	}
+
+	_, err = applyTeamAccessChanges(cmd.io, client, changes, cmd.force)
+	return err
+}
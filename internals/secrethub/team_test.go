@@ -0,0 +1,128 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+// newFakeTeamStore returns a TeamStore that keeps the secret with the teams in memory.
+func newFakeTeamStore() *TeamStore {
+	var data []byte
+	return &TeamStore{
+		path: "namespace/admin/teams",
+		client: fakeclient.Client{
+			SecretService: &fakeclient.SecretService{
+				VersionService: &fakeclient.SecretVersionService{
+					GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+						if data == nil {
+							return nil, api.ErrSecretNotFound
+						}
+						return &api.SecretVersion{Data: data}, nil
+					},
+				},
+				WriteFunc: func(path string, written []byte) (*api.SecretVersion, error) {
+					data = written
+					return &api.SecretVersion{}, nil
+				},
+			},
+		},
+	}
+}
+
+func TestTeamSyncCommand_Run(t *testing.T) {
+	teams := newFakeTeamStore()
+	assert.OK(t, teams.save(teamDefinition{
+		Name:    "developers",
+		Members: []string{"dev1", "dev2", "dev3"},
+		Access: []teamAccessRule{
+			{Path: "namespace/repo", Permission: "write"},
+		},
+	}))
+
+	current := map[string]api.Permission{
+		"dev1": api.PermissionWrite,
+		"dev2": api.PermissionRead,
+	}
+
+	cases := map[string]struct {
+		dryRun bool
+		set    []string
+		out    string
+	}{
+		"dry run": {
+			dryRun: true,
+			out: "dev2: change read to write on namespace/repo\n" +
+				"dev3: set write on namespace/repo\n",
+		},
+		"sync": {
+			set: []string{"dev2", "dev3"},
+			out: "dev2: change read to write on namespace/repo\n" +
+				"dev3: set write on namespace/repo\n" +
+				"Access rules set!\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var set []string
+			io := fakeui.NewIO(t)
+			cmd := TeamSyncCommand{
+				io:     io,
+				teams:  teams,
+				dryRun: tc.dryRun,
+				force:  true,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						AccessRuleService: &fakeclient.AccessRuleService{
+							GetFunc: func(path string, accountName string) (*api.AccessRule, error) {
+								permission, ok := current[accountName]
+								if !ok {
+									return nil, api.ErrAccessRuleNotFound
+								}
+								return &api.AccessRule{Permission: permission}, nil
+							},
+							SetFunc: func(path string, permission string, accountName string) (*api.AccessRule, error) {
+								assert.Equal(t, path, "namespace/repo")
+								assert.Equal(t, permission, "write")
+								set = append(set, accountName)
+								return nil, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+			assert.OK(t, err)
+			assert.Equal(t, set, tc.set)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
+
+func TestTeamStore(t *testing.T) {
+	_, err := (&TeamStore{}).list()
+	assert.Equal(t, err, ErrTeamsPathNotSet)
+
+	teams := newFakeTeamStore()
+
+	_, err = teams.get("developers")
+	assert.Equal(t, err, ErrTeamNotFound("developers"))
+
+	assert.OK(t, teams.save(teamDefinition{Name: "ops"}))
+	assert.OK(t, teams.save(teamDefinition{Name: "developers"}))
+	assert.OK(t, teams.save(teamDefinition{Name: "Developers", Members: []string{"dev1"}}))
+
+	actual, err := teams.list()
+	assert.OK(t, err)
+	assert.Equal(t, actual, []teamDefinition{
+		{Name: "Developers", Members: []string{"dev1"}},
+		{Name: "ops"},
+	})
+}
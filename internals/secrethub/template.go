@@ -15,6 +15,8 @@ func getTemplateParser(raw []byte, version string) (tpl.Parser, error) {
 		return tpl.NewV1Parser(), nil
 	case "2", "v2":
 		return tpl.NewV2Parser(), nil
+	case "3", "v3":
+		return tpl.NewV3Parser(), nil
 	case "latest":
 		return tpl.NewParser(), nil
 	default:
@@ -0,0 +1,122 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrInvalidTerraformQuery     = errMain.Code("invalid_terraform_query").ErrorPref("invalid query on stdin: %s: must be a JSON object with string values")
+	ErrTerraformQueryPathMissing = errMain.Code("terraform_query_path_missing").Error("the query on stdin has no path: use {\"path\": \"<secret-path>\"}, or --batch to read multiple secrets")
+	ErrEmptyTerraformQuery       = errMain.Code("empty_terraform_query").Error("the query on stdin has no secret paths")
+)
+
+// TerraformOutputCommand reads secrets for the external data source of Terraform.
+type TerraformOutputCommand struct {
+	batch     bool
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewTerraformOutputCommand creates a new TerraformOutputCommand.
+func NewTerraformOutputCommand(io ui.IO, newClient newClientFunc) *TerraformOutputCommand {
+	return &TerraformOutputCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *TerraformOutputCommand) Register(r cli.Registerer) {
+	clause := r.Command("terraform-output", "Read secrets for the external data source of Terraform.")
+	clause.HelpLong("This command implements the protocol of the external data source of Terraform. " +
+		"It reads a JSON query from stdin and writes the result as a JSON object to stdout:\n\n" +
+		"    data \"external\" \"db_password\" {\n" +
+		"      program = [\"secrethub\", \"terraform-output\"]\n" +
+		"      query   = { path = \"my-org/my-repo/db/password\" }\n" +
+		"    }\n\n" +
+		"The result is {\"value\": \"<secret>\"}, so the secret can be used as data.external.db_password.result.value.\n\n" +
+		"With --batch, every key of the query is mapped to a secret path and the result contains the secrets under the same keys. " +
+		"For example, the query { username = \"my-org/my-repo/db/username\", password = \"my-org/my-repo/db/password\" } " +
+		"results in {\"username\": \"...\", \"password\": \"...\"}.\n\n" +
+		"Note that Terraform stores the result in its state, so make sure the state is stored securely.")
+	clause.Flags().BoolVar(&cmd.batch, "batch", false, "Read a secret for every key of the query instead of only the path key.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments(nil)
+}
+
+// Run reads the query from stdin and writes the secrets to stdout.
+func (cmd *TerraformOutputCommand) Run() error {
+	if !cmd.io.IsInputPiped() {
+		return ErrNoDataOnStdin
+	}
+
+	var query map[string]string
+	err := json.NewDecoder(cmd.io.Input()).Decode(&query)
+	if err != nil {
+		return ErrInvalidTerraformQuery(err)
+	}
+
+	paths, err := cmd.paths(query)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	// The keys are sorted, so that secrets are read in a predictable order.
+	keys := make([]string, 0, len(paths))
+	for key := range paths {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]string, len(paths))
+	for _, key := range keys {
+		secret, err := client.Secrets().Versions().GetWithData(paths[key])
+		if err != nil {
+			return err
+		}
+		result[key] = string(secret.Data)
+	}
+
+	return json.NewEncoder(cmd.io.Output()).Encode(result)
+}
+
+// paths returns the secret paths to read, by the key under which they are written to the result.
+func (cmd *TerraformOutputCommand) paths(query map[string]string) (map[string]string, error) {
+	paths := map[string]string{}
+	if cmd.batch {
+		for key, path := range query {
+			paths[key] = path
+		}
+	} else {
+		path, ok := query["path"]
+		if !ok {
+			return nil, ErrTerraformQueryPathMissing
+		}
+		paths["value"] = path
+	}
+
+	if len(paths) == 0 {
+		return nil, ErrEmptyTerraformQuery
+	}
+
+	for _, path := range paths {
+		_, err := api.NewSecretPath(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
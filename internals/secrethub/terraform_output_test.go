@@ -0,0 +1,92 @@
+package secrethub
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestTerraformOutputCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		cmd         TerraformOutputCommand
+		in          string
+		piped       bool
+		getErr      error
+		expectedOut string
+		expectedErr error
+	}{
+		"single": {
+			in:          `{"path": "namespace/repo/secret"}`,
+			piped:       true,
+			expectedOut: `{"value":"value of namespace/repo/secret"}` + "\n",
+		},
+		"batch": {
+			cmd: TerraformOutputCommand{
+				batch: true,
+			},
+			in:          `{"username": "namespace/repo/username", "password": "namespace/repo/password"}`,
+			piped:       true,
+			expectedOut: `{"password":"value of namespace/repo/password","username":"value of namespace/repo/username"}` + "\n",
+		},
+		"not piped": {
+			expectedErr: ErrNoDataOnStdin,
+		},
+		"missing path": {
+			in:          `{"secret": "namespace/repo/secret"}`,
+			piped:       true,
+			expectedErr: ErrTerraformQueryPathMissing,
+		},
+		"empty batch": {
+			cmd: TerraformOutputCommand{
+				batch: true,
+			},
+			in:          `{}`,
+			piped:       true,
+			expectedErr: ErrEmptyTerraformQuery,
+		},
+		"secret not found": {
+			in:          `{"path": "namespace/repo/secret"}`,
+			piped:       true,
+			getErr:      api.ErrSecretNotFound,
+			expectedErr: api.ErrSecretNotFound,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					SecretService: &fakeclient.SecretService{
+						VersionService: &fakeclient.SecretVersionService{
+							GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+								if tc.getErr != nil {
+									return nil, tc.getErr
+								}
+								return &api.SecretVersion{Data: []byte("value of " + path)}, nil
+							},
+						},
+					},
+				}, nil
+			}
+
+			io := fakeui.NewIO(t)
+			io.In.Piped = tc.piped
+			io.In.Buffer = bytes.NewBufferString(tc.in)
+			tc.cmd.io = io
+
+			// Act
+			err := tc.cmd.Run()
+
+			// Assert
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+		})
+	}
+}
@@ -6,7 +6,8 @@ import (
 
 // Evaluate errors
 var (
-	ErrTemplateVarNotFound = tplError.Code("template_var_not_found").ErrorPref("no value was supplied for template variable '%s'")
+	ErrTemplateVarNotFound    = tplError.Code("template_var_not_found").ErrorPref("no value was supplied for template variable '%s'")
+	ErrDirListingNotSupported = tplError.Code("dir_listing_not_supported").Error("range blocks cannot be used here, because listing directories is not supported")
 )
 
 // Parse errors
@@ -15,9 +16,14 @@ type templateSyntaxError struct {
 	colNo  int
 	code   string
 	msg    string
+	// filename is the included file in which the error occurred, or empty when it occurred in the template itself.
+	filename string
 }
 
 func (err templateSyntaxError) Error() string {
+	if err.filename != "" {
+		return tplError.Code(err.code).Errorf("template syntax error in %s at %d:%d: %s", err.filename, err.lineNo, err.colNo, err.msg).Error()
+	}
 	return tplError.Code(err.code).Errorf("template syntax error at %d:%d: %s", err.lineNo, err.colNo, err.msg).Error()
 }
 
@@ -73,3 +79,34 @@ func ErrVariableTagNotClosed(lineNo, colNo int) error {
 		msg:    "expected the closing of a variable tag `}`, but reached the end of the template.",
 	}
 }
+
+// ErrUnexpectedBlockTag is returned when a block tag occurs at a place where it is not allowed,
+// for example an `{{ end }}` tag without a corresponding opening block tag.
+func ErrUnexpectedBlockTag(lineNo, colNo int, keyword string) error {
+	return templateSyntaxError{
+		lineNo: lineNo,
+		colNo:  colNo,
+		code:   "unexpected_block_tag",
+		msg:    fmt.Sprintf("unexpected `%s` tag", keyword),
+	}
+}
+
+// ErrInvalidBlockArguments is returned when the arguments of a block tag cannot be parsed.
+func ErrInvalidBlockArguments(lineNo, colNo int, keyword string, args string) error {
+	return templateSyntaxError{
+		lineNo: lineNo,
+		colNo:  colNo,
+		code:   "invalid_block_arguments",
+		msg:    fmt.Sprintf("invalid arguments for `%s`: '%s'", keyword, args),
+	}
+}
+
+// ErrBlockNotClosed is returned when a block is opened, but never closed.
+func ErrBlockNotClosed(lineNo, colNo int) error {
+	return templateSyntaxError{
+		lineNo: lineNo,
+		colNo:  colNo,
+		code:   "block_not_closed",
+		msg:    "expected the closing of a block `{{ end }}`, but reached the end of the template.",
+	}
+}
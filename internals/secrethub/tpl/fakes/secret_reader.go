@@ -1,17 +1,37 @@
 package fakes
 
-import "errors"
+import (
+	"errors"
 
-// FakeSecretReader implements tpl.SecretReader.
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// FakeSecretReader implements tpl.SecretReader and tpl.DirReader.
 type FakeSecretReader struct {
 	Secrets map[string]string
+	Dirs    map[string][]string
+	// Errors are returned when the secrets at their paths are read.
+	Errors map[string]error
 }
 
 // ReadSecret implements tpl.SecretReader.ReadSecret.
 func (fsr FakeSecretReader) ReadSecret(path string) (string, error) {
+	err, ok := fsr.Errors[path]
+	if ok {
+		return "", err
+	}
 	secret, ok := fsr.Secrets[path]
 	if ok {
 		return secret, nil
 	}
-	return "", errors.New("secret not found")
+	return "", api.ErrSecretNotFound
+}
+
+// ListSecrets implements tpl.DirReader.ListSecrets.
+func (fsr FakeSecretReader) ListSecrets(dirPath string) ([]string, error) {
+	names, ok := fsr.Dirs[dirPath]
+	if ok {
+		return names, nil
+	}
+	return nil, errors.New("dir not found")
 }
@@ -0,0 +1,165 @@
+package tpl
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Include errors
+var (
+	ErrIncludeCycle = tplError.Code("include_cycle").ErrorPref("template %s includes itself: %s")
+	ErrIncludeRead  = tplError.Code("include_read_error").ErrorPref("could not read included template %s: %s")
+)
+
+// includeTag matches include directives: {{ include "path/to/file" }}
+var includeTag = regexp.MustCompile(`\{\{[\t ]*include[\t ]+"([^"\n]+)"[\t ]*\}\}`)
+
+// ExpandIncludes replaces all `{{ include "path/to/partial" }}` directives in
+// the raw template with the contents of the referenced files. Included files
+// can include other files themselves. Relative paths are resolved relative to
+// the directory of the including file. The filename of the raw template is used
+// to resolve its includes; when it is empty, paths are resolved relative to the
+// working directory. An error is returned when a file (indirectly) includes itself.
+//
+// The returned source map translates the positions in errors of parsing the
+// expanded template to the files and lines they come from.
+func ExpandIncludes(raw string, filename string, readFile func(filename string) ([]byte, error)) (string, *SourceMap, error) {
+	var stack []string
+	if filename != "" {
+		abs, err := filepath.Abs(filename)
+		if err != nil {
+			return "", nil, err
+		}
+		stack = append(stack, abs)
+	}
+
+	sources := &SourceMap{}
+	var b strings.Builder
+	// The included files are named in errors, the raw template is not, as it is the template the user passed.
+	err := expandIncludes(&b, sources, raw, filename, "", readFile, stack)
+	if err != nil {
+		return "", nil, err
+	}
+	sources.expanded = b.String()
+	return sources.expanded, sources, nil
+}
+
+// expandIncludes writes the raw template with its includes expanded to b and records where every part comes from.
+// The name of the file is recorded for the parts that come from it, which is empty for the raw template itself.
+func expandIncludes(b *strings.Builder, sources *SourceMap, raw string, filename string, name string, readFile func(filename string) ([]byte, error), stack []string) error {
+	baseDir := ""
+	if filename != "" {
+		baseDir = filepath.Dir(filename)
+	}
+
+	offset := 0
+	for _, match := range includeTag.FindAllStringSubmatchIndex(raw, -1) {
+		tagStart, tagEnd := match[0], match[1]
+		if tagStart > 0 && raw[tagStart-1] == '\\' {
+			// Escaped opening delimiter, so this is not an include directive.
+			continue
+		}
+
+		include := raw[match[2]:match[3]]
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(baseDir, include)
+		}
+		abs, err := filepath.Abs(include)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range stack {
+			if f == abs {
+				return ErrIncludeCycle(abs, strings.Join(append(stack, abs), " -> "))
+			}
+		}
+
+		contents, err := readFile(include)
+		if err != nil {
+			return ErrIncludeRead(include, err)
+		}
+
+		sources.add(b, raw, offset, tagStart, name)
+		err = expandIncludes(b, sources, string(contents), include, include, readFile, append(stack[:len(stack):len(stack)], abs))
+		if err != nil {
+			return err
+		}
+		offset = tagEnd
+	}
+	sources.add(b, raw, offset, len(raw), name)
+
+	return nil
+}
+
+// SourceMap maps positions in a template with expanded includes to the files they come from.
+type SourceMap struct {
+	expanded string
+	segments []sourceSegment
+}
+
+// sourceSegment is a part of the expanded template that comes from a single file.
+type sourceSegment struct {
+	// offset is the offset of the segment in the expanded template.
+	offset int
+	// name is the name of the file, or empty for the template that includes the other files.
+	name string
+	// line and column are the position of the start of the segment in the file.
+	line   int
+	column int
+}
+
+// add writes raw[start:end] to b and records that it comes from the file with the given name.
+func (m *SourceMap) add(b *strings.Builder, raw string, start, end int, name string) {
+	if start == end {
+		return
+	}
+	line, column := position(raw[:start], 1, 1)
+	m.segments = append(m.segments, sourceSegment{
+		offset: b.Len(),
+		name:   name,
+		line:   line,
+		column: column,
+	})
+	b.WriteString(raw[start:end])
+}
+
+// Translate returns the error with the position of a syntax error in the expanded template
+// translated to the file and position it comes from. Other errors are returned as is.
+func (m *SourceMap) Translate(err error) error {
+	syntaxErr, ok := err.(templateSyntaxError)
+	if !ok || m == nil || len(m.segments) == 0 {
+		return err
+	}
+
+	offset := m.offset(syntaxErr.lineNo, syntaxErr.colNo)
+	segment := m.segments[0]
+	for _, s := range m.segments {
+		if s.offset > offset {
+			break
+		}
+		segment = s
+	}
+
+	syntaxErr.filename = segment.name
+	syntaxErr.lineNo, syntaxErr.colNo = position(m.expanded[segment.offset:offset], segment.line, segment.column)
+	return syntaxErr
+}
+
+// offset returns the offset of the given line and column in the expanded template.
+func (m *SourceMap) offset(line, column int) int {
+	l, c := 1, 1
+	for i, r := range m.expanded {
+		if (l == line && c == column) || l > line {
+			return i
+		}
+		if r == '\n' {
+			l++
+			c = 1
+		} else {
+			c++
+		}
+	}
+	return len(m.expanded)
+}
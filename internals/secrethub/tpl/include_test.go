@@ -0,0 +1,167 @@
+package tpl
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestExpandIncludes(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.OK(t, err)
+
+	abs := func(path string) string {
+		return filepath.Join(wd, path)
+	}
+
+	cases := map[string]struct {
+		raw      string
+		filename string
+		files    map[string]string
+		expected string
+		err      error
+	}{
+		"no includes": {
+			raw:      "hello {{ path/to/secret }}",
+			filename: "app.tpl",
+			expected: "hello {{ path/to/secret }}",
+		},
+		"include": {
+			raw:      "[db]\n{{ include \"partials/db.tpl\" }}\n",
+			filename: "config/app.tpl",
+			files: map[string]string{
+				"config/partials/db.tpl": "password = {{ path/to/db/password }}",
+			},
+			expected: "[db]\npassword = {{ path/to/db/password }}\n",
+		},
+		"nested include relative to including file": {
+			raw:      "{{include \"partials/a.tpl\"}}",
+			filename: "app.tpl",
+			files: map[string]string{
+				"partials/a.tpl":        "a{{ include \"nested/b.tpl\" }}",
+				"partials/nested/b.tpl": "b",
+			},
+			expected: "ab",
+		},
+		"include from stdin": {
+			raw: "{{ include \"a.tpl\" }}",
+			files: map[string]string{
+				"a.tpl": "a",
+			},
+			expected: "a",
+		},
+		"escaped include": {
+			raw:      "\\{{ include \"a.tpl\" }}",
+			filename: "app.tpl",
+			expected: "\\{{ include \"a.tpl\" }}",
+		},
+		"same file included twice": {
+			raw:      "{{ include \"a.tpl\" }}{{ include \"a.tpl\" }}",
+			filename: "app.tpl",
+			files: map[string]string{
+				"a.tpl": "a",
+			},
+			expected: "aa",
+		},
+		"cycle": {
+			raw:      "{{ include \"a.tpl\" }}",
+			filename: "app.tpl",
+			files: map[string]string{
+				"a.tpl": "{{ include \"app.tpl\" }}",
+			},
+			err: ErrIncludeCycle(abs("app.tpl"), abs("app.tpl")+" -> "+abs("a.tpl")+" -> "+abs("app.tpl")),
+		},
+		"missing file": {
+			raw:      "{{ include \"a.tpl\" }}",
+			filename: "app.tpl",
+			err:      ErrIncludeRead("a.tpl", errors.New("file not found")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			readFile := func(filename string) ([]byte, error) {
+				contents, ok := tc.files[filepath.ToSlash(filename)]
+				if !ok {
+					return nil, errors.New("file not found")
+				}
+				return []byte(contents), nil
+			}
+
+			actual, _, err := ExpandIncludes(tc.raw, tc.filename, readFile)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestSourceMap_Translate(t *testing.T) {
+	// inFile returns the syntax error as it occurred in the included file with the given name.
+	inFile := func(err error, filename string) error {
+		syntaxErr := err.(templateSyntaxError)
+		syntaxErr.filename = filename
+		return syntaxErr
+	}
+
+	cases := map[string]struct {
+		raw      string
+		files    map[string]string
+		expected error
+	}{
+		"no includes": {
+			raw:      "a\n{{ end }}",
+			expected: ErrUnexpectedBlockTag(2, 1, "end"),
+		},
+		"error after include": {
+			raw: "[db]\n{{ include \"db.tpl\" }}\nfoo {{ end }}",
+			files: map[string]string{
+				"db.tpl": "user = root\npassword = secret\n",
+			},
+			expected: ErrUnexpectedBlockTag(3, 5, "end"),
+		},
+		"error in include": {
+			raw: "[db]\n{{ include \"db.tpl\" }}\n",
+			files: map[string]string{
+				"db.tpl": "user = root\n  {{ end }}\n",
+			},
+			expected: inFile(ErrUnexpectedBlockTag(2, 3, "end"), "db.tpl"),
+		},
+		"error in nested include": {
+			raw: "{{ include \"a.tpl\" }}",
+			files: map[string]string{
+				"a.tpl": "a {{ include \"b.tpl\" }} {{ end }}",
+				"b.tpl": "b\nb",
+			},
+			expected: inFile(ErrUnexpectedBlockTag(1, 25, "end"), "a.tpl"),
+		},
+		"error on line of include": {
+			raw: "x {{ include \"a.tpl\" }} {{ end }}",
+			files: map[string]string{
+				"a.tpl": "a",
+			},
+			expected: ErrUnexpectedBlockTag(1, 25, "end"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			readFile := func(filename string) ([]byte, error) {
+				contents, ok := tc.files[filepath.ToSlash(filename)]
+				if !ok {
+					return nil, errors.New("file not found")
+				}
+				return []byte(contents), nil
+			}
+
+			expanded, sources, err := ExpandIncludes(tc.raw, "", readFile)
+			assert.OK(t, err)
+
+			_, err = NewV3Parser().Parse(expanded, 1, 1)
+			assert.Equal(t, sources.Translate(err), tc.expected)
+		})
+	}
+}
@@ -301,6 +301,9 @@ func (p *v2Parser) parseVar() (node, error) {
 // of the closing delimiter of the secret tag ('}').
 func (p *v2Parser) parseSecret() (node, error) {
 	path := []node{}
+	// inVersion is set once the ':' that separates the path from the version is read.
+	// The version can be a constraint, such as >=3 <5, which can contain spaces.
+	inVersion := false
 
 	checkError := func(err error) error {
 		if err == io.EOF {
@@ -360,6 +363,11 @@ func (p *v2Parser) parseSecret() (node, error) {
 				return nil, checkError(err)
 			}
 
+			if inVersion && p.isVersionConstraintRune(p.next) {
+				path = append(path, character(' '))
+				continue
+			}
+
 			if p.next != token.RBracket {
 				return nil, ErrUnexpectedCharacter(p.lineNo, p.columnNo+1, p.next, token.RBracket)
 			}
@@ -387,7 +395,10 @@ func (p *v2Parser) parseSecret() (node, error) {
 			return nil, ErrUnexpectedCharacter(p.lineNo, p.columnNo+1, p.next, token.RBracket)
 		}
 
-		if p.isSecretPathRune(p.current) {
+		if p.isSecretPathRune(p.current) || inVersion && p.isVersionConstraintRune(p.current) {
+			if p.current == ':' {
+				inVersion = true
+			}
 			path = append(path, character(p.current))
 			continue
 		}
@@ -402,6 +413,12 @@ func (p v2Parser) isSecretPathRune(r rune) bool {
 	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' || r == '/' || r == ':'
 }
 
+// isVersionConstraintRune returns whether the given rune is allowed to be used in
+// a version constraint after the ':' of a secret path.
+func (p v2Parser) isVersionConstraintRune(r rune) bool {
+	return unicode.IsDigit(r) || r == '<' || r == '>' || r == '=' || r == '!' || r == '~'
+}
+
 // isVariableRune returns whether the given rune is allowed to be used in a template variable key.
 func (p v2Parser) isVariableRune(r rune) bool {
 	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
@@ -536,6 +536,14 @@ func TestParserV2_parse(t *testing.T) {
 			input: "{{ secret\t with tab and space }}",
 			err:   ErrUnexpectedCharacter(1, 12, 'w', '}'),
 		},
+		"illegal constraint character in secret path": {
+			input: "{{ a>b }}",
+			err:   ErrIllegalSecretCharacter(1, 5, '>'),
+		},
+		"space in secret path before version": {
+			input: "{{ a b:1 }}",
+			err:   ErrUnexpectedCharacter(1, 6, 'b', '}'),
+		},
 		"illegal variable character": {
 			input: "${ var@var }",
 			err:   ErrIllegalVariableCharacter(1, 7, '@'),
@@ -662,6 +670,20 @@ func TestV2(t *testing.T) {
 			},
 			expected: "hello world",
 		},
+		"version constraint": {
+			raw: "hello {{ company/helloworld/greeting:>=3  <5 }}",
+			secrets: map[string]string{
+				"company/helloworld/greeting:>=3 <5": "world",
+			},
+			expected: "hello world",
+		},
+		"version constraint without spaces": {
+			raw: "hello {{company/helloworld/greeting:~2}}",
+			secrets: map[string]string{
+				"company/helloworld/greeting:~2": "world",
+			},
+			expected: "hello world",
+		},
 		"missing var": {
 			raw:  "hello {{ ${app}/greeting }}",
 			vars: map[string]string{},
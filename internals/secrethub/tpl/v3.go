@@ -0,0 +1,391 @@
+package tpl
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// NewV3Parser returns a parser for the v3 template syntax.
+//
+// V3 templates support everything v2 templates support and add
+// block tags for conditionals and loops:
+//
+//	{{ if ${var} }} ... {{ else }} ... {{ end }}
+//	{{ if exists path/to/secret }} ... {{ end }}
+//	{{ range name in path/to/dir }} ... {{ end }}
+//
+// A range block is rendered once for every secret directly inside the given
+// directory, in alphabetical order. Within the block, the name of the current
+// secret is available as the template variable given in the range tag, so the
+// secret itself can be referenced with {{ path/to/dir/${name} }}.
+func NewV3Parser() Parser {
+	return parserV3{}
+}
+
+// DirReader lists the names of the secrets directly inside a directory.
+// The SecretReader passed to the evaluation of a v3 template should also
+// implement DirReader for range blocks to be evaluated.
+type DirReader interface {
+	ListSecrets(dirPath string) ([]string, error)
+}
+
+type parserV3 struct{}
+
+// blockTag matches the block tags of the v3 syntax.
+var blockTag = regexp.MustCompile(`\{\{[\t ]*(if|else|end|range)(?:[\t ]+([^\n]*?))?[\t ]*\}\}`)
+
+// Parse parses a v3 secret template from a raw string.
+//
+// Syntax rules, in addition to those of the v2 syntax:
+//   - `{{ if ${var} }}` starts a conditional block that is rendered when the variable is not empty.
+//   - `{{ if exists path/to/secret }}` starts a conditional block that is rendered when the secret exists.
+//   - `{{ range name in path/to/dir }}` starts a block that is rendered once for every secret in the directory.
+//   - `{{ else }}` separates the alternative of a conditional block.
+//   - `{{ end }}` closes the innermost open block.
+//   - Blocks can be nested.
+func (p parserV3) Parse(raw string, line, column int) (Template, error) {
+	root := &blockV3{}
+	stack := []*blockV3{root}
+
+	offset := 0
+	for _, match := range blockTag.FindAllStringSubmatchIndex(raw, -1) {
+		tagStart, tagEnd := match[0], match[1]
+		if tagStart > 0 && raw[tagStart-1] == '\\' {
+			// Escaped opening delimiter, so this is not a block tag.
+			continue
+		}
+		keyword := raw[match[2]:match[3]]
+		args := ""
+		if match[4] >= 0 {
+			args = raw[match[4]:match[5]]
+		}
+
+		tagLine, tagColumn := position(raw[:tagStart], line, column)
+
+		current := stack[len(stack)-1]
+		textLine, textColumn := position(raw[:offset], line, column)
+		err := current.appendText(raw[offset:tagStart], textLine, textColumn)
+		if err != nil {
+			return nil, err
+		}
+		offset = tagEnd
+
+		switch keyword {
+		case "if":
+			cond, err := parseCondition(args, tagLine, tagColumn)
+			if err != nil {
+				return nil, err
+			}
+			block := &blockV3{condition: cond, line: tagLine, column: tagColumn}
+			current.appendNode(block)
+			stack = append(stack, block)
+		case "range":
+			loop, err := parseRange(args, tagLine, tagColumn)
+			if err != nil {
+				return nil, err
+			}
+			block := &blockV3{loop: loop, line: tagLine, column: tagColumn}
+			current.appendNode(block)
+			stack = append(stack, block)
+		case "else":
+			if current.condition == nil || current.inElse || args != "" {
+				return nil, ErrUnexpectedBlockTag(tagLine, tagColumn, keyword)
+			}
+			current.inElse = true
+		case "end":
+			if len(stack) == 1 || args != "" {
+				return nil, ErrUnexpectedBlockTag(tagLine, tagColumn, keyword)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	if len(stack) > 1 {
+		return nil, ErrBlockNotClosed(stack[len(stack)-1].line, stack[len(stack)-1].column)
+	}
+
+	textLine, textColumn := position(raw[:offset], line, column)
+	err := root.appendText(raw[offset:], textLine, textColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	return templateV3{root: root}, nil
+}
+
+// position returns the line and column directly after the given text,
+// when the text starts at the given line and column.
+func position(text string, line, column int) (int, int) {
+	for _, r := range text {
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
+
+// condition decides whether the body of an if block is rendered.
+type condition interface {
+	holds(ctx context) (bool, error)
+}
+
+// variableCondition holds when the variable has a non-empty value.
+type variableCondition struct {
+	key string
+}
+
+func (c variableCondition) holds(ctx context) (bool, error) {
+	val, err := ctx.varReader.ReadVariable(c.key)
+	if err != nil {
+		return false, err
+	}
+	return val != "", nil
+}
+
+// existsCondition holds when the secret exists. Other errors than the secret
+// not existing, such as a denied permission, are returned.
+type existsCondition struct {
+	path secret
+}
+
+func (c existsCondition) holds(ctx context) (bool, error) {
+	_, err := c.path.evaluate(ctx)
+	if err == api.ErrSecretNotFound || err == api.ErrSecretVersionNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// rangeLoop iterates over the secrets in a directory.
+type rangeLoop struct {
+	key string
+	dir []node
+}
+
+var variableTag = regexp.MustCompile(`^\$\{?[\t ]*([_a-zA-Z][_a-zA-Z0-9]*)[\t ]*\}?$`)
+
+func parseCondition(args string, line, column int) (condition, error) {
+	fields := strings.Fields(args)
+	switch {
+	case len(fields) == 1 && variableTag.MatchString(fields[0]):
+		key := variableTag.FindStringSubmatch(fields[0])[1]
+		return variableCondition{key: strings.ToLower(key)}, nil
+	case len(fields) == 2 && fields[0] == "exists":
+		path, err := parsePath(fields[1], line, column)
+		if err != nil {
+			return nil, err
+		}
+		return existsCondition{path: secret{path: path}}, nil
+	}
+	return nil, ErrInvalidBlockArguments(line, column, "if", args)
+}
+
+func parseRange(args string, line, column int) (*rangeLoop, error) {
+	fields := strings.Fields(args)
+	if len(fields) != 3 || fields[1] != "in" {
+		return nil, ErrInvalidBlockArguments(line, column, "range", args)
+	}
+
+	for i, r := range fields[0] {
+		if !(unicode.IsLetter(r) || r == '_' || (i > 0 && unicode.IsDigit(r))) {
+			return nil, ErrIllegalVariableCharacter(line, column, r)
+		}
+	}
+
+	dir, err := parsePath(fields[2], line, column)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rangeLoop{
+		key: strings.ToLower(fields[0]),
+		dir: dir,
+	}, nil
+}
+
+// parsePath parses a path that can contain variables, by reusing the v2 secret tag parser.
+func parsePath(raw string, line, column int) ([]node, error) {
+	parser := newV2Parser(bytes.NewBufferString("{{"+raw+"}}"), line, column)
+	nodes, err := parser.parse()
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) != 1 {
+		return nil, ErrInvalidBlockArguments(line, column, "path", raw)
+	}
+	s, ok := nodes[0].(secret)
+	if !ok {
+		return nil, ErrInvalidBlockArguments(line, column, "path", raw)
+	}
+	return s.path, nil
+}
+
+// blockV3 is a list of nodes that is optionally rendered conditionally or repeatedly.
+type blockV3 struct {
+	nodes []node
+
+	condition condition
+	inElse    bool
+	elseNodes []node
+
+	loop *rangeLoop
+
+	line   int
+	column int
+}
+
+// appendText parses the given text with the v2 syntax and appends the result to the block.
+func (b *blockV3) appendText(text string, line, column int) error {
+	if text == "" {
+		return nil
+	}
+	parser := newV2Parser(bytes.NewBufferString(text), line, column)
+	nodes, err := parser.parse()
+	if err != nil {
+		return err
+	}
+	b.appendNode(nodes...)
+	return nil
+}
+
+// appendNode appends nodes to the block, or to its else branch if the else tag has been parsed.
+func (b *blockV3) appendNode(nodes ...node) {
+	if b.inElse {
+		b.elseNodes = append(b.elseNodes, nodes...)
+	} else {
+		b.nodes = append(b.nodes, nodes...)
+	}
+}
+
+func (b *blockV3) evaluate(ctx context) (string, error) {
+	if b.condition != nil {
+		ok, err := b.condition.holds(ctx)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return evaluateNodes(ctx, b.elseNodes)
+		}
+		return evaluateNodes(ctx, b.nodes)
+	}
+
+	if b.loop != nil {
+		return b.evaluateLoop(ctx)
+	}
+
+	return evaluateNodes(ctx, b.nodes)
+}
+
+func (b *blockV3) evaluateLoop(ctx context) (string, error) {
+	dirReader, ok := ctx.secretReader.(DirReader)
+	if !ok {
+		return "", ErrDirListingNotSupported
+	}
+
+	dirPath, err := evaluateNodes(ctx, b.loop.dir)
+	if err != nil {
+		return "", err
+	}
+
+	names, err := dirReader.ListSecrets(dirPath)
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(names)
+
+	var buffer bytes.Buffer
+	for _, name := range names {
+		loopCtx := context{
+			varReader: scopedVariableReader{
+				key:    b.loop.key,
+				value:  name,
+				parent: ctx.varReader,
+			},
+			secretReader: ctx.secretReader,
+		}
+		eval, err := evaluateNodes(loopCtx, b.nodes)
+		if err != nil {
+			return "", err
+		}
+		buffer.WriteString(eval)
+	}
+	return buffer.String(), nil
+}
+
+func evaluateNodes(ctx context, nodes []node) (string, error) {
+	var buffer bytes.Buffer
+	for _, n := range nodes {
+		eval, err := n.evaluate(ctx)
+		if err != nil {
+			return "", err
+		}
+		buffer.WriteString(eval)
+	}
+	return buffer.String(), nil
+}
+
+// scopedVariableReader sets a single variable on top of a parent variable reader.
+type scopedVariableReader struct {
+	key    string
+	value  string
+	parent VariableReader
+}
+
+func (r scopedVariableReader) ReadVariable(name string) (string, error) {
+	if name == r.key {
+		return r.value, nil
+	}
+	return r.parent.ReadVariable(name)
+}
+
+type templateV3 struct {
+	root *blockV3
+}
+
+// Evaluate renders a template. It replaces all variable- and secret tags in the template
+// and renders the conditional and range blocks.
+func (t templateV3) Evaluate(varReader VariableReader, sr SecretReader) (string, error) {
+	return t.root.evaluate(context{
+		varReader:    varReader,
+		secretReader: sr,
+	})
+}
+
+func (t templateV3) ContainsSecrets() bool {
+	return t.root.containsSecrets()
+}
+
+func (b *blockV3) containsSecrets() bool {
+	if b.loop != nil {
+		return true
+	}
+	if b.condition != nil {
+		if _, ok := b.condition.(existsCondition); ok {
+			return true
+		}
+	}
+	for _, nodes := range [][]node{b.nodes, b.elseNodes} {
+		for _, n := range nodes {
+			switch v := n.(type) {
+			case secret:
+				return true
+			case *blockV3:
+				if v.containsSecrets() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package tpl
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl/fakes"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestV3(t *testing.T) {
+	cases := map[string]struct {
+		raw        string
+		vars       map[string]string
+		secrets    map[string]string
+		secretErrs map[string]error
+		dirs       map[string][]string
+
+		expected string
+		parseErr error
+		evalErr  error
+	}{
+		"v2 syntax": {
+			raw: "hello {{ ${app}/greeting }}",
+			vars: map[string]string{
+				"app": "company/helloworld",
+			},
+			secrets: map[string]string{
+				"company/helloworld/greeting": "world",
+			},
+			expected: "hello world",
+		},
+		"if variable set": {
+			raw: "{{ if ${debug} }}debug=true{{ end }}",
+			vars: map[string]string{
+				"debug": "1",
+			},
+			expected: "debug=true",
+		},
+		"if variable empty": {
+			raw: "{{ if $debug }}debug=true{{ else }}debug=false{{ end }}",
+			vars: map[string]string{
+				"debug": "",
+			},
+			expected: "debug=false",
+		},
+		"if exists": {
+			raw: "{{ if exists company/app/password }}password={{ company/app/password }}{{ end }}",
+			secrets: map[string]string{
+				"company/app/password": "secret",
+			},
+			expected: "password=secret",
+		},
+		"if not exists": {
+			raw:      "{{ if exists company/app/password }}password={{ company/app/password }}{{ else }}no password{{ end }}",
+			expected: "no password",
+		},
+		"if exists with error": {
+			raw: "{{ if exists company/app/password }}password={{ company/app/password }}{{ end }}",
+			secretErrs: map[string]error{
+				"company/app/password": api.ErrForbidden,
+			},
+			evalErr: api.ErrForbidden,
+		},
+		"range": {
+			raw: "{{ range name in ${app}/db }}${name}={{ ${app}/db/${name} }}\n{{ end }}",
+			vars: map[string]string{
+				"app": "company/app",
+			},
+			secrets: map[string]string{
+				"company/app/db/user":     "root",
+				"company/app/db/password": "secret",
+			},
+			dirs: map[string][]string{
+				"company/app/db": {"user", "password"},
+			},
+			expected: "password=secret\nuser=root\n",
+		},
+		"nested blocks": {
+			raw: "{{ range name in company/app }}{{ if ${verbose} }}${name}:{{ end }}{{ company/app/${name} }};{{ end }}",
+			vars: map[string]string{
+				"verbose": "yes",
+			},
+			secrets: map[string]string{
+				"company/app/a": "1",
+				"company/app/b": "2",
+			},
+			dirs: map[string][]string{
+				"company/app": {"a", "b"},
+			},
+			expected: "a:1;b:2;",
+		},
+		"escaped block tag": {
+			raw:      `\{{ end }}`,
+			expected: "{{ end }}",
+		},
+		"range over missing dir": {
+			raw:     "{{ range name in company/app }}{{ end }}",
+			evalErr: errors.New("dir not found"),
+		},
+		"end without block": {
+			raw:      "foo\n{{ end }}",
+			parseErr: ErrUnexpectedBlockTag(2, 1, "end"),
+		},
+		"else without if": {
+			raw:      "{{ range name in company/app }}{{ else }}{{ end }}",
+			parseErr: ErrUnexpectedBlockTag(1, 32, "else"),
+		},
+		"block not closed": {
+			raw:      "foo {{ if $var }}bar",
+			parseErr: ErrBlockNotClosed(1, 5),
+		},
+		"invalid range": {
+			raw:      "{{ range company/app }}{{ end }}",
+			parseErr: ErrInvalidBlockArguments(1, 1, "range", "company/app"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := NewV3Parser().Parse(tc.raw, 1, 1)
+			assert.Equal(t, err, tc.parseErr)
+
+			if err != nil {
+				return
+			}
+
+			actual, err := parsed.Evaluate(fakes.FakeVariableReader{Variables: tc.vars}, fakes.FakeSecretReader{Secrets: tc.secrets, Dirs: tc.dirs, Errors: tc.secretErrs})
+			assert.Equal(t, err, tc.evalErr)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
@@ -36,7 +36,10 @@ func (cmd *TreeCommand) Run() error {
 		return err
 	}
 
+	progress := ui.NewProgress(cmd.io, "Fetching "+cmd.path.Value(), 0)
+	progress.Start()
 	t, err := client.Dirs().GetTree(cmd.path.Value(), -1, false)
+	progress.Stop()
 	if err != nil {
 		return err
 	}
@@ -139,6 +139,10 @@ func TestSimpleTree(t *testing.T) {
 }
 
 func TestTreeColoring(t *testing.T) {
+	noColor := color.NoColor
+	defer func() {
+		color.NoColor = noColor
+	}()
 	color.NoColor = false
 	uuid0, _ := uuid.FromString("0")
 	uuid1, _ := uuid.FromString("1")
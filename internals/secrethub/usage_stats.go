@@ -0,0 +1,180 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/atomicfile"
+
+	"github.com/spf13/cobra"
+)
+
+const usageStatsFileName = "stats.json"
+
+// Errors
+var (
+	ErrInvalidUsageStats = errMain.Code("invalid_stats").ErrorPref("could not parse the usage statistics file %s: %s")
+)
+
+// usageStatsFile is the content of the usage statistics file. Its existence means
+// that recording usage statistics is enabled.
+type usageStatsFile struct {
+	Since    time.Time                     `json:"since"`
+	Commands map[string]*commandUsageStats `json:"commands"`
+}
+
+// commandUsageStats are the usage statistics of a single command. Only the name of
+// the command is recorded, never its arguments or flags.
+type commandUsageStats struct {
+	Invocations     int       `json:"invocations"`
+	Failures        int       `json:"failures"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	LastUsed        time.Time `json:"last_used"`
+}
+
+// averageDuration returns the average duration of an invocation of the command.
+func (s commandUsageStats) averageDuration() time.Duration {
+	if s.Invocations == 0 {
+		return 0
+	}
+	return time.Duration(s.DurationSeconds / float64(s.Invocations) * float64(time.Second))
+}
+
+// UsageStats counts how often every command is run and how long it takes in a file in the
+// configuration directory. Nothing is recorded until it is enabled with the stats command
+// and the statistics never leave the device.
+type UsageStats struct {
+	dir     func() string
+	logger  cli.Logger
+	now     func() time.Time
+	start   time.Time
+	command string
+}
+
+// NewUsageStats creates a new UsageStats that stores its statistics in the configuration
+// directory of the given credential config.
+func NewUsageStats(store CredentialConfig) *UsageStats {
+	return &UsageStats{
+		dir: func() string {
+			return store.ConfigDir().Path()
+		},
+		logger: cli.NewLogger(),
+		now:    time.Now,
+	}
+}
+
+// Register starts measuring before a command is executed.
+func (s *UsageStats) Register(app *cli.App) {
+	app.Root.AddPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+		s.start = s.now()
+		s.command = strings.TrimPrefix(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()), " ")
+		return nil
+	})
+}
+
+// Record adds the executed command with its result to the usage statistics. It does nothing
+// when recording is disabled, no command has been executed or the command is the stats command
+// itself. The statistics are only read by the user with `secrethub stats`, so statistics that
+// cannot be written, e.g. to a read-only configuration directory, are logged as a warning and
+// do not fail the command.
+func (s *UsageStats) Record(commandErr error) {
+	if s.start.IsZero() || s.command == "" || s.command == "stats" || strings.HasPrefix(s.command, "stats ") {
+		return
+	}
+
+	stats, err := s.read()
+	if err != nil {
+		s.logger.Warningf("could not record usage statistics: %s", err)
+		return
+	} else if stats == nil {
+		return
+	}
+
+	command, ok := stats.Commands[s.command]
+	if !ok {
+		command = &commandUsageStats{}
+		stats.Commands[s.command] = command
+	}
+	command.Invocations++
+	if commandErr != nil {
+		command.Failures++
+	}
+	command.DurationSeconds += s.now().Sub(s.start).Seconds()
+	command.LastUsed = s.now().UTC()
+
+	err = s.write(stats)
+	if err != nil {
+		s.logger.Warningf("could not record usage statistics: %s", err)
+	}
+}
+
+// path returns the location of the usage statistics file.
+func (s *UsageStats) path() string {
+	return filepath.Join(s.dir(), usageStatsFileName)
+}
+
+// enabled returns whether usage statistics are recorded.
+func (s *UsageStats) enabled() (bool, error) {
+	_, err := os.Stat(s.path())
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// read returns the recorded usage statistics or nil when recording is disabled.
+func (s *UsageStats) read() (*usageStatsFile, error) {
+	raw, err := os.ReadFile(s.path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, ErrCannotReadFile(s.path(), err)
+	}
+
+	var stats usageStatsFile
+	err = json.Unmarshal(raw, &stats)
+	if err != nil {
+		return nil, ErrInvalidUsageStats(s.path(), err)
+	}
+	if stats.Commands == nil {
+		stats.Commands = make(map[string]*commandUsageStats)
+	}
+	return &stats, nil
+}
+
+// enable starts recording usage statistics. Statistics that were already recorded are kept.
+func (s *UsageStats) enable() error {
+	enabled, err := s.enabled()
+	if err != nil || enabled {
+		return err
+	}
+	return s.write(&usageStatsFile{
+		Since:    s.now().UTC(),
+		Commands: make(map[string]*commandUsageStats),
+	})
+}
+
+// disable stops recording usage statistics and removes the statistics that were recorded.
+func (s *UsageStats) disable() error {
+	err := os.Remove(s.path())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// write atomically replaces the usage statistics file.
+func (s *UsageStats) write(stats *usageStatsFile) error {
+	raw, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(s.path(), raw, 0600)
+}
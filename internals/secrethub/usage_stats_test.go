@@ -0,0 +1,84 @@
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestUsageStats_Record(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	warnings := &bytes.Buffer{}
+	stats := &UsageStats{
+		dir: func() string {
+			return dir
+		},
+		logger: cli.NewLoggerWithOutput(warnings),
+		now: func() time.Time {
+			return now
+		},
+	}
+
+	run := func(command string, duration time.Duration, err error) {
+		stats.start = start
+		stats.command = command
+		now = start.Add(duration)
+		stats.Record(err)
+	}
+
+	// Nothing is recorded before recording is enabled.
+	run("read", time.Second, nil)
+	recorded, err := stats.read()
+	assert.OK(t, err)
+	assert.Equal(t, recorded, (*usageStatsFile)(nil))
+
+	now = start
+	assert.OK(t, stats.enable())
+
+	run("read", time.Second, nil)
+	run("read", 2*time.Second, errors.New("secret not found"))
+	run("write", 500*time.Millisecond, nil)
+	// The stats command itself is not recorded.
+	run("stats", time.Second, nil)
+
+	recorded, err = stats.read()
+	assert.OK(t, err)
+	assert.Equal(t, warnings.String(), "")
+	assert.Equal(t, recorded, &usageStatsFile{
+		Since: start,
+		Commands: map[string]*commandUsageStats{
+			"read": {
+				Invocations:     2,
+				Failures:        1,
+				DurationSeconds: 3,
+				LastUsed:        start.Add(2 * time.Second),
+			},
+			"write": {
+				Invocations:     1,
+				DurationSeconds: 0.5,
+				LastUsed:        start.Add(500 * time.Millisecond),
+			},
+		},
+	})
+	assert.Equal(t, recorded.Commands["read"].averageDuration(), 1500*time.Millisecond)
+
+	// Enabling again keeps the recorded statistics.
+	assert.OK(t, stats.enable())
+	recorded, err = stats.read()
+	assert.OK(t, err)
+	assert.Equal(t, recorded.Commands["read"].Invocations, 2)
+
+	assert.OK(t, stats.disable())
+	enabled, err := stats.enabled()
+	assert.OK(t, err)
+	assert.Equal(t, enabled, false)
+}
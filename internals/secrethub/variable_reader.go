@@ -3,6 +3,7 @@ package secrethub
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/validation"
 
@@ -55,6 +56,9 @@ type promptMissingVariableReader struct {
 	reader  tpl.VariableReader
 	io      ui.IO
 	answers map[string]string
+	// mutex ensures only one question is asked at a time when
+	// variables are read concurrently.
+	mutex sync.Mutex
 }
 
 func newPromptMissingVariableReader(reader tpl.VariableReader, io ui.IO) tpl.VariableReader {
@@ -72,6 +76,9 @@ func (p *promptMissingVariableReader) ReadVariable(name string) (string, error)
 		return variable, err
 	}
 
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
 	variable, ok := p.answers[name]
 	if ok {
 		return variable, nil
@@ -4,4 +4,7 @@ package secrethub
 var (
 	Version string
 	Commit  string
+	// ReleaseSigningKey is the base64 encoded Ed25519 public key that the checksums of releases
+	// are signed with. self-update only installs releases with a valid signature for this key.
+	ReleaseSigningKey string
 )
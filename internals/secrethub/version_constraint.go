@@ -0,0 +1,129 @@
+package secrethub
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrInvalidVersionConstraint = errMain.Code("invalid_version_constraint").ErrorPref("invalid version constraint %q: each condition must be an operator (=, !=, >, >=, <, <= or ~) followed by a version number")
+	ErrNoVersionMatches         = errMain.Code("no_matching_version").ErrorPref("no version of %s matches %s")
+)
+
+// versionCondition is a single comparison with a version number, such as >=3.
+type versionCondition struct {
+	operator string
+	version  int
+}
+
+// matches returns whether the given version satisfies the condition.
+func (c versionCondition) matches(version int) bool {
+	switch c.operator {
+	case "=":
+		return version == c.version
+	case "!=":
+		return version != c.version
+	case ">":
+		return version > c.version
+	case ">=", "~":
+		return version >= c.version
+	case "<":
+		return version < c.version
+	case "<=":
+		return version <= c.version
+	}
+	return false
+}
+
+// versionConstraint is a list of conditions that a version must all satisfy.
+type versionConstraint []versionCondition
+
+// versionOperators are the supported operators. Longer operators come first,
+// so that >= is not parsed as > followed by =.
+var versionOperators = []string{">=", "<=", "!=", ">", "<", "=", "~"}
+
+// isVersionConstraint returns whether the version of a secret path is a constraint
+// instead of a version number or latest.
+func isVersionConstraint(version string) bool {
+	return strings.ContainsAny(version, "<>=!~")
+}
+
+// parseVersionConstraint parses a space separated list of conditions, such as ">=3 <5".
+// The ~ operator means "this version or a newer one", so ~2 is the same as >=2.
+func parseVersionConstraint(raw string) (versionConstraint, error) {
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return nil, ErrInvalidVersionConstraint(raw)
+	}
+
+	constraint := make(versionConstraint, len(fields))
+	for i, field := range fields {
+		operator := ""
+		for _, op := range versionOperators {
+			if strings.HasPrefix(field, op) {
+				operator = op
+				break
+			}
+		}
+		if operator == "" {
+			return nil, ErrInvalidVersionConstraint(raw)
+		}
+
+		version, err := strconv.Atoi(strings.TrimPrefix(field, operator))
+		if err != nil || version < 1 {
+			return nil, ErrInvalidVersionConstraint(raw)
+		}
+		constraint[i] = versionCondition{operator: operator, version: version}
+	}
+	return constraint, nil
+}
+
+// matches returns whether the given version satisfies all conditions.
+func (c versionConstraint) matches(version int) bool {
+	for _, condition := range c {
+		if !condition.matches(version) {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveVersionConstraint returns the path of the highest version of the secret that
+// satisfies the constraint after the ':' of the given path. Versions that are not ok,
+// e.g. because they are flagged, are never selected. Paths without a constraint are
+// returned unchanged.
+func resolveVersionConstraint(client secrethub.ClientInterface, path string) (string, error) {
+	i := strings.LastIndex(path, ":")
+	if i == -1 || !isVersionConstraint(path[i+1:]) {
+		return path, nil
+	}
+	secretPath, raw := path[:i], path[i+1:]
+
+	constraint, err := parseVersionConstraint(raw)
+	if err != nil {
+		return "", err
+	}
+
+	versions, err := client.Secrets().Versions().ListWithoutData(secretPath)
+	if err != nil {
+		return "", err
+	}
+
+	highest := 0
+	for _, version := range versions {
+		if version.Status != api.StatusOK || !constraint.matches(version.Version) {
+			continue
+		}
+		if version.Version > highest {
+			highest = version.Version
+		}
+	}
+	if highest == 0 {
+		return "", ErrNoVersionMatches(secretPath, strings.Join(strings.Fields(raw), " "))
+	}
+	return secretPath + ":" + strconv.Itoa(highest), nil
+}
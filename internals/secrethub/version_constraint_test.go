@@ -0,0 +1,88 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestResolveVersionConstraint(t *testing.T) {
+	client := fakeclient.Client{
+		SecretService: &fakeclient.SecretService{
+			VersionService: &fakeclient.SecretVersionService{
+				ListWithoutDataFunc: func(path string) ([]*api.SecretVersion, error) {
+					return []*api.SecretVersion{
+						{Version: 1, Status: api.StatusOK},
+						{Version: 2, Status: api.StatusOK},
+						{Version: 3, Status: api.StatusOK},
+						{Version: 4, Status: api.StatusOK},
+						{Version: 5, Status: "flagged"},
+						{Version: 6, Status: api.StatusOK},
+					}, nil
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		path     string
+		expected string
+		err      error
+	}{
+		"no version": {
+			path:     "namespace/repo/secret",
+			expected: "namespace/repo/secret",
+		},
+		"version number": {
+			path:     "namespace/repo/secret:2",
+			expected: "namespace/repo/secret:2",
+		},
+		"latest": {
+			path:     "namespace/repo/secret:latest",
+			expected: "namespace/repo/secret:latest",
+		},
+		"range": {
+			path:     "namespace/repo/secret:>=2 <4",
+			expected: "namespace/repo/secret:3",
+		},
+		"flagged version is skipped": {
+			path:     "namespace/repo/secret:<=5",
+			expected: "namespace/repo/secret:4",
+		},
+		"tilde": {
+			path:     "namespace/repo/secret:~2",
+			expected: "namespace/repo/secret:6",
+		},
+		"not equal": {
+			path:     "namespace/repo/secret:!=6",
+			expected: "namespace/repo/secret:4",
+		},
+		"equal": {
+			path:     "namespace/repo/secret:=1",
+			expected: "namespace/repo/secret:1",
+		},
+		"no match": {
+			path: "namespace/repo/secret:>6",
+			err:  ErrNoVersionMatches("namespace/repo/secret", ">6"),
+		},
+		"missing operator": {
+			path: "namespace/repo/secret:>=2 4",
+			err:  ErrInvalidVersionConstraint(">=2 4"),
+		},
+		"missing version": {
+			path: "namespace/repo/secret:>=",
+			err:  ErrInvalidVersionConstraint(">="),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := resolveVersionConstraint(client, tc.path)
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
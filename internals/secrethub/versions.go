@@ -0,0 +1,26 @@
+package secrethub
+
+import (
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+)
+
+// VersionsCommand handles operations on the versions of secrets.
+type VersionsCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewVersionsCommand creates a new VersionsCommand.
+func NewVersionsCommand(io ui.IO, newClient newClientFunc) *VersionsCommand {
+	return &VersionsCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command and its sub-commands on the provided Registerer.
+func (cmd *VersionsCommand) Register(r cli.Registerer) {
+	clause := r.Command("versions", "Manage the versions of secrets.")
+	NewVersionsPruneCommand(cmd.io, cmd.newClient).Register(clause)
+}
@@ -0,0 +1,218 @@
+package secrethub
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrNoPrunePolicy      = errMain.Code("no_prune_policy").Error("set --keep-last, --older-than or both to select the versions to remove")
+	ErrInvalidKeepLast    = errMain.Code("invalid_keep_last").ErrorPref("invalid value for --keep-last: %d: at least 1 version must be kept")
+	ErrCannotPruneDir     = errMain.Code("cannot_prune_dir").Error("cannot prune the versions of the secrets in a directory. Use the -r flag to prune directories.")
+	ErrCannotPruneVersion = errMain.Code("cannot_prune_version").Error("cannot prune a single version. Use the path of the secret or use rm to remove the version.")
+)
+
+// VersionsPruneCommand removes old versions of secrets.
+type VersionsPruneCommand struct {
+	io        ui.IO
+	newClient newClientFunc
+	path      api.Path
+	keepLast  int
+	olderThan dayDurationValue
+	recursive bool
+	dryRun    bool
+	force     bool
+	now       func() time.Time
+}
+
+// NewVersionsPruneCommand creates a new VersionsPruneCommand.
+func NewVersionsPruneCommand(io ui.IO, newClient newClientFunc) *VersionsPruneCommand {
+	return &VersionsPruneCommand{
+		io:        io,
+		newClient: newClient,
+		now:       time.Now,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *VersionsPruneCommand) Register(r cli.Registerer) {
+	clause := r.Command("prune", "Remove old versions of a secret or of all secrets in a directory.")
+	clause.HelpLong("Prune removes the versions of secrets that fall outside a retention policy. " +
+		"With --keep-last, only the given number of newest versions of every secret are kept. " +
+		"With --older-than, only the versions that were created longer ago than the given duration are removed. " +
+		"When both are set, a version is only removed when it matches both. " +
+		"The newest version of a secret is never removed, so a secret always keeps its current value.\n\n" +
+		"A summary is shown first and the removal has to be confirmed by typing the number of versions, unless --force is set.")
+	clause.Flags().IntVar(&cmd.keepLast, "keep-last", 0, "Keep this number of newest versions of every secret.")
+	clause.Flags().Var(&cmd.olderThan, "older-than", "Only remove versions created longer ago than this duration, given in days (180d), weeks (26w) or hours (36h).")
+	clause.Flags().BoolVarP(&cmd.recursive, "recursive", "r", false, "Prune the versions of all secrets in the directory and its subdirectories.")
+	clause.Flags().BoolVar(&cmd.dryRun, "dry-run", false, "Only show which versions would be removed.")
+	registerForceFlag(clause, &cmd.force)
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{{Value: &cmd.path, Name: "path", Required: true, Placeholder: generalPathPlaceHolder, Description: "The path of the secret, or of the directory when -r is set, to prune the versions of."}})
+}
+
+// Run removes the versions that fall outside the retention policy.
+func (cmd *VersionsPruneCommand) Run() error {
+	if cmd.keepLast == 0 && cmd.olderThan.Duration == 0 {
+		return ErrNoPrunePolicy
+	}
+	if cmd.keepLast < 0 {
+		return ErrInvalidKeepLast(cmd.keepLast)
+	}
+	if cmd.path.HasVersion() {
+		return ErrCannotPruneVersion
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	secretPaths, err := cmd.secretPaths(client)
+	if err != nil {
+		return err
+	}
+
+	var prunable []string
+	for _, secretPath := range secretPaths {
+		versions, err := client.Secrets().Versions().ListWithoutData(secretPath)
+		if err != nil {
+			return err
+		}
+		for _, version := range cmd.selectVersions(versions) {
+			prunable = append(prunable, secretPath+":"+strconv.Itoa(version.Version))
+		}
+	}
+
+	w := cmd.io.Output()
+	if len(prunable) == 0 {
+		fmt.Fprintf(w, "No versions of %d secrets have to be removed.\n", len(secretPaths))
+		return nil
+	}
+
+	fmt.Fprintf(w, "%d versions of %d secrets will be removed:\n", len(prunable), len(secretPaths))
+	for _, path := range prunable {
+		fmt.Fprintf(w, "  %s\n", path)
+	}
+
+	if cmd.dryRun {
+		fmt.Fprintln(w, "Nothing has been removed, because --dry-run is set.")
+		return nil
+	}
+
+	if !cmd.force {
+		confirmed, err := ui.ConfirmCaseInsensitive(
+			cmd.io,
+			fmt.Sprintf("[WARNING] This action cannot be undone. This will permanently remove %d versions. "+
+				"Please type in the number of versions to confirm", len(prunable)),
+			strconv.Itoa(len(prunable)),
+		)
+		if err == ui.ErrCannotAsk {
+			return ErrCannotDoWithoutForce
+		} else if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(w, "Number does not match. Aborting.")
+			return nil
+		}
+	}
+
+	failed := 0
+	for _, path := range prunable {
+		err = client.Secrets().Versions().Delete(path)
+		if err != nil {
+			failed++
+			fmt.Fprintf(w, "Could not remove %s: %s\n", path, err)
+		}
+	}
+
+	fmt.Fprintf(w, "Prune complete! %d of %d versions have been permanently removed.\n", len(prunable)-failed, len(prunable))
+	if failed > 0 {
+		return ErrBatchFailed(failed, len(prunable))
+	}
+	return nil
+}
+
+// secretPaths returns the path of the secret to prune, or the paths of all secrets in the
+// directory when the path is a directory and --recursive is set.
+func (cmd *VersionsPruneCommand) secretPaths(client secrethub.ClientInterface) ([]string, error) {
+	dirPath, err := cmd.path.ToDirPath()
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := client.Dirs().GetTree(dirPath.Value(), -1, false)
+	if err == nil {
+		if !cmd.recursive {
+			return nil, ErrCannotPruneDir
+		}
+
+		paths := make([]string, 0, len(tree.Secrets))
+		for id := range tree.Secrets {
+			secretPath, err := tree.AbsSecretPath(id)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, secretPath.String())
+		}
+		sort.Strings(paths)
+		return paths, nil
+	} else if !api.IsErrNotFound(err) {
+		return nil, err
+	}
+
+	secretPath, err := cmd.path.ToSecretPath()
+	if err != nil {
+		return nil, err
+	}
+	_, err = client.Secrets().Get(secretPath.Value())
+	if api.IsErrNotFound(err) {
+		return nil, ErrResourceNotFound(cmd.path)
+	} else if err != nil {
+		return nil, err
+	}
+	return []string{secretPath.Value()}, nil
+}
+
+// selectVersions returns the versions that fall outside the retention policy, from the oldest to the newest.
+// The newest version is never selected.
+func (cmd *VersionsPruneCommand) selectVersions(versions []*api.SecretVersion) []*api.SecretVersion {
+	sorted := make([]*api.SecretVersion, len(versions))
+	copy(sorted, versions)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	keep := 1
+	if cmd.keepLast > keep {
+		keep = cmd.keepLast
+	}
+	if len(sorted) <= keep {
+		return nil
+	}
+	candidates := sorted[:len(sorted)-keep]
+
+	if cmd.olderThan.Duration == 0 {
+		return candidates
+	}
+	cutoff := cmd.now().Add(-cmd.olderThan.Duration)
+	var selected []*api.SecretVersion
+	for _, version := range candidates {
+		if version.CreatedAt.Before(cutoff) {
+			selected = append(selected, version)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,151 @@
+package secrethub
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestVersionsPruneCommand_Run(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	cases := map[string]struct {
+		cmd             VersionsPruneCommand
+		in              string
+		expectedRemoved []string
+		expectedOut     string
+		expectedErr     error
+	}{
+		"keep last": {
+			cmd: VersionsPruneCommand{
+				path:     "namespace/repo/secret",
+				keepLast: 2,
+			},
+			in:              "2",
+			expectedRemoved: []string{"namespace/repo/secret:1", "namespace/repo/secret:2"},
+			expectedOut: "2 versions of 1 secrets will be removed:\n" +
+				"  namespace/repo/secret:1\n" +
+				"  namespace/repo/secret:2\n" +
+				"Prune complete! 2 of 2 versions have been permanently removed.\n",
+		},
+		"older than and keep last": {
+			cmd: VersionsPruneCommand{
+				path:      "namespace/repo/secret",
+				keepLast:  1,
+				olderThan: dayDurationValue{Duration: 90 * day},
+				dryRun:    true,
+			},
+			expectedOut: "1 versions of 1 secrets will be removed:\n" +
+				"  namespace/repo/secret:1\n" +
+				"Nothing has been removed, because --dry-run is set.\n",
+		},
+		"newest version is kept": {
+			cmd: VersionsPruneCommand{
+				path:      "namespace/repo/secret",
+				olderThan: dayDurationValue{Duration: day},
+				force:     true,
+			},
+			expectedRemoved: []string{"namespace/repo/secret:1", "namespace/repo/secret:2", "namespace/repo/secret:3"},
+			expectedOut: "3 versions of 1 secrets will be removed:\n" +
+				"  namespace/repo/secret:1\n" +
+				"  namespace/repo/secret:2\n" +
+				"  namespace/repo/secret:3\n" +
+				"Prune complete! 3 of 3 versions have been permanently removed.\n",
+		},
+		"nothing to prune": {
+			cmd: VersionsPruneCommand{
+				path:     "namespace/repo/secret",
+				keepLast: 4,
+			},
+			expectedOut: "No versions of 1 secrets have to be removed.\n",
+		},
+		"wrong count": {
+			cmd: VersionsPruneCommand{
+				path:     "namespace/repo/secret",
+				keepLast: 3,
+			},
+			in: "2",
+			expectedOut: "1 versions of 1 secrets will be removed:\n" +
+				"  namespace/repo/secret:1\n" +
+				"Number does not match. Aborting.\n",
+		},
+		"no policy": {
+			cmd: VersionsPruneCommand{
+				path: "namespace/repo/secret",
+			},
+			expectedErr: ErrNoPrunePolicy,
+		},
+		"version path": {
+			cmd: VersionsPruneCommand{
+				path:     "namespace/repo/secret:1",
+				keepLast: 1,
+			},
+			expectedErr: ErrCannotPruneVersion,
+		},
+		"directory without recursive": {
+			cmd: VersionsPruneCommand{
+				path:     "namespace/repo/dir",
+				keepLast: 1,
+			},
+			expectedErr: ErrCannotPruneDir,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			io.PromptIn.Buffer = bytes.NewBufferString(tc.in)
+			tc.cmd.io = io
+			tc.cmd.now = func() time.Time {
+				return now
+			}
+
+			var removed []string
+			tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					SecretService: &fakeclient.SecretService{
+						GetFunc: func(path string) (*api.Secret, error) {
+							return &api.Secret{}, nil
+						},
+						VersionService: &fakeclient.SecretVersionService{
+							ListWithoutDataFunc: func(path string) ([]*api.SecretVersion, error) {
+								return []*api.SecretVersion{
+									{Version: 4, CreatedAt: now.Add(-2 * day)},
+									{Version: 2, CreatedAt: now.Add(-60 * day)},
+									{Version: 1, CreatedAt: now.Add(-200 * day)},
+									{Version: 3, CreatedAt: now.Add(-30 * day)},
+								}, nil
+							},
+							DeleteFunc: func(path string) error {
+								removed = append(removed, path)
+								return nil
+							},
+						},
+					},
+					DirService: &fakeclient.DirService{
+						GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+							if path == "namespace/repo/dir" {
+								return &api.Tree{}, nil
+							}
+							return nil, api.ErrDirNotFound
+						},
+					},
+				}, nil
+			}
+
+			err := tc.cmd.Run()
+
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+			assert.Equal(t, removed, tc.expectedRemoved)
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package secrethub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Types of the events reported by the watch command.
+const (
+	watchEventCreated = "created"
+	watchEventUpdated = "updated"
+	watchEventDeleted = "deleted"
+)
+
+// Errors
+var (
+	ErrCannotWatchVersion = errMain.Code("cannot_watch_version").Error("cannot watch a secret version, as versions do not change. Use the path of the secret instead.")
+)
+
+// watchEvent is a change to a watched secret.
+type watchEvent struct {
+	Type    string    `json:"type"`
+	Path    string    `json:"path"`
+	Version int       `json:"version,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// env returns the environment variables describing the event to the command run with --exec.
+func (e watchEvent) env() []string {
+	version := ""
+	if e.Version > 0 {
+		version = strconv.Itoa(e.Version)
+	}
+	return []string{
+		"SECRETHUB_WATCH_EVENT=" + e.Type,
+		"SECRETHUB_WATCH_PATH=" + e.Path,
+		"SECRETHUB_WATCH_VERSION=" + version,
+	}
+}
+
+// WatchCommand polls a secret or directory for changes.
+type WatchCommand struct {
+	io         ui.IO
+	newClient  newClientFunc
+	path       api.Path
+	json       bool
+	exec       string
+	interval   time.Duration
+	wait       func(ctx context.Context, d time.Duration) bool
+	now        func() time.Time
+	runCommand func(command string, env []string) error
+}
+
+// NewWatchCommand creates a new WatchCommand.
+func NewWatchCommand(io ui.IO, newClient newClientFunc) *WatchCommand {
+	return &WatchCommand{
+		io:         io,
+		newClient:  newClient,
+		wait:       waitContext,
+		now:        time.Now,
+		runCommand: runShellCommand,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *WatchCommand) Register(r cli.Registerer) {
+	clause := r.Command("watch", "Watch a secret or directory for new versions.")
+	clause.HelpLong("Watch checks a secret, or all secrets in a directory and its subdirectories, for changes every interval until it is stopped with Ctrl+C. " +
+		"An event is reported when a new version of a secret is written (updated), a secret is added to the directory (created) or a secret is removed (deleted). " +
+		"Changes made before the command started are not reported.\n\n" +
+		"Every event is printed, or printed as a JSON object on a single line with --json. " +
+		"With --exec, a shell command is also run for every event, with the event in the SECRETHUB_WATCH_EVENT, SECRETHUB_WATCH_PATH and SECRETHUB_WATCH_VERSION environment variables. " +
+		"When the command fails, a warning is logged and watching continues.")
+	clause.Flags().BoolVar(&cmd.json, "json", false, "Print every event as a JSON object on a single line.")
+	clause.Flags().StringVar(&cmd.exec, "exec", "", "A shell command to run for every event.")
+	clause.Flags().DurationVar(&cmd.interval, "interval", 30*time.Second, "The time between checks for changes.")
+
+	clause.BindAction(cmd.Run)
+	clause.BindArguments([]cli.Argument{{Value: &cmd.path, Name: "path", Required: true, Placeholder: generalPathPlaceHolder, Description: "The path to the secret or directory to watch."}})
+}
+
+// Run watches the path until interrupted.
+func (cmd *WatchCommand) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return cmd.run(ctx)
+}
+
+func (cmd *WatchCommand) run(ctx context.Context) error {
+	if cmd.interval <= 0 {
+		return ErrInvalidPollInterval(cmd.interval)
+	}
+	if cmd.path.HasVersion() {
+		return ErrCannotWatchVersion
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	previous, err := cmd.snapshot(client)
+	if err != nil {
+		return err
+	}
+
+	for cmd.wait(ctx, cmd.interval) {
+		current, err := cmd.snapshot(client)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range diffWatchSnapshots(previous, current, cmd.now()) {
+			err = cmd.report(event)
+			if err != nil {
+				return err
+			}
+		}
+		previous = current
+	}
+	return nil
+}
+
+// snapshot returns the latest version of every watched secret, keyed by path.
+func (cmd *WatchCommand) snapshot(client secrethub.ClientInterface) (map[string]int, error) {
+	dirPath, err := cmd.path.ToDirPath()
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := client.Dirs().GetTree(dirPath.Value(), -1, false)
+	if err == nil {
+		versions := make(map[string]int, len(tree.Secrets))
+		for id, secret := range tree.Secrets {
+			secretPath, err := tree.AbsSecretPath(id)
+			if err != nil {
+				return nil, err
+			}
+			versions[secretPath.String()] = secret.LatestVersion
+		}
+		return versions, nil
+	} else if !api.IsErrNotFound(err) {
+		return nil, err
+	}
+
+	secretPath, err := cmd.path.ToSecretPath()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := client.Secrets().Get(secretPath.Value())
+	if api.IsErrNotFound(err) {
+		return map[string]int{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return map[string]int{secretPath.Value(): secret.LatestVersion}, nil
+}
+
+// diffWatchSnapshots returns the events that happened between two snapshots, sorted by path.
+func diffWatchSnapshots(previous, current map[string]int, now time.Time) []watchEvent {
+	var events []watchEvent
+	for path, version := range current {
+		previousVersion, ok := previous[path]
+		if !ok {
+			events = append(events, watchEvent{Type: watchEventCreated, Path: path, Version: version, Time: now})
+		} else if version != previousVersion {
+			events = append(events, watchEvent{Type: watchEventUpdated, Path: path, Version: version, Time: now})
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			events = append(events, watchEvent{Type: watchEventDeleted, Path: path, Time: now})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Path < events[j].Path
+	})
+	return events
+}
+
+// report prints the event and runs the command set with --exec for it.
+func (cmd *WatchCommand) report(event watchEvent) error {
+	if cmd.json {
+		err := json.NewEncoder(cmd.io.Output()).Encode(event)
+		if err != nil {
+			return err
+		}
+	} else if event.Version > 0 {
+		fmt.Fprintf(cmd.io.Output(), "%s %s %s (version %d)\n", event.Time.Format(time.RFC3339), event.Type, event.Path, event.Version)
+	} else {
+		fmt.Fprintf(cmd.io.Output(), "%s %s %s\n", event.Time.Format(time.RFC3339), event.Type, event.Path)
+	}
+
+	if cmd.exec != "" {
+		err := cmd.runCommand(cmd.exec, event.env())
+		if err != nil {
+			logger.Warningf("command for %s of %s failed: %s", event.Type, event.Path, err)
+		}
+	}
+	return nil
+}
+
+// runShellCommand runs the command in a shell with the given extra environment variables.
+// Its output is written to stderr, so that it does not mix with the output of the command itself.
+func runShellCommand(command string, env []string) error {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.Command("cmd", "/C", command)
+	} else {
+		c = exec.Command("sh", "-c", command)
+	}
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(), env...)
+	return c.Run()
+}
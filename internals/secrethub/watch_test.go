@@ -0,0 +1,112 @@
+package secrethub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestDiffWatchSnapshots(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	previous := map[string]int{
+		"namespace/repo/a": 1,
+		"namespace/repo/b": 2,
+		"namespace/repo/c": 3,
+	}
+	current := map[string]int{
+		"namespace/repo/a": 1,
+		"namespace/repo/b": 3,
+		"namespace/repo/d": 1,
+	}
+
+	assert.Equal(t, diffWatchSnapshots(previous, current, now), []watchEvent{
+		{Type: watchEventUpdated, Path: "namespace/repo/b", Version: 3, Time: now},
+		{Type: watchEventDeleted, Path: "namespace/repo/c", Time: now},
+		{Type: watchEventCreated, Path: "namespace/repo/d", Version: 1, Time: now},
+	})
+}
+
+func TestWatchCommand_run(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		json        bool
+		expectedOut string
+	}{
+		"text": {
+			expectedOut: "2020-01-01T00:00:00Z updated namespace/repo/secret (version 2)\n" +
+				"2020-01-01T00:00:00Z deleted namespace/repo/secret\n",
+		},
+		"json": {
+			json: true,
+			expectedOut: `{"type":"updated","path":"namespace/repo/secret","version":2,"time":"2020-01-01T00:00:00Z"}` + "\n" +
+				`{"type":"deleted","path":"namespace/repo/secret","time":"2020-01-01T00:00:00Z"}` + "\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// The latest version of the secret at every poll. 0 means the secret does not exist.
+			polls := []int{1, 1, 2, 0}
+			poll := 0
+
+			io := fakeui.NewIO(t)
+			var executed [][]string
+			cmd := WatchCommand{
+				io:       io,
+				path:     "namespace/repo/secret",
+				json:     tc.json,
+				exec:     "./react.sh",
+				interval: time.Second,
+				wait: func(ctx context.Context, d time.Duration) bool {
+					return poll < len(polls)
+				},
+				now: func() time.Time {
+					return now
+				},
+				runCommand: func(command string, env []string) error {
+					assert.Equal(t, command, "./react.sh")
+					executed = append(executed, env)
+					return errors.New("failing commands do not stop watching")
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								return nil, api.ErrDirNotFound
+							},
+						},
+						SecretService: &fakeclient.SecretService{
+							GetFunc: func(path string) (*api.Secret, error) {
+								version := polls[poll]
+								poll++
+								if version == 0 {
+									return nil, api.ErrSecretNotFound
+								}
+								return &api.Secret{LatestVersion: version}, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.run(context.Background())
+
+			assert.OK(t, err)
+			assert.Equal(t, io.Out.String(), tc.expectedOut)
+			assert.Equal(t, executed, [][]string{
+				{"SECRETHUB_WATCH_EVENT=updated", "SECRETHUB_WATCH_PATH=namespace/repo/secret", "SECRETHUB_WATCH_VERSION=2"},
+				{"SECRETHUB_WATCH_EVENT=deleted", "SECRETHUB_WATCH_PATH=namespace/repo/secret", "SECRETHUB_WATCH_VERSION="},
+			})
+		})
+	}
+}
@@ -2,9 +2,13 @@ package secrethub
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/clip"
@@ -18,6 +22,12 @@ var (
 	errEmptySecret                     = errMain.Code("cannot_write_empty_secret").Error("secret is empty or contains only whitespace")
 	errClipAndInFile                   = errMain.Code("clip_and_in_file").Error("clip and in-file cannot be used together")
 	errMultilineWithNonInteractiveFlag = errMain.Code("multiline_flag_conflict").Error("multiline cannot be used together with clip or in-file")
+	errInvalidJSONSecrets              = errMain.Code("invalid_json_secrets").ErrorPref("invalid JSON: %s: must be an object")
+	errJSONNullValue                   = errMain.Code("json_null_value").ErrorPref("the value of %s is null")
+	errEmptyJSONValue                  = errMain.Code("empty_json_value").ErrorPref("the value of %s is empty or contains only whitespace")
+	errEmptyJSONObject                 = errMain.Code("empty_json_object").Error("the JSON object does not contain any values")
+	errEmptyFlattenSeparator           = errMain.Code("empty_flatten_separator").Error("--flatten-separator must not be empty")
+	errBinaryInput                     = errMain.Code("binary_input").Error("--binary can only be used with --in-file or data on stdin")
 )
 
 // WriteCommand is a command to write content to a secret.
@@ -28,16 +38,21 @@ type WriteCommand struct {
 	multiline    bool
 	useClipboard bool
 	noTrim       bool
+	fromJSON     bool
+	binary       bool
+	separator    string
 	clipper      clip.Clipper
 	newClient    newClientFunc
+	completion   *CompletionCache
 }
 
 // NewWriteCommand creates a new WriteCommand.
-func NewWriteCommand(io ui.IO, newClient newClientFunc) *WriteCommand {
+func NewWriteCommand(io ui.IO, newClient newClientFunc, completion *CompletionCache) *WriteCommand {
 	return &WriteCommand{
-		clipper:   clip.NewClipboard(),
-		io:        io,
-		newClient: newClient,
+		clipper:    clip.NewClipboard(),
+		io:         io,
+		newClient:  newClient,
+		completion: completion,
 	}
 }
 
@@ -48,9 +63,14 @@ func (cmd *WriteCommand) Register(r cli.Registerer) {
 	clause.Flags().BoolVarP(&cmd.multiline, "multiline", "m", false, "Prompt for multiple lines of input, until an EOF is reached. On Linux/Mac, press CTRL-D to end input. On Windows, press CTRL-Z and then ENTER to end input.")
 	clause.Flags().BoolVar(&cmd.noTrim, "no-trim", false, "Do not trim leading and trailing whitespace in the secret.")
 	clause.Flags().StringVarP(&cmd.inFile, "in-file", "i", "", "Use the contents of this file as the value of the secret.")
+	clause.Flags().BoolVar(&cmd.fromJSON, "from-json", false, "Read a JSON object and write every value in it as a secret in the directory at the given path, named after its key. For example, {\"username\": \"...\", \"password\": \"...\"} is written to <path>/username and <path>/password.")
+	clause.Flags().BoolVar(&cmd.binary, "binary", false, "Write binary data, such as a keystore or certificate, as is. The data is stored base64 encoded together with its SHA-256 checksum, which is validated by `secrethub read --binary`.")
+	clause.Flags().StringVar(&cmd.separator, "flatten-separator", "/", "With --from-json, the separator between the keys of nested objects in the names of the secrets. By default, nested objects are written to subdirectories.")
+
+	cmd.completion.registerArgument(clause, pathKindSecret)
 
 	clause.BindAction(cmd.Run)
-	clause.BindArguments([]cli.Argument{{Value: &cmd.path, Name: "secret-path", Required: true, Placeholder: secretPathPlaceHolder, Description: "The path to the secret."}})
+	clause.BindArguments([]cli.Argument{{Value: &cmd.path, Name: "secret-path", Required: true, Placeholder: secretPathPlaceHolder, Description: "The path to the secret, or to the directory to write the secrets to with --from-json."}})
 }
 
 // Run handles the command with the options as specified in the command.
@@ -72,6 +92,15 @@ func (cmd *WriteCommand) Run() error {
 		return errClipAndInFile
 	}
 
+	if cmd.binary {
+		if cmd.fromJSON {
+			return ErrFlagsConflict("--binary and --from-json")
+		}
+		if cmd.inFile == "" && !cmd.io.IsInputPiped() || cmd.useClipboard || cmd.multiline {
+			return errBinaryInput
+		}
+	}
+
 	var data []byte
 	if cmd.useClipboard {
 		data, err = cmd.clipper.ReadAll()
@@ -102,13 +131,25 @@ func (cmd *WriteCommand) Run() error {
 		data = []byte(str)
 	}
 
-	if !cmd.noTrim {
-		// The data needs to be sanitized and trimmed for whitespace.
-		data = bytes.TrimSpace(data)
+	if cmd.fromJSON {
+		return cmd.writeJSON(data)
 	}
 
-	if len(bytes.TrimSpace(data)) == 0 {
-		return errEmptySecret
+	if cmd.binary {
+		// Binary data is not trimmed, because whitespace is part of the data.
+		if len(data) == 0 {
+			return errEmptySecret
+		}
+		data = encodeBinarySecret(data)
+	} else {
+		if !cmd.noTrim {
+			// The data needs to be sanitized and trimmed for whitespace.
+			data = bytes.TrimSpace(data)
+		}
+
+		if len(bytes.TrimSpace(data)) == 0 {
+			return errEmptySecret
+		}
 	}
 
 	_, err = fmt.Fprint(cmd.io.Output(), "Writing secret value...\n")
@@ -126,10 +167,119 @@ func (cmd *WriteCommand) Run() error {
 		return err
 	}
 
-	_, err = fmt.Fprintf(cmd.io.Output(), "Write complete! The given value has been written to %s:%d\n", cmd.path, version.Version)
+	ui.Successf(cmd.io.Output(), "Write complete! The given value has been written to %s:%d\n", cmd.path, version.Version)
+	return nil
+}
+
+// writeJSON writes every value in the JSON object as a secret in the directory at the path of the command.
+func (cmd *WriteCommand) writeJSON(data []byte) error {
+	if cmd.separator == "" {
+		return errEmptyFlattenSeparator
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Numbers are decoded as they are written, so that they are not rounded.
+	decoder.UseNumber()
+	var object map[string]interface{}
+	err := decoder.Decode(&object)
+	if err != nil {
+		return errInvalidJSONSecrets(err)
+	}
+
+	secrets := map[string][]byte{}
+	err = cmd.flattenJSON("", object, secrets)
+	if err != nil {
+		return err
+	}
+	if len(secrets) == 0 {
+		return errEmptyJSONObject
+	}
+
+	dirPath := strings.TrimSuffix(cmd.path.Value(), "/")
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		_, err := api.NewSecretPath(dirPath + "/" + name)
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	client, err := cmd.newClient()
 	if err != nil {
 		return err
 	}
 
+	// The directories are created before the secrets, parents first.
+	created := map[string]bool{}
+	for _, name := range names {
+		secretPath := dirPath + "/" + name
+		for i := len(dirPath); i < len(secretPath); i++ {
+			if secretPath[i] != '/' || created[secretPath[:i]] {
+				continue
+			}
+			_, err = client.Dirs().Create(secretPath[:i])
+			if err != nil && err != api.ErrDirAlreadyExists {
+				return err
+			}
+			created[secretPath[:i]] = true
+		}
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Writing %d %s...\n", len(names), pluralize("secret", "secrets", len(names)))
+	for _, name := range names {
+		secretPath := dirPath + "/" + name
+		version, err := client.Secrets().Write(secretPath, secrets[name])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Written to %s:%d\n", secretPath, version.Version)
+	}
+	return nil
+}
+
+// flattenJSON adds the values in the object to the secrets, named after their keys.
+// The keys of nested objects are joined with the separator.
+func (cmd *WriteCommand) flattenJSON(prefix string, object map[string]interface{}, secrets map[string][]byte) error {
+	for key, value := range object {
+		name := key
+		if prefix != "" {
+			name = prefix + cmd.separator + key
+		}
+
+		var data []byte
+		switch v := value.(type) {
+		case map[string]interface{}:
+			err := cmd.flattenJSON(name, v, secrets)
+			if err != nil {
+				return err
+			}
+			continue
+		case nil:
+			return errJSONNullValue(name)
+		case string:
+			data = []byte(v)
+		case json.Number:
+			data = []byte(v.String())
+		case bool:
+			data = []byte(strconv.FormatBool(v))
+		default:
+			// Arrays are written as JSON.
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			data = encoded
+		}
+
+		if !cmd.noTrim {
+			data = bytes.TrimSpace(data)
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			return errEmptyJSONValue(name)
+		}
+		secrets[name] = data
+	}
 	return nil
 }
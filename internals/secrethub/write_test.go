@@ -267,3 +267,103 @@ func TestWriteCommand_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteCommand_RunFromJSON(t *testing.T) {
+	cases := map[string]struct {
+		separator       string
+		in              string
+		expectedDirs    []string
+		expectedSecrets map[string]string
+		expectedErr     error
+	}{
+		"flat": {
+			in:           `{"username": "admin", "password": " secret\n", "port": 5432, "tls": true}`,
+			expectedDirs: []string{"namespace/repo/db"},
+			expectedSecrets: map[string]string{
+				"namespace/repo/db/password": "secret",
+				"namespace/repo/db/port":     "5432",
+				"namespace/repo/db/tls":      "true",
+				"namespace/repo/db/username": "admin",
+			},
+		},
+		"nested": {
+			in:           `{"primary": {"host": "db1", "replica": {"host": "db2"}}, "hosts": ["db1", "db2"]}`,
+			expectedDirs: []string{"namespace/repo/db", "namespace/repo/db/primary", "namespace/repo/db/primary/replica"},
+			expectedSecrets: map[string]string{
+				"namespace/repo/db/hosts":                "[\"db1\",\"db2\"]",
+				"namespace/repo/db/primary/host":         "db1",
+				"namespace/repo/db/primary/replica/host": "db2",
+			},
+		},
+		"nested with separator": {
+			separator:    "_",
+			in:           `{"primary": {"host": "db1"}}`,
+			expectedDirs: []string{"namespace/repo/db"},
+			expectedSecrets: map[string]string{
+				"namespace/repo/db/primary_host": "db1",
+			},
+		},
+		"not an object": {
+			in:          `["db1"]`,
+			expectedErr: errInvalidJSONSecrets("json: cannot unmarshal array into Go value of type map[string]interface {}"),
+		},
+		"null": {
+			in:          `{"primary": {"host": null}}`,
+			expectedErr: errJSONNullValue("primary/host"),
+		},
+		"empty value": {
+			in:          `{"password": "  "}`,
+			expectedErr: errEmptyJSONValue("password"),
+		},
+		"empty object": {
+			in:          `{"primary": {}}`,
+			expectedErr: errEmptyJSONObject,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var dirs []string
+			secrets := map[string]string{}
+
+			separator := tc.separator
+			if separator == "" {
+				separator = "/"
+			}
+			cmd := WriteCommand{
+				path:      "namespace/repo/db",
+				fromJSON:  true,
+				separator: separator,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						DirService: &fakeclient.DirService{
+							CreateFunc: func(path string) (*api.Dir, error) {
+								dirs = append(dirs, path)
+								return nil, api.ErrDirAlreadyExists
+							},
+						},
+						SecretService: &fakeclient.SecretService{
+							WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+								secrets[path] = string(data)
+								return &api.SecretVersion{Version: 1}, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			io := fakeui.NewIO(t)
+			io.In.Piped = true
+			io.In.Buffer = bytes.NewBufferString(tc.in)
+			cmd.io = io
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.expectedErr)
+			if tc.expectedErr == nil {
+				assert.Equal(t, dirs, tc.expectedDirs)
+				assert.Equal(t, secrets, tc.expectedSecrets)
+			}
+		})
+	}
+}
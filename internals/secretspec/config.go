@@ -0,0 +1,323 @@
+package secretspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	fieldFields = "fields"
+)
+
+// Errors
+var (
+	ErrInvalidConfigFile  = errConsumption.Code("invalid_config_file").ErrorPref("cannot parse %s: %v")
+	ErrConfigKeyNotFound  = errConsumption.Code("config_key_not_found").ErrorPref("key %s is not found in %s")
+	ErrInvalidConfigField = errConsumption.Code("invalid_config_field").ErrorPref("the secret path of key %s must be a string")
+)
+
+// JSONParser parses consumables that inject secrets into keys of a JSON file.
+type JSONParser struct{}
+
+// Type returns the parser type.
+func (p JSONParser) Type() string {
+	return "json"
+}
+
+// Parse parses a config to create a JSON config Consumable.
+func (p JSONParser) Parse(rootPath string, allowMountAnywhere bool, config map[string]interface{}) (Consumable, error) {
+	return parseConfigFile(rootPath, allowMountAnywhere, config, jsonFormat{})
+}
+
+// YAMLParser parses consumables that inject secrets into keys of a YAML file.
+type YAMLParser struct{}
+
+// Type returns the parser type.
+func (p YAMLParser) Type() string {
+	return "yaml"
+}
+
+// Parse parses a config to create a YAML config Consumable.
+func (p YAMLParser) Parse(rootPath string, allowMountAnywhere bool, config map[string]interface{}) (Consumable, error) {
+	return parseConfigFile(rootPath, allowMountAnywhere, config, yamlFormat{})
+}
+
+// TOMLParser parses consumables that inject secrets into keys of a TOML file.
+type TOMLParser struct{}
+
+// Type returns the parser type.
+func (p TOMLParser) Type() string {
+	return "toml"
+}
+
+// Parse parses a config to create a TOML config Consumable.
+func (p TOMLParser) Parse(rootPath string, allowMountAnywhere bool, config map[string]interface{}) (Consumable, error) {
+	return parseConfigFile(rootPath, allowMountAnywhere, config, tomlFormat{})
+}
+
+// configFormat locates values in a configuration file format.
+type configFormat interface {
+	// name returns the name of the format, equal to the type of its parser.
+	name() string
+	// validate returns an error when the data is not a valid file of the format.
+	validate(data []byte) error
+	// valueSpan returns the location of the value of the key with the given path,
+	// or false when the file does not contain the key.
+	valueSpan(data []byte, path []string) (valueSpan, bool, error)
+}
+
+// valueSpan is the location of a value in a configuration file. When the value is
+// replaced, prefix is written before the new value.
+type valueSpan struct {
+	start  int
+	end    int
+	prefix string
+}
+
+// parseConfigFile parses a config to create a configFile Consumable of the given format.
+// The config has the following form:
+//
+//	source: config.json
+//	target: config-injected.json
+//	fields:
+//	  database.password: company/app/db/password
+func parseConfigFile(rootPath string, allowMountAnywhere bool, config map[string]interface{}, format configFormat) (Consumable, error) {
+	sourceName, ok := config[fieldSource].(string)
+	if !ok {
+		return nil, ErrFieldNotSet(fieldSource, fieldSource)
+	}
+
+	source, err := filepath.Abs(sourceName)
+	if err != nil {
+		return nil, ErrCannotFindAbsPath(sourceName, err)
+	}
+
+	targetName, ok := config[fieldTarget].(string)
+	if !ok {
+		return nil, ErrFieldNotSet(fieldTarget, fieldTarget)
+	}
+
+	target, err := createTarget(rootPath, targetName, allowMountAnywhere)
+	if err != nil {
+		return nil, err
+	}
+
+	filemode := DefaultFileMode
+	mode, ok := config[fieldFilemode].(string)
+	if ok {
+		filemode, err = strToFileMode(mode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rawFields, ok := config[fieldFields].(map[interface{}]interface{})
+	if !ok {
+		return nil, ErrFieldNotSet(fieldFields, map[string]string{})
+	}
+
+	raw, err := os.ReadFile(source)
+	if err != nil {
+		return nil, ErrCannotReadFile(source, err)
+	}
+
+	err = format.validate(raw)
+	if err != nil {
+		return nil, ErrInvalidConfigFile(source, err)
+	}
+
+	c := &configFile{
+		format:   format,
+		source:   source,
+		target:   target,
+		filemode: filemode,
+		fields:   make(map[string]string, len(rawFields)),
+		raw:      raw,
+	}
+	for rawKey, rawPath := range rawFields {
+		key := fmt.Sprint(rawKey)
+		secretPath, ok := rawPath.(string)
+		if !ok {
+			return nil, ErrInvalidConfigField(key)
+		}
+		secretPath = strings.ToLower(secretPath)
+		err = api.ValidateSecretPath(secretPath)
+		if err != nil {
+			return nil, ErrInvalidSourcePath(err)
+		}
+
+		// Check that all keys exist, so that a typo is reported before any secrets are fetched.
+		_, err = c.valueSpan(raw, key)
+		if err != nil {
+			return nil, err
+		}
+		c.fields[key] = secretPath
+	}
+
+	return c, nil
+}
+
+// configFile implements a consumable that takes a configuration file and replaces
+// the values of the given keys with secrets. The rest of the file is left as is,
+// so formatting, comments and the order of keys are preserved.
+type configFile struct {
+	format   configFormat
+	source   string
+	target   string
+	filemode os.FileMode
+	// fields maps the paths of keys, separated by dots, to the paths of the secrets.
+	fields map[string]string
+	raw    []byte
+}
+
+// Set replaces the values of the configured keys with the matching secrets in the map
+// and writes the result to the target file.
+func (c *configFile) Set(secrets map[string]api.SecretVersion) error {
+	keys := make([]string, 0, len(c.fields))
+	for key := range c.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	output := c.raw
+	for _, key := range keys {
+		secret, found := secrets[c.fields[key]]
+		if !found {
+			return ErrSecretNotFound(c.fields[key])
+		}
+
+		var err error
+		output, err = c.inject(output, key, string(secret.Data))
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Debugf("writing injected %s file to %s", c.format.name(), c.target)
+
+	return overwriteFile(c.target, output, c.filemode)
+}
+
+// valueSpan returns the location of the value of the key, which is a path separated by dots.
+func (c *configFile) valueSpan(data []byte, key string) (valueSpan, error) {
+	span, found, err := c.format.valueSpan(data, strings.Split(key, "."))
+	if err != nil {
+		return valueSpan{}, ErrInvalidConfigFile(c.source, err)
+	}
+	if !found {
+		return valueSpan{}, ErrConfigKeyNotFound(key, c.source)
+	}
+	return span, nil
+}
+
+// inject replaces the value of the key in the data with the given value, encoded as a string.
+func (c *configFile) inject(data []byte, key string, value string) ([]byte, error) {
+	span, err := c.valueSpan(data, key)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := encodeConfigString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]byte, 0, len(data)+len(encoded))
+	output = append(output, data[:span.start]...)
+	output = append(output, span.prefix...)
+	output = append(output, encoded...)
+	output = append(output, data[span.end:]...)
+	return output, nil
+}
+
+// encodeConfigString returns the value as a double quoted string. The escape sequences
+// of JSON strings are also valid in double quoted YAML strings and basic TOML strings.
+func encodeConfigString(value string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// Sources returns the full paths of the secrets from which the Consumable is sourced.
+func (c *configFile) Sources() map[string]struct{} {
+	sources := make(map[string]struct{})
+	for _, path := range c.fields {
+		sources[path] = struct{}{}
+	}
+	return sources
+}
+
+// Clear removes the injected file from the filesystem.
+func (c *configFile) Clear() error {
+	err := os.Remove(c.target)
+	if os.IsNotExist(err) {
+		log.Warningf("cannot clear file %s as it does not exist", c.target)
+		return nil
+	}
+	return err
+}
+
+// Equals checks whether two configFiles have the same target.
+func (c *configFile) Equals(consumable Consumable) bool {
+	configConsumable, ok := consumable.(*configFile)
+	if !ok {
+		return false
+	}
+	return configConsumable.target == c.target
+}
+
+// String returns the string representation of the configFile.
+func (c *configFile) String() string {
+	return fmt.Sprintf("%s:%s", c.format.name(), c.target)
+}
+
+// jsonFormat locates values in JSON files.
+type jsonFormat struct{}
+
+func (jsonFormat) name() string {
+	return "json"
+}
+
+func (jsonFormat) validate(data []byte) error {
+	var v interface{}
+	return json.Unmarshal(data, &v)
+}
+
+// yamlFormat locates values in block style YAML mappings.
+type yamlFormat struct{}
+
+func (yamlFormat) name() string {
+	return "yaml"
+}
+
+func (yamlFormat) validate(data []byte) error {
+	var v interface{}
+	return yaml.Unmarshal(data, &v)
+}
+
+// tomlFormat locates values in TOML files.
+type tomlFormat struct{}
+
+func (tomlFormat) name() string {
+	return "toml"
+}
+
+// validate does nothing for TOML, because no TOML decoder is available.
+// Syntax errors in the parts that are scanned are reported by valueSpan.
+func (tomlFormat) validate(data []byte) error {
+	return nil
+}
@@ -0,0 +1,580 @@
+package secretspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	errUnexpectedEnd = errors.New("unexpected end of file")
+)
+
+// jsonScanner finds the location of values in JSON data.
+type jsonScanner struct {
+	data []byte
+	pos  int
+}
+
+// valueSpan returns the location of the value of the key with the given path,
+// or false when there is no such key. Only objects can be descended into.
+func (jsonFormat) valueSpan(data []byte, path []string) (valueSpan, bool, error) {
+	s := &jsonScanner{data: data}
+	return s.find(path)
+}
+
+func (s *jsonScanner) find(path []string) (valueSpan, bool, error) {
+	s.skipWhitespace()
+	if len(path) == 0 {
+		start := s.pos
+		err := s.skipValue()
+		if err != nil {
+			return valueSpan{}, false, err
+		}
+		return valueSpan{start: start, end: s.pos}, true, nil
+	}
+
+	if s.peek() != '{' {
+		return valueSpan{}, false, nil
+	}
+	s.pos++
+
+	for {
+		s.skipWhitespace()
+		if s.peek() == '}' {
+			return valueSpan{}, false, nil
+		}
+
+		key, err := s.string()
+		if err != nil {
+			return valueSpan{}, false, err
+		}
+		s.skipWhitespace()
+		err = s.expect(':')
+		if err != nil {
+			return valueSpan{}, false, err
+		}
+
+		if key == path[0] {
+			return s.find(path[1:])
+		}
+
+		s.skipWhitespace()
+		err = s.skipValue()
+		if err != nil {
+			return valueSpan{}, false, err
+		}
+
+		s.skipWhitespace()
+		if s.peek() != ',' {
+			return valueSpan{}, false, nil
+		}
+		s.pos++
+	}
+}
+
+func (s *jsonScanner) peek() byte {
+	if s.pos >= len(s.data) {
+		return 0
+	}
+	return s.data[s.pos]
+}
+
+func (s *jsonScanner) expect(c byte) error {
+	if s.peek() != c {
+		return s.errUnexpected()
+	}
+	s.pos++
+	return nil
+}
+
+func (s *jsonScanner) errUnexpected() error {
+	if s.pos >= len(s.data) {
+		return errUnexpectedEnd
+	}
+	return fmt.Errorf("unexpected character %q at offset %d", s.data[s.pos], s.pos)
+}
+
+func (s *jsonScanner) skipWhitespace() {
+	for s.pos < len(s.data) && strings.IndexByte(" \t\r\n", s.data[s.pos]) != -1 {
+		s.pos++
+	}
+}
+
+// string reads a string and returns it decoded.
+func (s *jsonScanner) string() (string, error) {
+	start := s.pos
+	err := s.expect('"')
+	if err != nil {
+		return "", err
+	}
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '\\':
+			s.pos += 2
+		case '"':
+			s.pos++
+			var str string
+			err = json.Unmarshal(s.data[start:s.pos], &str)
+			return str, err
+		default:
+			s.pos++
+		}
+	}
+	return "", errUnexpectedEnd
+}
+
+// skipValue moves past the value at the current position.
+func (s *jsonScanner) skipValue() error {
+	switch s.peek() {
+	case '"':
+		_, err := s.string()
+		return err
+	case '{', '[':
+		closing := byte('}')
+		if s.peek() == '[' {
+			closing = ']'
+		}
+		s.pos++
+		for {
+			s.skipWhitespace()
+			if s.peek() == closing {
+				s.pos++
+				return nil
+			}
+			err := s.skipValue()
+			if err != nil {
+				return err
+			}
+			s.skipWhitespace()
+			if s.peek() == ':' || s.peek() == ',' {
+				s.pos++
+			}
+		}
+	default:
+		start := s.pos
+		for s.pos < len(s.data) && strings.IndexByte(",:}] \t\r\n", s.data[s.pos]) == -1 {
+			s.pos++
+		}
+		if s.pos == start {
+			return s.errUnexpected()
+		}
+		return nil
+	}
+}
+
+// valueSpan returns the location of the value of the key with the given path,
+// or false when there is no such key. Only block style mappings can be descended
+// into. A nested mapping or block scalar that is the value of the key is replaced
+// as a whole.
+func (yamlFormat) valueSpan(data []byte, path []string) (valueSpan, bool, error) {
+	lines := splitLines(data)
+
+	parentIndent := -1
+	start := 0
+	for i, key := range path {
+		childIndent := -1
+		found := -1
+		for l := start; l < len(lines); l++ {
+			line := data[lines[l].start:lines[l].end]
+			indent, ok := yamlContentIndent(line)
+			if !ok {
+				continue
+			}
+			if indent <= parentIndent {
+				break
+			}
+			if childIndent == -1 {
+				childIndent = indent
+			}
+			if indent != childIndent {
+				continue
+			}
+
+			lineKey, _, ok := parseYAMLKey(line[indent:])
+			if ok && lineKey == key {
+				found = l
+				break
+			}
+		}
+		if found == -1 {
+			return valueSpan{}, false, nil
+		}
+
+		if i < len(path)-1 {
+			parentIndent = childIndent
+			start = found + 1
+			continue
+		}
+
+		line := data[lines[found].start:lines[found].end]
+		_, colon, _ := parseYAMLKey(line[childIndent:])
+		valueStart := lines[found].start + childIndent + colon
+
+		rest := line[childIndent+colon:]
+		trimmed := bytes.TrimLeft(rest, " \t")
+		if len(trimmed) > 0 && trimmed[0] != '#' && trimmed[0] != '|' && trimmed[0] != '>' {
+			inlineStart := valueStart + len(rest) - len(trimmed)
+			inlineEnd, err := yamlInlineValueEnd(trimmed)
+			if err != nil {
+				return valueSpan{}, false, err
+			}
+			return valueSpan{start: inlineStart, end: inlineStart + inlineEnd}, true, nil
+		}
+
+		// The value is empty or continues on the following lines, which are indented more than the key.
+		end := valueStart + len(bytes.TrimRight(rest, " \t"))
+		if len(trimmed) > 0 && trimmed[0] == '#' {
+			end = valueStart
+		}
+		for l := found + 1; l < len(lines); l++ {
+			line := data[lines[l].start:lines[l].end]
+			indent, ok := yamlContentIndent(line)
+			if !ok {
+				continue
+			}
+			if indent <= childIndent {
+				break
+			}
+			end = lines[l].start + len(bytes.TrimRight(line, " \t"))
+		}
+		return valueSpan{start: valueStart, end: end, prefix: " "}, true, nil
+	}
+	return valueSpan{}, false, nil
+}
+
+// yamlContentIndent returns the indentation of a line and false when the line
+// is empty, a comment or a document marker.
+func yamlContentIndent(line []byte) (int, bool) {
+	trimmed := bytes.TrimLeft(line, " ")
+	if len(bytes.TrimSpace(trimmed)) == 0 || trimmed[0] == '#' {
+		return 0, false
+	}
+	if bytes.HasPrefix(line, []byte("---")) || bytes.HasPrefix(line, []byte("...")) {
+		return 0, false
+	}
+	return len(line) - len(trimmed), true
+}
+
+// parseYAMLKey parses a line of the form `key: value`. It returns the key and
+// the offset directly after the colon.
+func parseYAMLKey(line []byte) (string, int, bool) {
+	if len(line) == 0 {
+		return "", 0, false
+	}
+
+	var key string
+	var i int
+	switch line[0] {
+	case '"':
+		end, err := quotedEnd(line, '"', true)
+		if err != nil {
+			return "", 0, false
+		}
+		err = json.Unmarshal(line[:end], &key)
+		if err != nil {
+			return "", 0, false
+		}
+		i = end
+	case '\'':
+		end, err := quotedEnd(line, '\'', false)
+		if err != nil {
+			return "", 0, false
+		}
+		key = strings.ReplaceAll(string(line[1:end-1]), "''", "'")
+		i = end
+	default:
+		for i = 0; i < len(line); i++ {
+			if line[i] == ':' && (i+1 == len(line) || line[i+1] == ' ' || line[i+1] == '\t') {
+				break
+			}
+		}
+		if i == len(line) {
+			return "", 0, false
+		}
+		key = strings.TrimRight(string(line[:i]), " \t")
+	}
+
+	for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	if i == len(line) || line[i] != ':' {
+		return "", 0, false
+	}
+	return key, i + 1, true
+}
+
+// yamlInlineValueEnd returns the length of the value at the start of the string,
+// without any trailing comment or whitespace.
+func yamlInlineValueEnd(value []byte) (int, error) {
+	switch value[0] {
+	case '"':
+		return quotedEnd(value, '"', true)
+	case '\'':
+		return quotedEnd(value, '\'', false)
+	}
+
+	end := len(value)
+	for i := 1; i < len(value); i++ {
+		if value[i] == '#' && (value[i-1] == ' ' || value[i-1] == '\t') {
+			end = i
+			break
+		}
+	}
+	return len(bytes.TrimRight(value[:end], " \t")), nil
+}
+
+// quotedEnd returns the offset directly after the closing quote of the string at the
+// start of data. Backslash escapes are only recognized when escapes is true. Otherwise,
+// the quote is escaped by doubling it.
+func quotedEnd(data []byte, quote byte, escapes bool) (int, error) {
+	for i := 1; i < len(data); i++ {
+		switch {
+		case escapes && data[i] == '\\':
+			i++
+		case data[i] == quote:
+			if !escapes && i+1 < len(data) && data[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, nil
+		case data[i] == '\n':
+			return 0, errors.New("string is not closed before the end of the line")
+		}
+	}
+	return 0, errUnexpectedEnd
+}
+
+// valueSpan returns the location of the value of the key with the given path, or false
+// when there is no such key. The path is matched against the table and the key of every
+// key/value pair, so both `password` in the table `[database]` and `database.password`
+// at the root match the path database.password. Keys in arrays of tables and inline
+// tables cannot be addressed.
+func (tomlFormat) valueSpan(data []byte, path []string) (valueSpan, bool, error) {
+	var table []string
+	inArrayTable := false
+
+	pos := 0
+	for pos < len(data) {
+		lineEnd := len(data)
+		if i := bytes.IndexByte(data[pos:], '\n'); i != -1 {
+			lineEnd = pos + i
+		}
+		line := data[pos:lineEnd]
+		trimmed := bytes.TrimLeft(line, " \t")
+		if len(bytes.TrimSpace(trimmed)) == 0 || trimmed[0] == '#' {
+			pos = lineEnd + 1
+			continue
+		}
+
+		if trimmed[0] == '[' {
+			inArrayTable = bytes.HasPrefix(trimmed, []byte("[["))
+			header := bytes.TrimLeft(trimmed, "[")
+			end := bytes.IndexByte(header, ']')
+			if end == -1 {
+				return valueSpan{}, false, fmt.Errorf("table header is not closed: %s", trimmed)
+			}
+			var err error
+			table, err = splitTOMLKey(header[:end])
+			if err != nil {
+				return valueSpan{}, false, err
+			}
+			pos = lineEnd + 1
+			continue
+		}
+
+		eq, err := tomlKeyEnd(trimmed)
+		if err != nil {
+			return valueSpan{}, false, err
+		}
+		keys, err := splitTOMLKey(trimmed[:eq])
+		if err != nil {
+			return valueSpan{}, false, err
+		}
+
+		valueStart := pos + len(line) - len(trimmed) + eq + 1
+		for valueStart < len(data) && (data[valueStart] == ' ' || data[valueStart] == '\t') {
+			valueStart++
+		}
+		valueEnd, err := tomlValueEnd(data, valueStart)
+		if err != nil {
+			return valueSpan{}, false, err
+		}
+
+		if !inArrayTable && equalKeys(append(append([]string{}, table...), keys...), path) {
+			return valueSpan{start: valueStart, end: valueEnd}, true, nil
+		}
+
+		// Continue on the line after the value, which can span multiple lines.
+		next := bytes.IndexByte(data[valueEnd:], '\n')
+		if next == -1 {
+			break
+		}
+		pos = valueEnd + next + 1
+	}
+	return valueSpan{}, false, nil
+}
+
+// tomlKeyEnd returns the offset of the equals sign that ends the key of a key/value pair.
+func tomlKeyEnd(line []byte) (int, error) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"', '\'':
+			end, err := quotedEnd(line[i:], line[i], line[i] == '"')
+			if err != nil {
+				return 0, err
+			}
+			i += end - 1
+		case '=':
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("expected a key/value pair: %s", line)
+}
+
+// splitTOMLKey splits a dotted key into its parts, unquoting quoted parts.
+func splitTOMLKey(key []byte) ([]string, error) {
+	var parts []string
+	for {
+		key = bytes.TrimLeft(key, " \t")
+		if len(key) == 0 {
+			return nil, errors.New("empty key")
+		}
+
+		var part string
+		switch key[0] {
+		case '"':
+			end, err := quotedEnd(key, '"', true)
+			if err != nil {
+				return nil, err
+			}
+			err = json.Unmarshal(key[:end], &part)
+			if err != nil {
+				return nil, err
+			}
+			key = key[end:]
+		case '\'':
+			end := bytes.IndexByte(key[1:], '\'')
+			if end == -1 {
+				return nil, errors.New("key is not closed")
+			}
+			part = string(key[1 : end+1])
+			key = key[end+2:]
+		default:
+			end := bytes.IndexAny(key, ". \t")
+			if end == -1 {
+				end = len(key)
+			}
+			part = string(key[:end])
+			key = key[end:]
+		}
+		parts = append(parts, part)
+
+		key = bytes.TrimLeft(key, " \t")
+		if len(key) == 0 {
+			return parts, nil
+		}
+		if key[0] != '.' {
+			return nil, fmt.Errorf("unexpected character %q in key", key[0])
+		}
+		key = key[1:]
+	}
+}
+
+// tomlValueEnd returns the offset directly after the value that starts at the given offset.
+func tomlValueEnd(data []byte, start int) (int, error) {
+	value := data[start:]
+	switch {
+	case bytes.HasPrefix(value, []byte(`"""`)), bytes.HasPrefix(value, []byte(`'''`)):
+		delimiter := value[:3]
+		for i := 3; i < len(value); i++ {
+			if delimiter[0] == '"' && value[i] == '\\' {
+				i++
+				continue
+			}
+			if bytes.HasPrefix(value[i:], delimiter) {
+				end := i + 3
+				// Up to two quotes directly before the delimiter are part of the string.
+				for n := 0; n < 2 && end < len(value) && value[end] == delimiter[0]; n++ {
+					end++
+				}
+				return start + end, nil
+			}
+		}
+		return 0, errUnexpectedEnd
+	case len(value) > 0 && (value[0] == '"' || value[0] == '\''):
+		end, err := quotedEnd(value, value[0], value[0] == '"')
+		if err != nil {
+			return 0, err
+		}
+		return start + end, nil
+	case len(value) > 0 && (value[0] == '[' || value[0] == '{'):
+		depth := 0
+		for i := 0; i < len(value); i++ {
+			switch value[i] {
+			case '"', '\'':
+				end, err := tomlValueEnd(data, start+i)
+				if err != nil {
+					return 0, err
+				}
+				i = end - start - 1
+			case '#':
+				next := bytes.IndexByte(value[i:], '\n')
+				if next == -1 {
+					return 0, errUnexpectedEnd
+				}
+				i += next
+			case '[', '{':
+				depth++
+			case ']', '}':
+				depth--
+				if depth == 0 {
+					return start + i + 1, nil
+				}
+			}
+		}
+		return 0, errUnexpectedEnd
+	default:
+		end := len(value)
+		if i := bytes.IndexAny(value, "#\n"); i != -1 {
+			end = i
+		}
+		return start + len(bytes.TrimRight(value[:end], " \t\r")), nil
+	}
+}
+
+// line is the location of a line in a file, without the line ending.
+type line struct {
+	start int
+	end   int
+}
+
+// splitLines returns the locations of all lines in the data.
+func splitLines(data []byte) []line {
+	var lines []line
+	start := 0
+	for start <= len(data) {
+		end := bytes.IndexByte(data[start:], '\n')
+		if end == -1 {
+			lines = append(lines, line{start: start, end: len(data)})
+			break
+		}
+		lines = append(lines, line{start: start, end: start + len(bytes.TrimSuffix(data[start:start+end], []byte("\r")))})
+		start += end + 1
+	}
+	return lines
+}
+
+func equalKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+package secretspec
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestConfigFile_inject(t *testing.T) {
+	cases := map[string]struct {
+		format   configFormat
+		raw      string
+		key      string
+		value    string
+		expected string
+		err      error
+	}{
+		"json": {
+			format: jsonFormat{},
+			raw: `{
+  "name": "app",
+  "database": {
+    "user": "app",
+    "password": "placeholder", "port": 5432
+  }
+}`,
+			key:   "database.password",
+			value: "s3cr\"t",
+			expected: `{
+  "name": "app",
+  "database": {
+    "user": "app",
+    "password": "s3cr\"t", "port": 5432
+  }
+}`,
+		},
+		"json number value": {
+			format:   jsonFormat{},
+			raw:      `{"port":null,"password":0}`,
+			key:      "password",
+			value:    "secret",
+			expected: `{"port":null,"password":"secret"}`,
+		},
+		"json key in other object": {
+			format: jsonFormat{},
+			raw:    `{"cache": {"password": ""}, "database": {}}`,
+			key:    "database.password",
+			err:    ErrConfigKeyNotFound("database.password", "config"),
+		},
+		"yaml": {
+			format: yamlFormat{},
+			raw: `# Application config
+name: app
+database:
+  user: app # the user
+  password:   changeme   # replaced
+cache:
+  password: other
+`,
+			key:   "database.password",
+			value: "secret",
+			expected: `# Application config
+name: app
+database:
+  user: app # the user
+  password:   "secret"   # replaced
+cache:
+  password: other
+`,
+		},
+		"yaml empty value": {
+			format: yamlFormat{},
+			raw: `database:
+  password:
+  port: 5432
+`,
+			key:   "database.password",
+			value: "secret",
+			expected: `database:
+  password: "secret"
+  port: 5432
+`,
+		},
+		"yaml block scalar": {
+			format: yamlFormat{},
+			raw: `key: |
+  line 1
+  line 2
+other: value
+`,
+			key:   "key",
+			value: "line 1\nline 2",
+			expected: `key: "line 1\nline 2"
+other: value
+`,
+		},
+		"yaml quoted key": {
+			format:   yamlFormat{},
+			raw:      `"api key": 'x'`,
+			key:      "api key",
+			value:    "secret",
+			expected: `"api key": "secret"`,
+		},
+		"yaml key not in parent": {
+			format: yamlFormat{},
+			raw: `database:
+  user: app
+password: other
+`,
+			key: "database.password",
+			err: ErrConfigKeyNotFound("database.password", "config"),
+		},
+		"toml table": {
+			format: tomlFormat{},
+			raw: `title = "app"
+
+[database]
+user = "app"
+password = "changeme" # replaced
+ports = [
+  8000,
+  8001,
+]
+
+[cache]
+password = ""
+`,
+			key:   "database.password",
+			value: "secret",
+			expected: `title = "app"
+
+[database]
+user = "app"
+password = "secret" # replaced
+ports = [
+  8000,
+  8001,
+]
+
+[cache]
+password = ""
+`,
+		},
+		"toml dotted key": {
+			format:   tomlFormat{},
+			raw:      "description = '''\nmulti\nline = \"no key\"'''\ndatabase.password = 'x'\n",
+			key:      "database.password",
+			value:    "secret",
+			expected: "description = '''\nmulti\nline = \"no key\"'''\ndatabase.password = \"secret\"\n",
+		},
+		"toml multi-line value is not a key": {
+			format: tomlFormat{},
+			raw:    "description = '''\nline = \"no key\"'''\n",
+			key:    "line",
+			err:    ErrConfigKeyNotFound("line", "config"),
+		},
+		"toml array of tables": {
+			format: tomlFormat{},
+			raw:    "[[servers]]\nname = \"a\"\n",
+			key:    "servers.name",
+			err:    ErrConfigKeyNotFound("servers.name", "config"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &configFile{
+				format: tc.format,
+				source: "config",
+			}
+
+			actual, err := c.inject([]byte(tc.raw), tc.key, tc.value)
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, string(actual), tc.expected)
+			}
+		})
+	}
+}
+
+func TestConfigFile_Set(t *testing.T) {
+	c := &configFile{
+		format: yamlFormat{},
+		source: "config.yml",
+		fields: map[string]string{
+			"database.password": "company/app/db/password",
+		},
+		raw: []byte("database:\n  password: x\n"),
+	}
+
+	err := c.Set(map[string]api.SecretVersion{})
+	assert.Equal(t, err, ErrSecretNotFound("company/app/db/password"))
+
+	assert.Equal(t, c.Sources(), map[string]struct{}{"company/app/db/password": {}})
+}
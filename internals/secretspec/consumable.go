@@ -25,6 +25,9 @@ var (
 		FileParser{},
 		EnvParser{},
 		InjectParser{},
+		JSONParser{},
+		YAMLParser{},
+		TOMLParser{},
 	}
 
 	// DefaultFileMode is the default filemode to use for consumables.
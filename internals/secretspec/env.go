@@ -209,3 +209,28 @@ func (s sortEnvarsByTarget) Swap(i, j int) {
 func (s sortEnvarsByTarget) Less(i, j int) bool {
 	return s[i].target < s[j].target
 }
+
+// EnvVars returns the variables of the env consumable with the given name, mapping the
+// name of every variable to the path of the secret it is sourced from. When the name is
+// empty, it defaults to the defaultEnvName. The returned bool is false when the presenter
+// does not contain an env consumable with the given name.
+func (p *Presenter) EnvVars(name string) (map[string]string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = defaultEnvName
+	}
+
+	for _, consumable := range p.consumables {
+		e, ok := consumable.(*env)
+		if !ok || e.name != name {
+			continue
+		}
+
+		vars := make(map[string]string, len(e.vars))
+		for _, v := range e.vars {
+			vars[v.target] = v.source
+		}
+		return vars, true
+	}
+	return nil, false
+}
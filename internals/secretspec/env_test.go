@@ -418,3 +418,38 @@ func TestEnvSetAndClear(t *testing.T) {
 		})
 	}
 }
+
+func TestPresenter_EnvVars(t *testing.T) {
+	spec := []byte(`
+secrets:
+  - env:
+      vars:
+        DB_USER: namespace/repo/db/user
+        DB_PASSWORD: namespace/repo/db/password
+  - env:
+      name: test_env
+      vars:
+        API_KEY: namespace/repo/api_key
+`)
+
+	presenter, err := NewPresenter("", true, EnvParser{})
+	assert.OK(t, err)
+	err = presenter.Parse(spec)
+	assert.OK(t, err)
+
+	vars, found := presenter.EnvVars("")
+	assert.Equal(t, found, true)
+	assert.Equal(t, vars, map[string]string{
+		"DB_USER":     "namespace/repo/db/user",
+		"DB_PASSWORD": "namespace/repo/db/password",
+	})
+
+	vars, found = presenter.EnvVars("test_env")
+	assert.Equal(t, found, true)
+	assert.Equal(t, vars, map[string]string{
+		"API_KEY": "namespace/repo/api_key",
+	})
+
+	_, found = presenter.EnvVars("other")
+	assert.Equal(t, found, false)
+}
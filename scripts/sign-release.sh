@@ -0,0 +1,24 @@
+#!/usr/bin/env sh
+# Signs the checksums file of a release for `secrethub self-update`.
+#
+# Usage: sign-release.sh <tag> <checksums-file> <signature-file>
+#
+# The tag of the release and the checksums file are signed together with the Ed25519 private key
+# in the PEM file at $RELEASE_SIGNING_KEY_FILE. The base64 encoded public key, which is compiled
+# into the CLI as $RELEASE_SIGNING_KEY, is printed by:
+#
+#   openssl pkey -in "$RELEASE_SIGNING_KEY_FILE" -pubout -outform DER | tail -c 32 | base64
+
+set -eu
+
+TAG=$1
+CHECKSUMS=$2
+SIGNATURE=$3
+
+PAYLOAD=$(mktemp)
+trap 'rm -f "${PAYLOAD}"' EXIT
+
+printf 'secrethub-cli %s\n' "${TAG}" > "${PAYLOAD}"
+cat "${CHECKSUMS}" >> "${PAYLOAD}"
+
+openssl pkeyutl -sign -rawin -inkey "${RELEASE_SIGNING_KEY_FILE}" -in "${PAYLOAD}" | base64 | tr -d '\n' > "${SIGNATURE}"